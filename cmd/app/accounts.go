@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"subspace/internal/accounts"
+	"subspace/internal/config"
+	"subspace/internal/i18n"
+)
+
+// accountStatus is one account as configured and as registered
+type accountStatus struct {
+	Name       string            `json:"name"`
+	Active     bool              `json:"active"`
+	Configured bool              `json:"configured"`
+	Registered *accounts.Binding `json:"registered,omitempty"`
+	Matches    bool              `json:"matches"`
+}
+
+// accounts handles "accounts [list]", "accounts rebind <name>" and the
+// export and import of account bundles
+func (c *cli) accounts(args []string) error {
+	registry, err := accounts.Load(c.cfg.App.DataDir)
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 0 && args[0] == "export" {
+		return c.accountsExport(args[1:])
+	}
+	if len(args) > 0 && args[0] == "import" {
+		return c.accountsImport(args[1:])
+	}
+	if len(args) > 0 && args[0] == "rebind" {
+		if len(args) != 2 {
+			return fmt.Errorf("usage: accounts rebind <name>")
+		}
+		a, ok := c.cfg.Accounts[args[1]]
+		if !ok {
+			return fmt.Errorf("unknown account: %s (define it under accounts)", args[1])
+		}
+		if err := registry.Rebind(args[1], a); err != nil {
+			return err
+		}
+		fmt.Printf("🔗 %s\n", i18n.T("accounts.rebound", args[1]))
+		return nil
+	}
+	if len(args) > 0 && args[0] != "list" {
+		return fmt.Errorf("usage: accounts [list] | accounts rebind <name> | accounts export <name> [-out file] | accounts import <file> [-force]")
+	}
+
+	var statuses []accountStatus
+	seen := make(map[string]bool)
+	for _, name := range sortedKeys(c.cfg.Accounts) {
+		st := accountStatus{Name: name, Active: name == c.cfg.App.Account, Configured: true}
+		if b, ok := registry.Accounts[name]; ok {
+			st.Registered = &b
+			st.Matches = registry.Check(name, c.cfg.Accounts[name]) == nil
+		}
+		statuses = append(statuses, st)
+		seen[name] = true
+	}
+	for _, name := range registry.Names() {
+		if !seen[name] {
+			b := registry.Accounts[name]
+			statuses = append(statuses, accountStatus{Name: name, Registered: &b})
+		}
+	}
+
+	return render(c.output, statuses, func() {
+		fmt.Printf("\n🔗 %s\n\n", i18n.T("accounts.title", len(statuses)))
+		if len(statuses) == 0 {
+			fmt.Printf("  %s\n", i18n.T("accounts.none"))
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, i18n.T("accounts.header"))
+		for _, st := range statuses {
+			name := st.Name
+			if st.Active {
+				name += " *"
+			}
+			status := i18n.T("accounts.bound")
+			switch {
+			case !st.Configured:
+				status = i18n.T("accounts.not_configured")
+			case st.Registered == nil:
+				status = i18n.T("accounts.unregistered")
+			case !st.Matches:
+				status = i18n.T("accounts.changed")
+			}
+			a := c.cfg.Accounts[st.Name]
+			if !st.Configured {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", name, st.Registered.ProxyHost, st.Registered.TimeZone, st.Registered.Persona, status)
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", name, config.ProxyHost(a.Proxy), a.TimeZone, a.Persona, status)
+		}
+		w.Flush()
+	})
+}
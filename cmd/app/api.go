@@ -0,0 +1,42 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"subspace/internal/config"
+	"subspace/internal/logger"
+	"subspace/internal/storage"
+)
+
+// serveAPI serves the API on app.api_addr, apart from the metrics server:
+// its endpoints read and change profiles and the queue, so every request
+// must carry app.api_token
+func serveAPI(cfg *config.Config, db storage.Backend) {
+	mux := http.NewServeMux()
+	mux.Handle("/queue/", requireToken(cfg.App.APIToken, queueAPIHandler(cfg, db)))
+	mux.Handle("/queue", requireToken(cfg.App.APIToken, queueHandler(cfg, db)))
+	mux.Handle("/profiles", requireToken(cfg.App.APIToken, profilesHandler(db)))
+	mux.Handle("/schedule", requireToken(cfg.App.APIToken, scheduleHandler(cfg, db)))
+	logger.Info("Serving API", "addr", cfg.App.APIAddr)
+	go func() {
+		if err := http.ListenAndServe(cfg.App.APIAddr, mux); err != nil {
+			logger.Error("Queue API stopped", "error", err)
+		}
+	}()
+}
+
+// requireToken passes on requests carrying the bearer token and refuses
+// the rest with 401
+func requireToken(token string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="subspace"`)
+			http.Error(w, "missing or wrong API token", http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
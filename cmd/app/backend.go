@@ -0,0 +1,27 @@
+package main
+
+import (
+	"subspace/internal/config"
+	"subspace/internal/logger"
+	"subspace/internal/storage"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // registers the "pgx" driver
+)
+
+// openBackend returns the storage the run, stats, the HTTP endpoints and
+// the commands on profiles and the queue work on: db, the db.json file, or
+// the shared Postgres database when storage.backend is postgres. Commands
+// that work on the file itself, like compact, verify and dedupe, always use
+// db.
+func openBackend(cfg *config.Config, db *storage.Storage) (storage.Backend, error) {
+	if cfg.Storage.Backend != "postgres" {
+		return db, nil
+	}
+	pg, err := storage.OpenPostgres(cfg.Storage)
+	if err != nil {
+		return nil, err
+	}
+	pg.SetAccount(cfg.App.Account)
+	logger.Info("Using shared storage", "backend", "postgres")
+	return pg, nil
+}
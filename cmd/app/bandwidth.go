@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"subspace/internal/bandwidth"
+	"subspace/internal/clock"
+	"subspace/internal/i18n"
+)
+
+// bandwidthReport is the machine-readable view of "stats bandwidth"
+type bandwidthReport struct {
+	Days       int              `json:"days"`
+	Runs       []bandwidth.Run  `json:"runs"`
+	Bytes      int64            `json:"bytes"`
+	Cost       float64          `json:"cost,omitempty"`
+	ByAccount  map[string]int64 `json:"by_account,omitempty"`
+	ByStep     map[string]int64 `json:"by_step,omitempty"`
+	ByCampaign map[string]int64 `json:"by_campaign,omitempty"`
+}
+
+// bandwidth handles "stats bandwidth [-days n]", the traffic of recent runs
+// and what it cost at bandwidth.cost_per_gb
+func (c *cli) bandwidth(args []string) error {
+	fs := flag.NewFlagSet("stats bandwidth", flag.ContinueOnError)
+	days := fs.Int("days", 30, "Days of runs to include")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	runs, err := bandwidth.Runs(c.cfg.App.DataDir, clock.Now().AddDate(0, 0, -*days))
+	if err != nil {
+		return err
+	}
+	rep := bandwidthReport{Days: *days, Runs: runs, ByAccount: make(map[string]int64), ByStep: make(map[string]int64), ByCampaign: make(map[string]int64)}
+	for _, r := range runs {
+		rep.Bytes += r.Bytes
+		rep.ByAccount[r.Account] += r.Bytes
+		for name, n := range r.ByStep {
+			rep.ByStep[name] += n
+		}
+		for name, n := range r.ByCampaign {
+			rep.ByCampaign[name] += n
+		}
+	}
+	perGB := c.cfg.Bandwidth.CostPerGB
+	rep.Cost = bandwidth.Cost(rep.Bytes, perGB)
+
+	return render(c.output, rep, func() {
+		fmt.Printf("\n📶 %s\n\n", i18n.T("bandwidth.title", *days))
+		if len(runs) == 0 {
+			fmt.Printf("  %s\n", i18n.T("bandwidth.none"))
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, i18n.T("bandwidth.header"))
+		for _, r := range runs {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", r.StartedAt.Format("2006-01-02 15:04"), r.EndedAt.Sub(r.StartedAt).Round(time.Minute),
+				formatBytes(r.Bytes), r.Requests, r.Account)
+		}
+		w.Flush()
+
+		fmt.Println()
+		printStat("bandwidth.total", formatBytes(rep.Bytes))
+		if perGB > 0 {
+			printStat("bandwidth.cost", fmt.Sprintf("%.2f", rep.Cost))
+		}
+		if len(rep.ByAccount) > 1 {
+			for _, name := range sortedKeys(rep.ByAccount) {
+				printStat("  "+name, formatBytes(rep.ByAccount[name]))
+			}
+		}
+		if len(rep.ByStep) > 0 {
+			fmt.Printf("\n  %s\n", i18n.T("bandwidth.by_step"))
+			for _, name := range sortedKeys(rep.ByStep) {
+				printStat("  "+name, formatBytes(rep.ByStep[name]))
+			}
+		}
+		if len(rep.ByCampaign) > 0 {
+			fmt.Printf("\n  %s\n", i18n.T("bandwidth.by_campaign"))
+			for _, name := range sortedKeys(rep.ByCampaign) {
+				printStat("  "+name, formatBytes(rep.ByCampaign[name]))
+			}
+		}
+	})
+}
+
+// formatBytes renders a byte count in decimal units, as proxies bill
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1e9:
+		return fmt.Sprintf("%.2f GB", float64(n)/1e9)
+	case n >= 1e6:
+		return fmt.Sprintf("%.1f MB", float64(n)/1e6)
+	case n >= 1e3:
+		return fmt.Sprintf("%.0f kB", float64(n)/1e3)
+	}
+	return fmt.Sprintf("%d B", n)
+}
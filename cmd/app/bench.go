@@ -0,0 +1,146 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"subspace/internal/bench"
+	"subspace/internal/i18n"
+	"subspace/internal/logger"
+)
+
+// bench handles "bench [-profiles n] [-days n] [-interval d] [-seed n] [-chaos] [-keep]",
+// running the pipeline in simulation under a fake clock
+func (c *cli) bench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	profiles := fs.Int("profiles", 2000, "Synthetic profiles to seed")
+	days := fs.Int("days", 7, "Simulated days to run")
+	interval := fs.Duration("interval", time.Hour, "Simulated time between pipeline cycles")
+	seed := fs.Int64("seed", 0, "Random seed for a reproducible run (0 = random)")
+	chaos := fs.Bool("chaos", false, "Inject the simulation.faults failures even if not enabled")
+	keep := fs.Bool("keep", false, "Keep the bench data directory instead of deleting it")
+	verbose := fs.Bool("verbose", false, "Keep pipeline logging at the configured level")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *profiles < 0 || *days <= 0 || *interval <= 0 {
+		return fmt.Errorf("profiles must be >= 0, days and interval must be positive")
+	}
+
+	dir, err := os.MkdirTemp("", "subspace-bench-")
+	if err != nil {
+		return fmt.Errorf("failed to create bench directory: %w", err)
+	}
+	if !*keep {
+		defer os.RemoveAll(dir)
+	}
+
+	if !machineReadable(c.output) {
+		fmt.Printf("🏁 %s\n", i18n.T("bench.running", *profiles, *days))
+	}
+	// Thousands of simulated actions would otherwise drown the report
+	if !*verbose {
+		logger.Init("error")
+		defer logger.Init(c.cfg.App.LogLevel)
+	}
+
+	report, err := bench.Run(c.cfg, bench.Options{
+		Profiles: *profiles,
+		Days:     *days,
+		Interval: *interval,
+		Seed:     *seed,
+		DataDir:  dir,
+		Chaos:    *chaos,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = render(c.output, report, func() {
+		printBenchReport(report)
+		if *keep {
+			fmt.Printf("\n📁 %s\n", i18n.T("bench.kept", dir))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if !report.LimiterOK() {
+		return fmt.Errorf("%d rate limit violations", len(report.Violations))
+	}
+	return nil
+}
+
+// printBenchReport displays a bench report
+func printBenchReport(r *bench.Report) {
+	fmt.Printf("\n📊 %s\n\n", i18n.T("bench.title"))
+	printStat("bench.simulated", i18n.T("bench.simulated_value",
+		r.SimulatedEnd.Sub(r.SimulatedStart).Hours()/24, r.Cycles))
+	printStat("bench.wall_time", r.WallTime.Round(time.Millisecond))
+	printStat("bench.throughput", i18n.T("bench.throughput_value", r.ActionsPerSecond, r.Speedup))
+	printStat("bench.saves", i18n.T("bench.saves_value",
+		r.Saves, float64(r.SaveAvg.Microseconds())/1000, float64(r.SaveMax.Microseconds())/1000))
+
+	fmt.Printf("\n  %s\n", i18n.T("bench.actions"))
+	for _, action := range sortedKeys(r.Actions) {
+		fmt.Printf("    %-14s %d\n", action, r.Actions[action])
+	}
+
+	fmt.Printf("\n  %s\n", i18n.T("bench.funnel"))
+	for _, state := range sortedKeys(r.Funnel) {
+		fmt.Printf("    %-14s %d\n", state, r.Funnel[state])
+	}
+
+	if len(r.Faults) > 0 || len(r.Failed) > 0 || r.SessionsLost > 0 {
+		fmt.Printf("\n  %s\n", i18n.T("bench.chaos"))
+		for _, fault := range sortedKeys(r.Faults) {
+			fmt.Printf("    %-20s %d\n", fault, r.Faults[fault])
+		}
+		for _, action := range sortedKeys(r.Failed) {
+			fmt.Printf("    %-20s %d\n", i18n.T("bench.failed", action), r.Failed[action])
+		}
+		fmt.Printf("    %-20s %d\n", i18n.T("bench.sessions_lost"), r.SessionsLost)
+	}
+
+	fmt.Printf("\n  %s\n", i18n.T("bench.growth"))
+	for _, d := range r.Growth {
+		fmt.Printf("    %s\n", i18n.T("bench.growth_day", d.Day, float64(d.SizeBytes)/1024, d.ActionLogs, d.Messages))
+	}
+
+	fmt.Println()
+	if r.LimiterOK() {
+		fmt.Printf("✅ %s\n", i18n.T("bench.limits_ok"))
+		return
+	}
+	fmt.Printf("❌ %s\n", i18n.T("bench.limits_violated", len(r.Violations)))
+	for _, v := range r.Violations {
+		fmt.Printf("   %s\n", i18n.T("bench.violation",
+			v.Action, formatPeriod(v.Period), v.Start.Format("2006-01-02 15:04"), v.Count, v.Max))
+	}
+}
+
+// formatPeriod renders a window length without zero units ("24h", not "24h0m0s")
+func formatPeriod(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// sortedKeys returns the keys of a map in a stable order
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"subspace/internal/accounts"
+	"subspace/internal/bundle"
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/cookies"
+	"subspace/internal/i18n"
+	"subspace/internal/webstorage"
+)
+
+// passphraseEnv holds the bundle passphrase for unattended use; without
+// it the passphrase is read from stdin
+const passphraseEnv = "SUBSPACE_BUNDLE_PASSPHRASE"
+
+// accountsExport handles "accounts export <name> [-out file]"
+func (c *cli) accountsExport(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("usage: accounts export <name> [-out file]")
+	}
+	name := args[0]
+	fs := flag.NewFlagSet("accounts export", flag.ContinueOnError)
+	out := fs.String("out", name+".bundle", "Bundle file to write")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	a, ok := c.cfg.Accounts[name]
+	if !ok {
+		return fmt.Errorf("unknown account: %s (define it under accounts)", name)
+	}
+
+	var fragment strings.Builder
+	enc := yaml.NewEncoder(&fragment)
+	enc.SetIndent(2)
+	if err := enc.Encode(map[string]config.AccountConfig{name: a}); err != nil {
+		return fmt.Errorf("failed to encode account config: %w", err)
+	}
+	b := &bundle.Bundle{Account: name, ExportedAt: clock.Now(), Config: fragment.String()}
+	var err error
+	if b.Cookies, err = cookies.Load(c.sessionPath()); err != nil {
+		return err
+	}
+	ws, ok, err := webstorage.Saved(c.cfg.App.DataDir, name)
+	if err != nil {
+		return err
+	}
+	if ok {
+		b.WebStorage = &ws
+	}
+
+	passphrase, err := readPassphrase()
+	if err != nil {
+		return err
+	}
+	data, err := bundle.Seal(b, passphrase)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out, data, 0600); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	fmt.Printf("📦 %s\n", i18n.T("accounts.exported", name, *out, len(b.Cookies)))
+	return nil
+}
+
+// accountsImport handles "accounts import <file> [-force]". Nothing is
+// overwritten without -force: neither an account already registered here
+// nor a saved session.
+func (c *cli) accountsImport(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("usage: accounts import <file> [-force]")
+	}
+	file := args[0]
+	fs := flag.NewFlagSet("accounts import", flag.ContinueOnError)
+	force := fs.Bool("force", false, "Replace the registered binding and the saved session")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read bundle: %w", err)
+	}
+	passphrase, err := readPassphrase()
+	if err != nil {
+		return err
+	}
+	b, err := bundle.Open(data, passphrase)
+	if err != nil {
+		return err
+	}
+	var fragment map[string]config.AccountConfig
+	if err := yaml.Unmarshal([]byte(b.Config), &fragment); err != nil {
+		return fmt.Errorf("failed to parse bundled account config: %w", err)
+	}
+	a, ok := fragment[b.Account]
+	if !ok {
+		return fmt.Errorf("bundle has no config for account %s", b.Account)
+	}
+
+	registry, err := accounts.Load(c.cfg.App.DataDir)
+	if err != nil {
+		return err
+	}
+	if _, ok := registry.Accounts[b.Account]; ok && !*force {
+		return fmt.Errorf("account %s is already registered here (use -force to replace it)", b.Account)
+	}
+	session := c.sessionPath()
+	if existing, err := cookies.Load(session); err != nil {
+		return err
+	} else if len(existing) > 0 && !*force {
+		return fmt.Errorf("a session is already saved in %s (use -force to replace it)", session)
+	}
+
+	if err := registry.Rebind(b.Account, a); err != nil {
+		return err
+	}
+	if err := cookies.Save(session, b.Cookies); err != nil {
+		return err
+	}
+	if b.WebStorage != nil {
+		if err := webstorage.Put(c.cfg.App.DataDir, b.Account, *b.WebStorage); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("📦 %s\n", i18n.T("accounts.imported", b.Account, b.ExportedAt.Format("2006-01-02"), len(b.Cookies)))
+	if _, ok := c.cfg.Accounts[b.Account]; !ok {
+		fmt.Printf("\n%s\n\n", i18n.T("accounts.add_config"))
+		for _, line := range strings.Split(strings.TrimRight(b.Config, "\n"), "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	return nil
+}
+
+// sessionPath is where the saved session is, as auth resolves it
+func (c *cli) sessionPath() string {
+	return config.GetEnv("SESSION_COOKIE_PATH", c.cfg.Auth.SessionCookiePath)
+}
+
+// readPassphrase returns the bundle passphrase from the environment, or
+// else from stdin
+func readPassphrase() (string, error) {
+	if p := os.Getenv(passphraseEnv); p != "" {
+		return p, nil
+	}
+	fmt.Printf("%s ", i18n.T("accounts.passphrase", passphraseEnv))
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if p := strings.TrimRight(line, "\r\n"); p != "" {
+		return p, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return "", fmt.Errorf("a passphrase is required")
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/i18n"
+	"subspace/internal/report"
+)
+
+// burnDownWidth is the width of the hourly bars
+const burnDownWidth = 30
+
+// burnDown handles "stats burndown", today's consumption of each daily
+// budget against an even pace over the business hours
+func (c *cli) burnDown() error {
+	now := clock.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	end := start.Add(24 * time.Hour)
+	if c.cfg.Stealth.BusinessHoursEnabled {
+		start, end = atClock(start, c.cfg.Stealth.BusinessHoursStart), atClock(start, c.cfg.Stealth.BusinessHoursEnd)
+	}
+
+	logs := c.store.GetActionLogs("")
+	limits := c.cfg.Limits
+	rows := []report.BurnDown{
+		report.BudgetBurnDown(logs, "connection", limits.ConnectionsPerDay, start, end, now),
+		report.BudgetBurnDown(logs, "message", limits.MessagesPerDay, start, end, now),
+		report.BudgetBurnDown(logs, "search", limits.SearchesPerDay, start, end, now),
+	}
+
+	return render(c.output, rows, func() {
+		until := end.Format("15:04")
+		if end.Day() != start.Day() {
+			until = "24:00"
+		}
+		fmt.Printf("\n📉 %s\n", i18n.T("burndown.title", start.Format("2006-01-02"), start.Format("15:04"), until))
+		for _, b := range rows {
+			fmt.Printf("\n  %s\n", i18n.T("burndown.summary", b.Action, b.Used, b.Limit, b.Expected, i18n.T("burndown.pace_"+b.Pace)))
+			switch {
+			case b.ExhaustedAt == nil:
+				fmt.Printf("  %s\n", i18n.T("burndown.lasts"))
+			case b.Projected:
+				fmt.Printf("  ⚠️  %s\n", i18n.T("burndown.projected", b.ExhaustedAt.Format("15:04")))
+			default:
+				fmt.Printf("  ⛔ %s\n", i18n.T("burndown.exhausted", b.ExhaustedAt.Format("15:04")))
+			}
+			for _, h := range b.Hours {
+				fmt.Printf("    %s  %s  %s\n", h.Start.Format("15:04"), burnBar(b, h), burnCount(b, h))
+			}
+		}
+	})
+}
+
+// burnBar draws the cumulative use by the end of an hour (█) and, beyond
+// it, the even-pace target (░)
+func burnBar(b report.BurnDown, h report.HourUsage) string {
+	if b.Limit <= 0 {
+		return strings.Repeat(" ", burnDownWidth)
+	}
+	scale := func(n int) int {
+		if n > b.Limit {
+			n = b.Limit
+		}
+		return n * burnDownWidth / b.Limit
+	}
+	used := 0
+	if !h.Future {
+		used = scale(h.Cumulative)
+	}
+	target := scale(h.Expected)
+	if target < used {
+		target = used
+	}
+	return strings.Repeat("█", used) + strings.Repeat("░", target-used) + strings.Repeat(" ", burnDownWidth-target)
+}
+
+// burnCount labels an hour with its cumulative use and target
+func burnCount(b report.BurnDown, h report.HourUsage) string {
+	if h.Future {
+		return i18n.T("burndown.hour_future", h.Expected)
+	}
+	return i18n.T("burndown.hour", h.Cumulative, h.Expected)
+}
+
+// atClock returns day at the "15:04" time of day hm
+func atClock(day time.Time, hm string) time.Time {
+	t, _ := time.Parse("15:04", hm)
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location())
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"subspace/internal/config"
+	"subspace/internal/storage"
+)
+
+// cli carries what subcommands need from main
+type cli struct {
+	cfg    *config.Config
+	db     *storage.Storage // db.json, for commands working on the file itself
+	store  storage.Backend  // The configured backend, see openBackend
+	output string
+
+	configPath string // As given with -config
+}
+
+// readOnly reports whether a subcommand leaves storage alone, so it may run
+// alongside the instance holding the run lock. stop and resume only touch
+// the stop file, and must reach a running instance.
+func readOnly(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	switch args[0] {
+	case "stats", "plan", "schedule", "export", "messages", "stop", "resume", "service", "version", "self-update":
+		return true
+	case "queue":
+		return len(args) == 1 || args[1] == "list" || strings.HasPrefix(args[1], "-")
+	}
+	return false
+}
+
+// run dispatches a subcommand given after the flags,
+// e.g. "subspace -config=custom.yaml maintenance run"
+func (c *cli) run(args []string) error {
+	switch args[0] {
+	case "accounts":
+		return c.accounts(args[1:])
+	case "cookies":
+		return c.cookieJar(args[1:])
+	case "maintenance":
+		return c.maintenance(args[1:])
+	case "stats":
+		if len(args) > 1 && args[1] == "latency" {
+			return c.latency()
+		}
+		if len(args) > 1 && args[1] == "burndown" {
+			return c.burnDown()
+		}
+		if len(args) > 1 && args[1] == "bandwidth" {
+			return c.bandwidth(args[2:])
+		}
+		return c.stats(args[1:])
+	case "profiles":
+		return c.profiles(args[1:])
+	case "notes":
+		return c.notes(args[1:])
+	case "inbox":
+		return c.inbox(args[1:])
+	case "messages":
+		return c.messages(args[1:])
+	case "snooze":
+		return c.snooze(args[1:])
+	case "queue":
+		return c.queue(args[1:])
+	case "enrich":
+		return c.enrich(args[1:])
+	case "templates":
+		return c.templates(args[1:])
+	case "review":
+		return c.review(args[1:])
+	case "plan":
+		return c.plan(args[1:])
+	case "bench":
+		return c.bench(args[1:])
+	case "experiment":
+		return c.experiment(args[1:])
+	case "replay":
+		return c.replay(args[1:])
+	case "schedule":
+		return c.schedule(args[1:])
+	case "session":
+		return c.session(args[1:])
+	case "export":
+		return c.export(args[1:])
+	case "stop":
+		return c.stop(args[1:])
+	case "resume":
+		return c.resume(args[1:])
+	case "service":
+		return c.service(args[1:])
+	case "version":
+		return c.version(args[1:])
+	case "self-update":
+		return c.selfUpdate(args[1:])
+	default:
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"runtime"
+
+	"subspace/internal/config"
+	"subspace/internal/estop"
+)
+
+// containerEnv switches container mode on when set to anything but 0
+const containerEnv = "SUBSPACE_CONTAINER"
+
+// defaultContainerMetricsAddr serves /metrics and /healthz in a container
+// that didn't configure an address
+const defaultContainerMetricsAddr = ":9090"
+
+// inContainer reports whether to run in container mode: asked for with
+// SUBSPACE_CONTAINER, or detected from the marker file of Docker or Podman
+func inContainer() bool {
+	if v, ok := os.LookupEnv(containerEnv); ok {
+		return v != "" && v != "0" && v != "false"
+	}
+	for _, marker := range []string{"/.dockerenv", "/run/.containerenv"} {
+		if _, err := os.Stat(marker); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// noDisplay reports whether there is no X or Wayland display to show a
+// headful Chrome on
+func noDisplay() bool {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		return false
+	}
+	return os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == ""
+}
+
+// applyContainer adapts the config to a container: Chrome runs headless
+// with container-safe flags, and an automation run always serves /metrics
+// and /healthz. Commands leave the port to the run.
+func applyContainer(cfg *config.Config, automation bool) {
+	cfg.App.Container = true
+	cfg.App.Headless = true
+	if automation && cfg.App.MetricsAddr == "" {
+		cfg.App.MetricsAddr = defaultContainerMetricsAddr
+	}
+}
+
+// healthHandler answers liveness probes; the emergency stop is reported
+// but doesn't fail the probe, since restarting wouldn't release it
+func healthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "ok",
+			"stopped": estop.State().Engaged,
+		})
+	})
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"subspace/internal/clock"
+	"subspace/internal/cookies"
+	"subspace/internal/i18n"
+)
+
+// cookieEntry is one saved cookie, without its value
+type cookieEntry struct {
+	Name    string          `json:"name"`
+	Domain  string          `json:"domain"`
+	Expires string          `json:"expires,omitempty"` // Empty for session cookies
+	Size    int             `json:"size"`
+	Verdict cookies.Verdict `json:"verdict"`
+}
+
+// cookieJar handles "cookies [list]", "cookies prune [-dry-run]" and
+// "cookies drop <pattern>" on the saved session
+func (c *cli) cookieJar(args []string) error {
+	path := c.sessionPath()
+	jar, err := cookies.Load(path)
+	if err != nil {
+		return err
+	}
+	policy := cookies.NewPolicy(c.cfg.Auth.Cookies)
+
+	cmd := "list"
+	if len(args) > 0 {
+		cmd = args[0]
+	}
+	switch cmd {
+	case "list":
+		entries := make([]cookieEntry, 0, len(jar))
+		for _, ck := range jar {
+			e := cookieEntry{Name: ck.Name, Domain: ck.Domain, Size: ck.Size, Verdict: policy.Judge(ck, clock.Now())}
+			if ck.Expires > 0 {
+				e.Expires = ck.Expires.Time().Format("2006-01-02 15:04")
+			}
+			entries = append(entries, e)
+		}
+		return render(c.output, entries, func() {
+			fmt.Printf("\n🍪 %s\n\n", i18n.T("cookies.title", len(entries), path))
+			if len(entries) == 0 {
+				fmt.Printf("  %s\n", i18n.T("cookies.none"))
+				return
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, i18n.T("cookies.header"))
+			for _, e := range entries {
+				expires := e.Expires
+				if expires == "" {
+					expires = i18n.T("cookies.session")
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", e.Name, e.Domain, expires, e.Size, i18n.T("cookies.verdict_"+string(e.Verdict)))
+			}
+			w.Flush()
+		})
+
+	case "prune":
+		fs := flag.NewFlagSet("cookies prune", flag.ContinueOnError)
+		dryRun := fs.Bool("dry-run", false, "Show what would be removed without saving")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		kept, dropped := policy.Filter(jar, clock.Now())
+		return c.rewriteJar(path, kept, dropped, *dryRun)
+
+	case "drop":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: cookies drop <name-pattern|domain>")
+		}
+		var kept, dropped []*proto.NetworkCookie
+		for _, ck := range jar {
+			if cookies.Matches(ck, args[1:]) || cookies.OnDomain(ck, args[1]) {
+				dropped = append(dropped, ck)
+			} else {
+				kept = append(kept, ck)
+			}
+		}
+		return c.rewriteJar(path, kept, dropped, false)
+
+	default:
+		return fmt.Errorf("usage: cookies [list] | cookies prune [-dry-run] | cookies drop <name-pattern|domain>")
+	}
+}
+
+// rewriteJar saves the kept cookies, unless nothing was dropped or it is a
+// dry run, and prints the dropped ones
+func (c *cli) rewriteJar(path string, kept, dropped []*proto.NetworkCookie, dryRun bool) error {
+	for _, ck := range dropped {
+		fmt.Printf("  - %s (%s)\n", ck.Name, ck.Domain)
+	}
+	if len(dropped) > 0 && !dryRun {
+		if err := cookies.Save(path, kept); err != nil {
+			return err
+		}
+	}
+	key := "cookies.removed"
+	if dryRun {
+		key = "cookies.would_remove"
+	}
+	fmt.Printf("🍪 %s\n", i18n.T(key, len(dropped), len(kept)+len(dropped)))
+	return nil
+}
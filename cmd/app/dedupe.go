@@ -0,0 +1,143 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"subspace/internal/i18n"
+	"subspace/internal/storage"
+)
+
+// dedupeGroup is one set of profiles that describe the same person
+type dedupeGroup struct {
+	Keep       *storage.Profile   `json:"keep"`
+	Duplicates []*storage.Profile `json:"duplicates"`
+	Merged     storage.Profile    `json:"merged"`
+	Messages   map[string]int     `json:"messages"` // Profile ID -> message count
+	Applied    bool               `json:"applied"`
+}
+
+// dedupe handles "profiles dedupe [-exact] [-apply]". Without -apply it only
+// shows what would be merged.
+func (c *cli) dedupe(args []string) error {
+	fs := flag.NewFlagSet("profiles dedupe", flag.ContinueOnError)
+	exact := fs.Bool("exact", false, "Only match identical profile URLs, not similar names at the same company")
+	apply := fs.Bool("apply", false, "Merge the duplicates instead of only showing them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var groups []dedupeGroup
+	for _, profiles := range c.db.FindDuplicates(!*exact) {
+		g := dedupeGroup{
+			Keep:       profiles[0],
+			Duplicates: profiles[1:],
+			Merged:     storage.PreviewMerge(profiles[0], profiles[1:]...),
+			Messages:   make(map[string]int),
+		}
+		for _, p := range profiles {
+			g.Messages[p.ID] = len(c.db.GetMessagesByProfile(p.ID))
+		}
+		groups = append(groups, g)
+	}
+
+	if *apply {
+		for i := range groups {
+			ids := make([]string, 0, len(groups[i].Duplicates))
+			for _, dup := range groups[i].Duplicates {
+				ids = append(ids, dup.ID)
+			}
+			if _, err := c.db.Merge(groups[i].Keep.ID, ids...); err != nil {
+				return fmt.Errorf("failed to merge into %s: %w", groups[i].Keep.ID, err)
+			}
+			groups[i].Applied = true
+		}
+	}
+
+	return render(c.output, groups, func() {
+		if len(groups) == 0 {
+			fmt.Printf("\n✅ %s\n", i18n.T("dedupe.none"))
+			return
+		}
+		fmt.Printf("\n🔍 %s\n", i18n.T("dedupe.title", len(groups)))
+		for i, g := range groups {
+			fmt.Printf("\n%s\n", i18n.T("dedupe.group", i+1, g.Keep.ID, len(g.Duplicates)))
+			printDedupeDiff(g)
+		}
+		fmt.Println()
+		if *apply {
+			fmt.Printf("✅ %s\n", i18n.T("dedupe.applied", countDuplicates(groups), len(groups)))
+		} else {
+			fmt.Printf("💡 %s\n", i18n.T("dedupe.dry_run"))
+		}
+	})
+}
+
+// printDedupeDiff prints the fields that differ within a duplicate group,
+// one column per profile plus the merged result
+func printDedupeDiff(g dedupeGroup) {
+	profiles := append([]*storage.Profile{g.Keep}, g.Duplicates...)
+	merged := g.Merged
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := []string{"  " + i18n.T("dedupe.field")}
+	for i, p := range profiles {
+		if i == 0 {
+			header = append(header, p.ID+" "+i18n.T("dedupe.keep"))
+		} else {
+			header = append(header, p.ID)
+		}
+	}
+	header = append(header, "→ "+i18n.T("dedupe.merged"))
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+
+	fields := []struct {
+		name  string
+		value func(p *storage.Profile) string
+	}{
+		{"name", func(p *storage.Profile) string { return p.Name }},
+		{"title", func(p *storage.Profile) string { return p.Title }},
+		{"company", func(p *storage.Profile) string { return p.Company }},
+		{"profile_url", func(p *storage.Profile) string { return p.ProfileURL }},
+		{"state", func(p *storage.Profile) string { return string(p.State) }},
+		{"discovered_at", func(p *storage.Profile) string { return p.DiscoveredAt.Format("2006-01-02 15:04") }},
+		{"notes", func(p *storage.Profile) string { return strings.ReplaceAll(p.Notes, "\n", " / ") }},
+	}
+	for _, f := range fields {
+		row := []string{"  " + f.name}
+		differs := false
+		for _, p := range profiles {
+			v := f.value(p)
+			differs = differs || v != f.value(profiles[0])
+			row = append(row, v)
+		}
+		if !differs {
+			continue
+		}
+		row = append(row, f.value(&merged))
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	row := []string{"  " + i18n.T("dedupe.messages")}
+	total := 0
+	for _, p := range profiles {
+		row = append(row, strconv.Itoa(g.Messages[p.ID]))
+		total += g.Messages[p.ID]
+	}
+	row = append(row, strconv.Itoa(total))
+	fmt.Fprintln(w, strings.Join(row, "\t"))
+	w.Flush()
+}
+
+// countDuplicates returns the number of profiles merged away across groups
+func countDuplicates(groups []dedupeGroup) int {
+	n := 0
+	for _, g := range groups {
+		n += len(g.Duplicates)
+	}
+	return n
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"subspace/internal/enrich"
+	"subspace/internal/i18n"
+)
+
+// enrich handles "enrich [-refresh]", filling company industry and size on
+// stored profiles from the configured provider
+func (c *cli) enrich(args []string) error {
+	fs := flag.NewFlagSet("enrich", flag.ContinueOnError)
+	refresh := fs.Bool("refresh", false, "Look every company up again, ignoring the cache")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	enricher, err := enrich.New(c.cfg.Enrichment, c.store)
+	if err != nil {
+		return err
+	}
+	if enricher == nil {
+		return fmt.Errorf("enrichment is disabled (set enrichment.provider to csv or http)")
+	}
+	result, err := enricher.EnrichAll(*refresh)
+	if err != nil {
+		return err
+	}
+	return render(c.output, result, func() {
+		fmt.Printf("🏢 %s\n", i18n.T("enrich.done", result.Profiles, result.Fetched, result.Cached, result.Unknown))
+		if result.Failed > 0 {
+			fmt.Printf("⚠️  %s\n", i18n.T("enrich.failed", result.Failed))
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"subspace/internal/estop"
+	"subspace/internal/i18n"
+)
+
+// stop handles "stop [reason]", engaging the emergency stop; a running
+// instance refuses its next action
+func (c *cli) stop(args []string) error {
+	reason := strings.Join(args, " ")
+	if reason == "" {
+		reason = "stopped by " + currentUser()
+	}
+	if err := estop.Engage(reason); err != nil {
+		return err
+	}
+	fmt.Printf("🛑 %s\n", i18n.T("estop.engaged", reason))
+	fmt.Printf("   %s\n", i18n.T("estop.resume_hint"))
+	return nil
+}
+
+// resume handles "resume", lifting the emergency stop
+func (c *cli) resume(args []string) error {
+	if err := estop.Release(); err != nil {
+		return err
+	}
+	if estop.State().Engaged {
+		fmt.Printf("⚠️  %s\n", i18n.T("estop.still_env", estop.EnvVar))
+		return nil
+	}
+	fmt.Printf("✅ %s\n", i18n.T("estop.released"))
+	return nil
+}
+
+// currentUser names the operator for a stop engaged without a reason
+func currentUser() string {
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "operator"
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"subspace/internal/bench"
+	"subspace/internal/i18n"
+	"subspace/internal/logger"
+)
+
+// experiment handles "experiment [<name>] [-seed n]", running a pacing
+// experiment from the config in simulation, or listing them without a name
+func (c *cli) experiment(args []string) error {
+	if len(args) == 0 || args[0] == "list" {
+		return c.experimentList()
+	}
+
+	name := args[0]
+	exp, ok := c.cfg.Experiments[name]
+	if !ok {
+		return fmt.Errorf("unknown experiment: %s", name)
+	}
+	fs := flag.NewFlagSet("experiment", flag.ContinueOnError)
+	seed := fs.Int64("seed", 0, "First random seed; each run uses the next one (0 = random)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "subspace-experiment-")
+	if err != nil {
+		return fmt.Errorf("failed to create experiment directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if !machineReadable(c.output) {
+		fmt.Printf("🧪 %s\n", i18n.T("experiment.running", name, len(exp.Arms), exp.Runs))
+	}
+	// Thousands of simulated actions would otherwise drown the report
+	logger.Init("error")
+	defer logger.Init(c.cfg.App.LogLevel)
+
+	report, err := bench.RunExperiment(c.cfg, name, exp, *seed, dir)
+	if err != nil {
+		return err
+	}
+	return render(c.output, report, func() {
+		fmt.Printf("\n🧪 %s\n\n", i18n.T("experiment.title", report.Name, report.Profiles, report.Days, report.Seeds))
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(w, i18n.T("experiment.header"))
+		for _, a := range report.Arms {
+			fmt.Fprintf(w, "%s\t%.1f\t%.1f\t%.0f%%\t%.1f\t%d\t%s\t%.2f\t%.2f\t%.1f\t%d\t\n",
+				a.Arm, a.Connections, a.Accepted, a.AcceptanceRate*100, a.Messages,
+				a.Pacing.PeakHour, a.Pacing.MedianGap.Round(time.Second), a.Pacing.GapCV,
+				a.Pacing.DailyCV, a.Pacing.ActiveHours, a.Violations)
+		}
+		w.Flush()
+		fmt.Printf("\n  %s\n", i18n.T("experiment.legend"))
+	})
+}
+
+// experimentList prints the configured experiments
+func (c *cli) experimentList() error {
+	names := make([]string, 0, len(c.cfg.Experiments))
+	for name := range c.cfg.Experiments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return render(c.output, c.cfg.Experiments, func() {
+		if len(names) == 0 {
+			fmt.Println(i18n.T("experiment.none"))
+			return
+		}
+		for _, name := range names {
+			exp := c.cfg.Experiments[name]
+			arms := make([]string, len(exp.Arms))
+			for i, a := range exp.Arms {
+				arms[i] = a.Name
+			}
+			fmt.Printf("  %s\n", i18n.T("experiment.item", name, arms, exp.Days, exp.Runs))
+		}
+	})
+}
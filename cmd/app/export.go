@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"subspace/internal/anonymize"
+	"subspace/internal/clock"
+	"subspace/internal/events"
+	"subspace/internal/i18n"
+	"subspace/internal/report"
+	"subspace/internal/rules"
+	"subspace/internal/storage"
+)
+
+// export handles "export events [-out target] [-since time]", "export
+// profiles", see exportProfiles, and "export anonymized", see
+// exportAnonymized
+func (c *cli) export(args []string) error {
+	if len(args) > 0 && args[0] == "profiles" {
+		return c.exportProfiles(args[1:])
+	}
+	if len(args) > 0 && args[0] == "anonymized" {
+		return c.exportAnonymized(args[1:])
+	}
+	if len(args) == 0 || args[0] != "events" {
+		return fmt.Errorf("usage: export events [-out file|-|url] [-since time] | export profiles [-out file|-] [-state s] [-tag t] [-where rule] [-since day] | export anonymized [-out file|-] [-since day] [-salt s]")
+	}
+	fs := flag.NewFlagSet("export events", flag.ContinueOnError)
+	out := fs.String("out", "-", "File to append to, - for stdout, or an http(s) URL to POST to")
+	since := fs.String("since", "", "Only events after this time (RFC 3339) or day (YYYY-MM-DD)")
+	batch := fs.Int("batch", 500, "Events per write or request")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *batch <= 0 {
+		return fmt.Errorf("-batch must be positive")
+	}
+	after, err := parseSince(*since)
+	if err != nil {
+		return fmt.Errorf("invalid -since: %w", err)
+	}
+
+	sink, err := events.Open(*out)
+	if err != nil {
+		return err
+	}
+	defer sink.Close()
+	list := events.Collect(c.store, after)
+	if err := events.Export(list, sink, *batch); err != nil {
+		return err
+	}
+	if *out != "-" {
+		last := "-"
+		if len(list) > 0 {
+			last = list[len(list)-1].Time.Format(time.RFC3339Nano)
+		}
+		fmt.Printf("📤 %s\n", i18n.T("export.done", len(list), *out, last))
+	}
+	return nil
+}
+
+// exportProfiles handles "export profiles [-out file|-] [-state state,...]
+// [-tag tag] [-where rule] [-since day]", writing the pipeline as CSV for
+// analysis in a spreadsheet
+func (c *cli) exportProfiles(args []string) error {
+	fs := flag.NewFlagSet("export profiles", flag.ContinueOnError)
+	out := fs.String("out", "-", "CSV file to write, - for stdout")
+	states := fs.String("state", "", "Only profiles in these comma-separated states")
+	tag := fs.String("tag", "", "Only profiles carrying this tag")
+	where := fs.String("where", "", "Only profiles matching a targeting rule")
+	since := fs.String("since", "", "Only profiles discovered after this time (RFC 3339) or day (YYYY-MM-DD)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var filter storage.ExportFilter
+	var err error
+	if filter.Since, err = parseSince(*since); err != nil {
+		return fmt.Errorf("invalid -since: %w", err)
+	}
+	if *states != "" {
+		for _, state := range strings.Split(*states, ",") {
+			filter.States = append(filter.States, storage.ProfileState(strings.TrimSpace(state)))
+		}
+	}
+	filter.Tag = *tag
+	if *where != "" {
+		rule, err := rules.Compile(*where)
+		if err != nil {
+			return err
+		}
+		filter.Match = func(p *storage.Profile) bool { return rule.Match(p.RuleEnv()) }
+	}
+
+	if *out == "-" {
+		_, err := c.store.ExportCSV(os.Stdout, filter)
+		return err
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	n, err := c.store.ExportCSV(f, filter)
+	if cerr := f.Close(); err == nil && cerr != nil {
+		err = fmt.Errorf("failed to write export file: %w", cerr)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("📤 %s\n", i18n.T("export.profiles_done", n, *out))
+	return nil
+}
+
+// exportAnonymized handles "export anonymized [-out file|-] [-since day]
+// [-salt secret]", writing the pipeline and last run's telemetry as JSON
+// with every name, URL and message text left out, for sharing
+func (c *cli) exportAnonymized(args []string) error {
+	fs := flag.NewFlagSet("export anonymized", flag.ContinueOnError)
+	out := fs.String("out", "-", "JSON file to write, - for stdout")
+	since := fs.String("since", "", "Only profiles discovered, and events, after this time (RFC 3339) or day (YYYY-MM-DD)")
+	salt := fs.String("salt", "", "Keeps pseudonyms stable across exports; by default each export gets new ones")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts := anonymize.Options{Salt: []byte(*salt)}
+	var err error
+	if opts.Since, err = parseSince(*since); err != nil {
+		return fmt.Errorf("invalid -since: %w", err)
+	}
+	if opts.Summary, err = report.ReadSummary(c.cfg.App.DataDir); err != nil {
+		return err
+	}
+	dataset, err := anonymize.Build(c.store, opts, clock.Now())
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(dataset, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode dataset: %w", err)
+	}
+	data = append(data, '\n')
+
+	if *out == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*out, data, 0644); err != nil {
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+	fmt.Printf("📤 %s\n", i18n.T("export.anonymized_done", len(dataset.Profiles), len(dataset.Messages), len(dataset.Actions), *out))
+	return nil
+}
+
+// parseSince parses an RFC 3339 time or a local YYYY-MM-DD day; "" is the
+// zero time
+func parseSince(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	return parseDay(s)
+}
+
+// streamsToStdout reports whether a subcommand writes its data to stdout,
+// which the banner and logs must then stay out of
+func streamsToStdout(args []string) bool {
+	if len(args) < 2 || args[0] != "export" {
+		return false
+	}
+	for i, arg := range args[2:] {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name != "out" {
+			continue
+		}
+		if !hasValue && i+3 < len(args) {
+			value = args[i+3]
+		}
+		return value == "-"
+	}
+	return true
+}
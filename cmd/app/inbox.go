@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"subspace/internal/browser"
+	"subspace/internal/i18n"
+	"subspace/internal/logger"
+	"subspace/internal/messaging"
+	"subspace/internal/stealth"
+	"subspace/internal/storage"
+)
+
+// inbox handles "inbox [list | reply <profile-id> <text>]". Without
+// arguments it walks the conversations awaiting an answer, so the operator
+// can reply by hand; replies are typed by the stealth engine like any
+// automated message.
+func (c *cli) inbox(args []string) error {
+	if len(args) == 0 {
+		if machineReadable(c.output) {
+			return c.inboxList()
+		}
+		return c.inboxInteractive()
+	}
+
+	switch args[0] {
+	case "list":
+		return c.inboxList()
+	case "reply":
+		if len(args) < 3 {
+			return fmt.Errorf("usage: inbox reply <profile-id> <text>")
+		}
+		p, err := c.store.GetProfile(args[1])
+		if err != nil {
+			return err
+		}
+		m, closeBrowser, err := c.messenger()
+		if err != nil {
+			return err
+		}
+		defer closeBrowser()
+		if err := m.SendReply(p, strings.Join(args[2:], " ")); err != nil {
+			return err
+		}
+		fmt.Printf("✅ %s\n", i18n.T("inbox.sent", p.Name))
+		return nil
+	default:
+		return fmt.Errorf("unknown inbox command: %s", args[0])
+	}
+}
+
+// inboxList prints the conversations awaiting an answer
+func (c *cli) inboxList() error {
+	inbox := c.store.Inbox()
+	return render(c.output, inbox, func() {
+		fmt.Printf("\n📥 %s\n\n", i18n.T("inbox.title", len(inbox)))
+		if len(inbox) == 0 {
+			fmt.Printf("  %s\n", i18n.T("inbox.empty"))
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, i18n.T("inbox.header"))
+		for _, conv := range inbox {
+			latest := conv.Unanswered[len(conv.Unanswered)-1]
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n",
+				conv.Profile.ID, conv.Profile.Name, conv.WaitingSince().Format("2006-01-02 15:04"),
+				len(conv.Unanswered), strings.Join(strings.Fields(latest.Content), " "))
+		}
+		w.Flush()
+	})
+}
+
+// inboxInteractive shows each conversation awaiting an answer and sends the
+// operator's reply, until the inbox is done or the operator quits
+func (c *cli) inboxInteractive() error {
+	inbox := c.store.Inbox()
+	if len(inbox) == 0 {
+		fmt.Printf("\n✅ %s\n", i18n.T("inbox.empty"))
+		return nil
+	}
+
+	// The browser is only launched once there is a reply to send
+	var m *messaging.Messenger
+	closeBrowser := func() {}
+	defer func() { closeBrowser() }()
+
+	in := bufio.NewReader(os.Stdin)
+	sent := 0
+	fmt.Printf("\n📥 %s\n", i18n.T("inbox.title", len(inbox)))
+	for i, conv := range inbox {
+		p := conv.Profile
+		fmt.Printf("\n[%d/%d] %s\n", i+1, len(inbox), p.Name)
+		fmt.Printf("   %s\n", strings.TrimSpace(p.Title+" @ "+p.Company))
+		printThread("   ", conv.Thread)
+
+		answer := ""
+		for answer == "" {
+			fmt.Printf("   %s ", i18n.T("inbox.prompt"))
+			line, err := in.ReadString('\n')
+			answer = strings.ToLower(strings.TrimSpace(line))
+			if err != nil && answer == "" {
+				answer = "q" // stdin closed
+			}
+			if answer != "r" && answer != "n" && answer != "q" {
+				answer = ""
+			}
+		}
+		if answer == "q" {
+			break
+		}
+		if answer == "n" {
+			continue
+		}
+
+		fmt.Printf("   %s ", i18n.T("inbox.reply_prompt"))
+		text, _ := in.ReadString('\n')
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if m == nil {
+			var err error
+			if m, closeBrowser, err = c.messenger(); err != nil {
+				return err
+			}
+		}
+		if err := m.SendReply(p, text); err != nil {
+			fmt.Printf("   ⚠️  %v\n", err)
+			continue
+		}
+		fmt.Printf("   ✅ %s\n", i18n.T("inbox.sent", p.Name))
+		sent++
+	}
+
+	fmt.Printf("\n✅ %s\n", i18n.T("inbox.summary", sent))
+	return nil
+}
+
+// printThread prints the messages of a conversation, oldest first
+func printThread(indent string, thread []*storage.Message) {
+	for _, msg := range thread {
+		who := i18n.T("inbox.you")
+		if msg.Inbound {
+			who = i18n.T("inbox.them")
+		}
+		fmt.Printf("%s%s  %-8s %s\n", indent, msg.SentAt.Format("2006-01-02 15:04"), who+":",
+			strings.Join(strings.Fields(msg.Content), " "))
+	}
+}
+
+// messenger launches a browser with the stealth engine for commands that
+// act on the site, returning a messenger and a function that closes the
+// browser
+func (c *cli) messenger() (*messaging.Messenger, func(), error) {
+	logger.Info("Initializing browser", "headless", c.cfg.App.Headless)
+	b, err := browser.New(c.cfg.App)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize browser: %w", err)
+	}
+	closeBrowser := func() {
+		if err := b.Close(); err != nil {
+			logger.Error("Error closing browser", "error", err)
+		}
+	}
+
+	s := stealth.New(c.cfg.Stealth, b.Page)
+	if err := s.MaskFingerprint(); err != nil {
+		logger.Warn("Failed to apply fingerprint masking", "error", err)
+	}
+	return messaging.New(b, s, c.store, c.cfg.Limits, c.cfg.Messaging), closeBrowser, nil
+}
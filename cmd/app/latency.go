@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"subspace/internal/clock"
+	"subspace/internal/i18n"
+	"subspace/internal/report"
+)
+
+// latency handles "stats latency", the request-to-acceptance latency per
+// search keyword
+func (c *cli) latency() error {
+	rows := report.AcceptanceLatency(c.store.GetAllProfiles(), clock.Now())
+
+	return render(c.output, rows, func() {
+		fmt.Printf("\n⏱️  %s\n\n", i18n.T("latency.title"))
+		if len(rows) == 0 {
+			fmt.Printf("  %s\n", i18n.T("latency.none"))
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  "+i18n.T("latency.header"))
+		for _, r := range rows {
+			keyword := r.Keyword
+			switch keyword {
+			case report.AllKeywords:
+				keyword = i18n.T("latency.all")
+			case "":
+				keyword = i18n.T("latency.no_keyword")
+			}
+			median, p90, longest := "-", "-", "-"
+			if r.Accepted > 0 {
+				median, p90, longest = fmt.Sprintf("%.1fh", r.MedianHours), fmt.Sprintf("%.1fh", r.P90Hours), fmt.Sprintf("%.1fh", r.MaxHours)
+			}
+			fmt.Fprintf(w, "  %s\t%d\t%d\t%s\t%s\t%s\t%d\n",
+				keyword, r.Accepted, r.Pending, median, p90, longest, r.PendingPastP90)
+		}
+		w.Flush()
+		fmt.Printf("\n💡 %s\n", i18n.T("latency.hint"))
+	})
+}
@@ -4,17 +4,42 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
-	"subspace/internal/auth"
+	"subspace/internal/accounts"
+	"subspace/internal/app"
+	"subspace/internal/bandwidth"
+	"subspace/internal/batch"
 	"subspace/internal/browser"
+	"subspace/internal/calendar"
+	"subspace/internal/clock"
 	"subspace/internal/config"
 	"subspace/internal/connect"
+	"subspace/internal/crash"
+	"subspace/internal/enrich"
+	"subspace/internal/estop"
+	"subspace/internal/harden"
+	"subspace/internal/i18n"
+	"subspace/internal/ids"
 	"subspace/internal/logger"
+	"subspace/internal/maintenance"
 	"subspace/internal/messaging"
+	"subspace/internal/metrics"
+	"subspace/internal/notify"
+	"subspace/internal/proxy"
+	"subspace/internal/report"
+	"subspace/internal/runlock"
+	"subspace/internal/schedule"
+	"subspace/internal/seal"
 	"subspace/internal/search"
 	"subspace/internal/stealth"
 	"subspace/internal/storage"
+	"subspace/internal/version"
+	"subspace/internal/watchdog"
+	"subspace/internal/workflow"
 )
 
 /*
@@ -46,45 +71,234 @@ func main() {
 	configPath := flag.String("config", "config.yaml", "Path to configuration file")
 	demoMode := flag.Bool("demo", false, "Run in demo mode (shows stealth techniques)")
 	statsOnly := flag.Bool("stats", false, "Show statistics and exit")
+	exportPath := flag.String("export", "", "Write the profiles as CSV to this file (- for stdout) and exit")
+	output := flag.String("output", outputTable, "Output format for stats/plan/profiles: table, json or yaml")
+	forceTakeover := flag.Bool("force-takeover", false, "Stop the instance running on the data directory and take over")
+	containerFlag := flag.Bool("container", false, "Run in container mode (also SUBSPACE_CONTAINER=1, or detected)")
+	tagFlag := flag.String("tag", "", "Only contact profiles carrying one of these comma-separated tags this run")
 	flag.Parse()
+	container := *containerFlag || inContainer()
 
-	// Banner
-	printBanner()
+	if !validOutput(*output) {
+		fmt.Printf("❌ Invalid -output %q (must be table, json or yaml)\n", *output)
+		os.Exit(1)
+	}
 
-	// 1. Load Configuration
-	fmt.Println("📋 Loading configuration...")
-	cfg, err := config.Load(*configPath)
+	// Keep stdout clean when output is meant to be piped into jq or scripts
+	if machineReadable(*output) || streamsToStdout(flag.Args()) {
+		logger.SetOutput(os.Stderr)
+	} else if container {
+		// Only JSON logs on stdout for the log collector; the console
+		// output of a run goes to stderr
+		if !*statsOnly && len(flag.Args()) == 0 {
+			os.Stdout = os.Stderr
+		}
+	} else {
+		// Banner
+		printBanner()
+		fmt.Println("📋 Loading configuration...")
+	}
+
+	// 1. Load Configuration, from the environment in a container
+	load := config.Load
+	if container {
+		load = func(path string) (*config.Config, error) { return config.LoadEnv(path, os.Environ()) }
+	}
+	cfg, err := load(*configPath)
 	if err != nil {
 		fmt.Printf("❌ Failed to load config: %v\n", err)
 		os.Exit(1)
 	}
+	if container {
+		applyContainer(cfg, !*statsOnly && len(flag.Args()) == 0)
+	}
+	if *tagFlag != "" {
+		tags, err := parseTags(*tagFlag)
+		if err != nil {
+			fmt.Printf("❌ Invalid -tag: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.Targeting.Tags = tags
+	}
 
-	// 2. Initialize Logger
+	// 2. Initialize Logger and console language
 	logger.Init(cfg.App.LogLevel)
+	if err := i18n.SetLanguage(cfg.App.Language); err != nil {
+		logger.Warn("Falling back to English console output", "error", err)
+	}
+	if gen, err := ids.ForFormat(cfg.App.IDFormat); err == nil {
+		ids.Set(gen) // Validated with the config
+	}
+	if _, err := calendar.New(cfg.Stealth); err != nil {
+		logger.Error("Invalid holiday calendar", "error", err)
+		os.Exit(1)
+	}
+	if cfg.App.TimeZone != "" {
+		// Business hours and days off follow the account's time zone
+		time.Local, _ = time.LoadLocation(cfg.App.TimeZone) // Validated with the config
+	}
 	logger.Info("Starting Subspace Automation PoC",
-		"version", "1.0.0",
-		"mode", getMode(*demoMode, *statsOnly))
+		"version", version.Info().String(),
+		"mode", getMode(*demoMode, *statsOnly),
+		"container", container)
+	if !cfg.App.Headless && noDisplay() {
+		logger.Warn("No display found, running Chrome headless")
+		cfg.App.Headless = true
+	}
+
+	estop.SetFile(cfg.App.StopFile)
+
+	// Restrict the data directories before anything is written to them
+	if _, err := harden.Apply(cfg.Hardening, dataDirs(cfg)...); err != nil {
+		logger.Error("Insecure data directory", "error", err)
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	// One instance per data directory, or db.json gets clobbered. Stats,
+	// exports and read-only commands skip the lock and open storage
+	// read-only instead.
+	lockFree := *statsOnly || *exportPath != "" || readOnly(flag.Args())
+	if !lockFree {
+		acquire := runlock.Acquire
+		if *forceTakeover {
+			acquire = func(dir string) (*runlock.Lock, error) { return runlock.Takeover(dir, 30*time.Second) }
+		}
+		lock, err := acquire(cfg.App.DataDir)
+		if err != nil {
+			logger.Error("Failed to lock the data directory", "error", err)
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		defer lock.Release()
+	}
 
 	// 3. Initialize Storage
-	logger.Info("Initializing storage", "path", cfg.App.DataDir)
-	db, err := storage.New(cfg.App.DataDir + "/db.json")
+	if err := seal.Configure(cfg.Storage.Encryption); err != nil {
+		logger.Error("Failed to load the encryption key", "error", err)
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	logger.Info("Initializing storage", "path", cfg.App.DataDir, "encrypted", cfg.Storage.Encryption.Enabled)
+	openStorage := storage.New
+	if lockFree {
+		openStorage = storage.NewReadOnly
+	}
+	db, err := openStorage(cfg.App.DataDir+"/db.json", cfg.Storage)
 	if err != nil {
 		logger.Error("Failed to initialize storage", "error", err)
 		os.Exit(1)
 	}
+	db.SetAccount(cfg.App.Account)
+	defer db.Close()
+	flushOnSignal(db)
+	backend, err := openBackend(cfg, db)
+	if err != nil {
+		logger.Error("Failed to open storage backend", "error", err)
+		closeAndExit(1, db)
+	}
+	defer backend.Close()
 
 	// Show stats if requested
 	if *statsOnly {
-		showStats(db)
+		if err := showStats(backend, *output); err != nil {
+			logger.Error("Failed to show stats", "error", err)
+			closeAndExit(1, backend, db)
+		}
+		return
+	}
+
+	// Export the profiles if requested, as "export profiles -out" does
+	if *exportPath != "" {
+		c := &cli{cfg: cfg, db: db, store: backend, output: *output, configPath: *configPath}
+		if err := c.exportProfiles([]string{"-out", *exportPath}); err != nil {
+			logger.Error("Failed to export profiles", "error", err)
+			fmt.Printf("❌ %v\n", err)
+			closeAndExit(1, backend, db)
+		}
+		return
+	}
+
+	// Run a subcommand if one was given (e.g. "subspace maintenance run")
+	if args := flag.Args(); len(args) > 0 {
+		c := &cli{cfg: cfg, db: db, store: backend, output: *output, configPath: *configPath}
+		guard := crash.New(cfg.App.DataDir, backend, nil)
+		if err := guard.Guard("command "+args[0], func() error { return c.run(args) }); err != nil {
+			logger.Error("Command failed", "command", args[0], "error", err)
+			fmt.Printf("❌ %v\n", err)
+			closeAndExit(1, backend, db)
+		}
 		return
 	}
 
-	// 4. Initialize Browser
+	// Nothing goes out while the emergency stop is engaged
+	if status := estop.State(); status.Engaged {
+		logger.Warn("Emergency stop engaged, not running", "source", status.Source, "reason", status.Reason)
+		fmt.Printf("🛑 %s\n", i18n.T("estop.refused", status.Source, status.Reason))
+		closeAndExit(1, backend, db)
+	}
+
+	// Expose metrics and the API if configured, only for runs: stats and
+	// commands exit before binding any port
+	if cfg.App.MetricsAddr != "" {
+		metrics.Handle("/healthz", healthHandler())
+		logger.Info("Serving metrics", "addr", cfg.App.MetricsAddr)
+		go func() {
+			if err := metrics.Serve(cfg.App.MetricsAddr); err != nil {
+				logger.Error("Metrics server stopped", "error", err)
+			}
+		}()
+	}
+	if cfg.App.APIAddr != "" {
+		serveAPI(cfg, backend)
+	}
+
+	// 4. Initialize Browser, as the account it is bound to
+	if account, ok := cfg.ActiveAccount(); ok {
+		registry, err := accounts.Load(cfg.App.DataDir)
+		if err == nil {
+			err = registry.Check(cfg.App.Account, account)
+		}
+		if err != nil {
+			logger.Error("Account binding check failed", "account", cfg.App.Account, "error", err)
+			fmt.Printf("❌ %v\n", err)
+			closeAndExit(1, backend, db)
+		}
+		logger.Info("Running as account", "account", cfg.App.Account,
+			"proxy", config.ProxyHost(account.Proxy), "time_zone", account.TimeZone, "persona", account.Persona)
+	}
+	var sessions *proxy.Sessions
+	if cfg.App.Proxy != "" {
+		if sessions, err = proxy.NewSessions(cfg.ProxySession, cfg.App.DataDir, cfg.App.Account); err != nil {
+			logger.Error("Failed to start proxy session", "error", err)
+			closeAndExit(1, backend, db)
+		}
+	}
+	proxies := proxy.ForConfig(cfg, sessions)
+	if proxies != nil {
+		selected, results, err := proxies.Select()
+		for _, r := range results {
+			if !r.Healthy() {
+				fmt.Printf("⚠️  %s\n", i18n.T("proxy.unhealthy", r.Proxy, r.Err))
+			}
+		}
+		if err != nil {
+			logger.Error("Proxy check failed", "error", err)
+			fmt.Printf("🛑 %s\n", i18n.T("proxy.none_healthy", config.ProxyHost(cfg.App.Proxy)))
+			closeAndExit(1, backend, db)
+		}
+		if selected != cfg.App.Proxy {
+			fmt.Printf("🔀 %s\n", i18n.T("proxy.failover", config.ProxyHost(cfg.App.Proxy), config.ProxyHost(selected)))
+		}
+		cfg.App.Proxy = selected
+	}
+	upstream := cfg.App.Proxy
+	cfg.App.Proxy = sessions.Apply(upstream)
 	logger.Info("Initializing browser", "headless", cfg.App.Headless)
 	b, err := browser.New(cfg.App)
 	if err != nil {
 		logger.Error("Failed to initialize browser", "error", err)
-		os.Exit(1)
+		closeAndExit(1, backend, db)
 	}
 	defer func() {
 		logger.Info("Shutting down browser")
@@ -97,6 +311,15 @@ func main() {
 	logger.Info("Initializing stealth engine")
 	s := stealth.New(cfg.Stealth, b.Page)
 	logger.Info(s.Summary())
+	if cfg.Stealth.TraceActions {
+		rec := stealth.NewRecorder(cfg.App.DataDir)
+		s.SetRecorder(rec)
+		logger.Info("Tracing actions", "path", rec.Path())
+	}
+	if ws := applyWorkSession(s, cfg.App.DataDir); ws != nil {
+		logger.Warn("Running in manual work session", "until", ws.ExpiresAt.Format(time.RFC3339), "reason", ws.Reason)
+		fmt.Printf("🟢 %s\n", i18n.T("session.running", ws.ExpiresAt.Format("15:04")))
+	}
 
 	// Apply fingerprint masking
 	if err := s.MaskFingerprint(); err != nil {
@@ -105,235 +328,454 @@ func main() {
 
 	// 6. Initialize Modules
 	logger.Info("Initializing automation modules")
-	authenticator := auth.New(b, s, db)
-	searcher := search.New(b, s, db)
-	connector := connect.New(b, s, db, cfg.Limits)
-	messenger := messaging.New(b, s, db, cfg.Limits)
+	modules, err := app.New(cfg, backend, b, s, app.Options{})
+	if err != nil {
+		logger.Error("Failed to initialize modules", "error", err)
+		closeAndExit(1, backend, db)
+	}
 
 	// 7. Run Demo or Automation Flow
 	if *demoMode {
 		runDemo(s, b)
 	} else {
-		runAutomation(cfg, s, authenticator, searcher, connector, messenger)
+		if cfg.Retention.RunOnStart {
+			if _, err := maintenance.Run(cfg.Retention, backend, cfg.App.DataDir); err != nil {
+				logger.Warn("Retention maintenance failed", "error", err)
+			}
+		}
+		rec := &recovery{b: b, s: s, auth: modules.Auth, max: cfg.App.BrowserRestarts,
+			proxies: proxies, sessions: sessions, upstream: upstream, crashes: crash.New(cfg.App.DataDir, backend, s)}
+		rec.watchdog = watchdog.New(cfg.Watchdog, rec, func() error {
+			_, err := db.Compact()
+			return err
+		})
+		if cfg.Bandwidth.Enabled {
+			rec.meter = bandwidth.NewMeter(cfg.App.Account)
+			b.OnBytes(rec.meter.Add)
+			modules.Connect.SetMeter(rec.meter)
+		}
+		summary := runAutomation(cfg, s, rec, modules)
+		summary.BrowserRestarts = rec.restarts
+		if rec.meter != nil {
+			run, err := rec.meter.Finish(cfg.App.DataDir)
+			if err != nil {
+				logger.Warn("Failed to record bandwidth", "error", err)
+			}
+			logger.Info("Run bandwidth", "bytes", run.Bytes, "requests", run.Requests)
+			summary.BandwidthBytes = run.Bytes
+			fmt.Printf("📶 %s\n", i18n.T("bandwidth.run", formatBytes(run.Bytes), run.Requests))
+		}
+		writeSummary(cfg, backend, summary)
 	}
 
 	logger.Info("Application shutdown complete")
 }
 
-// runAutomation executes the main automation workflow
+// runAutomation executes the main automation workflow and returns its
+// summary, finished but for what only main knows
 func runAutomation(
 	cfg *config.Config,
 	s *stealth.Stealth,
-	authenticator *auth.Authenticator,
-	searcher *search.Searcher,
-	connector *connect.Connector,
-	messenger *messaging.Messenger,
-) {
-	logger.Info("Starting automation workflow")
+	rec *recovery,
+	modules *app.App,
+) *report.RunSummary {
+	authenticator, searcher, enricher := modules.Auth, modules.Search, modules.Enrich
+	connector, messenger, notifier := modules.Connect, modules.Messaging, modules.Notifier
+	logger.Info("Starting automation workflow", "modules", cfg.Modules.Enabled())
+	summary := report.NewRunSummary(version.Info().Version, cfg.App.Account, clock.Now())
+	rec.summary = summary
+	messenger.SetSession(sessionStart(cfg))
 
 	// Check Business Hours
 	if !s.CheckBusinessHours() {
 		logger.Warn("Outside business hours")
-		fmt.Println("\n⏰ Current time is outside configured business hours")
-		fmt.Println("   Configure business_hours in config.yaml to adjust")
-		return
+		if off, reason := s.DayOff(); off {
+			fmt.Printf("\n🏖️  %s\n", i18n.T("run.day_off", reason))
+			summary.Outcome, summary.Reason = report.OutcomeDayOff, reason
+			return summary
+		}
+		fmt.Printf("\n⏰ %s\n", i18n.T("run.outside_hours"))
+		fmt.Printf("   %s\n", i18n.T("run.outside_hours_hint"))
+		summary.Outcome = report.OutcomeOutsideHours
+		return summary
 	}
 
 	// Step 1: Authentication
-	fmt.Println("\n🔐 Step 1: Authentication")
+	fmt.Printf("\n🔐 %s\n", i18n.T("run.step_auth"))
 	logger.Info("Attempting login")
 	
-	if err := authenticator.Login(); err != nil {
+	if err := rec.guard("auth", authenticator.Login); err != nil {
 		logger.Error("Login failed", "error", err)
-		fmt.Printf("❌ Login failed: %v\n", err)
-		fmt.Println("   NOTE: This is expected in PoC mode (no real credentials)")
+		fmt.Printf("❌ %s\n", i18n.T("run.login_failed", err))
+		fmt.Printf("   %s\n", i18n.T("run.login_failed_note"))
 		// Continue anyway for demo purposes
 	} else {
-		fmt.Println("✅ Login successful (session restored or mock login)")
+		fmt.Printf("✅ %s\n", i18n.T("run.login_ok"))
+		rec.checkpoint()
 	}
 
 	// Small delay between major steps
 	s.ThinkingPause()
 
-	// Step 2: Search
-	fmt.Println("\n🔍 Step 2: Search & Discovery")
-	logger.Info("Running search")
-	
-	keywords := "Software Engineer"
-	if err := searcher.RunSearch(keywords, 2); err != nil {
-		logger.Error("Search failed", "error", err)
-		fmt.Printf("❌ Search failed: %v\n", err)
-	} else {
-		fmt.Println("✅ Search completed - profiles discovered")
+	steps := map[string]func(){
+		workflow.Search: func() {
+			fmt.Printf("\n🔍 %s\n", i18n.T("run.step_search"))
+			logger.Info("Running search")
+
+			// Empty keywords search for the configured default keywords
+			filters := search.SearchFilters{Locations: cfg.Targeting.Locations, MaxPages: 2}
+			if !searcher.CanSearchMore() {
+				fmt.Printf("⚠️  %s\n", i18n.T("run.search_limit"))
+			} else if err := rec.step("search", func() error { return searcher.SearchByFilters("", filters) }); err != nil {
+				logger.Error("Search failed", "error", err)
+				fmt.Printf("❌ %s\n", i18n.T("run.search_failed", err))
+			} else {
+				fmt.Printf("✅ %s\n", i18n.T("run.search_ok"))
+			}
+			if enricher != nil {
+				var result enrich.Result
+				if err := rec.guard("enrich", func() (err error) {
+					result, err = enricher.EnrichAll(false)
+					return err
+				}); err != nil {
+					logger.Warn("Enrichment failed", "error", err)
+				} else if result.Profiles > 0 {
+					fmt.Printf("🏢 %s\n", i18n.T("run.enriched", result.Profiles))
+				}
+			}
+			s.ThinkingPause()
+		},
+		workflow.Connect: func() {
+			fmt.Printf("\n🤝 %s\n", i18n.T("run.step_connect"))
+			if !connector.CanSendMore() {
+				fmt.Printf("⚠️  %s\n", i18n.T("run.connect_limit"))
+				return
+			}
+			logger.Info("Processing connections")
+			var result *batch.Result
+			err := rec.step("connect", func() (err error) {
+				result, err = connector.ProcessDailyConnections()
+				summary.AddBatch(result)
+				return err
+			})
+			if err != nil {
+				logger.Error("Connection processing failed", "error", err)
+				fmt.Printf("❌ %s\n", i18n.T("run.connect_failed", err))
+			} else {
+				fmt.Printf("✅ %s\n", i18n.T("run.connect_ok"))
+				printBatch(result)
+			}
+			s.ThinkingPause()
+		},
+		workflow.Acceptance: func() {
+			fmt.Printf("\n✉️  %s\n", i18n.T("run.step_accepted"))
+			logger.Info("Checking for acceptances")
+			if err := rec.step("acceptance", connector.CheckAcceptedConnections); err != nil {
+				logger.Error("Acceptance check failed", "error", err)
+			} else {
+				accepted := connector.GetAcceptedConnections()
+				fmt.Printf("✅ %s\n", i18n.T("run.accepted_found", len(accepted)))
+			}
+			s.ThinkingPause()
+		},
+		workflow.Messaging: func() {
+			fmt.Printf("\n💬 %s\n", i18n.T("run.step_message"))
+			if !messenger.CanSendMore() {
+				fmt.Printf("⚠️  %s\n", i18n.T("run.message_limit"))
+				return
+			}
+			if messenger.SessionFull() {
+				fmt.Printf("⚠️  %s\n", i18n.T("run.session_cap", cfg.Limits.MessagesPerSession))
+				return
+			}
+			logger.Info("Processing messages")
+			var result *batch.Result
+			err := rec.step("messaging", func() (err error) {
+				result, err = messenger.ProcessAcceptedConnections()
+				summary.AddBatch(result)
+				return err
+			})
+			if err != nil {
+				logger.Error("Messaging failed", "error", err)
+				fmt.Printf("❌ %s\n", i18n.T("run.message_failed", err))
+			} else {
+				fmt.Printf("✅ %s\n", i18n.T("run.message_ok"))
+				printBatch(result)
+			}
+		},
 	}
 
-	s.ThinkingPause()
-
-	// Step 3: Connections
-	fmt.Println("\n🤝 Step 3: Connection Requests")
-	logger.Info("Processing connections")
-	
-	if connector.CanSendMore() {
-		if err := connector.ProcessDailyConnections(); err != nil {
-			logger.Error("Connection processing failed", "error", err)
-			fmt.Printf("❌ Connection processing failed: %v\n", err)
-		} else {
-			fmt.Println("✅ Connection requests processed")
+	// Steps 2-5: search, connect, acceptance and messaging, in the order
+	// of the workflow strategy
+	plan := workflow.For(cfg.Workflow, cfg.Modules, clock.Now())
+	logger.Info("Workflow plan", "strategy", plan.Strategy, "steps", plan.Steps, "rounds", plan.Rounds, "batch", plan.Batch)
+	fmt.Printf("\n🗺️  %s\n", i18n.T("run.plan", plan.Strategy))
+	for _, name := range []string{workflow.Search, workflow.Connect, workflow.Acceptance, workflow.Messaging} {
+		if !slices.Contains(cfg.Modules.Enabled(), name) {
+			skipModule(name)
 		}
-	} else {
-		fmt.Println("⚠️  Daily connection limit reached")
 	}
-
-	s.ThinkingPause()
-
-	// Step 4: Check for accepted connections
-	fmt.Println("\n✉️  Step 4: Check Accepted Connections")
-	logger.Info("Checking for acceptances")
-	
-	if err := connector.CheckAcceptedConnections(); err != nil {
-		logger.Error("Acceptance check failed", "error", err)
-	} else {
-		accepted := connector.GetAcceptedConnections()
-		fmt.Printf("✅ Found %d accepted connections\n", len(accepted))
+	for _, name := range plan.Steps {
+		steps[name]()
 	}
-
-	s.ThinkingPause()
-
-	// Step 5: Messaging
-	fmt.Println("\n💬 Step 5: Follow-up Messaging")
-	logger.Info("Processing messages")
-	
-	if messenger.CanSendMore() {
-		if err := messenger.ProcessAcceptedConnections(); err != nil {
-			logger.Error("Messaging failed", "error", err)
-			fmt.Printf("❌ Messaging failed: %v\n", err)
-		} else {
-			fmt.Println("✅ Follow-up messages sent")
+	if len(plan.Rounds) > 0 {
+		connector.SetBatch(plan.Batch)
+		messenger.SetBatch(plan.Batch)
+		defer connector.SetBatch(0)
+		defer messenger.SetBatch(0)
+
+		// Every round that sends something uses up the daily budgets, so
+		// this bounds the rounds even if counting went wrong
+		maxRounds := (cfg.Limits.ConnectionsPerDay+cfg.Limits.MessagesPerDay)/plan.Batch + 1
+		for round := 1; round <= maxRounds; round++ {
+			before := sentToday(connector, messenger)
+			fmt.Printf("\n🔁 %s\n", i18n.T("run.round", round, plan.Batch))
+			for _, name := range plan.Rounds {
+				steps[name]()
+			}
+			if sentToday(connector, messenger) == before {
+				break
+			}
+		}
+	}
+	if cfg.Modules.Messaging {
+		var waiting int
+		if err := rec.guard("inbox", func() (err error) {
+			waiting, err = messenger.CheckInbox()
+			return err
+		}); err != nil {
+			logger.Error("Inbox check failed", "error", err)
+		} else if waiting > 0 {
+			fmt.Printf("📥 %s\n", i18n.T("run.inbox_waiting", waiting))
 		}
-	} else {
-		fmt.Println("⚠️  Daily message limit reached")
 	}
 
 	// Final Summary
-	fmt.Println("\n📊 Workflow Summary")
+	fmt.Printf("\n📊 %s\n", i18n.T("run.summary"))
 	connStats := connector.GetStats()
 	msgStats := messenger.GetStats()
 	
-	fmt.Printf("   Connections today: %v/%v\n", 
-		connStats["connections_today"], 
-		connStats["limit_daily"])
-	fmt.Printf("   Messages today: %v/%v\n", 
-		msgStats["messages_today"], 
-		msgStats["limit_daily"])
-	fmt.Printf("   Pending requests: %v\n", 
-		connStats["pending_requests"])
-	fmt.Printf("   Accepted connections: %v\n", 
-		connStats["accepted_connections"])
+	fmt.Printf("   %s\n", i18n.T("run.summary_connections",
+		connStats.ConnectionsToday,
+		connStats.LimitDaily))
+	fmt.Printf("   %s\n", i18n.T("run.summary_messages",
+		msgStats.MessagesToday,
+		msgStats.LimitDaily))
+	fmt.Printf("   %s\n", i18n.T("run.summary_pending",
+		connStats.PendingRequests))
+	fmt.Printf("   %s\n", i18n.T("run.summary_accepted",
+		connStats.AcceptedConnections))
+	for _, o := range s.Overhead() {
+		logger.Info("Stealth overhead", "action", o.Action, "actions", o.Actions,
+			"delay_seconds", o.Delay.Seconds(), "work_seconds", o.Work().Seconds())
+		summary.AddOverhead(o.Action, o.Actions, o.Total, o.Delay)
+		if o.Total > 0 {
+			fmt.Printf("   %s\n", i18n.T("run.summary_overhead", o.Action,
+				o.Delay.Round(time.Second), o.Work().Round(time.Second), int(o.DelayShare()*100)))
+		}
+	}
+	notifier.Notify(notify.DailySummary, i18n.T("run.summary"), strings.Join([]string{
+		i18n.T("run.summary_connections", connStats.ConnectionsToday, connStats.LimitDaily),
+		i18n.T("run.summary_messages", msgStats.MessagesToday, msgStats.LimitDaily),
+		i18n.T("run.summary_accepted", connStats.AcceptedConnections),
+	}, "\n"))
+
+	// Counted from the start of the run, which may have begun yesterday
+	summary.ConnectionsSent = modules.Storage.GetActionCountSince("connection", summary.StartedAt)
+	summary.MessagesSent = modules.Storage.GetActionCountSince("message", summary.StartedAt)
+	summary.Pending = connStats.PendingRequests
+	summary.Accepted = connStats.AcceptedConnections
+	summary.Limits = []report.LimitUsage{
+		{Action: "connection", Used: connStats.ConnectionsToday, Limit: connStats.LimitDaily},
+		{Action: "message", Used: msgStats.MessagesToday, Limit: msgStats.LimitDaily},
+	}
 
 	logger.Info("Automation cycle complete")
 
 	// Keep browser open briefly in non-headless mode
 	if !cfg.App.Headless {
-		fmt.Println("\n⏳ Keeping browser open for 5 seconds...")
+		fmt.Printf("\n⏳ %s\n", i18n.T("run.keep_open", 5))
 		time.Sleep(5 * time.Second)
 	}
+	return summary
+}
+
+// writeSummary finishes the run summary with the next scheduled session
+// and writes it to the data directory
+func writeSummary(cfg *config.Config, db storage.Backend, summary *report.RunSummary) {
+	now := clock.Now()
+	if events, err := upcoming(cfg, db, cfg.App.ScheduleHours); err == nil {
+		for _, e := range events {
+			if (e.Kind == schedule.KindSession || e.Kind == schedule.KindWorkSession) && e.At.After(now) {
+				at := e.At
+				summary.NextRun = &at
+				break
+			}
+		}
+	}
+	summary.Finish(now)
+	path, err := summary.Write(cfg.App.DataDir)
+	if err != nil {
+		logger.Warn("Failed to write run summary", "error", err)
+		return
+	}
+	logger.Info("Run summary written", "path", path, "outcome", summary.Outcome)
+}
+
+// printBatch shows what a batch did, and why each profile that failed did
+func printBatch(result *batch.Result) {
+	if result == nil || len(result.Items) == 0 {
+		return
+	}
+	fmt.Printf("   %s\n", i18n.T("run.batch_counts",
+		result.Count(batch.Sent), result.Count(batch.Failed), result.Count(batch.Skipped)))
+	if n := result.Count(batch.TimedOut); n > 0 {
+		fmt.Printf("   %s\n", i18n.T("run.batch_timed_out", n))
+	}
+	for _, item := range result.Items {
+		switch item.Outcome {
+		case batch.Failed:
+			fmt.Printf("   ❌ %s: %s\n", orDash(item.Name), item.Error)
+		case batch.TimedOut:
+			fmt.Printf("   ⏱️  %s: %s\n", orDash(item.Name), item.Error)
+		}
+	}
+}
+
+// sentToday counts the connection requests and messages sent today
+func sentToday(connector *connect.Connector, messenger *messaging.Messenger) int {
+	return connector.GetStats().ConnectionsToday + messenger.GetStats().MessagesToday
+}
+
+// skipModule reports a workflow step turned off under modules in config.yaml
+func skipModule(name string) {
+	logger.Info("Module disabled, skipping", "module", name)
+	fmt.Printf("⏭️  %s\n", i18n.T("run.module_disabled", name))
 }
 
 // runDemo showcases stealth techniques
 func runDemo(s *stealth.Stealth, b *browser.Browser) {
 	logger.Info("Running demonstration mode")
-	fmt.Println("\n🎭 STEALTH TECHNIQUES DEMONSTRATION\n")
+	fmt.Printf("\n🎭 %s\n\n", i18n.T("demo.title"))
 
 	// Demo 1: Mouse Movement
-	fmt.Println("1️⃣  Bézier Curve Mouse Movement")
-	fmt.Println("   Moving mouse from (100,100) to (800,600)...")
-	s.MoveMouse(800, 600)
-	fmt.Println("   ✓ Smooth, curved path demonstrated\n")
+	fmt.Printf("1️⃣  %s\n", i18n.T("demo.mouse"))
+	fmt.Printf("   %s\n", i18n.T("demo.mouse_doing"))
+	s.MoveTo(0.67, 0.75)
+	fmt.Printf("   ✓ %s\n\n", i18n.T("demo.mouse_done"))
 	time.Sleep(1 * time.Second)
 
 	// Demo 2: Typing with Typos
-	fmt.Println("2️⃣  Human-like Typing Simulation")
-	fmt.Println("   Typing: 'Hello, this is a test message'")
-	s.TypeHumanLike("demo", "Hello, this is a test message")
-	fmt.Println("   ✓ Variable speed + occasional typos demonstrated\n")
+	sample := i18n.T("demo.typing_sample")
+	fmt.Printf("2️⃣  %s\n", i18n.T("demo.typing"))
+	fmt.Printf("   %s\n", i18n.T("demo.typing_doing", sample))
+	s.TypeHumanLike("demo", sample)
+	fmt.Printf("   ✓ %s\n\n", i18n.T("demo.typing_done"))
 	time.Sleep(1 * time.Second)
 
 	// Demo 3: Random Scrolling
-	fmt.Println("3️⃣  Natural Scrolling Behavior")
-	fmt.Println("   Performing random scroll...")
+	fmt.Printf("3️⃣  %s\n", i18n.T("demo.scroll"))
+	fmt.Printf("   %s\n", i18n.T("demo.scroll_doing"))
 	s.RandomScroll()
-	fmt.Println("   ✓ Accelerated scroll with physics demonstrated\n")
+	fmt.Printf("   ✓ %s\n\n", i18n.T("demo.scroll_done"))
 	time.Sleep(1 * time.Second)
 
 	// Demo 4: Mouse Wandering
-	fmt.Println("4️⃣  Mouse Hover Wandering")
-	fmt.Println("   Simulating reading behavior...")
+	fmt.Printf("4️⃣  %s\n", i18n.T("demo.wander"))
+	fmt.Printf("   %s\n", i18n.T("demo.wander_doing"))
 	s.WanderMouse()
-	fmt.Println("   ✓ Random micro-movements demonstrated\n")
+	fmt.Printf("   ✓ %s\n\n", i18n.T("demo.wander_done"))
 	time.Sleep(1 * time.Second)
 
 	// Demo 5: Timing Patterns
-	fmt.Println("5️⃣  Randomized Timing")
-	fmt.Println("   Action delay...")
+	fmt.Printf("5️⃣  %s\n", i18n.T("demo.timing"))
+	fmt.Printf("   %s\n", i18n.T("demo.timing_delay"))
 	start := time.Now()
 	s.RandomDelay()
-	fmt.Printf("   ✓ Delayed %dms (randomized)\n\n", time.Since(start).Milliseconds())
+	fmt.Printf("   ✓ %s\n\n", i18n.T("demo.timing_delay_done", time.Since(start).Milliseconds()))
 	
-	fmt.Println("   Thinking pause...")
+	fmt.Printf("   %s\n", i18n.T("demo.timing_think"))
 	start = time.Now()
 	s.ThinkingPause()
-	fmt.Printf("   ✓ Paused %dms (simulating thought)\n\n", time.Since(start).Milliseconds())
+	fmt.Printf("   ✓ %s\n\n", i18n.T("demo.timing_think_done", time.Since(start).Milliseconds()))
 
 	// Demo 6: Business Hours
-	fmt.Println("6️⃣  Business Hours Enforcement")
+	fmt.Printf("6️⃣  %s\n", i18n.T("demo.hours"))
 	if s.CheckBusinessHours() {
-		fmt.Println("   ✓ Currently within business hours\n")
+		fmt.Printf("   ✓ %s\n\n", i18n.T("demo.hours_in"))
 	} else {
-		fmt.Println("   ⚠️  Currently outside business hours\n")
+		fmt.Printf("   ⚠️  %s\n\n", i18n.T("demo.hours_out"))
 	}
 
 	// Demo 7: Fingerprint Masking
-	fmt.Println("7️⃣  Browser Fingerprint Masking")
-	fmt.Println("   Applied WebDriver flag masking")
-	fmt.Println("   Applied viewport randomization")
-	fmt.Println("   ✓ Fingerprint techniques active\n")
+	fmt.Printf("7️⃣  %s\n", i18n.T("demo.fingerprint"))
+	fmt.Printf("   %s\n", i18n.T("demo.fingerprint_webdriver"))
+	fmt.Printf("   %s\n", i18n.T("demo.fingerprint_viewport"))
+	fmt.Printf("   ✓ %s\n\n", i18n.T("demo.fingerprint_done"))
 
 	// Demo 8: Rate Limiting
-	fmt.Println("8️⃣  Rate Limiting & Cooldown")
-	fmt.Println("   Enforcing 5-second cooldown...")
+	fmt.Printf("8️⃣  %s\n", i18n.T("demo.cooldown"))
+	fmt.Printf("   %s\n", i18n.T("demo.cooldown_doing", 5))
 	start = time.Now()
 	s.EnforceCooldown("demo", 5)
-	fmt.Printf("   ✓ Cooldown enforced (%dms)\n\n", time.Since(start).Milliseconds())
+	fmt.Printf("   ✓ %s\n\n", i18n.T("demo.cooldown_done", time.Since(start).Milliseconds()))
 
-	fmt.Println("✅ Demo complete! All 8+ stealth techniques showcased.")
-	fmt.Println("\nℹ️  Check logs for detailed timing and execution data")
+	fmt.Printf("✅ %s\n", i18n.T("demo.complete"))
+	fmt.Printf("\nℹ️  %s\n", i18n.T("demo.check_logs"))
 	
 	// Keep browser open
-	fmt.Println("\n⏳ Keeping browser open for 10 seconds...")
+	fmt.Printf("\n⏳ %s\n", i18n.T("run.keep_open", 10))
 	time.Sleep(10 * time.Second)
 }
 
 // showStats displays current statistics
-func showStats(db *storage.Storage) {
-	fmt.Println("\n📊 AUTOMATION STATISTICS\n")
-	
+func showStats(db storage.Backend, output string) error {
 	stats := db.GetStats()
-	
-	fmt.Println("Profile States:")
-	fmt.Printf("  Discovered:  %v\n", stats["discovered"])
-	fmt.Printf("  Requested:   %v\n", stats["requested"])
-	fmt.Printf("  Accepted:    %v\n", stats["accepted"])
-	fmt.Printf("  Cooled Down: %v\n", stats["cooled_down"])
-	fmt.Printf("  Rejected:    %v\n", stats["rejected"])
-	fmt.Printf("  TOTAL:       %v\n\n", stats["total_profiles"])
-	
-	fmt.Println("Activity Today:")
-	fmt.Printf("  Connections: %v\n", stats["connections_today"])
-	fmt.Printf("  Messages:    %v\n", stats["messages_today"])
-	fmt.Printf("  Total Msgs:  %v\n\n", stats["total_messages"])
-	
-	fmt.Println("Recent Activity:")
-	fmt.Printf("  Connections (last hour): %v\n", stats["connections_last_hour"])
+
+	return render(output, stats, func() {
+		fmt.Printf("\n📊 %s\n\n", i18n.T("stats.title"))
+
+		fmt.Println(i18n.T("stats.profile_states"))
+		printStat("stats.discovered", stats.Discovered)
+		printStat("stats.approved", stats.Approved)
+		printStat("stats.skipped", stats.Skipped)
+		printStat("stats.requested", stats.Requested)
+		printStat("stats.accepted", stats.Accepted)
+		printStat("stats.cooled_down", stats.CooledDown)
+		printStat("stats.rejected", stats.Rejected)
+		printStat("stats.total", stats.TotalProfiles)
+		fmt.Println()
+
+		fmt.Println(i18n.T("stats.activity_today"))
+		printStat("stats.connections", stats.ConnectionsToday)
+		printStat("stats.messages", stats.MessagesToday)
+		printStat("stats.total_messages", stats.TotalMessages)
+		fmt.Println()
+
+		fmt.Println(i18n.T("stats.recent"))
+		fmt.Printf("  %s: %v\n", i18n.T("stats.connections_last_hour"), stats.ConnectionsLastHour)
+
+		// Limits count every source; the split shows manual work
+		bySource := stats.TodayBySource
+		if len(bySource) > 0 {
+			fmt.Println()
+			fmt.Println(i18n.T("stats.by_source"))
+			for _, source := range []string{storage.SourceAuto, storage.SourceManual, storage.SourceAPI} {
+				if counts, ok := bySource[source]; ok {
+					parts := make([]string, 0, len(counts))
+					for _, action := range sortedKeys(counts) {
+						parts = append(parts, fmt.Sprintf("%s %d", action, counts[action]))
+					}
+					fmt.Printf("  %-16s %s\n", source+":", strings.Join(parts, ", "))
+				}
+			}
+		}
+	})
+}
+
+// printStat prints one aligned "label: value" line of the stats table
+func printStat(key string, value interface{}) {
+	fmt.Printf("  %-16s %v\n", i18n.T(key)+":", value)
 }
 
 // printBanner displays the application banner
@@ -375,6 +817,17 @@ See README.md for complete documentation and guidelines.
 	fmt.Println(banner)
 }
 
+// dataDirs returns the directories holding sensitive state: the data
+// directory and, if it lives elsewhere, the saved session's
+func dataDirs(cfg *config.Config) []string {
+	dirs := []string{cfg.App.DataDir}
+	session := filepath.Dir(config.GetEnv("SESSION_COOKIE_PATH", cfg.Auth.SessionCookiePath))
+	if rel, err := filepath.Rel(cfg.App.DataDir, session); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		dirs = append(dirs, session)
+	}
+	return dirs
+}
+
 // getMode returns a description of the current running mode
 func getMode(demo, stats bool) string {
 	if demo {
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"subspace/internal/config"
+	"subspace/internal/i18n"
+	"subspace/internal/maintenance"
+)
+
+// maintenance handles "maintenance <subcommand>"
+func (c *cli) maintenance(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: maintenance run|compact|verify [-repair]")
+	}
+
+	switch args[0] {
+	case "run":
+		fmt.Printf("🧹 %s\n", i18n.T("maintenance.running"))
+		report, err := maintenance.Run(c.cfg.Retention, c.store, c.cfg.App.DataDir)
+		if err != nil {
+			return err
+		}
+		printMaintenanceReport(c.cfg.Retention, report)
+		return nil
+	case "compact":
+		fmt.Printf("🧹 %s\n", i18n.T("maintenance.compacting"))
+		result, err := c.db.Compact()
+		if err != nil {
+			return err
+		}
+		return render(c.output, result, func() {
+			fmt.Printf("\n📊 %s\n\n", i18n.T("maintenance.compact_title"))
+			fmt.Printf("  %-17s %d\n", i18n.T("maintenance.tombstones")+":", result.Tombstones)
+			fmt.Printf("  %-17s %d\n", i18n.T("maintenance.duplicate_logs")+":", result.DuplicateLogs)
+			fmt.Printf("  %-17s %d\n", i18n.T("maintenance.orphaned")+":", result.OrphanedMessages)
+			fmt.Printf("  %-17s %s\n", i18n.T("maintenance.size")+":",
+				i18n.T("maintenance.size_line", formatBytes(result.BytesBefore), formatBytes(result.BytesAfter)))
+		})
+	case "verify":
+		return c.verifyStorage(args[1:])
+	default:
+		return fmt.Errorf("unknown maintenance command: %s", args[0])
+	}
+}
+
+// verifyStorage handles "maintenance verify [-repair]", the storage integrity check
+func (c *cli) verifyStorage(args []string) error {
+	fs := flag.NewFlagSet("maintenance verify", flag.ContinueOnError)
+	repair := fs.Bool("repair", false, "Fix the inconsistencies that can be fixed")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	issues, err := c.db.Verify(*repair)
+	if err != nil {
+		return err
+	}
+	return render(c.output, issues, func() {
+		fmt.Printf("\n🔎 %s\n\n", i18n.T("maintenance.verify_title"))
+		if len(issues) == 0 {
+			fmt.Printf("  %s\n", i18n.T("maintenance.verify_ok"))
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, i18n.T("maintenance.verify_header"))
+		repaired := 0
+		for _, issue := range issues {
+			status := ""
+			if issue.Repaired {
+				status = i18n.T("maintenance.repaired")
+				repaired++
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", issue.Kind, issue.ID, issue.Detail, status)
+		}
+		w.Flush()
+		fmt.Printf("\n  %s\n", i18n.T("maintenance.verify_summary", len(issues), repaired))
+		if !*repair {
+			fmt.Printf("  %s\n", i18n.T("maintenance.verify_hint"))
+		}
+	})
+}
+
+// printMaintenanceReport displays what a retention run purged
+func printMaintenanceReport(cfg config.RetentionConfig, report *maintenance.Report) {
+	fmt.Printf("\n📊 %s\n\n", i18n.T("maintenance.title"))
+	fmt.Printf("  %-13s %s\n", i18n.T("maintenance.duplicates")+":", i18n.T("maintenance.merged", report.Merged))
+	printPurged("maintenance.profiles", report.Profiles, cfg.ProfilesDays)
+	printPurged("maintenance.messages", report.Messages, cfg.MessagesDays)
+	printPurged("maintenance.action_logs", report.ActionLogs, cfg.ActionLogsDays)
+	printPurged("maintenance.screenshots", report.Screenshots, cfg.ScreenshotsDays)
+	printPurged("maintenance.traces", report.Traces, cfg.TracesDays)
+	fmt.Printf("  %-13s %s\n", i18n.T("maintenance.total")+":",
+		i18n.T("maintenance.total_line", report.Total(), report.Duration.Milliseconds()))
+}
+
+// printPurged prints one record type line of the retention report
+func printPurged(key string, count, days int) {
+	fmt.Printf("  %-13s %s\n", i18n.T(key)+":", i18n.T("maintenance.purged", count, retentionLabel(days)))
+}
+
+// retentionLabel describes a retention period for display
+func retentionLabel(days int) string {
+	if days <= 0 {
+		return i18n.T("maintenance.forever")
+	}
+	return i18n.T("maintenance.older_than", days)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"subspace/internal/i18n"
+	"subspace/internal/storage"
+)
+
+// messages handles "messages search [-profile id] [-kind message|note]
+// [-since day] [-limit n] <words>...", finding what was said to whom in the
+// sent and received messages and the operator notes. Quoted words match as
+// a phrase.
+func (c *cli) messages(args []string) error {
+	if len(args) == 0 || args[0] != "search" {
+		return fmt.Errorf(`usage: messages search [-profile id] [-kind message|note] [-since day] [-limit n] <words>...`)
+	}
+	fs := flag.NewFlagSet("messages search", flag.ContinueOnError)
+	profile := fs.String("profile", "", "Only this profile's messages and notes")
+	kind := fs.String("kind", "", "Only messages or only notes")
+	since := fs.String("since", "", "Only after this time (RFC 3339) or day (YYYY-MM-DD)")
+	limit := fs.Int("limit", 50, "Newest matches shown, 0 for all")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	q := storage.ArchiveQuery{Text: strings.Join(fs.Args(), " "), ProfileID: *profile, Kind: *kind, Limit: *limit}
+	var err error
+	if q.Since, err = parseSince(*since); err != nil {
+		return fmt.Errorf("invalid -since: %w", err)
+	}
+
+	hits, err := c.store.SearchArchive(q)
+	if err != nil {
+		return err
+	}
+
+	return render(c.output, hits, func() {
+		fmt.Printf("\n🔎 %s\n\n", i18n.T("messages.search_title", len(hits), q.Text))
+		if len(hits) == 0 {
+			fmt.Printf("  %s\n", i18n.T("messages.search_empty"))
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, i18n.T("messages.search_header"))
+		for _, hit := range hits {
+			at := "-"
+			if !hit.At.IsZero() {
+				at = hit.At.Format("2006-01-02 15:04")
+			}
+			kind := i18n.T("messages.kind_note")
+			if hit.Kind == storage.ArchiveMessage {
+				kind = i18n.T("messages.kind_sent")
+				if hit.Inbound {
+					kind = i18n.T("messages.kind_received")
+				}
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", at, kind, hit.ProfileID, hit.Name,
+				strings.Join(strings.Fields(hit.Text), " "))
+		}
+		w.Flush()
+	})
+}
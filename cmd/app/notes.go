@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"subspace/internal/clock"
+	"subspace/internal/i18n"
+	"subspace/internal/storage"
+)
+
+// notes handles "notes <profile-id>" and "notes add <profile-id> <text>",
+// the append-only operator annotations on a profile
+func (c *cli) notes(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: notes <profile-id> | notes add <profile-id> <text>")
+	}
+
+	if args[0] == "add" {
+		if len(args) < 3 {
+			return fmt.Errorf("usage: notes add <profile-id> <text>")
+		}
+		p, err := c.store.GetProfile(args[1])
+		if err != nil {
+			return err
+		}
+		if err := c.addNote(p, strings.Join(args[2:], " ")); err != nil {
+			return err
+		}
+		fmt.Printf("📝 %s\n", i18n.T("notes.added", p.ID, p.Name))
+		return nil
+	}
+
+	p, err := c.store.GetProfile(args[0])
+	if err != nil {
+		return err
+	}
+	notes := p.NoteList()
+	return render(c.output, notes, func() {
+		fmt.Printf("\n📝 %s\n\n", i18n.T("notes.title", p.Name, p.ID))
+		if len(notes) == 0 {
+			fmt.Printf("  %s\n", i18n.T("notes.none"))
+		}
+		printNotes("  ", notes)
+	})
+}
+
+// addNote appends a note to a profile and saves it
+func (c *cli) addNote(p *storage.Profile, text string) error {
+	if err := p.AddNote(text, clock.Now()); err != nil {
+		return err
+	}
+	if err := c.store.SaveProfile(p); err != nil {
+		return fmt.Errorf("failed to save note: %w", err)
+	}
+	return nil
+}
+
+// printNotes prints notes one per line, oldest first
+func printNotes(indent string, notes []storage.Note) {
+	for _, n := range notes {
+		if n.At.IsZero() {
+			fmt.Printf("%s%s\n", indent, n.Text)
+			continue
+		}
+		fmt.Printf("%s%s  %s\n", indent, n.At.Format("2006-01-02 15:04"), n.Text)
+	}
+}
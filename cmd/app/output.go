@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by -output
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// validOutput reports whether format is a supported -output value
+func validOutput(format string) bool {
+	switch format {
+	case outputTable, outputJSON, outputYAML:
+		return true
+	}
+	return false
+}
+
+// machineReadable reports whether output is meant to be piped into other
+// tools, in which case banners and logs must stay off stdout
+func machineReadable(format string) bool {
+	return format == outputJSON || format == outputYAML
+}
+
+// render writes v to stdout in the requested machine-readable format, or
+// calls table to print the default human-readable view
+func render(format string, v interface{}, table func()) error {
+	switch format {
+	case outputJSON:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode json: %w", err)
+		}
+		fmt.Println(string(data))
+	case outputYAML:
+		// Round-trip through JSON so YAML keys match the json tags
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("failed to encode yaml: %w", err)
+		}
+		var generic interface{}
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return fmt.Errorf("failed to encode yaml: %w", err)
+		}
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(generic); err != nil {
+			return fmt.Errorf("failed to encode yaml: %w", err)
+		}
+		return enc.Close()
+	default:
+		table()
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"subspace/internal/campaign"
+	"subspace/internal/connect"
+	"subspace/internal/i18n"
+	"subspace/internal/messaging"
+	"subspace/internal/ratelimit"
+	"subspace/internal/stealth"
+	"subspace/internal/storage"
+	"subspace/internal/workflow"
+)
+
+// runPlan describes what the next automation run would do
+type runPlan struct {
+	GeneratedAt           time.Time             `json:"generated_at"`
+	WithinBusinessHours   bool                  `json:"within_business_hours"`
+	WorkSessionUntil      *time.Time            `json:"work_session_until,omitempty"`
+	DayOff                string                `json:"day_off,omitempty"`
+	Modules               []string              `json:"modules"`
+	Order                 workflow.Plan         `json:"order"`
+	AwaitingReview        int                   `json:"awaiting_review,omitempty"`
+	OutsideRecipientHours int                   `json:"outside_recipient_hours,omitempty"`
+	Snoozed               int                   `json:"snoozed,omitempty"`
+	Searches              planStep              `json:"searches"`
+	Connections           planStep              `json:"connections"`
+	Campaigns             []campaign.Allocation `json:"campaigns,omitempty"`
+	Messages              planStep              `json:"messages"`
+}
+
+// planStep is the budget and workload of a single workflow step
+type planStep struct {
+	DoneToday  int  `json:"done_today"`
+	LimitDaily int  `json:"limit_daily"`
+	Candidates int  `json:"candidates"`
+	Planned    int  `json:"planned"`
+	Disabled   bool `json:"disabled,omitempty"`
+}
+
+// plan handles "plan", a dry run of the next automation cycle
+func (c *cli) plan(args []string) error {
+	limits := c.cfg.Limits
+	limiter := ratelimit.New(c.store, limits)
+	s := stealth.New(c.cfg.Stealth, nil)
+
+	p := runPlan{
+		GeneratedAt: time.Now(),
+		Modules:     c.cfg.Modules.Enabled(),
+	}
+	p.Order = workflow.For(c.cfg.Workflow, c.cfg.Modules, p.GeneratedAt)
+	if ws := applyWorkSession(s, c.cfg.App.DataDir); ws != nil {
+		p.WorkSessionUntil = &ws.ExpiresAt
+	}
+	p.WithinBusinessHours = s.CheckBusinessHours()
+	if off, reason := s.DayOff(); off && !p.WithinBusinessHours {
+		p.DayOff = reason
+	}
+
+	// Searches: one per run while budget remains
+	p.Searches = planStep{
+		DoneToday:  c.store.GetActionCountToday("search"),
+		LimitDaily: limits.SearchesPerDay,
+		Candidates: 1,
+	}
+	p.Searches.Planned = minInt(1, limiter.Remaining("search"))
+
+	// Connections: bounded by both the sliding daily and hourly limits
+	eligible := connect.Eligible(c.store.ConnectCandidates(c.cfg.Review.RequireApproval), c.cfg.Targeting)
+	p.Connections = planStep{
+		DoneToday:  c.store.GetActionCountToday("connection"),
+		LimitDaily: limits.ConnectionsPerDay,
+		Candidates: len(eligible),
+	}
+	p.Connections.Planned = minInt(p.Connections.Candidates, limiter.Remaining("connection"))
+	if c.cfg.Review.RequireApproval {
+		p.AwaitingReview = len(c.store.GetProfilesByState(storage.StateDiscovered))
+	}
+	p.Snoozed = len(c.store.SnoozedProfiles(p.GeneratedAt))
+
+	// Messages: accepted connections that have not been messaged yet and
+	// for whom it is currently daytime
+	messenger := messaging.New(nil, s, c.store, limits, c.cfg.Messaging)
+	unmessaged := 0
+	for _, profile := range c.store.GetProfilesByState(storage.StateAccepted) {
+		if len(c.store.GetMessagesByProfile(profile.ID)) == 0 && !profile.Snoozed(p.GeneratedAt) {
+			unmessaged++
+			if !messenger.InRecipientHours(profile) {
+				p.OutsideRecipientHours++
+			}
+		}
+	}
+	p.Messages = planStep{
+		DoneToday:  c.store.GetActionCountToday("message"),
+		LimitDaily: limits.MessagesPerDay,
+		Candidates: unmessaged,
+	}
+	p.Messages.Planned = minInt(unmessaged-p.OutsideRecipientHours, limiter.Remaining("message"))
+
+	if !p.WithinBusinessHours {
+		p.Searches.Planned, p.Connections.Planned, p.Messages.Planned = 0, 0, 0
+	}
+	if p.Order.Strategy == "search_only" {
+		p.Connections.Planned, p.Messages.Planned = 0, 0
+	}
+	if campaigns := campaign.New(c.cfg.Targeting.Campaigns); campaigns.Enabled() && c.cfg.Modules.Connect {
+		_, p.Campaigns = campaigns.Select(eligible, p.Connections.Planned)
+	}
+	for _, step := range []struct {
+		on   bool
+		step *planStep
+	}{{c.cfg.Modules.Search, &p.Searches}, {c.cfg.Modules.Connect, &p.Connections}, {c.cfg.Modules.Messaging, &p.Messages}} {
+		if !step.on {
+			step.step.Planned, step.step.Disabled = 0, true
+		}
+	}
+
+	return render(c.output, p, func() {
+		fmt.Printf("\n🗓️  %s\n\n", i18n.T("plan.title"))
+		if p.WorkSessionUntil != nil {
+			fmt.Printf("  🟢 %s\n", i18n.T("session.running", p.WorkSessionUntil.Format("15:04")))
+		} else if p.DayOff != "" {
+			fmt.Printf("  🏖️  %s\n", i18n.T("plan.day_off", p.DayOff))
+		} else if p.WithinBusinessHours {
+			fmt.Printf("  ✅ %s\n", i18n.T("plan.within_hours"))
+		} else {
+			fmt.Printf("  ⏰ %s\n", i18n.T("plan.outside_hours"))
+		}
+		fmt.Printf("  🗺️  %s\n", i18n.T("run.plan", p.Order.Strategy))
+		fmt.Println()
+		printPlanStep("plan.searches", p.Searches)
+		printPlanStep("plan.connections", p.Connections)
+		for _, a := range p.Campaigns {
+			fmt.Printf("  %-12s %s\n", "", i18n.T("plan.campaign", a.Campaign, a.Allocated, a.Candidates, a.Priority, a.Weight))
+		}
+		if p.AwaitingReview > 0 {
+			fmt.Printf("  %-12s %s\n", "", i18n.T("plan.awaiting_review", p.AwaitingReview))
+		}
+		printPlanStep("plan.messages", p.Messages)
+		if p.OutsideRecipientHours > 0 && !p.Messages.Disabled {
+			fmt.Printf("  %-12s %s\n", "", i18n.T("plan.recipient_night", p.OutsideRecipientHours))
+		}
+		if p.Snoozed > 0 {
+			fmt.Printf("\n  💤 %s\n", i18n.T("plan.snoozed", p.Snoozed))
+		}
+	})
+}
+
+// printPlanStep prints one plan line in the table view
+func printPlanStep(key string, step planStep) {
+	if step.Disabled {
+		fmt.Printf("  %-12s %s\n", i18n.T(key)+":", i18n.T("plan.disabled"))
+		return
+	}
+	fmt.Printf("  %-12s %s\n", i18n.T(key)+":",
+		i18n.T("plan.step", step.Planned, step.Candidates, step.DoneToday, step.LimitDaily))
+}
+
+// minInt returns the smaller of two ints, clamped at zero
+func minInt(a, b int) int {
+	if b < a {
+		a = b
+	}
+	if a < 0 {
+		return 0
+	}
+	return a
+}
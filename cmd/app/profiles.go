@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"subspace/internal/i18n"
+	"subspace/internal/rules"
+	"subspace/internal/storage"
+)
+
+// profiles handles "profiles [-state <state,...>] [-tag <tag>] [-company <text>]
+// [-title <text>] [-where <rule>] [-sort discovered|requested] [-desc]
+// [-limit n] [-offset n]", listing stored profiles, and dispatches "profiles dedupe", "profiles tag",
+// "profiles untag" and "profiles import"
+func (c *cli) profiles(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "dedupe":
+			return c.dedupe(args[1:])
+		case "tag", "untag":
+			return c.tagProfile(args[0] == "tag", args[1:])
+		case "import":
+			return c.importProfiles(args[1:])
+		}
+	}
+
+	fs := flag.NewFlagSet("profiles", flag.ContinueOnError)
+	state := fs.String("state", "", "Only list profiles in these states, comma-separated")
+	tag := fs.String("tag", "", "Only list profiles carrying this tag")
+	company := fs.String("company", "", "Only list profiles whose company contains this")
+	title := fs.String("title", "", "Only list profiles whose title contains this")
+	where := fs.String("where", "", `Only list profiles matching a targeting rule, e.g. 'title ~= "engineer"'`)
+	by := fs.String("sort", storage.SortDiscovered, "Sort by discovered or requested date")
+	desc := fs.Bool("desc", false, "Newest first")
+	limit := fs.Int("limit", 0, "List at most this many (0 for all)")
+	offset := fs.Int("offset", 0, "Skip this many first")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *limit < 0 || *offset < 0 {
+		return fmt.Errorf("-limit and -offset cannot be negative")
+	}
+	opts := storage.ListOptions{States: splitStates(*state), Tag: *tag, Company: *company, Title: *title,
+		Sort: *by, Desc: *desc, Limit: *limit, Offset: *offset}
+
+	// A rule is matched here, so the page is cut after it
+	var rule *rules.Rule
+	if *where != "" {
+		var err error
+		if rule, err = rules.Compile(*where); err != nil {
+			return err
+		}
+		opts.Limit, opts.Offset = 0, 0
+	}
+	page, err := c.store.ListProfiles(opts)
+	if err != nil {
+		return err
+	}
+	if rule != nil {
+		matched := page.Profiles[:0]
+		for _, p := range page.Profiles {
+			if rule.Match(p.RuleEnv()) {
+				matched = append(matched, p)
+			}
+		}
+		page.Total = len(matched)
+		matched = matched[min(*offset, len(matched)):]
+		if *limit > 0 && len(matched) > *limit {
+			matched = matched[:*limit]
+		}
+		page.Profiles = matched
+	}
+	profiles := page.Profiles
+
+	return render(c.output, profiles, func() {
+		fmt.Printf("\n👥 %s\n\n", i18n.T("profiles.title", page.Total))
+		if len(profiles) > 0 && len(profiles) < page.Total {
+			fmt.Printf("%s\n\n", i18n.T("profiles.page", *offset+1, *offset+len(profiles), page.Total))
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, i18n.T("profiles.header"))
+		for _, p := range profiles {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				p.ID, p.Name, p.Title, p.Company, p.State,
+				p.DiscoveredAt.Format("2006-01-02 15:04"), strings.Join(p.Tags, ","))
+		}
+		w.Flush()
+	})
+}
+
+// splitStates parses a comma-separated list of states
+func splitStates(list string) []storage.ProfileState {
+	var states []storage.ProfileState
+	for _, s := range strings.Split(list, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			states = append(states, storage.ProfileState(s))
+		}
+	}
+	return states
+}
+
+// maxProfilesPage caps the page size of the /profiles endpoint
+const maxProfilesPage = 1000
+
+// profilesHandler serves a page of profiles as JSON. The query takes the
+// list flags: ?state=requested,accepted&company=acme&sort=requested&desc=1
+// &limit=50&offset=100. The page size defaults to 100.
+func profilesHandler(db storage.Backend) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		opts := storage.ListOptions{States: splitStates(q.Get("state")), Tag: q.Get("tag"), Company: q.Get("company"),
+			Title: q.Get("title"), Sort: q.Get("sort"), Desc: q.Get("desc") == "1" || q.Get("desc") == "true", Limit: 100}
+		for name, n := range map[string]*int{"limit": &opts.Limit, "offset": &opts.Offset} {
+			if v := q.Get(name); v != "" {
+				parsed, err := strconv.Atoi(v)
+				if err != nil || parsed < 0 {
+					http.Error(w, name+" must be a number of at least 0", http.StatusBadRequest)
+					return
+				}
+				*n = parsed
+			}
+		}
+		if opts.Limit == 0 || opts.Limit > maxProfilesPage {
+			opts.Limit = maxProfilesPage
+		}
+
+		page, err := db.ListProfiles(opts)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page)
+	})
+}
+
+// tagProfile handles "profiles tag|untag <profile-id> <tag>..."
+func (c *cli) tagProfile(add bool, args []string) error {
+	verb := "untag"
+	if add {
+		verb = "tag"
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("usage: profiles %s <profile-id> <tag>...", verb)
+	}
+	p, err := c.store.GetProfile(args[0])
+	if err != nil {
+		return err
+	}
+	tags := args[1:]
+	for i, tag := range tags {
+		if tags[i], err = storage.NormalizeTag(tag); err != nil {
+			return err
+		}
+	}
+	for _, tag := range tags {
+		if add {
+			err = c.store.AddTag(p.ID, tag)
+		} else {
+			err = c.store.RemoveTag(p.ID, tag)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	key := "profiles.untagged"
+	if add {
+		key = "profiles.tagged"
+	}
+	fmt.Printf("🏷️  %s\n", i18n.T(key, p.ID, p.Name, strings.Join(tags, ", ")))
+	return nil
+}
+
+// importProfiles handles "profiles import [-tag tag,...] [-query label]
+// [-dry-run] <file.csv|->", seeding a target list as discovered profiles
+func (c *cli) importProfiles(args []string) error {
+	fs := flag.NewFlagSet("profiles import", flag.ContinueOnError)
+	tags := fs.String("tag", "", "Comma-separated tags added to every imported profile")
+	query := fs.String("query", storage.ImportQuery, "Search query recorded on the imported profiles")
+	dryRun := fs.Bool("dry-run", false, "Report what would be imported without saving")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: profiles import [-tag tag,...] [-query label] [-dry-run] <file.csv|->")
+	}
+
+	opts := storage.ImportOptions{Query: *query, DryRun: *dryRun}
+	if *tags != "" {
+		var err error
+		if opts.Tags, err = parseTags(*tags); err != nil {
+			return err
+		}
+	}
+	in := os.Stdin
+	if path := fs.Arg(0); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open import file: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	result, err := storage.ImportCSV(c.store, in, opts)
+	if err != nil {
+		return err
+	}
+	for _, invalid := range result.Invalid {
+		fmt.Printf("⚠️  %s\n", invalid)
+	}
+	key := "profiles.imported"
+	if *dryRun {
+		key = "profiles.import_dry_run"
+	}
+	fmt.Printf("📥 %s\n", i18n.T(key, result.Imported, result.Duplicates, len(result.Invalid)))
+	return nil
+}
+
+// parseTags splits a comma-separated list of tags and normalizes each
+func parseTags(list string) ([]string, error) {
+	var tags []string
+	for _, tag := range strings.Split(list, ",") {
+		tag, err := storage.NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"subspace/internal/calendar"
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/connect"
+	"subspace/internal/i18n"
+	"subspace/internal/ids"
+	"subspace/internal/queue"
+	"subspace/internal/schedule"
+	"subspace/internal/storage"
+	"subspace/internal/worksession"
+)
+
+// queueQuery is the search query recorded on profiles posted to /queue
+const queueQuery = "api"
+
+// enqueueRequest is the body of a POST to /queue/connection or
+// /queue/message: the person to send a connection request or, for a
+// stored accepted connection, a first message
+type enqueueRequest struct {
+	ProfileURL string   `json:"profile_url"`
+	Name       string   `json:"name"`
+	Title      string   `json:"title"`
+	Company    string   `json:"company"`
+	Location   string   `json:"location"`
+	Tags       []string `json:"tags"`
+}
+
+// enqueueResponse tells the caller where its job landed
+type enqueueResponse struct {
+	ProfileID   string    `json:"profile_id"`
+	Action      string    `json:"action"`
+	Position    int       `json:"position"` // 1 runs next
+	Depth       int       `json:"depth"`    // Jobs of the action pending, this one included
+	EstimatedAt time.Time `json:"estimated_at"`
+	Existing    bool      `json:"existing,omitempty"` // Already pending; nothing was changed
+}
+
+// enqueueProfile builds the profile a connection request describes,
+// approved so it is queued for one
+func enqueueProfile(req enqueueRequest, now time.Time) (*storage.Profile, error) {
+	if !strings.HasPrefix(storage.ProfileKey(req.ProfileURL), "in/") {
+		return nil, fmt.Errorf("profile_url is not a profile URL: %q", req.ProfileURL)
+	}
+	p := &storage.Profile{
+		ID:           "api-" + ids.New(),
+		Name:         strings.TrimSpace(req.Name),
+		Title:        strings.TrimSpace(req.Title),
+		Company:      strings.TrimSpace(req.Company),
+		Location:     strings.TrimSpace(req.Location),
+		ProfileURL:   storage.CanonicalURL(req.ProfileURL),
+		State:        storage.StateApproved,
+		DiscoveredAt: now,
+		ReviewedAt:   &now,
+		SearchQuery:  queueQuery,
+	}
+	for _, raw := range req.Tags {
+		tag, err := storage.NormalizeTag(raw)
+		if err != nil {
+			return nil, err
+		}
+		p.Tags = append(p.Tags, tag)
+	}
+	sort.Strings(p.Tags)
+	p.Tags = slices.Compact(p.Tags)
+	return p, nil
+}
+
+// queueHandler serves GET /queue, listing the pending jobs
+func queueHandler(cfg *config.Config, db storage.Backend) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "use GET", http.StatusMethodNotAllowed)
+			return
+		}
+		items, err := listQueue(cfg, db, clock.Now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(items)
+	})
+}
+
+// queueAPIHandler serves the queue API: POST /queue/connection and
+// /queue/message add a job, and POST /queue/prioritize, /queue/defer and
+// /queue/cancel change one
+func queueAPIHandler(cfg *config.Config, db storage.Backend) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "use POST", http.StatusMethodNotAllowed)
+			return
+		}
+		switch op := strings.TrimPrefix(r.URL.Path, "/queue/"); op {
+		case queue.ActionConnection, queue.ActionMessage:
+			enqueue(w, r, cfg, db, op)
+		case "prioritize", "defer", "cancel":
+			changeQueue(w, r, cfg, db, op)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// enqueue handles POST /queue/connection and /queue/message. The job is
+// placed at its position in the pending queue and the reply estimates
+// when it runs from the rate limits, cooldowns and sessions ahead. A job
+// that wouldn't run within app.queue_horizon_hours is refused with 429 and
+// a Retry-After of when the next pending job frees a place.
+func enqueue(w http.ResponseWriter, r *http.Request, cfg *config.Config, db storage.Backend, action string) {
+	if (action == queue.ActionConnection && !cfg.Modules.Connect) || (action == queue.ActionMessage && !cfg.Modules.Messaging) {
+		http.Error(w, fmt.Sprintf("the %s module is disabled", action), http.StatusServiceUnavailable)
+		return
+	}
+
+	var req enqueueRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	now := clock.Now()
+	p, err := enqueueProfile(req, now)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// A stored profile is only accepted if it is already waiting for
+	// this action; its position is reported as is. Only connections the
+	// run has seen accepted get a message, so a message needs one stored.
+	pending := queue.Pending(cfg, db, action, now)
+	existing, err := db.GetProfile(p.ID)
+	if err != nil {
+		existing = db.FindDuplicate(p)
+	}
+	if existing != nil {
+		p = existing
+	} else if action == queue.ActionConnection {
+		pending = connect.Eligible(append(pending, p), cfg.Targeting)
+	} else {
+		http.Error(w, "no accepted connection is stored for this profile; queue a connection request first",
+			http.StatusUnprocessableEntity)
+		return
+	}
+	position := 0
+	for i, q := range pending {
+		if q.ID == p.ID {
+			position = i + 1
+			break
+		}
+	}
+	if position == 0 {
+		if existing != nil {
+			http.Error(w, fmt.Sprintf("profile %s is stored as %s and not waiting for a %s", p.ID, p.State, action), http.StatusConflict)
+		} else {
+			http.Error(w, "profile is excluded by the targeting settings", http.StatusUnprocessableEntity)
+		}
+		return
+	}
+
+	slots, err := projectQueue(cfg, db, action, position, now)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(slots) < position {
+		retry := time.Duration(cfg.App.QueueHorizonHours) * time.Hour
+		if len(slots) > 0 {
+			retry = slots[0].Sub(now)
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(max(int(retry.Seconds()), 60)))
+		http.Error(w, fmt.Sprintf("the %d %s jobs ahead reach past %d hours", position-1, action, cfg.App.QueueHorizonHours),
+			http.StatusTooManyRequests)
+		return
+	}
+
+	status := http.StatusOK
+	if existing == nil {
+		if err := db.SaveProfile(p); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		status = http.StatusAccepted
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(enqueueResponse{
+		ProfileID:   p.ID,
+		Action:      action,
+		Position:    position,
+		Depth:       len(pending),
+		EstimatedAt: slots[position-1],
+		Existing:    existing != nil,
+	})
+}
+
+// queueRequest is the body of a POST to /queue/prioritize, /queue/defer
+// or /queue/cancel
+type queueRequest struct {
+	ProfileID string `json:"profile_id"`
+	Priority  int    `json:"priority"` // prioritize
+	Until     string `json:"until"`    // defer: a date or a duration, as for snooze
+	Reason    string `json:"reason"`   // defer
+}
+
+// changeQueue handles POST /queue/prioritize, /queue/defer and
+// /queue/cancel, replying with the changed profile
+func changeQueue(w http.ResponseWriter, r *http.Request, cfg *config.Config, db storage.Backend, op string) {
+	var req queueRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, err := db.GetProfile(req.ProfileID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	now := clock.Now()
+	var p *storage.Profile
+	var err error
+	switch op {
+	case "prioritize":
+		p, err = queue.Prioritize(cfg, db, req.ProfileID, req.Priority)
+	case "defer":
+		var until time.Time
+		if until, err = parseUntil(req.Until, now); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		p, err = queue.Defer(cfg, db, req.ProfileID, until, req.Reason)
+	case "cancel":
+		p, _, err = queue.Cancel(cfg, db, req.ProfileID, now)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p)
+}
+
+// queueSchedule returns the calendar and work session the queue is
+// projected over
+func queueSchedule(cfg *config.Config) (*calendar.Calendar, *worksession.Session, error) {
+	cal, err := calendar.New(cfg.Stealth)
+	if err != nil {
+		return nil, nil, err
+	}
+	ws, err := worksession.Current(cfg.App.DataDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cal, ws, nil
+}
+
+// projectQueue returns when the first n pending jobs of action run, up to
+// the queue horizon
+func projectQueue(cfg *config.Config, db storage.Backend, action string, n int, now time.Time) ([]time.Time, error) {
+	cal, ws, err := queueSchedule(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return schedule.Project(cfg, db, cal, ws, action, n, now, time.Duration(cfg.App.QueueHorizonHours)*time.Hour), nil
+}
+
+// listQueue returns the pending jobs, projected up to the queue horizon
+func listQueue(cfg *config.Config, db storage.Backend, now time.Time) ([]queue.Item, error) {
+	cal, ws, err := queueSchedule(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return queue.List(cfg, db, cal, ws, now, time.Duration(cfg.App.QueueHorizonHours)*time.Hour), nil
+}
+
+// queue handles "queue [-action connection|message]", listing the pending
+// jobs, and "queue prioritize <profile-id> <n>", "queue defer <profile-id>
+// <date|duration> [reason]" and "queue cancel <profile-id>"
+func (c *cli) queue(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "prioritize":
+			if len(args) != 3 {
+				return fmt.Errorf("usage: queue prioritize <profile-id> <priority>")
+			}
+			priority, err := strconv.Atoi(args[2])
+			if err != nil {
+				return fmt.Errorf("priority must be a whole number: %q", args[2])
+			}
+			p, err := queue.Prioritize(c.cfg, c.store, args[1], priority)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("⏫ %s\n", i18n.T("queue.prioritized", p.ID, p.Name, p.Priority))
+			return nil
+		case "defer":
+			if len(args) < 3 {
+				return fmt.Errorf("usage: queue defer <profile-id> <date|duration> [reason]")
+			}
+			until, err := parseUntil(args[2], clock.Now())
+			if err != nil {
+				return err
+			}
+			p, err := queue.Defer(c.cfg, c.store, args[1], until, strings.Join(args[3:], " "))
+			if err != nil {
+				return err
+			}
+			fmt.Printf("💤 %s\n", i18n.T("queue.deferred", p.ID, p.Name, until.Format("2006-01-02 15:04")))
+			return nil
+		case "cancel":
+			if len(args) != 2 {
+				return fmt.Errorf("usage: queue cancel <profile-id>")
+			}
+			p, action, err := queue.Cancel(c.cfg, c.store, args[1], clock.Now())
+			if err != nil {
+				return err
+			}
+			fmt.Printf("🗑️  %s\n", i18n.T("queue.cancelled_"+action, p.ID, p.Name))
+			return nil
+		case "list":
+			args = args[1:]
+		}
+	}
+
+	fs := flag.NewFlagSet("queue", flag.ContinueOnError)
+	only := fs.String("action", "", "Only list connection or message jobs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *only != "" && *only != queue.ActionConnection && *only != queue.ActionMessage {
+		return fmt.Errorf("-action must be %s or %s", queue.ActionConnection, queue.ActionMessage)
+	}
+	all, err := listQueue(c.cfg, c.store, clock.Now())
+	if err != nil {
+		return err
+	}
+	items := all[:0]
+	for _, item := range all {
+		if *only == "" || item.Action == *only {
+			items = append(items, item)
+		}
+	}
+
+	return render(c.output, items, func() {
+		fmt.Printf("\n📥 %s\n\n", i18n.T("queue.title", len(items)))
+		if len(items) == 0 {
+			fmt.Printf("  %s\n", i18n.T("queue.none"))
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, i18n.T("queue.header"))
+		for _, item := range items {
+			at := i18n.T("queue.past_horizon", c.cfg.App.QueueHorizonHours)
+			if item.At != nil {
+				at = item.At.Format("Mon 02 Jan 15:04")
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\n", item.Position, item.Action, item.ProfileID, item.Name, item.Priority, at)
+		}
+		w.Flush()
+	})
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"subspace/internal/auth"
+	"subspace/internal/bandwidth"
+	"subspace/internal/browser"
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/crash"
+	"subspace/internal/i18n"
+	"subspace/internal/logger"
+	"subspace/internal/proxy"
+	"subspace/internal/report"
+	"subspace/internal/stealth"
+	"subspace/internal/watchdog"
+)
+
+// recovery relaunches Chrome when it crashes or loses its CDP connection
+// during a workflow step. Steps persist every completed action, so rerunning
+// an interrupted step resumes its batch from the last completed action.
+type recovery struct {
+	b        *browser.Browser
+	s        *stealth.Stealth
+	auth     *auth.Authenticator
+	max      int
+	restarts int
+	cookies  []*proto.NetworkCookie // Session as of the last healthy checkpoint
+	watchdog *watchdog.Watchdog     // Checked after every step, if enabled
+	proxies  *proxy.Checker         // Rechecked between steps, if enabled
+	sessions *proxy.Sessions        // Rotated between steps, if due
+	upstream string                 // Proxy in use, before the session is embedded
+	meter    *bandwidth.Meter       // Told which step is running, if metering
+	crashes  *crash.Handler         // Recovers panics in steps
+	summary  *report.RunSummary     // Records step timings and errors
+}
+
+// checkpoint remembers the session cookies while Chrome is healthy
+func (r *recovery) checkpoint() {
+	if cookies, err := r.b.GetCookies(); err == nil && len(cookies) > 0 {
+		r.cookies = cookies
+	}
+}
+
+// step runs one workflow step, relaunching Chrome and rerunning the step
+// for as long as Chrome is found dead afterwards and restarts remain
+func (r *recovery) step(name string, fn func() error) (err error) {
+	start := clock.Now()
+	defer func() { r.summary.Step(name, start, clock.Now(), err) }()
+	r.meter.SetStep(name)
+	err = r.crashes.Guard(name, fn)
+	for !r.b.Alive() {
+		if r.restarts >= r.max {
+			return fmt.Errorf("browser lost during %s, %d restarts used: %w", name, r.restarts, errOrDisconnected(err))
+		}
+		r.restarts++
+		logger.Warn("Browser crashed or disconnected", "step", name, "restart", r.restarts, "max", r.max, "error", err)
+
+		if rerr := r.b.Relaunch(r.cookies); rerr != nil {
+			logger.Error("Browser relaunch failed", "error", rerr)
+			continue
+		}
+		r.s.SetPage(r.b.Page)
+		if len(r.cookies) == 0 {
+			// Nothing to restore from memory; fall back to the saved session
+			if lerr := r.auth.Login(); lerr != nil {
+				logger.Warn("Login after relaunch failed", "error", lerr)
+			}
+		}
+		fmt.Printf("🔁 %s\n", i18n.T("run.browser_recovered", name, r.restarts, r.max))
+		err = r.crashes.Guard(name, fn)
+	}
+	var panicked *crash.PanicError
+	if errors.As(err, &panicked) {
+		fmt.Printf("🚨 %s\n", i18n.T("run.panic", name, panicked.Report.Path))
+	}
+	r.checkpoint()
+	if r.watchdog != nil {
+		if _, werr := r.watchdog.Check(); werr != nil {
+			logger.Warn("Watchdog check failed", "step", name, "error", werr)
+		}
+	}
+	if perr := r.checkProxy(); perr != nil && err == nil {
+		err = perr
+	}
+	return err
+}
+
+// guard runs fn, a part of the run that doesn't drive Chrome through a
+// workflow step, recovering panics and recording it in the summary
+func (r *recovery) guard(name string, fn func() error) error {
+	start := clock.Now()
+	err := r.crashes.Guard(name, fn)
+	r.summary.Step(name, start, clock.Now(), err)
+	return err
+}
+
+// checkProxy rechecks the proxy when due and moves Chrome to the next
+// healthy one of the account if it failed; with none healthy the run has
+// to stop. A rotating proxy session that has run its course is renewed.
+func (r *recovery) checkProxy() error {
+	if r.proxies != nil && r.proxies.Due() {
+		from := config.ProxyHost(r.upstream)
+		switched, _, err := r.proxies.Recheck()
+		if err != nil {
+			fmt.Printf("🛑 %s\n", i18n.T("proxy.none_healthy", from))
+			return fmt.Errorf("proxy check: %w", err)
+		}
+		if switched {
+			r.upstream = r.proxies.Current()
+			fmt.Printf("🔀 %s\n", i18n.T("proxy.failover", from, config.ProxyHost(r.upstream)))
+			return r.useProxy()
+		}
+	}
+	if r.sessions.Due() {
+		if err := r.sessions.Rotate(); err != nil {
+			return err
+		}
+		fmt.Printf("🔄 %s\n", i18n.T("proxy.rotated", config.ProxyHost(r.upstream)))
+		return r.useProxy()
+	}
+	return nil
+}
+
+// useProxy relaunches Chrome on the upstream proxy with the current session
+func (r *recovery) useProxy() error {
+	r.b.SetProxy(r.sessions.Apply(r.upstream))
+	if err := r.relaunch(); err != nil {
+		return fmt.Errorf("failed to switch to proxy %s: %w", config.ProxyHost(r.upstream), err)
+	}
+	return nil
+}
+
+// PID returns the Chrome process ID, for the watchdog
+func (r *recovery) PID() int { return r.b.PID() }
+
+// PageCount returns the pages open in Chrome, for the watchdog
+func (r *recovery) PageCount() (int, error) { return r.b.PageCount() }
+
+// Recycle relaunches Chrome with the current session, for the watchdog
+func (r *recovery) Recycle() error {
+	if err := r.relaunch(); err != nil {
+		return err
+	}
+	fmt.Printf("♻️  %s\n", i18n.T("run.browser_recycled"))
+	return nil
+}
+
+// relaunch replaces a healthy Chrome, keeping the session
+func (r *recovery) relaunch() error {
+	r.checkpoint()
+	if err := r.b.Relaunch(r.cookies); err != nil {
+		return err
+	}
+	r.s.SetPage(r.b.Page)
+	return nil
+}
+
+// errOrDisconnected returns err, or a generic disconnect error for steps
+// that didn't notice Chrome going away
+func errOrDisconnected(err error) error {
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("browser disconnected")
+}
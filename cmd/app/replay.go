@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"subspace/internal/bench"
+	"subspace/internal/i18n"
+	"subspace/internal/logger"
+	"subspace/internal/stealth"
+)
+
+// replay handles "replay <trace file> [-action n]", re-executing recorded
+// actions with their recorded stealth decisions in simulation
+func (c *cli) replay(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: replay <trace file> [-action n]")
+	}
+	path := args[0]
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	action := fs.Int("action", 0, "Replay only the n-th action of the trace (0 = all)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	traces, err := stealth.ReadTraces(path)
+	if err != nil {
+		return err
+	}
+	if *action != 0 {
+		if *action < 0 || *action > len(traces) {
+			return fmt.Errorf("trace has %d actions, no action %d", len(traces), *action)
+		}
+		traces = traces[*action-1 : *action]
+	}
+
+	dir, err := os.MkdirTemp("", "subspace-replay-")
+	if err != nil {
+		return fmt.Errorf("failed to create replay directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if !machineReadable(c.output) {
+		fmt.Printf("⏪ %s\n", i18n.T("replay.running", len(traces), path))
+	}
+	logger.Init("error")
+	defer logger.Init(c.cfg.App.LogLevel)
+
+	results, err := bench.Replay(c.cfg, c.store, traces, dir)
+	if err != nil {
+		return err
+	}
+	return render(c.output, results, func() {
+		identical := 0
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, i18n.T("replay.header"))
+		for i, r := range results {
+			status := "✅"
+			if r.Identical() {
+				identical++
+			} else {
+				status = "⚠️"
+			}
+			diverged := "-"
+			if d := r.Stats.Divergence; d != nil {
+				diverged = i18n.T("replay.divergence", d.Index+1, kindLabel(d.Expected), kindLabel(d.Got))
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%d/%d\t%s\t%s\t%s\t\n",
+				status, i+1, r.Action, r.ProfileID, r.Stats.Taken, r.Stats.Recorded,
+				r.Recorded.Round(time.Millisecond), r.Replayed.Round(time.Millisecond), diverged)
+		}
+		w.Flush()
+		fmt.Printf("\n  %s\n", i18n.T("replay.summary", identical, len(results)))
+	})
+}
+
+// kindLabel shows a missing decision kind as a dash
+func kindLabel(kind string) string {
+	if kind == "" {
+		return "-"
+	}
+	return kind
+}
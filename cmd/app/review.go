@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"subspace/internal/clock"
+	"subspace/internal/i18n"
+	"subspace/internal/storage"
+)
+
+// review handles "review [list | accept <id>... | skip <id>...]". Without
+// arguments it walks the queue of discovered profiles one at a time.
+func (c *cli) review(args []string) error {
+	if len(args) == 0 {
+		if machineReadable(c.output) {
+			return c.reviewList()
+		}
+		return c.reviewInteractive()
+	}
+
+	switch args[0] {
+	case "list":
+		return c.reviewList()
+	case "accept", "skip":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: review %s <profile-id>...", args[0])
+		}
+		to := storage.StateApproved
+		if args[0] == "skip" {
+			to = storage.StateSkipped
+		}
+		for _, id := range args[1:] {
+			p, err := c.store.GetProfile(id)
+			if err != nil {
+				return err
+			}
+			if err := c.decide(p, to); err != nil {
+				return err
+			}
+			fmt.Printf("%s\n", i18n.T("review.decided", p.ID, p.Name, to))
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown review command: %s", args[0])
+	}
+}
+
+// reviewQueue returns the profiles awaiting review, oldest first
+func (c *cli) reviewQueue() []*storage.Profile {
+	queue := c.store.GetProfilesByState(storage.StateDiscovered)
+	sort.Slice(queue, func(i, j int) bool {
+		return queue[i].DiscoveredAt.Before(queue[j].DiscoveredAt)
+	})
+	return queue
+}
+
+// reviewList prints the review queue
+func (c *cli) reviewList() error {
+	queue := c.reviewQueue()
+	return render(c.output, queue, func() {
+		fmt.Printf("\n📋 %s\n\n", i18n.T("review.title", len(queue)))
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, i18n.T("review.header"))
+		for _, p := range queue {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+				p.ID, p.Name, p.Title, p.Company, p.MutualConnections(),
+				p.DiscoveredAt.Format("2006-01-02 15:04"), p.LatestNote())
+		}
+		w.Flush()
+	})
+}
+
+// reviewInteractive asks for a decision on each queued profile until the
+// queue is empty or the operator quits
+func (c *cli) reviewInteractive() error {
+	queue := c.reviewQueue()
+	if len(queue) == 0 {
+		fmt.Printf("\n✅ %s\n", i18n.T("review.empty"))
+		return nil
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	approved, skipped := 0, 0
+	fmt.Printf("\n📋 %s\n", i18n.T("review.title", len(queue)))
+	for i, p := range queue {
+		fmt.Printf("\n[%d/%d] %s\n", i+1, len(queue), p.Name)
+		fmt.Printf("   %s\n", strings.TrimSpace(p.Title+" @ "+p.Company))
+		fmt.Printf("   %s\n", p.ProfileURL)
+		if p.SearchQuery != "" {
+			fmt.Printf("   %s\n", i18n.T("review.found_by", p.SearchQuery))
+		}
+		if p.Shared != nil {
+			shared := append([]string{i18n.T("review.mutuals", p.Shared.MutualConnections)}, p.Shared.Groups...)
+			shared = append(shared, p.Shared.Schools...)
+			fmt.Printf("   🤝 %s\n", strings.Join(shared, " · "))
+		}
+		printNotes("   📝 ", p.NoteList())
+
+		answer := ""
+		for answer == "" {
+			fmt.Printf("   %s ", i18n.T("review.prompt"))
+			line, err := in.ReadString('\n')
+			answer = strings.ToLower(strings.TrimSpace(line))
+			if err != nil && answer == "" {
+				answer = "q" // stdin closed
+			}
+			if answer == "t" {
+				fmt.Printf("   %s ", i18n.T("review.note_prompt"))
+				text, _ := in.ReadString('\n')
+				if err := c.addNote(p, text); err != nil {
+					fmt.Printf("   ⚠️  %v\n", err)
+				}
+				answer = ""
+				continue
+			}
+			if answer != "a" && answer != "s" && answer != "n" && answer != "q" {
+				answer = ""
+			}
+		}
+
+		switch answer {
+		case "a":
+			if err := c.decide(p, storage.StateApproved); err != nil {
+				return err
+			}
+			approved++
+		case "s":
+			if err := c.decide(p, storage.StateSkipped); err != nil {
+				return err
+			}
+			skipped++
+		}
+		if answer == "q" {
+			break
+		}
+	}
+
+	fmt.Printf("\n✅ %s\n", i18n.T("review.summary", approved, skipped))
+	return nil
+}
+
+// decide records a review decision for a profile
+func (c *cli) decide(p *storage.Profile, to storage.ProfileState) error {
+	if err := p.Transition(to, clock.Now()); err != nil {
+		return err
+	}
+	if err := c.store.SaveProfile(p); err != nil {
+		return fmt.Errorf("failed to save review decision: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"subspace/internal/calendar"
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/i18n"
+	"subspace/internal/schedule"
+	"subspace/internal/storage"
+	"subspace/internal/worksession"
+)
+
+// upcoming projects the schedule over the next hours
+func upcoming(cfg *config.Config, db storage.Backend, hours int) ([]schedule.Event, error) {
+	cal, err := calendar.New(cfg.Stealth)
+	if err != nil {
+		return nil, err
+	}
+	ws, err := worksession.Current(cfg.App.DataDir)
+	if err != nil {
+		return nil, err
+	}
+	return schedule.Build(cfg, db, cal, ws, clock.Now(), time.Duration(hours)*time.Hour), nil
+}
+
+// sessionStart returns when the scheduled session the run is in began, for
+// the per-session message cap
+func sessionStart(cfg *config.Config) time.Time {
+	now := clock.Now()
+	cal, err := calendar.New(cfg.Stealth)
+	if err != nil {
+		return now
+	}
+	ws, err := worksession.Current(cfg.App.DataDir)
+	if err != nil {
+		return now
+	}
+	return schedule.SessionStart(cfg, cal, ws, now)
+}
+
+// schedule handles "schedule [-hours n]", listing what happens next
+func (c *cli) schedule(args []string) error {
+	fs := flag.NewFlagSet("schedule", flag.ContinueOnError)
+	hours := fs.Int("hours", c.cfg.App.ScheduleHours, "Hours ahead to list")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *hours < 1 {
+		return fmt.Errorf("-hours must be at least 1")
+	}
+
+	events, err := upcoming(c.cfg, c.store, *hours)
+	if err != nil {
+		return err
+	}
+	return render(c.output, events, func() {
+		fmt.Printf("\n📅 %s\n\n", i18n.T("schedule.title", *hours))
+		if len(events) == 0 {
+			fmt.Printf("  %s\n", i18n.T("schedule.none"))
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, i18n.T("schedule.header"))
+		for _, e := range events {
+			until := ""
+			if e.Until != nil {
+				until = e.Until.Format("Mon 15:04")
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.At.Format("Mon 02 Jan 15:04"), until, i18n.T("schedule."+e.Kind), e.Detail)
+		}
+		w.Flush()
+	})
+}
+
+// scheduleHandler serves the schedule as JSON; ?hours=n overrides the
+// configured horizon
+func scheduleHandler(cfg *config.Config, db storage.Backend) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hours := cfg.App.ScheduleHours
+		if v := r.URL.Query().Get("hours"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				http.Error(w, "hours must be a positive number", http.StatusBadRequest)
+				return
+			}
+			hours = n
+		}
+		events, err := upcoming(cfg, db, hours)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(events)
+	})
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"subspace/internal/i18n"
+	"subspace/internal/service"
+)
+
+// service handles "service install", which writes a systemd unit and
+// timer, or a launchd plist, running an automation cycle every interval
+func (c *cli) service(args []string) error {
+	if len(args) == 0 || args[0] != "install" {
+		return fmt.Errorf("usage: service install [-format systemd|launchd] [-interval d] [-print]")
+	}
+	defaultFormat := "systemd"
+	if runtime.GOOS == "darwin" {
+		defaultFormat = "launchd"
+	}
+	name := "subspace"
+	if c.cfg.App.Account != "" {
+		name += "-" + c.cfg.App.Account
+	}
+
+	fs := flag.NewFlagSet("service install", flag.ContinueOnError)
+	format := fs.String("format", defaultFormat, "systemd or launchd")
+	fs.StringVar(&name, "name", name, "Unit name or launchd label")
+	runAs := fs.String("user", serviceUser(), "User the service runs as (systemd)")
+	envFile := fs.String("env-file", ".env", "Environment file loaded first; skipped if it doesn't exist")
+	interval := fs.Duration("interval", 30*time.Minute, "Time between automation cycles")
+	restartDelay := fs.Duration("restart-delay", 5*time.Minute, "Wait before retrying a failed cycle")
+	dir := fs.String("dir", "", "Directory to write to (default /etc/systemd/system or ~/Library/LaunchAgents)")
+	printOnly := fs.Bool("print", false, "Print the files instead of writing them")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	spec, err := c.serviceSpec(name, *envFile, *runAs)
+	if err != nil {
+		return err
+	}
+	spec.Interval, spec.RestartDelay = *interval, *restartDelay
+
+	files := make(map[string]string)
+	var enable string
+	switch *format {
+	case "systemd":
+		unit, timer, err := service.Systemd(spec)
+		if err != nil {
+			return err
+		}
+		files[name+".service"], files[name+".timer"] = unit, timer
+		enable = fmt.Sprintf("systemctl daemon-reload && systemctl enable --now %s.timer", name)
+		if *dir == "" {
+			*dir = "/etc/systemd/system"
+		}
+	case "launchd":
+		plist, err := service.Launchd(spec)
+		if err != nil {
+			return err
+		}
+		files[name+".plist"] = plist
+		if *dir == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return err
+			}
+			*dir = filepath.Join(home, "Library", "LaunchAgents")
+		}
+		enable = fmt.Sprintf("launchctl load -w %s", filepath.Join(*dir, name+".plist"))
+	default:
+		return fmt.Errorf("unknown -format %q (must be systemd or launchd)", *format)
+	}
+
+	for _, file := range sortedKeys(files) {
+		if *printOnly {
+			fmt.Printf("# %s\n%s\n", file, files[file])
+			continue
+		}
+		path := filepath.Join(*dir, file)
+		if err := os.WriteFile(path, []byte(files[file]), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		fmt.Printf("✅ %s\n", i18n.T("service.written", path))
+	}
+	if !*printOnly {
+		fmt.Printf("   %s\n", i18n.T("service.enable", enable))
+	}
+	return nil
+}
+
+// serviceSpec describes this binary running the current config
+func (c *cli) serviceSpec(name, envFile, runAs string) (service.Spec, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return service.Spec{}, fmt.Errorf("failed to find the subspace binary: %w", err)
+	}
+	workDir, err := os.Getwd()
+	if err != nil {
+		return service.Spec{}, err
+	}
+	config, err := filepath.Abs(c.configPath)
+	if err != nil {
+		return service.Spec{}, err
+	}
+	dataDir, err := filepath.Abs(c.cfg.App.DataDir)
+	if err != nil {
+		return service.Spec{}, err
+	}
+	if envFile != "" {
+		if envFile, err = filepath.Abs(envFile); err != nil {
+			return service.Spec{}, err
+		}
+		if _, err := os.Stat(envFile); err != nil {
+			envFile = ""
+		}
+	}
+	return service.Spec{
+		Name:    name,
+		Exec:    []string{exe, "-config", config},
+		WorkDir: workDir,
+		DataDir: dataDir,
+		User:    runAs,
+		EnvFile: envFile,
+	}, nil
+}
+
+// serviceUser is the user to run the service as: whoever ran sudo, or
+// else the current user
+func serviceUser() string {
+	if name := os.Getenv("SUDO_USER"); name != "" {
+		return name
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/i18n"
+	"subspace/internal/logger"
+	"subspace/internal/stealth"
+	"subspace/internal/worksession"
+)
+
+// sessionStatus is the machine-readable view of the work session
+type sessionStatus struct {
+	Active    bool       `json:"active"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Remaining string     `json:"remaining,omitempty"`
+	Reason    string     `json:"reason,omitempty"`
+}
+
+// session handles "session start|status|end", manual work sessions that
+// allow supervised runs outside business hours
+func (c *cli) session(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: session start [-for 1h] [-reason text] [-yes] | session status | session end")
+	}
+
+	dataDir := c.cfg.App.DataDir
+	switch args[0] {
+	case "start":
+		return c.sessionStart(args[1:])
+	case "status":
+		ws, err := worksession.Current(dataDir)
+		if err != nil {
+			return err
+		}
+		status := sessionStatus{Active: ws != nil}
+		if ws != nil {
+			status.StartedAt, status.ExpiresAt = &ws.StartedAt, &ws.ExpiresAt
+			status.Remaining = ws.Remaining(clock.Now()).Round(time.Minute).String()
+			status.Reason = ws.Reason
+		}
+		return render(c.output, status, func() {
+			if ws == nil {
+				fmt.Printf("⏰ %s\n", i18n.T("session.none"))
+				return
+			}
+			fmt.Printf("🟢 %s\n", i18n.T("session.active", ws.ExpiresAt.Format("15:04"), status.Remaining))
+			if ws.Reason != "" {
+				fmt.Printf("   %s\n", i18n.T("session.reason", ws.Reason))
+			}
+		})
+	case "end":
+		if err := worksession.End(dataDir); err != nil {
+			return err
+		}
+		fmt.Printf("🛑 %s\n", i18n.T("session.ended"))
+		return nil
+	default:
+		return fmt.Errorf("unknown session command: %s", args[0])
+	}
+}
+
+// sessionStart starts a capped work session after explicit confirmation
+func (c *cli) sessionStart(args []string) error {
+	maxLength := time.Duration(c.cfg.Stealth.WorkSessionMaxMinutes) * time.Minute
+
+	fs := flag.NewFlagSet("session start", flag.ContinueOnError)
+	length := fs.Duration("for", minDuration(time.Hour, maxLength), "Session length, at most stealth.work_session_max_minutes")
+	reason := fs.String("reason", "", "Why the session is needed, kept with the session")
+	yes := fs.Bool("yes", false, "Skip the confirmation prompt")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if maxLength > 0 && *length > maxLength {
+		return fmt.Errorf("work session of %s exceeds the %s cap (stealth.work_session_max_minutes)", *length, maxLength)
+	}
+
+	if !*yes {
+		fmt.Printf("⚠️  %s\n", i18n.T("session.confirm", *length))
+		fmt.Printf("   %s ", i18n.T("session.confirm_prompt"))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.TrimSpace(strings.ToLower(answer)) != "yes" {
+			fmt.Printf("%s\n", i18n.T("session.aborted"))
+			return nil
+		}
+	}
+
+	ws, err := worksession.Start(c.cfg.App.DataDir, *length, maxLength, *reason)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("🟢 %s\n", i18n.T("session.started", ws.ExpiresAt.Format("15:04")))
+	fmt.Printf("   %s\n", i18n.T("session.started_hint"))
+	return nil
+}
+
+// applyWorkSession lets the stealth engine run outside business hours while
+// a manual work session is active, and returns that session or nil
+func applyWorkSession(s *stealth.Stealth, dataDir string) *worksession.Session {
+	ws, err := worksession.Current(dataDir)
+	if err != nil {
+		logger.Warn("Ignoring unreadable work session", "error", err)
+		return nil
+	}
+	if ws != nil {
+		s.SetWorkSession(ws.ExpiresAt)
+	}
+	return ws
+}
+
+// minDuration returns the shorter positive duration
+func minDuration(a, b time.Duration) time.Duration {
+	if b > 0 && b < a {
+		return b
+	}
+	return a
+}
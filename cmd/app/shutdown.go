@@ -0,0 +1,40 @@
+package main
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"subspace/internal/logger"
+	"subspace/internal/storage"
+)
+
+// flushOnSignal writes the storage's buffered changes when the process is
+// interrupted or terminated, then lets the signal end it as it would have
+func flushOnSignal(db *storage.Storage) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		if err := db.Close(); err != nil {
+			logger.Error("Failed to flush storage on shutdown", "error", err)
+		}
+		signal.Stop(signals)
+		if p, err := os.FindProcess(os.Getpid()); err == nil && p.Signal(sig) == nil {
+			select {} // The default handler ends the process
+		}
+		os.Exit(1)
+	}()
+}
+
+// closeAndExit closes the storage, writing its buffered changes, and then
+// exits with code: os.Exit skips the deferred closes
+func closeAndExit(code int, closers ...io.Closer) {
+	for _, c := range closers {
+		if err := c.Close(); err != nil {
+			logger.Error("Failed to flush storage on exit", "error", err)
+		}
+	}
+	os.Exit(code)
+}
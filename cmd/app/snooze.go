@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/i18n"
+)
+
+// snooze handles "snooze [list]", "snooze <profile-id> <until> [reason]"
+// and "snooze clear <profile-id>". Until is a date (2024-09-01) or a
+// duration from now (3d, 2w, 36h).
+func (c *cli) snooze(args []string) error {
+	if len(args) == 0 || args[0] == "list" {
+		return c.snoozeList()
+	}
+
+	if args[0] == "clear" {
+		if len(args) != 2 {
+			return fmt.Errorf("usage: snooze clear <profile-id>")
+		}
+		p, err := c.store.GetProfile(args[1])
+		if err != nil {
+			return err
+		}
+		p.Unsnooze()
+		if err := c.store.SaveProfile(p); err != nil {
+			return fmt.Errorf("failed to save profile: %w", err)
+		}
+		fmt.Printf("⏰ %s\n", i18n.T("snooze.cleared", p.ID, p.Name))
+		return nil
+	}
+
+	if len(args) < 2 {
+		return fmt.Errorf("usage: snooze <profile-id> <date|duration> [reason]")
+	}
+	p, err := c.store.GetProfile(args[0])
+	if err != nil {
+		return err
+	}
+	until, err := parseUntil(args[1], clock.Now())
+	if err != nil {
+		return err
+	}
+	p.Snooze(until, strings.Join(args[2:], " "))
+	if err := c.store.SaveProfile(p); err != nil {
+		return fmt.Errorf("failed to save profile: %w", err)
+	}
+	fmt.Printf("💤 %s\n", i18n.T("snooze.set", p.ID, p.Name, until.Format("2006-01-02 15:04")))
+	return nil
+}
+
+// snoozeList prints the snoozed profiles
+func (c *cli) snoozeList() error {
+	snoozed := c.store.SnoozedProfiles(clock.Now())
+	return render(c.output, snoozed, func() {
+		fmt.Printf("\n💤 %s\n\n", i18n.T("snooze.title", len(snoozed)))
+		if len(snoozed) == 0 {
+			fmt.Printf("  %s\n", i18n.T("snooze.none"))
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, i18n.T("snooze.header"))
+		for _, p := range snoozed {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				p.ID, p.Name, p.State, p.SnoozedUntil.Format("2006-01-02 15:04"), p.SnoozeReason)
+		}
+		w.Flush()
+	})
+}
+
+// parseUntil reads a snooze end given as a local date or a duration from
+// now; durations also accept days (d) and weeks (w)
+func parseUntil(s string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		if !t.After(now) {
+			return time.Time{}, fmt.Errorf("snooze date %s is in the past", s)
+		}
+		return t, nil
+	}
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, err := strconv.Atoi(strings.TrimSuffix(s, suffix)); err == nil && strings.HasSuffix(s, suffix) && n > 0 {
+			return now.Add(time.Duration(n) * unit), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid snooze end %q (use a date like 2024-09-01 or a duration like 3d, 2w, 36h)", s)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"subspace/internal/campaign"
+	"subspace/internal/i18n"
+	"subspace/internal/storage"
+)
+
+// activityReport is the machine-readable view of a filtered "stats"
+type activityReport struct {
+	From     string `json:"from,omitempty"`
+	To       string `json:"to,omitempty"`
+	Campaign string `json:"campaign,omitempty"`
+	Account  string `json:"account,omitempty"`
+	storage.Activity
+}
+
+// stats handles "stats [-campaign name] [-account name] [-from date]
+// [-to date]". Without flags it shows the pipeline overview; with any, the
+// activity they select broken down by campaign, account and day.
+func (c *cli) stats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	campaignName := fs.String("campaign", "", "Only actions on profiles of this campaign")
+	account := fs.String("account", "", "Only actions of this account")
+	from := fs.String("from", "", "First day, YYYY-MM-DD")
+	to := fs.String("to", "", "Last day, YYYY-MM-DD")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NFlag() == 0 {
+		return showStats(c.store, c.output)
+	}
+
+	filter := storage.StatsFilter{Campaign: *campaignName, Account: *account}
+	var err error
+	if filter.From, err = parseDay(*from); err != nil {
+		return fmt.Errorf("invalid -from: %w", err)
+	}
+	if filter.To, err = parseDay(*to); err != nil {
+		return fmt.Errorf("invalid -to: %w", err)
+	}
+	if !filter.To.IsZero() {
+		filter.To = filter.To.AddDate(0, 0, 1) // Through the end of the day
+	}
+	if campaigns := campaign.New(c.cfg.Targeting.Campaigns); campaigns.Enabled() {
+		filter.CampaignOf = campaigns.Of
+	}
+
+	rep := activityReport{From: *from, To: *to, Campaign: *campaignName, Account: *account, Activity: c.store.Activity(filter)}
+	return render(c.output, rep, func() {
+		title := i18n.T("stats.activity_title")
+		if filters := describeFilter(rep); filters != "" {
+			title += " (" + filters + ")"
+		}
+		fmt.Printf("\n📊 %s\n\n", title)
+		total := rep.Total
+		printStat("stats.connections", i18n.T("stats.accepted_rate", total.Connections, total.Accepted, total.AcceptanceRate()*100))
+		printStat("stats.messages", total.Messages)
+		printStat("stats.searches", total.Searches)
+		printStat("stats.failures", total.Failures)
+
+		printBreakdown("stats.by_campaign", rep.ByCampaign)
+		printBreakdown("stats.by_account", rep.ByAccount)
+		printBreakdown("stats.by_day", rep.ByDay)
+	})
+}
+
+// parseDay parses a YYYY-MM-DD day in local time; "" is the zero time
+func parseDay(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation("2006-01-02", s, time.Local)
+}
+
+// describeFilter summarizes the filters of a report for its title
+func describeFilter(rep activityReport) string {
+	var parts []string
+	if rep.From != "" || rep.To != "" {
+		parts = append(parts, fmt.Sprintf("%s – %s", orDash(rep.From), orDash(rep.To)))
+	}
+	if rep.Campaign != "" {
+		parts = append(parts, i18n.T("stats.filter_campaign", rep.Campaign))
+	}
+	if rep.Account != "" {
+		parts = append(parts, i18n.T("stats.filter_account", rep.Account))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printBreakdown prints one dimension of the activity as a table, unless
+// everything falls under a single key
+func printBreakdown(titleKey string, counts map[string]storage.Counts) {
+	if len(counts) < 2 {
+		return
+	}
+	fmt.Printf("\n%s\n", i18n.T(titleKey))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "  %s\n", i18n.T("stats.breakdown_header"))
+	for _, key := range sortedKeys(counts) {
+		c := counts[key]
+		fmt.Fprintf(w, "  %s\t%d\t%d\t%d\t%d\t%d\n", orDash(key), c.Connections, c.Accepted, c.Messages, c.Searches, c.Failures)
+	}
+	w.Flush()
+}
+
+// orDash shows an empty value as "-"
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"subspace/internal/campaign"
+	"subspace/internal/i18n"
+	"subspace/internal/messaging"
+	"subspace/internal/stealth"
+)
+
+// templates handles "templates list", "templates render -profile <id>
+// [-template <name>]" and "templates vars -profile <id>", previewing
+// messages without sending them, and "templates test", which checks them
+// against golden files
+func (c *cli) templates(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: templates list | render -profile <id> [-template <name>] | vars -profile <id> | test [-update]")
+	}
+	if args[0] == "test" {
+		return c.templatesTest(args[1:])
+	}
+	m := messaging.New(nil, stealth.New(c.cfg.Stealth, nil), c.store, c.cfg.Limits, c.cfg.Messaging)
+	m.SetCampaigns(campaign.New(c.cfg.Targeting.Campaigns))
+	names := m.ListTemplates()
+	sort.Strings(names)
+
+	switch args[0] {
+	case "list":
+		return render(c.output, names, func() {
+			for _, name := range names {
+				fmt.Println(name)
+			}
+		})
+	case "render", "vars":
+		fs := flag.NewFlagSet("templates "+args[0], flag.ContinueOnError)
+		profileID := fs.String("profile", "", "Profile to render for")
+		only := fs.String("template", "", "Render only this template")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *profileID == "" {
+			return fmt.Errorf("templates %s: -profile is required", args[0])
+		}
+		p, err := c.store.GetProfile(*profileID)
+		if err != nil {
+			return err
+		}
+		if *only != "" {
+			names = []string{*only}
+		}
+
+		if args[0] == "vars" {
+			data := m.TemplateData(*only, p)
+			return render(c.output, data, func() {
+				keys := make([]string, 0, len(data))
+				for k := range data {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				for _, k := range keys {
+					group, ok := data[k].(map[string]string)
+					if !ok {
+						fmt.Fprintf(w, "{{.%s}}\t%v\n", k, data[k])
+						continue
+					}
+					fields := make([]string, 0, len(group))
+					for f := range group {
+						fields = append(fields, f)
+					}
+					sort.Strings(fields)
+					for _, f := range fields {
+						fmt.Fprintf(w, "{{.%s.%s}}\t%v\n", k, f, group[f])
+					}
+				}
+				w.Flush()
+			})
+		}
+
+		rendered := make(map[string]string, len(names))
+		for _, name := range names {
+			content, err := m.Render(name, p)
+			if err != nil {
+				return err
+			}
+			rendered[name] = content
+		}
+		return render(c.output, rendered, func() {
+			for _, name := range names {
+				fmt.Printf("\n✉️  %s\n\n%s\n", i18n.T("templates.preview", name, p.Name), rendered[name])
+			}
+		})
+	default:
+		return fmt.Errorf("unknown templates command: %s", args[0])
+	}
+}
+
+// templatesTest handles "templates test [-dir <path>] [-update]", checking
+// the templates of every console language against their golden files
+func (c *cli) templatesTest(args []string) error {
+	fs := flag.NewFlagSet("templates test", flag.ContinueOnError)
+	dir := fs.String("dir", "testdata/templates", "Directory of golden files")
+	update := fs.Bool("update", false, "Write the current output as the new golden files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	defer i18n.SetLanguage(c.cfg.App.Language)
+	var results []messaging.GoldenResult
+	for _, lang := range i18n.Languages() {
+		if err := i18n.SetLanguage(lang); err != nil {
+			return err
+		}
+		m := messaging.New(nil, stealth.New(c.cfg.Stealth, nil), c.store, c.cfg.Limits, c.cfg.Messaging)
+		results = append(results, m.CheckGolden(*dir, *update)...)
+	}
+	i18n.SetLanguage(c.cfg.App.Language)
+
+	failed := 0
+	for _, r := range results {
+		if r.Failed() {
+			failed++
+		}
+	}
+	err := render(c.output, results, func() {
+		for _, r := range results {
+			if r.Status == messaging.GoldenOK {
+				continue
+			}
+			fmt.Printf("%-8s %s\n", r.Status, r.Path)
+			if r.Error != "" {
+				fmt.Printf("%s\n\n", r.Error)
+			}
+		}
+		fmt.Printf("\n🧪 %s\n", i18n.T("templates.tested", len(results), failed))
+	})
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d template checks failed", failed)
+	}
+	return nil
+}
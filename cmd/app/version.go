@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+
+	"subspace/internal/i18n"
+	"subspace/internal/update"
+	"subspace/internal/version"
+)
+
+// versionCheck is the result of "version -check"
+type versionCheck struct {
+	version.BuildInfo
+	Latest    string `json:"latest,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Available bool   `json:"update_available"`
+}
+
+// version handles "version [-check]", showing the build metadata and, with
+// -check, whether a newer release is out
+func (c *cli) version(args []string) error {
+	fs := flag.NewFlagSet("version", flag.ContinueOnError)
+	check := fs.Bool("check", false, "Check GitHub for a newer release")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	result := versionCheck{BuildInfo: version.Info()}
+	if *check {
+		rel, err := update.New().Latest()
+		if err != nil {
+			return err
+		}
+		result.Latest, result.URL = rel.Tag, rel.URL
+		result.Available = update.Newer(result.Version, rel.Tag)
+	}
+	return render(c.output, result, func() {
+		fmt.Printf("subspace %s\n", result.BuildInfo)
+		if !*check {
+			return
+		}
+		if result.Available {
+			fmt.Printf("⬆️  %s\n", i18n.T("version.available", result.Latest, result.URL))
+		} else {
+			fmt.Printf("✅ %s\n", i18n.T("version.up_to_date", result.Latest))
+		}
+	})
+}
+
+// selfUpdate handles "self-update", replacing this binary with the latest
+// release once its signature and checksum verify. Only newer releases are
+// installed.
+func (c *cli) selfUpdate(args []string) error {
+	fs := flag.NewFlagSet("self-update", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	key, err := update.ParseKey(update.PublicKey)
+	if err != nil {
+		return err
+	}
+	client := update.New()
+	rel, err := client.Latest()
+	if err != nil {
+		return err
+	}
+	current := version.Info().Version
+	if !update.Newer(current, rel.Tag) {
+		fmt.Printf("✅ %s\n", i18n.T("version.up_to_date", rel.Tag))
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the subspace binary: %w", err)
+	}
+	fmt.Printf("⬇️  %s\n", i18n.T("update.downloading", rel.Tag))
+	binary, err := client.Download(rel, update.AssetName(runtime.GOOS, runtime.GOARCH), current, key)
+	if err != nil {
+		return err
+	}
+	if err := update.Replace(exe, binary); err != nil {
+		return err
+	}
+	fmt.Printf("✅ %s\n", i18n.T("update.installed", current, rel.Tag, exe+".old"))
+	return nil
+}
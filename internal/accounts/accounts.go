@@ -0,0 +1,169 @@
+package accounts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+)
+
+/*
+ACCOUNT REGISTRY
+
+Remembers what each account was first run with: its proxy, time zone,
+persona and browser fingerprint. At startup the active account's binding
+in config.yaml is checked against the registry, so an edit that swaps two
+accounts' proxies or personas stops the run instead of exposing an account
+through the wrong identity. A deliberate change is accepted with
+"subspace accounts rebind <name>".
+
+The registry is a JSON file in the data directory. Proxy URLs may carry
+credentials, so only their host and a hash of the full URL are stored.
+*/
+
+// fileName is the registry file inside the data directory
+const fileName = "accounts.json"
+
+// Binding is what an account is bound to
+type Binding struct {
+	ProxyHost   string                   `json:"proxy_host"`
+	ProxyHash   string                   `json:"proxy_hash"` // Of the full URL, credentials included
+	TimeZone    string                   `json:"time_zone"`
+	Persona     string                   `json:"persona"`
+	Fingerprint config.FingerprintConfig `json:"fingerprint"`
+	BoundAt     time.Time                `json:"bound_at"`
+}
+
+// Bind returns the binding for an account's configuration
+func Bind(a config.AccountConfig) Binding {
+	sum := sha256.Sum256([]byte(a.Proxy))
+	return Binding{
+		ProxyHost:   config.ProxyHost(a.Proxy),
+		ProxyHash:   hex.EncodeToString(sum[:8]),
+		TimeZone:    a.TimeZone,
+		Persona:     a.Persona,
+		Fingerprint: a.Fingerprint,
+	}
+}
+
+// diff lists the fields in which two bindings differ
+func (b Binding) diff(o Binding) []string {
+	var fields []string
+	if b.ProxyHash != o.ProxyHash {
+		fields = append(fields, fmt.Sprintf("proxy %s -> %s", b.ProxyHost, o.ProxyHost))
+	}
+	if b.TimeZone != o.TimeZone {
+		fields = append(fields, fmt.Sprintf("time_zone %s -> %s", b.TimeZone, o.TimeZone))
+	}
+	if b.Persona != o.Persona {
+		fields = append(fields, fmt.Sprintf("persona %s -> %s", b.Persona, o.Persona))
+	}
+	if b.Fingerprint != o.Fingerprint {
+		fields = append(fields, "fingerprint")
+	}
+	return fields
+}
+
+// Registry is the set of known account bindings
+type Registry struct {
+	path     string
+	Accounts map[string]Binding `json:"accounts"`
+}
+
+// Load reads the registry of a data directory; a missing file is empty
+func Load(dataDir string) (*Registry, error) {
+	r := &Registry{path: filepath.Join(dataDir, fileName), Accounts: make(map[string]Binding)}
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read account registry: %w", err)
+	}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("failed to parse account registry %s: %w", r.path, err)
+	}
+	return r, nil
+}
+
+// Check verifies that an account runs with the binding it was registered
+// with. An account seen for the first time is registered, unless another
+// account is already bound to its proxy or fingerprint.
+func (r *Registry) Check(name string, a config.AccountConfig) error {
+	want := Bind(a)
+	if known, ok := r.Accounts[name]; ok {
+		if changed := known.diff(want); len(changed) > 0 {
+			return fmt.Errorf("account %s is bound differently than configured (%s); if intended, run: subspace accounts rebind %s",
+				name, strings.Join(changed, ", "), name)
+		}
+		return nil
+	}
+	if err := r.conflicts(name, want); err != nil {
+		return err
+	}
+	return r.set(name, want)
+}
+
+// Rebind registers an account's current configuration, replacing its old
+// binding. Proxies and fingerprints still can't be shared.
+func (r *Registry) Rebind(name string, a config.AccountConfig) error {
+	want := Bind(a)
+	if err := r.conflicts(name, want); err != nil {
+		return err
+	}
+	return r.set(name, want)
+}
+
+// Names returns the registered accounts in order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.Accounts))
+	for name := range r.Accounts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// conflicts reports another account bound to the same proxy or fingerprint
+func (r *Registry) conflicts(name string, b Binding) error {
+	for _, other := range r.Names() {
+		known := r.Accounts[other]
+		if other == name {
+			continue
+		}
+		if known.ProxyHash == b.ProxyHash {
+			return fmt.Errorf("proxy %s is already bound to account %s", b.ProxyHost, other)
+		}
+		if known.Fingerprint == b.Fingerprint {
+			return fmt.Errorf("fingerprint of account %s is already bound to account %s", name, other)
+		}
+	}
+	return nil
+}
+
+// set stores a binding and saves the registry
+func (r *Registry) set(name string, b Binding) error {
+	b.BoundAt = clock.Now()
+	r.Accounts[name] = b
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode account registry: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := os.WriteFile(r.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save account registry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package accounts
+
+import (
+	"strings"
+	"testing"
+
+	"subspace/internal/config"
+)
+
+func account(proxy, persona string, width int) config.AccountConfig {
+	return config.AccountConfig{
+		Proxy:       proxy,
+		TimeZone:    "Europe/Berlin",
+		Persona:     persona,
+		Fingerprint: config.FingerprintConfig{UserAgent: "UA", ViewportWidth: width, ViewportHeight: 900},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	r, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := account("socks5://alice:pw@gw.example:1080", "desktop", 1440)
+	if err := r.Check("alice", alice); err != nil {
+		t.Fatalf("first check: %v", err)
+	}
+
+	// The registry survives a reload and holds no credentials
+	r, err = Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := r.Accounts["alice"]; b.ProxyHost != "gw.example:1080" || strings.Contains(b.ProxyHash, "pw") {
+		t.Errorf("stored binding %+v", b)
+	}
+	if err := r.Check("alice", alice); err != nil {
+		t.Errorf("unchanged binding rejected: %v", err)
+	}
+
+	swapped := account("socks5://bob:pw@gw.example:1080", "laptop", 1440)
+	err = r.Check("alice", swapped)
+	if err == nil || !strings.Contains(err.Error(), "persona desktop -> laptop") || !strings.Contains(err.Error(), "proxy") {
+		t.Errorf("changed binding: got %v", err)
+	}
+
+	// A new account can't take over alice's proxy or fingerprint
+	if err := r.Check("bob", account(alice.Proxy, "laptop", 1280)); err == nil {
+		t.Error("shared proxy accepted")
+	}
+	if err := r.Check("bob", account("socks5://bob:pw@gw.example:1080", "desktop", 1440)); err == nil {
+		t.Error("shared fingerprint accepted")
+	}
+
+	if err := r.Rebind("alice", swapped); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Check("alice", swapped); err != nil {
+		t.Errorf("rebound binding rejected: %v", err)
+	}
+}
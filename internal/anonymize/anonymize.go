@@ -0,0 +1,298 @@
+package anonymize
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"subspace/internal/report"
+	"subspace/internal/storage"
+)
+
+/*
+ANONYMIZED EXPORT
+
+Runs are worth comparing across operators, but the data is about third
+parties: the people searched, contacted and written to. Build turns the
+pipeline into a dataset that keeps what research needs (funnel stages and
+their timings, message and action timing, the stealth telemetry of the
+last run) and drops everything that points at a person:
+
+  - names, titles, companies, profile URLs, locations below the country,
+    shared groups and schools, notes and tags
+  - message content, attachments and links, of which only the counts and
+    the length remain
+  - action log errors and batch errors, which quote pages and names
+  - the account name and search keywords
+
+Profiles are referred to by pseudonyms, an HMAC of the profile ID under a
+salt. Each export draws a fresh random salt, so two datasets can't be
+joined on the pseudonyms; passing the same salt keeps them stable across
+exports of one installation. Search keywords become pseudonyms the same
+way, so acceptance by keyword can still be compared.
+*/
+
+// FormatVersion is bumped when the dataset layout changes
+const FormatVersion = 1
+
+// Options configures Build
+type Options struct {
+	Since time.Time // Only profiles discovered, and events, at or after
+	Salt  []byte    // Pseudonym key; nil draws a random one
+
+	// Summary of the last run for its stealth telemetry; nil leaves the
+	// run out
+	Summary *report.RunSummary
+}
+
+// Dataset is the anonymized export
+type Dataset struct {
+	Format      int       `json:"format"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Since       time.Time `json:"since,omitempty"`
+
+	Funnel   Funnel    `json:"funnel"`
+	Profiles []Profile `json:"profiles"`
+	Messages []Message `json:"messages"`
+	Actions  []Action  `json:"actions"`
+	Run      *Run      `json:"last_run,omitempty"`
+}
+
+// Funnel counts the profiles that reached each stage
+type Funnel struct {
+	Discovered int `json:"discovered"`
+	Reviewed   int `json:"reviewed"`
+	Requested  int `json:"requested"`
+	Accepted   int `json:"accepted"`
+	Messaged   int `json:"messaged"`
+	Replied    int `json:"replied"`
+}
+
+// Profile is a profile reduced to its pipeline timing and classification
+type Profile struct {
+	ID                string     `json:"id"` // Pseudonym
+	State             string     `json:"state"`
+	Query             string     `json:"query,omitempty"` // Pseudonym of the search keywords
+	Seniority         string     `json:"seniority,omitempty"`
+	Function          string     `json:"function,omitempty"`
+	Industry          string     `json:"industry,omitempty"`
+	CompanySize       string     `json:"company_size,omitempty"`
+	Country           string     `json:"country,omitempty"`
+	MutualConnections *int       `json:"mutual_connections,omitempty"`
+	DiscoveredAt      time.Time  `json:"discovered_at"`
+	ReviewedAt        *time.Time `json:"reviewed_at,omitempty"`
+	RequestedAt       *time.Time `json:"requested_at,omitempty"`
+	AcceptedAt        *time.Time `json:"accepted_at,omitempty"`
+	CooledDownAt      *time.Time `json:"cooled_down_at,omitempty"`
+}
+
+// Message is a message without its content
+type Message struct {
+	Profile     string    `json:"profile"` // Pseudonym
+	Template    string    `json:"template,omitempty"`
+	SentAt      time.Time `json:"sent_at"`
+	Inbound     bool      `json:"inbound,omitempty"`
+	Length      int       `json:"length"` // In characters
+	Attachments int       `json:"attachments,omitempty"`
+	Links       int       `json:"links,omitempty"`
+}
+
+// Action is an action log entry without its error text
+type Action struct {
+	Action  string    `json:"action"`
+	At      time.Time `json:"at"`
+	Profile string    `json:"profile,omitempty"` // Pseudonym
+	Success bool      `json:"success"`
+	Source  string    `json:"source,omitempty"`
+}
+
+// Run is the telemetry of the last run
+type Run struct {
+	Outcome         string              `json:"outcome"`
+	StartedAt       time.Time           `json:"started_at"`
+	Duration        float64             `json:"duration_seconds"`
+	Steps           []Step              `json:"steps"`
+	ConnectionsSent int                 `json:"connections_sent"`
+	MessagesSent    int                 `json:"messages_sent"`
+	Limits          []report.LimitUsage `json:"limits"`
+	BrowserRestarts int                 `json:"browser_restarts"`
+	BandwidthBytes  int64               `json:"bandwidth_bytes,omitempty"`
+	StealthOverhead []report.Overhead   `json:"stealth_overhead"`
+	Batches         []BatchItem         `json:"batch_items"`
+}
+
+// Step is a workflow step's timing
+type Step struct {
+	Name     string  `json:"name"`
+	Duration float64 `json:"duration_seconds"`
+	Failed   bool    `json:"failed,omitempty"`
+}
+
+// BatchItem is the outcome and timing of one profile in a batch
+type BatchItem struct {
+	Action    string    `json:"action"`
+	Profile   string    `json:"profile"` // Pseudonym
+	Outcome   string    `json:"outcome"`
+	StartedAt time.Time `json:"started_at"`
+	Duration  float64   `json:"duration_seconds"`
+}
+
+// pseudonyms maps identifiers to keyed hashes
+type pseudonyms []byte
+
+// of returns the pseudonym of an identifier in a namespace, "" for ""
+func (key pseudonyms) of(namespace, id string) string {
+	if id == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(namespace + ":" + id))
+	return namespace[:1] + "_" + hex.EncodeToString(mac.Sum(nil))[:12]
+}
+
+// Build returns the anonymized dataset of the storage as of now
+func Build(db storage.Backend, opts Options, now time.Time) (*Dataset, error) {
+	key := pseudonyms(opts.Salt)
+	if len(key) == 0 {
+		key = make(pseudonyms, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to draw pseudonym salt: %w", err)
+		}
+	}
+	after := func(t time.Time) bool { return opts.Since.IsZero() || !t.Before(opts.Since) }
+
+	d := &Dataset{
+		Format:      FormatVersion,
+		GeneratedAt: now,
+		Since:       opts.Since,
+		Profiles:    []Profile{},
+		Messages:    []Message{},
+		Actions:     []Action{},
+	}
+
+	messaged, replied := make(map[string]bool), make(map[string]bool)
+	for _, m := range db.GetMessagesSince(opts.Since) {
+		if !after(m.SentAt) {
+			continue
+		}
+		if m.Inbound {
+			replied[m.ProfileID] = true
+		} else {
+			messaged[m.ProfileID] = true
+		}
+		d.Messages = append(d.Messages, Message{
+			Profile:     key.of("profile", m.ProfileID),
+			Template:    m.Template,
+			SentAt:      m.SentAt,
+			Inbound:     m.Inbound,
+			Length:      len([]rune(m.Content)),
+			Attachments: len(m.Attachments),
+			Links:       len(m.Links),
+		})
+	}
+	sort.SliceStable(d.Messages, func(i, j int) bool { return d.Messages[i].SentAt.Before(d.Messages[j].SentAt) })
+
+	for _, p := range db.GetAllProfiles() {
+		if !after(p.DiscoveredAt) {
+			continue
+		}
+		out := Profile{
+			ID:           key.of("profile", p.ID),
+			State:        string(p.State),
+			Query:        key.of("query", p.SearchQuery),
+			Seniority:    p.Seniority,
+			Function:     p.Function,
+			Industry:     p.Industry,
+			CompanySize:  p.CompanySize,
+			DiscoveredAt: p.DiscoveredAt,
+			ReviewedAt:   p.ReviewedAt,
+			RequestedAt:  p.RequestedAt,
+			AcceptedAt:   p.AcceptedAt,
+			CooledDownAt: p.CooledDownAt,
+		}
+		if p.Place != nil {
+			out.Country = p.Place.Country
+		}
+		if p.Shared != nil {
+			mutual := p.Shared.MutualConnections
+			out.MutualConnections = &mutual
+		}
+		d.Profiles = append(d.Profiles, out)
+
+		d.Funnel.Discovered++
+		for _, reached := range []struct {
+			stage *int
+			ok    bool
+		}{
+			{&d.Funnel.Reviewed, p.ReviewedAt != nil},
+			{&d.Funnel.Requested, p.RequestedAt != nil},
+			{&d.Funnel.Accepted, p.AcceptedAt != nil},
+			{&d.Funnel.Messaged, messaged[p.ID]},
+			{&d.Funnel.Replied, replied[p.ID]},
+		} {
+			if reached.ok {
+				*reached.stage++
+			}
+		}
+	}
+	sort.SliceStable(d.Profiles, func(i, j int) bool {
+		if !d.Profiles[i].DiscoveredAt.Equal(d.Profiles[j].DiscoveredAt) {
+			return d.Profiles[i].DiscoveredAt.Before(d.Profiles[j].DiscoveredAt)
+		}
+		return d.Profiles[i].ID < d.Profiles[j].ID
+	})
+
+	for _, l := range db.GetActionLogs("") {
+		if !after(l.Timestamp) {
+			continue
+		}
+		d.Actions = append(d.Actions, Action{
+			Action:  l.Action,
+			At:      l.Timestamp,
+			Profile: key.of("profile", l.ProfileID),
+			Success: l.Success,
+			Source:  l.Source,
+		})
+	}
+
+	if s := opts.Summary; s != nil && after(s.StartedAt) {
+		d.Run = anonymizeRun(s, key)
+	}
+	return d, nil
+}
+
+// anonymizeRun keeps the timings and counts of a run summary
+func anonymizeRun(s *report.RunSummary, key pseudonyms) *Run {
+	run := &Run{
+		Outcome:         s.Outcome,
+		StartedAt:       s.StartedAt,
+		Duration:        s.Duration,
+		Steps:           []Step{},
+		ConnectionsSent: s.ConnectionsSent,
+		MessagesSent:    s.MessagesSent,
+		Limits:          s.Limits,
+		BrowserRestarts: s.BrowserRestarts,
+		BandwidthBytes:  s.BandwidthBytes,
+		StealthOverhead: s.StealthOverhead,
+		Batches:         []BatchItem{},
+	}
+	for _, step := range s.Steps {
+		run.Steps = append(run.Steps, Step{Name: step.Name, Duration: step.Duration, Failed: step.Error != ""})
+	}
+	for _, b := range s.Batches {
+		for _, item := range b.Items {
+			run.Batches = append(run.Batches, BatchItem{
+				Action:    b.Action,
+				Profile:   key.of("profile", item.ProfileID),
+				Outcome:   item.Outcome,
+				StartedAt: item.StartedAt,
+				Duration:  item.Duration,
+			})
+		}
+	}
+	return run
+}
@@ -0,0 +1,84 @@
+package anonymize
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"subspace/internal/batch"
+	"subspace/internal/config"
+	"subspace/internal/report"
+	"subspace/internal/storage"
+)
+
+func TestBuildLeavesOutPersonalData(t *testing.T) {
+	db, err := storage.New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	accepted := day.Add(48 * time.Hour)
+	p := &storage.Profile{ID: "grace-id", Name: "Grace Hopper", Title: "Rear Admiral", Company: "US Navy",
+		Location: "Arlington, Virginia, United States", ProfileURL: "https://www.linkedin.com/in/grace-hopper",
+		State: storage.StateAccepted, DiscoveredAt: day, RequestedAt: &day, AcceptedAt: &accepted,
+		SearchQuery: "cobol pioneers", Tags: []string{"navy-list"},
+		Shared: &storage.SharedContext{MutualConnections: 4, Groups: []string{"COBOL Club"}}}
+	p.AddNote("met at the Smithsonian", day)
+	if err := db.SaveProfile(p); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveMessage(&storage.Message{ID: "m1", ProfileID: "grace-id", Template: "follow_up",
+		Content: "Dear Grace, loved your talk", SentAt: accepted, Links: []string{"https://example.com/talk"}}); err != nil {
+		t.Fatal(err)
+	}
+	db.LogAction("connection", "grace-id", false, errors.New("button missing on grace-hopper page"))
+
+	summary := report.NewRunSummary("1.0", "alice", day)
+	b := batch.New("message")
+	b.Add(p, "sent", day, errors.New("Grace Hopper blocked"))
+	summary.AddBatch(b)
+	summary.Step("message", day, day.Add(time.Minute), errors.New("failed on Grace Hopper"))
+	summary.AddOverhead("message", 1, time.Minute, 40*time.Second)
+
+	d, err := Build(db, Options{Salt: []byte("s3cret"), Summary: summary}, accepted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pii := range []string{"Grace", "Hopper", "grace", "Admiral", "Navy", "Arlington", "Virginia",
+		"linkedin", "cobol", "COBOL", "navy-list", "Smithsonian", "talk", "example.com", "alice", "button"} {
+		if strings.Contains(string(data), pii) {
+			t.Errorf("dataset contains %q: %s", pii, data)
+		}
+	}
+
+	if d.Funnel != (Funnel{Discovered: 1, Requested: 1, Accepted: 1, Messaged: 1}) {
+		t.Errorf("funnel = %+v", d.Funnel)
+	}
+	got := d.Profiles[0]
+	if got.Country != "United States" || *got.MutualConnections != 4 || !got.AcceptedAt.Equal(accepted) {
+		t.Errorf("profile = %+v", got)
+	}
+	if m := d.Messages[0]; m.Profile != got.ID || m.Length != 27 || m.Links != 1 {
+		t.Errorf("message = %+v", m)
+	}
+	if a := d.Actions[0]; a.Profile != got.ID || a.Success {
+		t.Errorf("action = %+v", a)
+	}
+	if d.Run == nil || d.Run.Batches[0].Profile != got.ID || !d.Run.Steps[0].Failed || len(d.Run.StealthOverhead) != 1 {
+		t.Errorf("run = %+v", d.Run)
+	}
+
+	// The salt decides whether exports can be joined
+	same, _ := Build(db, Options{Salt: []byte("s3cret")}, accepted)
+	fresh, _ := Build(db, Options{}, accepted)
+	if same.Profiles[0].ID != got.ID || fresh.Profiles[0].ID == got.ID {
+		t.Errorf("pseudonyms %s, %s, %s", got.ID, same.Profiles[0].ID, fresh.Profiles[0].ID)
+	}
+}
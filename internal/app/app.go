@@ -0,0 +1,129 @@
+package app
+
+import (
+	"fmt"
+
+	"subspace/internal/auth"
+	"subspace/internal/browser"
+	"subspace/internal/campaign"
+	"subspace/internal/config"
+	"subspace/internal/connect"
+	"subspace/internal/enrich"
+	"subspace/internal/estop"
+	"subspace/internal/hooks"
+	"subspace/internal/logger"
+	"subspace/internal/messaging"
+	"subspace/internal/modals"
+	"subspace/internal/notify"
+	"subspace/internal/search"
+	"subspace/internal/simulate"
+	"subspace/internal/stealth"
+	"subspace/internal/storage"
+	"subspace/internal/webstorage"
+)
+
+/*
+APP MODULE
+
+Builds the automation modules from the one loaded Config, so the run, the
+bench and tests wire them the same way instead of each picking config
+sections by hand. The browser, stealth engine and storage are passed in,
+since how they are created differs: a real Chrome for a run, the simulated
+browser for the bench, nil for tests that don't drive a page.
+*/
+
+// App is the set of automation modules built from one Config
+type App struct {
+	Config  *config.Config
+	Storage storage.Backend
+	Browser browser.Controller
+	Stealth *stealth.Stealth
+
+	Auth      *auth.Authenticator
+	Search    *search.Searcher
+	Connect   *connect.Connector
+	Messaging *messaging.Messenger
+	Enrich    *enrich.Enricher // Nil without an enrichment provider
+	Notifier  *notify.Notifier // Nil unless desktop notifications are on
+}
+
+// Options replaces parts of the default wiring
+type Options struct {
+	// Acceptance decides simulated acceptances; nil builds one from the
+	// simulation config
+	Acceptance simulate.AcceptanceModel
+
+	// Simulated leaves out what reaches outside the process: hooks,
+	// desktop notifications and enrichment lookups
+	Simulated bool
+}
+
+// New builds the modules for cfg around the given browser, stealth engine
+// and storage
+func New(cfg *config.Config, db storage.Backend, b browser.Controller, s *stealth.Stealth, opts Options) (*App, error) {
+	a := &App{Config: cfg, Storage: db, Browser: b, Stealth: s}
+
+	acceptance := opts.Acceptance
+	if acceptance == nil {
+		model, err := simulate.New(cfg.Simulation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize acceptance simulation: %w", err)
+		}
+		acceptance = model
+	}
+
+	a.Auth = auth.New(b, s, db, cfg.Auth)
+	a.Auth.SetWebStorage(webstorage.New(cfg.Auth.WebStorage, cfg.App.DataDir, cfg.App.Account))
+	a.Search = search.New(b, s, db, cfg.Search, cfg.Limits)
+	a.Connect = connect.New(b, s, db, cfg.Limits, cfg.Review, cfg.Targeting, acceptance)
+	a.Messaging = messaging.New(b, s, db, cfg.Limits, cfg.Messaging)
+	a.Messaging.SetTags(cfg.Targeting.Tags)
+	a.Messaging.SetCampaigns(campaign.New(cfg.Targeting.Campaigns))
+	if err := a.Messaging.CheckSender(); err != nil {
+		return nil, err
+	}
+	if d := modals.New(cfg.Modals, s); d != nil {
+		a.Connect.SetModals(d)
+		a.Messaging.SetModals(d)
+	}
+	if opts.Simulated {
+		return a, nil
+	}
+
+	enricher, err := enrich.New(cfg.Enrichment, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize enrichment: %w", err)
+	}
+	a.Enrich = enricher
+	a.Notifier = notify.New(cfg.Notifications)
+	a.Auth.SetNotifier(a.Notifier)
+	if h := hooks.New(cfg.Hooks); h != nil {
+		a.Connect.SetHooks(h)
+		a.Messaging.SetHooks(h)
+	}
+	if b != nil {
+		a.watch()
+	}
+	return a, nil
+}
+
+// watch handles the page lifecycle events for all modules. A security
+// challenge engages the emergency stop, so every module stops at its next
+// action until the operator has solved it and resumed; unexpected dialogs
+// and downloads, which the browser dismisses and denies, are logged.
+func (a *App) watch() {
+	log := logger.NewContext("app")
+	a.Browser.OnChallengePage(func(url string) {
+		log.Warn("Security challenge page, stopping all actions", "url", url)
+		a.Notifier.Notify(notify.Checkpoint, "Subspace", "Security challenge in the browser: solve it, then run subspace resume")
+		if err := estop.Engage("security challenge at " + url); err != nil {
+			log.Error("Failed to engage emergency stop", "error", err)
+		}
+	})
+	a.Browser.OnDialog(func(d browser.Dialog) {
+		log.Warn("Unexpected dialog dismissed", "type", d.Type, "message", d.Message, "url", d.URL)
+	})
+	a.Browser.OnDownload(func(d browser.Download) {
+		log.Warn("Unexpected download denied", "url", d.URL, "file", d.Filename)
+	})
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"subspace/internal/browser"
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/estop"
+	"subspace/internal/stealth"
+	"subspace/internal/storage"
+)
+
+func TestNewWiresEveryModule(t *testing.T) {
+	cfg := config.Defaults()
+	db, err := storage.New(filepath.Join(t.TempDir(), "db.json"), cfg.Storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := New(cfg, db, nil, stealth.New(cfg.Stealth, nil), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Auth == nil || a.Search == nil || a.Connect == nil || a.Messaging == nil {
+		t.Fatalf("app = %+v", a)
+	}
+	if got := a.Connect.GetStats().LimitDaily; got != cfg.Limits.ConnectionsPerDay {
+		t.Errorf("connector limit = %d, want %d from the config", got, cfg.Limits.ConnectionsPerDay)
+	}
+
+	cfg.Enrichment.Provider = "carrier-pigeon"
+	if _, err := New(cfg, db, nil, stealth.New(cfg.Stealth, nil), Options{}); err == nil {
+		t.Error("expected an unknown enrichment provider to fail")
+	}
+	if _, err := New(cfg, db, nil, stealth.New(cfg.Stealth, nil), Options{Simulated: true}); err != nil {
+		t.Errorf("simulated wiring shouldn't build the enricher: %v", err)
+	}
+}
+
+func TestChallengePageEngagesStop(t *testing.T) {
+	cfg := config.Defaults()
+	db, err := storage.New(filepath.Join(t.TempDir(), "db.json"), cfg.Storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	estop.SetFile(filepath.Join(t.TempDir(), "STOP"))
+	defer estop.SetFile("")
+
+	sim := browser.NewSim()
+	if _, err := New(cfg, db, sim, stealth.New(cfg.Stealth, nil), Options{}); err != nil {
+		t.Fatal(err)
+	}
+	sim.Navigate("https://www.linkedin.com/in/ada")
+	if estop.State().Engaged {
+		t.Fatal("stop engaged by a profile page")
+	}
+	sim.Navigate("https://www.linkedin.com/checkpoint/challenge/AgG1")
+	if err := estop.Check("connection"); err == nil {
+		t.Error("connection allowed after a challenge page")
+	}
+}
+
+// countingBackend is another Backend, counting the writes that reach it
+type countingBackend struct {
+	storage.Backend
+	logged, committed int
+}
+
+func (b *countingBackend) LogAction(action, profileID string, success bool, err error) error {
+	b.logged++
+	return b.Backend.LogAction(action, profileID, success, err)
+}
+
+func (b *countingBackend) Transaction(fn func(tx *storage.Tx) error) error {
+	b.committed++
+	return b.Backend.Transaction(fn)
+}
+
+func TestModulesUseTheBackend(t *testing.T) {
+	clock.Set(clock.NewFake(time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)))
+	defer clock.Set(clock.Real{})
+
+	cfg := config.Defaults()
+	cfg.Review.RequireApproval = false
+	db, err := storage.New(filepath.Join(t.TempDir(), "db.json"), cfg.Storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := &countingBackend{Backend: db}
+	a, err := New(cfg, backend, browser.NewSim(), stealth.New(cfg.Stealth, nil), Options{Simulated: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Search.RunSearch("golang", 1); err != nil {
+		t.Fatal(err)
+	}
+	if backend.logged == 0 {
+		t.Error("search did not log through the backend")
+	}
+	if _, err := a.Connect.ProcessDailyConnections(); err != nil {
+		t.Fatal(err)
+	}
+	if backend.committed == 0 {
+		t.Error("connection requests were not committed through the backend")
+	}
+}
@@ -1,10 +1,8 @@
 package auth
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -12,9 +10,13 @@ import (
 	
 	"subspace/internal/browser"
 	"subspace/internal/config"
+	"subspace/internal/cookies"
+	"subspace/internal/estop"
 	"subspace/internal/logger"
+	"subspace/internal/notify"
 	"subspace/internal/stealth"
 	"subspace/internal/storage"
+	"subspace/internal/webstorage"
 )
 
 /*
@@ -39,21 +41,19 @@ In a real system, this would:
 
 // Authenticator handles login and session management
 type Authenticator struct {
-	browser browser.Controller
-	stealth *stealth.Stealth
-	storage *storage.Storage
-	config  config.AuthConfig
-	log     *logger.ContextLogger
+	browser    browser.Controller
+	stealth    *stealth.Stealth
+	storage    storage.Backend
+	config     config.AuthConfig
+	webStorage *webstorage.Store // Nil when no key is allow-listed
+	notifier   *notify.Notifier  // Nil when desktop notifications are off
+	log        *logger.ContextLogger
 }
 
-// New creates a new authenticator
-func New(b browser.Controller, s *stealth.Stealth, storage *storage.Storage) *Authenticator {
-	// Load auth config from environment
-	cfg := config.AuthConfig{
-		SessionCookiePath: config.GetEnv("SESSION_COOKIE_PATH", "./data/session.json"),
-		ReuseSession:      true,
-		CheckpointRetries: 3,
-	}
+// New creates a new authenticator; SESSION_COOKIE_PATH overrides the
+// configured session path
+func New(b browser.Controller, s *stealth.Stealth, storage storage.Backend, cfg config.AuthConfig) *Authenticator {
+	cfg.SessionCookiePath = config.GetEnv("SESSION_COOKIE_PATH", cfg.SessionCookiePath)
 
 	return &Authenticator{
 		browser: b,
@@ -64,8 +64,23 @@ func New(b browser.Controller, s *stealth.Stealth, storage *storage.Storage) *Au
 	}
 }
 
+// SetWebStorage saves and restores allow-listed web storage with the
+// session cookies
+func (a *Authenticator) SetWebStorage(store *webstorage.Store) {
+	a.webStorage = store
+}
+
+// SetNotifier shows a desktop notification when a security checkpoint
+// is detected
+func (a *Authenticator) SetNotifier(n *notify.Notifier) {
+	a.notifier = n
+}
+
 // Login performs the login flow with session reuse and stealth
 func (a *Authenticator) Login() error {
+	if err := estop.Check("login"); err != nil {
+		return err
+	}
 	a.log.Info("Starting authentication process")
 	start := time.Now()
 
@@ -107,6 +122,7 @@ func (a *Authenticator) Login() error {
 		// Check if it's a checkpoint (security challenge)
 		if a.isCheckpoint(err) {
 			a.log.Warn("Security checkpoint detected, waiting before retry")
+			a.notifier.Notify(notify.Checkpoint, "Subspace", "Security checkpoint detected during login, check the browser")
 			// Exponential backoff
 			backoff := time.Duration(attempt*attempt) * time.Minute
 			time.Sleep(backoff)
@@ -148,8 +164,9 @@ func (a *Authenticator) performLogin() error {
 
 	// Step 4: Move mouse to email field (demonstrating Bézier movement)
 	a.log.Info("Moving to email field")
-	a.stealth.MoveMouse(400, 300) // Mock coordinates
+	a.stealth.MoveTo(0.33, 0.38) // Mock coordinates
 	a.stealth.RandomDelay()
+	a.stealth.ClickAt(0.33, 0.38)
 
 	// Step 5: Type email with human-like behavior
 	a.log.Info("Entering email", "email", maskEmail(email))
@@ -158,8 +175,9 @@ func (a *Authenticator) performLogin() error {
 
 	// Step 6: Move to password field
 	a.stealth.WanderMouse() // Simulate mouse wandering
-	a.stealth.MoveMouse(400, 400)
+	a.stealth.MoveTo(0.33, 0.5)
 	a.stealth.RandomDelay()
+	a.stealth.ClickAt(0.33, 0.5)
 
 	// Step 7: Type password
 	a.log.Info("Entering password")
@@ -171,7 +189,8 @@ func (a *Authenticator) performLogin() error {
 
 	// Step 9: Click login button
 	a.log.Info("Clicking login button")
-	a.stealth.MoveMouse(400, 500)
+	a.stealth.MoveTo(0.33, 0.62)
+	a.stealth.ClickAt(0.33, 0.62)
 	// In production: a.browser.Click("#login-submit")
 	a.stealth.RandomDelay()
 
@@ -202,25 +221,20 @@ func (a *Authenticator) tryLoadSession() error {
 		return fmt.Errorf("no session file found")
 	}
 
-	// Read cookies from file
-	data, err := os.ReadFile(a.config.SessionCookiePath)
+	// Read cookies from file, decrypting it if it was saved encrypted
+	saved, err := cookies.Load(a.config.SessionCookiePath)
 	if err != nil {
-		return fmt.Errorf("failed to read session file: %w", err)
-	}
-
-	var cookies []*proto.NetworkCookie
-	if err := json.Unmarshal(data, &cookies); err != nil {
-		return fmt.Errorf("failed to parse session file: %w", err)
+		return err
 	}
 
-	if len(cookies) == 0 {
+	if len(saved) == 0 {
 		return fmt.Errorf("no cookies found in session file")
 	}
 
 	// Check if cookies are expired
 	now := time.Now()
 	validCookies := make([]*proto.NetworkCookie, 0)
-	for _, cookie := range cookies {
+	for _, cookie := range saved {
 		if cookie.Expires > 0 && time.Unix(int64(cookie.Expires), 0).Before(now) {
 			a.log.Debug("Cookie expired", "name", cookie.Name)
 			continue
@@ -239,6 +253,8 @@ func (a *Authenticator) tryLoadSession() error {
 		}
 	}
 
+	a.restoreWebStorage()
+
 	// Navigate to verify session
 	// In production: a.browser.Navigate("https://www.linkedin.com/feed/")
 	a.stealth.WaitForPageLoad()
@@ -247,36 +263,57 @@ func (a *Authenticator) tryLoadSession() error {
 	return nil
 }
 
-// saveSession saves the current session cookies
+// saveSession saves the current session cookies that the cookie policy
+// keeps
 func (a *Authenticator) saveSession() error {
 	a.log.Info("Saving session cookies")
 
-	cookies, err := a.browser.GetCookies()
+	all, err := a.browser.GetCookies()
 	if err != nil {
 		return fmt.Errorf("failed to get cookies: %w", err)
 	}
+	kept, dropped := cookies.NewPolicy(a.config.Cookies).Filter(all, time.Now())
 
-	// Serialize cookies to JSON
-	data, err := json.MarshalIndent(cookies, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal cookies: %w", err)
+	if err := cookies.Save(a.config.SessionCookiePath, kept); err != nil {
+		return err
 	}
 
-	// Ensure directory exists
-	dir := filepath.Dir(a.config.SessionCookiePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
+	a.log.Info("Session saved", "path", a.config.SessionCookiePath, "cookies", len(kept), "dropped", len(dropped))
 
-	// Write to file
-	if err := os.WriteFile(a.config.SessionCookiePath, data, 0600); err != nil {
-		return fmt.Errorf("failed to write session file: %w", err)
+	if a.webStorage != nil {
+		ws, err := a.browser.GetWebStorage()
+		if err != nil {
+			return err
+		}
+		n, err := a.webStorage.Save(ws)
+		if err != nil {
+			return err
+		}
+		a.log.Info("Web storage saved", "origin", ws.Origin, "items", n)
 	}
-
-	a.log.Info("Session saved", "path", a.config.SessionCookiePath, "cookies", len(cookies))
 	return nil
 }
 
+// restoreWebStorage reapplies the saved web storage; the session may still
+// work on cookies alone, so failures are only logged
+func (a *Authenticator) restoreWebStorage() {
+	if a.webStorage == nil {
+		return
+	}
+	ws, ok, err := a.webStorage.Load()
+	if err != nil || !ok {
+		if err != nil {
+			a.log.Warn("Failed to load web storage", "error", err)
+		}
+		return
+	}
+	if err := a.browser.SetWebStorage(ws); err != nil {
+		a.log.Warn("Failed to restore web storage", "error", err)
+		return
+	}
+	a.log.Info("Web storage restored", "origin", ws.Origin, "items", len(ws.Local)+len(ws.Session))
+}
+
 // isCheckpoint checks if an error indicates a security checkpoint
 func (a *Authenticator) isCheckpoint(err error) bool {
 	if err == nil {
@@ -0,0 +1,152 @@
+package bandwidth
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/metrics"
+)
+
+/*
+BANDWIDTH MODULE
+
+Proxy providers bill by traffic, so every run meters what the browser
+receives over the network (CDP Network.loadingFinished, bytes on the wire)
+and appends a record to <data_dir>/bandwidth.jsonl when it ends. Bytes are
+attributed to the workflow step running at the time and, while connection
+requests are sent, to the campaign of the profile. "subspace stats
+bandwidth" sums the records and prices them at bandwidth.cost_per_gb.
+*/
+
+// fileName is the run log inside the data directory
+const fileName = "bandwidth.jsonl"
+
+var received = metrics.NewCounter("bandwidth_received_bytes_total", "Bytes the browser received over the network")
+
+// Run is the traffic of one automation run
+type Run struct {
+	Account    string           `json:"account,omitempty"`
+	StartedAt  time.Time        `json:"started_at"`
+	EndedAt    time.Time        `json:"ended_at"`
+	Bytes      int64            `json:"bytes"`
+	Requests   int              `json:"requests"`
+	ByStep     map[string]int64 `json:"by_step,omitempty"`
+	ByCampaign map[string]int64 `json:"by_campaign,omitempty"`
+}
+
+// Meter accumulates the traffic of a run. It is safe for concurrent use,
+// as network events arrive on their own goroutine.
+type Meter struct {
+	mu       sync.Mutex
+	run      Run
+	step     string
+	campaign string
+}
+
+// NewMeter starts metering a run of an account
+func NewMeter(account string) *Meter {
+	return &Meter{run: Run{
+		Account:    account,
+		StartedAt:  clock.Now(),
+		ByStep:     make(map[string]int64),
+		ByCampaign: make(map[string]int64),
+	}}
+}
+
+// Add records a completed request
+func (m *Meter) Add(bytes int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.run.Bytes += bytes
+	m.run.Requests++
+	if m.step != "" {
+		m.run.ByStep[m.step] += bytes
+	}
+	if m.campaign != "" {
+		m.run.ByCampaign[m.campaign] += bytes
+	}
+	received.Add(float64(bytes))
+}
+
+// SetStep attributes the following traffic to a workflow step
+func (m *Meter) SetStep(step string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.step = step
+	m.mu.Unlock()
+}
+
+// SetCampaign attributes the following traffic to a campaign; "" stops
+// attributing to any
+func (m *Meter) SetCampaign(campaign string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	m.campaign = campaign
+	m.mu.Unlock()
+}
+
+// Finish ends the run and appends it to the run log of dataDir
+func (m *Meter) Finish(dataDir string) (Run, error) {
+	m.mu.Lock()
+	m.run.EndedAt = clock.Now()
+	run := m.run
+	m.mu.Unlock()
+
+	data, err := json.Marshal(run)
+	if err != nil {
+		return run, fmt.Errorf("failed to encode bandwidth record: %w", err)
+	}
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return run, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(dataDir, fileName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return run, fmt.Errorf("failed to open bandwidth log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return run, fmt.Errorf("failed to write bandwidth log: %w", err)
+	}
+	return run, nil
+}
+
+// Runs reads the runs of dataDir that started at or after since
+func Runs(dataDir string, since time.Time) ([]Run, error) {
+	f, err := os.Open(filepath.Join(dataDir, fileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bandwidth log: %w", err)
+	}
+	defer f.Close()
+
+	var runs []Run
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		var r Run
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("bandwidth log line %d: %w", line, err)
+		}
+		if !r.StartedAt.Before(since) {
+			runs = append(runs, r)
+		}
+	}
+	return runs, sc.Err()
+}
+
+// Cost prices bytes at a cost per GB (10^9 bytes, as providers bill)
+func Cost(bytes int64, perGB float64) float64 {
+	return float64(bytes) / 1e9 * perGB
+}
@@ -0,0 +1,64 @@
+package bandwidth
+
+import (
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+)
+
+func TestMeterAttributesAndRoundTrips(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC))
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	m := NewMeter("main")
+	m.Add(100) // Before any step
+	m.SetStep("search")
+	m.Add(1000)
+	m.SetStep("connect")
+	m.SetCampaign("founders")
+	m.Add(500)
+	m.SetCampaign("")
+	m.Add(50)
+	fake.Advance(time.Hour)
+
+	dir := t.TempDir()
+	run, err := m.Finish(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Bytes != 1650 || run.Requests != 4 {
+		t.Fatalf("got %d bytes in %d requests, want 1650 in 4", run.Bytes, run.Requests)
+	}
+	if run.ByStep["search"] != 1000 || run.ByStep["connect"] != 550 {
+		t.Errorf("by step = %v", run.ByStep)
+	}
+	if run.ByCampaign["founders"] != 500 || len(run.ByCampaign) != 1 {
+		t.Errorf("by campaign = %v", run.ByCampaign)
+	}
+
+	runs, err := Runs(dir, run.StartedAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 1 || runs[0].Bytes != 1650 || runs[0].Account != "main" {
+		t.Fatalf("runs = %+v", runs)
+	}
+	if runs, _ := Runs(dir, run.StartedAt.Add(time.Minute)); len(runs) != 0 {
+		t.Errorf("runs after start = %d, want 0", len(runs))
+	}
+}
+
+func TestRunsWithoutLog(t *testing.T) {
+	runs, err := Runs(t.TempDir(), time.Time{})
+	if err != nil || runs != nil {
+		t.Fatalf("got %v, %v", runs, err)
+	}
+}
+
+func TestCost(t *testing.T) {
+	if got := Cost(2_500_000_000, 4); got != 10 {
+		t.Errorf("cost = %v, want 10", got)
+	}
+}
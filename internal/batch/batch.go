@@ -0,0 +1,83 @@
+package batch
+
+import (
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/storage"
+)
+
+/*
+BATCH MODULE
+
+Per-profile results of a batch of connection requests or messages, so
+callers can see which profiles succeeded, which failed and why, instead of
+a single error for the whole batch.
+*/
+
+// Item outcomes
+const (
+	Sent     = "sent"
+	Failed   = "failed"
+	Skipped  = "skipped"   // Held back by a guardrail or a hook, not attempted
+	TimedOut = "timed_out" // Abandoned after overrunning its time budget
+)
+
+// Reasons a batch ended before its budget or candidates ran out
+const (
+	StoppedDailyLimit  = "daily_limit"
+	StoppedHourlyLimit = "hourly_limit"
+	StoppedEmergency   = "emergency_stop"
+	StoppedSessionCap  = "session_cap"
+)
+
+// Item is the result for one profile of a batch
+type Item struct {
+	ProfileID string    `json:"profile_id"`
+	Name      string    `json:"name,omitempty"`
+	Outcome   string    `json:"outcome"`
+	Error     string    `json:"error,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	Duration  float64   `json:"duration_seconds"`
+}
+
+// Result is the outcome of a batch of one action
+type Result struct {
+	Action  string `json:"action"` // connection or message
+	Items   []Item `json:"items"`
+	Stopped string `json:"stopped,omitempty"` // Why the batch ended early
+}
+
+// New starts the result of a batch of action
+func New(action string) *Result {
+	return &Result{Action: action, Items: []Item{}}
+}
+
+// Add records the outcome for a profile whose processing began at start
+func (r *Result) Add(p *storage.Profile, outcome string, start time.Time, err error) {
+	item := Item{
+		ProfileID: p.ID,
+		Name:      p.Name,
+		Outcome:   outcome,
+		StartedAt: start,
+		Duration:  clock.Now().Sub(start).Seconds(),
+	}
+	if err != nil {
+		item.Error = err.Error()
+	}
+	r.Items = append(r.Items, item)
+}
+
+// Count returns the items with the outcome
+func (r *Result) Count(outcome string) int {
+	if r == nil {
+		return 0
+	}
+	n := 0
+	for _, item := range r.Items {
+		if item.Outcome == outcome {
+			n++
+		}
+	}
+	return n
+}
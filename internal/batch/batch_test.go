@@ -0,0 +1,35 @@
+package batch
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/storage"
+)
+
+func TestResult(t *testing.T) {
+	start := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	r := New("connection")
+	fake.Advance(3 * time.Second)
+	r.Add(&storage.Profile{ID: "a", Name: "Ann"}, Sent, start, nil)
+	r.Add(&storage.Profile{ID: "b"}, Failed, start, errors.New("button not found"))
+	r.Add(&storage.Profile{ID: "c"}, Skipped, start, nil)
+
+	if r.Count(Sent) != 1 || r.Count(Failed) != 1 || r.Count(Skipped) != 1 {
+		t.Errorf("counts = %d/%d/%d", r.Count(Sent), r.Count(Failed), r.Count(Skipped))
+	}
+	if item := r.Items[1]; item.ProfileID != "b" || item.Error != "button not found" || item.Duration != 3 {
+		t.Errorf("item = %+v", item)
+	}
+
+	var none *Result
+	if none.Count(Sent) != 0 {
+		t.Error("nil result should count nothing")
+	}
+}
@@ -0,0 +1,284 @@
+package bench
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	"subspace/internal/app"
+	"subspace/internal/browser"
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/connect"
+	"subspace/internal/logger"
+	"subspace/internal/messaging"
+	"subspace/internal/metrics"
+	"subspace/internal/ratelimit"
+	"subspace/internal/search"
+	"subspace/internal/simulate"
+	"subspace/internal/stealth"
+	"subspace/internal/storage"
+)
+
+/*
+BENCH MODULE
+
+Runs the real search -> connect -> accept -> message pipeline against a
+throwaway storage file seeded with synthetic profiles, under a fake clock.
+Every stealth delay advances the fake clock instead of sleeping, so a week
+of business-hours activity completes in seconds of wall time.
+
+The report answers three questions before any backend redesign:
+- throughput: pipeline actions per wall-clock second
+- storage growth: file size and action log length at the end of each day
+- limiter correctness: whether any daily or hourly limit was ever exceeded
+  in a sliding window, checked independently against the recorded action log
+
+EXPERIMENTS:
+A pacing experiment (config experiments section) runs the bench once per
+arm with that arm's limits and stealth overrides, on the same seeds, and
+compares outcomes (connections, acceptances, messages) with pacing signals
+(peak hourly volume, regularity of gaps between actions, day-to-day
+variation). Detection itself isn't simulated: the signals are what a
+detector would see, so claims like "slower pacing looks less scripted" can
+be checked instead of assumed.
+
+CHAOS:
+With simulation.faults enabled (or Options.Chaos), the pipeline drives a
+simulated browser that hides elements, slows navigations and drops the
+session cookies at random. Failed actions are logged and retried in later
+cycles like any other failure; a dropped session skips the cycle and logs
+back in for the next one. The report counts the injected faults next to
+the usual outcomes.
+*/
+
+// Options configures a bench run
+type Options struct {
+	Profiles int           // Synthetic profiles seeded before the run
+	Days     int           // Simulated days to run
+	Interval time.Duration // Simulated time between pipeline cycles
+	Seed     int64         // Seeds synthetic data and the acceptance model; 0 = random
+	DataDir  string        // Where the throwaway storage file is written
+	Chaos    bool          // Inject simulation.faults even if not enabled in config
+}
+
+// Report summarises a bench run
+type Report struct {
+	Profiles         int                   `json:"profiles"`
+	SimulatedStart   time.Time             `json:"simulated_start"`
+	SimulatedEnd     time.Time             `json:"simulated_end"`
+	WallTime         time.Duration         `json:"wall_time"`
+	Cycles           int                   `json:"cycles"`
+	Actions          map[string]int        `json:"actions"`
+	ActionsPerSecond float64               `json:"actions_per_second"`
+	Speedup          float64               `json:"speedup"` // Simulated time per wall-clock time
+	Funnel           map[string]int        `json:"funnel"`
+	Saves            int64                 `json:"saves"`
+	SaveAvg          time.Duration         `json:"save_avg"`
+	SaveMax          time.Duration         `json:"save_max"`
+	Growth           []DaySample           `json:"growth"`
+	Violations       []ratelimit.Violation `json:"violations"`
+	Pacing           Pacing                `json:"pacing"`
+	Faults           map[string]int        `json:"faults,omitempty"` // Injected faults by name, in chaos mode
+	Failed           map[string]int        `json:"failed,omitempty"` // Failed actions by type
+	SessionsLost     int                   `json:"sessions_lost"`    // Cycles skipped for a dropped session
+}
+
+// DaySample is the storage footprint at the end of a simulated day
+type DaySample struct {
+	Day        int   `json:"day"`
+	SizeBytes  int64 `json:"size_bytes"`
+	ActionLogs int   `json:"action_logs"`
+	Messages   int   `json:"messages"`
+}
+
+// LimiterOK reports whether no limit was exceeded
+func (r *Report) LimiterOK() bool {
+	return len(r.Violations) == 0
+}
+
+// Run executes the pipeline under a fake clock and reports on it. The
+// process-wide clock is restored before returning.
+func Run(cfg *config.Config, opts Options) (*Report, error) {
+	log := logger.NewContext("bench")
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+
+	// Start at midnight so each simulated day is a full calendar day
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	fake := clock.NewFake(start)
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	path := filepath.Join(opts.DataDir, "db.json")
+	db, err := storage.New(path, cfg.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bench storage: %w", err)
+	}
+	defer db.Close()
+	if err := seedProfiles(db, opts.Profiles, opts.Seed, start); err != nil {
+		return nil, fmt.Errorf("failed to seed profiles: %w", err)
+	}
+
+	simCfg := cfg.Simulation
+	simCfg.Seed = opts.Seed
+	model, err := simulate.New(simCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var page browser.Simulation = browser.NewSim()
+	var chaos *browser.Chaos
+	if opts.Chaos || cfg.Simulation.Faults.Enabled {
+		chaos = browser.NewChaos(page, cfg.Simulation.Faults, opts.Seed)
+		page = chaos
+	}
+
+	s := stealth.New(cfg.Stealth, nil)
+	modules, err := app.New(cfg, db, page, s, app.Options{Acceptance: model, Simulated: true})
+	if err != nil {
+		return nil, err
+	}
+	searcher, connector, messenger := modules.Search, modules.Connect, modules.Messaging
+
+	saves := metrics.NewTimer("storage_save_seconds", "")
+	savesBefore, sumBefore, _, _ := saves.Snapshot()
+
+	report := &Report{Profiles: opts.Profiles, SimulatedStart: start}
+	end := start.AddDate(0, 0, opts.Days)
+	nextDay := start.AddDate(0, 0, 1)
+	wallStart := time.Now()
+
+	for next := start; fake.Now().Before(end); next = next.Add(opts.Interval) {
+		fake.AdvanceTo(next)
+		for !fake.Now().Before(nextDay) {
+			report.Growth = append(report.Growth, sample(db, path, len(report.Growth)+1))
+			nextDay = nextDay.AddDate(0, 0, 1)
+		}
+		if !fake.Now().Before(end) || !s.CheckBusinessHours() {
+			continue
+		}
+
+		report.Cycles++
+		if !page.HasValidSession() {
+			// Logged out: give up on this cycle and log back in for the next
+			log.Warn("Session lost, logging in again")
+			report.SessionsLost++
+			cookies, _ := browser.NewSim().GetCookies()
+			page.SetCookies(cookies)
+			continue
+		}
+		runCycle(cfg, db, searcher, connector, messenger, log)
+	}
+	if len(report.Growth) < opts.Days {
+		report.Growth = append(report.Growth, sample(db, path, len(report.Growth)+1))
+	}
+
+	report.WallTime = time.Since(wallStart)
+	report.SimulatedEnd = fake.Now()
+
+	logs := db.GetActionLogs("")
+	report.Actions = make(map[string]int)
+	report.Failed = make(map[string]int)
+	total := 0
+	for _, l := range logs {
+		if l.Success {
+			report.Actions[l.Action]++
+			total++
+		} else {
+			report.Failed[l.Action]++
+		}
+	}
+	if chaos != nil {
+		report.Faults = chaos.Injected()
+	}
+	report.Funnel = make(map[string]int)
+	for _, p := range db.GetAllProfiles() {
+		report.Funnel[string(p.State)]++
+	}
+	if secs := report.WallTime.Seconds(); secs > 0 {
+		report.ActionsPerSecond = float64(total) / secs
+		report.Speedup = report.SimulatedEnd.Sub(start).Seconds() / secs
+	}
+
+	count, sum, max, _ := saves.Snapshot()
+	report.Saves = count - savesBefore
+	if report.Saves > 0 {
+		report.SaveAvg = (sum - sumBefore) / time.Duration(report.Saves)
+	}
+	report.SaveMax = max
+
+	report.Violations = ratelimit.Check(logs, ratelimit.Windows(cfg.Limits))
+	report.Pacing = MeasurePacing(logs)
+	return report, nil
+}
+
+// runCycle runs one pass of the automation workflow, mirroring runAutomation
+func runCycle(cfg *config.Config, db *storage.Storage, searcher *search.Searcher, connector *connect.Connector, messenger *messaging.Messenger, log *logger.ContextLogger) {
+	if cfg.Modules.Search && searcher.CanSearchMore() {
+		if err := searcher.RunSearch("bench", 1); err != nil {
+			log.Warn("Search failed", "error", err)
+		}
+	}
+	if cfg.Modules.Connect && connector.CanSendMore() {
+		if _, err := connector.ProcessDailyConnections(); err != nil {
+			log.Warn("Connection processing failed", "error", err)
+		}
+	}
+	if cfg.Modules.Acceptance {
+		if err := connector.CheckAcceptedConnections(); err != nil {
+			log.Warn("Acceptance check failed", "error", err)
+		}
+	}
+	if cfg.Modules.Messaging && messenger.CanSendMore() {
+		if _, err := messenger.ProcessAcceptedConnections(); err != nil {
+			log.Warn("Messaging failed", "error", err)
+		}
+	}
+}
+
+// sample records the storage footprint for a finished day
+func sample(db *storage.Storage, path string, day int) DaySample {
+	ds := DaySample{
+		Day:        day,
+		ActionLogs: len(db.GetActionLogs("")),
+		Messages:   db.MessageCount(),
+	}
+	if info, err := os.Stat(path); err == nil {
+		ds.SizeBytes = info.Size()
+	}
+	return ds
+}
+
+// Synthetic profile data; titles span every seniority level so the
+// seniority acceptance model has something to work with
+var (
+	firstNames = []string{"Ana", "Ben", "Chen", "Dana", "Elif", "Farid", "Grace", "Hiro", "Ines", "Jonas", "Kofi", "Lena", "Mateo", "Nora", "Omar", "Priya"}
+	lastNames  = []string{"Garcia", "Schmidt", "Okafor", "Tanaka", "Novak", "Rossi", "Kowalski", "Haddad", "Silva", "Larsen", "Ivanova", "Murphy"}
+	titles     = []string{"Junior Developer", "Software Engineer", "Backend Engineer", "Senior Software Engineer", "Staff Engineer", "Engineering Manager", "Director of Engineering", "VP Engineering", "CTO", "Engineering Intern"}
+	companies  = []string{"Acme Inc.", "Globex Corp.", "Initech", "Umbrella GmbH", "Hooli", "Vandelay Industries", "Stark Ltd.", "Wayne Enterprises"}
+)
+
+// seedProfiles stores n synthetic discovered profiles in a single write
+func seedProfiles(db *storage.Storage, n int, seed int64, at time.Time) error {
+	rng := rand.New(rand.NewSource(seed))
+	return db.Transaction(func(tx *storage.Tx) error {
+		for i := 0; i < n; i++ {
+			tx.SaveProfile(&storage.Profile{
+				ID:           fmt.Sprintf("bench-%06d", i),
+				Name:         firstNames[rng.Intn(len(firstNames))] + " " + lastNames[rng.Intn(len(lastNames))],
+				Title:        titles[rng.Intn(len(titles))],
+				Company:      companies[rng.Intn(len(companies))],
+				ProfileURL:   fmt.Sprintf("https://www.linkedin.com/in/bench-%06d", i),
+				State:        storage.StateDiscovered,
+				DiscoveredAt: at,
+				SearchQuery:  "bench",
+			})
+		}
+		return nil
+	})
+}
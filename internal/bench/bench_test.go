@@ -0,0 +1,79 @@
+package bench
+
+import (
+	"testing"
+	"time"
+
+	"subspace/internal/config"
+	"subspace/internal/storage"
+)
+
+// TestPipelineProperties runs the whole pipeline under the fake clock with
+// different seeds and checks the invariants that must hold whatever the
+// acceptance model decides: limits hold in every sliding window and the
+// funnel only contains profiles that moved through legal states
+func TestPipelineProperties(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Simulation.AcceptanceModel = "seniority"
+
+	for _, seed := range []int64{1, 7, 42} {
+		report, err := Run(cfg, Options{
+			Profiles: 120,
+			Days:     3,
+			Interval: 20 * time.Minute,
+			Seed:     seed,
+			DataDir:  t.TempDir(),
+		})
+		if err != nil {
+			t.Fatalf("seed %d: %v", seed, err)
+		}
+		if !report.LimiterOK() {
+			t.Fatalf("seed %d: %d limit violations, first: %+v", seed, len(report.Violations), report.Violations[0])
+		}
+		if report.Actions["connection"] == 0 {
+			t.Fatalf("seed %d: pipeline sent no connection requests", seed)
+		}
+
+		total := 0
+		for state, n := range report.Funnel {
+			switch storage.ProfileState(state) {
+			case storage.StateDiscovered, storage.StateRequested, storage.StateAccepted, storage.StateCooledDown, storage.StateRejected:
+			default:
+				t.Fatalf("seed %d: unknown state %q in funnel", seed, state)
+			}
+			total += n
+		}
+		if total < report.Profiles {
+			t.Fatalf("seed %d: funnel lost profiles: %d of %d", seed, total, report.Profiles)
+		}
+		// Every requested, accepted or cooled down profile was counted as a connection
+		moved := report.Funnel[string(storage.StateRequested)] + report.Funnel[string(storage.StateAccepted)] + report.Funnel[string(storage.StateCooledDown)]
+		if moved > report.Actions["connection"] {
+			t.Fatalf("seed %d: %d profiles past discovered but only %d connections logged", seed, moved, report.Actions["connection"])
+		}
+	}
+}
+
+// TestPartialPipeline runs discovery only: the disabled modules take no
+// actions and the discovered profiles stay where search left them
+func TestPartialPipeline(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Modules = config.ModulesConfig{Search: true}
+	cfg.Stealth.BusinessHoursEnabled = false // Whatever day the bench starts on
+
+	report, err := Run(cfg, Options{Profiles: 40, Days: 1, Interval: 30 * time.Minute, Seed: 1, DataDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Actions["search"] == 0 {
+		t.Error("no searches with search enabled")
+	}
+	if n := report.Actions["connection"] + report.Actions["message"]; n != 0 {
+		t.Errorf("%d connections and messages with connect and messaging disabled", n)
+	}
+	for state := range report.Funnel {
+		if storage.ProfileState(state) != storage.StateDiscovered {
+			t.Errorf("profiles in %s with only search enabled", state)
+		}
+	}
+}
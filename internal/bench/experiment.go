@@ -0,0 +1,108 @@
+package bench
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"subspace/internal/config"
+	"subspace/internal/storage"
+)
+
+// ExperimentReport compares the arms of a pacing experiment
+type ExperimentReport struct {
+	Name     string      `json:"name"`
+	Profiles int         `json:"profiles"`
+	Days     int         `json:"days"`
+	Seeds    []int64     `json:"seeds"`
+	Arms     []ArmResult `json:"arms"`
+}
+
+// ArmResult holds one arm's outcome metrics, averaged over the seeds
+type ArmResult struct {
+	Arm            string  `json:"arm"`
+	Connections    float64 `json:"connections"`
+	Accepted       float64 `json:"accepted"`
+	AcceptanceRate float64 `json:"acceptance_rate"` // Accepted / connections
+	Messages       float64 `json:"messages"`
+	Pacing         Pacing  `json:"pacing"`
+	Violations     int     `json:"violations"` // Summed over all seeds
+}
+
+// RunExperiment runs the simulated pipeline once per arm and seed. Every
+// arm sees the same seeds, so arms differ only in their pacing settings.
+// Runs write throwaway storage files under dataDir.
+func RunExperiment(cfg *config.Config, name string, exp config.Experiment, seed int64, dataDir string) (*ExperimentReport, error) {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	report := &ExperimentReport{Name: name, Profiles: exp.Profiles, Days: exp.Days}
+	for i := 0; i < exp.Runs; i++ {
+		report.Seeds = append(report.Seeds, seed+int64(i))
+	}
+
+	for _, arm := range exp.Arms {
+		armCfg, err := arm.Apply(cfg)
+		if err != nil {
+			return nil, err
+		}
+		result := ArmResult{Arm: arm.Name}
+		var pacings []Pacing
+		for _, s := range report.Seeds {
+			dir, err := os.MkdirTemp(dataDir, "arm-")
+			if err != nil {
+				return nil, fmt.Errorf("failed to create run directory: %w", err)
+			}
+			r, err := Run(armCfg, Options{
+				Profiles: exp.Profiles,
+				Days:     exp.Days,
+				Interval: time.Duration(exp.IntervalMinutes) * time.Minute,
+				Seed:     s,
+				DataDir:  dir,
+			})
+			os.RemoveAll(dir)
+			if err != nil {
+				return nil, fmt.Errorf("arm %s, seed %d: %w", arm.Name, s, err)
+			}
+			result.Connections += float64(r.Actions["connection"])
+			result.Accepted += float64(r.Funnel[string(storage.StateAccepted)] + r.Funnel[string(storage.StateCooledDown)])
+			result.Messages += float64(r.Actions["message"])
+			result.Violations += len(r.Violations)
+			pacings = append(pacings, r.Pacing)
+		}
+
+		runs := float64(len(report.Seeds))
+		result.Connections /= runs
+		result.Accepted /= runs
+		result.Messages /= runs
+		if result.Connections > 0 {
+			result.AcceptanceRate = result.Accepted / result.Connections
+		}
+		result.Pacing = meanPacing(pacings)
+		report.Arms = append(report.Arms, result)
+	}
+	return report, nil
+}
+
+// meanPacing averages pacing measurements
+func meanPacing(ps []Pacing) Pacing {
+	var m Pacing
+	if len(ps) == 0 {
+		return m
+	}
+	var peak, gap float64
+	for _, p := range ps {
+		peak += float64(p.PeakHour)
+		gap += float64(p.MedianGap)
+		m.GapCV += p.GapCV
+		m.DailyCV += p.DailyCV
+		m.ActiveHours += p.ActiveHours
+	}
+	n := float64(len(ps))
+	m.PeakHour = int(peak/n + 0.5)
+	m.MedianGap = time.Duration(gap / n)
+	m.GapCV /= n
+	m.DailyCV /= n
+	m.ActiveHours /= n
+	return m
+}
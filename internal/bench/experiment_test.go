@@ -0,0 +1,92 @@
+package bench
+
+import (
+	"testing"
+	"time"
+
+	"subspace/internal/config"
+	"subspace/internal/storage"
+)
+
+func TestMeasurePacing(t *testing.T) {
+	day := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+	var logs []storage.ActionLog
+	for _, offset := range []time.Duration{0, 10 * time.Minute, 20 * time.Minute, 30 * time.Minute, 3 * time.Hour} {
+		logs = append(logs, storage.ActionLog{Timestamp: day.Add(offset), Success: true})
+	}
+	logs = append(logs,
+		storage.ActionLog{Timestamp: day.Add(5 * time.Minute)}, // failed, ignored
+		storage.ActionLog{Timestamp: day.AddDate(0, 0, 1), Success: true},
+	)
+
+	p := MeasurePacing(logs)
+	if p.PeakHour != 4 {
+		t.Errorf("PeakHour = %d, want 4", p.PeakHour)
+	}
+	if p.MedianGap != 10*time.Minute {
+		t.Errorf("MedianGap = %s, want 10m", p.MedianGap)
+	}
+	if p.GapCV <= 0 || p.DailyCV <= 0 {
+		t.Errorf("GapCV = %v, DailyCV = %v, want both positive for uneven pacing", p.GapCV, p.DailyCV)
+	}
+	if p.ActiveHours != 1.5 { // (3h + 0h) / 2 days
+		t.Errorf("ActiveHours = %v, want 1.5", p.ActiveHours)
+	}
+}
+
+func TestRunExperiment(t *testing.T) {
+	cfg, err := config.Parse([]byte(`
+experiments:
+  pacing:
+    profiles: 80
+    days: 3
+    interval_minutes: 30
+    runs: 2
+    arms:
+      - name: baseline
+      - name: slow
+        limits: {connections_per_day: 5, connections_per_hour: 2, connection_cooldown_seconds: 600}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := RunExperiment(cfg, "pacing", cfg.Experiments["pacing"], 3, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Arms) != 2 || len(report.Seeds) != 2 {
+		t.Fatalf("got %d arms and %d seeds, want 2 and 2", len(report.Arms), len(report.Seeds))
+	}
+	baseline, slow := report.Arms[0], report.Arms[1]
+	if slow.Connections > 2*5 || slow.Connections >= baseline.Connections {
+		t.Errorf("slow arm sent %.1f connections, baseline %.1f", slow.Connections, baseline.Connections)
+	}
+	if baseline.Violations+slow.Violations > 0 {
+		t.Errorf("limit violations: %d / %d", baseline.Violations, slow.Violations)
+	}
+	if cfg.Limits.ConnectionsPerDay != config.Defaults().Limits.ConnectionsPerDay {
+		t.Error("an arm's overrides leaked into the base config")
+	}
+
+	if _, err := config.Parse([]byte("experiments:\n  x:\n    profiles: 1\n    days: 1\n    interval_minutes: 60\n    runs: 1\n    arms:\n      - name: a\n      - name: b\n        limits: {connections_per_dya: 5}\n")); err == nil {
+		t.Error("an arm with an unknown limits key was accepted")
+	}
+}
+
+func TestRunChaos(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Simulation.Faults = config.FaultsConfig{ElementNotFound: 0.3, SlowNavigation: 0.5, SlowNavigationSeconds: 30, DroppedCookies: 0.2}
+	r, err := Run(cfg, Options{Profiles: 80, Days: 3, Interval: 30 * time.Minute, Seed: 7, DataDir: t.TempDir(), Chaos: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Faults["element_not_found"] == 0 || r.Faults["slow_navigation"] == 0 || r.SessionsLost == 0 {
+		t.Errorf("faults not injected: %v, %d sessions lost", r.Faults, r.SessionsLost)
+	}
+	if r.Failed["connection"] == 0 || r.Actions["connection"] == 0 {
+		t.Errorf("want both failed and successful connections, got %d and %d", r.Failed["connection"], r.Actions["connection"])
+	}
+	if !r.LimiterOK() {
+		t.Errorf("limits violated under chaos: %+v", r.Violations)
+	}
+}
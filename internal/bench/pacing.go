@@ -0,0 +1,97 @@
+package bench
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"subspace/internal/storage"
+)
+
+// Pacing describes how successful actions were spread over time: the
+// signals a behavioural detector would look at
+type Pacing struct {
+	PeakHour    int           `json:"peak_hour"`    // Most actions in any sliding hour
+	MedianGap   time.Duration `json:"median_gap"`   // Between consecutive actions on the same day
+	GapCV       float64       `json:"gap_cv"`       // Std dev / mean of those gaps; near 0 looks scripted
+	DailyCV     float64       `json:"daily_cv"`     // Std dev / mean of actions per active day
+	ActiveHours float64       `json:"active_hours"` // Mean span from first to last action of a day
+}
+
+// MeasurePacing computes pacing signals from an action log
+func MeasurePacing(logs []storage.ActionLog) Pacing {
+	var times []time.Time
+	for _, l := range logs {
+		if l.Success {
+			times = append(times, l.Timestamp)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	var p Pacing
+	start := 0
+	for end := range times {
+		for times[end].Sub(times[start]) >= time.Hour {
+			start++
+		}
+		if n := end - start + 1; n > p.PeakHour {
+			p.PeakHour = n
+		}
+	}
+
+	var gaps []float64
+	var daily, spans []float64
+	for i := 0; i < len(times); {
+		day := dayOf(times[i])
+		j := i
+		for j < len(times) && dayOf(times[j]).Equal(day) {
+			if j > i {
+				gaps = append(gaps, times[j].Sub(times[j-1]).Seconds())
+			}
+			j++
+		}
+		daily = append(daily, float64(j-i))
+		spans = append(spans, times[j-1].Sub(times[i]).Hours())
+		i = j
+	}
+
+	if len(gaps) > 0 {
+		sorted := append([]float64(nil), gaps...)
+		sort.Float64s(sorted)
+		p.MedianGap = time.Duration(sorted[len(sorted)/2] * float64(time.Second))
+	}
+	p.GapCV = cv(gaps)
+	p.DailyCV = cv(daily)
+	p.ActiveHours = mean(spans)
+	return p
+}
+
+// dayOf truncates a time to its calendar day
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// mean returns the mean of xs, or 0 for none
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// cv returns the coefficient of variation of xs, or 0 when undefined
+func cv(xs []float64) float64 {
+	m := mean(xs)
+	if len(xs) < 2 || m == 0 {
+		return 0
+	}
+	sq := 0.0
+	for _, x := range xs {
+		sq += (x - m) * (x - m)
+	}
+	return math.Sqrt(sq/float64(len(xs))) / m
+}
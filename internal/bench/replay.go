@@ -0,0 +1,111 @@
+package bench
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/connect"
+	"subspace/internal/messaging"
+	"subspace/internal/simulate"
+	"subspace/internal/stealth"
+	"subspace/internal/storage"
+)
+
+// ReplayResult compares one replayed action with its recording
+type ReplayResult struct {
+	Action    string              `json:"action"`
+	ProfileID string              `json:"profile_id"`
+	Stats     stealth.ReplayStats `json:"stats"`
+	Recorded  time.Duration       `json:"recorded"` // Duration of the recorded action
+	Replayed  time.Duration       `json:"replayed"` // Simulated duration of the replay
+	Error     string              `json:"error,omitempty"`
+}
+
+// Identical reports whether the replay took exactly the recorded decisions
+// and time
+func (r *ReplayResult) Identical() bool {
+	return r.Stats.Divergence == nil && r.Recorded == r.Replayed
+}
+
+// Replay re-executes recorded actions through the real connect and
+// messaging code under a fake clock, feeding each one its recorded stealth
+// decisions. Profiles are copied from source when it has them, so messages
+// render as they did; missing ones are replaced by a stub. Each action gets
+// its own throwaway storage file under dataDir. The process-wide clock is
+// restored before returning.
+func Replay(cfg *config.Config, source storage.Backend, traces []stealth.ActionTrace, dataDir string) ([]ReplayResult, error) {
+	defer clock.Set(clock.Real{})
+
+	results := make([]ReplayResult, 0, len(traces))
+	for i := range traces {
+		t := &traces[i]
+		r, err := replayAction(cfg, source, t, filepath.Join(dataDir, fmt.Sprintf("replay-%d.json", i+1)))
+		if err != nil {
+			return nil, fmt.Errorf("action %d (%s %s): %w", i+1, t.Action, t.ProfileID, err)
+		}
+		results = append(results, *r)
+	}
+	return results, nil
+}
+
+// replayAction replays one recorded action against fresh storage at path
+func replayAction(cfg *config.Config, source storage.Backend, t *stealth.ActionTrace, path string) (*ReplayResult, error) {
+	fake := clock.NewFake(t.StartedAt)
+	clock.Set(fake)
+
+	stealthCfg := cfg.Stealth
+	if _, ok := stealthCfg.Personas[t.Persona]; ok {
+		stealthCfg.Persona = t.Persona
+	}
+
+	db, err := storage.New(path, cfg.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replay storage: %w", err)
+	}
+	defer db.Close()
+	profile := &storage.Profile{ID: t.ProfileID, Name: t.ProfileID}
+	if p, err := source.GetProfile(t.ProfileID); err == nil {
+		copied := *p
+		profile = &copied
+	}
+	profile.SnoozedUntil = nil
+
+	s := stealth.New(stealthCfg, nil)
+	result := &ReplayResult{Action: t.Action, ProfileID: t.ProfileID, Recorded: t.EndedAt.Sub(t.StartedAt)}
+
+	var actionErr error
+	switch t.Action {
+	case "connection":
+		profile.State = storage.StateDiscovered
+		if err := db.SaveProfile(profile); err != nil {
+			return nil, err
+		}
+		model, err := simulate.New(cfg.Simulation)
+		if err != nil {
+			return nil, err
+		}
+		connector := connect.New(nil, s, db, cfg.Limits, cfg.Review, cfg.Targeting, model)
+		s.Replay(t)
+		actionErr = connector.SendConnectionRequest(profile)
+	case "message":
+		profile.State = storage.StateAccepted
+		if err := db.SaveProfile(profile); err != nil {
+			return nil, err
+		}
+		messenger := messaging.New(nil, s, db, cfg.Limits, cfg.Messaging)
+		s.Replay(t)
+		actionErr = messenger.SendMessage(profile, t.Template)
+	default:
+		return nil, fmt.Errorf("unknown action: %s", t.Action)
+	}
+
+	result.Stats = s.EndReplay()
+	result.Replayed = fake.Now().Sub(t.StartedAt)
+	if actionErr != nil {
+		result.Error = actionErr.Error()
+	}
+	return result, nil
+}
@@ -0,0 +1,81 @@
+package bench
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/connect"
+	"subspace/internal/logger"
+	"subspace/internal/messaging"
+	"subspace/internal/simulate"
+	"subspace/internal/stealth"
+	"subspace/internal/storage"
+)
+
+func TestReplay(t *testing.T) {
+	logger.Init("error")
+	start := time.Date(2024, 6, 10, 10, 0, 0, 0, time.UTC)
+	clock.Set(clock.NewFake(start))
+	defer clock.Set(clock.Real{})
+
+	cfg := config.Defaults()
+	cfg.Stealth.TypoChance = 0.2
+	dir := t.TempDir()
+	db, err := storage.New(filepath.Join(dir, "db.json"), cfg.Storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ada := &storage.Profile{ID: "ada", Name: "Ada Lovelace", State: storage.StateDiscovered, DiscoveredAt: start.Add(-time.Hour)}
+	if err := db.SaveProfile(ada); err != nil {
+		t.Fatal(err)
+	}
+
+	// Record a connection request, then a message once it's accepted
+	s := stealth.New(cfg.Stealth, nil)
+	rec := stealth.NewRecorder(dir)
+	s.SetRecorder(rec)
+	model, _ := simulate.New(cfg.Simulation)
+	if err := connect.New(nil, s, db, cfg.Limits, cfg.Review, cfg.Targeting, model).SendConnectionRequest(ada); err != nil {
+		t.Fatal(err)
+	}
+	if err := ada.Transition(storage.StateAccepted, clock.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := messaging.New(nil, s, db, cfg.Limits, cfg.Messaging).SendMessage(ada, "follow_up"); err != nil {
+		t.Fatal(err)
+	}
+
+	traces, err := stealth.ReadTraces(rec.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(traces) != 2 {
+		t.Fatalf("recorded %d actions, want 2", len(traces))
+	}
+
+	results, err := Replay(cfg, db, traces, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if !r.Identical() || r.Error != "" {
+			t.Errorf("%s replay differs: %+v", r.Action, r)
+		}
+	}
+
+	// Without typos the message asks for different decisions
+	cfg.Stealth.TypoChance = 0
+	results, err = Replay(cfg, db, traces, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Stats.Divergence != nil {
+		t.Errorf("connection diverged without typing: %+v", results[0].Stats.Divergence)
+	}
+	if d := results[1].Stats.Divergence; d == nil || d.Expected != "typo_chance" {
+		t.Errorf("message divergence = %+v, want a missing typo_chance", d)
+	}
+}
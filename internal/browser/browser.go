@@ -2,6 +2,8 @@ package browser
 
 import (
 	"fmt"
+	"net/url"
+	"os"
 	"time"
 
 	"github.com/go-rod/rod"
@@ -16,59 +18,205 @@ import (
 // Browser wraps Rod browser functionality with a clean interface
 // This abstraction prevents business logic from directly calling Rod APIs
 type Browser struct {
-	browser *rod.Browser
-	Page    *rod.Page
-	config  config.AppConfig
-	log     *logger.ContextLogger
+	browser  *rod.Browser
+	launcher *launcher.Launcher
+	Page     *rod.Page
+	config   config.AppConfig
+	log      *logger.ContextLogger
+	onBytes  func(bytes int64) // Bandwidth meter, see OnBytes
+	parent   *Browser          // Chrome this is a context of, see NewContext
+	Observers
 }
 
+// aliveTimeout bounds the health check, as a hung Chrome never answers
+const aliveTimeout = 5 * time.Second
+
 // New creates a new browser instance with stealth configuration
 func New(cfg config.AppConfig) (*Browser, error) {
 	log := logger.NewContext("browser")
 	
 	log.Info("Initializing browser", "headless", cfg.Headless)
 	
+	launched, browser, page, err := launch(cfg, log)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Browser{
+		browser:  browser,
+		launcher: launched,
+		Page:     page,
+		config:   cfg,
+		log:      log,
+	}
+	b.watchPage()
+
+	log.Info("Browser initialized successfully")
+	return b, nil
+}
+
+// launch starts Chrome and opens a stealth page in it
+func launch(cfg config.AppConfig, log *logger.ContextLogger) (*launcher.Launcher, *rod.Browser, *rod.Page, error) {
 	// Launch browser with configured options
 	l := launcher.New().
 		Headless(cfg.Headless).
 		UserDataDir("") // Don't persist user data by default
 
+	// Chrome's sandbox can't start as root, and /dev/shm is 64MB in Docker
+	if cfg.Container {
+		l = l.Set("disable-dev-shm-usage").NoSandbox(os.Geteuid() == 0)
+	}
+
+	// Route through the account's proxy; credentials are answered below
+	// since Chrome doesn't take them on the command line
+	var proxy *url.URL
+	if cfg.Proxy != "" {
+		proxy, _ = url.Parse(cfg.Proxy) // Validated with the config
+		l = l.Proxy(proxy.Scheme + "://" + proxy.Host)
+	}
+	resolveDNS(l, cfg.DNS, proxy)
+
 	// Start the launcher
 	url, err := l.Launch()
 	if err != nil {
-		return nil, fmt.Errorf("failed to launch browser: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to launch browser: %w", err)
 	}
 
 	// Connect to browser
 	browser := rod.New().ControlURL(url)
 	if err := browser.Connect(); err != nil {
-		return nil, fmt.Errorf("failed to connect to browser: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to connect to browser: %w", err)
+	}
+	if proxy != nil && proxy.User != nil {
+		password, _ := proxy.User.Password()
+		go answerProxyAuth(browser, proxy.User.Username(), password)
 	}
 
 	// Create a new page
 	page, err := stealth.Page(browser)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create page: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to create page: %w", err)
 	}
+	preparePage(page, cfg, log)
+
+	return l, browser, page, nil
+}
 
-	// Set user agent
-	if cfg.UserAgent != "" {
-		if err := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{
-			UserAgent: cfg.UserAgent,
-		}); err != nil {
-			log.Warn("Failed to set user agent", "error", err)
+// preparePage presents the account's user agent, time zone and locale on a
+// new page
+func preparePage(page *rod.Page, cfg config.AppConfig, log *logger.ContextLogger) {
+	// Set user agent, with the client hints and headers to match
+	if err := applyHeaders(page, cfg); err != nil {
+		log.Warn("Failed to set request headers", "error", err)
+	}
+
+	// Present the account's time zone and locale
+	if cfg.TimeZone != "" {
+		if err := (proto.EmulationSetTimezoneOverride{TimezoneID: cfg.TimeZone}).Call(page); err != nil {
+			log.Warn("Failed to set time zone", "error", err)
+		}
+	}
+	if cfg.Locale != "" {
+		if err := (proto.EmulationSetLocaleOverride{Locale: cfg.Locale}).Call(page); err != nil {
+			log.Warn("Failed to set locale", "error", err)
 		}
 	}
+}
 
-	b := &Browser{
-		browser: browser,
-		Page:    page,
-		config:  cfg,
-		log:     log,
+// answerProxyAuth answers the proxy's authentication challenges until the
+// browser goes away
+func answerProxyAuth(browser *rod.Browser, username, password string) {
+	for {
+		if err := browser.HandleAuth(username, password)(); err != nil {
+			return
+		}
 	}
+}
 
-	log.Info("Browser initialized successfully")
-	return b, nil
+// Alive reports whether Chrome still answers over CDP
+func (b *Browser) Alive() bool {
+	_, err := b.browser.Timeout(aliveTimeout).Version()
+	return err == nil
+}
+
+// OnBytes calls fn with the bytes received for every network request the
+// page completes, as transferred on the wire. It keeps metering after a
+// Relaunch.
+func (b *Browser) OnBytes(fn func(bytes int64)) {
+	b.onBytes = fn
+	b.meterPage()
+}
+
+// meterPage feeds the current page's network traffic to the meter
+func (b *Browser) meterPage() {
+	if b.onBytes == nil {
+		return
+	}
+	fn := b.onBytes
+	go b.Page.EachEvent(func(e *proto.NetworkLoadingFinished) {
+		fn(int64(e.EncodedDataLength))
+	})()
+}
+
+// SetProxy changes the proxy Chrome is launched with; it takes effect at
+// the next Relaunch
+func (b *Browser) SetProxy(proxy string) {
+	b.config.Proxy = proxy
+}
+
+// PID returns the Chrome process ID, or 0 if it is unknown
+func (b *Browser) PID() int {
+	if b.parent != nil {
+		return b.parent.PID()
+	}
+	if b.launcher == nil {
+		return 0
+	}
+	return b.launcher.PID()
+}
+
+// PageCount returns the number of pages open in Chrome
+func (b *Browser) PageCount() (int, error) {
+	pages, err := b.browser.Pages()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list pages: %w", err)
+	}
+	return len(pages), nil
+}
+
+// Relaunch replaces a crashed or disconnected Chrome with a fresh one and
+// restores the given session cookies. Whatever is left of the old process
+// is killed.
+func (b *Browser) Relaunch(cookies []*proto.NetworkCookie) error {
+	if b.parent != nil {
+		return b.reopen(cookies)
+	}
+	b.log.Warn("Relaunching browser")
+	start := time.Now()
+
+	b.browser.Close()
+	if b.launcher != nil {
+		b.launcher.Kill()
+	}
+
+	launched, browser, page, err := launch(b.config, b.log)
+	if err != nil {
+		logger.Timing("browser", "relaunch", start, err)
+		return err
+	}
+	b.launcher, b.browser, b.Page = launched, browser, page
+	b.meterPage()
+	b.watchPage()
+
+	if len(cookies) > 0 {
+		if err := b.SetCookies(cookies); err != nil {
+			logger.Timing("browser", "relaunch", start, err)
+			return fmt.Errorf("failed to restore session: %w", err)
+		}
+	}
+	logger.Timing("browser", "relaunch", start, nil)
+	b.log.Info("Browser relaunched", "cookies", len(cookies))
+	return nil
 }
 
 // Navigate navigates to a URL with error handling
@@ -0,0 +1,169 @@
+package browser
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/logger"
+)
+
+/*
+CHAOS MODE
+
+Wraps a simulated browser and injects the failures a real session runs
+into, so the pipeline's handling of them can be exercised in simulation:
+- element_not_found: an element lookup, click or typing target is missing
+- slow_navigation:   a page takes slow_navigation_seconds longer to load
+- dropped_cookies:   the session cookies are gone at a session check or
+                     cookie read, as after a forced logout
+
+Each fault fires independently with its configured probability. Only
+simulated browsers can be wrapped; faults are never injected into a real
+one.
+*/
+
+// Fault names, as counted by Injected
+const (
+	FaultElementNotFound = "element_not_found"
+	FaultSlowNavigation  = "slow_navigation"
+	FaultDroppedCookies  = "dropped_cookies"
+)
+
+// ErrElementNotFound is returned for elements the chaos mode hid
+var ErrElementNotFound = errors.New("element not found (injected)")
+
+// Chaos is a simulated browser that injects faults
+type Chaos struct {
+	Simulation
+	cfg config.FaultsConfig
+	log *logger.ContextLogger
+
+	mu       sync.Mutex
+	rng      *rand.Rand
+	injected map[string]int
+}
+
+// NewChaos wraps a simulated browser with fault injection. The seed makes
+// the faults reproducible; 0 picks a random one.
+func NewChaos(inner Simulation, cfg config.FaultsConfig, seed int64) *Chaos {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Chaos{
+		Simulation: inner,
+		cfg:        cfg,
+		log:        logger.NewContext("chaos"),
+		rng:        rand.New(rand.NewSource(seed)),
+		injected:   make(map[string]int),
+	}
+}
+
+// Injected returns how often each fault has fired
+func (c *Chaos) Injected() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make(map[string]int, len(c.injected))
+	for k, v := range c.injected {
+		out[k] = v
+	}
+	return out
+}
+
+// inject rolls for a fault and counts it when it fires
+func (c *Chaos) inject(fault string, probability float64) bool {
+	if probability <= 0 {
+		return false
+	}
+	c.mu.Lock()
+	fire := c.rng.Float64() < probability
+	if fire {
+		c.injected[fault]++
+	}
+	c.mu.Unlock()
+	if fire {
+		c.log.Debug("Injecting fault", "fault", fault)
+	}
+	return fire
+}
+
+// missing reports whether to hide the element being looked up
+func (c *Chaos) missing() bool {
+	return c.inject(FaultElementNotFound, c.cfg.ElementNotFound)
+}
+
+// dropCookies clears the session if the dropped cookies fault fires
+func (c *Chaos) dropCookies() {
+	if c.inject(FaultDroppedCookies, c.cfg.DroppedCookies) {
+		c.Simulation.SetCookies(nil)
+	}
+}
+
+func (c *Chaos) Navigate(url string) error {
+	if c.inject(FaultSlowNavigation, c.cfg.SlowNavigation) {
+		clock.Sleep(time.Duration(c.cfg.SlowNavigationSeconds) * time.Second)
+	}
+	return c.Simulation.Navigate(url)
+}
+
+func (c *Chaos) WaitForElement(selector string, timeout time.Duration) error {
+	if c.missing() {
+		clock.Sleep(timeout)
+		return ErrElementNotFound
+	}
+	return c.Simulation.WaitForElement(selector, timeout)
+}
+
+func (c *Chaos) Click(selector string) error {
+	if c.missing() {
+		return ErrElementNotFound
+	}
+	return c.Simulation.Click(selector)
+}
+
+func (c *Chaos) Type(selector, text string) error {
+	if c.missing() {
+		return ErrElementNotFound
+	}
+	return c.Simulation.Type(selector, text)
+}
+
+func (c *Chaos) GetText(selector string) (string, error) {
+	if c.missing() {
+		return "", ErrElementNotFound
+	}
+	return c.Simulation.GetText(selector)
+}
+
+func (c *Chaos) GetAttribute(selector, attribute string) (string, error) {
+	if c.missing() {
+		return "", ErrElementNotFound
+	}
+	return c.Simulation.GetAttribute(selector, attribute)
+}
+
+func (c *Chaos) IsElementPresent(selector string) bool {
+	return !c.missing() && c.Simulation.IsElementPresent(selector)
+}
+
+func (c *Chaos) WaitVisible(selector string) error {
+	if c.missing() {
+		return ErrElementNotFound
+	}
+	return c.Simulation.WaitVisible(selector)
+}
+
+func (c *Chaos) GetCookies() ([]*proto.NetworkCookie, error) {
+	c.dropCookies()
+	return c.Simulation.GetCookies()
+}
+
+func (c *Chaos) HasValidSession() bool {
+	c.dropCookies()
+	return c.Simulation.HasValidSession()
+}
@@ -0,0 +1,104 @@
+package browser
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/stealth"
+
+	"subspace/internal/config"
+	"subspace/internal/logger"
+)
+
+// NewContext opens an isolated, incognito-style browser context in this
+// Chrome for another account. The context has its own cookies, cache and
+// storage, and its own proxy, user agent, time zone and locale. It shares
+// only the Chrome process, so accounts don't each need a Chrome. Closing
+// the context leaves Chrome running.
+//
+// DNS flags and proxy credentials apply to the whole Chrome, so an account
+// that needs other ones needs its own browser.
+func (b *Browser) NewContext(cfg config.AppConfig) (*Browser, error) {
+	if b.parent != nil {
+		return b.parent.NewContext(cfg)
+	}
+	if cfg.DNS != b.config.DNS || (cfg.DNS.Mode == "proxy" && config.ProxyHost(cfg.Proxy) != config.ProxyHost(b.config.Proxy)) {
+		return nil, fmt.Errorf("account %s resolves names differently, which needs its own browser", cfg.Account)
+	}
+	if proxyUser(cfg.Proxy) != proxyUser(b.config.Proxy) {
+		return nil, fmt.Errorf("account %s has other proxy credentials, which need their own browser", cfg.Account)
+	}
+
+	log := logger.NewContext("browser", "account", cfg.Account)
+	browser, page, err := openContext(b.browser, cfg, log)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Browser context opened")
+	ctx := &Browser{browser: browser, Page: page, config: cfg, log: log, parent: b}
+	ctx.watchPage()
+	return ctx, nil
+}
+
+// openContext creates a browser context on the account's proxy with a
+// stealth page in it
+func openContext(root *rod.Browser, cfg config.AppConfig, log *logger.ContextLogger) (*rod.Browser, *rod.Page, error) {
+	// Without a proxy of its own the context would use Chrome's
+	req := proto.TargetCreateBrowserContext{DisposeOnDetach: true, ProxyServer: "direct://"}
+	if cfg.Proxy != "" {
+		proxy, _ := url.Parse(cfg.Proxy) // Validated with the config
+		req.ProxyServer = proxy.Scheme + "://" + proxy.Host
+	}
+	res, err := req.Call(root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create browser context: %w", err)
+	}
+	browser := *root
+	browser.BrowserContextID = res.BrowserContextID
+
+	page, err := stealth.Page(&browser)
+	if err != nil {
+		browser.Close()
+		return nil, nil, fmt.Errorf("failed to create page: %w", err)
+	}
+	preparePage(page, cfg, log)
+	return &browser, page, nil
+}
+
+// reopen replaces a context lost with its Chrome, or a context of a
+// relaunched Chrome, and restores the given session cookies
+func (b *Browser) reopen(cookies []*proto.NetworkCookie) error {
+	b.log.Warn("Reopening browser context")
+	start := time.Now()
+
+	b.browser.Close()
+	browser, page, err := openContext(b.parent.browser, b.config, b.log)
+	if err != nil {
+		logger.Timing("browser", "reopen", start, err)
+		return err
+	}
+	b.browser, b.Page = browser, page
+	b.meterPage()
+	b.watchPage()
+
+	if len(cookies) > 0 {
+		if err := b.SetCookies(cookies); err != nil {
+			logger.Timing("browser", "reopen", start, err)
+			return fmt.Errorf("failed to restore session: %w", err)
+		}
+	}
+	logger.Timing("browser", "reopen", start, nil)
+	return nil
+}
+
+// proxyUser returns the credentials of a proxy URL, empty if it has none
+func proxyUser(proxy string) string {
+	u, err := url.Parse(proxy)
+	if err != nil || u.User == nil {
+		return ""
+	}
+	return u.User.String()
+}
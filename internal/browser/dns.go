@@ -0,0 +1,26 @@
+package browser
+
+import (
+	"net/url"
+
+	"github.com/go-rod/rod/lib/launcher"
+
+	"subspace/internal/config"
+)
+
+// resolveDNS sets Chrome's flags for a DNS mode. With "doh" the secure
+// DNS feature is forced on with the configured server and no fallback to
+// the OS resolver; with "proxy" every host name maps to NOTFOUND locally,
+// so only the proxy (which is exempt) ever resolves names.
+func resolveDNS(l *launcher.Launcher, dns config.DNSConfig, proxy *url.URL) {
+	switch dns.Mode {
+	case "doh":
+		l.Append("enable-features", "DnsOverHttps<DoHTrial")
+		l.Set("force-fieldtrials", "DoHTrial/Group1")
+		l.Set("force-fieldtrial-params", "DoHTrial.Group1:Fallback/false/Templates/"+url.QueryEscape(dns.DoHURL))
+	case "proxy":
+		if proxy != nil {
+			l.Set("host-resolver-rules", "MAP * ~NOTFOUND , EXCLUDE "+proxy.Hostname())
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package browser
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+
+	"subspace/internal/config"
+)
+
+// chromeVersion finds the brand and version of a Chromium-based user agent;
+// Edge names itself after Chrome
+var chromeVersion = regexp.MustCompile(`(Edg|Chrome)/((\d+)[\d.]*)`)
+
+// clientHints is what a Chromium browser tells sites about itself beyond
+// the user agent: sec-ch-ua headers and navigator.userAgentData
+type clientHints struct {
+	brand    string // Google Chrome or Microsoft Edge
+	major    string
+	full     string
+	platform string
+	mobile   bool
+}
+
+// parseClientHints derives the client hints from a user agent. Firefox and
+// Safari don't send client hints, so it reports false for them.
+func parseClientHints(userAgent string) (clientHints, bool) {
+	var h clientHints
+	var version []string
+	for _, m := range chromeVersion.FindAllStringSubmatch(userAgent, -1) {
+		if version == nil || m[1] == "Edg" {
+			version = m
+		}
+	}
+	if version == nil || strings.Contains(userAgent, "Firefox/") {
+		return h, false
+	}
+	h.brand = "Google Chrome"
+	if version[1] == "Edg" {
+		h.brand = "Microsoft Edge"
+	}
+	h.full, h.major = version[2], version[3]
+	h.mobile = strings.Contains(userAgent, "Mobile")
+
+	switch {
+	case strings.Contains(userAgent, "Android"):
+		h.platform = "Android"
+	case strings.Contains(userAgent, "Windows"):
+		h.platform = "Windows"
+	case strings.Contains(userAgent, "Mac OS X"):
+		h.platform = "macOS"
+	case strings.Contains(userAgent, "CrOS"):
+		h.platform = "Chrome OS"
+	default:
+		h.platform = "Linux"
+	}
+	return h, true
+}
+
+// brands lists the brands in Chrome's order, with the GREASE brand first
+func (h clientHints) brands(version string) []*proto.EmulationUserAgentBrandVersion {
+	return []*proto.EmulationUserAgentBrandVersion{
+		{Brand: "Not_A Brand", Version: "8"},
+		{Brand: "Chromium", Version: version},
+		{Brand: h.brand, Version: version},
+	}
+}
+
+// metadata returns the hints for the user agent override, which Chrome
+// reports in navigator.userAgentData and its own sec-ch-ua headers
+func (h clientHints) metadata() *proto.EmulationUserAgentMetadata {
+	return &proto.EmulationUserAgentMetadata{
+		Brands:          h.brands(h.major),
+		FullVersionList: h.brands(h.full),
+		FullVersion:     h.full,
+		Platform:        h.platform,
+		Mobile:          h.mobile,
+	}
+}
+
+// acceptLanguage returns the Accept-Language Chrome sends for a locale,
+// e.g. "de-DE,de;q=0.9" for de-DE
+func acceptLanguage(locale string) string {
+	if lang, _, ok := strings.Cut(locale, "-"); ok {
+		return locale + "," + lang + ";q=0.9"
+	}
+	return locale
+}
+
+// FingerprintHeaders returns the request headers that go with a user agent
+// and locale: Accept-Language, and the sec-ch-ua client hints of Chromium
+// user agents. An empty user agent or locale is Chrome's own and adds
+// nothing.
+func FingerprintHeaders(userAgent, locale string) map[string]string {
+	headers := map[string]string{}
+	if locale != "" {
+		headers["Accept-Language"] = acceptLanguage(locale)
+	}
+	if h, ok := parseClientHints(userAgent); ok {
+		var brands []string
+		for _, b := range h.brands(h.major) {
+			brands = append(brands, fmt.Sprintf("%q;v=%q", b.Brand, b.Version))
+		}
+		headers["sec-ch-ua"] = strings.Join(brands, ", ")
+		headers["sec-ch-ua-mobile"] = "?0"
+		if h.mobile {
+			headers["sec-ch-ua-mobile"] = "?1"
+		}
+		headers["sec-ch-ua-platform"] = fmt.Sprintf("%q", h.platform)
+	}
+	return headers
+}
+
+// applyHeaders makes every request of the page present the configured user
+// agent with matching client hints and Accept-Language, plus the extra
+// headers
+func applyHeaders(page *rod.Page, cfg config.AppConfig) error {
+	if cfg.UserAgent != "" {
+		override := &proto.NetworkSetUserAgentOverride{UserAgent: cfg.UserAgent}
+		if cfg.Locale != "" {
+			override.AcceptLanguage = acceptLanguage(cfg.Locale)
+		}
+		if h, ok := parseClientHints(cfg.UserAgent); ok {
+			override.UserAgentMetadata = h.metadata()
+		}
+		if err := page.SetUserAgent(override); err != nil {
+			return fmt.Errorf("failed to set user agent: %w", err)
+		}
+	}
+
+	headers := FingerprintHeaders(cfg.UserAgent, cfg.Locale)
+	for name, value := range cfg.Headers {
+		headers[name] = value
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+	dict := make([]string, 0, 2*len(headers))
+	for name, value := range headers {
+		dict = append(dict, name, value)
+	}
+	if _, err := page.SetExtraHeaders(dict); err != nil {
+		return fmt.Errorf("failed to set request headers: %w", err)
+	}
+	return nil
+}
+
+// SetHeaders replaces the extra headers sent with every request, on top of
+// those that follow the fingerprint. They survive relaunches.
+func (b *Browser) SetHeaders(headers map[string]string) error {
+	for name := range headers {
+		if config.FingerprintHeader(name) {
+			return fmt.Errorf("header %s follows user_agent and locale and can't be set", name)
+		}
+	}
+	b.config.Headers = headers
+	return applyHeaders(b.Page, b.config)
+}
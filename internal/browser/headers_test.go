@@ -0,0 +1,52 @@
+package browser
+
+import "testing"
+
+func TestFingerprintHeaders(t *testing.T) {
+	mac := "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	got := FingerprintHeaders(mac, "de-DE")
+	want := map[string]string{
+		"Accept-Language":    "de-DE,de;q=0.9",
+		"sec-ch-ua":          `"Not_A Brand";v="8", "Chromium";v="120", "Google Chrome";v="120"`,
+		"sec-ch-ua-mobile":   "?0",
+		"sec-ch-ua-platform": `"macOS"`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s = %s, want %s", name, got[name], value)
+		}
+	}
+
+	edge := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/121.0.0.0 Safari/537.36 Edg/121.0.2277.83"
+	got = FingerprintHeaders(edge, "")
+	if got["sec-ch-ua"] != `"Not_A Brand";v="8", "Chromium";v="121", "Microsoft Edge";v="121"` || got["sec-ch-ua-platform"] != `"Windows"` {
+		t.Errorf("Edge headers = %v", got)
+	}
+	if _, ok := got["Accept-Language"]; ok {
+		t.Error("Accept-Language set without a locale")
+	}
+
+	android := "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36"
+	if got := FingerprintHeaders(android, "en"); got["sec-ch-ua-mobile"] != "?1" || got["sec-ch-ua-platform"] != `"Android"` || got["Accept-Language"] != "en" {
+		t.Errorf("Android headers = %v", got)
+	}
+
+	// Firefox sends no client hints
+	firefox := "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0"
+	if got := FingerprintHeaders(firefox, "en-GB"); len(got) != 1 {
+		t.Errorf("Firefox headers = %v, want Accept-Language only", got)
+	}
+}
+
+func TestSimSetHeaders(t *testing.T) {
+	s := NewSim()
+	if err := s.SetHeaders(map[string]string{"X-Requested-With": "XMLHttpRequest"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetHeaders(map[string]string{"Sec-CH-UA-Platform": `"Linux"`}); err == nil {
+		t.Error("a client hint header was accepted")
+	}
+}
@@ -25,11 +25,20 @@ type Controller interface {
 	// Session Management
 	GetCookies() ([]*proto.NetworkCookie, error)
 	SetCookies(cookies []*proto.NetworkCookie) error
+	GetWebStorage() (WebStorage, error)
+	SetWebStorage(ws WebStorage) error
 	HasValidSession() bool
 	
 	// Utilities
 	Screenshot(path string) error
 	ExecuteScript(script string) (interface{}, error)
+	SetHeaders(headers map[string]string) error
+	
+	// Page Lifecycle, see observe.go
+	OnNavigated(fn func(url string))
+	OnDialog(fn func(Dialog))
+	OnDownload(fn func(Download))
+	OnChallengePage(fn func(url string))
 	
 	// Lifecycle
 	Close() error
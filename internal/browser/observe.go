@@ -0,0 +1,162 @@
+package browser
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+/*
+PAGE LIFECYCLE EVENTS
+
+Modules subscribe to what happens to the page instead of checking for it
+after every step:
+- OnNavigated:     the page committed a new URL, including redirects
+- OnDialog:        a JavaScript alert, confirm, prompt or beforeunload opened
+- OnDownload:      the page started a download
+- OnChallengePage: a navigation landed on a security challenge (checkpoint,
+                   CAPTCHA or auth wall)
+
+The browser handles dialogs and downloads itself, so a subscriber only has
+to observe them: dialogs are dismissed (a beforeunload is accepted, so
+navigation goes on) and downloads are denied. Handlers run on the event
+goroutine and must not block.
+*/
+
+// Dialog is a JavaScript dialog the page opened
+type Dialog struct {
+	Type    string // alert, confirm, prompt or beforeunload
+	Message string
+	URL     string
+}
+
+// Download is a download the page started
+type Download struct {
+	URL      string
+	Filename string // Suggested by the site
+}
+
+// challengePaths mark the pages a site shows instead of the requested one
+// when it wants proof of a human
+var challengePaths = []string{"/checkpoint/", "/challenge", "/authwall", "/captcha"}
+
+// IsChallengePage reports whether a URL is a security challenge page
+func IsChallengePage(url string) bool {
+	url = strings.ToLower(url)
+	for _, p := range challengePaths {
+		if strings.Contains(url, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Observers holds the page lifecycle subscribers of a controller
+type Observers struct {
+	mu        sync.Mutex
+	navigated []func(url string)
+	dialog    []func(Dialog)
+	download  []func(Download)
+	challenge []func(url string)
+}
+
+// OnNavigated calls fn with every URL the page navigates to
+func (o *Observers) OnNavigated(fn func(url string)) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.navigated = append(o.navigated, fn)
+}
+
+// OnDialog calls fn for every JavaScript dialog, after it was answered
+func (o *Observers) OnDialog(fn func(Dialog)) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.dialog = append(o.dialog, fn)
+}
+
+// OnDownload calls fn for every download the page attempts, after it was
+// denied
+func (o *Observers) OnDownload(fn func(Download)) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.download = append(o.download, fn)
+}
+
+// OnChallengePage calls fn with the URL whenever the page lands on a
+// security challenge
+func (o *Observers) OnChallengePage(fn func(url string)) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.challenge = append(o.challenge, fn)
+}
+
+// emitNavigated notifies the navigation, and the challenge if it is one
+func (o *Observers) emitNavigated(url string) {
+	o.mu.Lock()
+	navigated, challenge := o.navigated, o.challenge
+	o.mu.Unlock()
+	for _, fn := range navigated {
+		fn(url)
+	}
+	if IsChallengePage(url) {
+		for _, fn := range challenge {
+			fn(url)
+		}
+	}
+}
+
+// emitDialog notifies a dialog
+func (o *Observers) emitDialog(d Dialog) {
+	o.mu.Lock()
+	dialog := o.dialog
+	o.mu.Unlock()
+	for _, fn := range dialog {
+		fn(d)
+	}
+}
+
+// emitDownload notifies a download
+func (o *Observers) emitDownload(d Download) {
+	o.mu.Lock()
+	download := o.download
+	o.mu.Unlock()
+	for _, fn := range download {
+		fn(d)
+	}
+}
+
+// watchPage answers the current page's dialogs and downloads and feeds
+// its lifecycle events to the observers
+func (b *Browser) watchPage() {
+	page := b.Page
+	if err := (proto.BrowserSetDownloadBehavior{
+		Behavior:         proto.BrowserSetDownloadBehaviorBehaviorDeny,
+		BrowserContextID: b.browser.BrowserContextID,
+		EventsEnabled:    true,
+	}).Call(b.browser); err != nil {
+		b.log.Warn("Failed to deny downloads", "error", err)
+	}
+
+	go page.EachEvent(func(e *proto.PageFrameNavigated) {
+		if e.Frame.ParentID == "" {
+			b.emitNavigated(e.Frame.URL)
+		}
+	}, func(e *proto.PageJavascriptDialogOpening) {
+		accept := e.Type == proto.PageDialogTypeBeforeunload
+		if err := (proto.PageHandleJavaScriptDialog{Accept: accept}).Call(page); err != nil {
+			b.log.Warn("Failed to answer dialog", "type", e.Type, "error", err)
+		}
+		b.log.Debug("Dialog answered", "type", e.Type, "accepted", accept)
+		b.emitDialog(Dialog{Type: string(e.Type), Message: e.Message, URL: e.URL})
+	})()
+
+	// Downloads are reported by the browser, for all pages
+	go b.browser.EachEvent(func(e *proto.BrowserDownloadWillBegin) {
+		if e.FrameID != page.FrameID {
+			return
+		}
+		b.log.Debug("Download denied", "url", e.URL)
+		b.emitDownload(Download{URL: e.URL, Filename: e.SuggestedFilename})
+	})()
+}
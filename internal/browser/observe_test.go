@@ -0,0 +1,21 @@
+package browser
+
+import "testing"
+
+func TestObserversChallengePage(t *testing.T) {
+	s := NewSim()
+	var navigated, challenged []string
+	s.OnNavigated(func(url string) { navigated = append(navigated, url) })
+	s.OnChallengePage(func(url string) { challenged = append(challenged, url) })
+
+	s.Navigate("https://www.linkedin.com/in/ada")
+	s.Navigate("https://www.linkedin.com/checkpoint/challenge/AgG1")
+	s.Navigate("https://www.linkedin.com/authwall?trk=public")
+
+	if len(navigated) != 3 {
+		t.Errorf("navigated = %v, want all 3 URLs", navigated)
+	}
+	if len(challenged) != 2 || challenged[0] != "https://www.linkedin.com/checkpoint/challenge/AgG1" {
+		t.Errorf("challenged = %v, want the checkpoint and the auth wall", challenged)
+	}
+}
@@ -0,0 +1,99 @@
+package browser
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"subspace/internal/config"
+)
+
+// Simulation is a Controller standing in for the real site in simulation
+// runs. The mock flows drive simulated controllers only; a real browser is
+// never pointed at the site by them.
+type Simulation interface {
+	Controller
+	Simulated()
+}
+
+// Simulated returns c if it is a simulated controller, for the mock flows
+// to drive. For a real browser or nil it returns a fresh simulated one, so
+// the flows behave as before.
+func Simulated(c Controller) Controller {
+	if s, ok := c.(Simulation); ok {
+		return s
+	}
+	return NewSim()
+}
+
+// Sim is an in-memory simulated browser: pages always load, every element
+// is present and the session cookie stays until it is replaced
+type Sim struct {
+	url     string
+	cookies []*proto.NetworkCookie
+	storage WebStorage
+	headers map[string]string
+	Observers
+}
+
+// NewSim returns a simulated browser with a logged-in session
+func NewSim() *Sim {
+	return &Sim{cookies: []*proto.NetworkCookie{{Name: "session", Value: "simulated"}}}
+}
+
+// Simulated marks Sim as a Simulation
+func (s *Sim) Simulated() {}
+
+func (s *Sim) Navigate(url string) error {
+	s.url = url
+	s.emitNavigated(url)
+	return nil
+}
+
+func (s *Sim) WaitForElement(selector string, timeout time.Duration) error { return nil }
+func (s *Sim) GetCurrentURL() string                                       { return s.url }
+func (s *Sim) Click(selector string) error                                 { return nil }
+func (s *Sim) Type(selector, text string) error                            { return nil }
+func (s *Sim) GetText(selector string) (string, error)                     { return "", nil }
+func (s *Sim) GetAttribute(selector, attribute string) (string, error)     { return "", nil }
+func (s *Sim) IsElementPresent(selector string) bool                       { return true }
+func (s *Sim) WaitVisible(selector string) error                           { return nil }
+func (s *Sim) Screenshot(path string) error                                { return nil }
+func (s *Sim) ExecuteScript(script string) (interface{}, error)            { return nil, nil }
+func (s *Sim) Close() error                                                { return nil }
+
+func (s *Sim) GetCookies() ([]*proto.NetworkCookie, error) {
+	return s.cookies, nil
+}
+
+func (s *Sim) SetCookies(cookies []*proto.NetworkCookie) error {
+	s.cookies = cookies
+	return nil
+}
+
+func (s *Sim) GetWebStorage() (WebStorage, error) {
+	return s.storage, nil
+}
+
+func (s *Sim) SetWebStorage(ws WebStorage) error {
+	s.storage = ws
+	return nil
+}
+
+// HasValidSession reports whether any session cookie is set
+func (s *Sim) HasValidSession() bool {
+	return len(s.cookies) > 0
+}
+
+// SetHeaders keeps the extra headers, refusing those that follow the
+// fingerprint like the real browser does
+func (s *Sim) SetHeaders(headers map[string]string) error {
+	for name := range headers {
+		if config.FingerprintHeader(name) {
+			return fmt.Errorf("header %s follows user_agent and locale and can't be set", name)
+		}
+	}
+	s.headers = headers
+	return nil
+}
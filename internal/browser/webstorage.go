@@ -0,0 +1,56 @@
+package browser
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// WebStorage is the localStorage and sessionStorage of one origin
+type WebStorage struct {
+	Origin  string            `json:"origin"`
+	Local   map[string]string `json:"local,omitempty"`
+	Session map[string]string `json:"session,omitempty"`
+}
+
+// readStorage returns the storage of the page's origin as JSON
+const readStorage = `() => ({
+	origin: location.origin,
+	local: Object.fromEntries(Object.entries(localStorage)),
+	session: Object.fromEntries(Object.entries(sessionStorage)),
+})`
+
+// seedStorage sets the saved items missing from a document of the saved
+// origin, before the page's own scripts run
+const seedStorage = `(function (s) {
+	if (location.origin !== s.origin) return;
+	for (const [k, v] of Object.entries(s.local || {})) if (localStorage.getItem(k) === null) localStorage.setItem(k, v);
+	for (const [k, v] of Object.entries(s.session || {})) if (sessionStorage.getItem(k) === null) sessionStorage.setItem(k, v);
+})(%s)`
+
+// GetWebStorage returns the storage of the current page's origin
+func (b *Browser) GetWebStorage() (WebStorage, error) {
+	var ws WebStorage
+	res, err := b.Page.Eval(readStorage)
+	if err != nil {
+		return ws, fmt.Errorf("failed to read web storage: %w", err)
+	}
+	if err := res.Value.Unmarshal(&ws); err != nil {
+		return ws, fmt.Errorf("failed to decode web storage: %w", err)
+	}
+	return ws, nil
+}
+
+// SetWebStorage restores storage: every document of its origin loaded
+// from now on starts with the items it doesn't already have. Storage of
+// other origins can't be written from the current page, so nothing is set
+// until the origin is visited.
+func (b *Browser) SetWebStorage(ws WebStorage) error {
+	data, err := json.Marshal(ws)
+	if err != nil {
+		return fmt.Errorf("failed to encode web storage: %w", err)
+	}
+	if _, err := b.Page.EvalOnNewDocument(fmt.Sprintf(seedStorage, data)); err != nil {
+		return fmt.Errorf("failed to restore web storage: %w", err)
+	}
+	return nil
+}
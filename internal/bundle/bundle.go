@@ -0,0 +1,149 @@
+package bundle
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"subspace/internal/browser"
+)
+
+/*
+ACCOUNT BUNDLE MODULE
+
+Moves a long-lived persona between hosts: everything an account runs with
+is packed into one file, encrypted with a passphrase.
+- the account's config.yaml fragment (proxies, time zone, persona,
+  fingerprint, DNS)
+- the saved session cookies
+- the account's saved web storage
+
+The bundle is a JSON envelope around the AES-256-GCM sealed contents. The
+key is derived from the passphrase with PBKDF2-HMAC-SHA256 and a random
+salt, and a wrong passphrase or a tampered file fails to open.
+*/
+
+// Version is the bundle format written by Seal
+const Version = 1
+
+// iterations of PBKDF2 for new bundles
+const iterations = 600_000
+
+// ErrPassphrase is returned when a bundle can't be opened
+var ErrPassphrase = errors.New("wrong passphrase or corrupted bundle")
+
+// Bundle is the state of one account
+type Bundle struct {
+	Account    string                 `json:"account"`
+	ExportedAt time.Time              `json:"exported_at"`
+	Config     string                 `json:"config"` // YAML under accounts: <name>:
+	Cookies    []*proto.NetworkCookie `json:"cookies,omitempty"`
+	WebStorage *browser.WebStorage    `json:"web_storage,omitempty"`
+}
+
+// envelope is the file format around the sealed bundle
+type envelope struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+// Seal encrypts a bundle with a passphrase
+func Seal(b *Bundle, passphrase string) ([]byte, error) {
+	plain, err := json.Marshal(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bundle: %w", err)
+	}
+	env := envelope{Version: Version, KDF: "pbkdf2-sha256", Iterations: iterations, Salt: make([]byte, 16)}
+	if _, err := rand.Read(env.Salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	aead, err := newAEAD(passphrase, env.Salt, env.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	env.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(env.Nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	env.Data = aead.Seal(nil, env.Nonce, plain, nil)
+	return json.MarshalIndent(env, "", "  ")
+}
+
+// Open decrypts a bundle sealed with a passphrase
+func Open(data []byte, passphrase string) (*Bundle, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("not an account bundle: %w", err)
+	}
+	if env.Version != Version || env.KDF != "pbkdf2-sha256" {
+		return nil, fmt.Errorf("unsupported bundle version %d (%s)", env.Version, env.KDF)
+	}
+	aead, err := newAEAD(passphrase, env.Salt, env.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(env.Nonce) != aead.NonceSize() {
+		return nil, ErrPassphrase
+	}
+	plain, err := aead.Open(nil, env.Nonce, env.Data, nil)
+	if err != nil {
+		return nil, ErrPassphrase
+	}
+
+	var b Bundle
+	if err := json.Unmarshal(plain, &b); err != nil {
+		return nil, fmt.Errorf("failed to decode bundle: %w", err)
+	}
+	return &b, nil
+}
+
+// newAEAD returns AES-256-GCM keyed from a passphrase
+func newAEAD(passphrase string, salt []byte, iter int) (cipher.AEAD, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("a passphrase is required")
+	}
+	if iter < 1 {
+		return nil, fmt.Errorf("invalid bundle iterations: %d", iter)
+	}
+	block, err := aes.NewCipher(pbkdf2([]byte(passphrase), salt, iter, 32))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// pbkdf2 derives a key with PBKDF2-HMAC-SHA256 (RFC 8018)
+func pbkdf2(password, salt []byte, iter, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	var key []byte
+	for block := uint32(1); len(key) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(binary.BigEndian.AppendUint32(nil, block))
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for i := 1; i < iter; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
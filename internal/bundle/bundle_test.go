@@ -0,0 +1,46 @@
+package bundle
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"subspace/internal/browser"
+)
+
+func TestPBKDF2(t *testing.T) {
+	// RFC 7914, section 11
+	got := hex.EncodeToString(pbkdf2([]byte("passwd"), []byte("salt"), 1, 32))
+	if want := "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc"; got != want {
+		t.Errorf("pbkdf2 = %s, want %s", got, want)
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	b := &Bundle{
+		Account:    "alice",
+		Config:     "alice:\n  persona: laptop\n",
+		Cookies:    []*proto.NetworkCookie{{Name: "li_at", Value: "secret"}},
+		WebStorage: &browser.WebStorage{Origin: "https://www.linkedin.com", Local: map[string]string{"t": "v"}},
+	}
+	data, err := Seal(b, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Open(data, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Account != "alice" || got.Cookies[0].Value != "secret" || got.WebStorage.Local["t"] != "v" {
+		t.Errorf("opened %+v", got)
+	}
+	if _, err := Open(data, "wrong"); !errors.Is(err, ErrPassphrase) {
+		t.Errorf("wrong passphrase: %v", err)
+	}
+	if _, err := Seal(b, ""); err == nil {
+		t.Error("empty passphrase accepted")
+	}
+}
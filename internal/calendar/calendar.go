@@ -0,0 +1,125 @@
+package calendar
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"subspace/internal/config"
+)
+
+/*
+CALENDAR MODULE
+
+Decides whether a date is a day off for the persona: weekends, the built-in
+public holidays of the persona's country, and any extra days listed in an
+iCalendar (.ics) file, e.g. an exported company holiday calendar.
+
+Built-in lists cover nationwide holidays only; regional holidays (German
+states, Spanish communities, Scotland) belong in an ICS file.
+*/
+
+// Holiday is a named day off
+type Holiday struct {
+	Date time.Time `json:"date"` // Midnight UTC of the day
+	Name string    `json:"name"`
+}
+
+// Calendar answers whether a given day is a working day
+type Calendar struct {
+	country  string
+	weekends bool
+	extra    map[string]string // "2006-01-02" -> name, from the ICS file
+	years    map[int]map[string]string
+}
+
+// New builds the calendar for the active persona. An unreadable or
+// malformed ICS file is an error; built-in holidays never fail.
+func New(cfg config.StealthConfig) (*Calendar, error) {
+	c := &Calendar{
+		country:  cfg.ActivePersona().Country,
+		weekends: cfg.SkipWeekends,
+		extra:    make(map[string]string),
+		years:    make(map[int]map[string]string),
+	}
+	if c.country != "" && !Supported(c.country) {
+		return nil, fmt.Errorf("no built-in holidays for country %s (supported: %v)", c.country, Countries())
+	}
+
+	if cfg.HolidayICS != "" {
+		f, err := os.Open(cfg.HolidayICS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open holiday calendar: %w", err)
+		}
+		defer f.Close()
+
+		days, err := ParseICS(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse holiday calendar %s: %w", cfg.HolidayICS, err)
+		}
+		for _, d := range days {
+			c.extra[dayKey(d.Date)] = d.Name
+		}
+	}
+	return c, nil
+}
+
+// DayOff reports whether t falls on a day off and why: "weekend" or the
+// holiday's name. The day is taken in t's own location.
+func (c *Calendar) DayOff(t time.Time) (bool, string) {
+	if c == nil {
+		return false, ""
+	}
+	if c.weekends && (t.Weekday() == time.Saturday || t.Weekday() == time.Sunday) {
+		return true, "weekend"
+	}
+
+	key := dayKey(t)
+	if name, ok := c.extra[key]; ok {
+		return true, name
+	}
+	if c.country == "" {
+		return false, ""
+	}
+	byDay, ok := c.years[t.Year()]
+	if !ok {
+		byDay = make(map[string]string)
+		for _, h := range Holidays(c.country, t.Year()) {
+			byDay[dayKey(h.Date)] = h.Name
+		}
+		c.years[t.Year()] = byDay
+	}
+	if name, ok := byDay[key]; ok {
+		return true, name
+	}
+	return false, ""
+}
+
+// Upcoming lists the days off from t (inclusive) over the next n days,
+// weekends excluded
+func (c *Calendar) Upcoming(t time.Time, n int) []Holiday {
+	var out []Holiday
+	for i := 0; i < n; i++ {
+		day := t.AddDate(0, 0, i)
+		if off, name := c.DayOff(day); off && name != "weekend" {
+			out = append(out, Holiday{Date: date(day.Year(), day.Month(), day.Day()), Name: name})
+		}
+	}
+	return out
+}
+
+// dayKey identifies a calendar day independent of time zone
+func dayKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// date returns midnight UTC of a calendar day
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// sortHolidays orders holidays by date
+func sortHolidays(h []Holiday) {
+	sort.SliceStable(h, func(i, j int) bool { return h[i].Date.Before(h[j].Date) })
+}
@@ -0,0 +1,139 @@
+package calendar
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"subspace/internal/config"
+)
+
+func TestBuiltInHolidays(t *testing.T) {
+	tests := []struct {
+		country string
+		day     time.Time
+		name    string
+	}{
+		{"US", date(2024, time.November, 28), "Thanksgiving Day"},
+		{"US", date(2024, time.May, 27), "Memorial Day"},
+		{"US", date(2021, time.July, 5), "Independence Day"}, // July 4th was a Sunday
+		{"US", date(2021, time.December, 24), "Christmas Day"},
+		{"GB", date(2024, time.March, 29), "Good Friday"},
+		{"GB", date(2021, time.December, 28), "Boxing Day"},
+		{"DE", date(2024, time.May, 9), "Christi Himmelfahrt"},
+		{"DE", date(2025, time.June, 9), "Pfingstmontag"},
+		{"ES", date(2025, time.April, 18), "Viernes Santo"},
+	}
+	for _, tt := range tests {
+		found := false
+		for _, h := range Holidays(tt.country, tt.day.Year()) {
+			if h.Date.Equal(tt.day) {
+				found = true
+				if h.Name != tt.name {
+					t.Errorf("%s %s: got %q, want %q", tt.country, dayKey(tt.day), h.Name, tt.name)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%s %s: want %q, not a holiday", tt.country, dayKey(tt.day), tt.name)
+		}
+	}
+}
+
+func TestEaster(t *testing.T) {
+	for year, want := range map[int]string{
+		2019: "2019-04-21",
+		2024: "2024-03-31",
+		2025: "2025-04-20",
+		2038: "2038-04-25",
+	} {
+		if got := dayKey(Easter(year)); got != want {
+			t.Errorf("Easter(%d) = %s, want %s", year, got, want)
+		}
+	}
+}
+
+func TestParseICS(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20241223",
+		"DTEND;VALUE=DATE:20241225",
+		"SUMMARY:Office closed\\, winter",
+		" break",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20240712T090000Z",
+		"SUMMARY:Offsite",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	days, err := ParseICS(strings.NewReader(ics))
+	if err != nil {
+		t.Fatalf("ParseICS: %v", err)
+	}
+	want := []string{"2024-07-12 Offsite", "2024-12-23 Office closed, winterbreak", "2024-12-24 Office closed, winterbreak"}
+	if len(days) != len(want) {
+		t.Fatalf("got %d days, want %d: %v", len(days), len(want), days)
+	}
+	for i, d := range days {
+		if got := dayKey(d.Date) + " " + d.Name; got != want[i] {
+			t.Errorf("day %d: got %q, want %q", i, got, want[i])
+		}
+	}
+
+	for _, bad := range []string{
+		"BEGIN:VEVENT\nSUMMARY:No start\nEND:VEVENT",
+		"BEGIN:VEVENT\nDTSTART:2024\nEND:VEVENT",
+		"BEGIN:VEVENT\nDTSTART:20240101\nDTEND:20250101\nEND:VEVENT",
+		"BEGIN:VEVENT\nDTSTART:20240101",
+	} {
+		if _, err := ParseICS(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseICS(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestDayOff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "company.ics")
+	ics := "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20240715\nSUMMARY:Summer shutdown\nEND:VEVENT\n"
+	if err := os.WriteFile(path, []byte(ics), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.StealthConfig{
+		SkipWeekends: true,
+		HolidayICS:   path,
+		Persona:      "desktop",
+		Personas:     map[string]config.PersonaConfig{"desktop": {Country: "US"}},
+	}
+	cal, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tests := []struct {
+		day    time.Time
+		off    bool
+		reason string
+	}{
+		{time.Date(2024, time.July, 4, 10, 0, 0, 0, time.Local), true, "Independence Day"},
+		{time.Date(2024, time.July, 6, 10, 0, 0, 0, time.Local), true, "weekend"},
+		{time.Date(2024, time.July, 15, 10, 0, 0, 0, time.Local), true, "Summer shutdown"},
+		{time.Date(2024, time.July, 16, 10, 0, 0, 0, time.Local), false, ""},
+	}
+	for _, tt := range tests {
+		off, reason := cal.DayOff(tt.day)
+		if off != tt.off || reason != tt.reason {
+			t.Errorf("DayOff(%s) = %v, %q; want %v, %q", dayKey(tt.day), off, reason, tt.off, tt.reason)
+		}
+	}
+
+	cfg.Personas["desktop"] = config.PersonaConfig{Country: "XX"}
+	if _, err := New(cfg); err == nil {
+		t.Error("New with unsupported country succeeded, want error")
+	}
+}
@@ -0,0 +1,171 @@
+package calendar
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// countries maps an ISO 3166 country code to its nationwide public holidays
+var countries = map[string]func(year int) []Holiday{
+	"US": usHolidays,
+	"GB": gbHolidays,
+	"DE": deHolidays,
+	"ES": esHolidays,
+}
+
+// Countries lists the country codes with built-in holidays
+func Countries() []string {
+	codes := make([]string, 0, len(countries))
+	for code := range countries {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Supported reports whether a country has built-in holidays
+func Supported(country string) bool {
+	_, ok := countries[strings.ToUpper(country)]
+	return ok
+}
+
+// Holidays returns the public holidays of a country in a year, ordered by
+// date. Unknown countries have none.
+func Holidays(country string, year int) []Holiday {
+	fn, ok := countries[strings.ToUpper(country)]
+	if !ok {
+		return nil
+	}
+	h := fn(year)
+	sortHolidays(h)
+	return h
+}
+
+// usHolidays are the US federal holidays, moved to the observed weekday
+// when they fall on a weekend
+func usHolidays(year int) []Holiday {
+	return []Holiday{
+		{observedUS(date(year, time.January, 1)), "New Year's Day"},
+		{nthWeekday(year, time.January, time.Monday, 3), "Martin Luther King Jr. Day"},
+		{nthWeekday(year, time.February, time.Monday, 3), "Washington's Birthday"},
+		{lastWeekday(year, time.May, time.Monday), "Memorial Day"},
+		{observedUS(date(year, time.June, 19)), "Juneteenth"},
+		{observedUS(date(year, time.July, 4)), "Independence Day"},
+		{nthWeekday(year, time.September, time.Monday, 1), "Labor Day"},
+		{nthWeekday(year, time.October, time.Monday, 2), "Columbus Day"},
+		{observedUS(date(year, time.November, 11)), "Veterans Day"},
+		{nthWeekday(year, time.November, time.Thursday, 4), "Thanksgiving Day"},
+		{observedUS(date(year, time.December, 25)), "Christmas Day"},
+	}
+}
+
+// gbHolidays are the bank holidays of England and Wales, with substitute
+// days when a fixed holiday falls on a weekend
+func gbHolidays(year int) []Holiday {
+	easter := Easter(year)
+	christmas, boxing := date(year, time.December, 25), date(year, time.December, 26)
+	switch christmas.Weekday() {
+	case time.Friday: // Boxing Day on Saturday moves to Monday
+		boxing = boxing.AddDate(0, 0, 2)
+	case time.Saturday:
+		christmas, boxing = christmas.AddDate(0, 0, 2), boxing.AddDate(0, 0, 2)
+	case time.Sunday:
+		christmas = christmas.AddDate(0, 0, 2)
+	}
+	return []Holiday{
+		{nextWeekday(date(year, time.January, 1)), "New Year's Day"},
+		{easter.AddDate(0, 0, -2), "Good Friday"},
+		{easter.AddDate(0, 0, 1), "Easter Monday"},
+		{nthWeekday(year, time.May, time.Monday, 1), "Early May Bank Holiday"},
+		{lastWeekday(year, time.May, time.Monday), "Spring Bank Holiday"},
+		{lastWeekday(year, time.August, time.Monday), "Summer Bank Holiday"},
+		{christmas, "Christmas Day"},
+		{boxing, "Boxing Day"},
+	}
+}
+
+// deHolidays are the nationwide German public holidays
+func deHolidays(year int) []Holiday {
+	easter := Easter(year)
+	return []Holiday{
+		{date(year, time.January, 1), "Neujahr"},
+		{easter.AddDate(0, 0, -2), "Karfreitag"},
+		{easter.AddDate(0, 0, 1), "Ostermontag"},
+		{date(year, time.May, 1), "Tag der Arbeit"},
+		{easter.AddDate(0, 0, 39), "Christi Himmelfahrt"},
+		{easter.AddDate(0, 0, 50), "Pfingstmontag"},
+		{date(year, time.October, 3), "Tag der Deutschen Einheit"},
+		{date(year, time.December, 25), "1. Weihnachtstag"},
+		{date(year, time.December, 26), "2. Weihnachtstag"},
+	}
+}
+
+// esHolidays are the nationwide Spanish public holidays
+func esHolidays(year int) []Holiday {
+	return []Holiday{
+		{date(year, time.January, 1), "Año Nuevo"},
+		{date(year, time.January, 6), "Epifanía del Señor"},
+		{Easter(year).AddDate(0, 0, -2), "Viernes Santo"},
+		{date(year, time.May, 1), "Fiesta del Trabajo"},
+		{date(year, time.August, 15), "Asunción de la Virgen"},
+		{date(year, time.October, 12), "Fiesta Nacional de España"},
+		{date(year, time.November, 1), "Todos los Santos"},
+		{date(year, time.December, 6), "Día de la Constitución"},
+		{date(year, time.December, 8), "Inmaculada Concepción"},
+		{date(year, time.December, 25), "Navidad"},
+	}
+}
+
+// Easter returns Easter Sunday of a year (Gregorian calendar, anonymous
+// algorithm)
+func Easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
+
+// nthWeekday returns the n-th given weekday of a month
+func nthWeekday(year int, month time.Month, wd time.Weekday, n int) time.Time {
+	first := date(year, month, 1)
+	offset := (int(wd) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+7*(n-1))
+}
+
+// lastWeekday returns the last given weekday of a month
+func lastWeekday(year int, month time.Month, wd time.Weekday) time.Time {
+	last := date(year, month+1, 0)
+	offset := (int(last.Weekday()) - int(wd) + 7) % 7
+	return last.AddDate(0, 0, -offset)
+}
+
+// observedUS moves a Saturday holiday to Friday and a Sunday one to Monday
+func observedUS(t time.Time) time.Time {
+	switch t.Weekday() {
+	case time.Saturday:
+		return t.AddDate(0, 0, -1)
+	case time.Sunday:
+		return t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// nextWeekday moves a weekend day to the following Monday
+func nextWeekday(t time.Time) time.Time {
+	switch t.Weekday() {
+	case time.Saturday:
+		return t.AddDate(0, 0, 2)
+	case time.Sunday:
+		return t.AddDate(0, 0, 1)
+	}
+	return t
+}
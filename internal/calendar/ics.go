@@ -0,0 +1,128 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxEventDays bounds how many days a single ICS event may mark off, so a
+// malformed end date can't disable automation for years
+const maxEventDays = 31
+
+// ParseICS reads the all-day events of an iCalendar file as days off.
+// Multi-day events mark every day up to their (exclusive) DTEND. Timed
+// events count for the day they start on. Recurrence rules are not
+// expanded; holiday feeds list every occurrence explicitly.
+func ParseICS(r io.Reader) ([]Holiday, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		days    []Holiday
+		inEvent bool
+		start   time.Time
+		end     time.Time
+		summary string
+	)
+	for n, line := range lines {
+		name, value, ok := splitProperty(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			inEvent, start, end, summary = true, time.Time{}, time.Time{}, ""
+		case name == "END" && value == "VEVENT":
+			if !inEvent {
+				return nil, fmt.Errorf("line %d: END:VEVENT without BEGIN", n+1)
+			}
+			inEvent = false
+			if start.IsZero() {
+				return nil, fmt.Errorf("line %d: event %q has no DTSTART", n+1, summary)
+			}
+			if end.IsZero() || !end.After(start) {
+				end = start.AddDate(0, 0, 1)
+			}
+			if end.After(start.AddDate(0, 0, maxEventDays)) {
+				return nil, fmt.Errorf("line %d: event %q spans more than %d days", n+1, summary, maxEventDays)
+			}
+			for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+				days = append(days, Holiday{Date: d, Name: summary})
+			}
+		case !inEvent:
+			continue
+		case name == "DTSTART":
+			if start, err = parseICSDate(value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", n+1, err)
+			}
+		case name == "DTEND":
+			if end, err = parseICSDate(value); err != nil {
+				return nil, fmt.Errorf("line %d: %w", n+1, err)
+			}
+		case name == "SUMMARY":
+			summary = unescapeText(value)
+		}
+	}
+	if inEvent {
+		return nil, fmt.Errorf("unterminated VEVENT")
+	}
+
+	sortHolidays(days)
+	return days, nil
+}
+
+// unfold joins continuation lines (starting with a space or tab) onto the
+// line before them, as required by RFC 5545
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// splitProperty splits "DTSTART;VALUE=DATE:20240101" into its name
+// ("DTSTART") and value ("20240101"), dropping parameters
+func splitProperty(line string) (string, string, bool) {
+	colon := strings.IndexByte(line, ':')
+	if colon < 0 {
+		return "", "", false
+	}
+	name := line[:colon]
+	if semi := strings.IndexByte(name, ';'); semi >= 0 {
+		name = name[:semi]
+	}
+	return strings.ToUpper(name), line[colon+1:], true
+}
+
+// parseICSDate reads a DATE (20240101) or DATE-TIME (20240101T090000[Z])
+// value as the calendar day it names
+func parseICSDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	t, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return t, nil
+}
+
+// unescapeText undoes RFC 5545 TEXT escaping
+func unescapeText(s string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
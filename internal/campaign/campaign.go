@@ -0,0 +1,184 @@
+package campaign
+
+import (
+	"math"
+	"sort"
+
+	"subspace/internal/config"
+	"subspace/internal/rules"
+	"subspace/internal/storage"
+)
+
+/*
+CAMPAIGN MODULE
+
+Shares the account's connection budget between campaigns explicitly instead
+of first-come-first-served. A campaign is a named set of candidates chosen by
+a rule (e.g. search_query ~= "golang"); a profile belongs to the first
+campaign whose rule matches it, or to no campaign.
+
+The allocator fills campaigns in priority tiers, highest first. Within a
+tier the budget is split in proportion to the weights (largest remainder),
+and a campaign with fewer candidates than its share passes the rest on to
+the others in the tier. What a tier can't use goes to the next one;
+profiles outside every campaign get what is left at the end.
+*/
+
+// Unassigned names the profiles outside every campaign
+const Unassigned = "(none)"
+
+// Allocation is one campaign's share of a budget
+type Allocation struct {
+	Campaign   string  `json:"campaign"`
+	Priority   int     `json:"priority"`
+	Weight     float64 `json:"weight"`
+	Candidates int     `json:"candidates"`
+	Allocated  int     `json:"allocated"`
+}
+
+// Allocator assigns profiles to campaigns and splits budgets between them
+type Allocator struct {
+	campaigns []config.CampaignConfig
+	rules     []*rules.Rule
+}
+
+// New compiles the campaign rules. Rules are validated with the config.
+func New(campaigns []config.CampaignConfig) *Allocator {
+	a := &Allocator{campaigns: campaigns}
+	for _, c := range campaigns {
+		a.rules = append(a.rules, rules.MustCompile(c.Match))
+	}
+	return a
+}
+
+// Enabled reports whether any campaign is configured
+func (a *Allocator) Enabled() bool {
+	return len(a.campaigns) > 0
+}
+
+// Of returns the campaign a profile belongs to, or Unassigned
+func (a *Allocator) Of(p *storage.Profile) string {
+	env := p.RuleEnv()
+	for i, r := range a.rules {
+		if r.Match(env) {
+			return a.campaigns[i].Name
+		}
+	}
+	return Unassigned
+}
+
+// Select picks up to budget candidates, keeping their order within each
+// campaign, and returns them in the original order with the allocation
+// that chose them
+func (a *Allocator) Select(candidates []*storage.Profile, budget int) ([]*storage.Profile, []Allocation) {
+	byCampaign := make(map[string][]*storage.Profile)
+	of := make(map[*storage.Profile]string, len(candidates))
+	for _, p := range candidates {
+		name := a.Of(p)
+		of[p] = name
+		byCampaign[name] = append(byCampaign[name], p)
+	}
+	demand := make(map[string]int, len(byCampaign))
+	for name, ps := range byCampaign {
+		demand[name] = len(ps)
+	}
+
+	allocations := a.Allocate(budget, demand)
+	left := make(map[string]int, len(allocations))
+	for _, al := range allocations {
+		left[al.Campaign] = al.Allocated
+	}
+	selected := candidates[:0:0]
+	for _, p := range candidates {
+		if left[of[p]] > 0 {
+			left[of[p]]--
+			selected = append(selected, p)
+		}
+	}
+	return selected, allocations
+}
+
+// Allocate splits budget between the campaigns given how many candidates
+// each has. The result lists every configured campaign in priority order,
+// then Unassigned if it has candidates.
+func (a *Allocator) Allocate(budget int, demand map[string]int) []Allocation {
+	allocations := make([]Allocation, 0, len(a.campaigns)+1)
+	for _, c := range a.campaigns {
+		allocations = append(allocations, Allocation{Campaign: c.Name, Priority: c.Priority, Weight: c.Weight, Candidates: demand[c.Name]})
+	}
+	sort.SliceStable(allocations, func(i, j int) bool { return allocations[i].Priority > allocations[j].Priority })
+
+	for start := 0; start < len(allocations) && budget > 0; {
+		end := start
+		for end < len(allocations) && allocations[end].Priority == allocations[start].Priority {
+			end++
+		}
+		budget -= share(allocations[start:end], budget)
+		start = end
+	}
+
+	if n := demand[Unassigned]; n > 0 {
+		allocations = append(allocations, Allocation{Campaign: Unassigned, Candidates: n, Allocated: minInt(n, budget)})
+	}
+	return allocations
+}
+
+// share splits budget within one tier by weight and returns how much of it
+// the tier used. Campaigns whose candidates run out drop out and their
+// share is split again among the rest.
+func share(tier []Allocation, budget int) int {
+	used := 0
+	for budget > used {
+		var open []*Allocation
+		total := 0.0
+		for i := range tier {
+			if tier[i].Allocated < tier[i].Candidates && tier[i].Weight > 0 {
+				open = append(open, &tier[i])
+				total += tier[i].Weight
+			}
+		}
+		if len(open) == 0 {
+			break
+		}
+
+		// Largest remainder: floor every share, then hand the leftover
+		// units to the largest fractions
+		avail := budget - used
+		type part struct {
+			a    *Allocation
+			frac float64
+		}
+		parts := make([]part, len(open))
+		given := 0
+		for i, a := range open {
+			exact := float64(avail) * a.Weight / total
+			n := minInt(int(math.Floor(exact)), a.Candidates-a.Allocated)
+			a.Allocated += n
+			given += n
+			parts[i] = part{a, exact - math.Floor(exact)}
+		}
+		sort.SliceStable(parts, func(i, j int) bool { return parts[i].frac > parts[j].frac })
+		for _, p := range parts {
+			if given >= avail {
+				break
+			}
+			if p.a.Allocated < p.a.Candidates {
+				p.a.Allocated++
+				given++
+			}
+		}
+		if given == 0 {
+			break
+		}
+		used += given
+	}
+	return used
+}
+
+// minInt returns the smaller of two ints
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
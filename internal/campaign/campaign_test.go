@@ -0,0 +1,74 @@
+package campaign
+
+import (
+	"fmt"
+	"testing"
+
+	"subspace/internal/config"
+	"subspace/internal/storage"
+)
+
+func TestAllocate(t *testing.T) {
+	a := New([]config.CampaignConfig{
+		{Name: "go", Match: `search_query ~= "golang"`, Priority: 1, Weight: 2},
+		{Name: "rust", Match: `search_query ~= "rust"`, Priority: 1, Weight: 1},
+		{Name: "java", Match: `search_query ~= "java"`, Priority: 0, Weight: 1},
+	})
+
+	tests := []struct {
+		budget int
+		demand map[string]int
+		want   map[string]int
+	}{
+		// Weights split the top tier 2:1
+		{9, map[string]int{"go": 10, "rust": 10, "java": 10}, map[string]int{"go": 6, "rust": 3, "java": 0}},
+		// Largest remainder: 10 = 6.67 + 3.33
+		{10, map[string]int{"go": 10, "rust": 10}, map[string]int{"go": 7, "rust": 3}},
+		// Rust can't use its share; go takes it, then the next tier
+		{10, map[string]int{"go": 5, "rust": 1, "java": 10}, map[string]int{"go": 5, "rust": 1, "java": 4}},
+		// Unassigned profiles get what is left
+		{10, map[string]int{"go": 2, Unassigned: 20}, map[string]int{"go": 2, Unassigned: 8}},
+		{0, map[string]int{"go": 2}, map[string]int{"go": 0}},
+	}
+	for _, tt := range tests {
+		got := make(map[string]int)
+		total := 0
+		for _, al := range a.Allocate(tt.budget, tt.demand) {
+			got[al.Campaign] = al.Allocated
+			total += al.Allocated
+		}
+		for name, want := range tt.want {
+			if got[name] != want {
+				t.Errorf("budget %d, demand %v: %s got %d, want %d", tt.budget, tt.demand, name, got[name], want)
+			}
+		}
+		if total > tt.budget {
+			t.Errorf("budget %d, demand %v: allocated %d", tt.budget, tt.demand, total)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	a := New([]config.CampaignConfig{
+		{Name: "go", Match: `search_query ~= "golang"`, Weight: 1},
+		{Name: "rust", Match: `search_query ~= "rust"`, Weight: 1},
+	})
+	var candidates []*storage.Profile
+	for i := 0; i < 6; i++ {
+		query := "golang"
+		if i >= 4 {
+			query = "rust"
+		}
+		candidates = append(candidates, &storage.Profile{ID: fmt.Sprint(i), SearchQuery: query})
+	}
+
+	// First come would pick four gophers; the even split picks two of each
+	selected, _ := a.Select(candidates, 4)
+	var ids string
+	for _, p := range selected {
+		ids += p.ID
+	}
+	if ids != "0145" {
+		t.Errorf("selected %s, want 0145", ids)
+	}
+}
@@ -0,0 +1,114 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+CLOCK MODULE
+
+Pipeline code reads the time and sleeps through this package instead of
+calling time.Now/time.Sleep directly, so simulations (see the bench command)
+can swap in a Fake clock and run days of activity in seconds.
+
+Like the logger, the clock is process-wide:
+
+	clock.Set(clock.NewFake(start))
+	defer clock.Set(clock.Real{})
+
+Wall-clock measurements (latency metrics, logger.Timing) keep using the time
+package directly since they measure real elapsed time.
+*/
+
+// Clock tells the time and waits
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+// Real is the system clock
+type Real struct{}
+
+// Now returns the current system time
+func (Real) Now() time.Time { return time.Now() }
+
+// Sleep pauses the calling goroutine for d
+func (Real) Sleep(d time.Duration) { time.Sleep(d) }
+
+// Fake is a manually driven clock. Sleep returns immediately after
+// advancing the clock, so simulated waits cost no real time.
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFake returns a fake clock starting at start
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+// Now returns the fake current time
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Sleep advances the clock by d without blocking
+func (f *Fake) Sleep(d time.Duration) {
+	f.Advance(d)
+}
+
+// Advance moves the clock forward by d; negative durations are ignored
+func (f *Fake) Advance(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	f.mu.Lock()
+	f.now = f.now.Add(d)
+	f.mu.Unlock()
+}
+
+// AdvanceTo moves the clock forward to t if t is in the future
+func (f *Fake) AdvanceTo(t time.Time) {
+	f.mu.Lock()
+	if t.After(f.now) {
+		f.now = t
+	}
+	f.mu.Unlock()
+}
+
+var (
+	mu      sync.RWMutex
+	current Clock = Real{}
+)
+
+// Set replaces the process-wide clock
+func Set(c Clock) {
+	mu.Lock()
+	current = c
+	mu.Unlock()
+}
+
+// Get returns the process-wide clock
+func Get() Clock {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Now returns the current time of the process-wide clock
+func Now() time.Time {
+	return Get().Now()
+}
+
+// Sleep waits for d on the process-wide clock
+func Sleep(d time.Duration) {
+	Get().Sleep(d)
+}
+
+// Since returns the time elapsed since t on the process-wide clock
+func Since(t time.Time) time.Duration {
+	return Now().Sub(t)
+}
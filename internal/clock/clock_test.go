@@ -0,0 +1,43 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+	Set(fake)
+	defer Set(Real{})
+
+	// Sleeping advances the clock at once
+	began := time.Now()
+	Sleep(24 * time.Hour)
+	if time.Since(began) > time.Second {
+		t.Error("fake sleep blocked")
+	}
+	if got := Now(); !got.Equal(start.Add(24 * time.Hour)) {
+		t.Errorf("Now after sleeping a day = %v", got)
+	}
+
+	// Time never runs backwards
+	fake.Advance(-time.Hour)
+	fake.AdvanceTo(start)
+	if got := Since(start); got != 24*time.Hour {
+		t.Errorf("Since(start) = %v, want 24h", got)
+	}
+	fake.AdvanceTo(start.Add(48 * time.Hour))
+	if got := Since(start); got != 48*time.Hour {
+		t.Errorf("Since(start) after AdvanceTo = %v, want 48h", got)
+	}
+}
+
+func TestRealByDefault(t *testing.T) {
+	if _, ok := Get().(Real); !ok {
+		t.Fatalf("default clock is %T, want Real", Get())
+	}
+	if d := time.Since(Now()); d < 0 || d > time.Second {
+		t.Errorf("real clock is %v off the system time", d)
+	}
+}
@@ -1,20 +1,55 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"subspace/internal/geo"
+	"subspace/internal/i18n"
+	"subspace/internal/rules"
+	"subspace/internal/taxonomy"
 )
 
 // Config represents the complete application configuration
 type Config struct {
-	App     AppConfig     `yaml:"app"`
-	Stealth StealthConfig `yaml:"stealth"`
-	Limits  LimitsConfig  `yaml:"limits"`
-	Auth    AuthConfig    `yaml:"auth"`
-	Search  SearchConfig  `yaml:"search"`
+	App       AppConfig       `yaml:"app"`
+	Modules   ModulesConfig   `yaml:"modules"`
+	Workflow  WorkflowConfig  `yaml:"workflow"`
+	Hooks     HooksConfig     `yaml:"hooks"`
+	Notifications NotificationsConfig `yaml:"notifications"`
+	Modals    ModalsConfig    `yaml:"modals"`
+	Stealth   StealthConfig   `yaml:"stealth"`
+	Limits    LimitsConfig    `yaml:"limits"`
+	Auth      AuthConfig      `yaml:"auth"`
+	Search    SearchConfig    `yaml:"search"`
+	Targeting TargetingConfig `yaml:"targeting"`
+	Enrichment EnrichmentConfig `yaml:"enrichment"`
+	Review    ReviewConfig    `yaml:"review"`
+	Messaging MessagingConfig `yaml:"messaging"`
+	Retention RetentionConfig `yaml:"retention"`
+	Storage    StorageConfig    `yaml:"storage"`
+	Watchdog   WatchdogConfig   `yaml:"watchdog"`
+	Bandwidth  BandwidthConfig  `yaml:"bandwidth"`
+	Hardening  HardeningConfig  `yaml:"hardening"`
+	Simulation SimulationConfig `yaml:"simulation"`
+
+	// Pacing experiments, run in simulation with "subspace experiment <name>"
+	Experiments map[string]Experiment `yaml:"experiments"`
+
+	// Accounts and what each is bound to; app.account selects one
+	Accounts     map[string]AccountConfig `yaml:"accounts"`
+	ProxyCheck   ProxyCheckConfig         `yaml:"proxy_check"`
+	ProxySession ProxySessionConfig       `yaml:"proxy_session"`
 }
 
 // AppConfig contains general application settings
@@ -22,7 +57,129 @@ type AppConfig struct {
 	DataDir   string `yaml:"data_dir"`
 	LogLevel  string `yaml:"log_level"`
 	Headless  bool   `yaml:"headless"`
+
+	// Container launches Chrome for a minimal container: no sandbox when
+	// running as root, and no reliance on a large /dev/shm
+	Container bool `yaml:"container"`
 	UserAgent string `yaml:"user_agent"`
+
+	// Language of console output and default templates: en, es or de
+	Language string `yaml:"language"`
+
+	// MetricsAddr serves /metrics (Prometheus text format) when set, e.g. ":9090"
+	MetricsAddr string `yaml:"metrics_addr"`
+
+	// Times Chrome is relaunched after a crash or lost connection before
+	// the run gives up (0 never relaunches)
+	BrowserRestarts int `yaml:"browser_restarts"`
+
+	// Hours ahead covered by the "schedule" command and endpoint
+	ScheduleHours int `yaml:"schedule_hours"`
+
+	// Jobs posted to the /queue endpoints are refused when they wouldn't
+	// run within this many hours
+	QueueHorizonHours int `yaml:"queue_horizon_hours"`
+
+	// APIAddr serves the queue API, whose endpoints change the queue, when
+	// set, e.g. "127.0.0.1:9091". Every request needs APIToken as a bearer
+	// token.
+	APIAddr  string `yaml:"api_addr"`
+	APIToken string `yaml:"api_token"`
+
+	// While this file exists nothing is sent, see the estop module
+	StopFile string `yaml:"stop_file"`
+
+	// Account to run as, one of accounts; its binding overrides proxy,
+	// time_zone, locale, user_agent, the persona and the viewport
+	Account string `yaml:"account"`
+
+	Proxy    string `yaml:"proxy"`     // Chrome's proxy, e.g. socks5://host:1080
+	TimeZone string `yaml:"time_zone"` // IANA zone of business hours; empty is the system's
+	Locale   string `yaml:"locale"`    // Browser locale, e.g. en-US; empty is Chrome's
+
+	// How the browser resolves host names; an account's dns replaces it
+	DNS DNSConfig `yaml:"dns"`
+
+	// Format of the IDs of new messages and profiles, one of IDFormats
+	IDFormat string `yaml:"id_format"`
+
+	// Extra headers sent with every request. Accept-Language and the
+	// sec-ch-ua client hints follow user_agent and locale instead.
+	Headers map[string]string `yaml:"headers"`
+}
+
+// FingerprintHeader reports whether a request header is derived from the
+// user agent and locale, and so can't be set on its own
+func FingerprintHeader(name string) bool {
+	name = strings.ToLower(name)
+	return name == "user-agent" || name == "accept-language" || strings.HasPrefix(name, "sec-ch-ua")
+}
+
+// ModulesConfig switches whole workflow steps on or off, e.g. to run
+// discovery only or to message already accepted connections only
+type ModulesConfig struct {
+	Search     bool `yaml:"search"`     // Discover new profiles
+	Connect    bool `yaml:"connect"`    // Send connection requests
+	Acceptance bool `yaml:"acceptance"` // Check pending requests for acceptance
+	Messaging  bool `yaml:"messaging"`  // Send follow-up messages
+}
+
+// Enabled lists the names of the enabled modules in workflow order
+func (m ModulesConfig) Enabled() []string {
+	var names []string
+	for _, mod := range []struct {
+		name string
+		on   bool
+	}{{"search", m.Search}, {"connect", m.Connect}, {"acceptance", m.Acceptance}, {"messaging", m.Messaging}} {
+		if mod.on {
+			names = append(names, mod.name)
+		}
+	}
+	return names
+}
+
+// Workflow ordering strategies, see the workflow module
+var WorkflowStrategies = []string{"sequential", "interleaved", "message_first"}
+
+// IDFormats are the ID formats of the ids module
+var IDFormats = []string{"ulid", "uuidv7"}
+
+// WorkflowConfig chooses the order in which a run takes the workflow steps
+type WorkflowConfig struct {
+	Strategy       string   `yaml:"strategy"`         // One of WorkflowStrategies
+	BatchSize      int      `yaml:"batch_size"`       // Requests and messages per interleaved round
+	MorningUntil   string   `yaml:"morning_until"`    // HH:MM; message_first only applies before
+	SearchOnlyDays []string `yaml:"search_only_days"` // Weekdays that only search, e.g. [monday]
+}
+
+// HooksConfig sets the commands run around key actions, see the hooks
+// module; empty commands don't run
+type HooksConfig struct {
+	CanSend        string `yaml:"can_send"`
+	OnAccepted     string `yaml:"on_accepted"`
+	MutateMessage  string `yaml:"mutate_message"`
+	TimeoutSeconds int    `yaml:"timeout_seconds"`
+}
+
+// NotificationsConfig chooses the events shown as desktop notifications
+// when running interactively
+type NotificationsConfig struct {
+	Desktop bool     `yaml:"desktop"`
+	Events  []string `yaml:"events"` // checkpoint, daily_summary
+}
+
+// ModalsConfig lists the cookie-consent and promotional modals dismissed
+// after each page load, see the modals module
+type ModalsConfig struct {
+	Enabled bool        `yaml:"enabled"`
+	Rules   []ModalRule `yaml:"rules"`
+}
+
+// ModalRule recognizes one kind of modal and says how to close it
+type ModalRule struct {
+	Name    string `yaml:"name"`
+	Detect  string `yaml:"detect"`  // Selector present while the modal shows
+	Dismiss string `yaml:"dismiss"` // Selector of the button that closes it
 }
 
 // StealthConfig contains anti-detection configuration
@@ -45,6 +202,12 @@ type StealthConfig struct {
 	ThinkTimeMin   int `yaml:"think_time_min"`   // Longer pauses simulating "thinking"
 	ThinkTimeMax   int `yaml:"think_time_max"`
 
+	// Gaps between bulk messages: log-normal around the median, with a
+	// heavier tail the larger the spread, capped at the max
+	MessageGapMedianSeconds int     `yaml:"message_gap_median_seconds"`
+	MessageGapSpread        float64 `yaml:"message_gap_spread"`
+	MessageGapMaxSeconds    int     `yaml:"message_gap_max_seconds"`
+
 	// Scrolling Behavior
 	ScrollEnabled      bool    `yaml:"scroll_enabled"`
 	ScrollChance       float64 `yaml:"scroll_chance"`        // Chance to scroll before action
@@ -59,6 +222,14 @@ type StealthConfig struct {
 	BreakTimeStart       string `yaml:"break_time_start"`
 	BreakTimeEnd         string `yaml:"break_time_end"`
 
+	// Longest manual work session outside business hours (0 disables them)
+	WorkSessionMaxMinutes int `yaml:"work_session_max_minutes"`
+
+	// Days off: weekends, the persona country's public holidays and an
+	// optional iCalendar file of extra days
+	SkipWeekends bool   `yaml:"skip_weekends"`
+	HolidayICS   string `yaml:"holiday_ics"`
+
 	// Fingerprint Masking
 	MaskWebDriver    bool `yaml:"mask_webdriver"`     // Hide webdriver flag
 	MaskChrome       bool `yaml:"mask_chrome"`        // Hide automation indicators
@@ -67,6 +238,84 @@ type StealthConfig struct {
 	ViewportWidthMax int  `yaml:"viewport_width_max"`
 	ViewportHeightMin int  `yaml:"viewport_height_min"`
 	ViewportHeightMax int  `yaml:"viewport_height_max"`
+
+	// devicePixelRatio of the screen; mouse positions land on its device
+	// pixel grid (1 for a standard display, 2 for most HiDPI ones)
+	DevicePixelRatio float64 `yaml:"device_pixel_ratio"`
+
+	// Input Persona
+	Persona  string                   `yaml:"persona"`  // Which of the personas below to act as
+	Personas map[string]PersonaConfig `yaml:"personas"` // Named input device profiles
+
+	// Record every delay, typo and curve of each connection request and
+	// message to <data_dir>/traces for comparing flagged runs with clean ones
+	TraceActions bool `yaml:"trace_actions"`
+
+	// Longest a connection request or message may take, pauses included,
+	// before it is abandoned, in seconds; 0 or absent leaves it unlimited
+	ActionTimeoutSeconds map[string]int `yaml:"action_timeout_seconds"`
+}
+
+// PersonaConfig describes the input hardware of the simulated user. Stealth
+// behaviour that depends on the device (scrolling, click timing, hover
+// events) follows it. Zero values fall back to the defaults for the device.
+type PersonaConfig struct {
+	InputDevice      string  `yaml:"input_device"`       // "mouse", "trackpad" or "touch"
+	WheelNotch       int     `yaml:"wheel_notch"`        // mouse: pixels per wheel notch
+	NotchIntervalMin int     `yaml:"notch_interval_min"` // mouse: ms between notches of one flick
+	NotchIntervalMax int     `yaml:"notch_interval_max"`
+	MomentumDecay    float64 `yaml:"momentum_decay"`     // trackpad/touch: share of velocity kept per frame after release
+	ClickDwellMin    int     `yaml:"click_dwell_min"`    // ms between press and release of a click or tap
+	ClickDwellMax    int     `yaml:"click_dwell_max"`
+	Country          string  `yaml:"country"`            // ISO code whose public holidays are days off ("" = none)
+	Keyboard         string  `yaml:"keyboard"`           // Layout and input method text is typed with, see Keyboards
+}
+
+// InputDevices lists the supported persona input devices
+var InputDevices = []string{"mouse", "trackpad", "touch"}
+
+// Keyboards lists the supported persona keyboards: Latin layouts with
+// their dead keys, Russian, and the Japanese, Chinese and Korean IMEs
+var Keyboards = []string{"us", "uk", "de", "fr", "es", "ru", "ja", "zh", "ko"}
+
+// Hovers reports whether the device moves a pointer between clicks. Touch
+// screens have no hover: the finger lands directly on the target.
+func (p PersonaConfig) Hovers() bool {
+	return p.InputDevice != "touch"
+}
+
+// ActivePersona returns the selected persona with device defaults filled in
+func (c StealthConfig) ActivePersona() PersonaConfig {
+	p := c.Personas[c.Persona]
+	if p.InputDevice == "" {
+		p.InputDevice = "mouse"
+	}
+	if p.Keyboard == "" {
+		p.Keyboard = "us"
+	}
+	if p.WheelNotch == 0 {
+		p.WheelNotch = 100
+	}
+	if p.NotchIntervalMin == 0 && p.NotchIntervalMax == 0 {
+		p.NotchIntervalMin, p.NotchIntervalMax = 30, 80
+	}
+	if p.MomentumDecay == 0 {
+		p.MomentumDecay = 0.93
+		if p.InputDevice == "touch" {
+			p.MomentumDecay = 0.95 // Flung pages glide further
+		}
+	}
+	if p.ClickDwellMin == 0 && p.ClickDwellMax == 0 {
+		switch p.InputDevice {
+		case "trackpad":
+			p.ClickDwellMin, p.ClickDwellMax = 30, 90 // Tap to click
+		case "touch":
+			p.ClickDwellMin, p.ClickDwellMax = 50, 120
+		default:
+			p.ClickDwellMin, p.ClickDwellMax = 60, 140
+		}
+	}
+	return p
 }
 
 // LimitsConfig enforces rate limiting and safety boundaries
@@ -74,8 +323,19 @@ type LimitsConfig struct {
 	ConnectionsPerDay  int `yaml:"connections_per_day"`
 	ConnectionsPerHour int `yaml:"connections_per_hour"`
 	MessagesPerDay     int `yaml:"messages_per_day"`
+	MessagesPerSession int `yaml:"messages_per_session"` // Per scheduled session, 0 for no cap
 	SearchesPerDay     int `yaml:"searches_per_day"`
 	CooldownMinutes    int `yaml:"cooldown_minutes"` // After daily limit reached
+
+	// Minimum time between two connection requests and two messages
+	ConnectionCooldownSeconds int `yaml:"connection_cooldown_seconds"`
+	MessageCooldownSeconds    int `yaml:"message_cooldown_seconds"`
+
+	// No profile gets more than MaxTouchesPerProfile connection requests
+	// and messages in any TouchWindowDays, whatever campaigns ask for; 0
+	// turns the guardrail off
+	MaxTouchesPerProfile int `yaml:"max_touches_per_profile"`
+	TouchWindowDays      int `yaml:"touch_window_days"`
 }
 
 // AuthConfig contains authentication-related settings
@@ -83,6 +343,29 @@ type AuthConfig struct {
 	SessionCookiePath string `yaml:"session_cookie_path"`
 	ReuseSession      bool   `yaml:"reuse_session"`
 	CheckpointRetries int    `yaml:"checkpoint_retries"`
+
+	// Which cookies the saved session keeps
+	Cookies CookieConfig `yaml:"cookies"`
+
+	// Which localStorage and sessionStorage items it keeps
+	WebStorage WebStorageConfig `yaml:"web_storage"`
+}
+
+// WebStorageConfig allow-lists the localStorage and sessionStorage keys
+// saved with the session, per account, as path.Match patterns. Empty saves
+// none.
+type WebStorageConfig struct {
+	Keys []string `yaml:"keys"`
+}
+
+// CookieConfig is the policy for persisting cookies. A cookie on one of
+// drop_domains (or a subdomain) or named like one of drop is never saved;
+// when keep is set, only cookies named like one of keep are. Names match
+// path.Match patterns, e.g. "_ga*".
+type CookieConfig struct {
+	Keep        []string `yaml:"keep"`
+	Drop        []string `yaml:"drop"`
+	DropDomains []string `yaml:"drop_domains"`
 }
 
 // SearchConfig contains search behavior settings
@@ -91,17 +374,369 @@ type SearchConfig struct {
 	MaxPages            int      `yaml:"max_pages"`
 	DeduplicationWindow int      `yaml:"deduplication_window"` // Days to remember seen profiles
 	DefaultKeywords     []string `yaml:"default_keywords"`
+
+	Discovery DiscoveryConfig `yaml:"discovery"`
 }
 
-// Load reads and parses the configuration file
-func Load(path string) (*Config, error) {
-	// Set defaults
-	cfg := &Config{
+// DiscoveryConfig decides how much of each search is harvested
+type DiscoveryConfig struct {
+	// "exhaustive" saves every result on every page; "sample" keeps only
+	// part of each page and skips some pages, leaving the rest for later
+	// sessions
+	Mode         string  `yaml:"mode"`
+	SampleRate   float64 `yaml:"sample_rate"`    // sample: fraction of a page's results kept
+	PageSkipRate float64 `yaml:"page_skip_rate"` // sample: chance of skipping a page entirely
+}
+
+// TargetingConfig decides which discovered profiles are worth contacting
+type TargetingConfig struct {
+	// Skip profiles with fewer mutual connections; profiles that were
+	// never enriched count as having none
+	MinMutualConnections int `yaml:"min_mutual_connections"`
+
+	// Only contact profiles located in one of these cities, regions or
+	// countries ("Munich", "Bavaria", "Germany"); empty contacts anywhere
+	Locations []string `yaml:"locations"`
+
+	// Only contact profiles whose title classifies into one of these
+	// seniorities (ic, manager, director, vp, c_level) and functions
+	// (engineering, data, product, design, sales, marketing, people,
+	// finance, operations, general, other); empty contacts all
+	Seniorities []string `yaml:"seniorities"`
+	Functions   []string `yaml:"functions"`
+
+	// Only contact profiles whose company is in one of these industries
+	// and size buckets (1-10, 11-50, 51-200, 201-500, 501-1000, 1001-5000,
+	// 5001-10000, 10001+), from enrichment; empty contacts all
+	Industries   []string `yaml:"industries"`
+	CompanySizes []string `yaml:"company_sizes"`
+
+	// Only contact profiles carrying one of these tags, e.g. "q3-outreach";
+	// empty contacts all. Applies to follow-up messages too.
+	Tags []string `yaml:"tags"`
+
+	// Rules in the targeting expression language, e.g.
+	// title ~= "engineer" && company_size > 50 && !tag:competitor
+	Include string      `yaml:"include"` // Only contact profiles matching this
+	Exclude string      `yaml:"exclude"` // Never contact profiles matching this
+	Boosts  []RuleBoost `yaml:"boosts"`  // Contact higher-scoring profiles first
+
+	// Campaigns sharing the connection budget; empty sends to candidates
+	// first-come-first-served
+	Campaigns []CampaignConfig `yaml:"campaigns"`
+}
+
+// CampaignConfig is a named set of candidates with its claim on the
+// connection budget. Higher priorities are served first; campaigns of equal
+// priority split the budget in proportion to their weights.
+type CampaignConfig struct {
+	Name     string  `yaml:"name"`
+	Match    string  `yaml:"match"` // Rule choosing the campaign's profiles
+	Priority int     `yaml:"priority"`
+	Weight   float64 `yaml:"weight"`
+}
+
+// RuleBoost adds Score to the priority of profiles matching When
+type RuleBoost struct {
+	When  string `yaml:"when"`
+	Score int    `yaml:"score"`
+}
+
+// TemplateRule selects Template for profiles matching When
+type TemplateRule struct {
+	When     string `yaml:"when"`
+	Template string `yaml:"template"`
+}
+
+// EnrichmentConfig selects where company metadata comes from
+type EnrichmentConfig struct {
+	// "none", "csv" or "http"
+	Provider string `yaml:"provider"`
+
+	// csv: file with a header row naming company, industry and size columns
+	CSVPath string `yaml:"csv_path"`
+
+	// http: GET <url>?company=<name> (or {company} in the URL), answering
+	// JSON {"industry", "size" or "employees"}; the bearer token is read
+	// from the api_key_env environment variable
+	URL            string `yaml:"url"`
+	APIKeyEnv      string `yaml:"api_key_env"`
+	TimeoutSeconds int    `yaml:"timeout_seconds"`
+
+	// Look cached companies up again after this many days; 0 never does
+	CacheDays int `yaml:"cache_days"`
+}
+
+// APIKey returns the HTTP provider's token from the environment
+func (e EnrichmentConfig) APIKey() string {
+	if e.APIKeyEnv == "" {
+		return ""
+	}
+	return os.Getenv(e.APIKeyEnv)
+}
+
+// ReviewConfig controls the review queue for discovered profiles
+type ReviewConfig struct {
+	// Only send connection requests to profiles approved with
+	// "subspace review"; unreviewed profiles wait in the queue
+	RequireApproval bool `yaml:"require_approval"`
+}
+
+// MessagingConfig controls when follow-up messages reach recipients
+type MessagingConfig struct {
+	// Only send while it is daytime for the recipient, using the time zone
+	// inferred from their profile location
+	RecipientHoursEnabled bool   `yaml:"recipient_hours_enabled"`
+	RecipientHoursStart   string `yaml:"recipient_hours_start"`
+	RecipientHoursEnd     string `yaml:"recipient_hours_end"`
+
+	// Zone assumed when a location can't be resolved; empty sends anytime
+	DefaultTimeZone string `yaml:"default_time_zone"`
+
+	// Extra location keyword -> IANA zone entries, e.g. "greater boston": America/New_York
+	LocationTimeZones map[string]string `yaml:"location_time_zones"`
+
+	// Warn when more than this many identical messages would go out within
+	// the window; 0 disables the warning. Exact repeats to one person are
+	// always blocked.
+	DuplicateWarnCount   int `yaml:"duplicate_warn_count"`
+	DuplicateWindowHours int `yaml:"duplicate_window_hours"`
+
+	// How URLs in messages are entered: "paste" or "type"
+	LinkInsertion string `yaml:"link_insertion"`
+
+	// Per-template attachments and links, keyed by template name
+	Templates map[string]TemplateOptions `yaml:"templates"`
+
+	// Template used instead of the follow-up for profiles matching a rule;
+	// the first match wins and takes precedence over segment_templates
+	TemplateRules []TemplateRule `yaml:"template_rules"`
+
+	// Template used instead of the follow-up for a title segment, keyed by
+	// seniority, function or "seniority/function" (most specific wins)
+	SegmentTemplates map[string]string `yaml:"segment_templates"`
+
+	// Extra template variables naming an existing one, e.g. City: Location
+	Variables map[string]string `yaml:"variables"`
+
+	// Who messages go out from, for {{.Sender.Name}} in templates
+	Sender SenderConfig `yaml:"sender"`
+}
+
+// SenderConfig is the identity of the operator sending messages, so
+// templates shared by several operators don't hard-code one name. Accounts
+// can override it field by field.
+type SenderConfig struct {
+	Name         string `yaml:"name"`
+	Company      string `yaml:"company"`
+	Role         string `yaml:"role"`          // e.g. "Engineering Manager"
+	Signature    string `yaml:"signature"`     // Closing block, may span lines
+	CalendlyLink string `yaml:"calendly_link"` // Booking page, e.g. https://calendly.com/jane
+}
+
+// maxSignature is the longest signature accepted, well within a message
+const maxSignature = 500
+
+// merge returns the identity with the fields set in override replacing its own
+func (s SenderConfig) merge(override SenderConfig) SenderConfig {
+	for _, f := range []struct{ dst, src *string }{
+		{&s.Name, &override.Name},
+		{&s.Company, &override.Company},
+		{&s.Role, &override.Role},
+		{&s.Signature, &override.Signature},
+		{&s.CalendlyLink, &override.CalendlyLink},
+	} {
+		if *f.src != "" {
+			*f.dst = *f.src
+		}
+	}
+	return s
+}
+
+// validate checks that the identity renders cleanly into a message
+func (s SenderConfig) validate() error {
+	fields := []struct{ key, value string }{
+		{"name", s.Name}, {"company", s.Company}, {"role", s.Role},
+		{"signature", s.Signature}, {"calendly_link", s.CalendlyLink},
+	}
+	for _, f := range fields {
+		if strings.Contains(f.value, "{{") {
+			return fmt.Errorf("%s cannot contain template actions", f.key)
+		}
+		if f.key != "signature" && strings.ContainsAny(f.value, "\r\n") {
+			return fmt.Errorf("%s must be a single line", f.key)
+		}
+	}
+	if len([]rune(s.Signature)) > maxSignature {
+		return fmt.Errorf("signature is longer than %d characters", maxSignature)
+	}
+	if s.CalendlyLink != "" {
+		if u, err := url.Parse(s.CalendlyLink); err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("calendly_link must be an https URL")
+		}
+	}
+	return nil
+}
+
+// TemplateOptions extends one message template
+type TemplateOptions struct {
+	Attachments   []string `yaml:"attachments"`    // Files uploaded with the message
+	Link          string   `yaml:"link"`           // Value of {{.Link}}
+	LinkInsertion string   `yaml:"link_insertion"` // Overrides messaging.link_insertion
+}
+
+// templateVariable matches a message template variable name
+var templateVariable = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// LinkMode returns how URLs are entered for a template
+func (m MessagingConfig) LinkMode(template string) string {
+	if mode := m.Templates[template].LinkInsertion; mode != "" {
+		return mode
+	}
+	return m.LinkInsertion
+}
+
+// RetentionConfig controls how long each record type is kept before the
+// maintenance job purges it. A value of 0 keeps records forever.
+type RetentionConfig struct {
+	ProfilesDays    int  `yaml:"profiles_days"`
+	MessagesDays    int  `yaml:"messages_days"`
+	ActionLogsDays  int  `yaml:"action_logs_days"`
+	ScreenshotsDays int  `yaml:"screenshots_days"`
+	TracesDays      int  `yaml:"traces_days"`
+	RunOnStart      bool `yaml:"run_on_start"` // Purge before each automation run
+}
+
+// StorageConfig contains persistence tuning
+type StorageConfig struct {
+	// Warn when a single db.json rewrite takes longer than this.
+	// Persistent warnings are a sign the JSON file backend has outgrown the data.
+	SlowWriteThresholdMs int `yaml:"slow_write_threshold_ms"`
+
+	// Also treat profiles with different URLs as duplicates when the parsed
+	// name and company match (catches changed vanity URLs)
+	FuzzyDedup bool `yaml:"fuzzy_dedup"`
+
+	// Write routine changes (profiles found, action log entries) to db.json
+	// at most this often instead of rewriting it for each; sent requests
+	// and messages are always written at once. 0 writes every change.
+	FlushIntervalMs int `yaml:"flush_interval_ms"`
+
+	// Backend keeps the profiles, messages and action log of runs: "file"
+	// (db.json in the data directory) or "postgres", which several machines
+	// and accounts can share
+	Backend  string         `yaml:"backend"`
+	Postgres PostgresConfig `yaml:"postgres"`
+
+	// Encrypts db.json, the action log and the saved session on disk
+	Encryption EncryptionConfig `yaml:"encryption"`
+}
+
+// EncryptionConfig keys the at-rest encryption of the data files. The key
+// is 32 random bytes, base64 or hex encoded, read from the key_env
+// variable or, if that is unset, from key_file.
+type EncryptionConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	KeyEnv  string `yaml:"key_env"`  // e.g. SUBSPACE_ENCRYPTION_KEY
+	KeyFile string `yaml:"key_file"` // Should be readable by the owner only
+}
+
+// PostgresConfig locates the shared database and sizes its connection pool
+type PostgresConfig struct {
+	URL    string `yaml:"url"`    // e.g. postgres://subspace:secret@db:5432/subspace
+	Driver string `yaml:"driver"` // database/sql driver name; the binary registers "pgx"
+
+	MaxOpenConns           int `yaml:"max_open_conns"`
+	MaxIdleConns           int `yaml:"max_idle_conns"`
+	ConnMaxLifetimeMinutes int `yaml:"conn_max_lifetime_minutes"` // 0 keeps connections
+}
+
+// WatchdogConfig sets the resource limits checked between workflow steps.
+// Exceeding a browser limit recycles Chrome; exceeding the heap limit
+// compacts storage and returns freed memory to the OS. 0 disables a limit.
+type WatchdogConfig struct {
+	Enabled         bool `yaml:"enabled"`
+	MaxBrowserRSSMB int  `yaml:"max_browser_rss_mb"` // Resident memory of the Chrome browser process
+	MaxPages        int  `yaml:"max_pages"`          // Open tabs and pages
+	MaxHeapMB       int  `yaml:"max_heap_mb"`        // Go heap in use
+}
+
+// BandwidthConfig meters the browser's network traffic per run, for
+// budgeting bandwidth-billed proxies
+type BandwidthConfig struct {
+	Enabled   bool    `yaml:"enabled"`
+	CostPerGB float64 `yaml:"cost_per_gb"` // Proxy price per GB, for cost estimates (0 = unpriced)
+}
+
+// HardeningConfig restricts the data directories to the user running
+// subspace: files 0600, directories 0700, ownership checked at startup
+type HardeningConfig struct {
+	Enabled        bool `yaml:"enabled"`
+	RefuseInsecure bool `yaml:"refuse_insecure"` // Stop instead of fixing a group/world-readable data dir
+}
+
+// SimulationConfig controls how the PoC simulates the other side of the
+// network, e.g. which connection requests get accepted and when
+type SimulationConfig struct {
+	AcceptanceModel   string             `yaml:"acceptance_model"`    // "fixed" or "seniority"
+	AcceptanceRate    float64            `yaml:"acceptance_rate"`     // fixed: probability per check
+	SeniorityRates    map[string]float64 `yaml:"seniority_rates"`     // seniority: junior/mid/senior/executive -> probability
+	MedianAcceptHours float64            `yaml:"median_accept_hours"` // seniority: median time to accept
+	AcceptSpread      float64            `yaml:"accept_spread"`       // seniority: log-normal sigma of time to accept
+	Seed              int64              `yaml:"seed"`                // 0 = random each run
+
+	// Failures injected into the simulated browser of bench runs
+	Faults FaultsConfig `yaml:"faults"`
+}
+
+// FaultsConfig controls the chaos mode of simulation runs. Probabilities
+// apply per element lookup, navigation and session check respectively.
+type FaultsConfig struct {
+	Enabled               bool    `yaml:"enabled"`
+	ElementNotFound       float64 `yaml:"element_not_found"`
+	SlowNavigation        float64 `yaml:"slow_navigation"`
+	SlowNavigationSeconds int     `yaml:"slow_navigation_seconds"` // Extra load time of a slow navigation
+	DroppedCookies        float64 `yaml:"dropped_cookies"`
+}
+
+// Defaults returns the configuration used for any setting a file leaves out
+func Defaults() *Config {
+	return &Config{
 		App: AppConfig{
-			DataDir:   "./data",
-			LogLevel:  "info",
-			Headless:  false,
-			UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			DataDir:         "./data",
+			LogLevel:        "info",
+			Language:        "en",
+			Headless:        false,
+			BrowserRestarts: 3,
+			ScheduleHours:   24,
+			StopFile:        "./data/STOP",
+			DNS:             DNSConfig{Mode: "system"},
+			IDFormat:        "ulid",
+			UserAgent:       "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+
+			QueueHorizonHours: 72,
+		},
+		Modules: ModulesConfig{
+			Search:     true,
+			Connect:    true,
+			Acceptance: true,
+			Messaging:  true,
+		},
+		Workflow: WorkflowConfig{
+			Strategy:     "sequential",
+			BatchSize:    5,
+			MorningUntil: "12:00",
+		},
+		Hooks: HooksConfig{TimeoutSeconds: 5},
+		Notifications: NotificationsConfig{
+			Events: []string{"checkpoint", "daily_summary"},
+		},
+		Modals: ModalsConfig{
+			Rules: []ModalRule{
+				{Name: "cookie_consent", Detect: "#onetrust-banner-sdk", Dismiss: "#onetrust-reject-all-handler"},
+				{Name: "cookie_notice", Detect: "#artdeco-global-alert-container", Dismiss: "#artdeco-global-alert-container button[action-type='DENY']"},
+				{Name: "promotion", Detect: ".artdeco-modal[role='dialog']", Dismiss: ".artdeco-modal__dismiss"},
+			},
 		},
 		Stealth: StealthConfig{
 			MouseSpeed:            300.0,
@@ -125,6 +760,8 @@ func Load(path string) (*Config, error) {
 			BreakTimeEnabled:      true,
 			BreakTimeStart:        "12:00",
 			BreakTimeEnd:          "13:00",
+			WorkSessionMaxMinutes: 120,
+			SkipWeekends:          true,
 			MaskWebDriver:         true,
 			MaskChrome:            true,
 			RandomViewport:        true,
@@ -132,36 +769,170 @@ func Load(path string) (*Config, error) {
 			ViewportWidthMax:      1920,
 			ViewportHeightMin:     800,
 			ViewportHeightMax:     1080,
+			Persona:               "desktop",
+			Personas: map[string]PersonaConfig{
+				"desktop": {InputDevice: "mouse"},
+				"laptop":  {InputDevice: "trackpad"},
+				"tablet":  {InputDevice: "touch"},
+			},
+			TraceActions: true,
+
+			MessageGapMedianSeconds: 240,
+			MessageGapSpread:        0.6,
+			MessageGapMaxSeconds:    1200,
+
+			DevicePixelRatio: 1,
+
+			ActionTimeoutSeconds: map[string]int{"connection": 180, "message": 300},
 		},
 		Limits: LimitsConfig{
 			ConnectionsPerDay:  50,
 			ConnectionsPerHour: 10,
 			MessagesPerDay:     30,
+			MessagesPerSession: 12,
 			SearchesPerDay:     20,
 			CooldownMinutes:    60,
+
+			ConnectionCooldownSeconds: 30,
+			MessageCooldownSeconds:    60,
+
+			MaxTouchesPerProfile: 5,
+			TouchWindowDays:      30,
 		},
 		Auth: AuthConfig{
 			SessionCookiePath: "./data/session.json",
 			ReuseSession:      true,
 			CheckpointRetries: 3,
+			Cookies: CookieConfig{
+				Drop:        []string{"_ga*", "_gid", "_gcl_*", "__utm*", "_fbp", "_uet*", "IDE", "test_cookie"},
+				DropDomains: []string{"doubleclick.net", "google-analytics.com", "ads.linkedin.com"},
+			},
 		},
 		Search: SearchConfig{
 			ResultsPerPage:      25,
 			MaxPages:            10,
 			DeduplicationWindow: 30,
 			DefaultKeywords:     []string{"software engineer", "golang developer"},
+			Discovery: DiscoveryConfig{
+				Mode:         "exhaustive",
+				SampleRate:   0.6,
+				PageSkipRate: 0.3,
+			},
+		},
+		Enrichment: EnrichmentConfig{
+			Provider:       "none",
+			APIKeyEnv:      "ENRICHMENT_API_KEY",
+			TimeoutSeconds: 10,
+			CacheDays:      90,
+		},
+		Messaging: MessagingConfig{
+			RecipientHoursEnabled: true,
+			RecipientHoursStart:   "09:00",
+			RecipientHoursEnd:     "18:00",
+			DuplicateWarnCount:    5,
+			DuplicateWindowHours:  24,
+			LinkInsertion:         "paste",
+		},
+		Retention: RetentionConfig{
+			ProfilesDays:    0,
+			MessagesDays:    365,
+			ActionLogsDays:  90,
+			ScreenshotsDays: 30,
+			TracesDays:      30,
+			RunOnStart:      true,
+		},
+		Storage: StorageConfig{
+			SlowWriteThresholdMs: 200,
+			FlushIntervalMs:      2000,
+			Backend:              "file",
+			Postgres: PostgresConfig{
+				Driver:                 "pgx",
+				MaxOpenConns:           10,
+				MaxIdleConns:           5,
+				ConnMaxLifetimeMinutes: 30,
+			},
+
+			Encryption: EncryptionConfig{KeyEnv: "SUBSPACE_ENCRYPTION_KEY"},
+		},
+		Watchdog: WatchdogConfig{
+			Enabled:         true,
+			MaxBrowserRSSMB: 1500,
+			MaxPages:        5,
+			MaxHeapMB:       512,
+		},
+		Bandwidth: BandwidthConfig{
+			Enabled:   true,
+			CostPerGB: 0,
+		},
+		ProxyCheck: ProxyCheckConfig{
+			Enabled:         false,
+			URL:             "https://ipinfo.io/json",
+			TimeoutSeconds:  10,
+			MaxLatencyMs:    3000,
+			IntervalMinutes: 15,
+		},
+		ProxySession: ProxySessionConfig{
+			Policy:           "off",
+			RotateHours:      24,
+			UsernameTemplate: "{user}-session-{session}",
+		},
+		Simulation: SimulationConfig{
+			AcceptanceModel: "fixed",
+			AcceptanceRate:  0.2,
+			SeniorityRates: map[string]float64{
+				"junior":    0.45,
+				"mid":       0.35,
+				"senior":    0.25,
+				"executive": 0.10,
+			},
+			MedianAcceptHours: 18,
+			AcceptSpread:      1.0,
+			Faults: FaultsConfig{
+				ElementNotFound:       0.02,
+				SlowNavigation:        0.05,
+				SlowNavigationSeconds: 20,
+				DroppedCookies:        0.01,
+			},
 		},
 	}
+}
 
-	// Override with file if exists
+// Load reads and parses the configuration file. A missing file yields the
+// defaults.
+func Load(path string) (*Config, error) {
+	var data []byte
 	if _, err := os.Stat(path); err == nil {
-		data, err := os.ReadFile(path)
+		data, err = os.ReadFile(path)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read config file: %w", err)
 		}
+	}
+	return Parse(data)
+}
+
+// Parse applies YAML on top of the defaults and validates the result.
+// Unknown keys and extra documents are rejected so that a typo can't
+// silently fall back to a default value.
+func Parse(data []byte) (*Config, error) {
+	return parse(data, nil)
+}
+
+// parse is Parse with overrides applied before validation
+func parse(data []byte, override func(*Config) error) (*Config, error) {
+	cfg := Defaults()
 
-		if err := yaml.Unmarshal(data, cfg); err != nil {
-			return nil, fmt.Errorf("failed to parse config file: %w", err)
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	var extra yaml.Node
+	if err := dec.Decode(&extra); err != io.EOF {
+		return nil, fmt.Errorf("failed to parse config file: expected a single YAML document")
+	}
+	if override != nil {
+		if err := override(cfg); err != nil {
+			return nil, err
 		}
 	}
 
@@ -169,6 +940,7 @@ func Load(path string) (*Config, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
 	}
+	cfg.applyAccount()
 
 	return cfg, nil
 }
@@ -180,6 +952,74 @@ func (c *Config) Validate() error {
 	if !validLevels[c.App.LogLevel] {
 		return fmt.Errorf("invalid log level: %s (must be debug, info, warn, or error)", c.App.LogLevel)
 	}
+	if !slices.Contains(IDFormats, c.App.IDFormat) {
+		return fmt.Errorf("invalid app.id_format: %q (must be one of %s)", c.App.IDFormat, strings.Join(IDFormats, ", "))
+	}
+	if err := validateWorkflow(c.Workflow); err != nil {
+		return err
+	}
+	if c.Hooks.TimeoutSeconds <= 0 {
+		return fmt.Errorf("hooks.timeout_seconds must be positive")
+	}
+	if err := validateStorage(c.Storage); err != nil {
+		return err
+	}
+	modals := map[string]bool{}
+	for i, r := range c.Modals.Rules {
+		if r.Name == "" || r.Detect == "" || r.Dismiss == "" {
+			return fmt.Errorf("modals.rules[%d] needs a name, detect and dismiss selector", i)
+		}
+		if modals[r.Name] {
+			return fmt.Errorf("modals.rules: %s is listed twice", r.Name)
+		}
+		modals[r.Name] = true
+	}
+	for _, event := range c.Notifications.Events {
+		if event != "checkpoint" && event != "daily_summary" {
+			return fmt.Errorf("invalid notifications.events entry: %q (must be checkpoint or daily_summary)", event)
+		}
+	}
+
+	for name := range c.App.Headers {
+		if FingerprintHeader(name) {
+			return fmt.Errorf("app.headers: %s follows user_agent and locale and can't be set", name)
+		}
+	}
+
+	if c.App.BrowserRestarts < 0 {
+		return fmt.Errorf("browser_restarts cannot be negative")
+	}
+	if c.App.ScheduleHours < 1 || c.App.ScheduleHours > 168 {
+		return fmt.Errorf("schedule_hours must be between 1 and 168")
+	}
+	if c.App.QueueHorizonHours < 1 || c.App.QueueHorizonHours > 720 {
+		return fmt.Errorf("queue_horizon_hours must be between 1 and 720")
+	}
+	if c.App.APIAddr != "" {
+		if len(c.App.APIToken) < 16 {
+			return fmt.Errorf("app.api_token must be set, at least 16 characters, to serve the queue API")
+		}
+		if c.App.APIAddr == c.App.MetricsAddr {
+			return fmt.Errorf("app.api_addr must differ from app.metrics_addr")
+		}
+	}
+
+	// Validate language
+	if !i18n.Supported(c.App.Language) {
+		return fmt.Errorf("invalid language: %s (must be one of %v)", c.App.Language, i18n.Languages())
+	}
+
+	for _, patterns := range [][]string{c.Auth.Cookies.Keep, c.Auth.Cookies.Drop, c.Auth.WebStorage.Keys} {
+		for _, p := range patterns {
+			if _, err := path.Match(p, ""); err != nil {
+				return fmt.Errorf("invalid auth pattern: %s", p)
+			}
+		}
+	}
+
+	if len(c.Modules.Enabled()) == 0 {
+		return fmt.Errorf("all modules are disabled; enable at least one under modules")
+	}
 
 	// Validate business hours format
 	if c.Stealth.BusinessHoursEnabled {
@@ -191,6 +1031,36 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.Stealth.WorkSessionMaxMinutes < 0 || c.Stealth.WorkSessionMaxMinutes > 12*60 {
+		return fmt.Errorf("work_session_max_minutes must be between 0 and 720")
+	}
+
+	if err := c.validateAccounts(); err != nil {
+		return err
+	}
+
+	// Validate persona
+	if _, ok := c.Stealth.Personas[c.Stealth.Persona]; !ok {
+		return fmt.Errorf("unknown persona: %s (define it under stealth.personas)", c.Stealth.Persona)
+	}
+	for name, p := range c.Stealth.Personas {
+		if p.InputDevice != "" && p.InputDevice != "mouse" && p.InputDevice != "trackpad" && p.InputDevice != "touch" {
+			return fmt.Errorf("invalid personas.%s.input_device: %s (must be one of %v)", name, p.InputDevice, InputDevices)
+		}
+		if p.Keyboard != "" && !slices.Contains(Keyboards, p.Keyboard) {
+			return fmt.Errorf("invalid personas.%s.keyboard: %s (must be one of %v)", name, p.Keyboard, Keyboards)
+		}
+		if p.WheelNotch < 0 || p.NotchIntervalMin < 0 || p.NotchIntervalMax < p.NotchIntervalMin {
+			return fmt.Errorf("personas.%s: wheel_notch and notch intervals must be non-negative with min <= max", name)
+		}
+		if p.ClickDwellMin < 0 || p.ClickDwellMax < p.ClickDwellMin {
+			return fmt.Errorf("personas.%s: click dwell must be non-negative with min <= max", name)
+		}
+		if p.MomentumDecay < 0 || p.MomentumDecay >= 1 {
+			return fmt.Errorf("personas.%s.momentum_decay must be between 0 and 1", name)
+		}
+	}
+
 	// Validate limits
 	if c.Limits.ConnectionsPerDay <= 0 || c.Limits.ConnectionsPerDay > 100 {
 		return fmt.Errorf("connections_per_day must be between 1 and 100")
@@ -198,10 +1068,324 @@ func (c *Config) Validate() error {
 	if c.Limits.ConnectionsPerHour > c.Limits.ConnectionsPerDay {
 		return fmt.Errorf("connections_per_hour cannot exceed connections_per_day")
 	}
+	if c.Limits.ConnectionCooldownSeconds < 0 || c.Limits.MessageCooldownSeconds < 0 {
+		return fmt.Errorf("connection and message cooldowns cannot be negative")
+	}
+	if c.Limits.MessagesPerSession < 0 {
+		return fmt.Errorf("messages_per_session cannot be negative")
+	}
+	if c.Stealth.MessageGapMedianSeconds <= 0 || c.Stealth.MessageGapSpread < 0 || c.Stealth.MessageGapMaxSeconds < c.Stealth.MessageGapMedianSeconds {
+		return fmt.Errorf("message gaps need a positive median, a non-negative spread and a max of at least the median")
+	}
+	for action, seconds := range c.Stealth.ActionTimeoutSeconds {
+		if action != "connection" && action != "message" {
+			return fmt.Errorf("stealth.action_timeout_seconds: unknown action %q (connection or message)", action)
+		}
+		if seconds < 0 {
+			return fmt.Errorf("stealth.action_timeout_seconds.%s cannot be negative", action)
+		}
+	}
+	if c.Stealth.DevicePixelRatio <= 0 || c.Stealth.DevicePixelRatio > 4 {
+		return fmt.Errorf("stealth.device_pixel_ratio must be above 0 and at most 4")
+	}
+	if c.Limits.MaxTouchesPerProfile < 0 {
+		return fmt.Errorf("max_touches_per_profile cannot be negative (use 0 for no touch limit)")
+	}
+	if c.Limits.MaxTouchesPerProfile > 0 && c.Limits.TouchWindowDays <= 0 {
+		return fmt.Errorf("touch_window_days must be positive")
+	}
+
+	if c.Targeting.MinMutualConnections < 0 {
+		return fmt.Errorf("min_mutual_connections cannot be negative")
+	}
+	for _, location := range c.Targeting.Locations {
+		if geo.Lookup(location) == nil {
+			return fmt.Errorf("unknown targeting location: %s (must be a city, region or country)", location)
+		}
+	}
+	for _, s := range c.Targeting.Seniorities {
+		if !slices.Contains(taxonomy.Seniorities, s) {
+			return fmt.Errorf("invalid targeting seniority: %s", s)
+		}
+	}
+	for _, f := range c.Targeting.Functions {
+		if !slices.Contains(taxonomy.Functions, f) {
+			return fmt.Errorf("invalid targeting function: %s", f)
+		}
+	}
+	for _, industry := range c.Targeting.Industries {
+		if industry == "" {
+			return fmt.Errorf("targeting industries cannot contain an empty name")
+		}
+	}
+	for _, tag := range c.Targeting.Tags {
+		if tag == "" || strings.Trim(strings.ToLower(tag), "abcdefghijklmnopqrstuvwxyz0123456789_-") != "" {
+			return fmt.Errorf("invalid targeting tag %q: use letters, digits, _ and -", tag)
+		}
+	}
+	for _, size := range c.Targeting.CompanySizes {
+		if !slices.Contains(taxonomy.CompanySizes, size) {
+			return fmt.Errorf("invalid targeting company size: %s", size)
+		}
+	}
+
+	for _, expr := range []string{c.Targeting.Include, c.Targeting.Exclude} {
+		if expr == "" {
+			continue
+		}
+		if _, err := rules.Compile(expr); err != nil {
+			return fmt.Errorf("targeting: %w", err)
+		}
+	}
+	for _, b := range c.Targeting.Boosts {
+		if _, err := rules.Compile(b.When); err != nil {
+			return fmt.Errorf("targeting boosts: %w", err)
+		}
+	}
+	campaigns := make(map[string]bool)
+	for _, cp := range c.Targeting.Campaigns {
+		if cp.Name == "" || cp.Name == "(none)" || campaigns[cp.Name] {
+			return fmt.Errorf("targeting campaigns: name %q is empty, reserved or used twice", cp.Name)
+		}
+		campaigns[cp.Name] = true
+		if _, err := rules.Compile(cp.Match); err != nil {
+			return fmt.Errorf("targeting campaign %s: %w", cp.Name, err)
+		}
+		if cp.Weight <= 0 {
+			return fmt.Errorf("targeting campaign %s: weight must be positive", cp.Name)
+		}
+	}
+	for _, t := range c.Messaging.TemplateRules {
+		if _, err := rules.Compile(t.When); err != nil {
+			return fmt.Errorf("template_rules: %w", err)
+		}
+		if t.Template == "" {
+			return fmt.Errorf("template_rules %q: template name is empty", t.When)
+		}
+	}
+
+	e := c.Enrichment
+	switch e.Provider {
+	case "none":
+	case "csv":
+		if e.CSVPath == "" {
+			return fmt.Errorf("enrichment csv_path is required for the csv provider")
+		}
+	case "http":
+		if e.URL == "" {
+			return fmt.Errorf("enrichment url is required for the http provider")
+		}
+		if e.TimeoutSeconds <= 0 {
+			return fmt.Errorf("enrichment timeout_seconds must be positive")
+		}
+	default:
+		return fmt.Errorf("invalid enrichment provider: %s (must be none, csv or http)", e.Provider)
+	}
+	if e.CacheDays < 0 {
+		return fmt.Errorf("enrichment cache_days cannot be negative")
+	}
+
+	for segment, template := range c.Messaging.SegmentTemplates {
+		if !taxonomy.ValidSegment(segment) {
+			return fmt.Errorf("invalid segment_templates key: %s (must be a seniority, function or seniority/function)", segment)
+		}
+		if template == "" {
+			return fmt.Errorf("segment_templates %s: template name is empty", segment)
+		}
+	}
+
+	if c.Search.MaxPages <= 0 || c.Search.ResultsPerPage <= 0 {
+		return fmt.Errorf("search max_pages and results_per_page must be positive")
+	}
+	if c.Limits.SearchesPerDay <= 0 {
+		return fmt.Errorf("searches_per_day must be positive")
+	}
+
+	d := c.Search.Discovery
+	if d.Mode != "exhaustive" && d.Mode != "sample" {
+		return fmt.Errorf("invalid discovery mode: %s (must be exhaustive or sample)", d.Mode)
+	}
+	if d.SampleRate <= 0 || d.SampleRate > 1 {
+		return fmt.Errorf("discovery sample_rate must be in (0, 1]")
+	}
+	if d.PageSkipRate < 0 || d.PageSkipRate >= 1 {
+		return fmt.Errorf("discovery page_skip_rate must be in [0, 1)")
+	}
+
+	// Validate recipient hours
+	m := c.Messaging
+	if m.RecipientHoursEnabled {
+		if _, err := time.Parse("15:04", m.RecipientHoursStart); err != nil {
+			return fmt.Errorf("invalid recipient_hours_start format: %s (use HH:MM)", m.RecipientHoursStart)
+		}
+		if _, err := time.Parse("15:04", m.RecipientHoursEnd); err != nil {
+			return fmt.Errorf("invalid recipient_hours_end format: %s (use HH:MM)", m.RecipientHoursEnd)
+		}
+	}
+	if m.DefaultTimeZone != "" {
+		if _, err := time.LoadLocation(m.DefaultTimeZone); err != nil {
+			return fmt.Errorf("invalid default_time_zone: %s", m.DefaultTimeZone)
+		}
+	}
+	if m.DuplicateWarnCount < 0 || m.DuplicateWindowHours < 0 {
+		return fmt.Errorf("duplicate_warn_count and duplicate_window_hours cannot be negative")
+	}
+	validInsertion := map[string]bool{"paste": true, "type": true}
+	if !validInsertion[m.LinkInsertion] {
+		return fmt.Errorf("invalid link_insertion: %s (must be paste or type)", m.LinkInsertion)
+	}
+	for name, t := range m.Templates {
+		if t.LinkInsertion != "" && !validInsertion[t.LinkInsertion] {
+			return fmt.Errorf("invalid templates.%s.link_insertion: %s (must be paste or type)", name, t.LinkInsertion)
+		}
+		for _, path := range t.Attachments {
+			info, err := os.Stat(path)
+			if err != nil {
+				return fmt.Errorf("templates.%s: attachment %s: %w", name, path, err)
+			}
+			if !info.Mode().IsRegular() {
+				return fmt.Errorf("templates.%s: attachment %s is not a regular file", name, path)
+			}
+		}
+	}
+	for alias, field := range m.Variables {
+		if !templateVariable.MatchString(alias) || !templateVariable.MatchString(field) {
+			return fmt.Errorf("invalid template variable %s: %s (names must be letters, digits and _)", alias, field)
+		}
+	}
+	if err := m.Sender.validate(); err != nil {
+		return fmt.Errorf("messaging.sender: %w", err)
+	}
+	for keyword, zone := range m.LocationTimeZones {
+		if _, err := time.LoadLocation(zone); err != nil {
+			return fmt.Errorf("invalid location_time_zones.%s: %s", keyword, zone)
+		}
+	}
+
+	// Validate retention
+	r := c.Retention
+	if r.ProfilesDays < 0 || r.MessagesDays < 0 || r.ActionLogsDays < 0 || r.ScreenshotsDays < 0 || r.TracesDays < 0 {
+		return fmt.Errorf("retention periods cannot be negative (use 0 to keep forever)")
+	}
+	// Purged entries no longer count as touches
+	if r.ActionLogsDays > 0 && c.Limits.MaxTouchesPerProfile > 0 && r.ActionLogsDays < c.Limits.TouchWindowDays {
+		return fmt.Errorf("retention.action_logs_days (%d) must cover touch_window_days (%d)", r.ActionLogsDays, c.Limits.TouchWindowDays)
+	}
+
+	if w := c.Watchdog; w.MaxBrowserRSSMB < 0 || w.MaxPages < 0 || w.MaxHeapMB < 0 {
+		return fmt.Errorf("watchdog limits cannot be negative (use 0 to disable)")
+	}
+	if c.Bandwidth.CostPerGB < 0 {
+		return fmt.Errorf("bandwidth.cost_per_gb cannot be negative")
+	}
+
+	// Validate simulation
+	sim := c.Simulation
+	if sim.AcceptanceModel != "fixed" && sim.AcceptanceModel != "seniority" {
+		return fmt.Errorf("invalid acceptance_model: %s (must be fixed or seniority)", sim.AcceptanceModel)
+	}
+	if sim.AcceptanceRate < 0 || sim.AcceptanceRate > 1 {
+		return fmt.Errorf("acceptance_rate must be between 0 and 1")
+	}
+	for level, rate := range sim.SeniorityRates {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("seniority_rates.%s must be between 0 and 1", level)
+		}
+	}
+	if sim.MedianAcceptHours <= 0 || sim.AcceptSpread < 0 {
+		return fmt.Errorf("median_accept_hours must be positive and accept_spread non-negative")
+	}
+	f := sim.Faults
+	for name, p := range map[string]float64{"element_not_found": f.ElementNotFound, "slow_navigation": f.SlowNavigation, "dropped_cookies": f.DroppedCookies} {
+		if p < 0 || p > 1 {
+			return fmt.Errorf("faults.%s must be between 0 and 1", name)
+		}
+	}
+	if f.SlowNavigationSeconds < 0 {
+		return fmt.Errorf("faults.slow_navigation_seconds cannot be negative")
+	}
+
+	for name, exp := range c.Experiments {
+		if err := exp.validate(c); err != nil {
+			return fmt.Errorf("experiment %s: %w", name, err)
+		}
+	}
 
 	return nil
 }
 
+// Experiment compares pacing strategies by running the simulated pipeline
+// once per arm (and per seed) on identical synthetic profiles
+type Experiment struct {
+	Profiles        int             `yaml:"profiles"`         // Synthetic profiles per run
+	Days            int             `yaml:"days"`             // Simulated days per run
+	IntervalMinutes int             `yaml:"interval_minutes"` // Simulated time between pipeline cycles
+	Runs            int             `yaml:"runs"`             // Seeds per arm; metrics are averaged
+	Arms            []ExperimentArm `yaml:"arms"`
+}
+
+// ExperimentArm is one pacing strategy: the limits and stealth settings it
+// changes, written like the top-level sections, e.g.
+// limits: {connections_per_day: 25, connection_cooldown_seconds: 120}
+type ExperimentArm struct {
+	Name    string    `yaml:"name"`
+	Limits  yaml.Node `yaml:"limits"`
+	Stealth yaml.Node `yaml:"stealth"`
+}
+
+// Apply returns a copy of cfg with the arm's overrides, without experiments
+func (a ExperimentArm) Apply(cfg *Config) (*Config, error) {
+	armCfg := *cfg
+	armCfg.Experiments = nil
+	for _, section := range []struct {
+		node *yaml.Node
+		out  interface{}
+	}{{&a.Limits, &armCfg.Limits}, {&a.Stealth, &armCfg.Stealth}} {
+		if section.node.Kind == 0 {
+			continue
+		}
+		if err := decodeStrict(section.node, section.out); err != nil {
+			return nil, fmt.Errorf("arm %s: %w", a.Name, err)
+		}
+	}
+	if err := armCfg.Validate(); err != nil {
+		return nil, fmt.Errorf("arm %s: %w", a.Name, err)
+	}
+	return &armCfg, nil
+}
+
+// decodeStrict decodes a node onto out, rejecting unknown keys
+func decodeStrict(node *yaml.Node, out interface{}) error {
+	raw, err := yaml.Marshal(node)
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	dec.KnownFields(true)
+	return dec.Decode(out)
+}
+
+// validate checks the experiment's sizes and every arm against cfg
+func (e Experiment) validate(cfg *Config) error {
+	if e.Profiles < 0 || e.Days <= 0 || e.IntervalMinutes <= 0 || e.Runs <= 0 {
+		return fmt.Errorf("profiles must be >= 0 and days, interval_minutes and runs positive")
+	}
+	if len(e.Arms) < 2 {
+		return fmt.Errorf("needs at least two arms to compare")
+	}
+	seen := make(map[string]bool)
+	for _, arm := range e.Arms {
+		if arm.Name == "" || seen[arm.Name] {
+			return fmt.Errorf("arm names must be set and unique: %q", arm.Name)
+		}
+		seen[arm.Name] = true
+		if _, err := arm.Apply(cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // GetEnv reads environment variables with fallback
 func GetEnv(key, fallback string) string {
 	if value := os.Getenv(key); value != "" {
@@ -209,3 +1393,57 @@ func GetEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// validateStorage checks the backend and its connection pool
+func validateStorage(s StorageConfig) error {
+	if s.FlushIntervalMs < 0 {
+		return fmt.Errorf("storage.flush_interval_ms cannot be negative")
+	}
+	if e := s.Encryption; e.Enabled && e.KeyEnv == "" && e.KeyFile == "" {
+		return fmt.Errorf("storage.encryption needs a key_env or a key_file")
+	}
+	switch s.Backend {
+	case "file":
+		return nil
+	case "postgres":
+	default:
+		return fmt.Errorf("storage.backend must be file or postgres, got %q", s.Backend)
+	}
+	p := s.Postgres
+	if p.URL == "" || p.Driver == "" {
+		return fmt.Errorf("storage.postgres needs a url and a driver")
+	}
+	if p.MaxOpenConns < 0 || p.MaxIdleConns < 0 || p.ConnMaxLifetimeMinutes < 0 {
+		return fmt.Errorf("storage.postgres pool sizes and lifetime must not be negative")
+	}
+	return nil
+}
+
+// validateWorkflow checks the ordering strategy and its settings
+func validateWorkflow(w WorkflowConfig) error {
+	if !slices.Contains(WorkflowStrategies, w.Strategy) {
+		return fmt.Errorf("invalid workflow.strategy: %q (must be one of %s)", w.Strategy, strings.Join(WorkflowStrategies, ", "))
+	}
+	if w.BatchSize <= 0 {
+		return fmt.Errorf("workflow.batch_size must be positive")
+	}
+	if _, err := time.Parse("15:04", w.MorningUntil); err != nil {
+		return fmt.Errorf("invalid workflow.morning_until format: %s (use HH:MM)", w.MorningUntil)
+	}
+	for _, day := range w.SearchOnlyDays {
+		if _, ok := ParseWeekday(day); !ok {
+			return fmt.Errorf("invalid workflow.search_only_days entry: %q (use monday..sunday)", day)
+		}
+	}
+	return nil
+}
+
+// ParseWeekday parses an English weekday name, in any case
+func ParseWeekday(name string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(name, d.String()) {
+			return d, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FuzzParse checks that arbitrary config files either load into a valid
+// configuration or fail with an error, and never panic
+func FuzzParse(f *testing.F) {
+	if data, err := os.ReadFile("../../config.yaml"); err == nil {
+		f.Add(data)
+	}
+	f.Add([]byte(""))
+	f.Add([]byte("app:\n  log_level: debug\n"))
+	f.Add([]byte("app:\n  log_levle: debug\n"))
+	f.Add([]byte("limits:\n  connections_per_day: -1\n"))
+	f.Add([]byte("limits: [1, 2]\n"))
+	f.Add([]byte("stealth:\n  business_hours_start: \"25:99\"\n"))
+	f.Add([]byte("simulation:\n  seniority_rates: {mid: 2}\n"))
+	f.Add([]byte("app: {}\n---\napp: {}\n"))
+	f.Add([]byte("\t- !!binary x"))
+	f.Add([]byte("a: &a [*a]"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		cfg, err := Parse(data)
+		if err != nil {
+			if cfg != nil {
+				t.Fatalf("Parse returned a config together with error %v", err)
+			}
+			return
+		}
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("Parse accepted a config that fails validation: %v", err)
+		}
+
+		// Whatever loads must survive a round trip unchanged
+		out, err := yaml.Marshal(cfg)
+		if err != nil {
+			t.Fatalf("failed to marshal parsed config: %v", err)
+		}
+		if _, err := Parse(out); err != nil {
+			t.Fatalf("re-parsing a marshalled config failed: %v\n%s", err, out)
+		}
+	})
+}
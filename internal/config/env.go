@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts the environment variables that override settings
+const EnvPrefix = "SUBSPACE_"
+
+// EnvConfig holds a whole YAML config, used instead of the config file
+const EnvConfig = EnvPrefix + "CONFIG"
+
+// LoadEnv loads the configuration for a container, where it comes from the
+// environment: EnvConfig replaces the config file when set, and any
+// setting can then be overridden by a variable named after its YAML path,
+// e.g. SUBSPACE_APP_LOG_LEVEL=debug or SUBSPACE_LIMITS_DAILY_CONNECTIONS=20.
+// Lists and maps take YAML flow syntax, e.g. [monday, friday].
+func LoadEnv(path string, environ []string) (*Config, error) {
+	env := make(map[string]string)
+	for _, kv := range environ {
+		if key, value, ok := strings.Cut(kv, "="); ok && strings.HasPrefix(key, EnvPrefix) {
+			env[key] = value
+		}
+	}
+
+	data := []byte(env[EnvConfig])
+	if len(data) == 0 {
+		if _, err := os.Stat(path); err == nil {
+			if data, err = os.ReadFile(path); err != nil {
+				return nil, fmt.Errorf("failed to read config file: %w", err)
+			}
+		}
+	}
+	return parse(data, func(cfg *Config) error {
+		return applyEnv(reflect.ValueOf(cfg).Elem(), strings.TrimSuffix(EnvPrefix, "_"), env)
+	})
+}
+
+// applyEnv sets the fields of v that have a variable in env, descending
+// into nested sections
+func applyEnv(v reflect.Value, prefix string, env map[string]string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + "_" + strings.ToUpper(name)
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := applyEnv(field, key, env); err != nil {
+				return err
+			}
+			continue
+		}
+		value, ok := env[key]
+		if !ok {
+			continue
+		}
+		if field.Kind() == reflect.String {
+			field.SetString(value)
+			continue
+		}
+		parsed := reflect.New(field.Type())
+		if err := yaml.Unmarshal([]byte(value), parsed.Interface()); err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		field.Set(parsed.Elem())
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLoadEnv(t *testing.T) {
+	cfg, err := LoadEnv("does-not-exist.yaml", []string{
+		"SUBSPACE_CONFIG=app:\n  log_level: warn\n  language: de\n",
+		"SUBSPACE_APP_LOG_LEVEL=debug",
+		"SUBSPACE_APP_HEADLESS=true",
+		"SUBSPACE_LIMITS_CONNECTIONS_PER_DAY=70",
+		"SUBSPACE_WORKFLOW_SEARCH_ONLY_DAYS=[monday, friday]",
+		"SUBSPACE_CONTAINER=1",
+		"HOME=/root",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.App.LogLevel != "debug" || cfg.App.Language != "de" || !cfg.App.Headless {
+		t.Errorf("app = %+v", cfg.App)
+	}
+	if cfg.Limits.ConnectionsPerDay != 70 {
+		t.Errorf("connections_per_day = %d", cfg.Limits.ConnectionsPerDay)
+	}
+	if !slices.Equal(cfg.Workflow.SearchOnlyDays, []string{"monday", "friday"}) {
+		t.Errorf("search_only_days = %v", cfg.Workflow.SearchOnlyDays)
+	}
+
+	if _, err := LoadEnv("", []string{"SUBSPACE_LIMITS_CONNECTIONS_PER_DAY=many"}); err == nil {
+		t.Error("expected an error for a non-numeric limit")
+	}
+	if _, err := LoadEnv("", []string{"SUBSPACE_APP_LOG_LEVEL=loud"}); err == nil {
+		t.Error("expected overrides to be validated")
+	}
+}
@@ -0,0 +1,26 @@
+package config
+
+import "testing"
+
+func TestValidateTouchLimit(t *testing.T) {
+	tests := []struct {
+		touches, window, retention int
+		ok                         bool
+	}{
+		{5, 30, 90, true},
+		{5, 30, 0, true},   // Action log kept forever
+		{0, 0, 7, true},    // Guardrail off
+		{5, 30, 14, false}, // Purged touches would stop counting
+		{5, 0, 90, false},
+		{-1, 30, 90, false},
+	}
+	for _, tt := range tests {
+		cfg := Defaults()
+		cfg.Limits.MaxTouchesPerProfile, cfg.Limits.TouchWindowDays = tt.touches, tt.window
+		cfg.Retention.ActionLogsDays = tt.retention
+		if err := cfg.Validate(); (err == nil) != tt.ok {
+			t.Errorf("touches %d in %d days, logs kept %d days: got %v, want ok=%v",
+				tt.touches, tt.window, tt.retention, err, tt.ok)
+		}
+	}
+}
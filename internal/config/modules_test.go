@@ -0,0 +1,26 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestModules(t *testing.T) {
+	cfg := Defaults()
+	if got := cfg.Modules.Enabled(); !slices.Equal(got, []string{"search", "connect", "acceptance", "messaging"}) {
+		t.Errorf("default modules = %v, want all in workflow order", got)
+	}
+
+	cfg.Modules = ModulesConfig{Messaging: true, Search: true}
+	if got := cfg.Modules.Enabled(); !slices.Equal(got, []string{"search", "messaging"}) {
+		t.Errorf("Enabled = %v", got)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("partial pipeline rejected: %v", err)
+	}
+
+	cfg.Modules = ModulesConfig{}
+	if err := cfg.Validate(); err == nil {
+		t.Error("config with every module disabled accepted")
+	}
+}
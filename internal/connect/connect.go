@@ -1,12 +1,24 @@
 package connect
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
+	"subspace/internal/clock"
+	"subspace/internal/bandwidth"
+	"subspace/internal/batch"
 	"subspace/internal/browser"
+	"subspace/internal/campaign"
 	"subspace/internal/config"
+	"subspace/internal/estop"
+	"subspace/internal/hooks"
 	"subspace/internal/logger"
+	"subspace/internal/modals"
+	"subspace/internal/ratelimit"
+	"subspace/internal/rules"
+	"subspace/internal/simulate"
 	"subspace/internal/stealth"
 	"subspace/internal/storage"
 )
@@ -19,8 +31,13 @@ Shows how to enforce rate limits and track profile states.
 
 STATE MACHINE:
 discovered → requested → accepted → cooled_down
-         ↓
-      rejected
+    ↓  ↑          ↓
+    ↓  approved   rejected
+    ↓  ↕
+    skipped
+
+With review.require_approval only approved profiles are requested; see
+"subspace review".
 
 FEATURES:
 - Daily/hourly connection limits
@@ -31,50 +48,90 @@ FEATURES:
 
 // Connector handles connection request operations
 type Connector struct {
-	browser browser.Controller
-	stealth *stealth.Stealth
-	storage *storage.Storage
-	limits  config.LimitsConfig
-	log     *logger.ContextLogger
+	browser   browser.Controller
+	stealth   *stealth.Stealth
+	storage   storage.Backend
+	limits    config.LimitsConfig
+	limiter   *ratelimit.Limiter
+	review    config.ReviewConfig
+	target    config.TargetingConfig
+	campaigns *campaign.Allocator
+	meter     *bandwidth.Meter
+	model     simulate.AcceptanceModel
+	batch     int // Most requests per ProcessDailyConnections, 0 for no cap
+	hooks     *hooks.Hooks
+	modals    *modals.Dismisser
+	log       *logger.ContextLogger
 }
 
-// New creates a new connector
-func New(b browser.Controller, s *stealth.Stealth, storage *storage.Storage, limits config.LimitsConfig) *Connector {
+// New creates a new connector. The acceptance model stands in for the
+// network when checking which requests were accepted.
+func New(b browser.Controller, s *stealth.Stealth, storage storage.Backend, limits config.LimitsConfig, review config.ReviewConfig, target config.TargetingConfig, model simulate.AcceptanceModel) *Connector {
 	return &Connector{
-		browser: b,
-		stealth: s,
-		storage: storage,
-		limits:  limits,
-		log:     logger.NewContext("connect"),
+		browser:   b,
+		stealth:   s,
+		storage:   storage,
+		limits:    limits,
+		limiter:   ratelimit.New(storage, limits),
+		review:    review,
+		target:    target,
+		campaigns: campaign.New(target.Campaigns),
+		model:     model,
+		log:       logger.NewContext("connect"),
 	}
 }
 
-// ProcessDailyConnections processes pending connection requests
-func (c *Connector) ProcessDailyConnections() error {
+// SetBatch caps the requests each ProcessDailyConnections sends, for
+// interleaved workflows; 0 lifts the cap
+func (c *Connector) SetBatch(n int) {
+	c.batch = n
+}
+
+// SetHooks runs the can_send and on_accepted hooks
+func (c *Connector) SetHooks(h *hooks.Hooks) {
+	c.hooks = h
+}
+
+// SetModals dismisses consent and promotional modals on each profile page
+func (c *Connector) SetModals(d *modals.Dismisser) {
+	c.modals = d
+}
+
+// SetMeter attributes the traffic of each connection request to the
+// campaign of its profile
+func (c *Connector) SetMeter(m *bandwidth.Meter) {
+	c.meter = m
+}
+
+// ProcessDailyConnections processes pending connection requests and
+// returns the outcome for every profile it got to
+func (c *Connector) ProcessDailyConnections() (*batch.Result, error) {
 	c.log.Info("Starting daily connection processing")
 	start := time.Now()
+	result := batch.New("connection")
 
-	// Check daily and hourly limits
-	connectionsToday := c.storage.GetActionCountToday("connection")
+	// Check daily and hourly limits (sliding windows, see ratelimit)
+	connectionsLastDay := c.storage.GetActionCountSince("connection", clock.Now().Add(-24*time.Hour))
 	connectionsLastHour := c.storage.GetActionCountLastHour("connection")
 
 	c.log.Info("Current connection counts",
-		"today", connectionsToday,
+		"last_24h", connectionsLastDay,
 		"last_hour", connectionsLastHour,
 		"limit_daily", c.limits.ConnectionsPerDay,
 		"limit_hourly", c.limits.ConnectionsPerHour)
 
 	// Check if we've hit daily limit
-	if connectionsToday >= c.limits.ConnectionsPerDay {
+	if connectionsLastDay >= c.limits.ConnectionsPerDay {
 		c.log.Warn("Daily connection limit reached, entering cooldown",
-			"count", connectionsToday,
+			"count", connectionsLastDay,
 			"limit", c.limits.ConnectionsPerDay)
 		
 		// Log cooldown start
-		cooldownUntil := time.Now().Add(time.Duration(c.limits.CooldownMinutes) * time.Minute)
+		cooldownUntil := clock.Now().Add(time.Duration(c.limits.CooldownMinutes) * time.Minute)
 		c.log.Info("Cooldown until", "time", cooldownUntil.Format(time.RFC3339))
 		
-		return nil
+		result.Stopped = batch.StoppedDailyLimit
+		return result, nil
 	}
 
 	// Check if we've hit hourly limit
@@ -82,29 +139,41 @@ func (c *Connector) ProcessDailyConnections() error {
 		c.log.Warn("Hourly connection limit reached, waiting",
 			"count", connectionsLastHour,
 			"limit", c.limits.ConnectionsPerHour)
-		return nil
+		result.Stopped = batch.StoppedHourlyLimit
+		return result, nil
 	}
 
-	// Get profiles in "discovered" state
-	candidates := c.storage.GetProfilesByState(storage.StateDiscovered)
-	c.log.Info("Found candidate profiles", "count", len(candidates))
+	// Approved profiles, plus unreviewed ones unless approval is required
+	candidates := Eligible(c.storage.ConnectCandidates(c.review.RequireApproval), c.target)
+	c.log.Info("Found candidate profiles", "count", len(candidates),
+		"require_approval", c.review.RequireApproval,
+		"min_mutual_connections", c.target.MinMutualConnections,
+		"locations", c.target.Locations)
 
 	if len(candidates) == 0 {
 		c.log.Info("No candidates to process")
-		return nil
+		return result, nil
 	}
 
-	// Calculate how many we can send
-	remainingDaily := c.limits.ConnectionsPerDay - connectionsToday
-	remainingHourly := c.limits.ConnectionsPerHour - connectionsLastHour
-	
-	maxToSend := remainingDaily
-	if remainingHourly < maxToSend {
-		maxToSend = remainingHourly
+	// Calculate how many we can send. Windows only ever free up capacity
+	// as time passes, so this upfront budget stays safe for the whole batch.
+	maxToSend := c.limiter.Remaining("connection")
+	if c.batch > 0 && c.batch < maxToSend {
+		maxToSend = c.batch
 	}
 
 	c.log.Info("Planning to send connections", "max", maxToSend)
 
+	// Share the budget between campaigns instead of first-come-first-served
+	if c.campaigns.Enabled() {
+		var allocations []campaign.Allocation
+		candidates, allocations = c.campaigns.Select(candidates, maxToSend)
+		for _, a := range allocations {
+			c.log.Info("Campaign allocation", "campaign", a.Campaign, "priority", a.Priority,
+				"weight", a.Weight, "candidates", a.Candidates, "allocated", a.Allocated)
+		}
+	}
+
 	// Process profiles
 	sent := 0
 	for i, profile := range candidates {
@@ -119,41 +188,150 @@ func (c *Connector) ProcessDailyConnections() error {
 			"name", profile.Name)
 
 		// Send connection request
-		if err := c.SendConnectionRequest(profile); err != nil {
+		itemStart := clock.Now()
+		if err := estop.Check("connection"); err != nil {
+			c.log.Warn("Stopping batch", "sent", sent, "error", err)
+			result.Stopped = batch.StoppedEmergency
+			break
+		}
+		if err := c.limiter.CheckProfile(profile.ID); err != nil {
+			c.log.Warn("Skipping profile", "profile", profile.Name, "error", err)
+			result.Add(profile, batch.Skipped, itemStart, err)
+			continue
+		}
+		if err := c.hooks.CanSend("connection", profile); err != nil {
+			c.log.Info("Skipping profile", "profile", profile.Name, "error", err)
+			result.Add(profile, batch.Skipped, itemStart, err)
+			continue
+		}
+		if c.campaigns.Enabled() {
+			c.meter.SetCampaign(c.campaigns.Of(profile))
+		}
+		if err := c.SendConnectionRequest(profile); errors.Is(err, storage.ErrClaimed) {
+			c.log.Info("Skipping profile", "profile", profile.Name, "error", err)
+			result.Add(profile, batch.Skipped, itemStart, err)
+			continue
+		} else if err != nil {
 			c.log.Error("Failed to send connection request",
 				"profile", profile.Name,
 				"error", err)
 			
 			// Log failed action
 			c.storage.LogAction("connection", profile.ID, false, err)
+			outcome := batch.Failed
+			if errors.Is(err, stealth.ErrActionTimeout) {
+				outcome = batch.TimedOut
+			}
+			result.Add(profile, outcome, itemStart, err)
 			
 			// Don't stop on error, continue with next
 			continue
 		}
 
 		sent++
+		result.Add(profile, batch.Sent, itemStart, nil)
 		
 		// Enforce cooldown between requests (stealth)
-		c.stealth.EnforceCooldown("connection", 30) // 30 seconds minimum
+		c.stealth.EnforceCooldown("connection", c.limits.ConnectionCooldownSeconds)
 	}
 
+	c.meter.SetCampaign("")
 	logger.Timing("connect", "process_daily", start, nil)
 	c.log.Info("Daily connection processing complete",
 		"sent", sent,
-		"remaining_daily", remainingDaily-sent)
+		"failed", result.Count(batch.Failed),
+		"skipped", result.Count(batch.Skipped),
+		"remaining", maxToSend-sent)
 
-	return nil
+	return result, nil
 }
 
-// SendConnectionRequest sends a connection request to a profile
+// Eligible drops the candidates that don't meet the targeting requirements
+// and orders the rest by queue priority, then boost score, highest first.
+// Rules are validated with the config.
+func Eligible(candidates []*storage.Profile, target config.TargetingConfig) []*storage.Profile {
+	var include, exclude *rules.Rule
+	if target.Include != "" {
+		include = rules.MustCompile(target.Include)
+	}
+	if target.Exclude != "" {
+		exclude = rules.MustCompile(target.Exclude)
+	}
+
+	eligible := candidates[:0:0]
+	for _, p := range candidates {
+		if p.MutualConnections() < target.MinMutualConnections || !p.InLocations(target.Locations) ||
+			!p.InSegments(target.Seniorities, target.Functions) ||
+			!p.InCompanies(target.Industries, target.CompanySizes) || !p.InTags(target.Tags) {
+			continue
+		}
+		if include != nil || exclude != nil {
+			env := p.RuleEnv()
+			if include != nil && !include.Match(env) || exclude != nil && exclude.Match(env) {
+				continue
+			}
+		}
+		eligible = append(eligible, p)
+	}
+
+	if len(target.Boosts) > 0 {
+		scores := make(map[string]int, len(eligible))
+		for _, p := range eligible {
+			scores[p.ID] = Score(p, target.Boosts)
+		}
+		sort.SliceStable(eligible, func(i, j int) bool { return scores[eligible[i].ID] > scores[eligible[j].ID] })
+	}
+	storage.SortByPriority(eligible)
+	return eligible
+}
+
+// Score returns the sum of the boosts whose rule matches the profile
+func Score(p *storage.Profile, boosts []config.RuleBoost) int {
+	score := 0
+	env := p.RuleEnv()
+	for _, b := range boosts {
+		if rules.MustCompile(b.When).Match(env) {
+			score += b.Score
+		}
+	}
+	return score
+}
+
+// SendConnectionRequest sends a connection request to a profile, tracing
+// the stealth decisions it takes
 func (c *Connector) SendConnectionRequest(profile *storage.Profile) error {
+	if err := estop.Check("connection"); err != nil {
+		return err
+	}
+	if err := c.limiter.CheckProfile(profile.ID); err != nil {
+		return err
+	}
+	if err := c.storage.Claim(profile.ID); err != nil {
+		return err
+	}
+	c.stealth.BeginAction("connection", profile.ID, "")
+	err := c.sendConnectionRequest(profile)
+	c.stealth.EndAction(err)
+	return err
+}
+
+// sendConnectionRequest walks through the profile page and the invitation
+// dialog, then records the request
+func (c *Connector) sendConnectionRequest(profile *storage.Profile) error {
 	c.log.Info("Sending connection request", "name", profile.Name, "profile_id", profile.ID)
 	start := time.Now()
 
 	// Step 1: Navigate to profile
 	c.log.Debug("Navigating to profile", "url", profile.ProfileURL)
-	// In production: c.browser.Navigate(profile.ProfileURL)
+	// In production: c.browser.Navigate(profile.ProfileURL). Simulated
+	// browsers are driven for real so their faults surface here.
+	page := browser.Simulated(c.browser)
+	if err := page.Navigate(profile.ProfileURL); err != nil {
+		logger.Timing("connect", "send_request", start, err)
+		return fmt.Errorf("failed to open profile: %w", err)
+	}
 	c.stealth.RandomDelay()
+	c.modals.Clear(page)
 
 	// Step 2: Wait for page load and scroll around (human-like)
 	c.stealth.ThinkingPause()
@@ -166,12 +344,22 @@ func (c *Connector) SendConnectionRequest(profile *storage.Profile) error {
 	// connectBtn := c.browser.Page.Element("[aria-label='Invite ... to connect']")
 	
 	// Step 4: Move mouse to button
-	c.stealth.MoveMouse(800, 400) // Mock coordinates
+	c.stealth.MoveTo(0.67, 0.5) // Mock coordinates
 	c.stealth.RandomDelay()
 
-	// Step 5: Click connect button
+	// Step 5: Click connect button, unless the request took too long
+	// already; nothing has been sent or stored yet
+	if err := c.stealth.CheckBudget(); err != nil {
+		logger.Timing("connect", "send_request", start, err)
+		return err
+	}
 	c.log.Debug("Clicking Connect button")
+	c.stealth.ClickAt(0.67, 0.5)
 	// In production: c.browser.Click(connectBtn selector)
+	if err := page.Click("mock-connect-button"); err != nil {
+		logger.Timing("connect", "send_request", start, err)
+		return fmt.Errorf("failed to click Connect: %w", err)
+	}
 	
 	// Step 6: Handle "Add a note" dialog (if appears)
 	c.stealth.ThinkingPause()
@@ -180,27 +368,42 @@ func (c *Connector) SendConnectionRequest(profile *storage.Profile) error {
 	// For now, send without note (can be enhanced with messaging module)
 	c.log.Debug("Sending without note")
 	
-	// Step 7: Click "Send" button in dialog
-	c.stealth.MoveMouse(700, 500)
+	// Step 7: Click "Send" button in dialog. Past the budget the dialog is
+	// left unsent and the profile stays as it was.
+	c.stealth.MoveTo(0.58, 0.62)
 	c.stealth.RandomDelay()
+	if err := c.stealth.CheckBudget(); err != nil {
+		logger.Timing("connect", "send_request", start, err)
+		return err
+	}
+	c.stealth.ClickAt(0.58, 0.62)
 	// In production: c.browser.Click("[aria-label='Send invitation']")
+	if err := page.Click("mock-send-invitation"); err != nil {
+		logger.Timing("connect", "send_request", start, err)
+		return fmt.Errorf("failed to send invitation: %w", err)
+	}
 
 	// Step 8: Wait for confirmation
 	c.stealth.RandomDelay()
 
 	// Step 9: Update profile state
-	now := time.Now()
-	profile.State = storage.StateRequested
-	profile.RequestedAt = &now
+	if err := profile.Transition(storage.StateRequested, clock.Now()); err != nil {
+		logger.Timing("connect", "send_request", start, err)
+		return err
+	}
 
-	if err := c.storage.SaveProfile(profile); err != nil {
+	// Persist the state change and the action log together so a failed
+	// write can't leave a requested profile that limits never counted
+	err := c.storage.Transaction(func(tx *storage.Tx) error {
+		tx.SaveProfile(profile)
+		tx.LogAction("connection", profile.ID, true, nil)
+		return nil
+	})
+	if err != nil {
 		logger.Timing("connect", "send_request", start, err)
 		return fmt.Errorf("failed to update profile state: %w", err)
 	}
 
-	// Log successful action
-	c.storage.LogAction("connection", profile.ID, true, nil)
-
 	logger.Timing("connect", "send_request", start, nil)
 	c.log.Info("Connection request sent successfully", "profile", profile.Name)
 
@@ -221,15 +424,16 @@ func (c *Connector) CheckAcceptedConnections() error {
 	// 3. Compare with requested profiles
 	// 4. Update states for accepted ones
 	//
-	// For PoC, we simulate random acceptances
+	// For PoC, acceptances come from the configured simulation model
 
 	accepted := 0
 	for _, profile := range requested {
-		// Simulate 20% chance of acceptance (for demo purposes)
-		if c.stealth.ShouldProceed(0.2) {
-			now := time.Now()
-			profile.State = storage.StateAccepted
-			profile.AcceptedAt = &now
+		now := clock.Now()
+		if c.model.Accepted(profile, now) {
+			if err := profile.Transition(storage.StateAccepted, now); err != nil {
+				c.log.Error("Failed to accept connection", "error", err)
+				continue
+			}
 
 			if err := c.storage.SaveProfile(profile); err != nil {
 				c.log.Error("Failed to update profile", "error", err)
@@ -237,11 +441,12 @@ func (c *Connector) CheckAcceptedConnections() error {
 			}
 
 			c.log.Info("Connection accepted", "name", profile.Name)
+			c.hooks.OnAccepted(profile)
 			accepted++
 		}
 	}
 
-	c.log.Info("Acceptance check complete", "newly_accepted", accepted)
+	c.log.Info("Acceptance check complete", "newly_accepted", accepted, "model", c.model.Name())
 	return nil
 }
 
@@ -249,9 +454,9 @@ func (c *Connector) CheckAcceptedConnections() error {
 func (c *Connector) MoveToCooldown(profile *storage.Profile) error {
 	c.log.Info("Moving profile to cooldown", "name", profile.Name)
 
-	now := time.Now()
-	profile.State = storage.StateCooledDown
-	profile.CooledDownAt = &now
+	if err := profile.Transition(storage.StateCooledDown, clock.Now()); err != nil {
+		return err
+	}
 
 	if err := c.storage.SaveProfile(profile); err != nil {
 		return fmt.Errorf("failed to update profile: %w", err)
@@ -262,6 +467,9 @@ func (c *Connector) MoveToCooldown(profile *storage.Profile) error {
 
 // WithdrawConnectionRequest withdraws a pending connection request
 func (c *Connector) WithdrawConnectionRequest(profile *storage.Profile) error {
+	if err := estop.Check("withdrawal"); err != nil {
+		return err
+	}
 	c.log.Info("Withdrawing connection request", "name", profile.Name)
 	start := time.Now()
 
@@ -274,9 +482,11 @@ func (c *Connector) WithdrawConnectionRequest(profile *storage.Profile) error {
 	// Mock withdrawal
 	c.stealth.RandomDelay()
 
-	// Update state
-	profile.State = storage.StateDiscovered // Reset to discovered
-	profile.RequestedAt = nil
+	// Update state, resetting to discovered
+	if err := profile.Transition(storage.StateDiscovered, clock.Now()); err != nil {
+		logger.Timing("connect", "withdraw", start, err)
+		return err
+	}
 
 	if err := c.storage.SaveProfile(profile); err != nil {
 		logger.Timing("connect", "withdraw", start, err)
@@ -299,23 +509,32 @@ func (c *Connector) GetAcceptedConnections() []*storage.Profile {
 	return c.storage.GetProfilesByState(storage.StateAccepted)
 }
 
-// CanSendMore checks if we can send more connections today
+// CanSendMore checks if the daily and hourly windows have room for another
+// connection request
 func (c *Connector) CanSendMore() bool {
-	today := c.storage.GetActionCountToday("connection")
-	hourly := c.storage.GetActionCountLastHour("connection")
+	return c.limiter.Allow("connection")
+}
 
-	return today < c.limits.ConnectionsPerDay && hourly < c.limits.ConnectionsPerHour
+// Stats is a snapshot of connection activity and limits
+type Stats struct {
+	ConnectionsToday    int  `json:"connections_today"`
+	ConnectionsLastHour int  `json:"connections_last_hour"`
+	PendingRequests     int  `json:"pending_requests"`
+	AcceptedConnections int  `json:"accepted_connections"`
+	LimitDaily          int  `json:"limit_daily"`
+	LimitHourly         int  `json:"limit_hourly"`
+	CanSendMore         bool `json:"can_send_more"`
 }
 
 // GetStats returns connection statistics
-func (c *Connector) GetStats() map[string]interface{} {
-	return map[string]interface{}{
-		"connections_today":      c.storage.GetActionCountToday("connection"),
-		"connections_last_hour":  c.storage.GetActionCountLastHour("connection"),
-		"pending_requests":       len(c.GetPendingRequests()),
-		"accepted_connections":   len(c.GetAcceptedConnections()),
-		"limit_daily":            c.limits.ConnectionsPerDay,
-		"limit_hourly":           c.limits.ConnectionsPerHour,
-		"can_send_more":          c.CanSendMore(),
+func (c *Connector) GetStats() Stats {
+	return Stats{
+		ConnectionsToday:    c.storage.GetActionCountToday("connection"),
+		ConnectionsLastHour: c.storage.GetActionCountLastHour("connection"),
+		PendingRequests:     len(c.GetPendingRequests()),
+		AcceptedConnections: len(c.GetAcceptedConnections()),
+		LimitDaily:          c.limits.ConnectionsPerDay,
+		LimitHourly:         c.limits.ConnectionsPerHour,
+		CanSendMore:         c.CanSendMore(),
 	}
 }
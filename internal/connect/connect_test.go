@@ -0,0 +1,116 @@
+package connect
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"subspace/internal/batch"
+	"subspace/internal/browser"
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/logger"
+	"subspace/internal/simulate"
+	"subspace/internal/stealth"
+	"subspace/internal/storage"
+)
+
+// ids returns the IDs of profiles in order
+func ids(profiles []*storage.Profile) []string {
+	out := make([]string, len(profiles))
+	for i, p := range profiles {
+		out[i] = p.ID
+	}
+	return out
+}
+
+func TestEligibleMutualConnections(t *testing.T) {
+	candidates := []*storage.Profile{
+		{ID: "none"}, // Never enriched
+		{ID: "few", Shared: &storage.SharedContext{MutualConnections: 2}},
+		{ID: "enough", Shared: &storage.SharedContext{MutualConnections: 5}},
+		{ID: "many", Shared: &storage.SharedContext{MutualConnections: 40, Groups: []string{"Gophers Slack"}}},
+	}
+
+	if got := ids(Eligible(candidates, config.TargetingConfig{})); !slices.Equal(got, []string{"none", "few", "enough", "many"}) {
+		t.Errorf("without a minimum: %v", got)
+	}
+	if got := ids(Eligible(candidates, config.TargetingConfig{MinMutualConnections: 5})); !slices.Equal(got, []string{"enough", "many"}) {
+		t.Errorf("at least 5 mutual connections: %v", got)
+	}
+	if len(candidates) != 4 || candidates[0].ID != "none" {
+		t.Errorf("Eligible changed the candidates: %v", ids(candidates))
+	}
+}
+
+// lostBrowser is a simulated Chrome whose CDP connection drops after a
+// number of page loads
+type lostBrowser struct {
+	*browser.Sim
+	loads, lostAfter int
+}
+
+func (b *lostBrowser) Navigate(url string) error {
+	if b.loads >= b.lostAfter {
+		return errors.New("websocket: close 1006 (abnormal closure)")
+	}
+	b.loads++
+	return b.Sim.Navigate(url)
+}
+
+// TestResumeAfterBrowserLost reruns a batch interrupted by a lost browser,
+// as the run does after relaunching Chrome: the rerun picks up where the
+// batch stopped and nobody gets a second request
+func TestResumeAfterBrowserLost(t *testing.T) {
+	clock.Set(clock.NewFake(time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)))
+	defer clock.Set(clock.Real{})
+	logger.Init("error")
+	defer logger.Init("info")
+
+	cfg := config.Defaults()
+	cfg.Review.RequireApproval = false
+	cfg.Limits.ConnectionsPerDay, cfg.Limits.ConnectionsPerHour = 100, 100
+	db, err := storage.New(filepath.Join(t.TempDir(), "db.json"), cfg.Storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprintf("p%d", i)
+		p := &storage.Profile{ID: id, ProfileURL: "https://www.linkedin.com/in/" + id, State: storage.StateDiscovered, DiscoveredAt: clock.Now()}
+		if err := db.SaveProfile(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run := func(b browser.Controller) *batch.Result {
+		t.Helper()
+		model, _ := simulate.New(config.SimulationConfig{Seed: 1})
+		c := New(b, stealth.New(cfg.Stealth, nil), db, cfg.Limits, cfg.Review, cfg.Targeting, model)
+		result, err := c.ProcessDailyConnections()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	if got := run(&lostBrowser{Sim: browser.NewSim(), lostAfter: 2}).Count(batch.Sent); got != 2 {
+		t.Fatalf("sent %d before the browser was lost, want 2", got)
+	}
+	if got := run(browser.NewSim()).Count(batch.Sent); got != 3 {
+		t.Errorf("rerun sent %d, want the 3 left", got)
+	}
+
+	sent := make(map[string]int)
+	for _, log := range db.GetActionLogs("connection") {
+		if log.Success {
+			sent[log.ProfileID]++
+		}
+	}
+	for i := 0; i < 5; i++ {
+		if n := sent[fmt.Sprintf("p%d", i)]; n != 1 {
+			t.Errorf("p%d got %d requests, want 1", i, n)
+		}
+	}
+}
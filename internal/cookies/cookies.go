@@ -0,0 +1,145 @@
+package cookies
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"subspace/internal/config"
+	"subspace/internal/seal"
+)
+
+/*
+COOKIE JAR MODULE
+
+The saved session (auth.session_cookie_path) is a JSON array of the
+browser's cookies. Rather than dumping every cookie, saving goes through
+auth.cookies: cookies on a dropped domain or with a dropped name (ad and
+tracking cookies, by default) are never written, and when keep is set only
+the cookies it names are. "subspace cookies" lists the saved jar with each
+cookie's verdict; "cookies prune" applies the policy and removes expired
+cookies from a jar saved before it; "cookies drop <pattern>" removes
+cookies by name or domain.
+*/
+
+// Verdict is what the policy does with a cookie
+type Verdict string
+
+const (
+	Keep    Verdict = "keep"
+	Drop    Verdict = "drop"    // Matched drop or drop_domains, or not keep
+	Expired Verdict = "expired" // Past its expiry, dropped whatever the policy
+)
+
+// Policy decides which cookies are persisted
+type Policy struct {
+	cfg config.CookieConfig
+}
+
+// NewPolicy returns the policy of a cookie configuration
+func NewPolicy(cfg config.CookieConfig) Policy {
+	return Policy{cfg: cfg}
+}
+
+// Judge returns the verdict on a cookie at a time
+func (p Policy) Judge(c *proto.NetworkCookie, now time.Time) Verdict {
+	if IsExpired(c, now) {
+		return Expired
+	}
+	for _, d := range p.cfg.DropDomains {
+		if OnDomain(c, d) {
+			return Drop
+		}
+	}
+	if Matches(c, p.cfg.Drop) {
+		return Drop
+	}
+	if len(p.cfg.Keep) > 0 && !Matches(c, p.cfg.Keep) {
+		return Drop
+	}
+	return Keep
+}
+
+// Filter splits cookies into those kept and those dropped or expired
+func (p Policy) Filter(cookies []*proto.NetworkCookie, now time.Time) (kept, dropped []*proto.NetworkCookie) {
+	for _, c := range cookies {
+		if p.Judge(c, now) == Keep {
+			kept = append(kept, c)
+		} else {
+			dropped = append(dropped, c)
+		}
+	}
+	return kept, dropped
+}
+
+// IsExpired reports whether a persistent cookie is past its expiry;
+// session cookies never are
+func IsExpired(c *proto.NetworkCookie, now time.Time) bool {
+	return c.Expires > 0 && c.Expires.Time().Before(now)
+}
+
+// Matches reports whether a cookie's name matches any of the patterns
+// (path.Match syntax, e.g. "_ga*")
+func Matches(c *proto.NetworkCookie, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, c.Name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// OnDomain reports whether a cookie belongs to a domain or one of its
+// subdomains
+func OnDomain(c *proto.NetworkCookie, domain string) bool {
+	host := strings.TrimPrefix(strings.ToLower(c.Domain), ".")
+	domain = strings.TrimPrefix(strings.ToLower(domain), ".")
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// Load reads a saved jar; a missing file is an empty jar
+func Load(file string) ([]*proto.NetworkCookie, error) {
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+	if data, err = seal.Open(data, seal.LabelSession); err != nil {
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+	var cookies []*proto.NetworkCookie
+	if err := json.Unmarshal(data, &cookies); err != nil {
+		return nil, fmt.Errorf("failed to parse session file: %w", err)
+	}
+	return cookies, nil
+}
+
+// Save writes a jar, readable by the owner only and encrypted if
+// storage.encryption is enabled
+func Save(file string, cookies []*proto.NetworkCookie) error {
+	if cookies == nil {
+		cookies = []*proto.NetworkCookie{}
+	}
+	data, err := json.MarshalIndent(cookies, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cookies: %w", err)
+	}
+	if data, err = seal.Seal(data, seal.LabelSession); err != nil {
+		return fmt.Errorf("failed to encrypt cookies: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package cookies
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"subspace/internal/config"
+)
+
+func TestPolicy(t *testing.T) {
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	future := proto.TimeSinceEpoch(now.Add(24 * time.Hour).Unix())
+	past := proto.TimeSinceEpoch(now.Add(-time.Hour).Unix())
+
+	p := NewPolicy(config.CookieConfig{Drop: []string{"_ga*"}, DropDomains: []string{"doubleclick.net"}})
+	cases := []struct {
+		cookie proto.NetworkCookie
+		want   Verdict
+	}{
+		{proto.NetworkCookie{Name: "li_at", Domain: ".linkedin.com", Expires: future}, Keep},
+		{proto.NetworkCookie{Name: "lang", Domain: ".linkedin.com", Expires: -1}, Keep},
+		{proto.NetworkCookie{Name: "li_at", Domain: ".linkedin.com", Expires: past}, Expired},
+		{proto.NetworkCookie{Name: "_ga_XYZ", Domain: ".linkedin.com", Expires: future}, Drop},
+		{proto.NetworkCookie{Name: "IDE", Domain: ".ad.doubleclick.net", Expires: future}, Drop},
+		{proto.NetworkCookie{Name: "x", Domain: "notdoubleclick.net", Expires: future}, Keep},
+	}
+	for _, c := range cases {
+		if got := p.Judge(&c.cookie, now); got != c.want {
+			t.Errorf("%s on %s: got %s, want %s", c.cookie.Name, c.cookie.Domain, got, c.want)
+		}
+	}
+
+	keepOnly := NewPolicy(config.CookieConfig{Keep: []string{"li_at", "JSESSIONID"}})
+	kept, dropped := keepOnly.Filter([]*proto.NetworkCookie{
+		{Name: "li_at", Expires: future}, {Name: "JSESSIONID", Expires: -1}, {Name: "bcookie", Expires: future},
+	}, now)
+	if len(kept) != 2 || len(dropped) != 1 || dropped[0].Name != "bcookie" {
+		t.Errorf("keep-only policy kept %d, dropped %d", len(kept), len(dropped))
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "sub", "session.json")
+	if jar, err := Load(file); err != nil || jar != nil {
+		t.Fatalf("missing jar: %v, %v", jar, err)
+	}
+	if err := Save(file, []*proto.NetworkCookie{{Name: "li_at", Value: "v"}}); err != nil {
+		t.Fatal(err)
+	}
+	jar, err := Load(file)
+	if err != nil || len(jar) != 1 || jar[0].Value != "v" {
+		t.Fatalf("got %v, %v", jar, err)
+	}
+}
@@ -0,0 +1,135 @@
+package crash
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/logger"
+	"subspace/internal/metrics"
+	"subspace/internal/stealth"
+	"subspace/internal/storage"
+)
+
+/*
+PANIC RECOVERY MODULE
+
+Workflow steps and commands run under a Guard, so a panic in a module
+(a nil profile field, a rod Must* call on a dead page) fails that step
+instead of killing the process with state half-updated. On a panic:
+- the stack trace is written to <data_dir>/crashes/<time>-<step>.json
+- the profile whose action was in flight, if any, is snoozed for a day
+  with a note: whether its request or message went out is unknown, so
+  nothing more is sent to it until an operator has checked
+- the panic is logged as an error, printed and counted in panics_total
+and the step returns a *PanicError like any other failure.
+*/
+
+// dirName holds crash reports inside the data directory
+const dirName = "crashes"
+
+// snoozeFor is how long an interrupted profile is held for checking
+const snoozeFor = 24 * time.Hour
+
+var panics = metrics.NewCounter("panics_total", "Panics recovered in workflow steps and commands")
+
+// Report is what is known about a recovered panic
+type Report struct {
+	Time     time.Time         `json:"time"`
+	Step     string            `json:"step"`
+	Panic    string            `json:"panic"`
+	Stack    string            `json:"stack"`
+	InFlight *stealth.InFlight `json:"in_flight,omitempty"` // Action interrupted, if any
+	Path     string            `json:"-"`                   // Where the report was written
+}
+
+// PanicError is the error a guarded function returns when it panicked
+type PanicError struct {
+	Report *Report
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in %s: %s", e.Report.Step, e.Report.Panic)
+}
+
+// Handler recovers panics for one process
+type Handler struct {
+	dataDir string
+	db      storage.Backend
+	stealth *stealth.Stealth // Asked for the action in flight; may be nil
+	log     *logger.ContextLogger
+}
+
+// New returns a handler writing reports under dataDir
+func New(dataDir string, db storage.Backend, s *stealth.Stealth) *Handler {
+	return &Handler{dataDir: dataDir, db: db, stealth: s, log: logger.NewContext("crash")}
+}
+
+// Guard runs fn and turns a panic in it into a *PanicError, after
+// reporting it and securing the profile in flight
+func (h *Handler) Guard(step string, fn func() error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Report: h.recovered(step, v, debug.Stack())}
+		}
+	}()
+	return fn()
+}
+
+// recovered handles a recovered panic value
+func (h *Handler) recovered(step string, v interface{}, stack []byte) *Report {
+	panics.Inc()
+	r := &Report{Time: clock.Now(), Step: step, Panic: fmt.Sprint(v), Stack: string(stack)}
+	if h.stealth != nil {
+		r.InFlight = h.stealth.InFlight()
+		h.stealth.EndAction(fmt.Errorf("panic: %s", r.Panic))
+	}
+
+	if r.InFlight != nil {
+		if err := h.secure(r); err != nil {
+			h.log.Error("Failed to hold interrupted profile", "profile_id", r.InFlight.ProfileID, "error", err)
+		}
+	}
+	if err := h.write(r); err != nil {
+		h.log.Error("Failed to write crash report", "error", err)
+	}
+	h.log.Error("Recovered panic", "step", step, "panic", r.Panic, "report", r.Path)
+	return r
+}
+
+// secure snoozes the interrupted profile, with a note saying why
+func (h *Handler) secure(r *Report) error {
+	p, err := h.db.GetProfile(r.InFlight.ProfileID)
+	if err != nil {
+		return err
+	}
+	reason := fmt.Sprintf("%s interrupted by a panic during %s; check whether it went out", r.InFlight.Action, r.Step)
+	p.Snooze(r.Time.Add(snoozeFor), reason)
+	if err := p.AddNote(reason, r.Time); err != nil {
+		return err
+	}
+	return h.db.SaveProfile(p)
+}
+
+// write saves the report as JSON in the crash directory
+func (h *Handler) write(r *Report) error {
+	dir := filepath.Join(h.dataDir, dirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create crash directory: %w", err)
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode crash report: %w", err)
+	}
+	name := r.Time.UTC().Format("20060102T150405") + "-" + strings.ReplaceAll(r.Step, " ", "_") + ".json"
+	r.Path = filepath.Join(dir, name)
+	if err := os.WriteFile(r.Path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write crash report: %w", err)
+	}
+	return nil
+}
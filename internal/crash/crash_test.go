@@ -0,0 +1,61 @@
+package crash
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/stealth"
+	"subspace/internal/storage"
+)
+
+func TestGuardRecoversAndSecuresProfile(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC))
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	dir := t.TempDir()
+	db, err := storage.New(filepath.Join(dir, "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveProfile(&storage.Profile{ID: "p1", Name: "Ada", State: storage.StateApproved, DiscoveredAt: fake.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	s := stealth.New(config.Defaults().Stealth, nil)
+	h := New(dir, db, s)
+
+	if err := h.Guard("connect", func() error { return errors.New("plain") }); err == nil || err.Error() != "plain" {
+		t.Fatalf("plain error changed: %v", err)
+	}
+
+	err = h.Guard("connect", func() error {
+		s.BeginAction("connection", "p1", "")
+		var p *storage.Profile
+		_ = p.Name // nil dereference
+		return nil
+	})
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("got %v, want a *PanicError", err)
+	}
+	if pe.Report.InFlight == nil || pe.Report.InFlight.ProfileID != "p1" || !strings.Contains(pe.Report.Stack, "crash_test.go") {
+		t.Errorf("report = %+v", pe.Report)
+	}
+	if s.InFlight() != nil {
+		t.Error("action still in flight after the panic")
+	}
+	if _, err := os.Stat(pe.Report.Path); err != nil {
+		t.Errorf("crash report not written: %v", err)
+	}
+
+	p, _ := db.GetProfile("p1")
+	if !p.Snoozed(fake.Now().Add(23*time.Hour)) || p.State != storage.StateApproved || p.LatestNote() == "" {
+		t.Errorf("profile not held: snoozed until %v, state %s, note %q", p.SnoozedUntil, p.State, p.LatestNote())
+	}
+}
@@ -0,0 +1,163 @@
+package enrich
+
+import (
+	"fmt"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/logger"
+	"subspace/internal/storage"
+)
+
+/*
+ENRICHMENT MODULE
+
+Fills in company metadata (industry and employee count bucket) on profiles
+from a pluggable provider, for targeting and templates.
+
+PROVIDERS:
+- csv:  a local file exported from a CRM or data vendor
+- http: an external API queried once per company
+
+Every lookup is cached in storage by company, including companies the
+provider doesn't know, so a company shared by many profiles costs one query
+per cache period and re-running enrichment is free.
+*/
+
+// Company is what a provider knows about a company
+type Company struct {
+	Industry string
+	Size     string // Employee count bucket, see taxonomy.CompanySizes
+}
+
+// Provider looks up company metadata
+type Provider interface {
+	// Name identifies the provider in the cache
+	Name() string
+
+	// Lookup returns the company's metadata, or nil if it is unknown
+	Lookup(company string) (*Company, error)
+}
+
+// Result summarises an enrichment pass
+type Result struct {
+	Profiles int `json:"profiles"` // Profiles updated
+	Fetched  int `json:"fetched"`  // Companies looked up with the provider
+	Cached   int `json:"cached"`   // Companies answered from the cache
+	Unknown  int `json:"unknown"`  // Companies the provider didn't know
+	Failed   int `json:"failed"`   // Lookups that returned an error
+}
+
+// Enricher applies provider data to stored profiles
+type Enricher struct {
+	provider Provider
+	storage  storage.Backend
+	maxAge   time.Duration // 0 keeps cache entries forever
+	log      *logger.ContextLogger
+}
+
+// New creates an enricher using the configured provider, or returns nil
+// when enrichment is disabled
+func New(cfg config.EnrichmentConfig, s storage.Backend) (*Enricher, error) {
+	var provider Provider
+	switch cfg.Provider {
+	case "none", "":
+		return nil, nil
+	case "csv":
+		p, err := NewCSV(cfg.CSVPath)
+		if err != nil {
+			return nil, err
+		}
+		provider = p
+	case "http":
+		provider = NewHTTP(cfg.URL, cfg.APIKey(), time.Duration(cfg.TimeoutSeconds)*time.Second)
+	default:
+		return nil, fmt.Errorf("unknown enrichment provider: %s", cfg.Provider)
+	}
+	return NewWithProvider(provider, s, time.Duration(cfg.CacheDays)*24*time.Hour), nil
+}
+
+// NewWithProvider creates an enricher around any provider. Cache entries
+// older than maxAge are looked up again; 0 keeps them forever.
+func NewWithProvider(p Provider, s storage.Backend, maxAge time.Duration) *Enricher {
+	return &Enricher{provider: p, storage: s, maxAge: maxAge, log: logger.NewContext("enrich")}
+}
+
+// EnrichAll fills company metadata on every profile with a company that
+// hasn't been enriched yet, or on all of them with refresh (which also
+// bypasses the cache)
+func (e *Enricher) EnrichAll(refresh bool) (Result, error) {
+	start := time.Now()
+	var result Result
+
+	looked := make(map[string]*storage.CompanyInfo)
+	var companies []*storage.CompanyInfo
+	var updated []*storage.Profile
+	for _, p := range e.storage.GetAllProfiles() {
+		if p.Company == "" || (!refresh && (p.Industry != "" || p.CompanySize != "")) {
+			continue
+		}
+		key := storage.CompanyKey(p.Company)
+		info, ok := looked[key]
+		if !ok {
+			var fetched bool
+			var err error
+			info, fetched, err = e.company(p.Company, refresh)
+			if err != nil {
+				e.log.Warn("Company lookup failed", "company", p.Company, "error", err)
+				result.Failed++
+			}
+			looked[key] = info
+			switch {
+			case info == nil:
+			case fetched:
+				result.Fetched++
+				companies = append(companies, info)
+			default:
+				result.Cached++
+			}
+			if info != nil && !info.Known() {
+				result.Unknown++
+			}
+		}
+		if info == nil || !info.Known() || (p.Industry == info.Industry && p.CompanySize == info.Size) {
+			continue
+		}
+		p.ApplyCompany(info)
+		updated = append(updated, p)
+	}
+	result.Profiles = len(updated)
+
+	var err error
+	if len(companies) > 0 || len(updated) > 0 {
+		err = e.storage.SaveCompanies(companies, updated)
+	}
+	logger.Timing("enrich", "enrich_all", start, err)
+	if err != nil {
+		return result, fmt.Errorf("failed to save enrichment: %w", err)
+	}
+	e.log.Info("Enrichment complete", "provider", e.provider.Name(), "profiles", result.Profiles,
+		"fetched", result.Fetched, "cached", result.Cached, "unknown", result.Unknown, "failed", result.Failed)
+	return result, nil
+}
+
+// company returns the metadata for a company from the cache or the
+// provider, and whether it was fetched. Failed lookups return nil so they
+// are retried next time.
+func (e *Enricher) company(name string, refresh bool) (*storage.CompanyInfo, bool, error) {
+	if cached := e.storage.CachedCompany(name); cached != nil && !refresh &&
+		cached.Source == e.provider.Name() && (e.maxAge == 0 || clock.Now().Sub(cached.FetchedAt) < e.maxAge) {
+		return cached, false, nil
+	}
+
+	c, err := e.provider.Lookup(name)
+	if err != nil {
+		return nil, false, err
+	}
+	info := &storage.CompanyInfo{Name: name, Source: e.provider.Name(), FetchedAt: clock.Now()}
+	if c != nil {
+		info.Industry, info.Size = c.Industry, c.Size
+	}
+	return info, true, nil
+}
@@ -0,0 +1,126 @@
+package enrich
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/storage"
+)
+
+func TestParseCSV(t *testing.T) {
+	p, err := ParseCSV(strings.NewReader("Size,Company,Industry,Notes\n51-200,\"Acme, Inc.\",Software,x\n12000,Globex,Manufacturing,\n,Initech,,\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for company, want := range map[string]*Company{
+		"ACME":     {Industry: "Software", Size: "51-200"},
+		"Globex":   {Industry: "Manufacturing", Size: "10001+"},
+		"Initech":  {},
+		"Umbrella": nil,
+	} {
+		got, _ := p.Lookup(company)
+		if (got == nil) != (want == nil) || got != nil && *got != *want {
+			t.Errorf("Lookup(%q) = %+v, want %+v", company, got, want)
+		}
+	}
+
+	if _, err := ParseCSV(strings.NewReader("company,size\nAcme,lots\n")); err == nil {
+		t.Error("ParseCSV accepted an invalid size")
+	}
+	if _, err := ParseCSV(strings.NewReader("name,size\n")); err == nil {
+		t.Error("ParseCSV accepted a file without a company column")
+	}
+}
+
+func TestHTTPProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Query().Get("company") {
+		case "Acme Inc.":
+			w.Write([]byte(`{"industry": "Software", "employees": 120}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	p := NewHTTP(srv.URL, "secret", time.Second)
+	if c, err := p.Lookup("Acme Inc."); err != nil || c == nil || *c != (Company{Industry: "Software", Size: "51-200"}) {
+		t.Errorf("Lookup(Acme) = %+v, %v", c, err)
+	}
+	if c, err := p.Lookup("Globex"); err != nil || c != nil {
+		t.Errorf("Lookup(unknown) = %+v, %v, want nil, nil", c, err)
+	}
+	if _, err := NewHTTP(srv.URL, "wrong", time.Second).Lookup("Acme Inc."); err == nil {
+		t.Error("Lookup ignored an error status")
+	}
+}
+
+// countingProvider counts lookups per company key
+type countingProvider struct {
+	calls map[string]int
+}
+
+func (p *countingProvider) Name() string { return "test" }
+
+func (p *countingProvider) Lookup(company string) (*Company, error) {
+	key := storage.CompanyKey(company)
+	p.calls[key]++
+	if key == "acme" {
+		return &Company{Industry: "Software", Size: "11-50"}, nil
+	}
+	return nil, nil
+}
+
+func TestEnrichAllCaches(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC))
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	db, err := storage.New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []*storage.Profile{
+		{ID: "a", Company: "Acme", ProfileURL: "https://x/a"},
+		{ID: "b", Company: "ACME Inc.", ProfileURL: "https://x/b"},
+		{ID: "c", Company: "Globex", ProfileURL: "https://x/c"},
+		{ID: "d", ProfileURL: "https://x/d"},
+	} {
+		if err := db.SaveProfile(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	provider := &countingProvider{calls: make(map[string]int)}
+	e := NewWithProvider(provider, db, 30*24*time.Hour)
+	result, err := e.EnrichAll(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Result{Profiles: 2, Fetched: 2, Unknown: 1}); result != want {
+		t.Errorf("first pass = %+v, want %+v", result, want)
+	}
+	if b, _ := db.GetProfile("b"); b.Industry != "Software" || b.CompanySize != "11-50" {
+		t.Errorf("profile b = %q/%q, want Software/11-50", b.Industry, b.CompanySize)
+	}
+
+	// Unknown companies are cached too, until they expire
+	if result, _ = e.EnrichAll(false); result != (Result{Cached: 1, Unknown: 1}) {
+		t.Errorf("second pass = %+v", result)
+	}
+	fake.Advance(31 * 24 * time.Hour)
+	e.EnrichAll(false)
+	if provider.calls["acme"] != 1 || provider.calls["globex"] != 2 {
+		t.Errorf("lookups = %v, want Acme once and Globex twice", provider.calls)
+	}
+}
@@ -0,0 +1,155 @@
+package enrich
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"subspace/internal/storage"
+	"subspace/internal/taxonomy"
+)
+
+// CSVProvider answers lookups from a CSV file with a header row naming the
+// columns company, industry and size (a bucket such as "51-200" or an
+// employee count). Column order doesn't matter and extra columns are
+// ignored.
+type CSVProvider struct {
+	companies map[string]*Company // CompanyKey -> metadata
+}
+
+// NewCSV loads a CSV provider from a file
+func NewCSV(path string) (*CSVProvider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open enrichment file: %w", err)
+	}
+	defer f.Close()
+	p, err := ParseCSV(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// ParseCSV reads a CSV provider's data
+func ParseCSV(r io.Reader) (*CSVProvider, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("missing header row")
+	}
+
+	col := map[string]int{"company": -1, "industry": -1, "size": -1}
+	for i, name := range rows[0] {
+		if _, ok := col[strings.ToLower(strings.TrimSpace(name))]; ok {
+			col[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+	}
+	if col["company"] < 0 {
+		return nil, fmt.Errorf("header has no company column")
+	}
+
+	get := func(row []string, name string) string {
+		if i := col[name]; i >= 0 && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+	p := &CSVProvider{companies: make(map[string]*Company)}
+	for n, row := range rows[1:] {
+		name := get(row, "company")
+		if name == "" {
+			continue
+		}
+		c := &Company{Industry: get(row, "industry")}
+		if raw := get(row, "size"); raw != "" {
+			if c.Size = taxonomy.NormalizeSize(raw); c.Size == "" {
+				return nil, fmt.Errorf("row %d: invalid size %q", n+2, raw)
+			}
+		}
+		p.companies[storage.CompanyKey(name)] = c
+	}
+	return p, nil
+}
+
+// Name identifies the provider
+func (p *CSVProvider) Name() string { return "csv" }
+
+// Lookup returns the row for a company, matched by CompanyKey
+func (p *CSVProvider) Lookup(company string) (*Company, error) {
+	return p.companies[storage.CompanyKey(company)], nil
+}
+
+// HTTPProvider queries an external API with GET <url>?company=<name> (or
+// <url> with {company} replaced) and a bearer token. The response is JSON
+// {"industry": "...", "size": "51-200"} or {"industry": "...",
+// "employees": 120}; 404 means the company is unknown.
+type HTTPProvider struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+// NewHTTP creates an HTTP provider
+func NewHTTP(baseURL, apiKey string, timeout time.Duration) *HTTPProvider {
+	return &HTTPProvider{url: baseURL, apiKey: apiKey, client: &http.Client{Timeout: timeout}}
+}
+
+// Name identifies the provider
+func (p *HTTPProvider) Name() string { return "http" }
+
+// Lookup queries the API for a company
+func (p *HTTPProvider) Lookup(company string) (*Company, error) {
+	target := p.url
+	if strings.Contains(target, "{company}") {
+		target = strings.ReplaceAll(target, "{company}", url.PathEscape(company))
+	} else {
+		sep := "?"
+		if strings.Contains(target, "?") {
+			sep = "&"
+		}
+		target += sep + "company=" + url.QueryEscape(company)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enrichment URL: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("enrichment request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("enrichment API returned %s", resp.Status)
+	}
+	var body struct {
+		Industry  string `json:"industry"`
+		Size      string `json:"size"`
+		Employees int    `json:"employees"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid enrichment response: %w", err)
+	}
+	c := &Company{Industry: strings.TrimSpace(body.Industry), Size: taxonomy.NormalizeSize(body.Size)}
+	if c.Size == "" {
+		c.Size = taxonomy.SizeBucket(body.Employees)
+	}
+	return c, nil
+}
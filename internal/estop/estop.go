@@ -0,0 +1,123 @@
+package estop
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"subspace/internal/logger"
+)
+
+/*
+EMERGENCY STOP MODULE
+
+For incident response, every externally visible action (logging in,
+searching, sending or withdrawing connection requests, sending messages and
+replies) can be refused at once, without killing the running instance:
+
+- create the stop file (app.stop_file, "subspace stop [reason]" or a plain
+  touch), or
+- set SUBSPACE_STOP to anything but "", "0" or "false".
+
+Modules call Check right before they act, so a run in progress stops at its
+next action and commands that only read storage keep working. Removing the
+file ("subspace resume") lifts the stop.
+*/
+
+// EnvVar engages the stop from the environment
+const EnvVar = "SUBSPACE_STOP"
+
+// ErrStopped is wrapped by Check while the stop is engaged
+var ErrStopped = errors.New("emergency stop engaged")
+
+var (
+	mu   sync.RWMutex
+	file string
+	log  = logger.NewContext("estop")
+)
+
+// SetFile sets the stop file; empty leaves only the environment variable
+func SetFile(path string) {
+	mu.Lock()
+	defer mu.Unlock()
+	file = path
+}
+
+// File returns the stop file
+func File() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return file
+}
+
+// Status is whether the stop is engaged, and by what
+type Status struct {
+	Engaged bool      `json:"engaged"`
+	Source  string    `json:"source,omitempty"` // "env" or the stop file
+	Reason  string    `json:"reason,omitempty"`
+	Since   time.Time `json:"since,omitempty"` // When the stop file was written
+}
+
+// State returns the current status, the environment variable first
+func State() Status {
+	if v := strings.TrimSpace(os.Getenv(EnvVar)); v != "" && v != "0" && !strings.EqualFold(v, "false") {
+		return Status{Engaged: true, Source: "env", Reason: EnvVar + "=" + v}
+	}
+	path := File()
+	if path == "" {
+		return Status{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Status{}
+	}
+	status := Status{Engaged: true, Source: path, Since: info.ModTime()}
+	if data, err := os.ReadFile(path); err == nil {
+		status.Reason, _, _ = strings.Cut(strings.TrimSpace(string(data)), "\n")
+	}
+	return status
+}
+
+// Check returns an error wrapping ErrStopped if the stop is engaged,
+// naming the action that was refused
+func Check(action string) error {
+	status := State()
+	if !status.Engaged {
+		return nil
+	}
+	log.Warn("Refusing action", "action", action, "source", status.Source, "reason", status.Reason)
+	if status.Reason != "" && status.Source != "env" {
+		return fmt.Errorf("%w (%s): refusing %s", ErrStopped, status.Reason, action)
+	}
+	return fmt.Errorf("%w (%s): refusing %s", ErrStopped, status.Source, action)
+}
+
+// Engage writes the stop file with the reason
+func Engage(reason string) error {
+	path := File()
+	if path == "" {
+		return fmt.Errorf("no stop file configured, set %s instead", EnvVar)
+	}
+	if err := os.WriteFile(path, []byte(reason+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write stop file: %w", err)
+	}
+	log.Warn("Emergency stop engaged", "file", path, "reason", reason)
+	return nil
+}
+
+// Release removes the stop file. A stop engaged from the environment
+// stays engaged.
+func Release() error {
+	path := File()
+	if path == "" {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove stop file: %w", err)
+	}
+	log.Info("Emergency stop released", "file", path)
+	return nil
+}
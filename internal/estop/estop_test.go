@@ -0,0 +1,41 @@
+package estop
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestStopFileAndEnvironment(t *testing.T) {
+	SetFile(filepath.Join(t.TempDir(), "STOP"))
+	defer SetFile("")
+	t.Setenv(EnvVar, "")
+
+	if err := Check("message"); err != nil {
+		t.Fatalf("Check before stop = %v", err)
+	}
+	if err := Engage("account flagged"); err != nil {
+		t.Fatal(err)
+	}
+	err := Check("message")
+	if !errors.Is(err, ErrStopped) {
+		t.Fatalf("Check with stop file = %v, want ErrStopped", err)
+	}
+	if s := State(); s.Reason != "account flagged" || s.Since.IsZero() {
+		t.Errorf("State = %+v", s)
+	}
+
+	if err := Release(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Check("message"); err != nil {
+		t.Errorf("Check after release = %v", err)
+	}
+
+	for value, engaged := range map[string]bool{"1": true, "yes": true, "0": false, "false": false} {
+		t.Setenv(EnvVar, value)
+		if got := State(); got.Engaged != engaged || engaged && got.Source != "env" {
+			t.Errorf("%s=%q: State = %+v, want engaged %v", EnvVar, value, got, engaged)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"subspace/internal/storage"
+)
+
+/*
+EVENTS MODULE
+
+Run data for external pipelines as JSON Lines, one event per line: every
+action log entry (type "action") and every message sent or received (type
+"message", without its text). Events come oldest first, so a consumer
+resumes with -since the time of the last event it has.
+
+Sinks:
+- a file, appended to
+- "-", standard output
+- an http(s) URL, POSTed batches of application/x-ndjson; Kafka REST
+  proxies and NATS HTTP bridges accept these
+
+Other transports implement Sink.
+*/
+
+// Event is one line of the export
+type Event struct {
+	Time      time.Time `json:"time"`
+	Type      string    `json:"type"` // "action" or "message"
+	Action    string    `json:"action,omitempty"`
+	ProfileID string    `json:"profile_id,omitempty"`
+	Account   string    `json:"account,omitempty"`
+	Source    string    `json:"source,omitempty"`
+	Success   *bool     `json:"success,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	MessageID string    `json:"message_id,omitempty"`
+	Template  string    `json:"template,omitempty"`
+	Inbound   bool      `json:"inbound,omitempty"`
+	Length    int       `json:"length,omitempty"` // Characters of the message
+}
+
+// Collect returns the events after since (all if zero), oldest first
+func Collect(db storage.Backend, since time.Time) []Event {
+	var events []Event
+	for _, l := range db.GetActionLogs("") {
+		if !l.Timestamp.After(since) {
+			continue
+		}
+		success := l.Success
+		events = append(events, Event{Time: l.Timestamp, Type: "action", Action: l.Action, ProfileID: l.ProfileID,
+			Account: l.Account, Source: l.Source, Success: &success, Error: l.Error})
+	}
+	for _, m := range db.GetMessagesSince(since) {
+		if !m.SentAt.After(since) {
+			continue
+		}
+		events = append(events, Event{Time: m.SentAt, Type: "message", ProfileID: m.ProfileID, MessageID: m.ID,
+			Template: m.Template, Inbound: m.Inbound, Length: len([]rune(m.Content))})
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events
+}
+
+// Sink receives exported events
+type Sink interface {
+	Write(events []Event) error
+	Close() error
+}
+
+// Open returns the sink for a target: "-" for standard output, an http(s)
+// URL, or else a file path
+func Open(target string) (Sink, error) {
+	switch {
+	case target == "-":
+		return NewWriter(nopCloser{os.Stdout}), nil
+	case strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://"):
+		return NewHTTP(target, 30*time.Second), nil
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event file: %w", err)
+	}
+	return NewWriter(f), nil
+}
+
+// Export writes events to the sink in batches of batchSize
+func Export(events []Event, sink Sink, batchSize int) error {
+	for start := 0; start < len(events); start += batchSize {
+		end := min(start+batchSize, len(events))
+		if err := sink.Write(events[start:end]); err != nil {
+			return fmt.Errorf("failed to export events %d-%d: %w", start+1, end, err)
+		}
+	}
+	return nil
+}
+
+// WriterSink writes JSON Lines to a stream
+type WriterSink struct {
+	w   io.WriteCloser
+	buf *bufio.Writer
+}
+
+// NewWriter creates a sink writing to w, which it closes with the sink
+func NewWriter(w io.WriteCloser) *WriterSink {
+	return &WriterSink{w: w, buf: bufio.NewWriter(w)}
+}
+
+// Write appends the events, one per line
+func (s *WriterSink) Write(events []Event) error {
+	if err := encode(s.buf, events); err != nil {
+		return err
+	}
+	return s.buf.Flush()
+}
+
+// Close closes the underlying stream
+func (s *WriterSink) Close() error {
+	return s.w.Close()
+}
+
+// HTTPSink POSTs each batch as one application/x-ndjson request
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTP creates an HTTP sink
+func NewHTTP(url string, timeout time.Duration) *HTTPSink {
+	return &HTTPSink{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+// Write posts the events; any status but 2xx is an error
+func (s *HTTPSink) Write(events []Event) error {
+	var body bytes.Buffer
+	if err := encode(&body, events); err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.url, "application/x-ndjson", &body)
+	if err != nil {
+		return fmt.Errorf("event sink request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("event sink returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Close does nothing; requests don't outlive Write
+func (s *HTTPSink) Close() error { return nil }
+
+// encode writes events as JSON Lines
+func encode(w io.Writer, events []Event) error {
+	enc := json.NewEncoder(w)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("failed to encode event: %w", err)
+		}
+	}
+	return nil
+}
+
+// nopCloser keeps standard output open when the sink is closed
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
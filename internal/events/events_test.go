@@ -0,0 +1,89 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/storage"
+)
+
+func testEvents(t *testing.T) (*storage.Storage, time.Time) {
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	clock.Set(fake)
+	t.Cleanup(func() { clock.Set(clock.Real{}) })
+
+	db, err := storage.New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.LogAction("connection", "p1", true, nil)
+	fake.Advance(time.Minute)
+	db.SaveMessage(&storage.Message{ID: "m1", ProfileID: "p1", Content: "Hallo Jürgen", SentAt: fake.Now(), Template: "follow_up"})
+	fake.Advance(time.Minute)
+	db.LogAction("message", "p2", false, errors.New("send failed"))
+	return db, start
+}
+
+func TestCollectAndWriteJSONLines(t *testing.T) {
+	db, start := testEvents(t)
+
+	var buf bytes.Buffer
+	if err := Export(Collect(db, time.Time{}), NewWriter(nopCloser{&buf}), 2); err != nil {
+		t.Fatal(err)
+	}
+	var got []Event
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("line %q: %v", scanner.Text(), err)
+		}
+		got = append(got, e)
+	}
+	if len(got) != 3 || got[0].Action != "connection" || got[1].Type != "message" || got[2].Error != "send failed" {
+		t.Fatalf("events = %+v", got)
+	}
+	if got[1].Length != 12 || got[2].Success == nil || *got[2].Success {
+		t.Errorf("message length %d, failure success %v", got[1].Length, got[2].Success)
+	}
+
+	if n := len(Collect(db, start.Add(time.Minute))); n != 1 {
+		t.Errorf("events after the message = %d, want 1", n)
+	}
+}
+
+func TestHTTPSinkPostsBatches(t *testing.T) {
+	db, _ := testEvents(t)
+
+	var lines []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/x-ndjson" {
+			t.Errorf("content type %q", r.Header.Get("Content-Type"))
+		}
+		var body bytes.Buffer
+		body.ReadFrom(r.Body)
+		lines = append(lines, bytes.Count(body.Bytes(), []byte("\n")))
+	}))
+	defer server.Close()
+
+	sink, err := Open(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Export(Collect(db, time.Time{}), sink, 2); err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 2 || lines[0] != 2 || lines[1] != 1 {
+		t.Errorf("batches = %v, want [2 1]", lines)
+	}
+}
@@ -0,0 +1,288 @@
+package geo
+
+import (
+	"sort"
+	"strings"
+)
+
+/*
+GEO MODULE
+
+Normalizes the free-text location on a profile ("Greater Munich
+Metropolitan Area", "SF Bay Area") into a structured place: city, region
+and country, plus the IANA time zone when it is unambiguous. Targeting can
+then select profiles by any level of the hierarchy ("Bavaria", "Germany"),
+and recipient send windows use the place's time zone.
+
+Names are matched on word boundaries, most specific level first (city, then
+region, then country) and longest name first within a level, so "New South
+Wales" wins over "Wales" and "Indianapolis" doesn't match "India".
+*/
+
+// Place is a normalized location. Fields below the matched level are empty,
+// e.g. a profile that only says "Germany" has no city or region.
+type Place struct {
+	City     string `json:"city,omitempty"`
+	Region   string `json:"region,omitempty"`
+	Country  string `json:"country"`
+	TimeZone string `json:"time_zone,omitempty"`
+}
+
+// Matches reports whether the place is in target, a city, region or
+// country name (or alias) from the normalization table
+func (p *Place) Matches(target string) bool {
+	if p == nil {
+		return false
+	}
+	t := Lookup(target)
+	if t == nil {
+		return false
+	}
+	switch {
+	case t.City != "":
+		return p.City == t.City && p.Country == t.Country
+	case t.Region != "":
+		return p.Region == t.Region && p.Country == t.Country
+	default:
+		return p.Country == t.Country
+	}
+}
+
+// String renders the place from the most to the least specific level
+func (p *Place) String() string {
+	if p == nil {
+		return ""
+	}
+	parts := make([]string, 0, 3)
+	for _, s := range []string{p.City, p.Region, p.Country} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// countries: name -> time zone, empty when the country spans several
+var countries = map[string]string{
+	"United States": "", "Canada": "", "Mexico": "America/Mexico_City", "Brazil": "",
+	"Argentina": "America/Argentina/Buenos_Aires", "United Kingdom": "Europe/London",
+	"Ireland": "Europe/Dublin", "Portugal": "Europe/Lisbon", "Spain": "Europe/Madrid",
+	"France": "Europe/Paris", "Netherlands": "Europe/Amsterdam", "Germany": "Europe/Berlin",
+	"Switzerland": "Europe/Zurich", "Austria": "Europe/Vienna", "Italy": "Europe/Rome",
+	"Sweden": "Europe/Stockholm", "Poland": "Europe/Warsaw", "Finland": "Europe/Helsinki",
+	"Greece": "Europe/Athens", "Turkey": "Europe/Istanbul", "Nigeria": "Africa/Lagos",
+	"Egypt": "Africa/Cairo", "South Africa": "Africa/Johannesburg",
+	"United Arab Emirates": "Asia/Dubai", "Israel": "Asia/Jerusalem", "India": "Asia/Kolkata",
+	"Singapore": "Asia/Singapore", "China": "Asia/Shanghai", "Japan": "Asia/Tokyo",
+	"South Korea": "Asia/Seoul", "Australia": "", "New Zealand": "Pacific/Auckland",
+}
+
+// regions: name -> country and time zone
+var regions = map[string]struct{ country, zone string }{
+	"California": {"United States", "America/Los_Angeles"}, "Washington State": {"United States", "America/Los_Angeles"},
+	"Texas": {"United States", "America/Chicago"}, "Illinois": {"United States", "America/Chicago"},
+	"Colorado": {"United States", "America/Denver"}, "New York State": {"United States", "America/New_York"},
+	"Massachusetts": {"United States", "America/New_York"}, "Georgia": {"United States", "America/New_York"},
+	"Florida": {"United States", "America/New_York"}, "Ontario": {"Canada", "America/Toronto"},
+	"British Columbia": {"Canada", "America/Vancouver"}, "São Paulo State": {"Brazil", "America/Sao_Paulo"},
+	"England": {"United Kingdom", "Europe/London"}, "Scotland": {"United Kingdom", "Europe/London"},
+	"Wales": {"United Kingdom", "Europe/London"}, "Catalonia": {"Spain", "Europe/Madrid"},
+	"Community of Madrid": {"Spain", "Europe/Madrid"}, "Île-de-France": {"France", "Europe/Paris"},
+	"North Holland": {"Netherlands", "Europe/Amsterdam"}, "Bavaria": {"Germany", "Europe/Berlin"},
+	"Berlin State": {"Germany", "Europe/Berlin"}, "Hamburg State": {"Germany", "Europe/Berlin"},
+	"Lombardy": {"Italy", "Europe/Rome"}, "Karnataka": {"India", "Asia/Kolkata"},
+	"Maharashtra": {"India", "Asia/Kolkata"}, "Telangana": {"India", "Asia/Kolkata"},
+	"New South Wales": {"Australia", "Australia/Sydney"}, "Victoria": {"Australia", "Australia/Melbourne"},
+}
+
+// cities: name -> region and country; the zone comes from the region or,
+// for cities without one here, the country
+var cities = map[string]struct{ region, country, zone string }{
+	"New York":      {"New York State", "United States", ""},
+	"Boston":        {"Massachusetts", "United States", ""},
+	"Atlanta":       {"Georgia", "United States", ""},
+	"Miami":         {"Florida", "United States", ""},
+	"Chicago":       {"Illinois", "United States", ""},
+	"Austin":        {"Texas", "United States", ""},
+	"Dallas":        {"Texas", "United States", ""},
+	"Denver":        {"Colorado", "United States", ""},
+	"San Francisco": {"California", "United States", ""},
+	"Los Angeles":   {"California", "United States", ""},
+	"San Diego":     {"California", "United States", ""},
+	"Seattle":       {"Washington State", "United States", ""},
+	"Toronto":       {"Ontario", "Canada", ""},
+	"Vancouver":     {"British Columbia", "Canada", ""},
+	"Mexico City":   {"", "Mexico", ""},
+	"São Paulo":     {"São Paulo State", "Brazil", ""},
+	"Buenos Aires":  {"", "Argentina", ""},
+	"London":        {"England", "United Kingdom", ""},
+	"Manchester":    {"England", "United Kingdom", ""},
+	"Edinburgh":     {"Scotland", "United Kingdom", ""},
+	"Cardiff":       {"Wales", "United Kingdom", ""},
+	"Dublin":        {"", "Ireland", ""},
+	"Lisbon":        {"", "Portugal", ""},
+	"Madrid":        {"Community of Madrid", "Spain", ""},
+	"Barcelona":     {"Catalonia", "Spain", ""},
+	"Paris":         {"Île-de-France", "France", ""},
+	"Amsterdam":     {"North Holland", "Netherlands", ""},
+	"Berlin":        {"Berlin State", "Germany", ""},
+	"Munich":        {"Bavaria", "Germany", ""},
+	"Hamburg":       {"Hamburg State", "Germany", ""},
+	"Zurich":        {"", "Switzerland", ""},
+	"Vienna":        {"", "Austria", ""},
+	"Milan":         {"Lombardy", "Italy", ""},
+	"Stockholm":     {"", "Sweden", ""},
+	"Warsaw":        {"", "Poland", ""},
+	"Helsinki":      {"", "Finland", ""},
+	"Athens":        {"", "Greece", ""},
+	"Istanbul":      {"", "Turkey", ""},
+	"Lagos":         {"", "Nigeria", ""},
+	"Cairo":         {"", "Egypt", ""},
+	"Johannesburg":  {"", "South Africa", ""},
+	"Cape Town":     {"", "South Africa", ""},
+	"Dubai":         {"", "United Arab Emirates", ""},
+	"Tel Aviv":      {"", "Israel", ""},
+	"Bengaluru":     {"Karnataka", "India", ""},
+	"Mumbai":        {"Maharashtra", "India", ""},
+	"Hyderabad":     {"Telangana", "India", ""},
+	"Delhi":         {"", "India", ""},
+	"Singapore":     {"", "Singapore", ""},
+	"Hong Kong":     {"", "China", "Asia/Hong_Kong"},
+	"Shanghai":      {"", "China", ""},
+	"Beijing":       {"", "China", ""},
+	"Tokyo":         {"", "Japan", ""},
+	"Seoul":         {"", "South Korea", ""},
+	"Sydney":        {"New South Wales", "Australia", ""},
+	"Melbourne":     {"Victoria", "Australia", ""},
+	"Auckland":      {"", "New Zealand", ""},
+}
+
+// aliases maps alternative spellings to a table name
+var aliases = map[string]string{
+	"nyc": "New York", "sf": "San Francisco", "bay area": "San Francisco", "silicon valley": "San Francisco",
+	"sao paulo": "São Paulo", "münchen": "Munich", "bangalore": "Bengaluru", "new delhi": "Delhi",
+	"zürich": "Zurich", "wien": "Vienna", "milano": "Milan", "lisboa": "Lisbon",
+	"usa": "United States", "us": "United States", "united states of america": "United States",
+	"uk": "United Kingdom", "great britain": "United Kingdom", "deutschland": "Germany",
+	"españa": "Spain", "the netherlands": "Netherlands", "holland": "Netherlands",
+	"uae": "United Arab Emirates", "korea": "South Korea", "bayern": "Bavaria",
+}
+
+// levels lists the lowercase names of each level, longest first
+var levels = func() [3][]string {
+	var l [3][]string
+	for name := range cities {
+		l[0] = append(l[0], strings.ToLower(name))
+	}
+	for name := range regions {
+		l[1] = append(l[1], strings.ToLower(name))
+	}
+	for name := range countries {
+		l[2] = append(l[2], strings.ToLower(name))
+	}
+	for alias, name := range aliases {
+		l[level(name)] = append(l[level(name)], alias)
+	}
+	for i := range l {
+		names := l[i]
+		sort.Slice(names, func(a, b int) bool {
+			if len(names[a]) != len(names[b]) {
+				return len(names[a]) > len(names[b])
+			}
+			return names[a] < names[b]
+		})
+	}
+	return l
+}()
+
+// canonical maps a lowercase name or alias to its table name
+var canonical = func() map[string]string {
+	c := make(map[string]string)
+	for name := range cities {
+		c[strings.ToLower(name)] = name
+	}
+	for name := range regions {
+		c[strings.ToLower(name)] = name
+	}
+	for name := range countries {
+		c[strings.ToLower(name)] = name
+	}
+	for alias, name := range aliases {
+		c[alias] = name
+	}
+	return c
+}()
+
+// level returns 0 for a city, 1 for a region and 2 for a country
+func level(name string) int {
+	if _, ok := cities[name]; ok {
+		return 0
+	}
+	if _, ok := regions[name]; ok {
+		return 1
+	}
+	return 2
+}
+
+// Normalize returns the place named in a free-text location, or nil if it
+// names nowhere in the table ("Remote", "Earth")
+func Normalize(location string) *Place {
+	l := " " + strings.ToLower(location) + " "
+	for _, names := range levels {
+		for _, name := range names {
+			if containsWord(l, name) {
+				return place(canonical[name])
+			}
+		}
+	}
+	return nil
+}
+
+// Lookup returns the place for an exact city, region or country name or
+// alias, or nil if the name is unknown
+func Lookup(name string) *Place {
+	table, ok := canonical[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil
+	}
+	return place(table)
+}
+
+// place builds the full place for a table name
+func place(name string) *Place {
+	if c, ok := cities[name]; ok {
+		p := &Place{City: name, Region: c.region, Country: c.country, TimeZone: c.zone}
+		if p.TimeZone == "" {
+			p.TimeZone = regions[c.region].zone
+		}
+		if p.TimeZone == "" {
+			p.TimeZone = countries[c.country]
+		}
+		return p
+	}
+	if r, ok := regions[name]; ok {
+		return &Place{Region: name, Country: r.country, TimeZone: r.zone}
+	}
+	return &Place{Country: name, TimeZone: countries[name]}
+}
+
+// containsWord reports whether keyword occurs in s on word boundaries
+func containsWord(s, keyword string) bool {
+	for i := 0; ; {
+		j := strings.Index(s[i:], keyword)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(keyword)
+		if !isLetter(s[start-1]) && (end >= len(s) || !isLetter(s[end])) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+// isLetter reports whether b is part of a word (ASCII letters and UTF-8
+// continuation bytes)
+func isLetter(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 0x80
+}
@@ -0,0 +1,48 @@
+package geo
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"San Francisco Bay Area":             "San Francisco, California, United States (America/Los_Angeles)",
+		"Greater Munich Metropolitan Area":   "Munich, Bavaria, Germany (Europe/Berlin)",
+		"München, Bayern":                    "Munich, Bavaria, Germany (Europe/Berlin)",
+		"Sydney, New South Wales, Australia": "Sydney, New South Wales, Australia (Australia/Sydney)",
+		"New South Wales":                    "New South Wales, Australia (Australia/Sydney)",
+		"Cardiff, Wales, United Kingdom":     "Cardiff, Wales, United Kingdom (Europe/London)",
+		"Tokyo, Japan":                       "Tokyo, Japan (Asia/Tokyo)",
+		"Germany":                            "Germany (Europe/Berlin)",
+		"United States":                      "United States ()",
+		"Indianapolis, Indiana":              "", // not India
+		"Remote":                             "",
+		"":                                   "",
+	}
+	for location, want := range tests {
+		got := ""
+		if p := Normalize(location); p != nil {
+			got = p.String() + " (" + p.TimeZone + ")"
+		}
+		if got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", location, got, want)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	munich := Normalize("Munich, Germany")
+	for target, want := range map[string]bool{
+		"Munich": true, "münchen": true, "Bavaria": true, "germany": true, "Deutschland": true,
+		"Berlin": false, "France": false, "Mars": false,
+	} {
+		if got := munich.Matches(target); got != want {
+			t.Errorf("Munich.Matches(%q) = %v, want %v", target, got, want)
+		}
+	}
+	if Normalize("Germany").Matches("Bavaria") {
+		t.Error("a country-only place matched one of its regions")
+	}
+	var unknown *Place
+	if unknown.Matches("Germany") {
+		t.Error("an unknown place matched a target")
+	}
+}
@@ -0,0 +1,98 @@
+package harden
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"subspace/internal/config"
+	"subspace/internal/logger"
+)
+
+/*
+FILE PERMISSION HARDENING
+
+The data directory holds session cookies, proxy credentials and the
+profile database. With hardening.enabled, at startup:
+- the process umask becomes 077, so every file and directory created from
+  then on is 0600/0700, whatever mode the writer asks for
+- each data directory must be owned by the user running subspace
+- files and directories already there are tightened to 0600/0700, or, with
+  refuse_insecure, a directory readable by group or others stops the run
+  instead, so the exposure is noticed and fixed deliberately
+*/
+
+const (
+	fileMode = 0600
+	dirMode  = 0700
+)
+
+// Apply hardens the given directories (created if missing) and returns how
+// many entries it tightened. It does nothing when hardening is disabled.
+func Apply(cfg config.HardeningConfig, dirs ...string) (int, error) {
+	if !cfg.Enabled {
+		return 0, nil
+	}
+	log := logger.NewContext("harden")
+	restrictUmask()
+
+	fixed := 0
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, dirMode); err != nil {
+			return fixed, fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+		info, err := os.Stat(dir)
+		if err != nil {
+			return fixed, fmt.Errorf("failed to stat %s: %w", dir, err)
+		}
+		if uid, ok := owner(info); ok && uid != os.Getuid() {
+			return fixed, fmt.Errorf("%s is owned by uid %d, not by the running user (uid %d)", dir, uid, os.Getuid())
+		}
+		if cfg.RefuseInsecure && info.Mode().Perm()&0077 != 0 {
+			return fixed, fmt.Errorf("%s is accessible to group or others (mode %04o); chmod 700 it", dir, info.Mode().Perm())
+		}
+
+		n, err := tighten(dir)
+		fixed += n
+		if err != nil {
+			return fixed, err
+		}
+	}
+	if fixed > 0 {
+		log.Warn("Tightened data permissions", "entries", fixed)
+	}
+	log.Info("Data permissions hardened", "dirs", len(dirs))
+	return fixed, nil
+}
+
+// tighten removes group and other access below dir and returns how many
+// entries it changed
+func tighten(dir string) (int, error) {
+	fixed := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil // Never follow a link out of the data directory
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		want := os.FileMode(fileMode)
+		if d.IsDir() {
+			want = dirMode
+		}
+		if info.Mode().Perm()&^want == 0 {
+			return nil
+		}
+		if err := os.Chmod(path, info.Mode().Perm()&want); err != nil {
+			return fmt.Errorf("failed to restrict %s: %w", path, err)
+		}
+		fixed++
+		return nil
+	})
+	return fixed, err
+}
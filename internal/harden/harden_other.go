@@ -0,0 +1,13 @@
+//go:build !unix
+
+package harden
+
+import "os"
+
+// restrictUmask is a no-op without umask; tighten still applies
+func restrictUmask() {}
+
+// owner is unknown without POSIX ownership
+func owner(info os.FileInfo) (int, bool) {
+	return 0, false
+}
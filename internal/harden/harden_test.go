@@ -0,0 +1,47 @@
+package harden
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"subspace/internal/config"
+)
+
+func TestApplyTightensPermissions(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+	if err := os.MkdirAll(filepath.Join(dir, "backups"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.Chmod(dir, 0755)
+	if err := os.WriteFile(filepath.Join(dir, "db.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := Apply(config.HardeningConfig{}, dir); n != 0 || err != nil {
+		t.Fatalf("disabled: fixed %d, err %v", n, err)
+	}
+	if _, err := Apply(config.HardeningConfig{Enabled: true, RefuseInsecure: true}, dir); err == nil {
+		t.Fatal("refuse_insecure accepted a world-readable data dir")
+	}
+
+	n, err := Apply(config.HardeningConfig{Enabled: true}, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("fixed %d entries, want 3", n)
+	}
+	for path, want := range map[string]os.FileMode{dir: 0700, filepath.Join(dir, "backups"): 0700, filepath.Join(dir, "db.json"): 0600} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%s: mode %04o, want %04o", path, got, want)
+		}
+	}
+	if _, err := Apply(config.HardeningConfig{Enabled: true, RefuseInsecure: true}, dir); err != nil {
+		t.Errorf("refused a hardened dir: %v", err)
+	}
+}
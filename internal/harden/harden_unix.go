@@ -0,0 +1,22 @@
+//go:build unix
+
+package harden
+
+import (
+	"os"
+	"syscall"
+)
+
+// restrictUmask makes new files 0600 and new directories 0700
+func restrictUmask() {
+	syscall.Umask(0077)
+}
+
+// owner returns the uid owning a file
+func owner(info os.FileInfo) (int, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return int(st.Uid), true
+}
@@ -0,0 +1,129 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"subspace/internal/config"
+	"subspace/internal/logger"
+	"subspace/internal/storage"
+)
+
+/*
+HOOKS MODULE
+
+Scripts that customize decisions without recompiling. A hook is a shell
+command, run with a JSON document on stdin, so it can be written in any
+language: "lua hooks/can_send.lua" and "node hooks/mutate.js" work as well
+as a shell script.
+
+- can_send runs before every connection request and message with
+  {"action": "connection"|"message", "profile": {...}}. Exit status 0
+  allows the action; 1 vetoes it, stdout giving the reason. Any other
+  failure (another status, a crash, the timeout) vetoes it too: a broken
+  policy script must not let actions through.
+- on_accepted runs when a request is found accepted, with
+  {"profile": {...}}. Its output is ignored and failures are only logged.
+- mutate_message runs after a message is rendered, with {"profile": {...},
+  "template": "...", "content": "..."}. Non-empty stdout replaces the
+  content; a failure stops the message from being sent.
+*/
+
+// ErrVetoed is wrapped by CanSend when the hook refuses an action
+var ErrVetoed = errors.New("vetoed by can_send hook")
+
+// Hooks runs the configured hook commands. A nil *Hooks runs none.
+type Hooks struct {
+	cfg     config.HooksConfig
+	timeout time.Duration
+	log     *logger.ContextLogger
+}
+
+// New returns the hooks of the config, or nil if none are set
+func New(cfg config.HooksConfig) *Hooks {
+	if cfg.CanSend == "" && cfg.OnAccepted == "" && cfg.MutateMessage == "" {
+		return nil
+	}
+	return &Hooks{cfg: cfg, timeout: time.Duration(cfg.TimeoutSeconds) * time.Second, log: logger.NewContext("hooks")}
+}
+
+// CanSend asks the can_send hook whether action may be taken on profile
+func (h *Hooks) CanSend(action string, profile *storage.Profile) error {
+	if h == nil || h.cfg.CanSend == "" {
+		return nil
+	}
+	out, err := h.run(h.cfg.CanSend, map[string]any{"action": action, "profile": profile})
+	var exit *exec.ExitError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &exit) && exit.ExitCode() == 1:
+		reason := strings.TrimSpace(out)
+		if reason == "" {
+			reason = "no reason given"
+		}
+		return fmt.Errorf("%w: %s", ErrVetoed, reason)
+	default:
+		h.log.Error("can_send hook failed, vetoing", "action", action, "profile", profile.ID, "error", err)
+		return fmt.Errorf("%w: hook failed: %v", ErrVetoed, err)
+	}
+}
+
+// OnAccepted tells the on_accepted hook about an accepted request
+func (h *Hooks) OnAccepted(profile *storage.Profile) {
+	if h == nil || h.cfg.OnAccepted == "" {
+		return
+	}
+	if _, err := h.run(h.cfg.OnAccepted, map[string]any{"profile": profile}); err != nil {
+		h.log.Warn("on_accepted hook failed", "profile", profile.ID, "error", err)
+	}
+}
+
+// MutateMessage returns the content as rewritten by the mutate_message
+// hook, or unchanged when the hook prints nothing
+func (h *Hooks) MutateMessage(profile *storage.Profile, template, content string) (string, error) {
+	if h == nil || h.cfg.MutateMessage == "" {
+		return content, nil
+	}
+	out, err := h.run(h.cfg.MutateMessage, map[string]any{"profile": profile, "template": template, "content": content})
+	if err != nil {
+		return "", fmt.Errorf("mutate_message hook failed: %w", err)
+	}
+	if mutated := strings.TrimRight(out, "\r\n"); strings.TrimSpace(mutated) != "" {
+		return mutated, nil
+	}
+	return content, nil
+}
+
+// run executes a hook command with input as JSON on stdin and returns its
+// stdout
+func (h *Hooks) run(command string, input any) (string, error) {
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode hook input: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.WaitDelay = time.Second // Don't wait on children still holding stdout
+	cmd.Stdin = bytes.NewReader(payload)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	start := time.Now()
+	err = cmd.Run()
+	h.log.Debug("Ran hook", "command", command, "duration_ms", time.Since(start).Milliseconds(), "error", err)
+	if ctx.Err() != nil {
+		return stdout.String(), fmt.Errorf("timed out after %s", h.timeout)
+	}
+	if err != nil && stderr.Len() > 0 {
+		h.log.Warn("Hook wrote to stderr", "command", command, "stderr", strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), err
+}
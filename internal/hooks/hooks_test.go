@@ -0,0 +1,63 @@
+package hooks
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"subspace/internal/config"
+	"subspace/internal/storage"
+)
+
+func TestCanSendExitStatus(t *testing.T) {
+	profile := &storage.Profile{ID: "p1", Company: "Competitor Inc"}
+	tests := []struct {
+		command string
+		vetoed  bool
+		reason  string
+	}{
+		{`grep -q '"action":"message"'`, true, "no reason given"}, // exit 1: no match
+		{`grep -q Competitor && echo competitor && exit 1; exit 0`, true, "competitor"},
+		{`cat >/dev/null`, false, ""},
+		{`exit 2`, true, "hook failed"},
+		{`sleep 5`, true, "timed out"},
+	}
+	for _, tt := range tests {
+		h := New(config.HooksConfig{CanSend: tt.command, TimeoutSeconds: 1})
+		err := h.CanSend("connection", profile)
+		if got := errors.Is(err, ErrVetoed); got != tt.vetoed {
+			t.Errorf("%s: CanSend = %v, want vetoed %v", tt.command, err, tt.vetoed)
+		}
+		if tt.vetoed && !strings.Contains(err.Error(), tt.reason) {
+			t.Errorf("%s: CanSend = %v, want reason %q", tt.command, err, tt.reason)
+		}
+	}
+}
+
+func TestMutateMessageAndOnAccepted(t *testing.T) {
+	dir := t.TempDir()
+	seen := filepath.Join(dir, "accepted.json")
+	h := New(config.HooksConfig{
+		MutateMessage:  `sed -n 's/.*"content":"\([^"]*\)".*/\1 -- sent from my phone/p'`,
+		OnAccepted:     "cat > " + seen,
+		TimeoutSeconds: 5,
+	})
+	profile := &storage.Profile{ID: "p1", Name: "Ada"}
+
+	got, err := h.MutateMessage(profile, "follow_up", "Hi Ada")
+	if err != nil || got != "Hi Ada -- sent from my phone" {
+		t.Errorf("MutateMessage = %q, %v", got, err)
+	}
+
+	h.OnAccepted(profile)
+	if data, err := os.ReadFile(seen); err != nil || !strings.Contains(string(data), `"name":"Ada"`) {
+		t.Errorf("on_accepted input = %s, %v", data, err)
+	}
+
+	var none *Hooks
+	if got, err := none.MutateMessage(profile, "follow_up", "Hi"); got != "Hi" || err != nil || none.CanSend("message", profile) != nil {
+		t.Error("nil hooks changed behavior")
+	}
+}
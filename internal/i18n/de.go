@@ -0,0 +1,372 @@
+package i18n
+
+// de is the German catalog
+var de = map[string]string{
+	// Automation workflow
+	"run.outside_hours":       "Die aktuelle Uhrzeit liegt außerhalb der konfigurierten Geschäftszeiten",
+	"run.outside_hours_hint":  "Passe business_hours in config.yaml an oder starte einen beaufsichtigten Lauf mit: subspace session start",
+	"run.day_off":             "Heute ist ein freier Tag (%s) - keine Automatisierung",
+	"run.step_auth":           "Schritt 1: Anmeldung",
+	"run.login_failed":        "Anmeldung fehlgeschlagen: %v",
+	"run.login_failed_note":   "HINWEIS: Im PoC-Modus zu erwarten (keine echten Zugangsdaten)",
+	"run.login_ok":            "Anmeldung erfolgreich (Sitzung wiederhergestellt oder simuliert)",
+	"run.browser_recovered":   "Browser während %s abgestürzt; neu gestartet und fortgesetzt (Neustart %d von %d)",
+	"run.browser_recycled":    "Browser vom Ressourcen-Watchdog neu gestartet; Sitzung beibehalten",
+	"run.panic":               "Schritt %s ist mit einer Panic abgebrochen; der Lauf ging weiter. Absturzbericht: %s",
+	"proxy.unhealthy":         "Proxy %s hat die Prüfung nicht bestanden: %v",
+	"proxy.failover":          "Vom Proxy %s auf Ersatz %s umgeschaltet",
+	"proxy.rotated":           "Proxy-Sitzung auf %s für eine neue Ausgangs-IP gewechselt",
+	"proxy.none_healthy":      "Kein funktionierender Proxy für dieses Konto (zuletzt %s); Abbruch",
+	"run.module_disabled":     "Übersprungen: das Modul %s ist in config.yaml deaktiviert",
+	"run.plan":                "Ablaufreihenfolge: %s",
+	"run.round":               "Runde %d (Stapel zu je %d)",
+	"run.step_search":         "Schritt 2: Suche & Entdeckung",
+	"run.search_failed":       "Suche fehlgeschlagen: %v",
+	"run.search_ok":           "Suche abgeschlossen - Profile entdeckt",
+	"run.enriched":            "Firmendaten zu %d Profilen hinzugefügt",
+	"run.step_connect":        "Schritt 3: Kontaktanfragen",
+	"run.connect_failed":      "Verarbeitung der Kontaktanfragen fehlgeschlagen: %v",
+	"run.connect_ok":          "Kontaktanfragen verarbeitet",
+	"run.connect_limit":       "Tageslimit für Kontaktanfragen erreicht",
+	"run.search_limit":        "Tageslimit für Suchen erreicht",
+	"run.step_accepted":       "Schritt 4: Angenommene Kontakte prüfen",
+	"run.accepted_found":      "%d angenommene Kontakte gefunden",
+	"run.step_message":        "Schritt 5: Folgenachrichten",
+	"run.message_failed":      "Nachrichtenversand fehlgeschlagen: %v",
+	"run.message_ok":          "Folgenachrichten gesendet",
+	"run.batch_counts":        "%d gesendet, %d fehlgeschlagen, %d übersprungen",
+	"run.batch_timed_out":     "%d haben ihr Zeitbudget überschritten und wurden abgebrochen",
+	"run.message_limit":       "Tageslimit für Nachrichten erreicht",
+	"run.session_cap":         "Nachrichtenobergrenze der Sitzung erreicht (%d); der Rest wartet auf die nächste Sitzung",
+	"run.inbox_waiting":       "%d Unterhaltungen warten auf deine Antwort; beantworte sie mit: subspace inbox",
+	"run.summary":             "Zusammenfassung",
+	"run.summary_connections": "Kontaktanfragen heute: %v/%v",
+	"run.summary_messages":    "Nachrichten heute: %v/%v",
+	"run.summary_pending":     "Offene Anfragen: %v",
+	"run.summary_accepted":    "Angenommene Kontakte: %v",
+	"run.summary_overhead":    "%v: %v Tarnpausen, %v Arbeit (%v%% Pausen)",
+	"run.keep_open":           "Browser bleibt %d Sekunden geöffnet...",
+
+	// Demo mode
+	"demo.title":                 "DEMONSTRATION DER STEALTH-TECHNIKEN",
+	"demo.mouse":                 "Mausbewegung mit Bézierkurven",
+	"demo.mouse_doing":           "Maus wird von (100,100) nach (800,600) bewegt...",
+	"demo.mouse_done":            "Weiche, gekrümmte Bahn demonstriert",
+	"demo.typing":                "Menschenähnliche Tippsimulation",
+	"demo.typing_sample":         "Hallo, dies ist eine Testnachricht",
+	"demo.typing_doing":          "Tippe: '%s'",
+	"demo.typing_done":           "Variable Geschwindigkeit + gelegentliche Tippfehler demonstriert",
+	"demo.scroll":                "Natürliches Scrollverhalten",
+	"demo.scroll_doing":          "Zufälliges Scrollen...",
+	"demo.scroll_done":           "Beschleunigtes Scrollen mit Physik demonstriert",
+	"demo.wander":                "Umherwandernde Maus",
+	"demo.wander_doing":          "Leseverhalten wird simuliert...",
+	"demo.wander_done":           "Zufällige Mikrobewegungen demonstriert",
+	"demo.timing":                "Zufällige Zeitabstände",
+	"demo.timing_delay":          "Aktionsverzögerung...",
+	"demo.timing_delay_done":     "%dms verzögert (zufällig)",
+	"demo.timing_think":          "Denkpause...",
+	"demo.timing_think_done":     "%dms pausiert (Nachdenken simuliert)",
+	"demo.hours":                 "Einhaltung der Geschäftszeiten",
+	"demo.hours_in":              "Aktuell innerhalb der Geschäftszeiten",
+	"demo.hours_out":             "Aktuell außerhalb der Geschäftszeiten",
+	"demo.fingerprint":           "Maskierung des Browser-Fingerabdrucks",
+	"demo.fingerprint_webdriver": "WebDriver-Kennzeichen maskiert",
+	"demo.fingerprint_viewport":  "Fenstergröße randomisiert",
+	"demo.fingerprint_done":      "Fingerabdruck-Techniken aktiv",
+	"demo.cooldown":              "Ratenbegrenzung & Abkühlphase",
+	"demo.cooldown_doing":        "%d Sekunden Abkühlphase werden erzwungen...",
+	"demo.cooldown_done":         "Abkühlphase eingehalten (%dms)",
+	"demo.complete":              "Demo abgeschlossen! Über 8 Stealth-Techniken vorgeführt.",
+	"demo.check_logs":            "Details zu Zeiten und Ausführung stehen in den Logs",
+
+	// Statistics
+	"stats.title":                 "AUTOMATISIERUNGSSTATISTIK",
+	"stats.profile_states":        "Profilstatus:",
+	"stats.discovered":            "Entdeckt",
+	"stats.approved":              "Freigegeben",
+	"stats.skipped":               "Übersprungen",
+	"stats.requested":             "Angefragt",
+	"stats.accepted":              "Angenommen",
+	"stats.cooled_down":           "Ruhend",
+	"stats.rejected":              "Abgelehnt",
+	"stats.total":                 "GESAMT",
+	"stats.activity_today":        "Aktivität heute:",
+	"stats.connections":           "Anfragen",
+	"stats.messages":              "Nachrichten",
+	"stats.total_messages":        "Nachr. gesamt",
+	"stats.recent":                "Letzte Aktivität:",
+	"stats.by_source":             "Heute nach Quelle:",
+	"stats.connections_last_hour": "Anfragen (letzte Stunde)",
+	"stats.activity_title":        "AKTIVITÄT",
+	"stats.filter_campaign":       "Kampagne %s",
+	"stats.filter_account":        "Konto %s",
+	"stats.accepted_rate":         "%d (%d angenommen, %.0f%%)",
+	"stats.searches":              "Suchen",
+	"stats.failures":              "Fehler",
+	"stats.by_campaign":           "Nach Kampagne:",
+	"stats.by_account":            "Nach Konto:",
+	"stats.by_day":                "Nach Tag:",
+	"stats.breakdown_header":      "\tANFRAGEN\tANGENOMMEN\tNACHRICHTEN\tSUCHEN\tFEHLER",
+
+	// Latency
+	"latency.title":      "ANNAHMELATENZ NACH SUCHBEGRIFF",
+	"latency.none":       "Noch keine Kontaktanfragen gesendet",
+	"latency.header":     "SUCHBEGRIFF\tANGENOMMEN\tOFFEN\tMEDIAN\tP90\tMAX\tOFFEN > P90",
+	"latency.all":        "(alle)",
+	"latency.no_keyword": "(ohne Suchbegriff)",
+	"latency.hint":       "Anfragen, die länger als die P90-Latenz offen sind, werden kaum noch angenommen; erwäge, sie zurückzuziehen",
+
+	// Accounts
+	"accounts.title":          "KONTEN (%d)",
+	"accounts.none":           "Keine Konten konfiguriert",
+	"accounts.header":         "KONTO\tPROXY\tZEITZONE\tPERSONA\tSTATUS",
+	"accounts.bound":          "gebunden",
+	"accounts.unregistered":   "noch nicht gelaufen",
+	"accounts.changed":        "GEÄNDERT - ausführen: subspace accounts rebind",
+	"accounts.not_configured": "registriert, nicht in der Konfiguration",
+	"accounts.rebound":        "Konto %s neu an seinen konfigurierten Proxy, Zeitzone, Persona und Fingerabdruck gebunden",
+	"accounts.exported":       "%s nach %s exportiert (%d Cookies); Passphrase getrennt von der Datei aufbewahren",
+	"accounts.imported":       "%s importiert (exportiert am %s): Bindung registriert, %d Cookies und Web-Speicher wiederhergestellt",
+	"accounts.add_config":     "Konto in config.yaml unter accounts eintragen:",
+	"accounts.passphrase":     "Passphrase des Pakets (oder %s setzen):",
+
+	// Plan
+	"plan.title":           "PLAN FÜR DEN NÄCHSTEN LAUF",
+	"plan.within_hours":    "Innerhalb der Geschäftszeiten",
+	"plan.outside_hours":   "Außerhalb der Geschäftszeiten - es wird nichts ausgeführt",
+	"plan.day_off":         "Freier Tag (%s) - es wird nichts ausgeführt",
+	"plan.searches":        "Suchen",
+	"plan.connections":     "Anfragen",
+	"plan.messages":        "Nachrichten",
+	"plan.step":            "%d geplant (%d Kandidaten, %d/%d heute genutzt)",
+	"plan.disabled":        "in config.yaml deaktiviert",
+	"plan.awaiting_review": "+ %d warten auf Prüfung (subspace review)",
+	"plan.campaign":        "- %s: %d von %d Kandidaten (Priorität %d, Gewicht %g)",
+	"plan.recipient_night": "+ %d warten auf Tageszeit in der Zeitzone des Empfängers",
+	"plan.snoozed":         "%d Profile pausiert; siehe: subspace snooze list",
+
+	"schedule.title":        "ZEITPLAN, NÄCHSTE %d STUNDEN",
+	"schedule.none":         "Nichts geplant",
+	"schedule.header":       "WANN\tBIS\tWAS\tDETAIL",
+	"schedule.session":      "Sitzung",
+	"schedule.work_session": "Arbeitssitzung",
+	"schedule.day_off":      "Freier Tag",
+	"schedule.snooze_ends":  "Pausierung endet",
+	"schedule.budget_frees": "Limit gibt Platz frei",
+
+	// Profiles
+	"profiles.title":          "PROFILE (%d)",
+	"profiles.page":           "Zeige %d-%d von %d",
+	"profiles.header":         "ID\tNAME\tPOSITION\tFIRMA\tSTATUS\tENTDECKT\tTAGS",
+	"profiles.tagged":         "%s (%s) getaggt: %s",
+	"profiles.untagged":       "Tags von %s (%s) entfernt: %s",
+	"profiles.imported":       "%d Profile importiert (%d Duplikate, %d ungültige Zeilen übersprungen)",
+	"profiles.import_dry_run": "%d Profile würden importiert (%d Duplikate, %d ungültige Zeilen übersprungen)",
+
+	// Profile deduplication
+	"dedupe.none":     "Keine doppelten Profile gefunden",
+	"dedupe.title":    "DOPPELTE PROFILE (%d Gruppen)",
+	"dedupe.group":    "Gruppe %d: %s behalten, %d zusammenführen",
+	"dedupe.field":    "FELD",
+	"dedupe.keep":     "(behalten)",
+	"dedupe.merged":   "ERGEBNIS",
+	"dedupe.messages": "Nachrichten",
+	"dedupe.dry_run":  "Nur Probelauf; mit -apply erneut ausführen, um zusammenzuführen",
+	"dedupe.applied":  "%d doppelte Profile in %d zusammengeführt",
+
+	// Review
+	"review.title":       "PRÜFWARTESCHLANGE (%d)",
+	"review.header":      "ID\tNAME\tPOSITION\tFIRMA\tGEMEINSAM\tENTDECKT\tLETZTE NOTIZ",
+	"review.prompt":      "[a] freigeben, [s] überspringen, [n] weiter, No[t]iz hinzufügen, [q] beenden:",
+	"review.note_prompt": "Notiz:",
+	"review.empty":       "Die Prüfwarteschlange ist leer",
+	"review.found_by":    "Gefunden über die Suche: %s",
+	"review.mutuals":     "%d gemeinsame Kontakte",
+	"review.summary":     "%d freigegeben, %d übersprungen",
+	"review.decided":     "%s (%s): %s",
+
+	// Inbox
+	"inbox.title":        "POSTEINGANG (%d unbeantwortet)",
+	"inbox.header":       "ID\tNAME\tWARTET SEIT\tANTWORTEN\tLETZTE",
+	"inbox.empty":        "Keine unbeantworteten Antworten",
+	"inbox.prompt":       "[r] antworten, [n] weiter, [q] beenden:",
+	"inbox.reply_prompt": "Antwort:",
+	"inbox.them":         "Kontakt",
+	"inbox.you":          "du",
+	"inbox.sent":         "Antwort an %s gesendet",
+	"inbox.summary":      "%d Antworten gesendet",
+
+	// Message archive
+	"messages.search_title":  "Nachrichten und Notizen durchsucht: %d Treffer für %q",
+	"messages.search_empty":  "Keine Nachricht und keine Notiz passt",
+	"messages.search_header": "ZEIT\tART\tPROFIL\tNAME\tTEXT",
+	"messages.kind_sent":     "gesendet",
+	"messages.kind_received": "empfangen",
+	"messages.kind_note":     "Notiz",
+
+	// Snooze
+	"snooze.title":       "PAUSIERTE PROFILE (%d)",
+	"snooze.header":      "ID\tNAME\tSTATUS\tBIS\tGRUND",
+	"snooze.none":        "Keine Profile pausiert",
+	"snooze.set":         "%s (%s) pausiert bis %s",
+	"snooze.cleared":     "%s (%s) ist nicht mehr pausiert",
+	"enrich.done":        "%d Profile angereichert (%d Firmen abgefragt, %d aus dem Cache, %d unbekannt)",
+	"enrich.failed":      "%d Firmenabfragen fehlgeschlagen, siehe Log",
+	"experiment.running": "Experiment %s läuft: %d Varianten x %d Läufe in der Simulation...",
+	"experiment.title":   "EXPERIMENT %s (%d Profile, %d Tage, Seeds %v)",
+	"experiment.header":  "VARIANTE\tVERBINDUNGEN\tANGENOMMEN\tQUOTE\tNACHRICHTEN\tSPITZE/H\tMEDIAN-PAUSE\tPAUSEN-VK\tTAGES-VK\tAKTIVE H\tVERSTÖSSE\t",
+	"experiment.legend":  "Mittelwerte pro Lauf. Niedrige Variationskoeffizienten (VK) bedeuten maschinenhaft gleichmäßiges Tempo; SPITZE/H ist die aktivste gleitende Stunde.",
+	"experiment.none":    "Keine Experimente konfiguriert (siehe Abschnitt experiments in config.yaml)",
+	"experiment.item":    "%s: Varianten %v, %d Tage, %d Läufe",
+	"replay.running":     "Spiele %d aufgezeichnete Aktionen aus %s in der Simulation ab...",
+	"replay.header":      "\t#\tAKTION\tPROFIL\tENTSCHEIDUNGEN\tAUFGEZEICHNET\tABGESPIELT\tABWEICHUNG\t",
+	"replay.divergence":  "bei Entscheidung %d: aufgezeichnet %s, angefragt %s",
+	"replay.summary":     "%d von %d Aktionen identisch abgespielt",
+
+	// Queue
+	"queue.title":                "WARTESCHLANGE (%d)",
+	"queue.header":               "#\tAKTION\tID\tNAME\tPRIORITÄT\tGEPLANT",
+	"queue.none":                 "Nichts wartet auf den Versand",
+	"queue.past_horizon":         "nach %d h",
+	"queue.prioritized":          "%s (%s) hat jetzt Priorität %d in der Warteschlange",
+	"queue.deferred":             "%s (%s) zurückgestellt bis %s",
+	"queue.cancelled_connection": "%s (%s) erhält keine Kontaktanfrage",
+	"queue.cancelled_message":    "%s (%s) erhält keine erste Nachricht",
+
+	// Templates
+	"templates.preview": "%s für %s",
+	"templates.tested":  "%d Vorlagenprüfungen, %d fehlgeschlagen",
+
+	// Burn-down
+	"burndown.title":         "BUDGETVERBRAUCH %s (aktiv %s-%s)",
+	"burndown.summary":       "%s: %d/%d verbraucht, %d bis jetzt erwartet (%s)",
+	"burndown.pace_ahead":    "schneller als geplant",
+	"burndown.pace_on_track": "im Plan",
+	"burndown.pace_behind":   "langsamer als geplant",
+	"burndown.lasts":         "Das Budget reicht beim aktuellen Tempo für den Tag",
+	"burndown.projected":     "Das Budget ist beim aktuellen Tempo gegen %s aufgebraucht",
+	"burndown.exhausted":     "Das Budget war um %s aufgebraucht",
+	"burndown.hour":          "%d (Ziel %d)",
+	"burndown.hour_future":   "(Ziel %d)",
+
+	// Bandwidth
+	"bandwidth.run":         "Bandbreite dieses Laufs: %s in %d Anfragen",
+	"bandwidth.title":       "BANDBREITE (letzte %d Tage)",
+	"bandwidth.none":        "Noch keine gemessenen Läufe",
+	"bandwidth.header":      "START\tDAUER\tEMPFANGEN\tANFRAGEN\tKONTO",
+	"bandwidth.total":       "Gesamt",
+	"bandwidth.cost":        "Kosten",
+	"bandwidth.by_step":     "Nach Schritt:",
+	"bandwidth.by_campaign": "Nach Kampagne:",
+
+	// Cookies
+	"cookies.title":           "GESPEICHERTE COOKIES (%d in %s)",
+	"cookies.none":            "Keine gespeicherte Sitzung",
+	"cookies.header":          "NAME\tDOMAIN\tLÄUFT AB\tGRÖSSE\tRICHTLINIE",
+	"cookies.session":         "Sitzung",
+	"cookies.verdict_keep":    "behalten",
+	"cookies.verdict_drop":    "verwerfen",
+	"cookies.verdict_expired": "abgelaufen",
+	"cookies.removed":         "%d von %d Cookies entfernt",
+	"cookies.would_remove":    "Würde %d von %d Cookies entfernen",
+
+	// Notes
+	"notes.title": "NOTIZEN ZU %s (%s)",
+	"notes.none":  "Noch keine Notizen; füge eine hinzu mit: subspace notes add <profile-id> <text>",
+	"notes.added": "Notiz zu %s (%s) hinzugefügt",
+
+	// Maintenance
+	"maintenance.running":        "Aufbewahrungswartung läuft...",
+	"maintenance.title":          "AUFBEWAHRUNGSBERICHT",
+	"maintenance.profiles":       "Profile",
+	"maintenance.messages":       "Nachrichten",
+	"maintenance.action_logs":    "Aktionslogs",
+	"maintenance.screenshots":    "Screenshots",
+	"maintenance.traces":         "Traces",
+	"maintenance.duplicates":     "Duplikate",
+	"maintenance.merged":         "%d zusammengeführt",
+	"maintenance.total":          "GESAMT",
+	"maintenance.purged":         "%d gelöscht (%s)",
+	"maintenance.total_line":     "%d in %dms",
+	"maintenance.forever":        "unbegrenzt aufbewahrt",
+	"maintenance.older_than":     "älter als %d Tage",
+	"maintenance.compacting":     "Speicher wird verdichtet...",
+	"maintenance.compact_title":  "VERDICHTUNGSBERICHT",
+	"maintenance.tombstones":     "Grabsteine",
+	"maintenance.duplicate_logs": "Doppelte Logs",
+	"maintenance.orphaned":       "Verwaiste Nachr.",
+	"maintenance.size":           "db.json",
+	"maintenance.size_line":      "%s -> %s",
+	"maintenance.verify_title":   "INTEGRITÄTSPRÜFUNG",
+	"maintenance.verify_ok":      "Keine Inkonsistenzen gefunden",
+	"maintenance.verify_header":  "PROBLEM\tID\tDETAIL\t",
+	"maintenance.repaired":       "repariert",
+	"maintenance.verify_summary": "%d Probleme, %d repariert",
+	"maintenance.verify_hint":    "'maintenance verify -repair' behebt sie",
+
+	// Default message templates
+	"template.follow_up": `Hallo{{with .FirstName}} {{.}}{{end}},
+
+danke für die Vernetzung!{{if .Title}} Mir ist Ihr Hintergrund als {{.Title}}{{with .Company}} bei {{.}}{{end}} aufgefallen.{{else if .Company}} Mir ist Ihre Arbeit bei {{.Company}} aufgefallen.{{end}}
+
+Ich vernetze mich gerne mit Fachleuten aus der Branche und würde mich freuen, in Kontakt zu bleiben!
+
+Viele Grüße`,
+	"template.introduction": `Hallo{{with .FirstName}} {{.}}{{end}},
+
+ich bin auf Ihr Profil gestoßen und war von Ihrer Erfahrung{{with .Title}} als {{.}}{{end}} beeindruckt.
+
+Ich arbeite an spannenden Projekten und denke, dass sich Synergien ergeben könnten.
+
+Ich freue mich auf die Vernetzung!`,
+	"template.follow_up_short": `Hallo{{with .FirstName}} {{.}}{{end}}, danke für die Vernetzung! Ich freue mich, in Kontakt zu bleiben.`,
+
+	// Bench
+	"bench.running":          "Bench läuft: %d synthetische Profile über %d simulierte Tage...",
+	"bench.title":            "BENCH-BERICHT",
+	"bench.simulated":        "Simuliert",
+	"bench.simulated_value":  "%.1f Tage, %d Durchläufe",
+	"bench.wall_time":        "Laufzeit",
+	"bench.throughput":       "Durchsatz",
+	"bench.throughput_value": "%.1f Aktionen/s (%.0fx Echtzeit)",
+	"bench.saves":            "Schreibvorgänge",
+	"bench.saves_value":      "%d (Ø %.2fms, max %.2fms)",
+	"bench.actions":          "Erfolgreiche Aktionen:",
+	"bench.funnel":           "Endstand Funnel:",
+	"bench.chaos":            "Fehler und Ausfälle:",
+	"bench.failed":           "%s fehlgeschlagen",
+	"bench.sessions_lost":    "verlorene Sitzungen",
+	"bench.growth":           "Speicherwachstum:",
+	"bench.growth_day":       "Tag %d: %.1f KiB, %d Aktionslogs, %d Nachrichten",
+	"bench.limits_ok":        "Limits in jedem Zeitfenster eingehalten",
+	"bench.limits_violated":  "%d Limitverstöße",
+	"bench.violation":        "%s: %s-Fenster ab %s hatte %d (Limit %d)",
+	"bench.kept":             "Bench-Daten in %s behalten",
+
+	// Work sessions
+	"session.none":           "Keine manuelle Arbeitssitzung aktiv",
+	"session.active":         "Arbeitssitzung aktiv bis %s (noch %s)",
+	"session.reason":         "Grund: %s",
+	"session.confirm":        "Damit läuft die Automatisierung %s lang außerhalb der Geschäftszeiten.",
+	"session.confirm_prompt": "Gib 'yes' ein, um eine beaufsichtigte Arbeitssitzung zu starten:",
+	"session.aborted":        "Abgebrochen, keine Arbeitssitzung gestartet",
+	"session.started":        "Arbeitssitzung gestartet, die Automatisierung darf bis %s laufen",
+	"session.started_hint":   "Starte jetzt subspace; vorzeitig beenden mit: subspace session end",
+	"session.ended":          "Arbeitssitzung beendet",
+	"session.running":        "Manuelle Arbeitssitzung aktiv bis %s",
+	"estop.engaged":          "Notstopp aktiviert: %s",
+	"estop.resume_hint":      "Bis 'subspace resume' wird nichts gesendet",
+	"estop.released":         "Notstopp aufgehoben",
+	"estop.still_env":        "Stoppdatei entfernt, aber %s hält den Stopp aktiv",
+	"estop.refused":          "Notstopp aktiviert (%s: %s), es wird nichts gesendet",
+	"service.written":        "%s geschrieben",
+	"service.enable":         "Aktivieren mit: %s",
+	"version.available":      "Update verfügbar: %s (%s), 'subspace self-update' ausführen",
+	"version.up_to_date":     "Bereits auf dem neuesten Release (%s)",
+	"update.downloading":     "Lade %s herunter...",
+	"update.installed":       "%s auf %s aktualisiert; das vorherige Binary liegt unter %s",
+	"export.done":            "%d Ereignisse nach %s exportiert (letztes um %s)",
+	"export.profiles_done":   "%d Profile nach %s exportiert",
+	"export.anonymized_done": "%d Profile, %d Nachrichten und %d Aktionen anonymisiert nach %s exportiert",
+}
@@ -0,0 +1,372 @@
+package i18n
+
+// en is the reference catalog; every message ID must exist here
+var en = map[string]string{
+	// Automation workflow
+	"run.outside_hours":       "Current time is outside configured business hours",
+	"run.outside_hours_hint":  "Configure business_hours in config.yaml, or start a supervised run with: subspace session start",
+	"run.day_off":             "Day off today (%s) - no automation runs",
+	"run.step_auth":           "Step 1: Authentication",
+	"run.login_failed":        "Login failed: %v",
+	"run.login_failed_note":   "NOTE: This is expected in PoC mode (no real credentials)",
+	"run.login_ok":            "Login successful (session restored or mock login)",
+	"run.browser_recovered":   "Browser crashed during %s; relaunched and resumed (restart %d of %d)",
+	"run.browser_recycled":    "Browser recycled by the resource watchdog; session kept",
+	"run.panic":               "Step %s panicked; the run continued. Crash report: %s",
+	"proxy.unhealthy":         "Proxy %s failed its health check: %v",
+	"proxy.failover":          "Failed over from proxy %s to backup %s",
+	"proxy.rotated":           "Rotated the proxy session on %s for a new exit IP",
+	"proxy.none_healthy":      "No healthy proxy for this account (was using %s); stopping",
+	"run.module_disabled":     "Skipped: the %s module is disabled in config.yaml",
+	"run.plan":                "Workflow order: %s",
+	"run.round":               "Round %d (batches of %d)",
+	"run.step_search":         "Step 2: Search & Discovery",
+	"run.search_failed":       "Search failed: %v",
+	"run.search_ok":           "Search completed - profiles discovered",
+	"run.enriched":            "Company data added to %d profiles",
+	"run.step_connect":        "Step 3: Connection Requests",
+	"run.connect_failed":      "Connection processing failed: %v",
+	"run.connect_ok":          "Connection requests processed",
+	"run.connect_limit":       "Daily connection limit reached",
+	"run.search_limit":        "Daily search limit reached",
+	"run.step_accepted":       "Step 4: Check Accepted Connections",
+	"run.accepted_found":      "Found %d accepted connections",
+	"run.step_message":        "Step 5: Follow-up Messaging",
+	"run.message_failed":      "Messaging failed: %v",
+	"run.message_ok":          "Follow-up messages sent",
+	"run.batch_counts":        "%d sent, %d failed, %d skipped",
+	"run.batch_timed_out":     "%d timed out and were abandoned",
+	"run.message_limit":       "Daily message limit reached",
+	"run.session_cap":         "Session message cap reached (%d); the rest waits for the next session",
+	"run.inbox_waiting":       "%d conversations awaiting your reply; answer them with: subspace inbox",
+	"run.summary":             "Workflow Summary",
+	"run.summary_connections": "Connections today: %v/%v",
+	"run.summary_messages":    "Messages today: %v/%v",
+	"run.summary_pending":     "Pending requests: %v",
+	"run.summary_accepted":    "Accepted connections: %v",
+	"run.summary_overhead":    "%v: %v of stealth delays, %v of work (%v%% delay)",
+	"run.keep_open":           "Keeping browser open for %d seconds...",
+
+	// Demo mode
+	"demo.title":                 "STEALTH TECHNIQUES DEMONSTRATION",
+	"demo.mouse":                 "Bézier Curve Mouse Movement",
+	"demo.mouse_doing":           "Moving mouse from (100,100) to (800,600)...",
+	"demo.mouse_done":            "Smooth, curved path demonstrated",
+	"demo.typing":                "Human-like Typing Simulation",
+	"demo.typing_sample":         "Hello, this is a test message",
+	"demo.typing_doing":          "Typing: '%s'",
+	"demo.typing_done":           "Variable speed + occasional typos demonstrated",
+	"demo.scroll":                "Natural Scrolling Behavior",
+	"demo.scroll_doing":          "Performing random scroll...",
+	"demo.scroll_done":           "Accelerated scroll with physics demonstrated",
+	"demo.wander":                "Mouse Hover Wandering",
+	"demo.wander_doing":          "Simulating reading behavior...",
+	"demo.wander_done":           "Random micro-movements demonstrated",
+	"demo.timing":                "Randomized Timing",
+	"demo.timing_delay":          "Action delay...",
+	"demo.timing_delay_done":     "Delayed %dms (randomized)",
+	"demo.timing_think":          "Thinking pause...",
+	"demo.timing_think_done":     "Paused %dms (simulating thought)",
+	"demo.hours":                 "Business Hours Enforcement",
+	"demo.hours_in":              "Currently within business hours",
+	"demo.hours_out":             "Currently outside business hours",
+	"demo.fingerprint":           "Browser Fingerprint Masking",
+	"demo.fingerprint_webdriver": "Applied WebDriver flag masking",
+	"demo.fingerprint_viewport":  "Applied viewport randomization",
+	"demo.fingerprint_done":      "Fingerprint techniques active",
+	"demo.cooldown":              "Rate Limiting & Cooldown",
+	"demo.cooldown_doing":        "Enforcing %d-second cooldown...",
+	"demo.cooldown_done":         "Cooldown enforced (%dms)",
+	"demo.complete":              "Demo complete! All 8+ stealth techniques showcased.",
+	"demo.check_logs":            "Check logs for detailed timing and execution data",
+
+	// Statistics
+	"stats.title":                 "AUTOMATION STATISTICS",
+	"stats.profile_states":        "Profile States:",
+	"stats.discovered":            "Discovered",
+	"stats.approved":              "Approved",
+	"stats.skipped":               "Skipped",
+	"stats.requested":             "Requested",
+	"stats.accepted":              "Accepted",
+	"stats.cooled_down":           "Cooled Down",
+	"stats.rejected":              "Rejected",
+	"stats.total":                 "TOTAL",
+	"stats.activity_today":        "Activity Today:",
+	"stats.connections":           "Connections",
+	"stats.messages":              "Messages",
+	"stats.total_messages":        "Total Msgs",
+	"stats.recent":                "Recent Activity:",
+	"stats.by_source":             "Today by Source:",
+	"stats.connections_last_hour": "Connections (last hour)",
+	"stats.activity_title":        "ACTIVITY",
+	"stats.filter_campaign":       "campaign %s",
+	"stats.filter_account":        "account %s",
+	"stats.accepted_rate":         "%d (%d accepted, %.0f%%)",
+	"stats.searches":              "Searches",
+	"stats.failures":              "Failures",
+	"stats.by_campaign":           "By Campaign:",
+	"stats.by_account":            "By Account:",
+	"stats.by_day":                "By Day:",
+	"stats.breakdown_header":      "\tREQUESTS\tACCEPTED\tMESSAGES\tSEARCHES\tFAILURES",
+
+	// Latency
+	"latency.title":      "ACCEPTANCE LATENCY BY SEARCH KEYWORD",
+	"latency.none":       "No connection requests sent yet",
+	"latency.header":     "KEYWORD\tACCEPTED\tPENDING\tMEDIAN\tP90\tMAX\tPENDING > P90",
+	"latency.all":        "(all)",
+	"latency.no_keyword": "(no keyword)",
+	"latency.hint":       "Requests pending past the p90 latency are unlikely to be accepted; consider withdrawing them",
+
+	// Accounts
+	"accounts.title":          "ACCOUNTS (%d)",
+	"accounts.none":           "No accounts configured",
+	"accounts.header":         "ACCOUNT\tPROXY\tTIME ZONE\tPERSONA\tSTATUS",
+	"accounts.bound":          "bound",
+	"accounts.unregistered":   "not run yet",
+	"accounts.changed":        "CHANGED - run: subspace accounts rebind",
+	"accounts.not_configured": "registered, not in config",
+	"accounts.rebound":        "Account %s rebound to its configured proxy, time zone, persona and fingerprint",
+	"accounts.exported":       "Exported %s to %s (%d cookies); keep the passphrase apart from the file",
+	"accounts.imported":       "Imported %s (exported %s): binding registered, %d cookies and web storage restored",
+	"accounts.add_config":     "Add the account to config.yaml under accounts:",
+	"accounts.passphrase":     "Bundle passphrase (or set %s):",
+
+	// Plan
+	"plan.title":           "NEXT RUN PLAN",
+	"plan.within_hours":    "Within business hours",
+	"plan.outside_hours":   "Outside business hours - nothing will run",
+	"plan.day_off":         "Day off (%s) - nothing will run",
+	"plan.searches":        "Searches",
+	"plan.connections":     "Connections",
+	"plan.messages":        "Messages",
+	"plan.step":            "%d planned (%d candidates, %d/%d used today)",
+	"plan.disabled":        "disabled in config.yaml",
+	"plan.awaiting_review": "+ %d awaiting review (subspace review)",
+	"plan.campaign":        "- %s: %d of %d candidates (priority %d, weight %g)",
+	"plan.recipient_night": "+ %d waiting for daytime in the recipient's time zone",
+	"plan.snoozed":         "%d profiles snoozed; see: subspace snooze list",
+
+	"schedule.title":        "SCHEDULE, NEXT %d HOURS",
+	"schedule.none":         "Nothing scheduled",
+	"schedule.header":       "AT\tUNTIL\tWHAT\tDETAIL",
+	"schedule.session":      "Session",
+	"schedule.work_session": "Work session",
+	"schedule.day_off":      "Day off",
+	"schedule.snooze_ends":  "Snooze ends",
+	"schedule.budget_frees": "Limit frees a slot",
+
+	// Profiles
+	"profiles.title":          "PROFILES (%d)",
+	"profiles.page":           "Showing %d-%d of %d",
+	"profiles.header":         "ID\tNAME\tTITLE\tCOMPANY\tSTATE\tDISCOVERED\tTAGS",
+	"profiles.tagged":         "Tagged %s (%s): %s",
+	"profiles.untagged":       "Untagged %s (%s): %s",
+	"profiles.imported":       "Imported %d profiles (%d duplicates, %d invalid rows skipped)",
+	"profiles.import_dry_run": "Would import %d profiles (%d duplicates, %d invalid rows skipped)",
+
+	// Profile deduplication
+	"dedupe.none":     "No duplicate profiles found",
+	"dedupe.title":    "DUPLICATE PROFILES (%d groups)",
+	"dedupe.group":    "Group %d: keep %s, merge %d",
+	"dedupe.field":    "FIELD",
+	"dedupe.keep":     "(keep)",
+	"dedupe.merged":   "MERGED",
+	"dedupe.messages": "messages",
+	"dedupe.dry_run":  "Dry run only; re-run with -apply to merge",
+	"dedupe.applied":  "Merged %d duplicate profiles into %d",
+
+	// Review
+	"review.title":       "REVIEW QUEUE (%d)",
+	"review.header":      "ID\tNAME\tTITLE\tCOMPANY\tMUTUAL\tDISCOVERED\tLATEST NOTE",
+	"review.prompt":      "[a]ccept, [s]kip, [n]ext, add no[t]e, [q]uit:",
+	"review.note_prompt": "Note:",
+	"review.empty":       "Review queue is empty",
+	"review.found_by":    "Found by search: %s",
+	"review.mutuals":     "%d mutual connections",
+	"review.summary":     "%d approved, %d skipped",
+	"review.decided":     "%s (%s): %s",
+
+	// Inbox
+	"inbox.title":        "INBOX (%d awaiting reply)",
+	"inbox.header":       "ID\tNAME\tWAITING SINCE\tREPLIES\tLATEST",
+	"inbox.empty":        "No replies awaiting an answer",
+	"inbox.prompt":       "[r]eply, [n]ext, [q]uit:",
+	"inbox.reply_prompt": "Reply:",
+	"inbox.them":         "them",
+	"inbox.you":          "you",
+	"inbox.sent":         "Reply sent to %s",
+	"inbox.summary":      "%d replies sent",
+
+	// Message archive
+	"messages.search_title":  "Searched messages and notes: %d matches for %q",
+	"messages.search_empty":  "No messages or notes match",
+	"messages.search_header": "TIME\tKIND\tPROFILE\tNAME\tTEXT",
+	"messages.kind_sent":     "sent",
+	"messages.kind_received": "received",
+	"messages.kind_note":     "note",
+
+	// Snooze
+	"snooze.title":       "SNOOZED PROFILES (%d)",
+	"snooze.header":      "ID\tNAME\tSTATE\tUNTIL\tREASON",
+	"snooze.none":        "No profiles are snoozed",
+	"snooze.set":         "%s (%s) snoozed until %s",
+	"snooze.cleared":     "%s (%s) is no longer snoozed",
+	"enrich.done":        "Enriched %d profiles (%d companies looked up, %d cached, %d unknown)",
+	"enrich.failed":      "%d company lookups failed, see the log",
+	"experiment.running": "Running experiment %s: %d arms x %d runs in simulation...",
+	"experiment.title":   "EXPERIMENT %s (%d profiles, %d days, seeds %v)",
+	"experiment.header":  "ARM\tCONNECTIONS\tACCEPTED\tRATE\tMESSAGES\tPEAK/H\tMEDIAN GAP\tGAP CV\tDAILY CV\tACTIVE H\tVIOLATIONS\t",
+	"experiment.legend":  "Means per run. Low GAP CV and DAILY CV mean machine-regular pacing; PEAK/H is the busiest sliding hour.",
+	"experiment.none":    "No experiments configured (see the experiments section of config.yaml)",
+	"experiment.item":    "%s: arms %v, %d days, %d runs",
+	"replay.running":     "Replaying %d recorded actions from %s in simulation...",
+	"replay.header":      "\t#\tACTION\tPROFILE\tDECISIONS\tRECORDED\tREPLAYED\tDIVERGENCE\t",
+	"replay.divergence":  "at decision %d: recorded %s, asked for %s",
+	"replay.summary":     "%d of %d actions replayed identically",
+
+	// Queue
+	"queue.title":                "PENDING QUEUE (%d)",
+	"queue.header":               "#\tACTION\tID\tNAME\tPRIORITY\tESTIMATED",
+	"queue.none":                 "Nothing is waiting to be sent",
+	"queue.past_horizon":         "beyond %d h",
+	"queue.prioritized":          "%s (%s) now has queue priority %d",
+	"queue.deferred":             "%s (%s) deferred until %s",
+	"queue.cancelled_connection": "%s (%s) will not get a connection request",
+	"queue.cancelled_message":    "%s (%s) will not get a first message",
+
+	// Templates
+	"templates.preview": "%s for %s",
+	"templates.tested":  "%d template checks, %d failed",
+
+	// Burn-down
+	"burndown.title":         "BUDGET BURN-DOWN %s (active %s-%s)",
+	"burndown.summary":       "%s: %d/%d used, %d expected by now (%s)",
+	"burndown.pace_ahead":    "ahead of pace",
+	"burndown.pace_on_track": "on track",
+	"burndown.pace_behind":   "behind pace",
+	"burndown.lasts":         "Budget lasts the day at the current rate",
+	"burndown.projected":     "Budget runs out around %s at the current rate",
+	"burndown.exhausted":     "Budget ran out at %s",
+	"burndown.hour":          "%d (target %d)",
+	"burndown.hour_future":   "(target %d)",
+
+	// Bandwidth
+	"bandwidth.run":         "Bandwidth this run: %s in %d requests",
+	"bandwidth.title":       "BANDWIDTH (last %d days)",
+	"bandwidth.none":        "No runs metered yet",
+	"bandwidth.header":      "STARTED\tDURATION\tRECEIVED\tREQUESTS\tACCOUNT",
+	"bandwidth.total":       "Total",
+	"bandwidth.cost":        "Cost",
+	"bandwidth.by_step":     "By step:",
+	"bandwidth.by_campaign": "By campaign:",
+
+	// Cookies
+	"cookies.title":           "SAVED COOKIES (%d in %s)",
+	"cookies.none":            "No saved session",
+	"cookies.header":          "NAME\tDOMAIN\tEXPIRES\tSIZE\tPOLICY",
+	"cookies.session":         "session",
+	"cookies.verdict_keep":    "keep",
+	"cookies.verdict_drop":    "drop",
+	"cookies.verdict_expired": "expired",
+	"cookies.removed":         "Removed %d of %d cookies",
+	"cookies.would_remove":    "Would remove %d of %d cookies",
+
+	// Notes
+	"notes.title": "NOTES FOR %s (%s)",
+	"notes.none":  "No notes yet; add one with: subspace notes add <profile-id> <text>",
+	"notes.added": "Note added to %s (%s)",
+
+	// Maintenance
+	"maintenance.running":        "Running retention maintenance...",
+	"maintenance.title":          "RETENTION REPORT",
+	"maintenance.profiles":       "Profiles",
+	"maintenance.messages":       "Messages",
+	"maintenance.action_logs":    "Action logs",
+	"maintenance.screenshots":    "Screenshots",
+	"maintenance.traces":         "Traces",
+	"maintenance.duplicates":     "Duplicates",
+	"maintenance.merged":         "%d merged",
+	"maintenance.total":          "TOTAL",
+	"maintenance.purged":         "%d purged (%s)",
+	"maintenance.total_line":     "%d in %dms",
+	"maintenance.forever":        "kept forever",
+	"maintenance.older_than":     "older than %d days",
+	"maintenance.compacting":     "Compacting storage...",
+	"maintenance.compact_title":  "COMPACTION REPORT",
+	"maintenance.tombstones":     "Tombstones",
+	"maintenance.duplicate_logs": "Duplicate logs",
+	"maintenance.orphaned":       "Orphaned msgs",
+	"maintenance.size":           "db.json",
+	"maintenance.size_line":      "%s -> %s",
+	"maintenance.verify_title":   "INTEGRITY CHECK",
+	"maintenance.verify_ok":      "No inconsistencies found",
+	"maintenance.verify_header":  "ISSUE\tID\tDETAIL\t",
+	"maintenance.repaired":       "repaired",
+	"maintenance.verify_summary": "%d issues, %d repaired",
+	"maintenance.verify_hint":    "Run 'maintenance verify -repair' to fix them",
+
+	// Default message templates
+	"template.follow_up": `Hi{{with .FirstName}} {{.}}{{end}},
+
+Thanks for connecting!{{if .Title}} I noticed your background in {{.Title}}{{with .Company}} at {{.}}{{end}}.{{else if .Company}} I noticed your work at {{.Company}}.{{end}}
+
+I'm always interested in connecting with professionals in the field. Would love to stay in touch!
+
+Best regards`,
+	"template.introduction": `Hi{{with .FirstName}} {{.}}{{end}},
+
+I came across your profile and was impressed by your experience{{with .Title}} in {{.}}{{end}}.
+
+I'm working on some interesting projects and thought we might have synergies to explore.
+
+Looking forward to connecting!`,
+	"template.follow_up_short": `Hi{{with .FirstName}} {{.}}{{end}}, thanks for connecting! Looking forward to staying in touch.`,
+
+	// Bench
+	"bench.running":          "Running bench: %d synthetic profiles over %d simulated days...",
+	"bench.title":            "BENCH REPORT",
+	"bench.simulated":        "Simulated",
+	"bench.simulated_value":  "%.1f days, %d pipeline cycles",
+	"bench.wall_time":        "Wall time",
+	"bench.throughput":       "Throughput",
+	"bench.throughput_value": "%.1f actions/s (%.0fx real time)",
+	"bench.saves":            "Storage writes",
+	"bench.saves_value":      "%d (avg %.2fms, max %.2fms)",
+	"bench.actions":          "Successful actions:",
+	"bench.funnel":           "Final funnel:",
+	"bench.chaos":            "Faults and failures:",
+	"bench.failed":           "failed %s",
+	"bench.sessions_lost":    "sessions lost",
+	"bench.growth":           "Storage growth:",
+	"bench.growth_day":       "day %d: %.1f KiB, %d action logs, %d messages",
+	"bench.limits_ok":        "Rate limits respected in every window",
+	"bench.limits_violated":  "%d rate limit violations",
+	"bench.violation":        "%s: %s window starting %s had %d (limit %d)",
+	"bench.kept":             "Bench data kept in %s",
+
+	// Work sessions
+	"session.none":           "No manual work session active",
+	"session.active":         "Work session active until %s (%s left)",
+	"session.reason":         "Reason: %s",
+	"session.confirm":        "This allows automation outside business hours for %s.",
+	"session.confirm_prompt": "Type 'yes' to start a supervised work session:",
+	"session.aborted":        "Aborted, no work session started",
+	"session.started":        "Work session started, automation may run until %s",
+	"session.started_hint":   "Run subspace now; end early with: subspace session end",
+	"session.ended":          "Work session ended",
+	"session.running":        "Manual work session active until %s",
+	"estop.engaged":          "Emergency stop engaged: %s",
+	"estop.resume_hint":      "Nothing will be sent until 'subspace resume'",
+	"estop.released":         "Emergency stop lifted",
+	"estop.still_env":        "Stop file removed, but %s still engages the stop",
+	"estop.refused":          "Emergency stop engaged (%s: %s), nothing will be sent",
+	"service.written":        "Wrote %s",
+	"service.enable":         "Enable it with: %s",
+	"version.available":      "Update available: %s (%s), run 'subspace self-update'",
+	"version.up_to_date":     "Already on the latest release (%s)",
+	"update.downloading":     "Downloading %s...",
+	"update.installed":       "Updated %s to %s; the previous binary is kept as %s",
+	"export.done":            "Exported %d events to %s (last at %s)",
+	"export.profiles_done":   "Exported %d profiles to %s",
+	"export.anonymized_done": "Exported %d profiles, %d messages and %d actions, anonymized, to %s",
+}
@@ -0,0 +1,372 @@
+package i18n
+
+// es is the Spanish catalog
+var es = map[string]string{
+	// Automation workflow
+	"run.outside_hours":       "La hora actual está fuera del horario laboral configurado",
+	"run.outside_hours_hint":  "Ajusta business_hours en config.yaml, o inicia una ejecución supervisada con: subspace session start",
+	"run.day_off":             "Hoy es día libre (%s) - no se ejecuta ninguna automatización",
+	"run.step_auth":           "Paso 1: Autenticación",
+	"run.login_failed":        "Error de inicio de sesión: %v",
+	"run.login_failed_note":   "NOTA: Es lo esperado en modo PoC (sin credenciales reales)",
+	"run.login_ok":            "Sesión iniciada (sesión restaurada o inicio simulado)",
+	"run.browser_recovered":   "El navegador falló durante %s; relanzado y reanudado (reinicio %d de %d)",
+	"run.browser_recycled":    "Navegador reciclado por el vigilante de recursos; sesión conservada",
+	"run.panic":               "El paso %s falló con un pánico; la ejecución continuó. Informe: %s",
+	"proxy.unhealthy":         "El proxy %s no superó la comprobación: %v",
+	"proxy.failover":          "Cambio del proxy %s al de respaldo %s",
+	"proxy.rotated":           "Sesión del proxy %s rotada para una nueva IP de salida",
+	"proxy.none_healthy":      "Ningún proxy sano para esta cuenta (se usaba %s); deteniendo",
+	"run.module_disabled":     "Omitido: el módulo %s está desactivado en config.yaml",
+	"run.plan":                "Orden del flujo: %s",
+	"run.round":               "Ronda %d (lotes de %d)",
+	"run.step_search":         "Paso 2: Búsqueda y descubrimiento",
+	"run.search_failed":       "Error en la búsqueda: %v",
+	"run.search_ok":           "Búsqueda completada - perfiles descubiertos",
+	"run.enriched":            "Datos de empresa añadidos a %d perfiles",
+	"run.step_connect":        "Paso 3: Solicitudes de conexión",
+	"run.connect_failed":      "Error al procesar conexiones: %v",
+	"run.connect_ok":          "Solicitudes de conexión procesadas",
+	"run.connect_limit":       "Límite diario de conexiones alcanzado",
+	"run.search_limit":        "Límite diario de búsquedas alcanzado",
+	"run.step_accepted":       "Paso 4: Comprobar conexiones aceptadas",
+	"run.accepted_found":      "%d conexiones aceptadas encontradas",
+	"run.step_message":        "Paso 5: Mensajes de seguimiento",
+	"run.message_failed":      "Error al enviar mensajes: %v",
+	"run.message_ok":          "Mensajes de seguimiento enviados",
+	"run.batch_counts":        "%d enviados, %d fallidos, %d omitidos",
+	"run.batch_timed_out":     "%d excedieron su tiempo y se abandonaron",
+	"run.message_limit":       "Límite diario de mensajes alcanzado",
+	"run.session_cap":         "Tope de mensajes por sesión alcanzado (%d); el resto espera a la próxima sesión",
+	"run.inbox_waiting":       "%d conversaciones esperan tu respuesta; respóndelas con: subspace inbox",
+	"run.summary":             "Resumen del flujo de trabajo",
+	"run.summary_connections": "Conexiones hoy: %v/%v",
+	"run.summary_messages":    "Mensajes hoy: %v/%v",
+	"run.summary_pending":     "Solicitudes pendientes: %v",
+	"run.summary_accepted":    "Conexiones aceptadas: %v",
+	"run.summary_overhead":    "%v: %v de pausas de sigilo, %v de trabajo (%v%% pausas)",
+	"run.keep_open":           "Manteniendo el navegador abierto %d segundos...",
+
+	// Demo mode
+	"demo.title":                 "DEMOSTRACIÓN DE TÉCNICAS DE SIGILO",
+	"demo.mouse":                 "Movimiento del ratón con curvas de Bézier",
+	"demo.mouse_doing":           "Moviendo el ratón de (100,100) a (800,600)...",
+	"demo.mouse_done":            "Trayectoria suave y curva demostrada",
+	"demo.typing":                "Simulación de escritura humana",
+	"demo.typing_sample":         "Hola, este es un mensaje de prueba",
+	"demo.typing_doing":          "Escribiendo: '%s'",
+	"demo.typing_done":           "Velocidad variable + errores ocasionales demostrados",
+	"demo.scroll":                "Desplazamiento natural",
+	"demo.scroll_doing":          "Realizando desplazamiento aleatorio...",
+	"demo.scroll_done":           "Desplazamiento acelerado con física demostrado",
+	"demo.wander":                "Movimiento errante del ratón",
+	"demo.wander_doing":          "Simulando lectura...",
+	"demo.wander_done":           "Micromovimientos aleatorios demostrados",
+	"demo.timing":                "Tiempos aleatorios",
+	"demo.timing_delay":          "Retraso entre acciones...",
+	"demo.timing_delay_done":     "Retraso de %dms (aleatorio)",
+	"demo.timing_think":          "Pausa para pensar...",
+	"demo.timing_think_done":     "Pausa de %dms (simulando reflexión)",
+	"demo.hours":                 "Control del horario laboral",
+	"demo.hours_in":              "Actualmente dentro del horario laboral",
+	"demo.hours_out":             "Actualmente fuera del horario laboral",
+	"demo.fingerprint":           "Enmascaramiento de la huella del navegador",
+	"demo.fingerprint_webdriver": "Indicador WebDriver enmascarado",
+	"demo.fingerprint_viewport":  "Tamaño de ventana aleatorizado",
+	"demo.fingerprint_done":      "Técnicas de huella activas",
+	"demo.cooldown":              "Límites de frecuencia y enfriamiento",
+	"demo.cooldown_doing":        "Aplicando enfriamiento de %d segundos...",
+	"demo.cooldown_done":         "Enfriamiento aplicado (%dms)",
+	"demo.complete":              "¡Demostración completa! Se mostraron más de 8 técnicas de sigilo.",
+	"demo.check_logs":            "Consulta los registros para ver tiempos y detalles de ejecución",
+
+	// Statistics
+	"stats.title":                 "ESTADÍSTICAS DE AUTOMATIZACIÓN",
+	"stats.profile_states":        "Estados de perfiles:",
+	"stats.discovered":            "Descubiertos",
+	"stats.approved":              "Aprobados",
+	"stats.skipped":               "Omitidos",
+	"stats.requested":             "Solicitados",
+	"stats.accepted":              "Aceptados",
+	"stats.cooled_down":           "En reposo",
+	"stats.rejected":              "Rechazados",
+	"stats.total":                 "TOTAL",
+	"stats.activity_today":        "Actividad de hoy:",
+	"stats.connections":           "Conexiones",
+	"stats.messages":              "Mensajes",
+	"stats.total_messages":        "Total mensajes",
+	"stats.recent":                "Actividad reciente:",
+	"stats.by_source":             "Hoy por origen:",
+	"stats.connections_last_hour": "Conexiones (última hora)",
+	"stats.activity_title":        "ACTIVIDAD",
+	"stats.filter_campaign":       "campaña %s",
+	"stats.filter_account":        "cuenta %s",
+	"stats.accepted_rate":         "%d (%d aceptadas, %.0f%%)",
+	"stats.searches":              "Búsquedas",
+	"stats.failures":              "Fallos",
+	"stats.by_campaign":           "Por campaña:",
+	"stats.by_account":            "Por cuenta:",
+	"stats.by_day":                "Por día:",
+	"stats.breakdown_header":      "\tSOLICITUDES\tACEPTADAS\tMENSAJES\tBÚSQUEDAS\tFALLOS",
+
+	// Latency
+	"latency.title":      "LATENCIA DE ACEPTACIÓN POR PALABRA CLAVE",
+	"latency.none":       "Aún no se han enviado solicitudes de conexión",
+	"latency.header":     "PALABRA CLAVE\tACEPTADAS\tPENDIENTES\tMEDIANA\tP90\tMÁX\tPENDIENTES > P90",
+	"latency.all":        "(todas)",
+	"latency.no_keyword": "(sin palabra clave)",
+	"latency.hint":       "Las solicitudes pendientes más allá de la latencia p90 probablemente no se acepten; considera retirarlas",
+
+	// Accounts
+	"accounts.title":          "CUENTAS (%d)",
+	"accounts.none":           "No hay cuentas configuradas",
+	"accounts.header":         "CUENTA\tPROXY\tZONA HORARIA\tPERSONA\tESTADO",
+	"accounts.bound":          "vinculada",
+	"accounts.unregistered":   "aún sin ejecutar",
+	"accounts.changed":        "CAMBIADA - ejecuta: subspace accounts rebind",
+	"accounts.not_configured": "registrada, no está en la configuración",
+	"accounts.rebound":        "Cuenta %s vinculada de nuevo a su proxy, zona horaria, persona y huella configurados",
+	"accounts.exported":       "%s exportada a %s (%d cookies); guarda la contraseña aparte del archivo",
+	"accounts.imported":       "%s importada (exportada el %s): vinculación registrada, %d cookies y almacenamiento web restaurados",
+	"accounts.add_config":     "Añade la cuenta a config.yaml bajo accounts:",
+	"accounts.passphrase":     "Contraseña del paquete (o define %s):",
+
+	// Plan
+	"plan.title":           "PLAN DE LA PRÓXIMA EJECUCIÓN",
+	"plan.within_hours":    "Dentro del horario laboral",
+	"plan.outside_hours":   "Fuera del horario laboral - no se ejecutará nada",
+	"plan.day_off":         "Día libre (%s) - no se ejecutará nada",
+	"plan.searches":        "Búsquedas",
+	"plan.connections":     "Conexiones",
+	"plan.messages":        "Mensajes",
+	"plan.step":            "%d previstas (%d candidatos, %d/%d usadas hoy)",
+	"plan.disabled":        "desactivado en config.yaml",
+	"plan.awaiting_review": "+ %d pendientes de revisión (subspace review)",
+	"plan.campaign":        "- %s: %d de %d candidatos (prioridad %d, peso %g)",
+	"plan.recipient_night": "+ %d esperando el horario diurno del destinatario",
+	"plan.snoozed":         "%d perfiles pospuestos; ver: subspace snooze list",
+
+	"schedule.title":        "AGENDA, PRÓXIMAS %d HORAS",
+	"schedule.none":         "Nada programado",
+	"schedule.header":       "CUÁNDO\tHASTA\tQUÉ\tDETALLE",
+	"schedule.session":      "Sesión",
+	"schedule.work_session": "Sesión de trabajo",
+	"schedule.day_off":      "Día libre",
+	"schedule.snooze_ends":  "Fin de pausa",
+	"schedule.budget_frees": "Límite libera un hueco",
+
+	// Profiles
+	"profiles.title":          "PERFILES (%d)",
+	"profiles.page":           "Mostrando %d-%d de %d",
+	"profiles.header":         "ID\tNOMBRE\tCARGO\tEMPRESA\tESTADO\tDESCUBIERTO\tETIQUETAS",
+	"profiles.tagged":         "Etiquetado %s (%s): %s",
+	"profiles.untagged":       "Etiquetas quitadas de %s (%s): %s",
+	"profiles.imported":       "%d perfiles importados (%d duplicados, %d filas no válidas omitidas)",
+	"profiles.import_dry_run": "Se importarían %d perfiles (%d duplicados, %d filas no válidas omitidas)",
+
+	// Profile deduplication
+	"dedupe.none":     "No se encontraron perfiles duplicados",
+	"dedupe.title":    "PERFILES DUPLICADOS (%d grupos)",
+	"dedupe.group":    "Grupo %d: conservar %s, fusionar %d",
+	"dedupe.field":    "CAMPO",
+	"dedupe.keep":     "(conservar)",
+	"dedupe.merged":   "FUSIONADO",
+	"dedupe.messages": "mensajes",
+	"dedupe.dry_run":  "Solo simulación; vuelve a ejecutar con -apply para fusionar",
+	"dedupe.applied":  "%d perfiles duplicados fusionados en %d",
+
+	// Review
+	"review.title":       "COLA DE REVISIÓN (%d)",
+	"review.header":      "ID\tNOMBRE\tCARGO\tEMPRESA\tEN COMÚN\tDESCUBIERTO\tÚLTIMA NOTA",
+	"review.prompt":      "[a]ceptar, [s]altar, [n] siguiente, añadir no[t]a, [q] salir:",
+	"review.note_prompt": "Nota:",
+	"review.empty":       "La cola de revisión está vacía",
+	"review.found_by":    "Encontrado por la búsqueda: %s",
+	"review.mutuals":     "%d contactos en común",
+	"review.summary":     "%d aprobados, %d omitidos",
+	"review.decided":     "%s (%s): %s",
+
+	// Inbox
+	"inbox.title":        "BANDEJA DE ENTRADA (%d por responder)",
+	"inbox.header":       "ID\tNOMBRE\tESPERANDO DESDE\tRESPUESTAS\tÚLTIMA",
+	"inbox.empty":        "No hay respuestas pendientes",
+	"inbox.prompt":       "[r] responder, [n] siguiente, [q] salir:",
+	"inbox.reply_prompt": "Respuesta:",
+	"inbox.them":         "contacto",
+	"inbox.you":          "tú",
+	"inbox.sent":         "Respuesta enviada a %s",
+	"inbox.summary":      "%d respuestas enviadas",
+
+	// Message archive
+	"messages.search_title":  "Búsqueda en mensajes y notas: %d resultados para %q",
+	"messages.search_empty":  "Ningún mensaje ni nota coincide",
+	"messages.search_header": "FECHA\tTIPO\tPERFIL\tNOMBRE\tTEXTO",
+	"messages.kind_sent":     "enviado",
+	"messages.kind_received": "recibido",
+	"messages.kind_note":     "nota",
+
+	// Snooze
+	"snooze.title":       "PERFILES POSPUESTOS (%d)",
+	"snooze.header":      "ID\tNOMBRE\tESTADO\tHASTA\tMOTIVO",
+	"snooze.none":        "No hay perfiles pospuestos",
+	"snooze.set":         "%s (%s) pospuesto hasta %s",
+	"snooze.cleared":     "%s (%s) ya no está pospuesto",
+	"enrich.done":        "%d perfiles enriquecidos (%d empresas consultadas, %d en caché, %d desconocidas)",
+	"enrich.failed":      "Fallaron %d consultas de empresas, consulta el log",
+	"experiment.running": "Ejecutando el experimento %s: %d variantes x %d ejecuciones en simulación...",
+	"experiment.title":   "EXPERIMENTO %s (%d perfiles, %d días, semillas %v)",
+	"experiment.header":  "VARIANTE\tCONEXIONES\tACEPTADAS\tTASA\tMENSAJES\tPICO/H\tPAUSA MEDIANA\tCV PAUSAS\tCV DIARIO\tH ACTIVAS\tINFRACCIONES\t",
+	"experiment.legend":  "Medias por ejecución. Un CV bajo de pausas y diario indica un ritmo regular de máquina; PICO/H es la hora móvil más activa.",
+	"experiment.none":    "No hay experimentos configurados (ver la sección experiments de config.yaml)",
+	"experiment.item":    "%s: variantes %v, %d días, %d ejecuciones",
+	"replay.running":     "Reproduciendo %d acciones grabadas de %s en simulación...",
+	"replay.header":      "\t#\tACCIÓN\tPERFIL\tDECISIONES\tGRABADA\tREPRODUCIDA\tDIVERGENCIA\t",
+	"replay.divergence":  "en la decisión %d: grabada %s, pedida %s",
+	"replay.summary":     "%d de %d acciones reproducidas de forma idéntica",
+
+	// Queue
+	"queue.title":                "COLA PENDIENTE (%d)",
+	"queue.header":               "#\tACCIÓN\tID\tNOMBRE\tPRIORIDAD\tESTIMADO",
+	"queue.none":                 "No hay nada pendiente de enviar",
+	"queue.past_horizon":         "después de %d h",
+	"queue.prioritized":          "%s (%s) tiene ahora prioridad %d en la cola",
+	"queue.deferred":             "%s (%s) aplazado hasta %s",
+	"queue.cancelled_connection": "%s (%s) no recibirá una solicitud de conexión",
+	"queue.cancelled_message":    "%s (%s) no recibirá un primer mensaje",
+
+	// Templates
+	"templates.preview": "%s para %s",
+	"templates.tested":  "%d comprobaciones de plantillas, %d fallidas",
+
+	// Burn-down
+	"burndown.title":         "CONSUMO DEL PRESUPUESTO %s (activo %s-%s)",
+	"burndown.summary":       "%s: %d/%d usados, %d esperados a esta hora (%s)",
+	"burndown.pace_ahead":    "por delante del ritmo",
+	"burndown.pace_on_track": "al ritmo previsto",
+	"burndown.pace_behind":   "por detrás del ritmo",
+	"burndown.lasts":         "El presupuesto dura todo el día al ritmo actual",
+	"burndown.projected":     "El presupuesto se agota hacia las %s al ritmo actual",
+	"burndown.exhausted":     "El presupuesto se agotó a las %s",
+	"burndown.hour":          "%d (objetivo %d)",
+	"burndown.hour_future":   "(objetivo %d)",
+
+	// Bandwidth
+	"bandwidth.run":         "Ancho de banda de esta ejecución: %s en %d solicitudes",
+	"bandwidth.title":       "ANCHO DE BANDA (últimos %d días)",
+	"bandwidth.none":        "Aún no hay ejecuciones medidas",
+	"bandwidth.header":      "INICIO\tDURACIÓN\tRECIBIDO\tSOLICITUDES\tCUENTA",
+	"bandwidth.total":       "Total",
+	"bandwidth.cost":        "Coste",
+	"bandwidth.by_step":     "Por paso:",
+	"bandwidth.by_campaign": "Por campaña:",
+
+	// Cookies
+	"cookies.title":           "COOKIES GUARDADAS (%d en %s)",
+	"cookies.none":            "No hay sesión guardada",
+	"cookies.header":          "NOMBRE\tDOMINIO\tCADUCA\tTAMAÑO\tPOLÍTICA",
+	"cookies.session":         "sesión",
+	"cookies.verdict_keep":    "conservar",
+	"cookies.verdict_drop":    "descartar",
+	"cookies.verdict_expired": "caducada",
+	"cookies.removed":         "Eliminadas %d de %d cookies",
+	"cookies.would_remove":    "Se eliminarían %d de %d cookies",
+
+	// Notes
+	"notes.title": "NOTAS DE %s (%s)",
+	"notes.none":  "Aún no hay notas; añade una con: subspace notes add <profile-id> <texto>",
+	"notes.added": "Nota añadida a %s (%s)",
+
+	// Maintenance
+	"maintenance.running":        "Ejecutando mantenimiento de retención...",
+	"maintenance.title":          "INFORME DE RETENCIÓN",
+	"maintenance.profiles":       "Perfiles",
+	"maintenance.messages":       "Mensajes",
+	"maintenance.action_logs":    "Registros",
+	"maintenance.screenshots":    "Capturas",
+	"maintenance.traces":         "Trazas",
+	"maintenance.duplicates":     "Duplicados",
+	"maintenance.merged":         "%d fusionados",
+	"maintenance.total":          "TOTAL",
+	"maintenance.purged":         "%d eliminados (%s)",
+	"maintenance.total_line":     "%d en %dms",
+	"maintenance.forever":        "se conservan siempre",
+	"maintenance.older_than":     "más de %d días",
+	"maintenance.compacting":     "Compactando el almacenamiento...",
+	"maintenance.compact_title":  "INFORME DE COMPACTACIÓN",
+	"maintenance.tombstones":     "Lápidas",
+	"maintenance.duplicate_logs": "Registros dup.",
+	"maintenance.orphaned":       "Msjs huérfanos",
+	"maintenance.size":           "db.json",
+	"maintenance.size_line":      "%s -> %s",
+	"maintenance.verify_title":   "COMPROBACIÓN DE INTEGRIDAD",
+	"maintenance.verify_ok":      "No se encontraron inconsistencias",
+	"maintenance.verify_header":  "PROBLEMA\tID\tDETALLE\t",
+	"maintenance.repaired":       "reparado",
+	"maintenance.verify_summary": "%d problemas, %d reparados",
+	"maintenance.verify_hint":    "Ejecuta 'maintenance verify -repair' para corregirlos",
+
+	// Default message templates
+	"template.follow_up": `Hola{{with .FirstName}} {{.}}{{end}}:
+
+¡Gracias por conectar!{{if .Title}} Vi tu experiencia como {{.Title}}{{with .Company}} en {{.}}{{end}}.{{else if .Company}} Vi tu trabajo en {{.Company}}.{{end}}
+
+Siempre me interesa conectar con profesionales del sector. ¡Me encantaría que siguiéramos en contacto!
+
+Saludos cordiales`,
+	"template.introduction": `Hola{{with .FirstName}} {{.}}{{end}}:
+
+Encontré tu perfil y me impresionó tu experiencia{{with .Title}} como {{.}}{{end}}.
+
+Estoy trabajando en proyectos interesantes y creo que podríamos encontrar sinergias.
+
+¡Espero que podamos conectar!`,
+	"template.follow_up_short": `Hola{{with .FirstName}} {{.}}{{end}}, ¡gracias por conectar! Espero que sigamos en contacto.`,
+
+	// Bench
+	"bench.running":          "Ejecutando bench: %d perfiles sintéticos durante %d días simulados...",
+	"bench.title":            "INFORME DE BENCH",
+	"bench.simulated":        "Simulado",
+	"bench.simulated_value":  "%.1f días, %d ciclos",
+	"bench.wall_time":        "Tiempo real",
+	"bench.throughput":       "Rendimiento",
+	"bench.throughput_value": "%.1f acciones/s (%.0fx tiempo real)",
+	"bench.saves":            "Escrituras",
+	"bench.saves_value":      "%d (media %.2fms, máx %.2fms)",
+	"bench.actions":          "Acciones con éxito:",
+	"bench.funnel":           "Embudo final:",
+	"bench.chaos":            "Fallos inyectados y errores:",
+	"bench.failed":           "%s fallidas",
+	"bench.sessions_lost":    "sesiones perdidas",
+	"bench.growth":           "Crecimiento del almacenamiento:",
+	"bench.growth_day":       "día %d: %.1f KiB, %d registros, %d mensajes",
+	"bench.limits_ok":        "Límites respetados en todas las ventanas",
+	"bench.limits_violated":  "%d infracciones de límites",
+	"bench.violation":        "%s: ventana de %s desde %s tuvo %d (límite %d)",
+	"bench.kept":             "Datos del bench conservados en %s",
+
+	// Work sessions
+	"session.none":           "No hay ninguna sesión de trabajo manual activa",
+	"session.active":         "Sesión de trabajo activa hasta las %s (quedan %s)",
+	"session.reason":         "Motivo: %s",
+	"session.confirm":        "Esto permite la automatización fuera del horario laboral durante %s.",
+	"session.confirm_prompt": "Escribe 'yes' para iniciar una sesión de trabajo supervisada:",
+	"session.aborted":        "Cancelado, no se inició ninguna sesión de trabajo",
+	"session.started":        "Sesión de trabajo iniciada, la automatización puede ejecutarse hasta las %s",
+	"session.started_hint":   "Ejecuta subspace ahora; termínala antes con: subspace session end",
+	"session.ended":          "Sesión de trabajo terminada",
+	"session.running":        "Sesión de trabajo manual activa hasta las %s",
+	"estop.engaged":          "Parada de emergencia activada: %s",
+	"estop.resume_hint":      "No se enviará nada hasta 'subspace resume'",
+	"estop.released":         "Parada de emergencia desactivada",
+	"estop.still_env":        "Archivo de parada eliminado, pero %s sigue activando la parada",
+	"estop.refused":          "Parada de emergencia activada (%s: %s), no se enviará nada",
+	"service.written":        "Escrito %s",
+	"service.enable":         "Actívalo con: %s",
+	"version.available":      "Actualización disponible: %s (%s), ejecuta 'subspace self-update'",
+	"version.up_to_date":     "Ya tienes la última versión (%s)",
+	"update.downloading":     "Descargando %s...",
+	"update.installed":       "Actualizado de %s a %s; el binario anterior se conserva en %s",
+	"export.done":            "Exportados %d eventos a %s (último a las %s)",
+	"export.profiles_done":   "%d perfiles exportados a %s",
+	"export.anonymized_done": "%d perfiles, %d mensajes y %d acciones exportados, anonimizados, a %s",
+}
@@ -0,0 +1,92 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+/*
+I18N MODULE
+
+Translations for operator-facing console output and the default outreach
+templates. Catalogs are plain Go maps (see en.go, es.go, de.go) keyed by
+dotted message IDs; English is the reference catalog and the fallback for
+any key a translation is missing.
+
+Layout (emoji, indentation, blank lines) stays at the call site so that
+catalogs only contain words:
+
+	fmt.Printf("✅ %s\n", i18n.T("run.accepted_found", len(accepted)))
+*/
+
+// DefaultLanguage is used when no language is configured
+const DefaultLanguage = "en"
+
+// catalogs maps language code -> message ID -> format string
+var catalogs = map[string]map[string]string{
+	"en": en,
+	"es": es,
+	"de": de,
+}
+
+var (
+	mu      sync.RWMutex
+	current = DefaultLanguage
+)
+
+// SetLanguage selects the catalog used by T
+func SetLanguage(lang string) error {
+	if !Supported(lang) {
+		return fmt.Errorf("unsupported language: %s (available: %v)", lang, Languages())
+	}
+	mu.Lock()
+	current = lang
+	mu.Unlock()
+	return nil
+}
+
+// Language returns the currently selected language code
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Supported reports whether a catalog exists for lang
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Languages returns the available language codes, sorted
+func Languages() []string {
+	langs := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// T translates a message ID into the current language, formatting it with
+// args like fmt.Sprintf. Missing translations fall back to English, and
+// unknown IDs are returned as-is so they are easy to spot.
+func T(key string, args ...interface{}) string {
+	return Lookup(Language(), key, args...)
+}
+
+// Lookup translates a message ID into a specific language
+func Lookup(lang, key string, args ...interface{}) string {
+	format, ok := catalogs[lang][key]
+	if !ok {
+		format, ok = catalogs[DefaultLanguage][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,68 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+	"text/template"
+)
+
+// verbs matches the formatting directives of a catalog entry
+var verbs = regexp.MustCompile(`%[-+# 0]*[0-9]*(\.[0-9]+)?[a-zA-Z%]`)
+
+func TestCatalogsMatchEnglish(t *testing.T) {
+	for _, lang := range Languages() {
+		for key, format := range en {
+			translated, ok := catalogs[lang][key]
+			if !ok {
+				t.Errorf("%s: missing %s", lang, key)
+				continue
+			}
+			want, got := verbs.FindAllString(format, -1), verbs.FindAllString(translated, -1)
+			slices.Sort(want)
+			slices.Sort(got)
+			if !slices.Equal(got, want) {
+				t.Errorf("%s: %s has verbs %v, want %v", lang, key, got, want)
+			}
+		}
+		for key := range catalogs[lang] {
+			if _, ok := en[key]; !ok {
+				t.Errorf("%s: %s is not in the English catalog", lang, key)
+			}
+		}
+	}
+}
+
+func TestDefaultTemplatesParse(t *testing.T) {
+	for _, lang := range Languages() {
+		for _, name := range []string{"follow_up", "introduction", "follow_up_short"} {
+			if _, err := template.New(name).Parse(Lookup(lang, "template."+name)); err != nil {
+				t.Errorf("%s: template.%s: %v", lang, name, err)
+			}
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	defer SetLanguage(DefaultLanguage)
+
+	if got := Lookup("de", "templates.tested", 3, 1); got == Lookup("en", "templates.tested", 3, 1) {
+		t.Errorf("German lookup returned English: %q", got)
+	}
+	if got := Lookup("fr", "templates.tested", 3, 1); got != "3 template checks, 1 failed" {
+		t.Errorf("unsupported language: got %q, want the English text", got)
+	}
+	if got := Lookup("es", "no.such.key"); got != "no.such.key" {
+		t.Errorf("unknown key: got %q, want it returned as-is", got)
+	}
+
+	if err := SetLanguage("fr"); err == nil {
+		t.Error("SetLanguage accepted an unsupported language")
+	}
+	if err := SetLanguage("es"); err != nil || Language() != "es" {
+		t.Fatalf("SetLanguage(es) = %v, language %s", err, Language())
+	}
+	if got := T("templates.tested", 3, 1); got != Lookup("es", "templates.tested", 3, 1) {
+		t.Errorf("T = %q, want the Spanish text", got)
+	}
+}
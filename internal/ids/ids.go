@@ -0,0 +1,168 @@
+package ids
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"subspace/internal/clock"
+)
+
+/*
+ID MODULE
+
+Records created at run time (messages, and profiles found by search,
+imported or queued through the API) get their IDs here. The IDs start with the creation time, so they sort in the
+order the records were made, and end in random bits, so workers creating
+records in the same millisecond, on one machine or several sharing a
+database, can't collide:
+
+	ulid    01HYX3K4Q8Z5N6M7P8R9S0T1V2 (26 characters, Crockford base32)
+	uuidv7  018f9e3a-4c21-7b5d-9e8f-0a1b2c3d4e5f (RFC 9562)
+
+IDs made in the same millisecond by one process also sort in the order
+they were made: the random part is incremented instead of drawn anew.
+Time comes from the clock module, so simulated runs get IDs from their
+simulated time.
+
+Like the clock, the generator is process-wide:
+
+	ids.Set(ids.NewUUIDv7())
+*/
+
+// Formats selectable with app.id_format
+const (
+	FormatULID   = "ulid"
+	FormatUUIDv7 = "uuidv7"
+)
+
+// Generator makes unique, time-ordered IDs
+type Generator interface {
+	New() string
+}
+
+var (
+	mu      sync.RWMutex
+	current Generator = NewULID()
+)
+
+// Set replaces the process-wide generator
+func Set(g Generator) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = g
+}
+
+// New returns a new ID from the process-wide generator
+func New() string {
+	mu.RLock()
+	g := current
+	mu.RUnlock()
+	return g.New()
+}
+
+// ForFormat returns a generator for an app.id_format value
+func ForFormat(format string) (Generator, error) {
+	switch format {
+	case FormatULID, "":
+		return NewULID(), nil
+	case FormatUUIDv7:
+		return NewUUIDv7(), nil
+	}
+	return nil, fmt.Errorf("unknown ID format %q", format)
+}
+
+// monotonic hands out a millisecond timestamp with random bits that are
+// incremented, not redrawn, while the millisecond stays the same
+type monotonic struct {
+	mu     sync.Mutex
+	ms     uint64
+	random [10]byte
+}
+
+// next returns the timestamp and random bits of the next ID
+func (m *monotonic) next() (uint64, [10]byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ms := uint64(clock.Now().UnixMilli())
+	if ms > m.ms {
+		m.ms = ms
+		if _, err := rand.Read(m.random[:]); err != nil {
+			panic(fmt.Sprintf("ids: no randomness: %v", err))
+		}
+		// Leave headroom so a burst can't overflow into the next
+		// millisecond's range
+		m.random[0] &= 0x7f
+		return m.ms, m.random
+	}
+
+	// Same millisecond, or the clock stepped back: keep counting from the
+	// last ID so order holds
+	for i := len(m.random) - 1; i >= 0; i-- {
+		m.random[i]++
+		if m.random[i] != 0 {
+			break
+		}
+	}
+	return m.ms, m.random
+}
+
+// ULID makes ULIDs
+type ULID struct {
+	m monotonic
+}
+
+// NewULID returns a ULID generator
+func NewULID() *ULID {
+	return &ULID{}
+}
+
+// crockford is the ULID alphabet
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// New returns a new ULID: 48 bits of milliseconds and 80 random bits
+func (u *ULID) New() string {
+	ms, random := u.m.next()
+	var b [16]byte
+	b[0], b[1], b[2] = byte(ms>>40), byte(ms>>32), byte(ms>>24)
+	b[3], b[4], b[5] = byte(ms>>16), byte(ms>>8), byte(ms)
+	copy(b[6:], random[:])
+
+	// 128 bits in 26 characters of 5 bits, the first holding 3
+	hi, lo := binary.BigEndian.Uint64(b[:8]), binary.BigEndian.Uint64(b[8:])
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// UUIDv7 makes version 7 UUIDs
+type UUIDv7 struct {
+	m monotonic
+}
+
+// NewUUIDv7 returns a UUIDv7 generator
+func NewUUIDv7() *UUIDv7 {
+	return &UUIDv7{}
+}
+
+// New returns a new UUIDv7: 48 bits of milliseconds, the version and
+// variant, and 74 bits counting up from a random start
+func (u *UUIDv7) New() string {
+	ms, random := u.m.next()
+	var b [16]byte
+	b[0], b[1], b[2] = byte(ms>>40), byte(ms>>32), byte(ms>>24)
+	b[3], b[4], b[5] = byte(ms>>16), byte(ms>>8), byte(ms)
+	copy(b[6:], random[:])
+	b[6] = 0x70 | b[6]&0x0f // Version 7
+	b[8] = 0x80 | b[8]&0x3f // RFC 9562 variant
+
+	h := hex.EncodeToString(b[:])
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:]
+}
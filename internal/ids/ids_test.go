@@ -0,0 +1,44 @@
+package ids
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+)
+
+func TestOrdered(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC))
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	for _, format := range []string{FormatULID, FormatUUIDv7} {
+		g, err := ForFormat(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prev := ""
+		for i := 0; i < 1000; i++ {
+			if i%100 == 0 {
+				fake.Advance(time.Millisecond)
+			}
+			id := g.New()
+			if id <= prev {
+				t.Fatalf("%s: %q not after %q", format, id, prev)
+			}
+			prev = id
+			if format == FormatULID && len(id) != 26 {
+				t.Fatalf("ULID %q has %d characters", id, len(id))
+			}
+			if format == FormatUUIDv7 && !uuid.MatchString(id) {
+				t.Fatalf("%q is not a UUIDv7", id)
+			}
+		}
+	}
+
+	if _, err := ForFormat("snowflake"); err == nil {
+		t.Error("unknown format accepted")
+	}
+}
@@ -3,6 +3,7 @@ package logger
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"time"
@@ -21,6 +22,7 @@ const (
 var (
 	currentLevel Level = INFO
 	logger       *log.Logger
+	output       io.Writer = os.Stdout
 )
 
 // logEntry represents a structured log entry
@@ -33,7 +35,7 @@ type logEntry struct {
 
 // Init initializes the logger with the specified level
 func Init(level string) {
-	logger = log.New(os.Stdout, "", 0)
+	logger = log.New(output, "", 0)
 	
 	switch level {
 	case "debug":
@@ -49,6 +51,15 @@ func Init(level string) {
 	}
 }
 
+// SetOutput redirects log output, e.g. to stderr so that stdout stays
+// clean for machine-readable command output
+func SetOutput(w io.Writer) {
+	output = w
+	if logger != nil {
+		logger.SetOutput(w)
+	}
+}
+
 // Debug logs a debug message with optional key-value pairs
 func Debug(msg string, keysAndValues ...interface{}) {
 	if currentLevel <= DEBUG {
@@ -56,6 +67,12 @@ func Debug(msg string, keysAndValues ...interface{}) {
 	}
 }
 
+// DebugEnabled reports whether debug messages are written, so hot loops can
+// skip building fields that would be thrown away
+func DebugEnabled() bool {
+	return currentLevel <= DEBUG
+}
+
 // Info logs an info message with optional key-value pairs
 func Info(msg string, keysAndValues ...interface{}) {
 	if currentLevel <= INFO {
@@ -94,7 +111,11 @@ func writeLog(level, msg string, keysAndValues ...interface{}) {
 	for i := 0; i < len(keysAndValues); i += 2 {
 		if i+1 < len(keysAndValues) {
 			key := fmt.Sprint(keysAndValues[i])
-			entry.Fields[key] = keysAndValues[i+1]
+			value := keysAndValues[i+1]
+			if err, ok := value.(error); ok && err != nil {
+				value = err.Error() // Errors marshal to {} otherwise
+			}
+			entry.Fields[key] = value
 		}
 	}
 
@@ -134,8 +155,12 @@ func NewContext(module string, fields ...interface{}) *ContextLogger {
 	return cl
 }
 
-// Debug logs with context
+// Debug logs with context. The level is checked before merging fields
+// since debug calls sit on hot paths such as per-keystroke typing.
 func (cl *ContextLogger) Debug(msg string, keysAndValues ...interface{}) {
+	if currentLevel > DEBUG {
+		return
+	}
 	Debug(msg, cl.mergeFields(keysAndValues...)...)
 }
 
@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSetOutput(t *testing.T) {
+	defer SetOutput(os.Stdout)
+
+	// Redirected before Init, as -output json does, and after
+	var before, after bytes.Buffer
+	SetOutput(&before)
+	Init("info")
+	Info("Loaded", "count", 3, "error", errors.New("disk full"))
+	Debug("Not written at info level")
+	SetOutput(&after)
+	Warn("Redirected")
+
+	var entry logEntry
+	if err := json.Unmarshal(before.Bytes(), &entry); err != nil {
+		t.Fatalf("entry is not JSON: %v\n%s", err, before.String())
+	}
+	if entry.Level != "INFO" || entry.Message != "Loaded" || entry.Fields["count"] != 3.0 || entry.Fields["error"] != "disk full" {
+		t.Errorf("entry = %+v", entry)
+	}
+	if !strings.Contains(after.String(), `"message":"Redirected"`) || strings.Contains(before.String(), "Redirected") {
+		t.Errorf("output not redirected: before %q, after %q", before.String(), after.String())
+	}
+}
@@ -0,0 +1,162 @@
+package maintenance
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"subspace/internal/config"
+	"subspace/internal/logger"
+	"subspace/internal/stealth"
+	"subspace/internal/storage"
+)
+
+/*
+MAINTENANCE MODULE
+
+Housekeeping jobs that keep the data directory bounded over long runs.
+
+RETENTION:
+Each record type has its own retention period (see RetentionConfig):
+- profiles:     kept forever by default (the dedup history is valuable)
+- messages:     1 year
+- action logs:  90 days (rate limits look back one day, the touch
+                guardrail touch_window_days, which this must cover)
+- screenshots:  30 days
+- traces:       30 days
+
+Profiles in db.json that share a profile key (the same person stored under
+slightly different URLs) are merged before retention is applied. Retention
+applies to the configured backend, db.json or Postgres.
+*/
+
+// ScreenshotsDir is where screenshots are stored, relative to the data dir
+const ScreenshotsDir = "screenshots"
+
+// Report summarises what a maintenance run purged
+type Report struct {
+	StartedAt   time.Time     `json:"started_at"`
+	Duration    time.Duration `json:"duration"`
+	Merged      int           `json:"merged"`
+	Profiles    int           `json:"profiles"`
+	Messages    int           `json:"messages"`
+	ActionLogs  int           `json:"action_logs"`
+	Screenshots int           `json:"screenshots"`
+	Traces      int           `json:"traces"`
+}
+
+// Total returns the number of records and files purged (merges excluded)
+func (r *Report) Total() int {
+	return r.Profiles + r.Messages + r.ActionLogs + r.Screenshots + r.Traces
+}
+
+// merger is a backend that can merge duplicate profiles, like the db.json
+// file
+type merger interface {
+	MergeDuplicates() (int, error)
+}
+
+// Run enforces the retention policy against storage and the screenshots
+// and traces directories, returning a report of everything that was purged
+func Run(cfg config.RetentionConfig, db storage.Backend, dataDir string) (*Report, error) {
+	log := logger.NewContext("maintenance")
+	report := &Report{StartedAt: time.Now()}
+
+	log.Info("Running retention maintenance",
+		"profiles_days", cfg.ProfilesDays,
+		"messages_days", cfg.MessagesDays,
+		"action_logs_days", cfg.ActionLogsDays,
+		"screenshots_days", cfg.ScreenshotsDays,
+		"traces_days", cfg.TracesDays)
+
+	if m, ok := db.(merger); ok {
+		merged, err := m.MergeDuplicates()
+		if err != nil {
+			logger.Timing("maintenance", "run", report.StartedAt, err)
+			return nil, fmt.Errorf("failed to merge duplicate profiles: %w", err)
+		}
+		report.Merged = merged
+	}
+
+	purged, err := db.Purge(
+		cutoff(report.StartedAt, cfg.ProfilesDays),
+		cutoff(report.StartedAt, cfg.MessagesDays),
+		cutoff(report.StartedAt, cfg.ActionLogsDays),
+	)
+	if err != nil {
+		logger.Timing("maintenance", "run", report.StartedAt, err)
+		return nil, fmt.Errorf("failed to purge storage: %w", err)
+	}
+	report.Profiles = purged.Profiles
+	report.Messages = purged.Messages
+	report.ActionLogs = purged.ActionLogs
+
+	dir := filepath.Join(dataDir, ScreenshotsDir)
+	report.Screenshots, err = purgeFiles(dir, cutoff(report.StartedAt, cfg.ScreenshotsDays))
+	if err != nil {
+		logger.Timing("maintenance", "run", report.StartedAt, err)
+		return nil, fmt.Errorf("failed to purge screenshots: %w", err)
+	}
+
+	dir = filepath.Join(dataDir, stealth.TracesDir)
+	report.Traces, err = purgeFiles(dir, cutoff(report.StartedAt, cfg.TracesDays))
+	if err != nil {
+		logger.Timing("maintenance", "run", report.StartedAt, err)
+		return nil, fmt.Errorf("failed to purge action traces: %w", err)
+	}
+
+	report.Duration = time.Since(report.StartedAt)
+	logger.Timing("maintenance", "run", report.StartedAt, nil)
+	log.Info("Retention maintenance complete",
+		"merged", report.Merged,
+		"profiles", report.Profiles,
+		"messages", report.Messages,
+		"action_logs", report.ActionLogs,
+		"screenshots", report.Screenshots,
+		"traces", report.Traces)
+
+	return report, nil
+}
+
+// cutoff converts a retention period into a cutoff time; zero days means
+// "keep forever" and yields the zero time
+func cutoff(now time.Time, days int) time.Time {
+	if days <= 0 {
+		return time.Time{}
+	}
+	return now.AddDate(0, 0, -days)
+}
+
+// purgeFiles removes regular files in dir last modified before the cutoff
+func purgeFiles(dir string, before time.Time) (int, error) {
+	if before.IsZero() {
+		return 0, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return removed, err
+		}
+		if info.ModTime().Before(before) {
+			if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+				return removed, err
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,107 @@
+package maintenance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/stealth"
+	"subspace/internal/storage"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	db, err := storage.New(filepath.Join(dir, "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	old, recent := now.AddDate(0, 0, -400), now.AddDate(0, 0, -5)
+	for _, p := range []*storage.Profile{
+		{ID: "old", ProfileURL: "https://www.linkedin.com/in/old", DiscoveredAt: old},
+		{ID: "new", ProfileURL: "https://www.linkedin.com/in/new", DiscoveredAt: recent},
+		{ID: "dup", ProfileURL: "https://www.linkedin.com/in/NEW/", DiscoveredAt: recent.Add(time.Hour)},
+	} {
+		if err := db.SaveProfile(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, m := range []*storage.Message{
+		{ID: "m-old", ProfileID: "old", SentAt: old},
+		{ID: "m-expired", ProfileID: "new", SentAt: old},
+		{ID: "m-new", ProfileID: "new", SentAt: recent},
+	} {
+		if err := db.SaveMessage(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clock.Set(clock.NewFake(old))
+	db.LogAction("connection", "old", true, nil)
+	clock.Set(clock.Real{})
+	db.LogAction("connection", "new", true, nil)
+
+	screenshots, traces := filepath.Join(dir, ScreenshotsDir), filepath.Join(dir, stealth.TracesDir)
+	for _, path := range []string{
+		filepath.Join(screenshots, "old.png"), filepath.Join(screenshots, "new.png"),
+		filepath.Join(traces, "old.jsonl"),
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, path := range []string{filepath.Join(screenshots, "old.png"), filepath.Join(traces, "old.jsonl")} {
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.RetentionConfig{ProfilesDays: 365, MessagesDays: 365, ActionLogsDays: 90, ScreenshotsDays: 30, TracesDays: 30}
+	report, err := Run(cfg, db, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Report{Merged: 1, Profiles: 1, Messages: 2, ActionLogs: 1, Screenshots: 1, Traces: 1}
+	report.StartedAt, report.Duration = time.Time{}, 0
+	if *report != want {
+		t.Errorf("report = %+v, want %+v", *report, want)
+	}
+	if report.Total() != 6 {
+		t.Errorf("Total = %d, want 6 (merges left out)", report.Total())
+	}
+	if _, err := db.GetProfile("new"); err != nil {
+		t.Errorf("recent profile purged: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(screenshots, "new.png")); err != nil {
+		t.Errorf("recent screenshot purged: %v", err)
+	}
+}
+
+func TestRunKeepsForever(t *testing.T) {
+	dir := t.TempDir()
+	db, err := storage.New(filepath.Join(dir, "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ancient := time.Now().AddDate(-10, 0, 0)
+	if err := db.SaveProfile(&storage.Profile{ID: "p", ProfileURL: "https://www.linkedin.com/in/p", DiscoveredAt: ancient}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveMessage(&storage.Message{ID: "m", ProfileID: "p", SentAt: ancient}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Zero days keeps a record type forever
+	report, err := Run(config.RetentionConfig{}, db, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total() != 0 {
+		t.Errorf("purged %+v with every period at zero", *report)
+	}
+}
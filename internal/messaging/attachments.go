@@ -0,0 +1,68 @@
+package messaging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"subspace/internal/clock"
+)
+
+// linkPattern matches URLs in message text, stopping before trailing
+// sentence punctuation
+var linkPattern = regexp.MustCompile(`https?://[^\s]*[^\s.,;:!?)"']`)
+
+// segment is a run of message text that is either plain text or a link
+type segment struct {
+	text string
+	link bool
+}
+
+// splitLinks splits content into text and link segments, in order
+func splitLinks(content string) []segment {
+	var segs []segment
+	last := 0
+	for _, loc := range linkPattern.FindAllStringIndex(content, -1) {
+		if loc[0] > last {
+			segs = append(segs, segment{text: content[last:loc[0]]})
+		}
+		segs = append(segs, segment{text: content[loc[0]:loc[1]], link: true})
+		last = loc[1]
+	}
+	if last < len(content) {
+		segs = append(segs, segment{text: content[last:]})
+	}
+	return segs
+}
+
+// links returns the URLs in content
+func links(content string) []string {
+	return linkPattern.FindAllString(content, -1)
+}
+
+// attachFiles uploads files to the open conversation before the message is
+// typed, waiting for each upload roughly in proportion to its size
+func (m *Messenger) attachFiles(paths []string) error {
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("attachment %s: %w", path, err)
+		}
+		m.log.Info("Attaching file", "file", filepath.Base(path), "bytes", info.Size())
+
+		// Open the file chooser via the paperclip
+		m.stealth.ClickAt(0.35, 0.81)
+		// In production: m.browser.Click(".msg-form__attachment-button") and
+		// page.MustElement("input[type=file]").MustSetFiles(path)
+
+		// Picking the file in the dialog
+		m.stealth.ThinkingPause()
+
+		// Upload at ~1MB/s, at least a second
+		upload := time.Second + time.Duration(info.Size())*time.Second/(1<<20)
+		clock.Sleep(upload)
+	}
+	return nil
+}
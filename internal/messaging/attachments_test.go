@@ -0,0 +1,25 @@
+package messaging
+
+import "testing"
+
+func TestSplitLinks(t *testing.T) {
+	got := splitLinks("See https://example.com/a?b=1. Or (https://x.io), thanks!")
+	want := []segment{
+		{text: "See "},
+		{text: "https://example.com/a?b=1", link: true},
+		{text: ". Or ("},
+		{text: "https://x.io", link: true},
+		{text: "), thanks!"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d segments, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("segment %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if segs := splitLinks("no links here"); len(segs) != 1 || segs[0].link {
+		t.Errorf("plain text split into %+v", segs)
+	}
+}
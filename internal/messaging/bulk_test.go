@@ -0,0 +1,43 @@
+package messaging
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"subspace/internal/batch"
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/logger"
+	"subspace/internal/stealth"
+	"subspace/internal/storage"
+)
+
+func TestSendBulkMessagesLogsFailures(t *testing.T) {
+	logger.Init("error")
+	fake := clock.NewFake(time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC))
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	cfg := config.Defaults()
+	db, err := storage.New(filepath.Join(t.TempDir(), "db.json"), cfg.Storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := New(nil, stealth.New(cfg.Stealth, nil), db, cfg.Limits, cfg.Messaging)
+
+	// Snoozed recipients fail before anything is sent
+	ada := &storage.Profile{ID: "ada", Name: "Ada", State: storage.StateAccepted}
+	ada.Snooze(fake.Now().Add(time.Hour), "on leave")
+	result, err := m.SendBulkMessages([]*storage.Profile{ada}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Count(batch.Failed) != 1 {
+		t.Fatalf("result = %+v, want one failure", result.Items)
+	}
+	logs := db.GetActionLogs("message")
+	if len(logs) != 1 || logs[0].Success || logs[0].ProfileID != "ada" {
+		t.Errorf("action log = %+v, want the failed message", logs)
+	}
+}
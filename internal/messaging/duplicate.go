@@ -0,0 +1,46 @@
+package messaging
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/storage"
+)
+
+// checkDuplicate blocks sending a profile the exact text it already
+// received and warns when the same text went out too often recently.
+// Texts are compared with whitespace collapsed and case folded.
+func (m *Messenger) checkDuplicate(profile *storage.Profile, content string) error {
+	key := contentKey(content)
+	for _, msg := range m.storage.GetMessagesByProfile(profile.ID) {
+		if !msg.Inbound && contentKey(msg.Content) == key {
+			return fmt.Errorf("duplicate message: %s already received this text on %s",
+				profile.ID, msg.SentAt.Format("2006-01-02 15:04"))
+		}
+	}
+
+	if m.cfg.DuplicateWarnCount <= 0 {
+		return nil
+	}
+	since := clock.Now().Add(-time.Duration(m.cfg.DuplicateWindowHours) * time.Hour)
+	identical := 0
+	for _, msg := range m.storage.GetMessagesSince(since) {
+		if !msg.Inbound && contentKey(msg.Content) == key {
+			identical++
+		}
+	}
+	if identical >= m.cfg.DuplicateWarnCount {
+		m.log.Warn("Identical message sent to many profiles recently",
+			"count", identical,
+			"window_hours", m.cfg.DuplicateWindowHours,
+			"threshold", m.cfg.DuplicateWarnCount)
+	}
+	return nil
+}
+
+// contentKey normalizes a message for comparison
+func contentKey(content string) string {
+	return strings.ToLower(strings.Join(strings.Fields(content), " "))
+}
@@ -0,0 +1,48 @@
+package messaging
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/logger"
+	"subspace/internal/storage"
+)
+
+func TestCheckDuplicate(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC))
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	db, err := storage.New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &Messenger{
+		storage: db,
+		cfg:     config.MessagingConfig{DuplicateWarnCount: 1, DuplicateWindowHours: 24},
+		log:     logger.NewContext("messaging"),
+	}
+	ada := &storage.Profile{ID: "ada"}
+	alan := &storage.Profile{ID: "alan"}
+
+	sent := "Hi Ada,\n\nThanks for connecting!"
+	if err := db.SaveMessage(&storage.Message{ID: "m1", ProfileID: ada.ID, Content: sent, SentAt: fake.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	fake.Advance(48 * time.Hour)
+
+	for _, content := range []string{sent, "hi ada, thanks   for connecting!"} {
+		if err := m.checkDuplicate(ada, content); err == nil {
+			t.Errorf("repeat %q to the same profile was not blocked", content)
+		}
+	}
+	if err := m.checkDuplicate(ada, "Hi Ada, following up on our chat."); err != nil {
+		t.Errorf("new text to the same profile was blocked: %v", err)
+	}
+	if err := m.checkDuplicate(alan, sent); err != nil {
+		t.Errorf("same text to another profile was blocked: %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package messaging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"subspace/internal/i18n"
+	"subspace/internal/storage"
+)
+
+/*
+TEMPLATE GOLDEN FILES
+
+Every template is rendered against a fixed set of edge-case profiles and
+compared with the expected output stored under
+<dir>/<language>/<template>/<fixture>.golden. A template that references a
+missing variable, or whose output drifts from its golden file, fails the
+check. Run with update to accept the current output as the new golden.
+*/
+
+// Golden check outcomes
+const (
+	GoldenOK      = "ok"
+	GoldenDrift   = "drift"   // Output differs from the golden file
+	GoldenMissing = "missing" // No golden file yet
+	GoldenError   = "error"   // Template failed to render
+	GoldenUpdated = "updated"
+)
+
+// GoldenResult is the outcome for one template and fixture
+type GoldenResult struct {
+	Template string `json:"template"`
+	Fixture  string `json:"fixture"`
+	Path     string `json:"path"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Failed reports whether the result should fail the check
+func (r GoldenResult) Failed() bool {
+	return r.Status != GoldenOK && r.Status != GoldenUpdated
+}
+
+// TemplateFixtures returns the edge-case profiles templates are checked
+// against
+func TemplateFixtures() []*storage.Profile {
+	discovered := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	return []*storage.Profile{
+		{
+			ID: "complete", Name: "Dr. Ada Lovelace", Title: "Principal Engineer", Company: "Analytical Engines Ltd",
+			Location: "London, United Kingdom", SearchQuery: "golang", DiscoveredAt: discovered,
+			Notes:  "2024-03-04 09:30 met at GopherCon",
+			Shared: &storage.SharedContext{MutualConnections: 12, Groups: []string{"Gophers Slack"}, Schools: []string{"MIT"}},
+		},
+		{ID: "empty-company", Name: "Sam Taylor", Title: "Independent Consultant", DiscoveredAt: discovered},
+		{ID: "empty", Name: "", DiscoveredAt: discovered},
+		{ID: "unicode", Name: "José Ñúñez-Müller 🚀", Title: "Ingeniero de Software", Company: "Café Técnico S.L.", DiscoveredAt: discovered},
+		{ID: "reversed", Name: "SMITH, Jane", Title: "CTO", Company: "Acme", DiscoveredAt: discovered},
+		{
+			ID: "long-title", Name: "Mary Ann van der Berg, PhD", Company: "Very Large Enterprise Holdings International Group",
+			Title: "Senior Vice President of Global Engineering, Platform Infrastructure, Developer Experience " +
+				"and Cloud Operations | Speaker | Mentor | Angel Investor | Board Member",
+			DiscoveredAt: discovered,
+		},
+	}
+}
+
+// CheckGolden renders every template against every fixture and compares
+// the output with the golden files in dir, or rewrites them when update
+// is set
+func (m *Messenger) CheckGolden(dir string, update bool) []GoldenResult {
+	templates := m.ListTemplates()
+	sort.Strings(templates)
+
+	var results []GoldenResult
+	for _, name := range templates {
+		for _, fixture := range TemplateFixtures() {
+			r := GoldenResult{
+				Template: name,
+				Fixture:  fixture.ID,
+				Path:     filepath.Join(dir, i18n.Language(), name, fixture.ID+".golden"),
+			}
+			r.Status, r.Error = m.checkGolden(name, fixture, r.Path, update)
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+// checkGolden checks one template and fixture
+func (m *Messenger) checkGolden(name string, fixture *storage.Profile, path string, update bool) (string, string) {
+	got, err := m.Render(name, fixture)
+	if err != nil {
+		return GoldenError, err.Error()
+	}
+
+	if update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return GoldenError, err.Error()
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			return GoldenError, err.Error()
+		}
+		return GoldenUpdated, ""
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return GoldenMissing, ""
+	}
+	if err != nil {
+		return GoldenError, err.Error()
+	}
+	if string(want) != got {
+		return GoldenDrift, fmt.Sprintf("got:\n%s\nwant:\n%s", got, want)
+	}
+	return GoldenOK, ""
+}
@@ -0,0 +1,26 @@
+package messaging
+
+import (
+	"testing"
+
+	"subspace/internal/config"
+	"subspace/internal/i18n"
+)
+
+// TestTemplateGolden checks the built-in templates of every language
+// against testdata. After an intended change, regenerate with:
+// subspace templates test -update
+func TestTemplateGolden(t *testing.T) {
+	defer i18n.SetLanguage("en")
+	for _, lang := range i18n.Languages() {
+		if err := i18n.SetLanguage(lang); err != nil {
+			t.Fatal(err)
+		}
+		m := New(nil, nil, nil, config.LimitsConfig{}, config.Defaults().Messaging)
+		for _, r := range m.CheckGolden("../../testdata/templates", false) {
+			if r.Failed() {
+				t.Errorf("%s: %s %s", r.Path, r.Status, r.Error)
+			}
+		}
+	}
+}
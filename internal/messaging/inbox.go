@@ -0,0 +1,132 @@
+package messaging
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/estop"
+	"subspace/internal/ids"
+	"subspace/internal/logger"
+	"subspace/internal/storage"
+)
+
+// ManualTemplate is the template name recorded on replies typed by the
+// operator
+const ManualTemplate = "manual"
+
+// CheckInbox records new replies from messaged connections and returns the
+// number of conversations awaiting an answer
+func (m *Messenger) CheckInbox() (int, error) {
+	m.log.Info("Checking inbox")
+
+	// EDUCATIONAL NOTE: In production this would:
+	// 1. Open the messaging page
+	// 2. Walk the unread conversations in the thread list
+	// 3. Store each new message from the other participant
+	//
+	// For PoC, some messaged connections reply with canned text
+	replies := []string{
+		"Thanks for reaching out! Happy to connect.",
+		"Hi, thanks for the message. What did you have in mind?",
+		"Appreciate it - could you contact me again next quarter?",
+		"Thanks, but I'm not looking for anything at the moment.",
+	}
+
+	received := 0
+	for _, state := range []storage.ProfileState{storage.StateAccepted, storage.StateCooledDown} {
+		for _, profile := range m.storage.GetProfilesByState(state) {
+			if len(m.storage.GetMessagesByProfile(profile.ID)) == 0 || m.storage.HasReplied(profile.ID) {
+				continue
+			}
+			if !m.stealth.ShouldProceed(0.3) {
+				continue
+			}
+			reply := &storage.Message{
+				ID:        "msg-" + ids.New(),
+				ProfileID: profile.ID,
+				Content:   replies[len(profile.ID)%len(replies)],
+				SentAt:    clock.Now(),
+				Inbound:   true,
+			}
+			if err := m.storage.SaveMessage(reply); err != nil {
+				return 0, fmt.Errorf("failed to save reply: %w", err)
+			}
+			m.log.Info("Reply received", "profile", profile.Name)
+			received++
+
+			if until, ok := SnoozeRequest(reply.Content, reply.SentAt); ok {
+				profile.Snooze(until, reply.Content)
+				if err := m.storage.SaveProfile(profile); err != nil {
+					return 0, fmt.Errorf("failed to snooze profile: %w", err)
+				}
+				m.log.Info("Profile asked to be contacted later", "profile", profile.Name, "until", until.Format("2006-01-02"))
+			}
+		}
+	}
+
+	waiting := len(m.storage.Inbox())
+	m.log.Info("Inbox check complete", "new_replies", received, "awaiting_answer", waiting)
+	return waiting, nil
+}
+
+// SendReply types an operator-written reply into a conversation with the
+// same human-like typing as automated messages. It counts against the
+// message limits.
+func (m *Messenger) SendReply(profile *storage.Profile, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("reply is empty")
+	}
+	m.log.Info("Sending manual reply", "profile", profile.Name)
+	start := time.Now()
+
+	if err := estop.Check("reply"); err != nil {
+		return err
+	}
+
+	if !m.limiter.Allow("message") {
+		err := fmt.Errorf("daily message limit reached: %d in the last 24h", m.limits.MessagesPerDay)
+		logger.Timing("messaging", "send_reply", start, err)
+		return err
+	}
+	if err := m.limiter.CheckProfile(profile.ID); err != nil {
+		logger.Timing("messaging", "send_reply", start, err)
+		return err
+	}
+	if err := m.checkDuplicate(profile, text); err != nil {
+		logger.Timing("messaging", "send_reply", start, err)
+		return err
+	}
+
+	if err := m.navigateToConversation(profile); err != nil {
+		logger.Timing("messaging", "send_reply", start, err)
+		return fmt.Errorf("failed to navigate: %w", err)
+	}
+	if err := m.typeAndSend(text, m.cfg.LinkInsertion); err != nil {
+		logger.Timing("messaging", "send_reply", start, err)
+		return fmt.Errorf("failed to send reply: %w", err)
+	}
+
+	message := &storage.Message{
+		ID:        "msg-" + ids.New(),
+		ProfileID: profile.ID,
+		Content:   text,
+		SentAt:    clock.Now(),
+		Template:  ManualTemplate,
+		Links:     links(text),
+	}
+	err := m.storage.Transaction(func(tx *storage.Tx) error {
+		tx.SaveMessage(message)
+		tx.LogActionFrom(storage.SourceManual, "message", profile.ID, true, nil)
+		return nil
+	})
+	if err != nil {
+		m.log.Error("Failed to save reply record", "error", err)
+		// Don't fail the operation, the reply was sent
+	}
+
+	logger.Timing("messaging", "send_reply", start, nil)
+	return nil
+}
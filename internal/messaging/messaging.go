@@ -1,65 +1,136 @@
 package messaging
 
 import (
+	"errors"
 	"fmt"
-	"strings"
+	"path/filepath"
+	"sort"
 	"time"
 
+	"subspace/internal/clock"
+	"subspace/internal/batch"
 	"subspace/internal/browser"
+	"subspace/internal/campaign"
 	"subspace/internal/config"
+	"subspace/internal/estop"
+	"subspace/internal/hooks"
+	"subspace/internal/i18n"
+	"subspace/internal/ids"
 	"subspace/internal/logger"
+	"subspace/internal/modals"
+	"subspace/internal/ratelimit"
 	"subspace/internal/stealth"
 	"subspace/internal/storage"
+	"subspace/internal/timezone"
 )
 
 // Messenger handles message sending operations
 type Messenger struct {
 	browser   browser.Controller
 	stealth   *stealth.Stealth
-	storage   *storage.Storage
+	storage   storage.Backend
 	limits    config.LimitsConfig
+	limiter   *ratelimit.Limiter
+	cfg       config.MessagingConfig
+	zones     *timezone.Resolver
+	fallback  *time.Location // Zone of recipients whose location is unknown
 	templates map[string]string
+	batch     int       // Most messages per ProcessAcceptedConnections, 0 for no cap
+	session   time.Time // Start of the scheduled session, for messages_per_session
+	hooks     *hooks.Hooks
+	modals    *modals.Dismisser
 	log       *logger.ContextLogger
+
+	tags      []string // Only follow up with profiles carrying one of these; empty for all
+	campaigns *campaign.Allocator
 }
 
 // New creates a new messenger with default templates
-func New(b browser.Controller, s *stealth.Stealth, storage *storage.Storage, limits config.LimitsConfig) *Messenger {
+func New(b browser.Controller, s *stealth.Stealth, storage storage.Backend, limits config.LimitsConfig, cfg config.MessagingConfig) *Messenger {
 	m := &Messenger{
 		browser:   b,
 		stealth:   s,
 		storage:   storage,
 		limits:    limits,
+		limiter:   ratelimit.New(storage, limits),
+		cfg:       cfg,
 		templates: make(map[string]string),
+		session:   clock.Now(),
 		log:       logger.NewContext("messaging"),
 	}
 
+	// Zones are validated with the config, so this only fails on a
+	// hand-built config; recipient hours are then not enforced
+	zones, err := timezone.NewResolver(cfg.LocationTimeZones)
+	if err != nil {
+		m.log.Error("Recipient time zones unavailable", "error", err)
+	}
+	m.zones = zones
+	if cfg.DefaultTimeZone != "" {
+		m.fallback, _ = time.LoadLocation(cfg.DefaultTimeZone)
+	}
+
 	// Load default templates
 	m.loadDefaultTemplates()
 
 	return m
 }
 
-// loadDefaultTemplates sets up default message templates
-func (m *Messenger) loadDefaultTemplates() {
-	m.templates["follow_up"] = `Hi {{.Name}},
+// SetBatch caps the messages each ProcessAcceptedConnections sends, for
+// interleaved workflows; 0 lifts the cap
+func (m *Messenger) SetBatch(n int) {
+	m.batch = n
+}
 
-Thanks for connecting! I noticed your background in {{.Title}} at {{.Company}}.
+// SetSession sets when the scheduled session the run is in began; messages
+// sent since then count towards messages_per_session
+func (m *Messenger) SetSession(start time.Time) {
+	m.session = start
+}
 
-I'm always interested in connecting with professionals in the field. Would love to stay in touch!
+// SessionFull reports whether this session's message cap is used up
+func (m *Messenger) SessionFull() bool {
+	return m.limits.MessagesPerSession > 0 && m.sessionMessages() >= m.limits.MessagesPerSession
+}
 
-Best regards`
+// sessionMessages counts the messages sent since the session began
+func (m *Messenger) sessionMessages() int {
+	return m.storage.GetActionCountSince("message", m.session)
+}
 
-	m.templates["introduction"] = `Hi {{.Name}},
+// SetHooks runs the can_send and mutate_message hooks
+func (m *Messenger) SetHooks(h *hooks.Hooks) {
+	m.hooks = h
+}
 
-I came across your profile and was impressed by your experience in {{.Title}}.
+// SetModals dismisses consent and promotional modals on each conversation
+// page
+func (m *Messenger) SetModals(d *modals.Dismisser) {
+	m.modals = d
+}
 
-I'm working on some interesting projects and thought we might have synergies to explore.
+// SetCampaigns resolves {{.Campaign.Name}} with the targeting campaigns
+func (m *Messenger) SetCampaigns(a *campaign.Allocator) {
+	m.campaigns = a
+}
 
-Looking forward to connecting!`
+// SetTags limits follow-ups to profiles carrying one of the tags; empty
+// follows up with every profile
+func (m *Messenger) SetTags(tags []string) {
+	m.tags = tags
+}
 
-	m.templates["follow_up_short"] = `Hi {{.Name}}, thanks for connecting! Looking forward to staying in touch.`
+// defaultTemplateNames lists the built-in templates; their text comes from
+// the i18n catalog for the configured language
+var defaultTemplateNames = []string{"follow_up", "introduction", "follow_up_short"}
 
-	m.log.Info("Loaded message templates", "count", len(m.templates))
+// loadDefaultTemplates sets up default message templates
+func (m *Messenger) loadDefaultTemplates() {
+	for _, name := range defaultTemplateNames {
+		m.templates[name] = i18n.T("template." + name)
+	}
+
+	m.log.Info("Loaded message templates", "count", len(m.templates), "language", i18n.Language())
 }
 
 // SendMessage sends a message to a connected profile
@@ -67,10 +138,13 @@ func (m *Messenger) SendMessage(profile *storage.Profile, templateName string) e
 	m.log.Info("Sending message", "profile", profile.Name, "template", templateName)
 	start := time.Now()
 
+	if err := estop.Check("message"); err != nil {
+		return err
+	}
+
 	// Check message limits
-	messagesToday := m.storage.GetActionCountToday("message")
-	if messagesToday >= m.limits.MessagesPerDay {
-		err := fmt.Errorf("daily message limit reached: %d/%d", messagesToday, m.limits.MessagesPerDay)
+	if !m.limiter.Allow("message") {
+		err := fmt.Errorf("daily message limit reached: %d in the last 24h", m.limits.MessagesPerDay)
 		m.log.Warn("Cannot send message", "error", err)
 		return err
 	}
@@ -79,6 +153,17 @@ func (m *Messenger) SendMessage(profile *storage.Profile, templateName string) e
 	if profile.State != storage.StateAccepted && profile.State != storage.StateCooledDown {
 		return fmt.Errorf("cannot message profile in state: %s", profile.State)
 	}
+	if profile.Snoozed(clock.Now()) {
+		return fmt.Errorf("profile %s is snoozed until %s", profile.ID, profile.SnoozedUntil.Format("2006-01-02 15:04"))
+	}
+	if err := m.limiter.CheckProfile(profile.ID); err != nil {
+		m.log.Warn("Cannot send message", "profile", profile.Name, "error", err)
+		return err
+	}
+	if err := m.hooks.CanSend("message", profile); err != nil {
+		m.log.Info("Cannot send message", "profile", profile.Name, "error", err)
+		return err
+	}
 
 	// Check if we've already messaged this profile
 	existingMessages := m.storage.GetMessagesByProfile(profile.ID)
@@ -88,62 +173,81 @@ func (m *Messenger) SendMessage(profile *storage.Profile, templateName string) e
 	}
 
 	// Generate personalized message
-	content, err := m.renderTemplate(templateName, profile)
+	content, err := m.Render(templateName, profile)
 	if err != nil {
 		logger.Timing("messaging", "send_message", start, err)
 		return fmt.Errorf("failed to render template: %w", err)
 	}
 
+	if content, err = m.hooks.MutateMessage(profile, templateName, content); err != nil {
+		logger.Timing("messaging", "send_message", start, err)
+		return err
+	}
 	m.log.Debug("Rendered message", "length", len(content))
 
-	// Navigate to messaging with profile
-	if err := m.navigateToConversation(profile); err != nil {
+	if err := m.checkDuplicate(profile, content); err != nil {
+		m.log.Warn("Blocked duplicate message", "profile", profile.Name, "error", err)
 		logger.Timing("messaging", "send_message", start, err)
-		return fmt.Errorf("failed to navigate: %w", err)
+		return err
 	}
 
-	// Type and send message
-	if err := m.typeAndSend(content); err != nil {
+	// Navigate, attach, type and send, tracing the stealth decisions
+	opts := m.cfg.Templates[templateName]
+	m.stealth.BeginAction("message", profile.ID, templateName)
+	err = m.deliver(profile, content, templateName)
+	m.stealth.EndAction(err)
+	if err != nil {
 		logger.Timing("messaging", "send_message", start, err)
-		return fmt.Errorf("failed to send message: %w", err)
+		return err
 	}
 
 	// Save message record
 	message := &storage.Message{
-		ID:        fmt.Sprintf("msg-%d", time.Now().UnixNano()),
+		ID:        "msg-" + ids.New(),
 		ProfileID: profile.ID,
 		Content:   content,
-		SentAt:    time.Now(),
+		SentAt:    clock.Now(),
 		Template:  templateName,
+		Links:     links(content),
+	}
+	for _, path := range opts.Attachments {
+		message.Attachments = append(message.Attachments, filepath.Base(path))
 	}
 
-	if err := m.storage.SaveMessage(message); err != nil {
+	// Save message record and log action for rate limiting in one commit
+	err = m.storage.Transaction(func(tx *storage.Tx) error {
+		tx.SaveMessage(message)
+		tx.LogAction("message", profile.ID, true, nil)
+		return nil
+	})
+	if err != nil {
 		m.log.Error("Failed to save message record", "error", err)
 		// Don't fail the operation, message was sent
 	}
 
-	// Log action for rate limiting
-	m.storage.LogAction("message", profile.ID, true, nil)
-
 	logger.Timing("messaging", "send_message", start, nil)
 	m.log.Info("Message sent successfully", "profile", profile.Name)
 
 	return nil
 }
 
-// renderTemplate fills in template variables with profile data
-func (m *Messenger) renderTemplate(templateName string, profile *storage.Profile) (string, error) {
-	template, exists := m.templates[templateName]
-	if !exists {
-		return "", fmt.Errorf("template not found: %s", templateName)
+// deliver opens the conversation with a profile, attaches the template's
+// files and sends the message
+func (m *Messenger) deliver(profile *storage.Profile, content, templateName string) error {
+	if err := m.navigateToConversation(profile); err != nil {
+		return fmt.Errorf("failed to navigate: %w", err)
 	}
 
-	content := template
-	content = strings.ReplaceAll(content, "{{.Name}}", profile.Name)
-	content = strings.ReplaceAll(content, "{{.Title}}", profile.Title)
-	content = strings.ReplaceAll(content, "{{.Company}}", profile.Company)
+	if attachments := m.cfg.Templates[templateName].Attachments; len(attachments) > 0 {
+		if err := m.attachFiles(attachments); err != nil {
+			return fmt.Errorf("failed to attach files: %w", err)
+		}
+	}
 
-	return content, nil
+	if err := m.typeAndSend(content, m.cfg.LinkMode(templateName)); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
 }
 
 // navigateToConversation opens the messaging conversation with a profile
@@ -158,70 +262,130 @@ func (m *Messenger) navigateToConversation(profile *storage.Profile) error {
 	// Alternative: Construct direct message URL if profile ID is known
 	// messageURL := fmt.Sprintf("https://www.linkedin.com/messaging/thread/xxx/")
 
-	// Mock navigation
+	// Mock navigation; simulated browsers are driven for real so their
+	// faults surface here
+	page := browser.Simulated(m.browser)
+	if err := page.Navigate(profile.ProfileURL); err != nil {
+		return err
+	}
 	m.stealth.RandomDelay()
 	m.stealth.WaitForPageLoad()
+	m.modals.Clear(page)
 
 	return nil
 }
 
 // typeAndSend types the message and sends it
-func (m *Messenger) typeAndSend(content string) error {
+func (m *Messenger) typeAndSend(content, linkMode string) error {
 	m.log.Debug("Typing and sending message")
+	page := browser.Simulated(m.browser)
 
 	// Step 1: Focus on message box
-	m.stealth.MoveMouse(500, 600) // Mock coordinates
+	m.stealth.MoveTo(0.42, 0.75) // Mock coordinates
 	m.stealth.RandomDelay()
+	m.stealth.ClickAt(0.42, 0.75)
 	// In production: m.browser.Click(".msg-form__contenteditable")
+	if err := page.Click("mock-message-input"); err != nil {
+		return fmt.Errorf("message box: %w", err)
+	}
 
-	// Step 2: Type message with human-like behavior
+	// Step 2: Type message with human-like behavior, pasting links unless
+	// they should be typed too
 	m.stealth.ThinkingPause() // Pause before typing (composing message)
-	m.stealth.TypeHumanLike("mock-message-input", content)
+	for _, seg := range splitLinks(content) {
+		var err error
+		if seg.link && linkMode == "paste" {
+			err = m.stealth.PasteText("mock-message-input", seg.text)
+		} else {
+			err = m.stealth.TypeHumanLike("mock-message-input", seg.text)
+		}
+		if err != nil {
+			return err
+		}
+	}
 
 	// Step 3: Pause before sending (reviewing message)
 	m.stealth.ThinkingPause()
 
 	// Step 4: Move to send button
-	m.stealth.MoveMouse(700, 700)
+	m.stealth.MoveTo(0.58, 0.88)
 	m.stealth.RandomDelay()
 
-	// Step 5: Click send
+	// Step 5: Click send, unless the message took too long already; the
+	// draft is then left unsent and nothing is stored
+	if err := m.stealth.CheckBudget(); err != nil {
+		return err
+	}
+	m.stealth.ClickAt(0.58, 0.88)
 	// In production: m.browser.Click(".msg-form__send-button")
+	if err := page.Click("mock-send-button"); err != nil {
+		return fmt.Errorf("send button: %w", err)
+	}
 	m.log.Debug("Message sent")
 
 	return nil
 }
 
-// SendBulkMessages sends messages to multiple profiles
-func (m *Messenger) SendBulkMessages(profiles []*storage.Profile, templateName string) error {
+// SendBulkMessages sends messages to multiple profiles and returns the
+// outcome for every profile it got to
+func (m *Messenger) SendBulkMessages(profiles []*storage.Profile, templateName string) (*batch.Result, error) {
 	m.log.Info("Starting bulk messaging", "count", len(profiles), "template", templateName)
 	
+	result := batch.New("message")
 	sent := 0
 	failed := 0
+	idle := false // A message went out, so wait before the next
 
 	for i, profile := range profiles {
 		m.log.Info("Processing profile", "index", i+1, "total", len(profiles))
 
+		// Wait a human-scale gap since the last message, browsing idly
+		if idle {
+			m.stealth.IdleBrowse(m.stealth.MessageGap(m.limits.MessageCooldownSeconds))
+			idle = false
+		}
+
+		itemStart := clock.Now()
+		if err := estop.Check("message"); err != nil {
+			m.log.Warn("Stopping bulk send", "sent", sent, "error", err)
+			result.Stopped = batch.StoppedEmergency
+			break
+		}
+
 		// Check if we've hit daily limit
-		messagesToday := m.storage.GetActionCountToday("message")
-		if messagesToday >= m.limits.MessagesPerDay {
+		if !m.limiter.Allow("message") {
 			m.log.Warn("Daily limit reached, stopping bulk send",
 				"sent", sent,
 				"remaining", len(profiles)-i)
+			result.Stopped = batch.StoppedDailyLimit
+			break
+		}
+		if m.SessionFull() {
+			m.log.Warn("Session message cap reached, leaving the rest to the next session",
+				"cap", m.limits.MessagesPerSession,
+				"remaining", len(profiles)-i)
+			result.Stopped = batch.StoppedSessionCap
 			break
 		}
 
 		// Send message
-		if err := m.SendMessage(profile, templateName); err != nil {
+		if err := m.SendMessage(profile, m.TemplateFor(profile, templateName)); err != nil {
 			m.log.Error("Failed to send message", "profile", profile.Name, "error", err)
 			failed++
+
+			// Log failed action, so the failure rate guard sees it
+			m.storage.LogAction("message", profile.ID, false, err)
+			outcome := batch.Failed
+			if errors.Is(err, stealth.ErrActionTimeout) {
+				outcome = batch.TimedOut
+			}
+			result.Add(profile, outcome, itemStart, err)
 			continue
 		}
 
 		sent++
-
-		// Enforce cooldown between messages
-		m.stealth.EnforceCooldown("message", 60) // 60 seconds minimum between messages
+		result.Add(profile, batch.Sent, itemStart, nil)
+		idle = true
 	}
 
 	m.log.Info("Bulk messaging complete",
@@ -229,11 +393,28 @@ func (m *Messenger) SendBulkMessages(profiles []*storage.Profile, templateName s
 		"failed", failed,
 		"total", len(profiles))
 
-	return nil
+	return result, nil
 }
 
-// ProcessAcceptedConnections sends follow-up messages to newly accepted connections
-func (m *Messenger) ProcessAcceptedConnections() error {
+// SortQueue orders the profiles waiting for their first message: by queue
+// priority, then the longest accepted first
+func SortQueue(profiles []*storage.Profile) {
+	sort.Slice(profiles, func(i, j int) bool {
+		a, b := profiles[i].AcceptedAt, profiles[j].AcceptedAt
+		if a != nil && b != nil && !a.Equal(*b) {
+			return a.Before(*b)
+		}
+		if (a == nil) != (b == nil) {
+			return a != nil
+		}
+		return profiles[i].ID < profiles[j].ID
+	})
+	storage.SortByPriority(profiles)
+}
+
+// ProcessAcceptedConnections sends follow-up messages to newly accepted
+// connections and returns the outcome for every profile it got to
+func (m *Messenger) ProcessAcceptedConnections() (*batch.Result, error) {
 	m.log.Info("Processing accepted connections for messaging")
 
 	// Get accepted connections that haven't been messaged yet
@@ -241,6 +422,9 @@ func (m *Messenger) ProcessAcceptedConnections() error {
 	
 	unmessaged := make([]*storage.Profile, 0)
 	for _, profile := range accepted {
+		if !profile.InTags(m.tags) {
+			continue
+		}
 		messages := m.storage.GetMessagesByProfile(profile.ID)
 		if len(messages) == 0 {
 			unmessaged = append(unmessaged, profile)
@@ -248,15 +432,58 @@ func (m *Messenger) ProcessAcceptedConnections() error {
 	}
 
 	m.log.Info("Found unmessaged connections", "count", len(unmessaged))
+	SortQueue(unmessaged)
+
+	// Leave snoozed recipients and those for whom it is night to a later run
+	ready := unmessaged[:0]
+	now := clock.Now()
+	for _, profile := range unmessaged {
+		if !profile.Snoozed(now) && m.InRecipientHours(profile) {
+			ready = append(ready, profile)
+		}
+	}
+	if deferred := len(unmessaged) - len(ready); deferred > 0 {
+		m.log.Info("Deferring messages to snoozed or sleeping recipients", "deferred", deferred)
+	}
+	unmessaged = ready
+	if m.batch > 0 && len(unmessaged) > m.batch {
+		unmessaged = unmessaged[:m.batch]
+	}
 
 	if len(unmessaged) == 0 {
-		return nil
+		return batch.New("message"), nil
 	}
 
 	// Send follow-up messages
 	return m.SendBulkMessages(unmessaged, "follow_up")
 }
 
+// RecipientZone returns the time zone of a profile's normalized place, else
+// the one inferred from its free-text location, the configured default, or
+// nil if none is known
+func (m *Messenger) RecipientZone(profile *storage.Profile) *time.Location {
+	if profile.Place != nil && profile.Place.TimeZone != "" {
+		if loc, err := time.LoadLocation(profile.Place.TimeZone); err == nil {
+			return loc
+		}
+	}
+	if m.zones != nil {
+		if loc := m.zones.Infer(profile.Location); loc != nil {
+			return loc
+		}
+	}
+	return m.fallback
+}
+
+// InRecipientHours reports whether it is currently daytime for the
+// recipient. Recipients with an unknown time zone are always in hours.
+func (m *Messenger) InRecipientHours(profile *storage.Profile) bool {
+	if !m.cfg.RecipientHoursEnabled || m.zones == nil {
+		return true
+	}
+	return timezone.Within(clock.Now(), m.RecipientZone(profile), m.cfg.RecipientHoursStart, m.cfg.RecipientHoursEnd)
+}
+
 // AddTemplate adds a custom message template
 func (m *Messenger) AddTemplate(name, content string) {
 	m.templates[name] = content
@@ -286,18 +513,25 @@ func (m *Messenger) GetMessageHistory(profileID string) []*storage.Message {
 	return m.storage.GetMessagesByProfile(profileID)
 }
 
-// CanSendMore checks if more messages can be sent today
+// CanSendMore checks if the daily window has room for another message
 func (m *Messenger) CanSendMore() bool {
-	today := m.storage.GetActionCountToday("message")
-	return today < m.limits.MessagesPerDay
+	return m.limiter.Allow("message")
+}
+
+// Stats is a snapshot of messaging activity and limits
+type Stats struct {
+	MessagesToday   int  `json:"messages_today"`
+	LimitDaily      int  `json:"limit_daily"`
+	CanSendMore     bool `json:"can_send_more"`
+	TemplatesLoaded int  `json:"templates_loaded"`
 }
 
 // GetStats returns messaging statistics
-func (m *Messenger) GetStats() map[string]interface{} {
-	return map[string]interface{}{
-		"messages_today":   m.storage.GetActionCountToday("message"),
-		"limit_daily":      m.limits.MessagesPerDay,
-		"can_send_more":    m.CanSendMore(),
-		"templates_loaded": len(m.templates),
+func (m *Messenger) GetStats() Stats {
+	return Stats{
+		MessagesToday:   m.storage.GetActionCountToday("message"),
+		LimitDaily:      m.limits.MessagesPerDay,
+		CanSendMore:     m.CanSendMore(),
+		TemplatesLoaded: len(m.templates),
 	}
 }
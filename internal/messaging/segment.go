@@ -0,0 +1,46 @@
+package messaging
+
+import (
+	"subspace/internal/rules"
+	"subspace/internal/storage"
+	"subspace/internal/taxonomy"
+)
+
+// TemplateFor returns the template of the first messaging.template_rules
+// rule the profile matches, else the one in messaging.segment_templates for
+// its title segment ("seniority/function", then the seniority, then the
+// function), else templateName. Configured templates that don't exist are
+// logged and skipped.
+func (m *Messenger) TemplateFor(profile *storage.Profile, templateName string) string {
+	if len(m.cfg.TemplateRules) > 0 {
+		env := profile.RuleEnv()
+		for _, r := range m.cfg.TemplateRules {
+			if !rules.MustCompile(r.When).Match(env) {
+				continue
+			}
+			if _, exists := m.templates[r.Template]; exists {
+				return r.Template
+			}
+			m.log.Warn("Rule template not found", "rule", r.When, "template", r.Template)
+		}
+	}
+	if len(m.cfg.SegmentTemplates) == 0 {
+		return templateName
+	}
+	role := taxonomy.Role{Seniority: profile.Seniority, Function: profile.Function}
+	if role.Seniority == "" {
+		role = taxonomy.Classify(profile.Title)
+	}
+	for _, key := range []string{role.Segment(), role.Seniority, role.Function} {
+		name, ok := m.cfg.SegmentTemplates[key]
+		if !ok {
+			continue
+		}
+		if _, exists := m.templates[name]; !exists {
+			m.log.Warn("Segment template not found, using default", "segment", key, "template", name)
+			continue
+		}
+		return name
+	}
+	return templateName
+}
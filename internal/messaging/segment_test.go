@@ -0,0 +1,42 @@
+package messaging
+
+import (
+	"testing"
+
+	"subspace/internal/config"
+	"subspace/internal/logger"
+	"subspace/internal/storage"
+)
+
+func TestTemplateFor(t *testing.T) {
+	m := &Messenger{
+		cfg: config.MessagingConfig{TemplateRules: []config.TemplateRule{
+			{When: `company ~= "globex"`, Template: "sales"},
+		}, SegmentTemplates: map[string]string{
+			"vp/engineering": "exec_engineering",
+			"vp":             "exec",
+			"sales":          "sales",
+			"c_level":        "missing",
+		}},
+		templates: map[string]string{"follow_up": "", "exec_engineering": "", "exec": "", "sales": ""},
+		log:       logger.NewContext("messaging"),
+	}
+	tests := map[string]string{
+		"VP Engineering":          "exec_engineering",
+		"Vice President, Product": "exec",
+		"VP Sales":                "exec", // seniority beats function
+		"Account Executive":       "sales",
+		"CEO":                     "follow_up", // segment template doesn't exist
+		"Software Engineer":       "follow_up",
+	}
+	for title, want := range tests {
+		if got := m.TemplateFor(&storage.Profile{Title: title}, "follow_up"); got != want {
+			t.Errorf("TemplateFor(%q) = %q, want %q", title, got, want)
+		}
+	}
+
+	// Rules win over segments
+	if got := m.TemplateFor(&storage.Profile{Title: "VP Engineering", Company: "Globex"}, "follow_up"); got != "sales" {
+		t.Errorf("TemplateFor(VP Engineering at Globex) = %q, want the rule's template", got)
+	}
+}
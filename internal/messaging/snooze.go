@@ -0,0 +1,84 @@
+package messaging
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+SNOOZE REQUESTS
+
+Replies like "please contact me next quarter" ask for a pause rather than a
+conversation. SnoozeRequest spots them so the profile can be snoozed until
+then instead of being followed up early. A reply must both ask to be
+contacted later and say when; anything vaguer is left to the operator.
+*/
+
+var (
+	// laterCue matches asking to be contacted again
+	laterCue = regexp.MustCompile(`\b(contact|reach out|get back|ping|touch base|follow up|circle back|talk|try again|come back|reconnect)\b`)
+
+	nextPeriod = regexp.MustCompile(`\bnext (week|month|quarter|year)\b`)
+	inPeriod   = regexp.MustCompile(`\bin (\d+|a|one|two|three|four|six) (day|week|month)s?\b`)
+	inMonth    = regexp.MustCompile(`\b(in|after|until|from) (january|february|march|april|may|june|july|august|september|october|november|december)\b`)
+)
+
+// counts maps spelled-out counts used in replies to numbers
+var counts = map[string]int{"a": 1, "one": 1, "two": 2, "three": 3, "four": 4, "six": 6}
+
+// SnoozeRequest reports whether a reply asks to be contacted later and,
+// if so, from when
+func SnoozeRequest(text string, now time.Time) (time.Time, bool) {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	if !laterCue.MatchString(text) {
+		return time.Time{}, false
+	}
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	if m := nextPeriod.FindStringSubmatch(text); m != nil {
+		switch m[1] {
+		case "week":
+			return day.AddDate(0, 0, 7), true
+		case "month":
+			return time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, now.Location()), true
+		case "quarter":
+			quarterStart := time.Month((int(now.Month())-1)/3*3 + 1)
+			return time.Date(now.Year(), quarterStart+3, 1, 0, 0, 0, 0, now.Location()), true
+		case "year":
+			return time.Date(now.Year()+1, 1, 1, 0, 0, 0, 0, now.Location()), true
+		}
+	}
+	if m := inPeriod.FindStringSubmatch(text); m != nil {
+		n, ok := counts[m[1]]
+		if !ok {
+			n, _ = strconv.Atoi(m[1])
+		}
+		switch m[2] {
+		case "day":
+			return day.AddDate(0, 0, n), true
+		case "week":
+			return day.AddDate(0, 0, 7*n), true
+		case "month":
+			return day.AddDate(0, n, 0), true
+		}
+	}
+	if m := inMonth.FindStringSubmatch(text); m != nil {
+		for month := time.January; month <= time.December; month++ {
+			if strings.ToLower(month.String()) != m[2] {
+				continue
+			}
+			year := now.Year()
+			if month <= now.Month() {
+				year++
+			}
+			from := time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+			if m[1] == "after" {
+				from = from.AddDate(0, 1, 0)
+			}
+			return from, true
+		}
+	}
+	return time.Time{}, false
+}
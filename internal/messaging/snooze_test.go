@@ -0,0 +1,32 @@
+package messaging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnoozeRequest(t *testing.T) {
+	now := time.Date(2024, 5, 15, 14, 30, 0, 0, time.UTC)
+	date := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		reply string
+		want  time.Time
+	}{
+		{"Appreciate it - could you contact me again next quarter?", date(2024, 7, 1)},
+		{"Busy right now, ping me next week", date(2024, 5, 22)},
+		{"Let's talk next year.", date(2025, 1, 1)},
+		{"Please reach out in two months", date(2024, 7, 15)},
+		{"Can you get back to me in 10 days?", date(2024, 5, 25)},
+		{"Happy to reconnect after September", date(2024, 10, 1)},
+		{"Try again in March", date(2025, 3, 1)},
+		{"Thanks, happy to connect!", time.Time{}},
+		{"I start my new job next month", time.Time{}}, // no request to be contacted
+	}
+	for _, tt := range tests {
+		got, ok := SnoozeRequest(tt.reply, now)
+		if ok != !tt.want.IsZero() || !got.Equal(tt.want) {
+			t.Errorf("SnoozeRequest(%q) = %v, %v; want %v", tt.reply, got, ok, tt.want)
+		}
+	}
+}
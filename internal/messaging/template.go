@@ -0,0 +1,180 @@
+package messaging
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"subspace/internal/campaign"
+	"subspace/internal/config"
+	"subspace/internal/names"
+	"subspace/internal/storage"
+)
+
+// Render fills in a template for a profile. Templates are Go text/template
+// text over the data map from TemplateData, so besides plain variables
+// ("Hi {{.FirstName}}") they may use conditionals
+// ("{{if .SharedGroup}}We're both in {{.SharedGroup}}.{{end}}").
+// Referencing a variable that doesn't exist is an error.
+func (m *Messenger) Render(templateName string, profile *storage.Profile) (string, error) {
+	text, exists := m.templates[templateName]
+	if !exists {
+		return "", fmt.Errorf("template not found: %s", templateName)
+	}
+
+	tmpl, err := template.New(templateName).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template %s: %w", templateName, err)
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, m.TemplateData(templateName, profile)); err != nil {
+		return "", fmt.Errorf("template %s: %w", templateName, err)
+	}
+	return out.String(), nil
+}
+
+// TemplateData returns the variables available to a template for a profile:
+//   - every profile field by its Go name ({{.Location}}, {{.SearchQuery}});
+//     dates are formatted as 2006-01-02 and lists joined with ", "
+//   - the parsed name: {{.FirstName}}, {{.LastName}}, {{.Honorific}} and
+//     {{.Salutation}} ("Dr. Smith")
+//   - {{.Note}}, the latest operator note, e.g. "met at GopherCon"
+//   - enrichment: {{.MutualConnections}}, {{.SharedGroup}}, {{.SharedSchool}}
+//     and the full {{.SharedGroups}} and {{.SharedSchools}}
+//   - the normalized location: {{.City}}, {{.Region}} and {{.Country}},
+//     empty when unknown
+//   - {{.Link}}, the template's configured link
+//   - the sender from messaging.sender, or the account's override:
+//     {{.Sender.Name}}, {{.Sender.Company}}, {{.Sender.Role}},
+//     {{.Sender.Signature}} and {{.Sender.CalendlyLink}}
+//   - {{.Campaign.Name}}, the targeting campaign the profile belongs to,
+//     empty outside every campaign
+//   - aliases from messaging.variables
+func (m *Messenger) TemplateData(templateName string, profile *storage.Profile) map[string]interface{} {
+	data := profileFields(profile)
+
+	name := names.Parse(profile.Name)
+	data["FirstName"] = name.First()
+	data["LastName"] = name.Last()
+	data["Honorific"] = name.Honorific
+	data["Salutation"] = name.Salutation()
+	data["Note"] = profile.LatestNote()
+	data["MutualConnections"] = profile.MutualConnections()
+	data["SharedGroup"] = profile.Shared.Group()
+	data["SharedSchool"] = profile.Shared.School()
+	data["SharedGroups"], data["SharedSchools"] = "", ""
+	if profile.Shared != nil {
+		data["SharedGroups"] = strings.Join(profile.Shared.Groups, ", ")
+		data["SharedSchools"] = strings.Join(profile.Shared.Schools, ", ")
+	}
+	data["City"], data["Region"], data["Country"] = "", "", ""
+	if place := profile.Place; place != nil {
+		data["City"], data["Region"], data["Country"] = place.City, place.Region, place.Country
+	}
+	data["Link"] = m.cfg.Templates[templateName].Link
+	data["Sender"] = senderFields(m.cfg.Sender)
+	data["Campaign"] = map[string]string{"Name": m.campaignOf(profile)}
+
+	for alias, field := range m.cfg.Variables {
+		if value, ok := data[field]; ok {
+			data[alias] = value
+		}
+	}
+	return data
+}
+
+// senderFields maps the sender's template fields to their values
+func senderFields(s config.SenderConfig) map[string]string {
+	return map[string]string{
+		"Name":         s.Name,
+		"Company":      s.Company,
+		"Role":         s.Role,
+		"Signature":    s.Signature,
+		"CalendlyLink": s.CalendlyLink,
+	}
+}
+
+// senderKeys maps the sender's template fields to their config keys
+var senderKeys = map[string]string{
+	"Name":         "name",
+	"Company":      "company",
+	"Role":         "role",
+	"Signature":    "signature",
+	"CalendlyLink": "calendly_link",
+}
+
+var senderRef = regexp.MustCompile(`\.Sender\.(\w+)`)
+
+// CheckSender makes sure every sender field the templates use is set, so a
+// missing signature or booking link fails at startup rather than leaving a
+// gap in messages already sent
+func (m *Messenger) CheckSender() error {
+	names := m.ListTemplates()
+	sort.Strings(names)
+	fields := senderFields(m.cfg.Sender)
+	for _, name := range names {
+		for _, ref := range senderRef.FindAllStringSubmatch(m.templates[name], -1) {
+			key, known := senderKeys[ref[1]]
+			if !known {
+				return fmt.Errorf("template %s uses unknown sender field {{.Sender.%s}}", name, ref[1])
+			}
+			if fields[ref[1]] == "" {
+				return fmt.Errorf("template %s uses {{.Sender.%s}} but messaging.sender.%s is not set", name, ref[1], key)
+			}
+		}
+	}
+	return nil
+}
+
+// campaignOf returns the name of the profile's campaign, or "" if it is in
+// none or no campaigns are set
+func (m *Messenger) campaignOf(profile *storage.Profile) string {
+	if m.campaigns == nil || !m.campaigns.Enabled() {
+		return ""
+	}
+	if name := m.campaigns.Of(profile); name != campaign.Unassigned {
+		return name
+	}
+	return ""
+}
+
+// profileFields flattens the scalar fields of a profile into template
+// variables keyed by field name. Nested structs are left to TemplateData.
+func profileFields(profile *storage.Profile) map[string]interface{} {
+	data := make(map[string]interface{})
+	v := reflect.ValueOf(profile).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		switch x := value.Interface().(type) {
+		case time.Time:
+			data[field.Name] = formatDate(&x)
+		case *time.Time:
+			data[field.Name] = formatDate(x)
+		case []string:
+			data[field.Name] = strings.Join(x, ", ")
+		default:
+			switch value.Kind() {
+			case reflect.String:
+				data[field.Name] = value.String()
+			case reflect.Int, reflect.Int64, reflect.Float64, reflect.Bool:
+				data[field.Name] = value.Interface()
+			}
+		}
+	}
+	return data
+}
+
+// formatDate renders an optional timestamp as a date, or "" if unset
+func formatDate(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
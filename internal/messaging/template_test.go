@@ -0,0 +1,66 @@
+package messaging
+
+import (
+	"strings"
+	"testing"
+
+	"subspace/internal/campaign"
+	"subspace/internal/config"
+	"subspace/internal/logger"
+	"subspace/internal/storage"
+)
+
+func TestRender(t *testing.T) {
+	m := &Messenger{
+		cfg:       config.MessagingConfig{Variables: map[string]string{"City": "Location"}},
+		templates: make(map[string]string),
+		log:       logger.NewContext("messaging"),
+	}
+	p := &storage.Profile{
+		ID:       "p1",
+		Name:     "Dr. Grace Hopper",
+		Location: "Arlington, Virginia",
+		Shared:   &storage.SharedContext{Groups: []string{"COBOL Club", "Navy Alumni"}},
+	}
+
+	tests := map[string]string{
+		"Hi {{.Salutation}}, how is {{.City}}?":                      "Hi Dr. Hopper, how is Arlington, Virginia?",
+		"{{if .SharedGroup}}Both in {{.SharedGroup}}.{{end}}":        "Both in COBOL Club.",
+		"{{if .Company}}At {{.Company}}{{else}}No company{{end}}":    "No company",
+		"Groups: {{.SharedGroups}}; mutuals: {{.MutualConnections}}": "Groups: COBOL Club, Navy Alumni; mutuals: 0",
+	}
+	for text, want := range tests {
+		m.AddTemplate("t", text)
+		got, err := m.Render("t", p)
+		if err != nil || got != want {
+			t.Errorf("Render(%q) = %q, %v; want %q", text, got, err, want)
+		}
+	}
+
+	// Sender and campaign come from config
+	m.cfg.Sender = config.SenderConfig{Name: "Ada Lovelace", Company: "Analytical Engines"}
+	m.SetCampaigns(campaign.New([]config.CampaignConfig{{Name: "navy", Match: `shared_groups ~= "navy"`}}))
+	m.AddTemplate("t", "{{.Sender.Name}} of {{.Sender.Company}}, {{.Campaign.Name}} campaign")
+	if got, err := m.Render("t", p); err != nil || got != "Ada Lovelace of Analytical Engines, navy campaign" {
+		t.Errorf("sender and campaign = %q, %v", got, err)
+	}
+	m.AddTemplate("t", "{{.Sender.Title}}")
+	if _, err := m.Render("t", p); err == nil {
+		t.Error("missing sender variable not reported")
+	}
+
+	// Templates can't use sender fields the operator left empty
+	m.AddTemplate("t", "{{.Sender.Signature}}")
+	if err := m.CheckSender(); err == nil || !strings.Contains(err.Error(), "messaging.sender.signature") {
+		t.Errorf("empty signature not reported: %v", err)
+	}
+	m.cfg.Sender.Signature = "Ada\nAnalytical Engines"
+	if err := m.CheckSender(); err != nil {
+		t.Errorf("CheckSender() = %v", err)
+	}
+
+	m.AddTemplate("t", "Hi {{.Nickname}}")
+	if _, err := m.Render("t", p); err == nil || !strings.Contains(err.Error(), "Nickname") {
+		t.Errorf("missing variable not reported: %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+/*
+METRICS MODULE
+
+A deliberately tiny metrics registry exposing counters, gauges and timers in
+the Prometheus text format, so the PoC can be scraped without pulling in a
+client library.
+
+Metrics are registered once at package init time by the modules that own
+them, e.g.:
+
+	var saveLatency = metrics.NewTimer("storage_save_seconds", "Time spent writing db.json")
+*/
+
+// Counter is a monotonically increasing value
+type Counter struct {
+	mu    sync.Mutex
+	value float64
+}
+
+// Inc increments the counter by one
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add increments the counter by delta
+func (c *Counter) Add(delta float64) {
+	c.mu.Lock()
+	c.value += delta
+	c.mu.Unlock()
+}
+
+// Value returns the current counter value
+func (c *Counter) Value() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.value
+}
+
+// Gauge is a value that can go up and down
+type Gauge struct {
+	mu    sync.Mutex
+	value float64
+}
+
+// Set replaces the gauge value
+func (g *Gauge) Set(value float64) {
+	g.mu.Lock()
+	g.value = value
+	g.mu.Unlock()
+}
+
+// Value returns the current gauge value
+func (g *Gauge) Value() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.value
+}
+
+// Timer records durations as a count, sum and maximum
+type Timer struct {
+	mu    sync.Mutex
+	count int64
+	sum   time.Duration
+	max   time.Duration
+	last  time.Duration
+}
+
+// Observe records a single duration
+func (t *Timer) Observe(d time.Duration) {
+	t.mu.Lock()
+	t.count++
+	t.sum += d
+	t.last = d
+	if d > t.max {
+		t.max = d
+	}
+	t.mu.Unlock()
+}
+
+// Snapshot returns the count, total, maximum and most recent durations
+func (t *Timer) Snapshot() (count int64, sum, max, last time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.count, t.sum, t.max, t.last
+}
+
+// Registry holds named metrics
+type Registry struct {
+	mu       sync.Mutex
+	help     map[string]string
+	counters map[string]*Counter
+	gauges   map[string]*Gauge
+	timers   map[string]*Timer
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		help:     make(map[string]string),
+		counters: make(map[string]*Counter),
+		gauges:   make(map[string]*Gauge),
+		timers:   make(map[string]*Timer),
+	}
+}
+
+// Default is the process-wide registry served by Handler
+var Default = NewRegistry()
+
+// Counter returns the named counter, registering it on first use
+func (r *Registry) Counter(name, help string) *Counter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c, ok := r.counters[name]; ok {
+		return c
+	}
+	c := &Counter{}
+	r.counters[name] = c
+	r.help[name] = help
+	return c
+}
+
+// Gauge returns the named gauge, registering it on first use
+func (r *Registry) Gauge(name, help string) *Gauge {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if g, ok := r.gauges[name]; ok {
+		return g
+	}
+	g := &Gauge{}
+	r.gauges[name] = g
+	r.help[name] = help
+	return g
+}
+
+// Timer returns the named timer, registering it on first use
+func (r *Registry) Timer(name, help string) *Timer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t, ok := r.timers[name]; ok {
+		return t
+	}
+	t := &Timer{}
+	r.timers[name] = t
+	r.help[name] = help
+	return t
+}
+
+// NewCounter registers a counter in the default registry
+func NewCounter(name, help string) *Counter {
+	return Default.Counter(name, help)
+}
+
+// NewGauge registers a gauge in the default registry
+func NewGauge(name, help string) *Gauge {
+	return Default.Gauge(name, help)
+}
+
+// NewTimer registers a timer in the default registry
+func NewTimer(name, help string) *Timer {
+	return Default.Timer(name, help)
+}
+
+// WriteText writes all metrics in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, name := range sortedKeys(r.counters) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, r.help[name], name)
+		fmt.Fprintf(w, "%s %g\n", name, r.counters[name].Value())
+	}
+	for _, name := range sortedKeys(r.gauges) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, r.help[name], name)
+		fmt.Fprintf(w, "%s %g\n", name, r.gauges[name].Value())
+	}
+	for _, name := range sortedKeys(r.timers) {
+		count, sum, max, _ := r.timers[name].Snapshot()
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s summary\n", name, r.help[name], name)
+		fmt.Fprintf(w, "%s_count %d\n", name, count)
+		fmt.Fprintf(w, "%s_sum %g\n", name, sum.Seconds())
+		fmt.Fprintf(w, "# HELP %s_max Maximum of %s\n# TYPE %s_max gauge\n", name, name, name)
+		fmt.Fprintf(w, "%s_max %g\n", name, max.Seconds())
+	}
+	return nil
+}
+
+// Handler serves the default registry over HTTP
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		Default.WriteText(w)
+	})
+}
+
+// extra holds endpoints served next to /metrics, see Handle
+var extra = struct {
+	sync.Mutex
+	routes map[string]http.Handler
+}{routes: make(map[string]http.Handler)}
+
+// Handle registers another endpoint for Serve to expose next to /metrics.
+// Endpoints must be registered before Serve is called.
+func Handle(pattern string, h http.Handler) {
+	extra.Lock()
+	defer extra.Unlock()
+	extra.routes[pattern] = h
+}
+
+// Serve exposes the default registry at /metrics on addr, along with any
+// endpoints registered with Handle. It blocks until the server stops, so
+// callers normally run it in a goroutine.
+func Serve(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	extra.Lock()
+	for pattern, h := range extra.routes {
+		mux.Handle(pattern, h)
+	}
+	extra.Unlock()
+	return http.ListenAndServe(addr, mux)
+}
+
+// sortedKeys returns map keys in a stable order for deterministic output
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistryWriteText(t *testing.T) {
+	r := NewRegistry()
+	saves := r.Timer("storage_save_seconds", "Time spent writing")
+	saves.Observe(100 * time.Millisecond)
+	saves.Observe(300 * time.Millisecond)
+	r.Counter("slow_saves_total", "Slow writes").Add(2)
+	r.Gauge("profiles", "Profiles stored").Set(42)
+
+	// Registering a name again returns the same metric
+	r.Counter("slow_saves_total", "Ignored").Inc()
+	if v := r.Counter("slow_saves_total", "").Value(); v != 3 {
+		t.Errorf("counter = %v, want 3", v)
+	}
+	if count, sum, max, last := saves.Snapshot(); count != 2 || sum != 400*time.Millisecond ||
+		max != 300*time.Millisecond || last != 300*time.Millisecond {
+		t.Errorf("timer = %d, %v, %v, %v", count, sum, max, last)
+	}
+
+	var out strings.Builder
+	if err := r.WriteText(&out); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"# HELP slow_saves_total Slow writes\n# TYPE slow_saves_total counter\nslow_saves_total 3\n",
+		"# TYPE profiles gauge\nprofiles 42\n",
+		"# TYPE storage_save_seconds summary\nstorage_save_seconds_count 2\nstorage_save_seconds_sum 0.4\n",
+		"storage_save_seconds_max 0.3\n",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("output misses %q:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	NewGauge("metrics_test_gauge", "Set by the test").Set(7)
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "metrics_test_gauge 7\n") {
+		t.Errorf("default registry not served:\n%s", rec.Body.String())
+	}
+}
@@ -0,0 +1,69 @@
+package modals
+
+import (
+	"subspace/internal/browser"
+	"subspace/internal/config"
+	"subspace/internal/logger"
+	"subspace/internal/stealth"
+)
+
+/*
+MODALS MODULE
+
+Cookie-consent banners and promotional modals cover the page after it
+loads, so a scripted click lands on the modal instead of the button behind
+it. After each navigation the flows clear them: every configured rule whose
+detect selector is present is closed by clicking its dismiss selector, with
+the same pauses and mouse movement as any other click. Consent is declined
+where the banner offers it.
+
+The rules are config, not code, since the markup of these modals changes
+more often than the flows do.
+*/
+
+// Dismisser closes the configured modals. A nil *Dismisser closes none.
+type Dismisser struct {
+	rules   []config.ModalRule
+	stealth *stealth.Stealth
+	log     *logger.ContextLogger
+}
+
+// New returns a dismisser for the config, or nil if it is disabled
+func New(cfg config.ModalsConfig, s *stealth.Stealth) *Dismisser {
+	if !cfg.Enabled || len(cfg.Rules) == 0 {
+		return nil
+	}
+	return &Dismisser{rules: cfg.Rules, stealth: s, log: logger.NewContext("modals")}
+}
+
+// Clear dismisses the modals showing on the page and returns how many it
+// closed. A modal that can't be closed is logged and left; the flow's own
+// click then reports the failure.
+func (d *Dismisser) Clear(page browser.Controller) int {
+	if d == nil {
+		return 0
+	}
+	closed := 0
+	for _, r := range d.rules {
+		if !page.IsElementPresent(r.Detect) {
+			continue
+		}
+		d.log.Debug("Dismissing modal", "modal", r.Name)
+
+		// Notice the modal and read it before reaching for the button
+		d.stealth.ThinkingPause()
+		d.stealth.MoveTo(0.53, 0.7) // Mock coordinates
+		d.stealth.RandomDelay()
+		d.stealth.ClickAt(0.53, 0.7)
+		if err := page.Click(r.Dismiss); err != nil {
+			d.log.Warn("Failed to dismiss modal", "modal", r.Name, "error", err)
+			continue
+		}
+		d.stealth.ShortPause()
+		closed++
+	}
+	if closed > 0 {
+		d.log.Info("Dismissed modals", "count", closed)
+	}
+	return closed
+}
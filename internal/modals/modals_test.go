@@ -0,0 +1,49 @@
+package modals
+
+import (
+	"testing"
+	"time"
+
+	"subspace/internal/browser"
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/stealth"
+)
+
+// page shows the given elements and records clicks
+type page struct {
+	*browser.Sim
+	present map[string]bool
+	clicked []string
+}
+
+func (p *page) IsElementPresent(selector string) bool { return p.present[selector] }
+
+func (p *page) Click(selector string) error {
+	p.clicked = append(p.clicked, selector)
+	return nil
+}
+
+func TestClear(t *testing.T) {
+	clock.Set(clock.NewFake(time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)))
+	defer clock.Set(clock.Real{})
+
+	cfg := config.Defaults()
+	if New(cfg.Modals, nil) != nil {
+		t.Fatal("disabled config returned a dismisser")
+	}
+	var none *Dismisser
+	if none.Clear(browser.NewSim()) != 0 {
+		t.Error("nil dismisser closed modals")
+	}
+
+	cfg.Modals.Enabled = true
+	d := New(cfg.Modals, stealth.New(cfg.Stealth, nil))
+	p := &page{Sim: browser.NewSim(), present: map[string]bool{"#onetrust-banner-sdk": true}}
+	if n := d.Clear(p); n != 1 {
+		t.Errorf("closed %d modals, want 1", n)
+	}
+	if len(p.clicked) != 1 || p.clicked[0] != "#onetrust-reject-all-handler" {
+		t.Errorf("clicked %v", p.clicked)
+	}
+}
@@ -0,0 +1,290 @@
+package names
+
+import (
+	"strings"
+	"unicode"
+)
+
+/*
+NAME PARSING
+
+Profile names arrive as free text: "Dr. Jane Smith", "SMITH, Jane",
+"Ludwig van Beethoven", "Mary Ann O'Brien, PhD", "Jane Smith 🚀".
+Templates want a first name for the greeting, not the raw string.
+
+RULES (heuristic, in order):
+1. Emoji and other decoration tokens are dropped
+2. "Family, Given" (comma form) is treated as reversed order
+3. Leading honorifics (Dr., Prof., Herr...) and trailing suffixes
+   (Jr., III, PhD...) are split off
+4. A lone name after an honorific is the family name ("Dr. Smith")
+5. Names in Chinese, Japanese or Korean script put the family name first
+   ("李 小龙")
+6. An ALL-CAPS token in an otherwise mixed-case name is the family name
+   ("SMITH Jane", common in French and East Asian formatting), unless it
+   reads as initials ("JD Smith", "J.D. Smith")
+7. The family name starts at the first particle ("van", "de la"...) or
+   is otherwise the last token
+8. Known compound given names ("Mary Ann", "Jean Pierre") are kept whole
+*/
+
+// Name is a parsed personal name
+type Name struct {
+	Honorific string `json:"honorific,omitempty"`
+	Given     string `json:"given,omitempty"`  // All given names, e.g. "Mary Ann Louise"
+	Family    string `json:"family,omitempty"` // Including particles, e.g. "van der Berg"
+	Suffix    string `json:"suffix,omitempty"`
+	Raw       string `json:"raw"`
+}
+
+var honorifics = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "miss": true, "mx": true,
+	"dr": true, "prof": true, "professor": true, "sir": true, "dame": true,
+	"herr": true, "frau": true, "sr": true, "sra": true, "srta": true,
+	"don": true, "doña": true, "m": true, "mme": true, "mlle": true,
+}
+
+var suffixes = map[string]bool{
+	"jr": true, "sr": true, "ii": true, "iii": true, "iv": true,
+	"phd": true, "md": true, "mba": true, "cpa": true, "pmp": true,
+	"esq": true, "msc": true, "bsc": true, "dds": true, "cfa": true,
+}
+
+var particles = map[string]bool{
+	"van": true, "von": true, "der": true, "den": true, "de": true,
+	"del": true, "della": true, "di": true, "da": true, "du": true,
+	"dos": true, "das": true, "la": true, "le": true, "ter": true,
+	"ten": true, "bin": true, "al": true, "el": true, "zu": true,
+}
+
+// compoundGiven lists two-word given names that should not be split
+var compoundGiven = map[string]bool{
+	"mary ann": true, "mary anne": true, "anne marie": true, "ann marie": true,
+	"jean pierre": true, "jean luc": true, "jean paul": true, "jean claude": true,
+	"marie claire": true, "marie louise": true, "juan carlos": true,
+	"juan pablo": true, "maria jose": true, "maría josé": true, "jose luis": true,
+	"josé luis": true, "ana maria": true, "ana maría": true, "karl heinz": true,
+	"hans peter": true, "billy bob": true, "mary kate": true,
+}
+
+// Parse splits a raw display name into its parts
+func Parse(raw string) Name {
+	n := Name{Raw: raw}
+
+	// Reversed "Family, Given" form. Anything after a comma that is only
+	// suffixes ("Smith, PhD") is not a reversal.
+	main := raw
+	var trailing []string
+	if i := strings.Index(raw, ","); i >= 0 {
+		before, after := raw[:i], raw[i+1:]
+		afterTokens := tokens(after)
+		if len(afterTokens) > 0 && !allSuffixes(afterTokens) {
+			familyTokens := tokens(before)
+			n.Honorific, afterTokens = splitHonorific(afterTokens)
+			afterTokens, n.Suffix = splitSuffixes(afterTokens)
+			n.Given = strings.Join(afterTokens, " ")
+			n.Family = strings.Join(familyTokens, " ")
+			if isUpper(n.Family) && !isUpper(n.Given) {
+				n.Family = titleCase(n.Family)
+			}
+			return n
+		}
+		main = before
+		trailing = afterTokens
+	}
+
+	words := tokens(main)
+	n.Honorific, words = splitHonorific(words)
+	words, n.Suffix = splitSuffixes(words)
+	if len(trailing) > 0 {
+		n.Suffix = strings.TrimSpace(n.Suffix + " " + strings.Join(trailing, " "))
+	}
+
+	switch len(words) {
+	case 0:
+		return n
+	case 1:
+		if n.Honorific != "" {
+			n.Family = words[0]
+		} else {
+			n.Given = words[0]
+		}
+		return n
+	}
+
+	// Family name first, as written in East Asian scripts
+	if familyFirst(words[0]) {
+		n.Family = words[0]
+		n.Given = strings.Join(words[1:], " ")
+		return n
+	}
+
+	// ALL-CAPS family name in an otherwise mixed-case name
+	if i := shoutedToken(words); i >= 0 {
+		n.Family = titleCase(words[i])
+		rest := append(append([]string{}, words[:i]...), words[i+1:]...)
+		n.Given = strings.Join(rest, " ")
+		return n
+	}
+
+	// Family name starts at the first particle; a name that opens with a
+	// lowercase particle ("van Gogh") is all family name
+	split := len(words) - 1
+	if particles[words[0]] {
+		split = 0
+	}
+	for i := 1; i < len(words)-1 && split > 0; i++ {
+		if particles[strings.ToLower(words[i])] {
+			split = i
+			break
+		}
+	}
+
+	n.Given = strings.Join(words[:split], " ")
+	n.Family = strings.Join(words[split:], " ")
+	return n
+}
+
+// First returns the name to greet someone by: the first given name, or a
+// known compound given name such as "Mary Ann". Falls back to the raw name.
+func (n Name) First() string {
+	given := strings.Fields(n.Given)
+	switch {
+	case len(given) == 0:
+		return strings.TrimSpace(n.Raw)
+	case len(given) >= 2 && compoundGiven[strings.ToLower(given[0]+" "+given[1])]:
+		return given[0] + " " + given[1]
+	default:
+		return given[0]
+	}
+}
+
+// Last returns the family name, or the first name if there is none
+func (n Name) Last() string {
+	if n.Family == "" {
+		return n.First()
+	}
+	return n.Family
+}
+
+// Salutation returns a formal form of address: "Dr. Smith" when an
+// honorific is known, otherwise the first name
+func (n Name) Salutation() string {
+	if n.Honorific != "" && n.Family != "" {
+		return n.Honorific + " " + n.Family
+	}
+	return n.First()
+}
+
+// tokens splits on whitespace and drops decoration (emoji, symbols)
+func tokens(s string) []string {
+	var out []string
+	for _, f := range strings.Fields(s) {
+		if hasLetter(f) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// splitHonorific removes leading honorifics
+func splitHonorific(words []string) (string, []string) {
+	var found []string
+	for len(words) > 1 && honorifics[normalize(words[0])] {
+		found = append(found, words[0])
+		words = words[1:]
+	}
+	return strings.Join(found, " "), words
+}
+
+// splitSuffixes removes trailing generational and credential suffixes
+func splitSuffixes(words []string) ([]string, string) {
+	end := len(words)
+	for end > 1 && suffixes[normalize(words[end-1])] {
+		end--
+	}
+	return words[:end], strings.Join(words[end:], " ")
+}
+
+// allSuffixes reports whether every token is a suffix
+func allSuffixes(words []string) bool {
+	for _, w := range words {
+		if !suffixes[normalize(w)] {
+			return false
+		}
+	}
+	return true
+}
+
+// shoutedToken returns the index of the single ALL-CAPS token in an
+// otherwise mixed-case name, or -1
+func shoutedToken(words []string) int {
+	found := -1
+	for i, w := range words {
+		if isUpper(w) && len([]rune(w)) > 1 && !isInitials(w) {
+			if found >= 0 {
+				return -1 // Whole name shouted; nothing to learn
+			}
+			found = i
+		}
+	}
+	return found
+}
+
+// isInitials reports whether an ALL-CAPS token is initials rather than a
+// name: dotted ("J.D.") or at most three letters without a vowel ("JD").
+// Short family names like "LI" or "WU" have one.
+func isInitials(word string) bool {
+	if strings.Contains(strings.TrimSuffix(word, "."), ".") {
+		return true
+	}
+	letters := []rune(strings.Trim(word, "."))
+	return len(letters) <= 3 && !strings.ContainsAny(strings.ToLower(string(letters)), "aeiouy")
+}
+
+// familyFirst reports whether a token is written in a script whose names
+// put the family name first: Han, Hiragana, Katakana or Hangul
+func familyFirst(word string) bool {
+	for _, r := range word {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize lowercases and strips punctuation for dictionary lookups
+func normalize(word string) string {
+	return strings.ToLower(strings.Trim(word, ".,"))
+}
+
+func hasLetter(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func isUpper(s string) bool {
+	letters := 0
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			letters++
+			if !unicode.IsUpper(r) {
+				return false
+			}
+		}
+	}
+	return letters > 0
+}
+
+// titleCase turns "SMITH" into "Smith"
+func titleCase(s string) string {
+	runes := []rune(strings.ToLower(s))
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	return string(runes)
+}
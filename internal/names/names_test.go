@@ -0,0 +1,43 @@
+package names
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw                              string
+		honorific, given, family, suffix string
+		first, salutation                string
+	}{
+		{"Jane Smith", "", "Jane", "Smith", "", "Jane", "Jane"},
+		{"Dr. Jane Smith", "Dr.", "Jane", "Smith", "", "Jane", "Dr. Smith"},
+		{"Dr. Smith", "Dr.", "", "Smith", "", "Dr. Smith", "Dr. Smith"},
+		{"SMITH, Jane", "", "Jane", "Smith", "", "Jane", "Jane"},
+		{"Smith, Jane", "", "Jane", "Smith", "", "Jane", "Jane"},
+		{"SMITH Jane", "", "Jane", "Smith", "", "Jane", "Jane"},
+		{"LI Wei", "", "Wei", "Li", "", "Wei", "Wei"},
+		{"JD Smith", "", "JD", "Smith", "", "JD", "JD"},
+		{"J.D. Smith", "", "J.D.", "Smith", "", "J.D.", "J.D."},
+		{"Ludwig van Beethoven", "", "Ludwig", "van Beethoven", "", "Ludwig", "Ludwig"},
+		{"van Gogh", "", "", "van Gogh", "", "van Gogh", "van Gogh"},
+		{"Mary Ann O'Brien, PhD", "", "Mary Ann", "O'Brien", "PhD", "Mary Ann", "Mary Ann"},
+		{"John Smith Jr.", "", "John", "Smith", "Jr.", "John", "John"},
+		{"Jane Smith 🚀", "", "Jane", "Smith", "", "Jane", "Jane"},
+		{"李 小龙", "", "小龙", "李", "", "小龙", "小龙"},
+		{"山田 太郎", "", "太郎", "山田", "", "太郎", "太郎"},
+		{"Cher", "", "Cher", "", "", "Cher", "Cher"},
+		{"", "", "", "", "", "", ""},
+	}
+	for _, tt := range tests {
+		n := Parse(tt.raw)
+		if n.Honorific != tt.honorific || n.Given != tt.given || n.Family != tt.family || n.Suffix != tt.suffix {
+			t.Errorf("Parse(%q) = %+v, want honorific %q, given %q, family %q, suffix %q",
+				tt.raw, n, tt.honorific, tt.given, tt.family, tt.suffix)
+		}
+		if got := n.First(); got != tt.first {
+			t.Errorf("Parse(%q).First() = %q, want %q", tt.raw, got, tt.first)
+		}
+		if got := n.Salutation(); got != tt.salutation {
+			t.Errorf("Parse(%q).Salutation() = %q, want %q", tt.raw, got, tt.salutation)
+		}
+	}
+}
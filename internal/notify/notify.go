@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+
+	"subspace/internal/config"
+	"subspace/internal/logger"
+)
+
+/*
+NOTIFY MODULE
+
+Desktop notifications for events an operator at the workstation should see
+without watching the console: a security checkpoint during login, and the
+summary at the end of a run. They are sent with the notifier of the OS,
+so nothing needs installing:
+
+- macOS: osascript ("display notification")
+- Windows: a PowerShell toast
+- Linux and BSDs: notify-send (libnotify)
+
+Notifications are only sent when running interactively (stdin is a
+terminal), never from cron or a service.
+*/
+
+// Events that can be notified
+const (
+	Checkpoint   = "checkpoint"
+	DailySummary = "daily_summary"
+)
+
+// Events lists the events notifications can be enabled for
+var Events = []string{Checkpoint, DailySummary}
+
+// Notifier sends the enabled events to the desktop. A nil *Notifier
+// sends nothing.
+type Notifier struct {
+	events []string
+	send   func(title, body string) error
+	log    *logger.ContextLogger
+}
+
+// New returns a notifier for the config, or nil if desktop notifications
+// are off or the process isn't interactive
+func New(cfg config.NotificationsConfig) *Notifier {
+	if !cfg.Desktop || !interactive() {
+		return nil
+	}
+	return &Notifier{events: cfg.Events, send: desktop, log: logger.NewContext("notify")}
+}
+
+// Notify shows a notification if the event is enabled. Failures are only
+// logged; a notification never stops a run.
+func (n *Notifier) Notify(event, title, body string) {
+	if n == nil || !slices.Contains(n.events, event) {
+		return
+	}
+	if err := n.send(title, body); err != nil {
+		n.log.Warn("Desktop notification failed", "event", event, "error", err)
+	}
+}
+
+// interactive reports whether stdin is a terminal
+func interactive() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// desktop shows a notification with the notifier of the OS
+func desktop(title, body string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsToast(title, body))
+	default:
+		cmd = exec.Command("notify-send", "--app-name=subspace", title, body)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w (%s)", cmd.Path, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// windowsToast returns a PowerShell script showing a toast notification
+func windowsToast(title, body string) string {
+	quote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+	return strings.Join([]string{
+		"[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null",
+		"$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)",
+		"$text = $xml.GetElementsByTagName('text')",
+		"$text.Item(0).AppendChild($xml.CreateTextNode(" + quote(title) + ")) | Out-Null",
+		"$text.Item(1).AppendChild($xml.CreateTextNode(" + quote(body) + ")) | Out-Null",
+		"[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('subspace').Show([Windows.UI.Notifications.ToastNotification]::new($xml))",
+	}, "; ")
+}
@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNotifyOnlyEnabledEvents(t *testing.T) {
+	var sent []string
+	n := &Notifier{events: []string{Checkpoint}, send: func(title, body string) error {
+		sent = append(sent, title+": "+body)
+		return nil
+	}}
+	n.Notify(Checkpoint, "Subspace", "Security checkpoint")
+	n.Notify(DailySummary, "Subspace", "Run complete")
+	if len(sent) != 1 || sent[0] != "Subspace: Security checkpoint" {
+		t.Errorf("sent = %v", sent)
+	}
+
+	var none *Notifier
+	none.Notify(Checkpoint, "Subspace", "nothing happens")
+}
+
+func TestQuoting(t *testing.T) {
+	if got := appleScriptString(`say "hi" \ bye`); got != `"say \"hi\" \\ bye"` {
+		t.Errorf("appleScriptString = %s", got)
+	}
+	if script := windowsToast("It's", "done"); !strings.Contains(script, "'It''s'") {
+		t.Errorf("windowsToast didn't escape quotes: %s", script)
+	}
+}
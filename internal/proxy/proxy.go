@@ -0,0 +1,196 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/logger"
+	"subspace/internal/metrics"
+)
+
+/*
+PROXY HEALTH MODULE
+
+Checks a proxy by fetching an IP echo service through it (proxy_check.url),
+which verifies in one request that:
+- the proxy accepts connections (and its credentials)
+- it answers within proxy_check.max_latency_ms
+- its exit IP geolocates to the account's exit_country
+
+Before a run the account's proxy is checked, then its backup_proxies in
+their configured order, and the first healthy one is used. During the run
+the proxy in use is rechecked between steps every interval_minutes and, if
+it fails, the run fails over the same way. Failover only ever picks from
+the account's own list, never a random proxy; when none is healthy the run
+stops rather than exposing the account through something else.
+*/
+
+var (
+	checkLatency = metrics.NewTimer("proxy_check_seconds", "Latency of proxy health checks")
+	checkFailed  = metrics.NewCounter("proxy_check_failures_total", "Failed proxy health checks")
+	failovers    = metrics.NewCounter("proxy_failovers_total", "Switches to another proxy after a failed check")
+)
+
+// Result is the outcome of one health check
+type Result struct {
+	Proxy   string        `json:"proxy"` // Host only, never credentials
+	Latency time.Duration `json:"latency"`
+	IP      string        `json:"ip,omitempty"`
+	Country string        `json:"country,omitempty"`
+	Err     error         `json:"-"`
+}
+
+// Healthy reports whether the proxy passed
+func (r Result) Healthy() bool {
+	return r.Err == nil
+}
+
+// Checker tracks the proxy in use and fails over between an account's
+// proxies
+type Checker struct {
+	cfg     config.ProxyCheckConfig
+	proxies []string
+	country string
+	current int
+	checked time.Time
+	session *Sessions // Applied to each proxy checked, may be nil
+	log     *logger.ContextLogger
+}
+
+// New returns a checker for the proxies, in failover order, whose exit IPs
+// must be in country (empty accepts any). It returns nil if checking is
+// disabled or there is no proxy.
+func New(cfg config.ProxyCheckConfig, proxies []string, country string) *Checker {
+	if !cfg.Enabled || len(proxies) == 0 {
+		return nil
+	}
+	return &Checker{cfg: cfg, proxies: proxies, country: country, log: logger.NewContext("proxy")}
+}
+
+// ForConfig returns the checker for the active account's proxies, or for
+// app.proxy without an account. Proxies are checked with the session
+// embedded, as the browser uses them.
+func ForConfig(cfg *config.Config, sessions *Sessions) *Checker {
+	var c *Checker
+	if a, ok := cfg.ActiveAccount(); ok {
+		c = New(cfg.ProxyCheck, a.Proxies(), a.ExitCountry)
+	} else if cfg.App.Proxy != "" {
+		c = New(cfg.ProxyCheck, []string{cfg.App.Proxy}, "")
+	}
+	if c != nil {
+		c.session = sessions
+	}
+	return c
+}
+
+// Current returns the proxy in use
+func (c *Checker) Current() string {
+	return c.proxies[c.current]
+}
+
+// Check fetches the echo service through a proxy
+func (c *Checker) Check(proxy string) Result {
+	r := Result{Proxy: config.ProxyHost(proxy)}
+	u, err := url.Parse(c.session.Apply(proxy))
+	if err != nil {
+		r.Err = fmt.Errorf("invalid proxy URL")
+		return r
+	}
+	client := &http.Client{
+		Timeout:   time.Duration(c.cfg.TimeoutSeconds) * time.Second,
+		Transport: &http.Transport{Proxy: http.ProxyURL(u)},
+	}
+
+	start := time.Now()
+	r.Err = c.fetch(client, &r)
+	r.Latency = time.Since(start)
+	checkLatency.Observe(r.Latency)
+
+	if r.Err == nil && c.cfg.MaxLatencyMs > 0 && r.Latency > time.Duration(c.cfg.MaxLatencyMs)*time.Millisecond {
+		r.Err = fmt.Errorf("latency %s over %dms", r.Latency.Round(time.Millisecond), c.cfg.MaxLatencyMs)
+	}
+	if r.Err == nil && c.country != "" && !strings.EqualFold(r.Country, c.country) {
+		r.Err = fmt.Errorf("exit IP %s is in %q, want %s", r.IP, r.Country, c.country)
+	}
+	if r.Err != nil {
+		checkFailed.Inc()
+	}
+	return r
+}
+
+// fetch reads the exit IP and country from the echo service
+func (c *Checker) fetch(client *http.Client, r *Result) error {
+	resp, err := client.Get(c.cfg.URL)
+	if err != nil {
+		return fmt.Errorf("unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("echo service answered %s", resp.Status)
+	}
+	var echo struct {
+		IP      string `json:"ip"`
+		Country string `json:"country"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&echo); err != nil {
+		return fmt.Errorf("failed to parse echo response: %w", err)
+	}
+	r.IP, r.Country = echo.IP, echo.Country
+	return nil
+}
+
+// Select checks the proxies from the first, in order, and switches to the
+// first healthy one. It returns every check made.
+func (c *Checker) Select() (string, []Result, error) {
+	return c.selectFrom(0)
+}
+
+// Due reports whether the proxy in use should be rechecked
+func (c *Checker) Due() bool {
+	return clock.Since(c.checked) >= time.Duration(c.cfg.IntervalMinutes)*time.Minute
+}
+
+// Recheck checks the proxy in use. If it fails, the checker fails over to
+// the next healthy proxy in order, wrapping around, and reports the switch.
+func (c *Checker) Recheck() (switched bool, results []Result, err error) {
+	r := c.Check(c.Current())
+	c.checked = clock.Now()
+	if r.Healthy() {
+		return false, []Result{r}, nil
+	}
+	c.log.Warn("Proxy unhealthy", "proxy", r.Proxy, "error", r.Err)
+
+	from := c.current
+	_, results, err = c.selectFrom(from + 1)
+	results = append([]Result{r}, results...)
+	return err == nil && c.current != from, results, err
+}
+
+// selectFrom checks every proxy once, starting at index start
+func (c *Checker) selectFrom(start int) (string, []Result, error) {
+	var results []Result
+	for i := 0; i < len(c.proxies); i++ {
+		idx := (start + i) % len(c.proxies)
+		r := c.Check(c.proxies[idx])
+		results = append(results, r)
+		if !r.Healthy() {
+			c.log.Warn("Proxy check failed", "proxy", r.Proxy, "error", r.Err)
+			continue
+		}
+		c.checked = clock.Now()
+		if idx != c.current {
+			failovers.Inc()
+			c.log.Warn("Failing over to backup proxy", "from", config.ProxyHost(c.Current()), "to", r.Proxy)
+		}
+		c.current = idx
+		c.log.Info("Proxy healthy", "proxy", r.Proxy, "latency_ms", r.Latency.Milliseconds(), "country", r.Country)
+		return c.Current(), results, nil
+	}
+	return "", results, fmt.Errorf("no healthy proxy among %d configured", len(c.proxies))
+}
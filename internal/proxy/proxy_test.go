@@ -0,0 +1,51 @@
+package proxy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"subspace/internal/config"
+)
+
+// echoProxy is an HTTP proxy that answers every request itself, as the IP
+// echo service reached through it
+func echoProxy(t *testing.T, country string) string {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"ip": "203.0.113.7", "country": %q}`, country)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestFailover(t *testing.T) {
+	cfg := config.ProxyCheckConfig{Enabled: true, URL: "http://echo.test/json", TimeoutSeconds: 5, IntervalMinutes: 1}
+	down := "http://127.0.0.1:1" // Nothing listens there
+	wrongCountry := echoProxy(t, "US")
+	healthy := echoProxy(t, "DE")
+
+	c := New(cfg, []string{down, wrongCountry, healthy}, "DE")
+	got, results, err := c.Select()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != healthy || len(results) != 3 {
+		t.Errorf("selected %s after %d checks, want the third proxy after 3", got, len(results))
+	}
+	if results[0].Healthy() || results[1].Healthy() || !results[2].Healthy() || results[2].IP != "203.0.113.7" {
+		t.Errorf("results %+v", results)
+	}
+
+	// The proxy in use stays while it is healthy
+	if switched, _, err := c.Recheck(); switched || err != nil {
+		t.Errorf("recheck of a healthy proxy: switched %v, err %v", switched, err)
+	}
+
+	if _, _, err := New(cfg, []string{down, wrongCountry}, "DE").Select(); err == nil {
+		t.Error("selected a proxy with none healthy")
+	}
+	if New(config.ProxyCheckConfig{}, []string{healthy}, "") != nil {
+		t.Error("disabled checker was created")
+	}
+}
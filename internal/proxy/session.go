@@ -0,0 +1,142 @@
+package proxy
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/logger"
+)
+
+// sessionsFile keeps rotating sessions across runs, inside the data
+// directory
+const sessionsFile = "proxy_sessions.json"
+
+// Session is a proxy session: the exit IP stays while its ID does
+type Session struct {
+	ID        string    `json:"id"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// Sessions embeds the session ID of proxy_session.policy in proxy
+// usernames, so a residential proxy keeps its exit IP instead of churning
+// mid-run
+type Sessions struct {
+	cfg     config.ProxySessionConfig
+	path    string
+	account string
+	current Session
+	log     *logger.ContextLogger
+}
+
+// NewSessions starts or resumes the proxy session of an account. It returns
+// nil with policy off.
+func NewSessions(cfg config.ProxySessionConfig, dataDir, account string) (*Sessions, error) {
+	if cfg.Policy == "off" || cfg.Policy == "" {
+		return nil, nil
+	}
+	s := &Sessions{cfg: cfg, path: filepath.Join(dataDir, sessionsFile), account: account, log: logger.NewContext("proxy")}
+
+	if cfg.Policy == "rotate" {
+		saved, err := s.load()
+		if err != nil {
+			return nil, err
+		}
+		if session, ok := saved[account]; ok {
+			s.current = session
+			if !s.Due() {
+				s.log.Info("Resuming proxy session", "started", session.StartedAt.Format(time.RFC3339))
+				return s, nil
+			}
+		}
+	}
+	return s, s.Rotate()
+}
+
+// ID returns the current session ID
+func (s *Sessions) ID() string {
+	return s.current.ID
+}
+
+// Due reports whether a rotating session has lasted rotate_hours
+func (s *Sessions) Due() bool {
+	if s == nil || s.cfg.Policy != "rotate" {
+		return false
+	}
+	return clock.Since(s.current.StartedAt) >= time.Duration(s.cfg.RotateHours)*time.Hour
+}
+
+// Rotate starts a new session, and so a new exit IP
+func (s *Sessions) Rotate() error {
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Errorf("failed to generate proxy session ID: %w", err)
+	}
+	s.current = Session{ID: hex.EncodeToString(id), StartedAt: clock.Now()}
+	s.log.Info("Starting proxy session", "policy", s.cfg.Policy)
+	if s.cfg.Policy != "rotate" {
+		return nil
+	}
+
+	saved, err := s.load()
+	if err != nil {
+		return err
+	}
+	saved[s.account] = s.current
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode proxy sessions: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save proxy sessions: %w", err)
+	}
+	return nil
+}
+
+// Apply returns the proxy URL with the session embedded in its username.
+// Proxies without a username, and all proxies when s is nil, are returned
+// unchanged.
+func (s *Sessions) Apply(proxy string) string {
+	if s == nil {
+		return proxy
+	}
+	u, err := url.Parse(proxy)
+	if err != nil || u.User == nil {
+		return proxy
+	}
+	user := strings.NewReplacer("{user}", u.User.Username(), "{session}", s.current.ID).Replace(s.cfg.UsernameTemplate)
+	if password, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(user, password)
+	} else {
+		u.User = url.User(user)
+	}
+	return u.String()
+}
+
+// load reads the saved rotating sessions by account
+func (s *Sessions) load() (map[string]Session, error) {
+	saved := make(map[string]Session)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return saved, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read proxy sessions: %w", err)
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse proxy sessions %s: %w", s.path, err)
+	}
+	return saved, nil
+}
@@ -0,0 +1,52 @@
+package proxy
+
+import (
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+)
+
+func TestSessions(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC))
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	cfg := config.ProxySessionConfig{Policy: "rotate", RotateHours: 6, UsernameTemplate: "{user}-session-{session}"}
+	dir := t.TempDir()
+	s, err := NewSessions(cfg, dir, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := s.ID()
+	if got, want := s.Apply("http://alice:pw@gw.example:8000"), "http://alice-session-"+id+":pw@gw.example:8000"; got != want {
+		t.Errorf("Apply = %s, want %s", got, want)
+	}
+	if got := s.Apply("http://gw.example:8000"); got != "http://gw.example:8000" {
+		t.Errorf("proxy without credentials changed to %s", got)
+	}
+
+	// A restart within the window keeps the session, and so the exit IP
+	fake.Advance(5 * time.Hour)
+	if s, err = NewSessions(cfg, dir, "alice"); err != nil || s.ID() != id || s.Due() {
+		t.Errorf("resumed session %v (due %v), want %s; err %v", s.ID(), s.Due(), id, err)
+	}
+	fake.Advance(time.Hour)
+	if !s.Due() {
+		t.Fatal("session not due after rotate_hours")
+	}
+	if s, err = NewSessions(cfg, dir, "alice"); err != nil || s.ID() == id {
+		t.Errorf("expired session resumed: %v, err %v", s.ID(), err)
+	}
+
+	// Sticky sessions never rotate on their own and are not kept
+	sticky, _ := NewSessions(config.ProxySessionConfig{Policy: "sticky", UsernameTemplate: "{session}"}, dir, "bob")
+	fake.Advance(1000 * time.Hour)
+	if sticky.Due() {
+		t.Error("sticky session due")
+	}
+	if s, _ := NewSessions(config.ProxySessionConfig{Policy: "off"}, dir, "alice"); s != nil || s.Apply("x") != "x" {
+		t.Error("policy off created sessions")
+	}
+}
@@ -0,0 +1,166 @@
+package queue
+
+import (
+	"fmt"
+	"time"
+
+	"subspace/internal/calendar"
+	"subspace/internal/config"
+	"subspace/internal/connect"
+	"subspace/internal/messaging"
+	"subspace/internal/schedule"
+	"subspace/internal/storage"
+	"subspace/internal/worksession"
+)
+
+/*
+QUEUE MODULE
+
+Pending work isn't stored as jobs but follows from the profiles:
+
+  - connection: an approved profile, or a discovered one when review isn't
+    required, that the targeting settings let through
+  - message: an accepted profile that hasn't been messaged yet
+
+List shows these jobs in the order runs take them, with when each is
+projected to run (see schedule.Project). The operator changes a job
+through its profile before it runs:
+
+  - Prioritize sets the profile's queue priority; higher goes first, ahead
+    of targeting boosts for connections and acceptance order for messages
+  - Defer snoozes the profile, which holds the job until then
+  - Cancel skips a connection request, or moves an accepted profile to
+    cooled down so it gets no first message
+
+Messages are projected without recipient hours, so a job for someone whose
+night it is runs later than listed.
+*/
+
+// Actions of queued jobs
+const (
+	ActionConnection = "connection"
+	ActionMessage    = "message"
+)
+
+// Item is one pending job
+type Item struct {
+	Position  int        `json:"position"` // Among the jobs of its action, 1 runs next
+	Action    string     `json:"action"`
+	ProfileID string     `json:"profile_id"`
+	Name      string     `json:"name"`
+	Priority  int        `json:"priority,omitempty"`
+	At        *time.Time `json:"at,omitempty"` // Projected; nil if past the horizon
+}
+
+// Pending returns the profiles waiting for action, in the order runs take
+// them
+func Pending(cfg *config.Config, db storage.Backend, action string, now time.Time) []*storage.Profile {
+	if action == ActionConnection {
+		return connect.Eligible(db.ConnectCandidates(cfg.Review.RequireApproval), cfg.Targeting)
+	}
+	var pending []*storage.Profile
+	for _, p := range db.GetProfilesByState(storage.StateAccepted) {
+		if p.InTags(cfg.Targeting.Tags) && len(db.GetMessagesByProfile(p.ID)) == 0 && !p.Snoozed(now) {
+			pending = append(pending, p)
+		}
+	}
+	messaging.SortQueue(pending)
+	return pending
+}
+
+// List returns the pending jobs of the enabled modules, connections first,
+// with the times projected over horizon
+func List(cfg *config.Config, db storage.Backend, cal *calendar.Calendar, ws *worksession.Session, now time.Time, horizon time.Duration) []Item {
+	items := make([]Item, 0)
+	for _, step := range []struct {
+		action string
+		on     bool
+	}{{ActionConnection, cfg.Modules.Connect}, {ActionMessage, cfg.Modules.Messaging}} {
+		if !step.on {
+			continue
+		}
+		pending := Pending(cfg, db, step.action, now)
+		slots := schedule.Project(cfg, db, cal, ws, step.action, len(pending), now, horizon)
+		for i, p := range pending {
+			item := Item{Position: i + 1, Action: step.action, ProfileID: p.ID, Name: p.Name, Priority: p.Priority}
+			if i < len(slots) {
+				at := slots[i]
+				item.At = &at
+			}
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ActionOf returns the job the profile is queued for, or "" if none. The
+// targeting settings aren't applied, so a profile they filter out still
+// counts as queued and can be changed.
+func ActionOf(cfg *config.Config, db storage.Backend, p *storage.Profile) string {
+	switch {
+	case p.State == storage.StateApproved || p.State == storage.StateDiscovered && !cfg.Review.RequireApproval:
+		return ActionConnection
+	case p.State == storage.StateAccepted && len(db.GetMessagesByProfile(p.ID)) == 0:
+		return ActionMessage
+	}
+	return ""
+}
+
+// queued returns the profile and its job, or an error if it has none
+func queued(cfg *config.Config, db storage.Backend, profileID string) (*storage.Profile, string, error) {
+	p, err := db.GetProfile(profileID)
+	if err != nil {
+		return nil, "", err
+	}
+	action := ActionOf(cfg, db, p)
+	if action == "" {
+		return nil, "", fmt.Errorf("%s (%s) has no pending connection request or message", p.ID, p.State)
+	}
+	return p, action, nil
+}
+
+// Prioritize sets the queue priority of a pending job's profile
+func Prioritize(cfg *config.Config, db storage.Backend, profileID string, priority int) (*storage.Profile, error) {
+	p, _, err := queued(cfg, db, profileID)
+	if err != nil {
+		return nil, err
+	}
+	p.Priority = priority
+	if err := db.SaveProfile(p); err != nil {
+		return nil, fmt.Errorf("failed to save profile: %w", err)
+	}
+	return p, nil
+}
+
+// Defer holds a pending job until the given time by snoozing its profile
+func Defer(cfg *config.Config, db storage.Backend, profileID string, until time.Time, reason string) (*storage.Profile, error) {
+	p, _, err := queued(cfg, db, profileID)
+	if err != nil {
+		return nil, err
+	}
+	p.Snooze(until, reason)
+	if err := db.SaveProfile(p); err != nil {
+		return nil, fmt.Errorf("failed to save profile: %w", err)
+	}
+	return p, nil
+}
+
+// Cancel drops a pending job and returns the profile and the action it
+// was queued for
+func Cancel(cfg *config.Config, db storage.Backend, profileID string, now time.Time) (*storage.Profile, string, error) {
+	p, action, err := queued(cfg, db, profileID)
+	if err != nil {
+		return nil, "", err
+	}
+	to := storage.StateSkipped
+	if action == ActionMessage {
+		to = storage.StateCooledDown
+	}
+	if err := p.Transition(to, now); err != nil {
+		return nil, "", err
+	}
+	if err := db.SaveProfile(p); err != nil {
+		return nil, "", fmt.Errorf("failed to save profile: %w", err)
+	}
+	return p, action, nil
+}
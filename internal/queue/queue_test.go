@@ -0,0 +1,78 @@
+package queue
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"subspace/internal/calendar"
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/storage"
+)
+
+func TestQueue(t *testing.T) {
+	// Friday 10:30
+	now := time.Date(2024, 5, 3, 10, 30, 0, 0, time.UTC)
+	clock.Set(clock.NewFake(now))
+	defer clock.Set(clock.Real{})
+
+	cfg := config.Defaults()
+	db, err := storage.New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cal, err := calendar.New(cfg.Stealth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accepted := now.Add(-time.Hour)
+	for i, p := range []*storage.Profile{
+		{ID: "d1", State: storage.StateDiscovered},
+		{ID: "d2", State: storage.StateDiscovered},
+		{ID: "a1", State: storage.StateApproved},
+		{ID: "m1", State: storage.StateAccepted, AcceptedAt: &accepted},
+		{ID: "r1", State: storage.StateRequested},
+	} {
+		p.DiscoveredAt = now.Add(time.Duration(i-10) * time.Hour)
+		if err := db.SaveProfile(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	order := func() string {
+		var ids []string
+		for _, item := range List(cfg, db, cal, nil, now, 24*time.Hour) {
+			ids = append(ids, item.ProfileID)
+		}
+		return strings.Join(ids, " ")
+	}
+
+	// Approved first, then discovered oldest first, then messages
+	if got := order(); got != "a1 d1 d2 m1" {
+		t.Fatalf("queue = %q", got)
+	}
+	if _, err := Prioritize(cfg, db, "d2", 5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Defer(cfg, db, "a1", now.Add(48*time.Hour), "after the event"); err != nil {
+		t.Fatal(err)
+	}
+	if got := order(); got != "d2 d1 m1" {
+		t.Errorf("after prioritize and defer, queue = %q", got)
+	}
+
+	p, action, err := Cancel(cfg, db, "m1", now)
+	if err != nil || action != ActionMessage || p.State != storage.StateCooledDown {
+		t.Fatalf("Cancel(m1) = %v, %q, %v", p.State, action, err)
+	}
+	if p, _, err := Cancel(cfg, db, "d1", now); err != nil || p.State != storage.StateSkipped {
+		t.Fatalf("Cancel(d1) = %v, %v", p, err)
+	}
+	if got := order(); got != "d2" {
+		t.Errorf("after cancelling, queue = %q", got)
+	}
+	if _, _, err := Cancel(cfg, db, "r1", now); err == nil {
+		t.Error("cancelled a profile with nothing queued")
+	}
+}
@@ -0,0 +1,150 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/storage"
+)
+
+/*
+RATE LIMIT MODULE
+
+Limits are enforced over sliding windows of the action log rather than
+calendar days: "50 connections per day" means at most 50 in any 24 hours,
+so a burst at the end of one day can't be followed by another at the start
+of the next. A sliding 24h window also bounds every calendar day of 24
+hours or less, so the "today" counts shown by stats stay within the
+configured limit, except on the 25-hour day when clocks go back, where the
+first and last hour together can hold one window's worth more.
+
+Only successful actions count, matching Storage.GetActionCountSince.
+
+On top of the per-action windows, every profile has a touch budget: no more
+than max_touches_per_profile connection requests and messages, replies
+included, in any touch_window_days. Campaigns and templates can't raise it.
+*/
+
+// Window limits one action type to Max successes in any Period
+type Window struct {
+	Action string        `json:"action"`
+	Period time.Duration `json:"period"`
+	Max    int           `json:"max"`
+}
+
+// Windows returns the sliding windows implied by the configured limits
+func Windows(cfg config.LimitsConfig) []Window {
+	return []Window{
+		{Action: "connection", Period: 24 * time.Hour, Max: cfg.ConnectionsPerDay},
+		{Action: "connection", Period: time.Hour, Max: cfg.ConnectionsPerHour},
+		{Action: "message", Period: 24 * time.Hour, Max: cfg.MessagesPerDay},
+		{Action: "search", Period: 24 * time.Hour, Max: cfg.SearchesPerDay},
+	}
+}
+
+// ErrProfileTouches is returned for a profile that has had all the touches
+// the guardrail allows
+var ErrProfileTouches = errors.New("profile touch limit reached")
+
+// Limiter answers "may I do this now?" from the stored action log
+type Limiter struct {
+	storage storage.Backend
+	windows []Window
+	touches Window // Per profile; Action is unused and Max 0 means unlimited
+}
+
+// New creates a limiter enforcing the configured limits
+func New(db storage.Backend, cfg config.LimitsConfig) *Limiter {
+	return &Limiter{
+		storage: db,
+		windows: Windows(cfg),
+		touches: Window{Period: time.Duration(cfg.TouchWindowDays) * 24 * time.Hour, Max: cfg.MaxTouchesPerProfile},
+	}
+}
+
+// CheckProfile returns an error wrapping ErrProfileTouches if one more
+// connection request or message would exceed the profile's touch budget
+func (l *Limiter) CheckProfile(profileID string) error {
+	if l.touches.Max <= 0 {
+		return nil
+	}
+	n := l.storage.GetProfileTouchesSince(profileID, clock.Now().Add(-l.touches.Period))
+	if n >= l.touches.Max {
+		return fmt.Errorf("%w: %s had %d touches in the last %d days (max %d)",
+			ErrProfileTouches, profileID, n, int(l.touches.Period.Hours()/24), l.touches.Max)
+	}
+	return nil
+}
+
+// Remaining returns how many more times action may succeed right now
+// without exceeding any of its windows. Actions without a window are
+// unlimited and return -1.
+func (l *Limiter) Remaining(action string) int {
+	now := clock.Now()
+	remaining := -1
+	for _, w := range l.windows {
+		if w.Action != action {
+			continue
+		}
+		left := w.Max - l.storage.GetActionCountSince(action, now.Add(-w.Period))
+		if left < 0 {
+			left = 0
+		}
+		if remaining < 0 || left < remaining {
+			remaining = left
+		}
+	}
+	return remaining
+}
+
+// Allow reports whether action may be performed now
+func (l *Limiter) Allow(action string) bool {
+	return l.Remaining(action) != 0
+}
+
+// Violation is a window in which more actions succeeded than allowed
+type Violation struct {
+	Action string        `json:"action"`
+	Period time.Duration `json:"period"`
+	Start  time.Time     `json:"start"` // First action in the offending window
+	Count  int           `json:"count"`
+	Max    int           `json:"max"`
+}
+
+// Check replays an action log against the windows and returns every window
+// in which more actions succeeded than allowed. It is independent of the
+// Limiter so it can verify that the Limiter was actually obeyed.
+func Check(logs []storage.ActionLog, windows []Window) []Violation {
+	byAction := make(map[string][]time.Time)
+	for _, l := range logs {
+		if l.Success {
+			byAction[l.Action] = append(byAction[l.Action], l.Timestamp)
+		}
+	}
+	for _, times := range byAction {
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	}
+
+	var violations []Violation
+	for _, w := range windows {
+		times := byAction[w.Action]
+		// The limiter counts actions strictly after now-period
+		lo, reported := 0, -1
+		for hi, t := range times {
+			for !times[lo].After(t.Add(-w.Period)) {
+				lo++
+			}
+			if count := hi - lo + 1; count > w.Max && lo != reported {
+				violations = append(violations, Violation{w.Action, w.Period, times[lo], count, w.Max})
+				reported = lo
+			}
+		}
+	}
+
+	sort.Slice(violations, func(i, j int) bool { return violations[i].Start.Before(violations[j].Start) })
+	return violations
+}
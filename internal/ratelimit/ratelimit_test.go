@@ -0,0 +1,197 @@
+package ratelimit
+
+import (
+	"errors"
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/storage"
+)
+
+// Small limits so that random sequences hit them constantly
+var testLimits = config.LimitsConfig{
+	ConnectionsPerDay:  12,
+	ConnectionsPerHour: 3,
+	MessagesPerDay:     5,
+	SearchesPerDay:     4,
+}
+
+var testActions = []string{"connection", "connection", "connection", "message", "message", "search", "view"}
+
+var sources = []string{storage.SourceAuto, storage.SourceAuto, storage.SourceManual, storage.SourceAPI}
+
+// randomStep returns how far to advance the fake clock before the next
+// attempt: mostly seconds and minutes, sometimes hours, rarely a day
+func randomStep(rng *rand.Rand) time.Duration {
+	switch r := rng.Intn(100); {
+	case r < 40:
+		return time.Duration(rng.Intn(120)) * time.Second
+	case r < 80:
+		return time.Duration(rng.Intn(60)) * time.Minute
+	case r < 97:
+		return time.Duration(rng.Intn(6*60)) * time.Minute
+	default:
+		return time.Duration(rng.Intn(48)) * time.Hour
+	}
+}
+
+// TestLimiterProperties drives the limiter with randomized action sequences
+// under a fake clock and checks that no sliding window is ever exceeded,
+// that remaining budgets stay in range and that counts only ever grow
+func TestLimiterProperties(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		seed := seed
+		t.Run("", func(t *testing.T) {
+			rng := rand.New(rand.NewSource(seed))
+			start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+			fake := clock.NewFake(start)
+			clock.Set(fake)
+			defer clock.Set(clock.Real{})
+
+			db, err := storage.New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			limiter := New(db, testLimits)
+			windows := Windows(testLimits)
+
+			maxOf := make(map[string]int)
+			for _, w := range windows {
+				if m, ok := maxOf[w.Action]; !ok || w.Max < m {
+					maxOf[w.Action] = w.Max
+				}
+			}
+
+			prevLogs := 0
+			prevSince := make(map[string]int)
+			for i := 0; i < 300; i++ {
+				fake.Advance(randomStep(rng))
+				action := testActions[rng.Intn(len(testActions))]
+
+				before := limiter.Remaining(action)
+				if limit, ok := maxOf[action]; ok {
+					if before < 0 || before > limit {
+						t.Fatalf("seed %d step %d: remaining %s = %d outside [0, %d]", seed, i, action, before, limit)
+					}
+				} else if before != -1 {
+					t.Fatalf("seed %d step %d: unlimited action %s has remaining %d", seed, i, action, before)
+				}
+
+				if limiter.Allow(action) {
+					success := rng.Intn(10) > 0               // Failed attempts never count
+					source := sources[rng.Intn(len(sources))] // Every source counts
+					if err := db.LogActionFrom(source, action, "p", success, nil); err != nil {
+						t.Fatal(err)
+					}
+					after := limiter.Remaining(action)
+					switch {
+					case before == -1 && after != -1:
+						t.Fatalf("seed %d step %d: unlimited %s became limited", seed, i, action)
+					case before > 0 && success && after != before-1:
+						t.Fatalf("seed %d step %d: %s remaining went %d -> %d after success", seed, i, action, before, after)
+					case before > 0 && !success && after != before:
+						t.Fatalf("seed %d step %d: %s remaining went %d -> %d after failure", seed, i, action, before, after)
+					}
+				} else if before != 0 {
+					t.Fatalf("seed %d step %d: %s denied with %d remaining", seed, i, action, before)
+				}
+
+				// Counts from a fixed point in time never decrease
+				logs := len(db.GetActionLogs(""))
+				if logs < prevLogs {
+					t.Fatalf("seed %d step %d: action log shrank %d -> %d", seed, i, prevLogs, logs)
+				}
+				prevLogs = logs
+				for _, a := range []string{"connection", "message", "search"} {
+					n := db.GetActionCountSince(a, start.Add(-time.Second))
+					if n < prevSince[a] {
+						t.Fatalf("seed %d step %d: %s count shrank %d -> %d", seed, i, a, prevSince[a], n)
+					}
+					prevSince[a] = n
+				}
+			}
+
+			if v := Check(db.GetActionLogs(""), windows); len(v) > 0 {
+				t.Fatalf("seed %d: limiter allowed %d window violations, first: %+v", seed, len(v), v[0])
+			}
+		})
+	}
+}
+
+// TestCheckDetectsViolations makes sure the checker used above isn't
+// vacuous: random logs ignoring the limiter must be flagged exactly when a
+// brute-force count finds an overfull window
+func TestCheckDetectsViolations(t *testing.T) {
+	windows := []Window{{Action: "connection", Period: time.Hour, Max: 3}}
+	for seed := int64(1); seed <= 200; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		at := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+		var logs []storage.ActionLog
+		for i := 0; i < 20; i++ {
+			at = at.Add(time.Duration(rng.Intn(40)) * time.Minute)
+			logs = append(logs, storage.ActionLog{Action: "connection", Timestamp: at, Success: rng.Intn(5) > 0})
+		}
+		// Shuffle, Check must not depend on log order
+		rng.Shuffle(len(logs), func(i, j int) { logs[i], logs[j] = logs[j], logs[i] })
+
+		want := false
+		for _, end := range logs {
+			count := 0
+			for _, l := range logs {
+				if l.Success && l.Timestamp.After(end.Timestamp.Add(-time.Hour)) && !l.Timestamp.After(end.Timestamp) {
+					count++
+				}
+			}
+			if end.Success && count > 3 {
+				want = true
+			}
+		}
+
+		if got := len(Check(logs, windows)) > 0; got != want {
+			t.Fatalf("seed %d: Check found violations = %v, brute force = %v", seed, got, want)
+		}
+	}
+}
+
+// TestProfileTouchGuardrail checks that connection requests and messages to
+// one profile share a rolling budget that frees up as touches age out
+func TestProfileTouchGuardrail(t *testing.T) {
+	start := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	db, err := storage.New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	limits := testLimits
+	limits.MaxTouchesPerProfile, limits.TouchWindowDays = 2, 7
+	limiter := New(db, limits)
+
+	db.LogAction("connection", "p1", true, nil)
+	db.LogAction("message", "p1", false, nil) // Failures don't count
+	db.LogAction("view", "p1", true, nil)     // Neither do other actions
+	fake.Advance(24 * time.Hour)
+	db.LogActionFrom(storage.SourceManual, "message", "p1", true, nil)
+
+	if err := limiter.CheckProfile("p1"); !errors.Is(err, ErrProfileTouches) {
+		t.Errorf("CheckProfile after 2 touches = %v, want ErrProfileTouches", err)
+	}
+	if err := limiter.CheckProfile("p2"); err != nil {
+		t.Errorf("CheckProfile of an untouched profile = %v", err)
+	}
+
+	fake.Advance(6*24*time.Hour + time.Minute) // The connection request ages out
+	if err := limiter.CheckProfile("p1"); err != nil {
+		t.Errorf("CheckProfile after the window = %v", err)
+	}
+	if err := New(db, testLimits).CheckProfile("p1"); err != nil {
+		t.Errorf("CheckProfile without a configured budget = %v", err)
+	}
+}
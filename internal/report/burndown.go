@@ -0,0 +1,109 @@
+package report
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"subspace/internal/storage"
+)
+
+// Pace of budget consumption relative to spreading it evenly over the
+// active hours
+const (
+	PaceAhead   = "ahead"
+	PaceOnTrack = "on_track"
+	PaceBehind  = "behind"
+)
+
+// BurnDown is how much of one action's daily budget has been consumed
+// over the active hours of a day
+type BurnDown struct {
+	Action   string `json:"action"`
+	Limit    int    `json:"limit"`
+	Used     int    `json:"used"`
+	Expected int    `json:"expected"` // Used by now if paced evenly
+	Pace     string `json:"pace"`
+
+	// When the budget ran out, or at the current rate will run out; nil if
+	// it lasts the active hours
+	ExhaustedAt *time.Time `json:"exhausted_at,omitempty"`
+	Projected   bool       `json:"projected"` // ExhaustedAt is a projection
+
+	Hours []HourUsage `json:"hours"`
+}
+
+// HourUsage is the consumption during one hour of the active window
+type HourUsage struct {
+	Start      time.Time `json:"start"`
+	Count      int       `json:"count"`
+	Cumulative int       `json:"cumulative"`
+	Expected   int       `json:"expected"` // Cumulative if paced evenly, at the end of the hour
+	Future     bool      `json:"future,omitempty"`
+}
+
+// BudgetBurnDown summarises successful actions between start and end (the
+// active hours of the day) against a daily limit, as of now
+func BudgetBurnDown(logs []storage.ActionLog, action string, limit int, start, end, now time.Time) BurnDown {
+	var times []time.Time
+	for _, l := range logs {
+		if l.Action == action && l.Success && !l.Timestamp.Before(start) && l.Timestamp.Before(end) && !l.Timestamp.After(now) {
+			times = append(times, l.Timestamp)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	b := BurnDown{Action: action, Limit: limit, Used: len(times), Expected: expectedBy(limit, start, end, now)}
+	tolerance := int(math.Max(1, math.Round(float64(limit)/10)))
+	switch {
+	case b.Used > b.Expected+tolerance:
+		b.Pace = PaceAhead
+	case b.Used < b.Expected-tolerance:
+		b.Pace = PaceBehind
+	default:
+		b.Pace = PaceOnTrack
+	}
+
+	switch elapsed := minTime(now, end).Sub(start); {
+	case limit > 0 && b.Used >= limit:
+		b.ExhaustedAt = &times[limit-1]
+	case b.Used > 0 && elapsed > 0 && now.Before(end):
+		rate := float64(b.Used) / elapsed.Seconds()
+		at := now.Add(time.Duration(float64(limit-b.Used) / rate * float64(time.Second)))
+		if at.Before(end) {
+			b.ExhaustedAt, b.Projected = &at, true
+		}
+	}
+
+	cumulative, i := 0, 0
+	for hour := start; hour.Before(end); hour = hour.Add(time.Hour) {
+		next := minTime(hour.Add(time.Hour), end)
+		h := HourUsage{Start: hour, Expected: expectedBy(limit, start, end, next), Future: hour.After(now)}
+		for ; i < len(times) && times[i].Before(next); i++ {
+			h.Count++
+		}
+		cumulative += h.Count
+		h.Cumulative = cumulative
+		b.Hours = append(b.Hours, h)
+	}
+	return b
+}
+
+// expectedBy returns how much of limit an even pace uses by t
+func expectedBy(limit int, start, end, t time.Time) int {
+	if !t.After(start) || !end.After(start) {
+		return 0
+	}
+	if !t.Before(end) {
+		return limit
+	}
+	return int(float64(limit) * t.Sub(start).Seconds() / end.Sub(start).Seconds())
+}
+
+// minTime returns the earlier of a and b
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
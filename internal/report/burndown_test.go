@@ -0,0 +1,49 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"subspace/internal/storage"
+)
+
+func TestBudgetBurnDown(t *testing.T) {
+	start := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+	end := start.Add(8 * time.Hour)
+	now := start.Add(2 * time.Hour) // 11:00, a quarter through the day
+
+	var logs []storage.ActionLog
+	for i := 0; i < 10; i++ {
+		logs = append(logs, storage.ActionLog{Action: "connection", Success: true, Timestamp: start.Add(time.Duration(i) * 12 * time.Minute)})
+	}
+	logs = append(logs,
+		storage.ActionLog{Action: "connection", Success: false, Timestamp: start.Add(time.Minute)},
+		storage.ActionLog{Action: "message", Success: true, Timestamp: start.Add(time.Minute)},
+		storage.ActionLog{Action: "connection", Success: true, Timestamp: start.Add(-time.Hour)}, // before hours
+	)
+
+	b := BudgetBurnDown(logs, "connection", 20, start, end, now)
+	if b.Used != 10 || b.Expected != 5 || b.Pace != PaceAhead {
+		t.Errorf("used %d expected %d pace %s, want 10, 5, ahead", b.Used, b.Expected, b.Pace)
+	}
+	// 10 in 2h is 5/h, so the other 10 last until 13:00
+	if b.ExhaustedAt == nil || !b.Projected || !b.ExhaustedAt.Equal(start.Add(4*time.Hour)) {
+		t.Errorf("exhaustion = %v (projected %v), want 13:00 projected", b.ExhaustedAt, b.Projected)
+	}
+	if len(b.Hours) != 8 || b.Hours[0].Count != 5 || b.Hours[1].Cumulative != 10 || b.Hours[2].Future || !b.Hours[3].Future {
+		t.Errorf("hours = %+v", b.Hours)
+	}
+	if last := b.Hours[len(b.Hours)-1]; last.Expected != 20 || !last.Future {
+		t.Errorf("last hour = %+v, want expected 20 in the future", last)
+	}
+
+	spent := BudgetBurnDown(logs, "connection", 4, start, end, now)
+	if spent.ExhaustedAt == nil || spent.Projected || !spent.ExhaustedAt.Equal(start.Add(36*time.Minute)) {
+		t.Errorf("spent budget exhaustion = %v (projected %v), want 09:36 actual", spent.ExhaustedAt, spent.Projected)
+	}
+
+	slow := BudgetBurnDown(logs, "message", 20, start, end, now)
+	if slow.Pace != PaceBehind || slow.ExhaustedAt != nil {
+		t.Errorf("slow pace %s exhaustion %v, want behind and lasting the day", slow.Pace, slow.ExhaustedAt)
+	}
+}
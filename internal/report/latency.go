@@ -0,0 +1,117 @@
+package report
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"subspace/internal/storage"
+)
+
+/*
+REPORT MODULE
+
+Read-only analyses over stored profiles, for tuning the pipeline.
+
+ACCEPTANCE LATENCY:
+The time from a connection request (RequestedAt) to its acceptance
+(AcceptedAt), summarised per search keyword. Requests still pending are
+counted with their age so far, which shows how many would be cut off by
+withdrawing after a given wait.
+*/
+
+// AllKeywords labels the group covering every profile
+const AllKeywords = "*"
+
+// Latency summarises acceptance latency for one group of profiles
+type Latency struct {
+	Keyword     string  `json:"keyword"`
+	Accepted    int     `json:"accepted"`
+	Pending     int     `json:"pending"`
+	MedianHours float64 `json:"median_hours"`
+	P90Hours    float64 `json:"p90_hours"`
+	MaxHours    float64 `json:"max_hours"`
+
+	// Requests pending for longer than the p90 acceptance latency, i.e.
+	// unlikely to still be accepted
+	PendingPastP90 int `json:"pending_past_p90"`
+}
+
+// AcceptanceLatency returns the overall latency summary followed by one
+// per search keyword, ordered by keyword. Only profiles that were sent a
+// request are counted.
+func AcceptanceLatency(profiles []*storage.Profile, now time.Time) []Latency {
+	type group struct {
+		latencies []time.Duration
+		pending   []time.Duration
+	}
+	groups := make(map[string]*group)
+	add := func(key string, p *storage.Profile) {
+		g, ok := groups[key]
+		if !ok {
+			g = &group{}
+			groups[key] = g
+		}
+		if p.AcceptedAt != nil {
+			g.latencies = append(g.latencies, p.AcceptedAt.Sub(*p.RequestedAt))
+		} else if p.State == storage.StateRequested {
+			g.pending = append(g.pending, now.Sub(*p.RequestedAt))
+		}
+	}
+	for _, p := range profiles {
+		if p.RequestedAt == nil {
+			continue
+		}
+		add(AllKeywords, p)
+		add(p.SearchQuery, p)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		if key != AllKeywords {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	if len(groups) > 0 {
+		keys = append([]string{AllKeywords}, keys...)
+	}
+
+	out := make([]Latency, 0, len(keys))
+	for _, key := range keys {
+		g := groups[key]
+		sort.Slice(g.latencies, func(i, j int) bool { return g.latencies[i] < g.latencies[j] })
+		l := Latency{Keyword: key, Accepted: len(g.latencies), Pending: len(g.pending)}
+		if len(g.latencies) > 0 {
+			p90 := Percentile(g.latencies, 90)
+			l.MedianHours = hours(Percentile(g.latencies, 50))
+			l.P90Hours = hours(p90)
+			l.MaxHours = hours(g.latencies[len(g.latencies)-1])
+			for _, age := range g.pending {
+				if age > p90 {
+					l.PendingPastP90++
+				}
+			}
+		}
+		out = append(out, l)
+	}
+	return out
+}
+
+// Percentile returns the p-th percentile (nearest rank) of sorted
+// durations, or 0 for none
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// hours converts a duration to hours rounded to one decimal
+func hours(d time.Duration) float64 {
+	return math.Round(d.Hours()*10) / 10
+}
@@ -0,0 +1,61 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"subspace/internal/storage"
+)
+
+func TestPercentile(t *testing.T) {
+	var d []time.Duration
+	for i := 1; i <= 10; i++ {
+		d = append(d, time.Duration(i)*time.Hour)
+	}
+	for p, want := range map[float64]time.Duration{0: time.Hour, 50: 5 * time.Hour, 90: 9 * time.Hour, 100: 10 * time.Hour} {
+		if got := Percentile(d, p); got != want {
+			t.Errorf("Percentile(%v) = %s, want %s", p, got, want)
+		}
+	}
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("Percentile of nothing = %s, want 0", got)
+	}
+}
+
+func TestAcceptanceLatency(t *testing.T) {
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	profile := func(query string, requestedAgo, acceptedAfter time.Duration) *storage.Profile {
+		requested := now.Add(-requestedAgo)
+		p := &storage.Profile{SearchQuery: query, State: storage.StateRequested, RequestedAt: &requested}
+		if acceptedAfter > 0 {
+			accepted := requested.Add(acceptedAfter)
+			p.State, p.AcceptedAt = storage.StateAccepted, &accepted
+		}
+		return p
+	}
+
+	profiles := []*storage.Profile{
+		profile("golang", 100*time.Hour, 2*time.Hour),
+		profile("golang", 100*time.Hour, 10*time.Hour),
+		profile("golang", 100*time.Hour, 30*time.Hour),
+		profile("golang", 50*time.Hour, 0), // pending past p90
+		profile("rust", 100*time.Hour, 4*time.Hour),
+		profile("rust", time.Hour, 0), // pending, still young
+		{SearchQuery: "rust", State: storage.StateDiscovered},
+	}
+
+	got := AcceptanceLatency(profiles, now)
+	want := []Latency{
+		{Keyword: AllKeywords, Accepted: 4, Pending: 2, MedianHours: 4, P90Hours: 30, MaxHours: 30, PendingPastP90: 1},
+		{Keyword: "golang", Accepted: 3, Pending: 1, MedianHours: 10, P90Hours: 30, MaxHours: 30, PendingPastP90: 1},
+		{Keyword: "rust", Accepted: 1, Pending: 1, MedianHours: 4, P90Hours: 4, MaxHours: 4},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d groups, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("group %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"subspace/internal/batch"
+)
+
+// SummaryFile is written to the data directory at the end of every run
+const SummaryFile = "summary.json"
+
+// Run outcomes
+const (
+	OutcomeCompleted    = "completed"
+	OutcomeFailed       = "failed" // Completed, but a step failed
+	OutcomeDayOff       = "day_off"
+	OutcomeOutsideHours = "outside_hours"
+)
+
+// RunSummary is the machine-readable result of one automation run, for
+// orchestration systems that shouldn't parse console output
+type RunSummary struct {
+	Version    string    `json:"version"`
+	Account    string    `json:"account,omitempty"`
+	Outcome    string    `json:"outcome"`
+	Reason     string    `json:"reason,omitempty"` // Why nothing ran, e.g. the holiday
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Duration   float64   `json:"duration_seconds"`
+
+	Steps   []StepResult    `json:"steps"`
+	Errors  []string        `json:"errors"`
+	Batches []*batch.Result `json:"batches"` // Per-profile outcomes of each batch sent
+
+	ConnectionsSent int `json:"connections_sent"` // By this run
+	MessagesSent    int `json:"messages_sent"`    // By this run
+	Pending         int `json:"pending_requests"`
+	Accepted        int `json:"accepted_connections"`
+
+	Limits          []LimitUsage `json:"limits"`
+	BrowserRestarts int          `json:"browser_restarts"`
+	BandwidthBytes  int64        `json:"bandwidth_bytes,omitempty"`
+
+	// Time per action type spent in deliberate stealth delays vs real work
+	StealthOverhead []Overhead `json:"stealth_overhead"`
+
+	// Start of the next session the schedule allows, if within the
+	// schedule horizon
+	NextRun *time.Time `json:"next_run,omitempty"`
+}
+
+// StepResult is the timing and outcome of one workflow step
+type StepResult struct {
+	Name     string  `json:"name"`
+	Duration float64 `json:"duration_seconds"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// LimitUsage is how much of a daily budget is used after the run
+type LimitUsage struct {
+	Action string `json:"action"`
+	Used   int    `json:"used"`
+	Limit  int    `json:"limit"`
+}
+
+// Overhead is the time one action type took during the run, split into
+// the delays stealth added on purpose and the work itself
+type Overhead struct {
+	Action       string  `json:"action"`
+	Actions      int     `json:"actions,omitempty"`
+	DelaySeconds float64 `json:"delay_seconds"`
+	WorkSeconds  float64 `json:"work_seconds"`
+	DelayShare   float64 `json:"delay_share"` // Of the action type's wall-clock time
+}
+
+// NewRunSummary starts the summary of a run starting at now
+func NewRunSummary(version, account string, now time.Time) *RunSummary {
+	return &RunSummary{
+		Version:   version,
+		Account:   account,
+		Outcome:   OutcomeCompleted,
+		StartedAt: now,
+		Steps:     []StepResult{},
+		Errors:    []string{},
+		Batches:   []*batch.Result{},
+		Limits:    []LimitUsage{},
+
+		StealthOverhead: []Overhead{},
+	}
+}
+
+// Step records a step that started at start and ended at now. A step that
+// failed fails the run.
+func (s *RunSummary) Step(name string, start, now time.Time, err error) {
+	if s == nil {
+		return
+	}
+	step := StepResult{Name: name, Duration: now.Sub(start).Seconds()}
+	if err != nil {
+		step.Error = err.Error()
+		s.Errors = append(s.Errors, fmt.Sprintf("%s: %v", name, err))
+		s.Outcome = OutcomeFailed
+	}
+	s.Steps = append(s.Steps, step)
+}
+
+// AddBatch records the per-profile outcomes of a batch
+func (s *RunSummary) AddBatch(r *batch.Result) {
+	if s != nil && r != nil {
+		s.Batches = append(s.Batches, r)
+	}
+}
+
+// AddOverhead records the time an action type took, delay of it spent
+// waiting on purpose
+func (s *RunSummary) AddOverhead(action string, actions int, total, delay time.Duration) {
+	if s == nil {
+		return
+	}
+	o := Overhead{Action: action, Actions: actions, DelaySeconds: delay.Seconds(), WorkSeconds: max(total-delay, 0).Seconds()}
+	if total > 0 {
+		o.DelayShare = min(delay.Seconds()/total.Seconds(), 1)
+	}
+	s.StealthOverhead = append(s.StealthOverhead, o)
+}
+
+// Finish stamps the end of the run
+func (s *RunSummary) Finish(now time.Time) {
+	s.FinishedAt = now
+	s.Duration = now.Sub(s.StartedAt).Seconds()
+}
+
+// ReadSummary reads the summary of the last run from dir, or returns nil
+// if no run has written one
+func ReadSummary(dir string) (*RunSummary, error) {
+	data, err := os.ReadFile(filepath.Join(dir, SummaryFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read run summary: %w", err)
+	}
+	s := &RunSummary{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse run summary: %w", err)
+	}
+	return s, nil
+}
+
+// Write replaces the summary file in dir
+func (s *RunSummary) Write(dir string) (string, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, SummaryFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write run summary: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", fmt.Errorf("failed to write run summary: %w", err)
+	}
+	return path, nil
+}
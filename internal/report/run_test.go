@@ -0,0 +1,36 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRunSummary(t *testing.T) {
+	start := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+	s := NewRunSummary("v1.0.0", "alice", start)
+	s.Step("search", start, start.Add(90*time.Second), nil)
+	s.Step("connect", start.Add(90*time.Second), start.Add(2*time.Minute), errors.New("rate limited"))
+	s.Finish(start.Add(3 * time.Minute))
+
+	path, err := s.Write(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got RunSummary
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Outcome != OutcomeFailed || got.Duration != 180 || len(got.Steps) != 2 || got.Steps[0].Duration != 90 {
+		t.Errorf("summary = %+v", got)
+	}
+	if len(got.Errors) != 1 || got.Errors[0] != "connect: rate limited" {
+		t.Errorf("errors = %v", got.Errors)
+	}
+}
@@ -0,0 +1,234 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// tokKind classifies a token
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokIdent
+	tokTag
+	tokString
+	tokNumber
+	tokOp // && || ! ( ) and comparison operators
+)
+
+// token is one lexed token; pos is its 1-based column
+type token struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of rule"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// parser is a recursive descent parser over the lexed tokens
+type parser struct {
+	src    string
+	tokens []token
+	i      int
+}
+
+// operators lists the operators, two-character ones first
+var operators = []string{"&&", "||", "==", "!=", "~=", "<=", ">=", "!", "(", ")", "<", ">"}
+
+// lex splits the source into tokens
+func (p *parser) lex() error {
+	src := p.src
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"' || c == '\'':
+			text, n, err := lexString(src[i:])
+			if err != nil {
+				return fmt.Errorf("column %d: %w", i+1, err)
+			}
+			p.tokens = append(p.tokens, token{tokString, text, i + 1})
+			i += n
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			j := i + 1
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.') {
+				j++
+			}
+			p.tokens = append(p.tokens, token{tokNumber, src[i:j], i + 1})
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(src) && (isIdent(src[j])) {
+				j++
+			}
+			if src[i:j] == "tag" && j < len(src) && src[j] == ':' {
+				k := j + 1
+				for k < len(src) && (isIdent(src[k]) || src[k] == '-') {
+					k++
+				}
+				if k == j+1 {
+					return fmt.Errorf("column %d: tag: needs a name", i+1)
+				}
+				p.tokens = append(p.tokens, token{tokTag, src[j+1 : k], i + 1})
+				i = k
+				continue
+			}
+			p.tokens = append(p.tokens, token{tokIdent, src[i:j], i + 1})
+			i = j
+		default:
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return fmt.Errorf("column %d: unexpected character %q", i+1, c)
+			}
+			p.tokens = append(p.tokens, token{tokOp, op, i + 1})
+			i += len(op)
+		}
+	}
+	p.tokens = append(p.tokens, token{tokEOF, "", len(src) + 1})
+	return nil
+}
+
+// comparisons is the set of comparison operators
+var comparisons = map[string]bool{"==": true, "!=": true, "~=": true, "<": true, "<=": true, ">": true, ">=": true}
+
+// isIdent reports whether b can be part of a field name
+func isIdent(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b == '_'
+}
+
+// lexString reads a quoted string with \" and \\ escapes, returning its
+// text and the number of bytes consumed
+func lexString(s string) (string, int, error) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 == len(s) {
+				return "", 0, fmt.Errorf("unterminated string")
+			}
+			i++
+			b.WriteByte(s[i])
+		case quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+func (p *parser) peek() token { return p.tokens[p.i] }
+
+// accept consumes the next token if it is the operator op
+func (p *parser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokOp && t.text == op {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("column %d: %s", p.peek().pos, fmt.Sprintf(format, args...))
+}
+
+// expr := and ("||" and)*
+func (p *parser) expr() (node, error) {
+	l, err := p.and()
+	for err == nil && p.accept("||") {
+		var r node
+		if r, err = p.and(); err == nil {
+			l = orNode{l, r}
+		}
+	}
+	return l, err
+}
+
+// and := unary ("&&" unary)*
+func (p *parser) and() (node, error) {
+	l, err := p.unary()
+	for err == nil && p.accept("&&") {
+		var r node
+		if r, err = p.unary(); err == nil {
+			l = andNode{l, r}
+		}
+	}
+	return l, err
+}
+
+// unary := "!" unary | "(" expr ")" | tag:<name> | field [op literal]
+func (p *parser) unary() (node, error) {
+	if p.accept("!") {
+		x, err := p.unary()
+		return notNode{x}, err
+	}
+	if p.accept("(") {
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, p.errorf("expected \")\", found %s", p.peek())
+		}
+		return x, nil
+	}
+
+	t := p.peek()
+	switch t.kind {
+	case tokTag:
+		p.i++
+		return tagNode{t.text}, nil
+	case tokIdent:
+		p.i++
+	default:
+		return nil, p.errorf("expected a field, tag or \"(\", found %s", t)
+	}
+	field := strings.ToLower(t.text)
+	if !knownField(field) {
+		return nil, fmt.Errorf("column %d: unknown field %q (known: %s)", t.pos, t.text, strings.Join(Fields, ", "))
+	}
+
+	op := p.peek()
+	if op.kind != tokOp || !comparisons[op.text] {
+		return setNode{field}, nil
+	}
+	p.i++
+	lit := p.peek()
+	var v Value
+	switch lit.kind {
+	case tokString:
+		v = Text(lit.text)
+	case tokNumber:
+		n, err := strconv.ParseFloat(lit.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("column %d: invalid number %q", lit.pos, lit.text)
+		}
+		v = Number(n)
+	default:
+		return nil, p.errorf("expected a string or number after %s, found %s", op.text, lit)
+	}
+	p.i++
+	switch {
+	case op.text == "~=" && v.IsNum:
+		return nil, fmt.Errorf("column %d: ~= needs a string", lit.pos)
+	case op.text != "~=" && op.text != "==" && op.text != "!=" && !v.IsNum:
+		return nil, fmt.Errorf("column %d: %s needs a number", lit.pos, op.text)
+	}
+	return cmpNode{field, op.text, v}, nil
+}
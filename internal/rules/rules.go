@@ -0,0 +1,187 @@
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+/*
+RULES MODULE
+
+A small expression language for targeting, evaluated against one profile:
+
+	title ~= "engineer" && company_size > 50 && !tag:competitor
+
+GRAMMAR:
+	expr       := and ("||" and)*
+	and        := unary ("&&" unary)*
+	unary      := "!" unary | "(" expr ")" | tag:<name> | field [op literal]
+	op         := "==" | "!=" | "~=" | "<" | "<=" | ">" | ">="
+	literal    := "string" | number
+
+- "~=" is a case-insensitive substring match; "==" and "!=" compare text
+  case-insensitively, or numbers when the literal is a number
+- "<", "<=", ">", ">=" take a number and never match a field without one
+- a bare field is true when it is set (non-empty, non-zero)
+- tag:<name> is true when the profile carries the tag
+
+Field names are checked when a rule is compiled, so a typo in the config
+fails at startup instead of silently matching nothing.
+*/
+
+// Fields lists the profile fields rules can use
+var Fields = []string{
+	"name", "title", "company", "location", "city", "region", "country",
+	"seniority", "function", "industry", "company_size", "mutual_connections",
+	"shared_groups", "shared_schools", "state", "search_query", "notes",
+}
+
+// Value is a field value: its text and, when it has one, its number
+type Value struct {
+	Text  string
+	Num   float64
+	IsNum bool
+}
+
+// Text returns a text value
+func Text(s string) Value { return Value{Text: s} }
+
+// Number returns a numeric value
+func Number(n float64) Value { return Value{Text: fmt.Sprint(n), Num: n, IsNum: true} }
+
+// set reports whether a value counts as true on its own
+func (v Value) set() bool {
+	if v.IsNum {
+		return v.Num != 0
+	}
+	return v.Text != ""
+}
+
+// Env is what a rule is evaluated against: one profile's fields and tags
+type Env struct {
+	Fields map[string]Value
+	Tags   []string
+}
+
+// hasTag reports whether the env carries a tag (case-insensitive)
+func (e Env) hasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Rule is a compiled expression
+type Rule struct {
+	src  string
+	root node
+}
+
+// Compile parses an expression
+func Compile(src string) (*Rule, error) {
+	p := &parser{src: src}
+	if err := p.lex(); err != nil {
+		return nil, fmt.Errorf("invalid rule %q: %w", src, err)
+	}
+	root, err := p.expr()
+	if err == nil && p.peek().kind != tokEOF {
+		err = p.errorf("unexpected %s", p.peek())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule %q: %w", src, err)
+	}
+	return &Rule{src: src, root: root}, nil
+}
+
+// MustCompile compiles an expression known to be valid, e.g. one checked
+// when the config was loaded
+func MustCompile(src string) *Rule {
+	r, err := Compile(src)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// Match evaluates the rule against an env
+func (r *Rule) Match(env Env) bool {
+	return r.root.eval(env)
+}
+
+// String returns the source of the rule
+func (r *Rule) String() string {
+	return r.src
+}
+
+// node is an expression tree node
+type node interface {
+	eval(env Env) bool
+}
+
+type andNode struct{ l, r node }
+
+func (n andNode) eval(env Env) bool { return n.l.eval(env) && n.r.eval(env) }
+
+type orNode struct{ l, r node }
+
+func (n orNode) eval(env Env) bool { return n.l.eval(env) || n.r.eval(env) }
+
+type notNode struct{ x node }
+
+func (n notNode) eval(env Env) bool { return !n.x.eval(env) }
+
+type tagNode struct{ tag string }
+
+func (n tagNode) eval(env Env) bool { return env.hasTag(n.tag) }
+
+type setNode struct{ field string }
+
+func (n setNode) eval(env Env) bool { return env.Fields[n.field].set() }
+
+type cmpNode struct {
+	field, op string
+	lit       Value
+}
+
+func (n cmpNode) eval(env Env) bool {
+	v := env.Fields[n.field]
+	switch n.op {
+	case "~=":
+		return strings.Contains(strings.ToLower(v.Text), strings.ToLower(n.lit.Text))
+	case "==", "!=":
+		equal := strings.EqualFold(v.Text, n.lit.Text)
+		if n.lit.IsNum {
+			equal = v.IsNum && v.Num == n.lit.Num
+		}
+		return equal == (n.op == "==")
+	}
+	if !v.IsNum {
+		return false
+	}
+	switch n.op {
+	case "<":
+		return v.Num < n.lit.Num
+	case "<=":
+		return v.Num <= n.lit.Num
+	case ">":
+		return v.Num > n.lit.Num
+	default:
+		return v.Num >= n.lit.Num
+	}
+}
+
+// knownField reports whether name is in Fields
+func knownField(name string) bool {
+	i := sort.SearchStrings(sortedFields, name)
+	return i < len(sortedFields) && sortedFields[i] == name
+}
+
+// sortedFields is Fields in sorted order, for lookups
+var sortedFields = func() []string {
+	s := append([]string(nil), Fields...)
+	sort.Strings(s)
+	return s
+}()
@@ -0,0 +1,57 @@
+package rules
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	env := Env{
+		Fields: map[string]Value{
+			"title":        Text("Senior Software Engineer"),
+			"company":      Text("Acme Inc."),
+			"company_size": {Text: "51-200", Num: 51, IsNum: true},
+			"country":      Text("Germany"),
+			"industry":     Text(""),
+		},
+		Tags: []string{"Competitor"},
+	}
+	tests := map[string]bool{
+		`title ~= "engineer"`:                                         true,
+		`title ~= "ENGINEER" && company_size > 50`:                    true,
+		`title ~= "engineer" && company_size > 50 && !tag:competitor`: false,
+		`tag:competitor || country == "france"`:                       true,
+		`!(country == "germany" || country == "austria")`:             false,
+		`company_size == "51-200" && company_size >= 51`:              true,
+		`company_size < 51`:                                           false,
+		`mutual_connections > 0`:                                      false, // unset numbers never compare
+		`industry`:                                                    false,
+		`company && country != 'Spain'`:                               true,
+	}
+	for src, want := range tests {
+		r, err := Compile(src)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", src, err)
+			continue
+		}
+		if got := r.Match(env); got != want {
+			t.Errorf("%s = %v, want %v", src, got, want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, src := range []string{
+		``,
+		`titel ~= "x"`,
+		`title ~= 5`,
+		`company_size > "50"`,
+		`title ~= "x" &&`,
+		`(title ~= "x"`,
+		`title ~= "x`,
+		`tag:`,
+		`title $ "x"`,
+		`title ~= "x" title`,
+	} {
+		if _, err := Compile(src); err == nil {
+			t.Errorf("Compile(%q) succeeded", src)
+		}
+	}
+}
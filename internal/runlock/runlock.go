@@ -0,0 +1,131 @@
+package runlock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/logger"
+)
+
+/*
+RUN LOCK MODULE
+
+Two instances writing one data directory would overwrite each other's
+db.json. Each instance therefore holds an exclusive lock on
+<data_dir>/subspace.lock (flock, released by the OS when the process dies,
+so a crash never leaves a stale lock) and writes its PID, host and start
+time into it for the next instance's error message.
+
+-force-takeover stops the holder (SIGTERM, on the same host only) and
+waits for its lock instead of failing.
+*/
+
+// fileName is the lock file inside the data directory
+const fileName = "subspace.lock"
+
+// Holder describes the instance holding the lock
+type Holder struct {
+	PID       int       `json:"pid"`
+	Host      string    `json:"host"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// HeldError is returned when another instance holds the lock
+type HeldError struct {
+	Holder Holder
+}
+
+func (e *HeldError) Error() string {
+	if e.Holder.PID == 0 {
+		return "another instance is running on this data directory"
+	}
+	return fmt.Sprintf("another instance is running on this data directory (PID %d on %s, since %s); stop it or use -force-takeover",
+		e.Holder.PID, e.Holder.Host, e.Holder.StartedAt.Format("2006-01-02 15:04"))
+}
+
+// Lock is a held run lock
+type Lock struct {
+	file *os.File
+}
+
+// Acquire takes the lock of dataDir, or returns a *HeldError naming the
+// instance holding it
+func Acquire(dataDir string) (*Lock, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	path := filepath.Join(dataDir, fileName)
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open run lock: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		if errors.Is(err, errLocked) {
+			return nil, &HeldError{Holder: readHolder(path)}
+		}
+		return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+	}
+
+	host, _ := os.Hostname()
+	data, _ := json.Marshal(Holder{PID: os.Getpid(), Host: host, StartedAt: clock.Now()})
+	if err := f.Truncate(0); err == nil {
+		_, err = f.WriteAt(data, 0)
+	}
+	if err != nil {
+		logger.NewContext("runlock").Warn("Failed to record lock holder", "error", err)
+	}
+	return &Lock{file: f}, nil
+}
+
+// Takeover acquires the lock, stopping the instance holding it first if
+// it runs on this host, and waits up to wait for its lock to be released
+func Takeover(dataDir string, wait time.Duration) (*Lock, error) {
+	l, err := Acquire(dataDir)
+	var held *HeldError
+	if !errors.As(err, &held) {
+		return l, err
+	}
+
+	log := logger.NewContext("runlock")
+	host, _ := os.Hostname()
+	if held.Holder.PID == 0 || held.Holder.Host != host {
+		return nil, fmt.Errorf("%w; it can't be stopped from here", err)
+	}
+	log.Warn("Taking over the data directory", "pid", held.Holder.PID)
+	if err := terminate(held.Holder.PID); err != nil {
+		return nil, fmt.Errorf("failed to stop PID %d: %w", held.Holder.PID, err)
+	}
+
+	deadline := time.Now().Add(wait)
+	for {
+		l, err := Acquire(dataDir)
+		if !errors.As(err, &held) || time.Now().After(deadline) {
+			return l, err
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// Release gives the lock up
+func (l *Lock) Release() error {
+	if l == nil {
+		return nil
+	}
+	l.file.Truncate(0)
+	return l.file.Close() // Closing releases the flock
+}
+
+// readHolder reads the holder a lock file records; zero if unreadable
+func readHolder(path string) Holder {
+	var h Holder
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &h)
+	}
+	return h
+}
@@ -0,0 +1,25 @@
+//go:build !unix
+
+package runlock
+
+import (
+	"errors"
+	"os"
+)
+
+// errLocked is returned by lockFile when another process holds the lock
+var errLocked = errors.New("locked")
+
+// lockFile is a no-op without flock: instances are not kept apart
+func lockFile(f *os.File) error {
+	return nil
+}
+
+// terminate stops a process
+func terminate(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
@@ -0,0 +1,37 @@
+package runlock
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestAcquireExcludesSecondInstance(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no flock")
+	}
+	dir := t.TempDir()
+	l, err := Acquire(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Acquire(dir)
+	var held *HeldError
+	if !errors.As(err, &held) {
+		t.Fatalf("second acquire: got %v, want a *HeldError", err)
+	}
+	if held.Holder.PID != os.Getpid() {
+		t.Errorf("holder PID = %d, want %d", held.Holder.PID, os.Getpid())
+	}
+
+	if err := l.Release(); err != nil {
+		t.Fatal(err)
+	}
+	l, err = Acquire(dir)
+	if err != nil {
+		t.Fatalf("acquire after release: %v", err)
+	}
+	l.Release()
+}
@@ -0,0 +1,26 @@
+//go:build unix
+
+package runlock
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// errLocked is returned by lockFile when another process holds the lock
+var errLocked = errors.New("locked")
+
+// lockFile takes an exclusive flock on f without blocking
+func lockFile(f *os.File) error {
+	err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return errLocked
+	}
+	return err
+}
+
+// terminate asks a process to exit
+func terminate(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM)
+}
@@ -0,0 +1,277 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"subspace/internal/calendar"
+	"subspace/internal/config"
+	"subspace/internal/ratelimit"
+	"subspace/internal/storage"
+	"subspace/internal/worksession"
+)
+
+/*
+SCHEDULE MODULE
+
+Answers "what will happen next" by projecting the scheduler state forward
+over a horizon (app.schedule_hours):
+- sessions: the business-hours windows in which runs act, split by the
+  break, plus any operator-started work session
+- days off: weekends and holidays on which nothing runs
+- snoozes ending: profiles that become eligible again
+- budget: when an exhausted rate-limit window frees its next slot
+
+The projection reads the same config, calendar and storage the run gates
+on, so it shows what the gates will allow, not what a run will certainly
+do: a run still needs to be started within a session.
+*/
+
+// Event kinds
+const (
+	KindSession     = "session"
+	KindWorkSession = "work_session"
+	KindDayOff      = "day_off"
+	KindSnoozeEnds  = "snooze_ends"
+	KindBudgetFrees = "budget_frees"
+)
+
+// Event is one upcoming scheduled item
+type Event struct {
+	At        time.Time  `json:"at"`
+	Until     *time.Time `json:"until,omitempty"` // End of a session
+	Kind      string     `json:"kind"`
+	Detail    string     `json:"detail,omitempty"`
+	ProfileID string     `json:"profile_id,omitempty"`
+}
+
+// Build lists the events between from and from+horizon, in time order.
+// Sessions already under way at from are included. The work session may be
+// nil.
+func Build(cfg *config.Config, db storage.Backend, cal *calendar.Calendar, ws *worksession.Session, from time.Time, horizon time.Duration) []Event {
+	to := from.Add(horizon)
+	events := sessions(cfg, cal, from, to)
+
+	if ws != nil && ws.ExpiresAt.After(from) && ws.StartedAt.Before(to) {
+		until := ws.ExpiresAt
+		events = append(events, Event{At: ws.StartedAt, Until: &until, Kind: KindWorkSession, Detail: ws.Reason})
+	}
+
+	for _, p := range db.SnoozedProfiles(from) {
+		if p.SnoozedUntil.After(to) {
+			break // Sorted by wake-up time
+		}
+		events = append(events, Event{
+			At:        *p.SnoozedUntil,
+			Kind:      KindSnoozeEnds,
+			Detail:    fmt.Sprintf("%s (%s)", p.Name, p.State),
+			ProfileID: p.ID,
+		})
+	}
+
+	for _, w := range ratelimit.Windows(cfg.Limits) {
+		if at, ok := nextSlot(db, w, from); ok && at.Before(to) {
+			events = append(events, Event{At: at, Kind: KindBudgetFrees, Detail: fmt.Sprintf("%s (%d per %s)", w.Action, w.Max, w.Period)})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+	return events
+}
+
+// SessionStart returns when the session under way at now began: the
+// operator's work session if one is running, else the activity window
+// containing now. Without either, the session is the run itself and starts
+// at now. The work session may be nil.
+func SessionStart(cfg *config.Config, cal *calendar.Calendar, ws *worksession.Session, now time.Time) time.Time {
+	if ws.Active(now) {
+		return ws.StartedAt
+	}
+	for _, e := range sessions(cfg, cal, now, now.Add(time.Nanosecond)) {
+		if e.Kind == KindSession && !e.At.After(now) {
+			return e.At
+		}
+	}
+	return now
+}
+
+// sessions lists the activity windows that overlap [from, to) and the days
+// off, including today, on which none open
+func sessions(cfg *config.Config, cal *calendar.Calendar, from, to time.Time) []Event {
+	detail := strings.Join(cfg.Modules.Enabled(), ", ")
+	st := cfg.Stealth
+	if !st.BusinessHoursEnabled {
+		return []Event{{At: from, Until: &to, Kind: KindSession, Detail: detail}}
+	}
+
+	var events []Event
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if off, reason := cal.DayOff(day); off {
+			events = append(events, Event{At: day, Kind: KindDayOff, Detail: reason})
+			continue
+		}
+
+		windows := [][2]string{{st.BusinessHoursStart, st.BusinessHoursEnd}}
+		if st.BreakTimeEnabled {
+			windows = [][2]string{{st.BusinessHoursStart, st.BreakTimeStart}, {st.BreakTimeEnd, st.BusinessHoursEnd}}
+		}
+		for _, w := range windows {
+			start, end := at(day, w[0]), at(day, w[1])
+			if !end.After(start) || !end.After(from) || !start.Before(to) {
+				continue
+			}
+			until := end
+			events = append(events, Event{At: start, Until: &until, Kind: KindSession, Detail: detail})
+		}
+	}
+	return events
+}
+
+// nextSlot returns when the window, if currently exhausted, frees its next
+// slot: when the oldest action that keeps it full ages out
+func nextSlot(db storage.Backend, w ratelimit.Window, from time.Time) (time.Time, bool) {
+	if w.Max <= 0 {
+		return time.Time{}, false
+	}
+	var inWindow []time.Time
+	for _, log := range db.GetActionLogs(w.Action) {
+		if log.Success && log.Timestamp.After(from.Add(-w.Period)) && !log.Timestamp.After(from) {
+			inWindow = append(inWindow, log.Timestamp)
+		}
+	}
+	if len(inWindow) < w.Max {
+		return time.Time{}, false
+	}
+	sort.Slice(inWindow, func(i, j int) bool { return inWindow[i].Before(inWindow[j]) })
+	return inWindow[len(inWindow)-w.Max].Add(w.Period), true
+}
+
+// at returns the given HH:MM on day. Invalid times are rejected by config
+// validation.
+func at(day time.Time, hhmm string) time.Time {
+	t, _ := time.Parse("15:04", hhmm)
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location())
+}
+
+// Project returns when the next n jobs of action could run, in order,
+// counting from from: each takes the earliest moment inside a session at
+// which the action's rate-limit windows, its cooldown and, for messages,
+// the per-session cap allow one more. Jobs that can't run within horizon
+// are left out, so fewer than n times means the backlog reaches past it.
+// The work session may be nil.
+func Project(cfg *config.Config, db storage.Backend, cal *calendar.Calendar, ws *worksession.Session, action string, n int, from time.Time, horizon time.Duration) []time.Time {
+	to := from.Add(horizon)
+	var open []Event
+	for _, e := range sessions(cfg, cal, from, to) {
+		if e.Kind == KindSession {
+			open = append(open, e)
+		}
+	}
+	if ws != nil && ws.ExpiresAt.After(from) && ws.StartedAt.Before(to) {
+		until := ws.ExpiresAt
+		open = append(open, Event{At: ws.StartedAt, Until: &until, Kind: KindWorkSession})
+	}
+
+	var windows []ratelimit.Window
+	lookback := time.Duration(0)
+	for _, w := range ratelimit.Windows(cfg.Limits) {
+		if w.Action != action {
+			continue
+		}
+		if w.Max <= 0 {
+			return nil // Disabled
+		}
+		windows = append(windows, w)
+		if w.Period > lookback {
+			lookback = w.Period
+		}
+	}
+	cooldown, perSession := time.Duration(0), 0
+	switch action {
+	case "connection":
+		cooldown = time.Duration(cfg.Limits.ConnectionCooldownSeconds) * time.Second
+	case "message":
+		cooldown = time.Duration(cfg.Limits.MessageCooldownSeconds) * time.Second
+		perSession = cfg.Limits.MessagesPerSession
+	}
+	if perSession > 0 {
+		for _, e := range open {
+			if e.At.Before(from) && from.Sub(e.At) > lookback {
+				lookback = from.Sub(e.At)
+			}
+		}
+	}
+
+	// Past successes still in a window, then the projected ones
+	var done []time.Time
+	for _, log := range db.GetActionLogs(action) {
+		if log.Success && log.Timestamp.After(from.Add(-lookback)) && !log.Timestamp.After(from) {
+			done = append(done, log.Timestamp)
+		}
+	}
+	sort.Slice(done, func(i, j int) bool { return done[i].Before(done[j]) })
+
+	var slots []time.Time
+	t := from
+	for len(slots) < n {
+		if len(done) > 0 && t.Before(done[len(done)-1].Add(cooldown)) {
+			t = done[len(done)-1].Add(cooldown)
+		}
+		session, ok := openAt(open, t)
+		if !ok || !t.Before(to) {
+			break
+		}
+		if session.At.After(t) {
+			t = session.At
+		}
+
+		// Move past whatever keeps t from being a slot and check again
+		next := t
+		for _, w := range windows {
+			if in := countSince(done, t.Add(-w.Period)); in >= w.Max {
+				if freed := done[len(done)-w.Max].Add(w.Period); freed.After(next) {
+					next = freed
+				}
+			}
+		}
+		if perSession > 0 && countSince(done, session.At.Add(-time.Nanosecond)) >= perSession {
+			next = *session.Until
+		}
+		if next.After(t) {
+			t = next
+			continue
+		}
+
+		slots = append(slots, t)
+		done = append(done, t)
+	}
+	return slots
+}
+
+// openAt returns the session that is open at t or, failing that, the
+// first to open after it
+func openAt(open []Event, t time.Time) (Event, bool) {
+	var first Event
+	found := false
+	for _, e := range open {
+		if !e.Until.After(t) {
+			continue
+		}
+		if !e.At.After(t) {
+			return e, true
+		}
+		if !found || e.At.Before(first.At) {
+			first, found = e, true
+		}
+	}
+	return first, found
+}
+
+// countSince counts the sorted times after since
+func countSince(times []time.Time, since time.Time) int {
+	i := sort.Search(len(times), func(i int) bool { return times[i].After(since) })
+	return len(times) - i
+}
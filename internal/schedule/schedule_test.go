@@ -0,0 +1,141 @@
+package schedule
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"subspace/internal/calendar"
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/storage"
+	"subspace/internal/worksession"
+)
+
+func TestBuild(t *testing.T) {
+	// Friday 10:30; the weekend follows
+	from := time.Date(2024, 5, 3, 10, 30, 0, 0, time.UTC)
+	clock.Set(clock.NewFake(from))
+	defer clock.Set(clock.Real{})
+
+	cfg := config.Defaults()
+	cfg.Limits.SearchesPerDay = 2
+	db, err := storage.New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cal, err := calendar.New(cfg.Stealth)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &storage.Profile{ID: "p1", Name: "Ada", ProfileURL: "https://www.linkedin.com/in/ada", State: storage.StateAccepted}
+	p.Snooze(from.Add(5*time.Hour), "")
+	if err := db.SaveProfile(p); err != nil {
+		t.Fatal(err)
+	}
+	// Two searches exhaust the daily budget; the first ages out tomorrow 08:00
+	for _, at := range []time.Time{from.Add(-150 * time.Minute), from.Add(-time.Hour)} {
+		clock.Set(clock.NewFake(at))
+		db.LogAction("search", "", true, nil)
+	}
+
+	events := Build(cfg, db, cal, nil, from, 48*time.Hour)
+	want := []struct {
+		kind string
+		at   string
+	}{
+		{KindSession, "2024-05-03 09:00"}, // Under way
+		{KindSession, "2024-05-03 13:00"},
+		{KindSnoozeEnds, "2024-05-03 15:30"},
+		{KindDayOff, "2024-05-04 00:00"},
+		{KindBudgetFrees, "2024-05-04 08:00"},
+		{KindDayOff, "2024-05-05 00:00"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		if e := events[i]; e.Kind != w.kind || e.At.Format("2006-01-02 15:04") != w.at {
+			t.Errorf("event %d = %s at %s, want %s at %s", i, e.Kind, e.At.Format("2006-01-02 15:04"), w.kind, w.at)
+		}
+	}
+	if until := events[0].Until; until == nil || until.Format("15:04") != "12:00" {
+		t.Errorf("first session ends %v, want 12:00", until)
+	}
+}
+
+func TestSessionStart(t *testing.T) {
+	cfg := config.Defaults()
+	cal, err := calendar.New(cfg.Stealth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC) // A Friday
+	ws := &worksession.Session{StartedAt: day.Add(18 * time.Hour), ExpiresAt: day.Add(20 * time.Hour)}
+
+	for _, tc := range []struct {
+		now  string
+		want string
+	}{
+		{"10:30", "09:00"},
+		{"14:00", "13:00"},
+		{"12:30", "12:30"}, // Break: the run is its own session
+		{"19:00", "18:00"}, // Work session
+	} {
+		now := at(day, tc.now)
+		if got := SessionStart(cfg, cal, ws, now); got.Format("15:04") != tc.want {
+			t.Errorf("SessionStart at %s = %s, want %s", tc.now, got.Format("15:04"), tc.want)
+		}
+	}
+}
+
+func TestProject(t *testing.T) {
+	// Friday 10:30; the weekend follows
+	from := time.Date(2024, 5, 3, 10, 30, 0, 0, time.UTC)
+	clock.Set(clock.NewFake(from))
+	defer clock.Set(clock.Real{})
+
+	cfg := config.Defaults()
+	db, err := storage.New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cal, err := calendar.New(cfg.Stealth)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ten an hour, 30 seconds apart: the eleventh waits for the first to
+	// age out of the hourly window
+	slots := Project(cfg, db, cal, nil, "connection", 12, from, 24*time.Hour)
+	if len(slots) != 12 {
+		t.Fatalf("got %d slots, want 12", len(slots))
+	}
+	for i, want := range map[int]string{0: "10:30:00", 9: "10:34:30", 10: "11:30:00", 11: "11:30:30"} {
+		if got := slots[i].Format("15:04:05"); got != want {
+			t.Errorf("connection %d at %s, want %s", i, got, want)
+		}
+	}
+
+	// Two messages a session, one sent this morning: one more fits before
+	// the break, two in the afternoon, and the rest wait for Monday, past
+	// the horizon
+	cfg.Limits.MessagesPerSession = 2
+	clock.Set(clock.NewFake(from.Add(-2 * time.Minute)))
+	db.LogAction("message", "", true, nil)
+	slots = Project(cfg, db, cal, nil, "message", 5, from.Add(88*time.Minute), 24*time.Hour)
+	var got []string
+	for _, s := range slots {
+		got = append(got, s.Format("15:04"))
+	}
+	if strings.Join(got, " ") != "11:58 13:00 13:01" {
+		t.Errorf("messages at %v, want 11:58 13:00 13:01", got)
+	}
+
+	cfg.Limits.MessagesPerDay = 0
+	if slots := Project(cfg, db, cal, nil, "message", 1, from, 24*time.Hour); len(slots) != 0 {
+		t.Errorf("disabled action projected at %v", slots)
+	}
+}
@@ -0,0 +1,231 @@
+package seal
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"subspace/internal/config"
+	"subspace/internal/logger"
+)
+
+/*
+AT-REST ENCRYPTION
+
+db.json, the action log and the saved session hold names, profile URLs,
+message text and login cookies. With storage.encryption enabled they are
+sealed with AES-256-GCM before they reach the disk:
+
+	SEAL1 | 12-byte nonce | ciphertext and tag
+
+Each kind of file seals with its own label as additional data, so a sealed
+session can't be passed off as db.json. A file that doesn't start with the
+magic is plaintext and is read as before, which lets existing data be
+encrypted in place: it is sealed the next time it is written.
+
+The key is read once at startup, from an environment variable or a key
+file, and is process-wide like the clock. It is loaded even with
+encryption disabled, so sealed files can still be read, and are written
+back in plaintext, when encryption is turned off.
+*/
+
+// Labels of the sealed file kinds
+const (
+	LabelStorage   = "storage"
+	LabelActionLog = "action-log"
+	LabelSession   = "session"
+)
+
+// magic starts every sealed file
+var magic = []byte("SEAL1")
+
+// ErrNoKey is returned when reading a sealed file without a key
+var ErrNoKey = errors.New("file is encrypted but no key is configured (storage.encryption)")
+
+var (
+	mu      sync.RWMutex
+	aead    cipher.AEAD // nil without a key
+	enabled bool        // Whether writes are sealed
+)
+
+// Configure loads the key for cfg and sets whether writes are sealed.
+// Enabling encryption without a usable key is an error; with encryption
+// disabled a missing key is fine.
+func Configure(cfg config.EncryptionConfig) error {
+	key, source, err := loadKey(cfg)
+	if err != nil {
+		return err
+	}
+	if key == nil && cfg.Enabled {
+		return fmt.Errorf("storage.encryption is enabled but %s", source)
+	}
+	var a cipher.AEAD
+	if key != nil {
+		if a, err = newAEAD(key); err != nil {
+			return err
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	aead, enabled = a, cfg.Enabled
+	return nil
+}
+
+// loadKey reads the key from the environment or the key file, returning
+// nil and what was looked at if neither has one
+func loadKey(cfg config.EncryptionConfig) ([]byte, string, error) {
+	if cfg.KeyEnv != "" {
+		if v := os.Getenv(cfg.KeyEnv); v != "" {
+			key, err := decodeKey(v)
+			if err != nil {
+				return nil, "", fmt.Errorf("%s: %w", cfg.KeyEnv, err)
+			}
+			return key, "", nil
+		}
+	}
+	if cfg.KeyFile != "" {
+		info, err := os.Stat(cfg.KeyFile)
+		if err == nil {
+			if info.Mode().Perm()&0o077 != 0 {
+				logger.NewContext("seal").Warn("Encryption key file is readable by others", "path", cfg.KeyFile, "mode", info.Mode().Perm().String())
+			}
+			raw, err := os.ReadFile(cfg.KeyFile)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to read encryption key: %w", err)
+			}
+			key, err := decodeKey(string(raw))
+			if err != nil {
+				return nil, "", fmt.Errorf("%s: %w", cfg.KeyFile, err)
+			}
+			return key, "", nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, "", fmt.Errorf("failed to read encryption key: %w", err)
+		}
+	}
+
+	var looked []string
+	if cfg.KeyEnv != "" {
+		looked = append(looked, cfg.KeyEnv+" is unset")
+	}
+	if cfg.KeyFile != "" {
+		looked = append(looked, cfg.KeyFile+" does not exist")
+	}
+	if len(looked) == 0 {
+		looked = append(looked, "no key_env or key_file is set")
+	}
+	return nil, strings.Join(looked, " and "), nil
+}
+
+// decodeKey parses a 32-byte key written as base64 or hex
+func decodeKey(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if key, err := base64.StdEncoding.DecodeString(s); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	if key, err := hex.DecodeString(s); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	return nil, fmt.Errorf("encryption key must be 32 bytes, base64 or hex encoded (openssl rand -base64 32)")
+}
+
+// newAEAD returns AES-256-GCM for the key
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// Enabled reports whether writes are sealed
+func Enabled() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return enabled
+}
+
+// IsSealed reports whether data is a sealed file
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
+
+// Seal encrypts plain under label when encryption is enabled, and returns
+// it unchanged otherwise
+func Seal(plain []byte, label string) ([]byte, error) {
+	mu.RLock()
+	a, on := aead, enabled
+	mu.RUnlock()
+	if !on {
+		return plain, nil
+	}
+
+	nonce := make([]byte, a.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to draw nonce: %w", err)
+	}
+	out := make([]byte, 0, len(magic)+len(nonce)+len(plain)+a.Overhead())
+	out = append(append(out, magic...), nonce...)
+	return a.Seal(out, nonce, plain, []byte(label)), nil
+}
+
+// Open decrypts a file sealed under label. Plaintext is returned as is.
+func Open(data []byte, label string) ([]byte, error) {
+	if !IsSealed(data) {
+		return data, nil
+	}
+	mu.RLock()
+	a := aead
+	mu.RUnlock()
+	if a == nil {
+		return nil, ErrNoKey
+	}
+
+	data = data[len(magic):]
+	if len(data) < a.NonceSize() {
+		return nil, fmt.Errorf("sealed file is truncated")
+	}
+	plain, err := a.Open(nil, data[:a.NonceSize()], data[a.NonceSize():], []byte(label))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt (wrong key, or the file was altered): %w", err)
+	}
+	return plain, nil
+}
+
+// SealLine seals one line of a line-oriented file, such as an action log
+// segment, as base64 text so lines stay newline-separated
+func SealLine(plain []byte, label string) ([]byte, error) {
+	if !Enabled() {
+		return plain, nil
+	}
+	sealed, err := Seal(plain, label)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, base64.StdEncoding.EncodedLen(len(sealed)))
+	base64.StdEncoding.Encode(out, sealed)
+	return out, nil
+}
+
+// OpenLine reverses SealLine. A line holding JSON is plaintext and
+// returned as is.
+func OpenLine(line []byte, label string) ([]byte, error) {
+	if len(line) == 0 || line[0] == '{' {
+		return line, nil
+	}
+	sealed := make([]byte, base64.StdEncoding.DecodedLen(len(line)))
+	n, err := base64.StdEncoding.Decode(sealed, line)
+	if err != nil || !IsSealed(sealed[:n]) {
+		return nil, fmt.Errorf("line is neither JSON nor sealed")
+	}
+	return Open(sealed[:n], label)
+}
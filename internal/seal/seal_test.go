@@ -0,0 +1,95 @@
+package seal
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"subspace/internal/config"
+)
+
+// testKey is 32 bytes, base64 encoded
+const testKey = "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="
+
+func TestSealRoundTrip(t *testing.T) {
+	t.Setenv("TEST_SEAL_KEY", testKey)
+	if err := Configure(config.EncryptionConfig{Enabled: true, KeyEnv: "TEST_SEAL_KEY"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Configure(config.EncryptionConfig{}) })
+
+	plain := []byte(`{"profiles":{"p1":{"name":"Grace Hopper"}}}`)
+	sealed, err := Seal(plain, LabelStorage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSealed(sealed) || bytes.Contains(sealed, []byte("Grace")) {
+		t.Fatalf("sealed = %q", sealed)
+	}
+	if got, err := Open(sealed, LabelStorage); err != nil || !bytes.Equal(got, plain) {
+		t.Errorf("Open = %q, %v", got, err)
+	}
+	if _, err := Open(sealed, LabelSession); err == nil {
+		t.Error("sealed storage opened as a session")
+	}
+	sealed[len(sealed)-1] ^= 1
+	if _, err := Open(sealed, LabelStorage); err == nil {
+		t.Error("altered file opened")
+	}
+
+	line, err := SealLine([]byte(`{"action":"message"}`), LabelActionLog)
+	if err != nil || bytes.ContainsAny(line, "\n{") {
+		t.Fatalf("SealLine = %q, %v", line, err)
+	}
+	if got, err := OpenLine(line, LabelActionLog); err != nil || string(got) != `{"action":"message"}` {
+		t.Errorf("OpenLine = %q, %v", got, err)
+	}
+
+	// Plaintext still reads; sealed data needs the key
+	if got, err := Open(plain, LabelStorage); err != nil || !bytes.Equal(got, plain) {
+		t.Errorf("Open(plaintext) = %q, %v", got, err)
+	}
+	sealed, _ = Seal(plain, LabelStorage)
+	os.Unsetenv("TEST_SEAL_KEY")
+	if err := Configure(config.EncryptionConfig{KeyEnv: "TEST_SEAL_KEY"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(sealed, LabelStorage); err != ErrNoKey {
+		t.Errorf("Open without key = %v, want ErrNoKey", err)
+	}
+	if got, _ := Seal(plain, LabelStorage); !bytes.Equal(got, plain) {
+		t.Error("sealed with encryption disabled")
+	}
+}
+
+func TestConfigureKeySources(t *testing.T) {
+	t.Cleanup(func() { Configure(config.EncryptionConfig{}) })
+	dir := t.TempDir()
+	file := filepath.Join(dir, "key")
+	if err := os.WriteFile(file, []byte(strings.Repeat("ab", 32)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_SEAL_KEY", "")
+
+	tests := []struct {
+		cfg     config.EncryptionConfig
+		env     string
+		wantErr string
+	}{
+		{config.EncryptionConfig{Enabled: true, KeyFile: file}, "", ""},
+		{config.EncryptionConfig{Enabled: true, KeyEnv: "TEST_SEAL_KEY", KeyFile: file}, testKey, ""},
+		{config.EncryptionConfig{Enabled: true, KeyEnv: "TEST_SEAL_KEY"}, "", "TEST_SEAL_KEY is unset"},
+		{config.EncryptionConfig{Enabled: true, KeyFile: filepath.Join(dir, "missing")}, "", "does not exist"},
+		{config.EncryptionConfig{Enabled: true, KeyEnv: "TEST_SEAL_KEY"}, "too short", "32 bytes"},
+		{config.EncryptionConfig{KeyEnv: "TEST_SEAL_KEY"}, "", ""},
+	}
+	for _, tt := range tests {
+		os.Setenv("TEST_SEAL_KEY", tt.env)
+		err := Configure(tt.cfg)
+		if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("Configure(%+v) with key %q = %v, want %q", tt.cfg, tt.env, err, tt.wantErr)
+		}
+	}
+}
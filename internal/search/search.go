@@ -2,54 +2,90 @@ package search
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	"subspace/internal/clock"
 	"subspace/internal/browser"
 	"subspace/internal/config"
+	"subspace/internal/estop"
+	"subspace/internal/ids"
 	"subspace/internal/logger"
+	"subspace/internal/ratelimit"
 	"subspace/internal/stealth"
 	"subspace/internal/storage"
 )
 
 type Searcher struct {
-	browser browser.Controller
-	stealth *stealth.Stealth
-	storage *storage.Storage
-	config  config.SearchConfig
-	log     *logger.ContextLogger
+	browser   browser.Controller
+	stealth   *stealth.Stealth
+	storage   storage.Backend
+	config    config.SearchConfig
+	limits    config.LimitsConfig
+	limiter   *ratelimit.Limiter
+	log       *logger.ContextLogger
 }
 
 // New creates a new searcher
-func New(b browser.Controller, s *stealth.Stealth, storage *storage.Storage) *Searcher {
-	// Default search config
-	cfg := config.SearchConfig{
-		ResultsPerPage:      25,
-		MaxPages:            10,
-		DeduplicationWindow: 30,
-		DefaultKeywords:     []string{"software engineer"},
-	}
-
+func New(b browser.Controller, s *stealth.Stealth, storage storage.Backend, cfg config.SearchConfig, limits config.LimitsConfig) *Searcher {
 	return &Searcher{
-		browser: b,
-		stealth: s,
-		storage: storage,
-		config:  cfg,
-		log:     logger.NewContext("search"),
+		browser:   b,
+		stealth:   s,
+		storage:   storage,
+		config:    cfg,
+		limits:    limits,
+		limiter:   ratelimit.New(storage, limits),
+		log:       logger.NewContext("search"),
 	}
 }
 
+// CanSearchMore reports whether the daily search limit allows another
+// search (sliding window, see ratelimit)
+func (s *Searcher) CanSearchMore() bool {
+	return s.limiter.Allow("search")
+}
+
 // RunSearch executes a search with pagination
 func (s *Searcher) RunSearch(keywords string, maxPages int) error {
-	s.log.Info("Starting search", "keywords", keywords, "max_pages", maxPages)
+	return s.SearchByFilters(keywords, SearchFilters{MaxPages: maxPages})
+}
+
+// SearchByFilters executes a search with pagination, narrowed by filters.
+// Empty keywords search for any of the default keywords; pages are capped
+// at max_pages.
+func (s *Searcher) SearchByFilters(keywords string, filters SearchFilters) error {
+	if err := estop.Check("search"); err != nil {
+		return err
+	}
+	if keywords == "" {
+		keywords = strings.Join(s.config.DefaultKeywords, " OR ")
+	}
+	maxPages := filters.MaxPages
+	if maxPages <= 0 || maxPages > s.config.MaxPages {
+		maxPages = s.config.MaxPages
+	}
+	s.log.Info("Starting search",
+		"keywords", keywords,
+		"max_pages", maxPages,
+		"locations", filters.Locations,
+		"connection_level", filters.ConnectionLevel)
 	start := time.Now()
 
-	// Check if search is allowed (rate limiting via storage)
-	todaySearches := s.storage.GetActionCountToday("search")
-	s.log.Info("Search count today", "count", todaySearches)
+	// Check if search is allowed (sliding window, see ratelimit)
+	searchesLastDay := s.storage.GetActionCountSince("search", clock.Now().Add(-24*time.Hour))
+	s.log.Info("Current search count", "last_24h", searchesLastDay, "limit_daily", s.limits.SearchesPerDay)
+	if !s.limiter.Allow("search") {
+		s.log.Warn("Daily search limit reached, entering cooldown",
+			"count", searchesLastDay,
+			"limit", s.limits.SearchesPerDay)
+		cooldownUntil := clock.Now().Add(time.Duration(s.limits.CooldownMinutes) * time.Minute)
+		s.log.Info("Cooldown until", "time", cooldownUntil.Format(time.RFC3339))
+		return nil
+	}
 
 	// Step 1: Navigate to search page
 	s.log.Info("Navigating to search")
-	searchURL := s.buildSearchURL(keywords)
+	searchURL := s.buildSearchURL(keywords, filters.Locations)
 	
 	// In production: s.browser.Navigate(searchURL)
 	_ = searchURL // Used in production
@@ -62,8 +98,24 @@ func (s *Searcher) RunSearch(keywords string, maxPages int) error {
 	// Step 3: Process pages
 	profilesFound := 0
 	profilesNew := 0
+	profilesSampledOut := 0
+	profilesElsewhere := 0
+	pagesSkipped := 0
+	sampling := s.config.Discovery.Mode == "sample"
 
 	for page := 1; page <= maxPages; page++ {
+		// Sometimes flick past a page without reading it
+		if sampling && page > 1 && page < maxPages && s.stealth.ShouldProceed(s.config.Discovery.PageSkipRate) {
+			s.log.Info("Skipping search page", "page", page)
+			pagesSkipped++
+			s.stealth.RandomScroll()
+			if err := s.goToNextPage(); err != nil {
+				s.log.Warn("Failed to navigate to next page", "error", err)
+				break
+			}
+			continue
+		}
+
 		s.log.Info("Processing search page", "page", page, "max", maxPages)
 
 		// Parse results on current page
@@ -82,15 +134,28 @@ func (s *Searcher) RunSearch(keywords string, maxPages int) error {
 		for _, profile := range profiles {
 			profilesFound++
 
+			// Leave part of the page for a later session
+			if sampling && !s.stealth.ShouldProceed(s.config.Discovery.SampleRate) {
+				profilesSampledOut++
+				continue
+			}
+
+			// The location filter is broad (a region covers its
+			// neighbours), so results are checked against the table too
+			if !profile.InLocations(filters.Locations) {
+				profilesElsewhere++
+				continue
+			}
+
 			// Check for duplicates
-			if s.storage.ProfileExists(profile.ProfileURL) {
-				s.log.Debug("Profile already exists, skipping", "name", profile.Name)
+			if existing := s.storage.FindDuplicate(profile); existing != nil {
+				s.log.Debug("Profile already exists, skipping", "name", profile.Name, "existing_id", existing.ID)
 				continue
 			}
 
 			// Save new profile
 			profile.State = storage.StateDiscovered
-			profile.DiscoveredAt = time.Now()
+			profile.DiscoveredAt = clock.Now()
 			profile.SearchQuery = keywords
 
 			if err := s.storage.SaveProfile(profile); err != nil {
@@ -124,18 +189,24 @@ func (s *Searcher) RunSearch(keywords string, maxPages int) error {
 	logger.Timing("search", "run_search", start, nil)
 	s.log.Info("Search completed",
 		"profiles_found", profilesFound,
-		"profiles_new", profilesNew)
+		"profiles_new", profilesNew,
+		"profiles_sampled_out", profilesSampledOut,
+		"profiles_elsewhere", profilesElsewhere,
+		"pages_skipped", pagesSkipped)
 
 	return nil
 }
 
 // buildSearchURL constructs the search URL (mock)
-func (s *Searcher) buildSearchURL(keywords string) string {
+func (s *Searcher) buildSearchURL(keywords string, locations []string) string {
 	// EDUCATIONAL NOTE: In production, this would build a real LinkedIn search URL
 	// with proper query parameters, filters, etc.
 	
 	// Mock URL for demonstration
 	url := fmt.Sprintf("https://www.linkedin.com/search/results/people/?keywords=%s", keywords)
+	if len(locations) > 0 {
+		url += "&geo=" + strings.Join(locations, ",") // Production uses geoUrn IDs
+	}
 	s.log.Debug("Built search URL", "url", url)
 	return url
 }
@@ -145,6 +216,9 @@ func (s *Searcher) parseSearchResults() ([]*storage.Profile, error) {
 	s.log.Debug("Parsing search results")
 
 	mockProfiles := s.generateMockProfiles()
+	if len(mockProfiles) > s.config.ResultsPerPage {
+		mockProfiles = mockProfiles[:s.config.ResultsPerPage]
+	}
 
 	s.log.Debug("Parsed results", "count", len(mockProfiles))
 	return mockProfiles, nil
@@ -182,19 +256,43 @@ func (s *Searcher) generateMockProfiles() []*storage.Profile {
 
 	for i := 0; i < count; i++ {
 		profile := &storage.Profile{
-			ID:          fmt.Sprintf("mock-profile-%d-%d", time.Now().Unix(), i),
+			ID:          "mock-profile-" + ids.New(),
 			Name:        names[i%len(names)],
 			Title:       titles[i%len(titles)],
 			Company:     companies[i%len(companies)],
 			ProfileURL:  fmt.Sprintf("https://www.linkedin.com/in/mock-user-%d/", i),
 			State:       storage.StateDiscovered,
 		}
+		s.enrich(profile, i)
 		profiles = append(profiles, profile)
 	}
 
 	return profiles
 }
 
+// enrich captures what the operator shares with a search result (mock).
+// EDUCATIONAL NOTE: In production this reads the insight line of the result
+// card ("12 mutual connections", "You both studied at ...") and the
+// location line under the headline.
+func (s *Searcher) enrich(profile *storage.Profile, i int) {
+	groups := []string{"Gophers Slack", "Cloud Native Computing", "Women Who Go"}
+	schools := []string{"MIT", "Stanford University", "TU Munich"}
+	locations := []string{
+		"San Francisco Bay Area", "Greater London", "Berlin, Germany", "New York, United States",
+		"Bengaluru, Karnataka, India", "Madrid, Community of Madrid, Spain", "Remote",
+	}
+
+	profile.Location = locations[i%len(locations)]
+	shared := &storage.SharedContext{MutualConnections: (i*7 + 3) % 23}
+	if i%3 == 0 {
+		shared.Groups = []string{groups[i%len(groups)]}
+	}
+	if i%4 == 1 {
+		shared.Schools = []string{schools[i%len(schools)]}
+	}
+	profile.Shared = shared
+}
+
 // goToNextPage navigates to the next page of results
 func (s *Searcher) goToNextPage() error {
 	s.log.Debug("Navigating to next page")
@@ -205,21 +303,9 @@ func (s *Searcher) goToNextPage() error {
 	return nil
 }
 
-// SearchByFilters performs a filtered search (extended functionality)
-func (s *Searcher) SearchByFilters(keywords string, filters SearchFilters) error {
-	s.log.Info("Starting filtered search",
-		"keywords", keywords,
-		"location", filters.Location,
-		"connection_level", filters.ConnectionLevel)
-
-	// This would build a more complex search URL with filters
-	// For PoC, we just run the basic search
-	return s.RunSearch(keywords, filters.MaxPages)
-}
-
 // SearchFilters represents advanced search parameters
 type SearchFilters struct {
-	Location        string
+	Locations       []string // Cities, regions or countries; results elsewhere are dropped
 	ConnectionLevel string // "1st", "2nd", "3rd"
 	Company         string
 	Industry        string
@@ -0,0 +1,70 @@
+package search
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/stealth"
+	"subspace/internal/storage"
+)
+
+func TestSearchHonorsDailyLimit(t *testing.T) {
+	cfg := config.Defaults()
+	cfg.Limits.SearchesPerDay = 1
+	db, err := storage.New(filepath.Join(t.TempDir(), "db.json"), cfg.Storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(nil, stealth.New(cfg.Stealth, nil), db, cfg.Search, cfg.Limits)
+	if !s.CanSearchMore() {
+		t.Fatal("expected a search to be allowed")
+	}
+
+	db.LogAction("search", "", true, nil)
+	if s.CanSearchMore() {
+		t.Error("expected the daily limit to be reached")
+	}
+	if err := s.SearchByFilters("", SearchFilters{}); err != nil {
+		t.Fatal(err)
+	}
+	if n := db.GetActionCountToday("search"); n != 1 {
+		t.Errorf("searches today = %d, want the limit to stop the search", n)
+	}
+	if stats := db.GetStats(); stats.TotalProfiles != 0 {
+		t.Errorf("profiles = %d, want none discovered", stats.TotalProfiles)
+	}
+}
+
+func TestSampledDiscovery(t *testing.T) {
+	clock.Set(clock.NewFake(time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)))
+	defer clock.Set(clock.Real{})
+
+	discover := func(discovery config.DiscoveryConfig) int {
+		t.Helper()
+		cfg := config.Defaults()
+		cfg.Search.MaxPages = 3
+		cfg.Search.Discovery = discovery
+		db, err := storage.New(filepath.Join(t.TempDir(), "db.json"), cfg.Storage)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := New(nil, stealth.New(cfg.Stealth, nil), db, cfg.Search, cfg.Limits)
+		if err := s.RunSearch("golang", 3); err != nil {
+			t.Fatal(err)
+		}
+		return db.GetStats().TotalProfiles
+	}
+
+	if n := discover(config.DiscoveryConfig{Mode: "exhaustive"}); n == 0 {
+		t.Fatal("exhaustive discovery saved no profiles")
+	}
+	if n := discover(config.DiscoveryConfig{Mode: "sample", SampleRate: 1, PageSkipRate: 0}); n == 0 {
+		t.Error("sampling everything saved no profiles")
+	}
+	if n := discover(config.DiscoveryConfig{Mode: "sample", SampleRate: 0, PageSkipRate: 0}); n != 0 {
+		t.Errorf("sampling nothing saved %d profiles", n)
+	}
+}
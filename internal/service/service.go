@@ -0,0 +1,193 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+/*
+SERVICE MODULE
+
+Generates the files that run subspace unattended, so deployment doesn't need
+hand-written units:
+
+- systemd (Linux): a oneshot service running one automation cycle, and a
+  timer starting it every interval. A failed cycle is retried after
+  RestartDelay; the timer keeps cycles from overlapping.
+- launchd (macOS): a LaunchAgent plist starting a cycle every interval,
+  and again after RestartDelay if it failed.
+
+Both run as the given user in the working directory that relative paths in
+the config are resolved against, with the environment file loaded first.
+*/
+
+// Spec describes the service to generate
+type Spec struct {
+	Name         string        // Unit or label name, e.g. subspace-alice
+	Exec         []string      // Absolute binary path and its arguments
+	WorkDir      string        // Directory the config's relative paths start from
+	DataDir      string        // Written to by the service
+	User         string        // Runs as this user (systemd)
+	EnvFile      string        // Loaded before starting, if set
+	Interval     time.Duration // Time between automation cycles
+	RestartDelay time.Duration // Wait before retrying a failed cycle
+}
+
+// Validate checks the spec can produce a working service
+func (s Spec) Validate() error {
+	if s.Name == "" || len(s.Exec) == 0 || s.WorkDir == "" {
+		return fmt.Errorf("service name, command and working directory are required")
+	}
+	if s.Interval < time.Minute {
+		return fmt.Errorf("interval must be at least a minute")
+	}
+	return nil
+}
+
+// Systemd returns the service unit and the timer unit for spec
+func Systemd(s Spec) (unit, timer string, err error) {
+	if err := s.Validate(); err != nil {
+		return "", "", err
+	}
+	if unit, err = render(systemdUnit, s); err != nil {
+		return "", "", err
+	}
+	if timer, err = render(systemdTimer, s); err != nil {
+		return "", "", err
+	}
+	return unit, timer, nil
+}
+
+// Launchd returns the LaunchAgent plist for spec
+func Launchd(s Spec) (string, error) {
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+	return render(launchdPlist, s)
+}
+
+var funcs = template.FuncMap{
+	"seconds": func(d time.Duration) int { return int(d.Seconds()) },
+	"systemdArgs": func(args []string) string {
+		quoted := make([]string, len(args))
+		for i, arg := range args {
+			quoted[i] = systemdQuote(arg)
+		}
+		return strings.Join(quoted, " ")
+	},
+	"shellQuote": shellQuote,
+	"xml":        xmlEscape,
+}
+
+// render executes one of the templates below
+func render(text string, s Spec) (string, error) {
+	tmpl, err := template.New("service").Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, s); err != nil {
+		return "", fmt.Errorf("failed to render service: %w", err)
+	}
+	return out.String(), nil
+}
+
+// systemdQuote quotes an ExecStart argument when it needs it
+func systemdQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\"'\\$%;") {
+		return s
+	}
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `$$`, `%`, `%%`).Replace(s)
+	return `"` + s + `"`
+}
+
+// shellQuote quotes s for sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// xmlEscape escapes s for a plist string
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+}
+
+const systemdUnit = `[Unit]
+Description=Subspace automation cycle ({{.Name}})
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=oneshot
+{{- if .User}}
+User={{.User}}
+{{- end}}
+WorkingDirectory={{.WorkDir}}
+{{- if .EnvFile}}
+EnvironmentFile={{.EnvFile}}
+{{- end}}
+ExecStart={{systemdArgs .Exec}}
+Restart=on-failure
+RestartSec={{seconds .RestartDelay}}
+{{- if .DataDir}}
+ReadWritePaths={{.DataDir}}
+{{- end}}
+NoNewPrivileges=true
+PrivateTmp=true
+ProtectSystem=full
+
+[Install]
+WantedBy=multi-user.target
+`
+
+const systemdTimer = `[Unit]
+Description=Run {{.Name}} every {{.Interval}}
+
+[Timer]
+OnBootSec=5min
+OnUnitInactiveSec={{seconds .Interval}}
+Persistent=true
+
+[Install]
+WantedBy=timers.target
+`
+
+const launchdPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>{{xml .Name}}</string>
+	<key>ProgramArguments</key>
+	<array>
+{{- if .EnvFile}}
+		<string>/bin/sh</string>
+		<string>-c</string>
+		<string>set -a; . {{xml (shellQuote .EnvFile)}}; set +a; exec "$0" "$@"</string>
+{{- end}}
+{{- range .Exec}}
+		<string>{{xml .}}</string>
+{{- end}}
+	</array>
+	<key>WorkingDirectory</key>
+	<string>{{xml .WorkDir}}</string>
+	<key>StartInterval</key>
+	<integer>{{seconds .Interval}}</integer>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>ThrottleInterval</key>
+	<integer>{{seconds .RestartDelay}}</integer>
+	<key>StandardOutPath</key>
+	<string>{{xml .WorkDir}}/{{xml .Name}}.log</string>
+	<key>StandardErrorPath</key>
+	<string>{{xml .WorkDir}}/{{xml .Name}}.log</string>
+</dict>
+</plist>
+`
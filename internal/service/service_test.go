@@ -0,0 +1,64 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func testSpec() Spec {
+	return Spec{
+		Name:         "subspace-alice",
+		Exec:         []string{"/usr/local/bin/subspace", "-config", "/srv/sub space/config.yaml"},
+		WorkDir:      "/srv/sub space",
+		DataDir:      "/srv/sub space/data",
+		User:         "alice",
+		EnvFile:      "/srv/sub space/.env",
+		Interval:     30 * time.Minute,
+		RestartDelay: 5 * time.Minute,
+	}
+}
+
+func TestSystemd(t *testing.T) {
+	unit, timer, err := Systemd(testSpec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"User=alice",
+		"EnvironmentFile=/srv/sub space/.env",
+		`ExecStart=/usr/local/bin/subspace -config "/srv/sub space/config.yaml"`,
+		"Restart=on-failure",
+		"RestartSec=300",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("unit is missing %q:\n%s", want, unit)
+		}
+	}
+	if !strings.Contains(timer, "OnUnitInactiveSec=1800") {
+		t.Errorf("timer:\n%s", timer)
+	}
+
+	spec := testSpec()
+	spec.Interval = time.Second
+	if _, _, err := Systemd(spec); err == nil {
+		t.Error("expected an error for a sub-minute interval")
+	}
+}
+
+func TestLaunchd(t *testing.T) {
+	plist, err := Launchd(testSpec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<string>subspace-alice</string>",
+		"<string>set -a; . '/srv/sub space/.env'; set +a; exec \"$0\" \"$@\"</string>",
+		"<integer>1800</integer>",
+		"<key>SuccessfulExit</key>",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("plist is missing %q:\n%s", want, plist)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package simulate
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"subspace/internal/config"
+	"subspace/internal/storage"
+)
+
+/*
+SIMULATION MODULE - EDUCATIONAL IMPLEMENTATION
+
+The PoC never talks to a real network, so connection acceptance is simulated.
+A pluggable AcceptanceModel decides, at each acceptance check, whether a
+requested profile has accepted by now.
+
+MODELS:
+- fixed:     every check accepts each pending request with a flat probability
+             (the original PoC behaviour, 20% by default)
+- seniority: each profile is assigned an acceptance rate by seniority of its
+             title and a log-normal time-to-accept. The outcome is derived
+             from the profile ID, so repeated checks agree with each other and
+             demo runs produce a realistic funnel (most acceptances within a
+             day or two, executives rarely accepting).
+*/
+
+// AcceptanceModel decides whether a requested profile has accepted
+type AcceptanceModel interface {
+	// Accepted reports whether profile has accepted the request by now
+	Accepted(profile *storage.Profile, now time.Time) bool
+	// Name identifies the model in logs
+	Name() string
+}
+
+// Seniority levels assigned by ClassifyTitle
+const (
+	SeniorityJunior    = "junior"
+	SeniorityMid       = "mid"
+	SenioritySenior    = "senior"
+	SeniorityExecutive = "executive"
+)
+
+// New returns the acceptance model selected in configuration
+func New(cfg config.SimulationConfig) (AcceptanceModel, error) {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	switch cfg.AcceptanceModel {
+	case "", "fixed":
+		return &FixedModel{
+			Rate: cfg.AcceptanceRate,
+			rng:  rand.New(rand.NewSource(seed)),
+		}, nil
+	case "seniority":
+		return &SeniorityModel{
+			Rates:  cfg.SeniorityRates,
+			Median: time.Duration(cfg.MedianAcceptHours * float64(time.Hour)),
+			Spread: cfg.AcceptSpread,
+			Seed:   seed,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown acceptance model: %s", cfg.AcceptanceModel)
+	}
+}
+
+// FixedModel accepts each pending request with a flat probability per check
+type FixedModel struct {
+	Rate float64
+	mu   sync.Mutex
+	rng  *rand.Rand
+}
+
+// Name identifies the model
+func (m *FixedModel) Name() string { return "fixed" }
+
+// Accepted rolls the dice once per call
+func (m *FixedModel) Accepted(_ *storage.Profile, _ time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.rng.Float64() < m.Rate
+}
+
+// SeniorityModel accepts requests at a per-seniority rate after a
+// log-normally distributed delay
+type SeniorityModel struct {
+	Rates  map[string]float64 // Seniority -> eventual acceptance probability
+	Median time.Duration      // Median time from request to acceptance
+	Spread float64            // Log-normal sigma; larger means a longer tail
+	Seed   int64
+}
+
+// Name identifies the model
+func (m *SeniorityModel) Name() string { return "seniority" }
+
+// Accepted reports whether the profile's simulated acceptance time has passed
+func (m *SeniorityModel) Accepted(profile *storage.Profile, now time.Time) bool {
+	if profile.RequestedAt == nil {
+		return false
+	}
+	accepts, delay := m.Outcome(profile)
+	return accepts && now.Sub(*profile.RequestedAt) >= delay
+}
+
+// Outcome returns whether the profile will ever accept and how long after
+// the request it does so. It is deterministic for a given seed and profile.
+func (m *SeniorityModel) Outcome(profile *storage.Profile) (bool, time.Duration) {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d:%s", m.Seed, profile.ID)
+	rng := rand.New(rand.NewSource(int64(h.Sum64())))
+
+	rate, ok := m.Rates[ClassifyTitle(profile.Title)]
+	if !ok {
+		rate = m.Rates[SeniorityMid]
+	}
+	accepts := rng.Float64() < rate
+
+	// Log-normal: median * e^(sigma * N(0,1))
+	delay := time.Duration(float64(m.Median) * math.Exp(m.Spread*rng.NormFloat64()))
+	return accepts, delay
+}
+
+// seniorityKeywords are checked in order; the first level with a matching
+// keyword wins
+var seniorityKeywords = []struct {
+	level    string
+	keywords []string
+}{
+	{SeniorityExecutive, []string{"chief", "ceo", "cto", "cfo", "coo", "cio", "ciso", "founder", "president", "vp", "vice president", "owner", "partner", "managing director"}},
+	{SenioritySenior, []string{"senior", "sr", "lead", "principal", "staff", "head", "director", "manager", "architect"}},
+	{SeniorityJunior, []string{"junior", "jr", "intern", "trainee", "graduate", "apprentice", "entry", "student", "associate"}},
+}
+
+// ClassifyTitle maps a job title to a seniority level, defaulting to mid
+func ClassifyTitle(title string) string {
+	words := " " + strings.Join(strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !('a' <= r && r <= 'z') && !('0' <= r && r <= '9')
+	}), " ") + " "
+
+	for _, level := range seniorityKeywords {
+		for _, kw := range level.keywords {
+			if strings.Contains(words, " "+kw+" ") {
+				return level.level
+			}
+		}
+	}
+	return SeniorityMid
+}
@@ -0,0 +1,90 @@
+package simulate
+
+import (
+	"testing"
+	"time"
+
+	"subspace/internal/config"
+	"subspace/internal/storage"
+)
+
+func TestNew(t *testing.T) {
+	for name, want := range map[string]string{"": "fixed", "fixed": "fixed", "seniority": "seniority"} {
+		model, err := New(config.SimulationConfig{AcceptanceModel: name, Seed: 1})
+		if err != nil || model.Name() != want {
+			t.Errorf("New(%q) = %v, %v, want the %s model", name, model, err, want)
+		}
+	}
+	if _, err := New(config.SimulationConfig{AcceptanceModel: "coin"}); err == nil {
+		t.Error("unknown model accepted")
+	}
+}
+
+func TestFixedModel(t *testing.T) {
+	model, _ := New(config.SimulationConfig{AcceptanceRate: 0.2, Seed: 7})
+	accepted := 0
+	for i := 0; i < 10000; i++ {
+		if model.Accepted(&storage.Profile{}, time.Time{}) {
+			accepted++
+		}
+	}
+	if accepted < 1800 || accepted > 2200 {
+		t.Errorf("accepted %d of 10000 checks at a 20%% rate", accepted)
+	}
+}
+
+func TestSeniorityModel(t *testing.T) {
+	cfg := config.SimulationConfig{
+		AcceptanceModel:   "seniority",
+		SeniorityRates:    map[string]float64{SeniorityJunior: 1, SeniorityMid: 1, SeniorityExecutive: 0},
+		MedianAcceptHours: 24,
+		AcceptSpread:      0.5,
+		Seed:              42,
+	}
+	model, _ := New(cfg)
+	requested := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	profile := &storage.Profile{ID: "p1", Title: "Software Engineer", RequestedAt: &requested}
+
+	// The outcome is the same at every check, and only comes after the delay
+	sm := model.(*SeniorityModel)
+	accepts, delay := sm.Outcome(profile)
+	if again, d := sm.Outcome(profile); again != accepts || d != delay {
+		t.Error("outcome differs between checks")
+	}
+	if !accepts {
+		t.Fatal("mid-level profile at a 100% rate never accepts")
+	}
+	if model.Accepted(profile, requested.Add(delay-time.Second)) {
+		t.Error("accepted before the delay")
+	}
+	if !model.Accepted(profile, requested.Add(delay)) {
+		t.Error("not accepted after the delay")
+	}
+
+	ceo := &storage.Profile{ID: "p2", Title: "CEO", RequestedAt: &requested}
+	if model.Accepted(ceo, requested.AddDate(1, 0, 0)) {
+		t.Error("executive accepted at a 0% rate")
+	}
+	if model.Accepted(&storage.Profile{ID: "p3"}, requested) {
+		t.Error("accepted a profile that was never requested")
+	}
+}
+
+func TestClassifyTitle(t *testing.T) {
+	tests := map[string]string{
+		"Chief Technology Officer":    SeniorityExecutive,
+		"VP, Engineering":             SeniorityExecutive,
+		"Sr. Software Engineer":       SenioritySenior,
+		"Engineering Manager":         SenioritySenior,
+		"Junior Developer":            SeniorityJunior,
+		"Software Engineering Intern": SeniorityJunior,
+		"Software Engineer":           SeniorityMid,
+		"Leader of Vipers":            SeniorityMid, // Whole words only
+		"":                            SeniorityMid,
+	}
+	for title, want := range tests {
+		if got := ClassifyTitle(title); got != want {
+			t.Errorf("ClassifyTitle(%q) = %s, want %s", title, got, want)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package stealth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"subspace/internal/clock"
+)
+
+// ErrActionTimeout is wrapped by CheckBudget once the action in flight has
+// taken longer than its budget
+var ErrActionTimeout = errors.New("action timed out")
+
+// actionBudget returns how long an action may take, pauses included; 0
+// leaves it unlimited
+func (s *Stealth) actionBudget(action string) time.Duration {
+	return time.Duration(s.config.ActionTimeoutSeconds[action]) * time.Second
+}
+
+// CheckBudget returns an error wrapping ErrActionTimeout if the action in
+// flight has overrun its budget, nil otherwise. Mouse movements, clicks and
+// typing check it themselves; flows check it before a step that can't be
+// undone, so an overrun action stops before it goes out.
+func (s *Stealth) CheckBudget() error {
+	a := s.inFlight
+	if a == nil || a.deadline.IsZero() || !clock.Now().After(a.deadline) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s took longer than its %s budget", ErrActionTimeout, a.Action, s.actionBudget(a.Action))
+}
@@ -0,0 +1,39 @@
+package stealth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+)
+
+func TestCheckBudget(t *testing.T) {
+	s := newBenchStealth(t)
+	s.config.ActionTimeoutSeconds = map[string]int{"connection": 60}
+	fake := clock.Get().(*clock.Fake)
+
+	s.BeginAction("message", "p1", "")
+	fake.Advance(time.Hour)
+	if err := s.CheckBudget(); err != nil {
+		t.Errorf("unbudgeted action: %v", err)
+	}
+	s.EndAction(nil)
+
+	s.BeginAction("connection", "p1", "")
+	fake.Advance(30 * time.Second)
+	if err := s.CheckBudget(); err != nil {
+		t.Errorf("within budget: %v", err)
+	}
+	fake.Advance(31 * time.Second)
+	if err := s.CheckBudget(); !errors.Is(err, ErrActionTimeout) {
+		t.Errorf("over budget: got %v", err)
+	}
+	if err := s.TypeHumanLike("input", "hello"); !errors.Is(err, ErrActionTimeout) {
+		t.Errorf("typing over budget: got %v", err)
+	}
+	s.EndAction(nil)
+	if err := s.CheckBudget(); err != nil {
+		t.Errorf("after the action ended: %v", err)
+	}
+}
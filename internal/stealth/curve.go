@@ -0,0 +1,174 @@
+package stealth
+
+import (
+	"math"
+	"sync"
+
+	"subspace/internal/config"
+)
+
+/*
+CURVE MATH
+
+Mouse paths are cubic Bézier curves sampled at 10-100 evenly spaced steps.
+The Bernstein weights for a given step count never change, so they are
+computed once and shared, leaving four multiply-adds per coordinate per
+step. Path buffers are pooled so that moving the mouse on many concurrent
+pages doesn't allocate on every movement.
+
+Randomized control points could otherwise push a path off the page or
+through negative coordinates, so endpoints and control points are clamped
+to the viewport before sampling.
+
+Targets are given as fractions of the viewport (MoveTo, ClickAt), so they
+stay on the same part of the page whatever window size was picked, and the
+control point jitter scales with the viewport too. Sampled points are
+snapped to the device pixel grid: on a standard display a real mouse
+reports whole CSS pixels, on a HiDPI one multiples of 1/devicePixelRatio.
+
+Benchmarks live in stealth_bench_test.go:
+
+	go test -bench . -benchmem ./internal/stealth
+*/
+
+const (
+	minMouseSteps = 10
+	maxMouseSteps = 100
+)
+
+// bezierWeights caches the Bernstein weights (1-t)³, 3(1-t)²t, 3(1-t)t², t³
+// for every step of a path, indexed by step count and filled on first use
+var (
+	bezierWeights     [maxMouseSteps + 1][][4]float64
+	bezierWeightsOnce [maxMouseSteps + 1]sync.Once
+)
+
+// weightsFor returns the Bernstein weights for a path with the given number
+// of steps. Step counts outside the cached range are computed on the fly.
+func weightsFor(steps int) [][4]float64 {
+	if steps < minMouseSteps || steps > maxMouseSteps {
+		return computeWeights(steps)
+	}
+	bezierWeightsOnce[steps].Do(func() {
+		bezierWeights[steps] = computeWeights(steps)
+	})
+	return bezierWeights[steps]
+}
+
+// computeWeights evaluates the Bernstein weights at steps+1 evenly spaced t
+func computeWeights(steps int) [][4]float64 {
+	if steps < 1 {
+		steps = 1
+	}
+	w := make([][4]float64, steps+1)
+	for i := range w {
+		t := float64(i) / float64(steps)
+		u := 1 - t
+		w[i] = [4]float64{u * u * u, 3 * u * u * t, 3 * u * t * t, t * t * t}
+	}
+	return w
+}
+
+// bezierPath appends the points of the cubic Bézier curve p0..p3, sampled
+// at steps+1 evenly spaced t values, to buf[:0]
+func bezierPath(buf []Point, p0, p1, p2, p3 Point, steps int) []Point {
+	buf = buf[:0]
+	for _, w := range weightsFor(steps) {
+		buf = append(buf, Point{
+			X: w[0]*p0.X + w[1]*p1.X + w[2]*p2.X + w[3]*p3.X,
+			Y: w[0]*p0.Y + w[1]*p1.Y + w[2]*p2.Y + w[3]*p3.Y,
+		})
+	}
+	return buf
+}
+
+// pathPool recycles path buffers large enough for the longest mouse path
+var pathPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]Point, 0, maxMouseSteps+1)
+		return &buf
+	},
+}
+
+// getPath borrows a path buffer from the pool
+func getPath() *[]Point {
+	return pathPool.Get().(*[]Point)
+}
+
+// putPath returns a path buffer to the pool
+func putPath(buf *[]Point) {
+	*buf = (*buf)[:0]
+	pathPool.Put(buf)
+}
+
+// viewportMargin keeps paths a few pixels inside the page edges, away from
+// the (0,0) corner and the window border
+const viewportMargin = 2
+
+// curveJitter is how far control points stray from the straight line, as
+// a share of the viewport width (about 50 pixels on a 1280 pixel page)
+const curveJitter = 0.04
+
+// Viewport is the visible page area mouse paths must stay within, in CSS
+// pixels
+type Viewport struct {
+	Width, Height float64
+	DPR           float64 `json:",omitempty"` // devicePixelRatio; 0 is 1
+}
+
+// defaultViewport assumes the smallest configured window until the real
+// size is known, so paths fit whatever size is picked later
+func defaultViewport(cfg config.StealthConfig) Viewport {
+	if cfg.ViewportWidthMin > 0 && cfg.ViewportHeightMin > 0 {
+		return Viewport{float64(cfg.ViewportWidthMin), float64(cfg.ViewportHeightMin), cfg.DevicePixelRatio}
+	}
+	return Viewport{1280, 720, cfg.DevicePixelRatio}
+}
+
+// At returns the point at the given fractions of the viewport's width and
+// height, e.g. At(0.5, 0.5) is its center
+func (v Viewport) At(fx, fy float64) Point {
+	return v.Clamp(Point{fx * v.Width, fy * v.Height})
+}
+
+// Snap moves p, inside the viewport margins, to the nearest position a
+// pointer can report there: whole device pixels, which are 1/DPR of a CSS
+// pixel
+func (v Viewport) Snap(p Point) Point {
+	ratio := v.DPR
+	if ratio <= 0 {
+		ratio = 1
+	}
+	snap := func(x, hi float64) float64 {
+		lo := math.Ceil(viewportMargin*ratio) / ratio
+		hi = math.Floor((hi-1-viewportMargin)*ratio) / ratio
+		return clampFloat(math.Round(x*ratio)/ratio, lo, hi)
+	}
+	return Point{X: snap(p.X, v.Width), Y: snap(p.Y, v.Height)}
+}
+
+// Clamp moves p to the nearest point inside the viewport margins
+func (v Viewport) Clamp(p Point) Point {
+	return Point{
+		X: clampFloat(p.X, viewportMargin, v.Width-1-viewportMargin),
+		Y: clampFloat(p.Y, viewportMargin, v.Height-1-viewportMargin),
+	}
+}
+
+// clampFloat limits x to [lo, hi]; hi wins if the range is empty
+func clampFloat(x, lo, hi float64) float64 {
+	if x < lo {
+		x = lo
+	}
+	if x > hi {
+		x = hi
+	}
+	return x
+}
+
+// SetViewport records the page size after it changes so mouse paths stay
+// on the page
+func (s *Stealth) SetViewport(width, height float64) {
+	s.viewport = Viewport{width, height, s.viewport.DPR}
+	s.cursor = s.viewport.Clamp(s.cursor)
+}
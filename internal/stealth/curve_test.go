@@ -0,0 +1,55 @@
+package stealth
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestMousePathStaysInViewport throws random targets at random viewports,
+// many of them far off the page, and checks that no sampled point leaves it
+func TestMousePathStaysInViewport(t *testing.T) {
+	s := newBenchStealth(t)
+	const eps = 1e-9 // Rounding in the Bernstein weights
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 2000; i++ {
+		w, h := 320+rng.Float64()*1600, 240+rng.Float64()*900
+		s.SetViewport(w, h)
+
+		// Half the targets are off-screen, including negative coordinates
+		target := Point{rng.Float64()*3*w - w, rng.Float64()*3*h - h}
+		if err := s.MoveMouse(target.X, target.Y); err != nil {
+			t.Fatal(err)
+		}
+
+		path := s.mousePath(nil, s.getCurrentMousePosition(), target)
+		for _, p := range append(path, s.getCurrentMousePosition()) {
+			if p.X < viewportMargin-eps || p.Y < viewportMargin-eps || p.X > w-1-viewportMargin+eps || p.Y > h-1-viewportMargin+eps {
+				t.Fatalf("viewport %vx%v, target %+v: point %+v off the page", w, h, target, p)
+			}
+		}
+	}
+}
+
+// TestViewportRelativeTargets checks that fractional targets follow the
+// viewport size and that paths land on the device pixel grid
+func TestViewportRelativeTargets(t *testing.T) {
+	s := newBenchStealth(t)
+	for _, size := range []Point{{1200, 800}, {1920, 1080}} {
+		s.SetViewport(size.X, size.Y)
+		if err := s.MoveTo(0.5, 0.25); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := s.getCurrentMousePosition(), (Point{size.X / 2, size.Y / 4}); got != want {
+			t.Errorf("viewport %v: MoveTo(0.5, 0.25) ended at %+v, want %+v", size, got, want)
+		}
+	}
+
+	s.viewport.DPR = 2
+	for _, p := range s.mousePath(nil, Point{10.3, 20.7}, Point{811.1, 407.9}) {
+		if p.X*2 != math.Trunc(p.X*2) || p.Y*2 != math.Trunc(p.Y*2) {
+			t.Fatalf("point %+v is off the half-pixel grid of a 2x display", p)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package stealth
+
+import (
+	"time"
+
+	"subspace/internal/logger"
+)
+
+// MoveTo moves the mouse to the point at the given fractions of the
+// viewport's width and height, so a target stays on the same part of the
+// page whatever its size
+func (s *Stealth) MoveTo(fx, fy float64) error {
+	to := s.viewport.At(fx, fy)
+	return s.MoveMouse(to.X, to.Y)
+}
+
+// ClickAt clicks the point at the given fractions of the viewport's width
+// and height
+func (s *Stealth) ClickAt(fx, fy float64) error {
+	to := s.viewport.At(fx, fy)
+	return s.Click(to.X, to.Y)
+}
+
+// Click moves to a point, in CSS pixels, and clicks it the way the
+// persona's input device would: a pointer travels there and settles before
+// pressing, a finger lands and taps. The press lasts the persona's click
+// dwell.
+func (s *Stealth) Click(x, y float64) error {
+	s.log.Debug("Clicking", "x", x, "y", y, "device", s.persona.InputDevice)
+	if err := s.CheckBudget(); err != nil {
+		return err
+	}
+	start := time.Now()
+
+	target := s.viewport.Snap(s.viewport.Clamp(Point{x, y}))
+	if target != s.getCurrentMousePosition() {
+		if err := s.MoveMouse(target.X, target.Y); err != nil {
+			logger.Timing("stealth", "click", start, err)
+			return err
+		}
+	}
+
+	if s.persona.Hovers() {
+		// Settle on the target before pressing
+		s.pause(time.Duration(s.randomInt("settle", 40, 120)) * time.Millisecond)
+	}
+
+	// EDUCATIONAL NOTE: In production:
+	// mouse/trackpad: s.page.Mouse.Down(proto.InputMouseButtonLeft, 1), then Up
+	// touch:          s.page.Touch.Start(...), then End
+	s.pause(time.Duration(s.randomInt("click_dwell", s.persona.ClickDwellMin, s.persona.ClickDwellMax)) * time.Millisecond)
+
+	logger.Timing("stealth", "click", start, nil)
+	return nil
+}
+
+// reachWithFinger puts a finger down on the target. Unlike a pointer the
+// hand moves above the screen, so no events fire on the way; it only takes
+// time proportional to the distance.
+func (s *Stealth) reachWithFinger(to Point) {
+	from := s.getCurrentMousePosition()
+	steps := s.calculateSteps(from.X, from.Y, to.X, to.Y)
+	s.pause(time.Duration(150+2*steps+s.randomInt("reach", 0, 150)) * time.Millisecond)
+	s.cursor = to
+}
+
+// PasteText inserts text in one go the way a person pastes it: a pause to
+// fetch it from elsewhere, the paste shortcut, then a glance at the result.
+// No per-character key events fire, so URLs arrive exactly as copied.
+func (s *Stealth) PasteText(selector, text string) error {
+	s.log.Debug("Pasting text", "length", len(text))
+	if err := s.CheckBudget(); err != nil {
+		return err
+	}
+	start := time.Now()
+
+	// Switching to the source and copying
+	s.pause(time.Duration(s.randomInt("paste_fetch", 800, 2500)) * time.Millisecond)
+
+	// EDUCATIONAL NOTE: In production:
+	// s.page.InsertText(text) after pressing Ctrl/Cmd+V, so the page sees a
+	// paste event rather than keystrokes
+	s.pause(time.Duration(s.randomInt("paste_keys", 80, 200)) * time.Millisecond)
+
+	s.ShortPause()
+	logger.Timing("stealth", "paste", start, nil)
+	return nil
+}
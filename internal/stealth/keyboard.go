@@ -0,0 +1,213 @@
+package stealth
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+/*
+KEYBOARD MODELS
+
+Text reaches an input the way the persona's keyboard (personas.<name>.
+keyboard) would produce it, one character at a time:
+
+  - key: ASCII, and letters with a key of their own on the layout (ä on a
+    German keyboard, Cyrillic on a Russian one), take one keystroke
+  - dead key: accented letters the layout has a dead key for take two, the
+    accent then the letter (´ then e gives é)
+  - long press: other accented Latin letters are picked from the accent
+    menu a held key opens
+  - IME: Japanese and Chinese are typed as their reading in Latin letters
+    into an input method, which shows the composition, converts it on
+    space, sometimes after another candidate is picked, and commits it.
+    Korean is composed as it is typed, one key per jamo.
+  - insert: emoji, and scripts the keyboard has no way to type, come from
+    the character picker and are inserted whole
+
+Text is normalized to NFC and split into clusters first, so a letter and
+its combining marks, or emoji joined with ZWJ or modifiers, are never sent
+as separate halves.
+*/
+
+// Stroke kinds
+const (
+	strokeKey       = "key"
+	strokeDead      = "dead_key"
+	strokeLongPress = "long_press"
+	strokeInsert    = "insert"
+)
+
+// IME kinds
+const (
+	imeJapanese = "ja"
+	imeChinese  = "zh"
+	imeKorean   = "ko"
+)
+
+// keyboard is a layout and input method
+type keyboard struct {
+	letters string              // Non-ASCII letters with their own key, lower case
+	script  *unicode.RangeTable // Alphabet the layout types instead of Latin, if any
+	dead    string              // Combining marks the layout has dead keys for
+	ime     string              // Input method composing CJK text, if any
+	typos   []rune              // Keys a slip hits; ASCII letters if empty
+}
+
+// keyboards are the models selectable with personas.<name>.keyboard.
+// Typing Latin text works on all of them, as with a layout switch.
+var keyboards = map[string]keyboard{
+	"us": {},
+	"uk": {},
+	"de": {letters: "äöüß", dead: "\u0301\u0300\u0302"}, // ´ ` ^
+	"fr": {letters: "éèàùç", dead: "\u0302\u0308"},      // ^ ¨
+	"es": {letters: "ñç", dead: "\u0301\u0300\u0302\u0308"},
+	"ru": {letters: "ё", script: unicode.Cyrillic, typos: []rune("йцукенгшщзхфывапролджэячсмитьбю")},
+	"ja": {ime: imeJapanese},
+	"zh": {ime: imeChinese},
+	"ko": {ime: imeKorean, typos: []rune("ㅂㅈㄷㄱㅅㅛㅕㅑㅐㅔㅁㄴㅇㄹㅎㅗㅓㅏㅣㅋㅌㅊㅍㅠㅜㅡ")},
+}
+
+// keyboardFor returns the named model, US for unknown names. Names are
+// validated with the config.
+func keyboardFor(name string) keyboard {
+	if kb, ok := keyboards[name]; ok {
+		return kb
+	}
+	return keyboards["us"]
+}
+
+// clusters normalizes text to NFC and splits it into the units typed as
+// one: a character with its combining marks, variation selectors and
+// skin tones, characters joined by ZWJ, and flag pairs
+func clusters(text string) []string {
+	runes := []rune(norm.NFC.String(text))
+	var out []string
+	for i := 0; i < len(runes); {
+		j := i + 1
+		if isRegionalIndicator(runes[i]) && j < len(runes) && isRegionalIndicator(runes[j]) {
+			j++
+		}
+		for j < len(runes) {
+			switch r := runes[j]; {
+			case unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || isVariation(r) || isSkinTone(r):
+				j++
+				continue
+			case r == '\u200d' && j+1 < len(runes): // ZWJ
+				j += 2
+				continue
+			}
+			break
+		}
+		out = append(out, string(runes[i:j]))
+		i = j
+	}
+	return out
+}
+
+// isRegionalIndicator reports whether r is half of a flag
+func isRegionalIndicator(r rune) bool { return r >= 0x1F1E6 && r <= 0x1F1FF }
+
+// isVariation reports whether r is a variation selector
+func isVariation(r rune) bool { return r >= 0xFE00 && r <= 0xFE0F }
+
+// isSkinTone reports whether r is an emoji skin tone modifier
+func isSkinTone(r rune) bool { return r >= 0x1F3FB && r <= 0x1F3FF }
+
+// stroke returns how the keyboard types a cluster outside an IME
+// composition, and for a dead key the number of keystrokes
+func (kb keyboard) stroke(cluster string) (string, int) {
+	runes := []rune(cluster)
+	r := runes[0]
+	if len(runes) == 1 {
+		lower := unicode.ToLower(r)
+		switch {
+		case r < unicode.MaxASCII && unicode.IsPrint(r), r == '\n', r == '\t':
+			return strokeKey, 1
+		case strings.ContainsRune(kb.letters, lower):
+			return strokeKey, 1
+		case kb.script != nil && unicode.Is(kb.script, r):
+			return strokeKey, 1
+		case kb.ime != "" && isFullWidth(r):
+			return strokeKey, 1 // The IME turns punctuation keys full width
+		}
+	}
+
+	// An accented Latin letter: its base letter and one mark
+	base := []rune(norm.NFD.String(cluster))
+	if len(base) == 2 && base[0] < unicode.MaxASCII && unicode.IsLetter(base[0]) && unicode.Is(unicode.Mn, base[1]) {
+		if strings.ContainsRune(kb.dead, base[1]) {
+			return strokeDead, 2
+		}
+		return strokeLongPress, 1
+	}
+	return strokeInsert, 1
+}
+
+// isFullWidth reports whether r is CJK punctuation or a full-width form
+// of an ASCII character
+func isFullWidth(r rune) bool {
+	return r >= 0x3000 && r <= 0x303F || r >= 0xFF01 && r <= 0xFF5E
+}
+
+// composes reports whether the keyboard's IME composes the cluster
+func (kb keyboard) composes(cluster string) bool {
+	r := []rune(cluster)[0]
+	switch kb.ime {
+	case imeJapanese:
+		return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) || r == 'ー'
+	case imeChinese:
+		return unicode.Is(unicode.Han, r)
+	case imeKorean:
+		return isHangulSyllable(r)
+	}
+	return false
+}
+
+// readingKeys estimates the keystrokes of a cluster's reading in the IME:
+// romaji for kana (vowels take one key, other kana two), about two kana
+// per kanji, about three letters of pinyin per hanzi, and one key per jamo
+// of a Hangul syllable
+func (kb keyboard) readingKeys(cluster string) int {
+	r := []rune(cluster)[0]
+	switch {
+	case kb.ime == imeKorean:
+		return hangulKeys(r)
+	case kb.ime == imeChinese:
+		return 3
+	case unicode.Is(unicode.Han, r):
+		return 4
+	case strings.ContainsRune("あいうえおアイウエオんンー", r):
+		return 1
+	}
+	return 2
+}
+
+// isHangulSyllable reports whether r is a precomposed Hangul syllable
+func isHangulSyllable(r rune) bool { return r >= 0xAC00 && r <= 0xD7A3 }
+
+// hangulKeys counts the keys of a Hangul syllable on the dubeolsik layout:
+// one per jamo, two for compound vowels and compound final consonants
+func hangulKeys(r rune) int {
+	i := int(r - 0xAC00)
+	vowel, final := i%588/28, i%28
+	keys := 2
+	if slices.Contains([]int{9, 10, 11, 14, 15, 16, 19}, vowel) { // ㅘ ㅙ ㅚ ㅝ ㅞ ㅟ ㅢ
+		keys++
+	}
+	if final > 0 {
+		keys++
+		if slices.Contains([]int{3, 5, 6, 9, 10, 11, 12, 13, 14, 15, 18}, final) { // ㄳ ㄵ ㄶ ㄺ-ㅀ ㅄ
+			keys++
+		}
+	}
+	return keys
+}
+
+// pausesAfter reports whether a word or phrase ends at the cluster, where
+// typists pause a little longer
+func pausesAfter(cluster string) bool {
+	return len([]rune(cluster)) == 1 && strings.Contains(" ,.、。，！？\u3000", cluster)
+}
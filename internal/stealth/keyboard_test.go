@@ -0,0 +1,98 @@
+package stealth
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestClusters(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"hi", []string{"h", "i"}},
+		{"e\u0301te\u0301", []string{"é", "t", "é"}},                                                           // Decomposed é is normalized
+		{"q\u0323\u0307", []string{"q\u0323\u0307"}},                                                           // Marks stay on their letter
+		{"\U0001F44D\U0001F3FD!", []string{"\U0001F44D\U0001F3FD", "!"}},                                       // Skin tone
+		{"\U0001F469\u200d\U0001F4BB", []string{"\U0001F469\u200d\U0001F4BB"}},                                 // ZWJ sequence
+		{"\U0001F1E9\U0001F1EA\U0001F1EB\U0001F1F7", []string{"\U0001F1E9\U0001F1EA", "\U0001F1EB\U0001F1F7"}}, // Flags pair up
+		{"\u2764\uFE0F", []string{"\u2764\uFE0F"}},                                                             // Variation selector
+		{"日本語", []string{"日", "本", "語"}},
+	}
+	for _, tt := range tests {
+		if got := clusters(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("clusters(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestStroke(t *testing.T) {
+	tests := []struct {
+		keyboard, cluster, kind string
+	}{
+		{"us", "a", strokeKey},
+		{"us", "é", strokeLongPress},
+		{"de", "ä", strokeKey},
+		{"de", "Ä", strokeKey},
+		{"de", "é", strokeDead},
+		{"fr", "é", strokeKey},
+		{"fr", "ê", strokeDead},
+		{"ru", "ж", strokeKey},
+		{"us", "ж", strokeInsert},
+		{"us", "\U0001F44D\U0001F3FD", strokeInsert},
+		{"zh", "。", strokeKey},
+	}
+	for _, tt := range tests {
+		if kind, _ := keyboardFor(tt.keyboard).stroke(tt.cluster); kind != tt.kind {
+			t.Errorf("%s: stroke(%q) = %s, want %s", tt.keyboard, tt.cluster, kind, tt.kind)
+		}
+	}
+}
+
+func TestHangulKeys(t *testing.T) {
+	for syllable, want := range map[rune]int{'가': 2, '한': 3, '과': 3, '닭': 4, '왔': 4} {
+		if got := hangulKeys(syllable); got != want {
+			t.Errorf("hangulKeys(%c) = %d, want %d", syllable, got, want)
+		}
+	}
+}
+
+func TestTypeKeyboards(t *testing.T) {
+	tests := []struct {
+		keyboard, text string
+		want           map[string]int
+	}{
+		{"us", "hi", map[string]int{"keystroke": 2}},
+		{"de", "é", map[string]int{"dead_key": 1, "keystroke": 1}},
+		{"us", "é", map[string]int{"long_press": 1, "accent_pick": 1, "keystroke": 1}},
+		{"us", "\U0001F44D\U0001F3FD", map[string]int{"picker": 1, "keystroke": 1}},
+		{"ja", "日本", map[string]int{"keystroke": 8, "ime_convert": 1, "ime_candidate": 1, "ime_commit": 1}},
+		{"ko", "한국", map[string]int{"keystroke": 6, "ime_commit": 1}},
+		{"zh", "你好。", map[string]int{"keystroke": 7, "ime_convert": 1, "ime_commit": 1, "word_pause": 1}},
+	}
+	for _, tt := range tests {
+		s := newBenchStealth(t)
+		s.config.TypoChance = 0
+		s.persona.Keyboard = tt.keyboard
+		s.action = &ActionTrace{}
+		if err := s.TypeHumanLike("input", tt.text); err != nil {
+			t.Fatalf("%s %q: %v", tt.keyboard, tt.text, err)
+		}
+
+		counts := make(map[string]int)
+		for _, d := range s.action.Decisions {
+			counts[d.Kind]++
+		}
+		delete(counts, "ime_pick") // Only if the candidate roll hits
+		for kind, n := range tt.want {
+			if counts[kind] != n {
+				t.Errorf("%s %q: %d %s decisions, want %d", tt.keyboard, tt.text, counts[kind], kind, n)
+			}
+		}
+		for kind := range counts {
+			if _, ok := tt.want[kind]; !ok && kind != "ime_candidate" {
+				t.Errorf("%s %q: unexpected %s decisions", tt.keyboard, tt.text, kind)
+			}
+		}
+	}
+}
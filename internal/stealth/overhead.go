@@ -0,0 +1,91 @@
+package stealth
+
+import (
+	"sort"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/metrics"
+)
+
+// OtherActivity is the overhead entry for time outside any action, like
+// searching, cooldowns and idling between messages
+const OtherActivity = "other"
+
+var delaySeconds = metrics.NewCounter("stealth_delay_seconds_total", "Wall-clock time spent in deliberate stealth delays")
+
+// Overhead is the wall-clock time one action type took during the run,
+// split into the delays stealth added on purpose and everything else
+type Overhead struct {
+	Action  string
+	Actions int // Actions begun; 0 for OtherActivity
+	Total   time.Duration
+	Delay   time.Duration
+}
+
+// Work returns the time not spent in deliberate delays: page loads,
+// clicks, storage and the like
+func (o Overhead) Work() time.Duration {
+	return max(o.Total-o.Delay, 0)
+}
+
+// DelayShare returns the fraction of the time spent in deliberate delays
+func (o Overhead) DelayShare() float64 {
+	if o.Total <= 0 {
+		return 0
+	}
+	return min(float64(o.Delay)/float64(o.Total), 1)
+}
+
+// pause sleeps for a deliberate delay and books it against the action in
+// flight
+func (s *Stealth) pause(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	clock.Sleep(d)
+	s.overheadOf(s.currentAction()).Delay += d
+	delaySeconds.Add(d.Seconds())
+}
+
+// currentAction returns the action type time is booked against
+func (s *Stealth) currentAction() string {
+	if s.inFlight != nil {
+		return s.inFlight.Action
+	}
+	return OtherActivity
+}
+
+// overheadOf returns the running totals of an action type
+func (s *Stealth) overheadOf(action string) *Overhead {
+	if s.overhead == nil {
+		s.overhead = make(map[string]*Overhead)
+	}
+	o := s.overhead[action]
+	if o == nil {
+		o = &Overhead{Action: action}
+		s.overhead[action] = o
+	}
+	return o
+}
+
+// Overhead returns the time spent per action type since the engine was
+// created, actions by name and OtherActivity last. Time outside actions
+// counts towards OtherActivity.
+func (s *Stealth) Overhead() []Overhead {
+	var result []Overhead
+	var inActions time.Duration
+	for action, o := range s.overhead {
+		if action != OtherActivity {
+			result = append(result, *o)
+			inActions += o.Total
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Action < result[j].Action })
+
+	other := Overhead{Action: OtherActivity, Total: max(clock.Since(s.started)-inActions, 0)}
+	if o := s.overhead[OtherActivity]; o != nil {
+		other.Delay = o.Delay
+	}
+	return append(result, other)
+}
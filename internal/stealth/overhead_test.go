@@ -0,0 +1,36 @@
+package stealth
+
+import (
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+)
+
+func TestOverhead(t *testing.T) {
+	s := newBenchStealth(t)
+	fake := clock.Get().(*clock.Fake)
+
+	s.BeginAction("connection", "p1", "")
+	s.ShortPause()
+	fake.Advance(2 * time.Second) // Page load
+	s.EndAction(nil)
+
+	s.RandomDelay()
+	fake.Advance(10 * time.Second) // Search
+
+	got := s.Overhead()
+	if len(got) != 2 || got[0].Action != "connection" || got[1].Action != OtherActivity {
+		t.Fatalf("Overhead = %+v, want connection and other", got)
+	}
+	conn, other := got[0], got[1]
+	if conn.Actions != 1 || conn.Delay <= 0 || conn.Work() != 2*time.Second {
+		t.Errorf("connection = %+v, want 1 action with 2s of work", conn)
+	}
+	if other.Delay <= 0 || other.Work() != 10*time.Second {
+		t.Errorf("other = %+v, want 10s of work", other)
+	}
+	if share := conn.DelayShare(); share <= 0 || share >= 1 {
+		t.Errorf("connection delay share = %v", share)
+	}
+}
@@ -0,0 +1,111 @@
+package stealth
+
+import (
+	"math"
+	"time"
+)
+
+/*
+SCROLL PHYSICS
+
+Pages are scrolled with wheel events whose deltas follow the persona's
+input device instead of a fixed number of equal steps:
+
+- mouse: the wheel moves in fixed notches. People flick a few notches in
+  quick succession, then pause to re-grip and flick again.
+- trackpad: a two-finger swipe sends a small delta every frame. Deltas grow
+  while the fingers move and decay geometrically once they lift (momentum
+  scrolling) until they drop below half a pixel.
+- touch: the same swipe-then-glide shape, but as a shorter one-finger drag
+  dispatched as touch events rather than wheel events.
+*/
+
+// WheelEvent is one scroll step: the wait before it and its vertical delta.
+// For touch personas it stands for a touchmove rather than a wheel event.
+type WheelEvent struct {
+	Delay  time.Duration
+	DeltaY float64
+}
+
+// swipeFrame is the interval between trackpad and touch scroll events (~60 Hz)
+const swipeFrame = 16 * time.Millisecond
+
+// wheelEvents turns a scroll distance into the events the persona's input
+// device would produce
+func (s *Stealth) wheelEvents(distance float64) []WheelEvent {
+	if distance == 0 {
+		return nil
+	}
+	switch s.persona.InputDevice {
+	case "trackpad":
+		return s.swipeEvents(distance, 4, 8)
+	case "touch":
+		return s.swipeEvents(distance, 3, 5)
+	}
+	return s.mouseWheelEvents(distance)
+}
+
+// mouseWheelEvents scrolls by whole notches, grouped into flicks
+func (s *Stealth) mouseWheelEvents(distance float64) []WheelEvent {
+	notch := math.Copysign(float64(s.persona.WheelNotch), distance)
+	notches := int(math.Round(distance / notch))
+	if notches < 1 {
+		notches = 1
+	}
+
+	events := make([]WheelEvent, 0, notches)
+	flick := 0
+	for i := 0; i < notches; i++ {
+		delay := time.Duration(s.randomInt("notch_interval", s.persona.NotchIntervalMin, s.persona.NotchIntervalMax)) * time.Millisecond
+		if flick == 0 {
+			// Start of a new flick, after re-gripping the wheel
+			delay = time.Duration(s.randomInt("regrip", 150, 400)) * time.Millisecond
+			if i == 0 {
+				delay = 0
+			}
+			flick = s.randomInt("flick_notches", 2, 5)
+		}
+		flick--
+		events = append(events, WheelEvent{Delay: delay, DeltaY: notch})
+	}
+	return events
+}
+
+// swipeEvents accelerates over a swipe of minFrames-maxFrames frames, then
+// glides out. The swipe share is chosen so that momentum picks up at the
+// lift-off velocity and the deltas add up to the distance.
+func (s *Stealth) swipeEvents(distance float64, minFrames, maxFrames int) []WheelEvent {
+	decay := s.persona.MomentumDecay
+	frames := s.randomInt("swipe_frames", minFrames, maxFrames)
+	n := float64(frames)
+
+	// Swipe deltas grow quadratically: swipe*(2i+1)/n²; the last one is
+	// the lift-off velocity, and momentum adds v/(1-decay) on top
+	swipe := distance / (1 + (2*n-1)/(n*n*(1-decay)))
+
+	events := make([]WheelEvent, 0, frames+64)
+	for i := 0; i < frames; i++ {
+		delta := swipe * float64(2*i+1) / (n * n)
+		events = append(events, WheelEvent{Delay: s.frameDelay(i == 0), DeltaY: s.jitter(delta)})
+	}
+
+	v := swipe * (2*n - 1) / (n * n) * decay
+	for math.Abs(v) >= 0.5 {
+		events = append(events, WheelEvent{Delay: s.frameDelay(false), DeltaY: s.jitter(v)})
+		v *= decay
+	}
+	return events
+}
+
+// frameDelay returns the wait before a swipe event, with a little jitter
+func (s *Stealth) frameDelay(first bool) time.Duration {
+	if first {
+		return 0
+	}
+	return swipeFrame + time.Duration(s.randomInt("frame_jitter_us", -2000, 2000))*time.Microsecond
+}
+
+// jitter varies a delta by up to ±10%
+func (s *Stealth) jitter(delta float64) float64 {
+	return delta * s.randomFloat("delta_jitter", 0.9, 1.1)
+}
@@ -0,0 +1,54 @@
+package stealth
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// TestWheelEvents checks the shape of generated scrolls: a mouse moves in
+// whole notches, trackpad and touch swipes cover the distance and glide out
+// smoothly
+func TestWheelEvents(t *testing.T) {
+	s := newBenchStealth(t)
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 500; i++ {
+		distance := float64(rng.Intn(1800) - 600)
+		if distance == 0 {
+			continue
+		}
+
+		s.persona.InputDevice = "mouse"
+		for _, ev := range s.wheelEvents(distance) {
+			if math.Abs(ev.DeltaY) != float64(s.persona.WheelNotch) || math.Signbit(ev.DeltaY) != math.Signbit(distance) {
+				t.Fatalf("mouse scroll of %v: delta %v is not one notch in the scroll direction", distance, ev.DeltaY)
+			}
+		}
+
+		for _, device := range []string{"trackpad", "touch"} {
+			s.persona.InputDevice = device
+			events := s.wheelEvents(distance)
+			total, peak := 0.0, 0
+			for j, ev := range events {
+				if math.Signbit(ev.DeltaY) != math.Signbit(distance) {
+					t.Fatalf("%s scroll of %v: delta %v against the scroll direction", device, distance, ev.DeltaY)
+				}
+				if math.Abs(ev.DeltaY) > math.Abs(events[peak].DeltaY) {
+					peak = j
+				}
+				total += ev.DeltaY
+			}
+			// Jitter is ±10% per event and the glide stops below half a pixel
+			if math.Abs(total-distance) > math.Abs(distance)*0.1+10 {
+				t.Fatalf("%s scroll of %v covered %v", device, distance, total)
+			}
+			if tail := events[len(events)-1].DeltaY; math.Abs(tail) > 1 {
+				t.Fatalf("%s scroll of %v stopped abruptly at delta %v", device, distance, tail)
+			}
+			if peak == 0 {
+				t.Fatalf("%s scroll of %v started at full speed", device, distance)
+			}
+		}
+	}
+}
@@ -4,98 +4,164 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/go-rod/rod"
 	
+	"subspace/internal/calendar"
+	"subspace/internal/clock"
 	"subspace/internal/config"
 	"subspace/internal/logger"
 )
 
 type Stealth struct {
-	config config.StealthConfig
-	page   *rod.Page
-	log    *logger.ContextLogger
-	rng    *rand.Rand
+	config   config.StealthConfig
+	page     *rod.Page
+	log      *logger.ContextLogger
+	rng      *rand.Rand
+	viewport Viewport
+	cursor   Point
+	persona  config.PersonaConfig
+	session  time.Time // Manual work session allows activity until then
+	calendar *calendar.Calendar
+	lastAction time.Time // Last action passed through EnforceCooldown
+	recorder *Recorder    // Writes action traces when set
+	action   *ActionTrace // Action being traced, if any
+	inFlight *InFlight    // Action begun and not yet ended, traced or not
+	replay   *replayState // Recorded decisions being replayed, if any
+
+	started  time.Time            // Engine creation, the start of the overhead figures
+	overhead map[string]*Overhead // Time spent per action type
 }
 
 // New creates a new stealth engine
 func New(cfg config.StealthConfig, page *rod.Page) *Stealth {
-	return &Stealth{
-		config: cfg,
-		page:   page,
-		log:    logger.NewContext("stealth"),
-		rng:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	s := &Stealth{
+		config:   cfg,
+		page:     page,
+		log:      logger.NewContext("stealth"),
+		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		viewport: defaultViewport(cfg),
+		cursor:   Point{100, 100},
+		persona:  cfg.ActivePersona(),
+
+		started: clock.Now(),
 	}
+
+	// main checks the calendar at startup, so this only fails if the ICS
+	// file changed since
+	cal, err := calendar.New(cfg)
+	if err != nil {
+		s.log.Error("Holiday calendar unavailable, not skipping holidays", "error", err)
+	}
+	s.calendar = cal
+	return s
+}
+
+// SetPage points the engine at a new page, e.g. after Chrome was relaunched
+func (s *Stealth) SetPage(page *rod.Page) {
+	s.page = page
 }
 
 type Point struct {
 	X, Y float64
 }
 
-// MoveMouse moves the mouse from current position to target using Bézier curves
+// MoveMouse moves the mouse from current position to target, in CSS
+// pixels, using Bézier curves; flows pass viewport fractions to MoveTo
+// instead. Touch personas have no pointer to move; the finger reaches the
+// target without any events on the way.
 func (s *Stealth) MoveMouse(toX, toY float64) error {
 	s.log.Debug("Moving mouse with Bézier curve", "to_x", toX, "to_y", toY)
+	if err := s.CheckBudget(); err != nil {
+		return err
+	}
 	start := time.Now()
 
-	// Get current mouse position (mock for PoC)
-	// EDUCATIONAL NOTE: In production, track actual cursor position
-	fromX, fromY := s.getCurrentMousePosition()
+	// Targets outside the page can't be reached by a real pointer
+	to := s.viewport.Clamp(Point{toX, toY})
+	if to.X != toX || to.Y != toY {
+		s.log.Debug("Mouse target clamped to viewport", "x", to.X, "y", to.Y)
+	}
+	to = s.viewport.Snap(to)
 
-	// Generate control points for Bézier curve
-	cp1, cp2 := s.generateBezierControlPoints(fromX, fromY, toX, toY)
+	if !s.persona.Hovers() {
+		s.reachWithFinger(to)
+		logger.Timing("stealth", "move_mouse", start, nil)
+		return nil
+	}
 
-	// Calculate movement steps
-	steps := s.calculateSteps(fromX, fromY, toX, toY)
-	
-	// Move along the curve
-	for i := 0; i <= steps; i++ {
-		t := float64(i) / float64(steps)
-		
-		// Calculate point on cubic Bézier curve
-		x, y := s.cubicBezier(
-			Point{fromX, fromY},
-			cp1,
-			cp2,
-			Point{toX, toY},
-			t,
-		)
+	// Sample the whole curve up front from the precomputed weights
+	path := getPath()
+	defer putPath(path)
+	*path = s.mousePath(*path, s.getCurrentMousePosition(), to)
+
+	// Add slight delay between movements
+	delay := time.Duration(1000/s.config.MouseSpeed) * time.Millisecond
 
+	// Move along the curve
+	for _, p := range *path {
 		// EDUCATIONAL NOTE: In production, use:
-		// s.page.Mouse.Move(x, y, steps)
-		_ = x // Used in production
-		_ = y
-		
-		// Add slight delay between movements
-		delay := time.Duration(1000/s.config.MouseSpeed) * time.Millisecond
-		time.Sleep(delay)
+		// s.page.Mouse.Move(p.X, p.Y, 1)
+		_ = p // Used in production
+
+		s.pause(delay)
 	}
+	s.cursor = to
 
 	logger.Timing("stealth", "move_mouse", start, nil)
 	return nil
 }
 
+// mousePath samples a randomized Bézier path from one point to another
+// into buf. Control points are clamped to the viewport; since a Bézier curve
+// never leaves the convex hull of its control points, the whole path then
+// stays on the page.
+func (s *Stealth) mousePath(buf []Point, from, to Point) []Point {
+	from = s.viewport.Clamp(from)
+	to = s.viewport.Clamp(to)
+
+	// Generate control points for Bézier curve
+	cp1, cp2 := s.generateBezierControlPoints(from.X, from.Y, to.X, to.Y)
+	cp1 = s.viewport.Clamp(cp1)
+	cp2 = s.viewport.Clamp(cp2)
+
+	// Calculate movement steps
+	steps := s.calculateSteps(from.X, from.Y, to.X, to.Y)
+
+	buf = bezierPath(buf, from, cp1, cp2, to, steps)
+	for i, p := range buf {
+		buf[i] = s.viewport.Snap(p)
+	}
+	return buf
+}
+
 // generateBezierControlPoints creates random control points for natural curves
 func (s *Stealth) generateBezierControlPoints(x1, y1, x2, y2 float64) (Point, Point) {
 	// Add randomness to control points for variation
 	distX := x2 - x1
 	distY := y2 - y1
 	
-	// Control points offset by 30-60% of distance
+	// Control points offset by 30-60% of distance, jittered in proportion
+	// to the page
+	jitter := s.viewport.Width * curveJitter
 	cp1 := Point{
-		X: x1 + distX*0.3 + s.randomFloat(-50, 50),
-		Y: y1 + distY*0.3 + s.randomFloat(-50, 50),
+		X: x1 + distX*0.3 + s.randomFloat("curve", -jitter, jitter),
+		Y: y1 + distY*0.3 + s.randomFloat("curve", -jitter, jitter),
 	}
 	
 	cp2 := Point{
-		X: x1 + distX*0.7 + s.randomFloat(-50, 50),
-		Y: y1 + distY*0.7 + s.randomFloat(-50, 50),
+		X: x1 + distX*0.7 + s.randomFloat("curve", -jitter, jitter),
+		Y: y1 + distY*0.7 + s.randomFloat("curve", -jitter, jitter),
 	}
 	
 	return cp1, cp2
 }
 
-// cubicBezier calculates a point on a cubic Bézier curve
+// cubicBezier calculates a point on a cubic Bézier curve at any t; whole
+// paths are sampled with bezierPath instead
 func (s *Stealth) cubicBezier(p0, p1, p2, p3 Point, t float64) (float64, float64) {
 	// B(t) = (1-t)³P₀ + 3(1-t)²tP₁ + 3(1-t)t²P₂ + t³P₃
 	u := 1 - t
@@ -112,35 +178,34 @@ func (s *Stealth) cubicBezier(p0, p1, p2, p3 Point, t float64) (float64, float64
 
 // calculateSteps determines how many steps needed for smooth movement
 func (s *Stealth) calculateSteps(x1, y1, x2, y2 float64) int {
-	distance := math.Sqrt(math.Pow(x2-x1, 2) + math.Pow(y2-y1, 2))
+	distance := math.Hypot(x2-x1, y2-y1)
 	// More steps for longer distances
 	steps := int(distance / 5)
-	if steps < 10 {
-		steps = 10
+	if steps < minMouseSteps {
+		steps = minMouseSteps
 	}
-	if steps > 100 {
-		steps = 100
+	if steps > maxMouseSteps {
+		steps = maxMouseSteps
 	}
 	return steps
 }
 
-// getCurrentMousePosition returns mock current position
-func (s *Stealth) getCurrentMousePosition() (float64, float64) {
-	// In production, track actual position
-	return 100, 100
+// getCurrentMousePosition returns where the last movement left the cursor
+func (s *Stealth) getCurrentMousePosition() Point {
+	return s.cursor
 }
 
 func (s *Stealth) RandomDelay() {
-	delay := s.randomInt(s.config.ActionDelayMin, s.config.ActionDelayMax)
+	delay := s.randomInt("action_delay", s.config.ActionDelayMin, s.config.ActionDelayMax)
 	s.log.Debug("Random delay", "ms", delay)
-	time.Sleep(time.Duration(delay) * time.Millisecond)
+	s.pause(time.Duration(delay) * time.Millisecond)
 }
 
 // ThinkingPause simulates a human "thinking" or reading
 func (s *Stealth) ThinkingPause() {
-	delay := s.randomInt(s.config.ThinkTimeMin, s.config.ThinkTimeMax)
+	delay := s.randomInt("think", s.config.ThinkTimeMin, s.config.ThinkTimeMax)
 	s.log.Debug("Thinking pause", "ms", delay)
-	time.Sleep(time.Duration(delay) * time.Millisecond)
+	s.pause(time.Duration(delay) * time.Millisecond)
 }
 
 
@@ -162,14 +227,16 @@ func (s *Stealth) MaskFingerprint() error {
 	}
 
 	if s.config.RandomViewport {
-		width := s.randomInt(s.config.ViewportWidthMin, s.config.ViewportWidthMax)
-		height := s.randomInt(s.config.ViewportHeightMin, s.config.ViewportHeightMax)
+		width := s.randomInt("viewport_width", s.config.ViewportWidthMin, s.config.ViewportWidthMax)
+		height := s.randomInt("viewport_height", s.config.ViewportHeightMin, s.config.ViewportHeightMax)
 		
 		//  NOTE: In production:
 		// s.page.SetViewport(&proto.EmulationSetDeviceMetricsOverride{
 		//     Width: width, Height: height,
+		//     DeviceScaleFactor: s.config.DevicePixelRatio,
 		// })
 		
+		s.SetViewport(float64(width), float64(height))
 		s.log.Debug("Viewport randomized", "width", width, "height", height)
 	}
 
@@ -182,80 +249,139 @@ func (s *Stealth) RandomScroll() error {
 		return nil
 	}
 
-	if s.rng.Float64() > s.config.ScrollChance {
+	if !s.chance("scroll_chance", s.config.ScrollChance) {
 		return nil // Don't scroll this time
 	}
 
-	s.log.Debug("Performing random scroll")
-	
 	// Random scroll distance (can be negative for scroll up)
-	distance := s.randomInt(-s.config.ScrollDistance, s.config.ScrollDistance*2)
-	
-	// Simulate scroll with acceleration
-	steps := 10
-	for i := 0; i < steps; i++ {
-		// Ease-in-out acceleration curve
-		progress := float64(i) / float64(steps)
-		acceleration := s.easeInOutCubic(progress)
-		
-		stepDistance := float64(distance) * acceleration / float64(steps)
-		
+	distance := s.randomInt("scroll_distance", -s.config.ScrollDistance, s.config.ScrollDistance*2)
+	events := s.wheelEvents(float64(distance))
+	s.log.Debug("Performing random scroll", "distance", distance, "events", len(events), "device", s.persona.InputDevice)
+
+	// Replay the wheel events the input device would send
+	for _, ev := range events {
+		s.pause(ev.Delay)
+
 		// NOTE: In production:
-		// s.page.Mouse.Scroll(0, stepDistance, steps)
-		_ = stepDistance // Used in production
-		
-		time.Sleep(20 * time.Millisecond)
+		// s.page.Mouse.Scroll(0, ev.DeltaY, 1), or a touchmove for touch personas
+		_ = ev.DeltaY // Used in production
 	}
 
 	return nil
 }
 
-// easeInOutCubic provides smooth acceleration curve
-func (s *Stealth) easeInOutCubic(t float64) float64 {
-	if t < 0.5 {
-		return 4 * t * t * t
-	}
-	return 1 - math.Pow(-2*t+2, 3)/2
-}
-
 // WHY: Instant text appearance is unnatural; perfect typing is rare.
 // HOW: Character-by-character typing with random delays and occasional typos.
 // TRADEOFF: Much slower than instant input, but highly realistic.
 
-// TypeHumanLike types text character by character with human-like behavior
+// TypeHumanLike types text character by character with human-like behavior,
+// producing each character the way the persona's keyboard would (see
+// keyboard.go)
 func (s *Stealth) TypeHumanLike(selector, text string) error {
-	s.log.Debug("Typing with human simulation", "length", len(text))
+	kb := keyboardFor(s.persona.Keyboard)
+	s.log.Debug("Typing with human simulation", "length", utf8.RuneCountInString(text), "keyboard", s.persona.Keyboard)
 	start := time.Now()
 
-	for i, char := range text {
-		// Check if we should make a typo
-		if s.config.TypoChance > 0 && s.rng.Float64() < s.config.TypoChance {
-			s.makeTypo(selector)
+	chars := clusters(text)
+	for i := 0; i < len(chars); {
+		if err := s.CheckBudget(); err != nil {
+			logger.Timing("stealth", "type_human", start, err)
+			return err
+		}
+
+		// The IME takes a phrase of up to four characters before converting
+		if kb.composes(chars[i]) {
+			n := 1
+			for n < 4 && i+n < len(chars) && kb.composes(chars[i+n]) {
+				n++
+			}
+			s.compose(selector, kb, chars[i:i+n])
+			i += n
+			continue
+		}
+
+		char := chars[i]
+		kind, keys := kb.stroke(char)
+
+		// Check if we should make a typo; the picker can't slip
+		if kind != strokeInsert && s.config.TypoChance > 0 && s.chance("typo_chance", s.config.TypoChance) {
+			s.makeTypo(selector, kb)
+		}
+
+		// Reach the character: the accent before the letter, the held key's
+		// accent menu, or the character picker
+		switch kind {
+		case strokeDead:
+			for k := 1; k < keys; k++ {
+				s.pause(time.Duration(s.randomInt("dead_key", s.config.TypingSpeedMin, s.config.TypingSpeedMax)) * time.Millisecond)
+			}
+		case strokeLongPress:
+			s.pause(time.Duration(s.randomInt("long_press", 400, 900)) * time.Millisecond)
+			s.pause(time.Duration(s.randomInt("accent_pick", 150, 400)) * time.Millisecond)
+		case strokeInsert:
+			s.pause(time.Duration(s.randomInt("picker", 1000, 3000)) * time.Millisecond)
 		}
 
 		// Type the character
 		// EDUCATIONAL NOTE: In production:
-		// element.Input(string(char))
+		// element.Input(char), whole even when it is several runes, or
+		// s.page.InsertText(char) for the picker
 		
 		// Variable delay between keystrokes
-		delay := s.randomInt(s.config.TypingSpeedMin, s.config.TypingSpeedMax)
+		delay := s.randomInt("keystroke", s.config.TypingSpeedMin, s.config.TypingSpeedMax)
 		
-		// Longer pause at word boundaries (spaces, commas)
-		if char == ' ' || char == ',' || char == '.' {
-			delay += s.randomInt(50, 200)
+		// Longer pause at word boundaries (spaces, commas, CJK punctuation)
+		if pausesAfter(char) {
+			delay += s.randomInt("word_pause", 50, 200)
 		}
 		
-		time.Sleep(time.Duration(delay) * time.Millisecond)
+		s.pause(time.Duration(delay) * time.Millisecond)
 
-		s.log.Debug("Typed character", "index", i, "char", string(char))
+		if logger.DebugEnabled() {
+			s.log.Debug("Typed character", "index", i, "char", char, "stroke", kind)
+		}
+		i++
 	}
 
 	logger.Timing("stealth", "type_human", start, nil)
 	return nil
 }
 
-// makeTypo simulates a typing error and correction
-func (s *Stealth) makeTypo(selector string) {
+// compose types a phrase through the IME: its reading key by key into the
+// composition, then, except for Korean which composes as it goes, the
+// conversion on space and sometimes a pick from the candidate list, and
+// finally the commit
+func (s *Stealth) compose(selector string, kb keyboard, phrase []string) {
+	for _, char := range phrase {
+		for k := 0; k < kb.readingKeys(char); k++ {
+			if s.config.TypoChance > 0 && s.chance("typo_chance", s.config.TypoChance) {
+				s.makeTypo(selector, kb)
+			}
+			// EDUCATIONAL NOTE: In production:
+			// proto.InputImeSetComposition{Text: reading so far}.Call(s.page)
+			s.pause(time.Duration(s.randomInt("keystroke", s.config.TypingSpeedMin, s.config.TypingSpeedMax)) * time.Millisecond)
+		}
+	}
+
+	if kb.ime != imeKorean {
+		s.pause(time.Duration(s.randomInt("ime_convert", 150, 400)) * time.Millisecond)
+		if s.chance("ime_candidate", 0.25) {
+			s.pause(time.Duration(s.randomInt("ime_pick", 300, 900)) * time.Millisecond)
+		}
+	}
+
+	// In production: proto.InputInsertText{Text: phrase} commits the
+	// composition, so the page sees compositionend and one input event
+	s.pause(time.Duration(s.randomInt("ime_commit", 80, 200)) * time.Millisecond)
+
+	if logger.DebugEnabled() {
+		s.log.Debug("Composed phrase", "text", strings.Join(phrase, ""), "ime", kb.ime)
+	}
+}
+
+// makeTypo simulates a typing error and correction, hitting a key of the
+// keyboard's layout
+func (s *Stealth) makeTypo(selector string, kb keyboard) {
 	if !s.config.TypoCorrection {
 		return
 	}
@@ -263,39 +389,46 @@ func (s *Stealth) makeTypo(selector string) {
 	s.log.Debug("Simulating typo")
 	
 	// Type wrong character
-	wrongChar := string(rune(s.randomInt(97, 122))) // Random lowercase letter
+	var wrongChar string
+	if len(kb.typos) > 0 {
+		wrongChar = string(kb.typos[s.randomInt("typo_key", 0, len(kb.typos)-1)])
+	} else {
+		wrongChar = string(rune(s.randomInt("typo_char", 97, 122))) // Random lowercase letter
+	}
 	// In production: element.Input(wrongChar)
 	_ = wrongChar // Used in production
 	
-	time.Sleep(time.Duration(s.randomInt(100, 300)) * time.Millisecond)
+	s.pause(time.Duration(s.randomInt("typo_notice", 100, 300)) * time.Millisecond)
 	
 	// "Notice" the error and backspace
 	// In production: element.Input("\b")
 	
-	time.Sleep(time.Duration(s.randomInt(50, 150)) * time.Millisecond)
+	s.pause(time.Duration(s.randomInt("typo_backspace", 50, 150)) * time.Millisecond)
 }
 
 func (s *Stealth) WanderMouse() error {
-	if !s.config.MouseWanderEnabled {
+	if !s.config.MouseWanderEnabled || !s.persona.Hovers() {
 		return nil
 	}
 
-	if s.rng.Float64() > s.config.MouseWanderChance {
+	if !s.chance("wander_chance", s.config.MouseWanderChance) {
 		return nil
 	}
 
 	s.log.Debug("Mouse wandering")
 	
-	// Small random movements (simulate reading or hovering)
-	for i := 0; i < s.randomInt(2, 5); i++ {
-		offsetX := s.randomFloat(-30, 30)
-		offsetY := s.randomFloat(-30, 30)
+	// Small random movements (simulate reading or hovering), about 30
+	// pixels on a 1200 pixel page
+	reach := s.viewport.Width * 0.025
+	for i, n := 0, s.randomInt("wander_moves", 2, 5); i < n; i++ {
+		offsetX := s.randomFloat("wander_offset", -reach, reach)
+		offsetY := s.randomFloat("wander_offset", -reach, reach)
 		
 		// Get current position and move slightly
-		currentX, currentY := s.getCurrentMousePosition()
-		s.MoveMouse(currentX+offsetX, currentY+offsetY)
+		current := s.getCurrentMousePosition()
+		s.MoveMouse(current.X+offsetX, current.Y+offsetY)
 		
-		time.Sleep(time.Duration(s.randomInt(200, 800)) * time.Millisecond)
+		s.pause(time.Duration(s.randomInt("wander_pause", 200, 800)) * time.Millisecond)
 	}
 
 	return nil
@@ -306,7 +439,15 @@ func (s *Stealth) CheckBusinessHours() bool {
 		return true // Always allowed if not enabled
 	}
 
-	now := time.Now()
+	now := clock.Now()
+	if now.Before(s.session) {
+		s.log.Debug("Within manual work session", "until", s.session.Format("15:04"))
+		return true
+	}
+	if off, reason := s.calendar.DayOff(now); off {
+		s.log.Warn("Day off, no activity", "date", now.Format("2006-01-02"), "reason", reason)
+		return false
+	}
 	currentTime := now.Format("15:04")
 
 	// Check if in business hours
@@ -330,6 +471,18 @@ func (s *Stealth) CheckBusinessHours() bool {
 	return allowed
 }
 
+// DayOff reports whether today is a weekend or holiday for the persona,
+// and which
+func (s *Stealth) DayOff() (bool, string) {
+	return s.calendar.DayOff(clock.Now())
+}
+
+// SetWorkSession allows activity outside business hours until the given
+// time, for an operator-supervised work session
+func (s *Stealth) SetWorkSession(until time.Time) {
+	s.session = until
+}
+
 // isTimeInRange checks if time is between start and end
 func (s *Stealth) isTimeInRange(current, start, end string) bool {
 	return current >= start && current <= end
@@ -339,20 +492,18 @@ func (s *Stealth) isTimeInRange(current, start, end string) bool {
 func (s *Stealth) WaitForBusinessHours() {
 	for !s.CheckBusinessHours() {
 		s.log.Info("Waiting for business hours to resume...")
-		time.Sleep(15 * time.Minute) // Check every 15 minutes
+		s.pause(15 * time.Minute) // Check every 15 minutes
 	}
 }
 
-var lastActionTime time.Time
-
 // EnforceCooldown ensures minimum time between actions
 func (s *Stealth) EnforceCooldown(actionType string, minDelaySeconds int) {
-	if lastActionTime.IsZero() {
-		lastActionTime = time.Now()
+	if s.lastAction.IsZero() {
+		s.lastAction = clock.Now()
 		return
 	}
 
-	elapsed := time.Since(lastActionTime)
+	elapsed := clock.Since(s.lastAction)
 	required := time.Duration(minDelaySeconds) * time.Second
 
 	if elapsed < required {
@@ -360,26 +511,35 @@ func (s *Stealth) EnforceCooldown(actionType string, minDelaySeconds int) {
 		s.log.Info("Enforcing cooldown", 
 			"action", actionType,
 			"wait_seconds", remaining.Seconds())
-		time.Sleep(remaining)
+		s.pause(remaining)
 	}
 
-	lastActionTime = time.Now()
+	s.lastAction = clock.Now()
 }
-func (s *Stealth) randomInt(min, max int) int {
-	if min >= max {
-		return min
+
+// randomInt returns a random int between min and max inclusive, recorded
+// in the action trace as the given kind of decision
+func (s *Stealth) randomInt(kind string, min, max int) int {
+	v := min
+	if min < max {
+		v = min + s.rng.Intn(max-min+1)
 	}
-	return min + s.rng.Intn(max-min+1)
+	return int(s.record(kind, float64(v)))
 }
 
 // randomFloat returns a random float64 between min and max
-func (s *Stealth) randomFloat(min, max float64) float64 {
-	return min + s.rng.Float64()*(max-min)
+func (s *Stealth) randomFloat(kind string, min, max float64) float64 {
+	return s.record(kind, min+s.rng.Float64()*(max-min))
+}
+
+// chance rolls the dice against a probability
+func (s *Stealth) chance(kind string, probability float64) bool {
+	return s.record(kind, s.rng.Float64()) < probability
 }
 
 // ShouldProceed checks if action should proceed based on random chance
 func (s *Stealth) ShouldProceed(probability float64) bool {
-	return s.rng.Float64() < probability
+	return s.chance("proceed", probability)
 }
 
 // Summary logs a summary of active stealth techniques
@@ -401,6 +561,7 @@ func (s *Stealth) Summary() string {
 	if s.config.MaskWebDriver {
 		active = append(active, "Fingerprint Masking")
 	}
+	active = append(active, "Input Persona ("+s.config.Persona+", "+s.persona.InputDevice+")")
 	
 	return fmt.Sprintf("Active stealth techniques: %v", active)
 }
@@ -0,0 +1,150 @@
+package stealth
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/logger"
+)
+
+// newBenchStealth returns a stealth engine on a fake clock with logging
+// quiet, so benchmarks measure the math rather than sleeps and log output
+func newBenchStealth(tb testing.TB) *Stealth {
+	tb.Helper()
+	logger.Init("error")
+	clock.Set(clock.NewFake(time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)))
+	tb.Cleanup(func() { clock.Set(clock.Real{}) })
+	return New(config.Defaults().Stealth, nil)
+}
+
+// TestBezierPathMatchesCubicBezier guards the precomputed weights against
+// the direct formula
+func TestBezierPathMatchesCubicBezier(t *testing.T) {
+	s := newBenchStealth(t)
+	p0, p3 := Point{100, 100}, Point{840, 610}
+	p1, p2 := s.generateBezierControlPoints(p0.X, p0.Y, p3.X, p3.Y)
+
+	for _, steps := range []int{1, 5, minMouseSteps, 37, maxMouseSteps, 250} {
+		path := bezierPath(nil, p0, p1, p2, p3, steps)
+		if len(path) != steps+1 {
+			t.Fatalf("steps %d: got %d points", steps, len(path))
+		}
+		for i, p := range path {
+			x, y := s.cubicBezier(p0, p1, p2, p3, float64(i)/float64(steps))
+			if math.Abs(p.X-x) > 1e-9 || math.Abs(p.Y-y) > 1e-9 {
+				t.Fatalf("steps %d point %d: got (%v, %v), want (%v, %v)", steps, i, p.X, p.Y, x, y)
+			}
+		}
+	}
+}
+
+func BenchmarkControlPoints(b *testing.B) {
+	s := newBenchStealth(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s.generateBezierControlPoints(100, 100, 840, 610)
+	}
+}
+
+// BenchmarkCubicBezierPath samples a full path with the direct formula,
+// the way MoveMouse used to
+func BenchmarkCubicBezierPath(b *testing.B) {
+	s := newBenchStealth(b)
+	p0, p1, p2, p3 := Point{100, 100}, Point{300, 250}, Point{600, 500}, Point{840, 610}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j <= maxMouseSteps; j++ {
+			s.cubicBezier(p0, p1, p2, p3, float64(j)/maxMouseSteps)
+		}
+	}
+}
+
+// BenchmarkBezierPath samples the same path from the precomputed weights
+// into a pooled buffer
+func BenchmarkBezierPath(b *testing.B) {
+	p0, p1, p2, p3 := Point{100, 100}, Point{300, 250}, Point{600, 500}, Point{840, 610}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		path := getPath()
+		*path = bezierPath(*path, p0, p1, p2, p3, maxMouseSteps)
+		putPath(path)
+	}
+}
+
+// BenchmarkWheelEvents generates the wheel events of one scroll for each
+// input device
+func BenchmarkWheelEvents(b *testing.B) {
+	for _, device := range []string{"mouse", "trackpad"} {
+		b.Run(device, func(b *testing.B) {
+			s := newBenchStealth(b)
+			s.persona.InputDevice = device
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				s.wheelEvents(600)
+			}
+		})
+	}
+}
+
+// BenchmarkMoveMouse covers a whole movement: control points, steps,
+// path sampling and the per-step fake sleeps
+func BenchmarkMoveMouse(b *testing.B) {
+	s := newBenchStealth(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s.MoveMouse(840, 610)
+	}
+}
+
+// BenchmarkMoveMouseParallel drives one stealth engine per goroutine, as
+// with many concurrent pages
+func BenchmarkMoveMouseParallel(b *testing.B) {
+	newBenchStealth(b)
+	cfg := config.Defaults().Stealth
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		s := New(cfg, nil)
+		for pb.Next() {
+			s.MoveMouse(840, 610)
+		}
+	})
+}
+
+func BenchmarkRandomDelay(b *testing.B) {
+	s := newBenchStealth(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s.RandomDelay()
+	}
+}
+
+func BenchmarkThinkingPause(b *testing.B) {
+	s := newBenchStealth(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s.ThinkingPause()
+	}
+}
+
+func BenchmarkWaitForNavigation(b *testing.B) {
+	s := newBenchStealth(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s.WaitForNavigation()
+	}
+}
+
+// BenchmarkTypeHumanLike types a typical connection note
+func BenchmarkTypeHumanLike(b *testing.B) {
+	s := newBenchStealth(b)
+	text := strings.Repeat("Hi Jane, great to connect. ", 10)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(text)))
+	for i := 0; i < b.N; i++ {
+		s.TypeHumanLike("#note", text)
+	}
+}
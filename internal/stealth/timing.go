@@ -1,27 +1,64 @@
 package stealth
 
 import (
+	"math"
 	"time"
+
+	"subspace/internal/clock"
 )
 
 // WaitForNavigation waits for page navigation to complete with human-like timing
 // This wraps sleep with proper abstraction and adds variable timing
 func (s *Stealth) WaitForNavigation() {
 	// Variable wait time for navigation (2-4 seconds)
-	delay := s.randomInt(2000, 4000)
+	delay := s.randomInt("navigation", 2000, 4000)
 	s.log.Debug("Waiting for navigation", "ms", delay)
-	time.Sleep(time.Duration(delay) * time.Millisecond)
+	s.pause(time.Duration(delay) * time.Millisecond)
 }
 
 // WaitForPageLoad waits for page to fully load with jitter
 func (s *Stealth) WaitForPageLoad() {
-	delay := s.randomInt(1500, 3000)
+	delay := s.randomInt("page_load", 1500, 3000)
 	s.log.Debug("Waiting for page load", "ms", delay)
-	time.Sleep(time.Duration(delay) * time.Millisecond)
+	s.pause(time.Duration(delay) * time.Millisecond)
 }
 
 // ShortPause adds a brief, randomized pause
 func (s *Stealth) ShortPause() {
-	delay := s.randomInt(200, 600)
-	time.Sleep(time.Duration(delay) * time.Millisecond)
+	delay := s.randomInt("short_pause", 200, 600)
+	s.pause(time.Duration(delay) * time.Millisecond)
+}
+
+// MessageGap picks the wait before the next bulk message: log-normal around
+// the configured median, so most gaps last a few minutes and the odd one
+// runs much longer. It is never shorter than minSeconds.
+func (s *Stealth) MessageGap(minSeconds int) time.Duration {
+	median := float64(s.config.MessageGapMedianSeconds)
+	secs := s.record("message_gap", median*math.Exp(s.config.MessageGapSpread*s.rng.NormFloat64()))
+	lo := float64(minSeconds)
+	secs = clampFloat(secs, lo, math.Max(lo, float64(s.config.MessageGapMaxSeconds)))
+	return time.Duration(secs * float64(time.Second))
+}
+
+// IdleBrowse passes d the way someone between two tasks would: reading
+// pauses broken up by the odd scroll or mouse wander
+func (s *Stealth) IdleBrowse(d time.Duration) {
+	s.log.Info("Idling before next action", "wait_seconds", d.Seconds())
+	deadline := clock.Now().Add(d)
+	for {
+		remaining := deadline.Sub(clock.Now())
+		if remaining <= 0 {
+			return
+		}
+		pause := time.Duration(s.randomInt("idle_pause", 5000, 45000)) * time.Millisecond
+		s.pause(min(pause, remaining))
+		if clock.Now().After(deadline) {
+			return
+		}
+		if s.chance("idle_scroll", 0.5) {
+			s.RandomScroll()
+		} else {
+			s.WanderMouse()
+		}
+	}
 }
@@ -0,0 +1,69 @@
+package stealth
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+)
+
+// TestMessageGap checks that bulk message gaps stay within the floor and
+// the cap, center on the median and have a long tail
+func TestMessageGap(t *testing.T) {
+	s := newBenchStealth(t)
+	s.config.MessageGapMedianSeconds = 240
+	s.config.MessageGapSpread = 0.6
+	s.config.MessageGapMaxSeconds = 1200
+
+	gaps := make([]time.Duration, 2000)
+	for i := range gaps {
+		gaps[i] = s.MessageGap(60)
+		if gaps[i] < time.Minute || gaps[i] > 20*time.Minute {
+			t.Fatalf("gap %v outside [1m, 20m]", gaps[i])
+		}
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+	if median := gaps[len(gaps)/2]; median < 3*time.Minute || median > 5*time.Minute {
+		t.Errorf("median gap %v, want about 4m", median)
+	}
+	if p95 := gaps[len(gaps)*95/100]; p95 < 2*gaps[len(gaps)/2] {
+		t.Errorf("95th percentile gap %v is not heavy-tailed", p95)
+	}
+
+	// A floor above the cap wins
+	if gap := s.MessageGap(1500); gap != 1500*time.Second {
+		t.Errorf("gap with floor above cap = %v, want 25m", gap)
+	}
+}
+
+// TestIdleBrowse checks that idling takes the requested time
+func TestIdleBrowse(t *testing.T) {
+	s := newBenchStealth(t)
+	start := clock.Now()
+	s.IdleBrowse(3 * time.Minute)
+	if elapsed := clock.Since(start); elapsed < 3*time.Minute || elapsed > 4*time.Minute {
+		t.Errorf("idled for %v, want about 3m", elapsed)
+	}
+}
+
+func TestWorkSessionAllowsEvenings(t *testing.T) {
+	s := newBenchStealth(t)
+	s.config.BusinessHoursEnabled = true
+	s.config.BusinessHoursStart, s.config.BusinessHoursEnd = "09:00", "17:00"
+	s.config.BreakTimeEnabled = false
+	evening := time.Date(2024, 3, 4, 20, 0, 0, 0, time.UTC) // A Monday
+	clock.Set(clock.NewFake(evening))
+
+	if s.CheckBusinessHours() {
+		t.Fatal("active at 20:00 without a work session")
+	}
+	s.SetWorkSession(evening.Add(time.Hour))
+	if !s.CheckBusinessHours() {
+		t.Error("not active within the work session")
+	}
+	clock.Set(clock.NewFake(evening.Add(time.Hour)))
+	if s.CheckBusinessHours() {
+		t.Error("still active once the work session ended")
+	}
+}
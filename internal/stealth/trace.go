@@ -0,0 +1,252 @@
+package stealth
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"subspace/internal/clock"
+)
+
+/*
+ACTION TRACES
+
+Every random choice the engine makes (each delay, keystroke interval, typo
+roll, Bézier control point offset, scroll distance) is a decision. While a
+high-level action such as a connection request or a message is traced,
+its decisions are recorded in order, so the trace of a session that was
+flagged can be compared line by line against one that went through.
+
+Traces are written as JSON lines, one action per line, to one file per run
+under <data_dir>/traces. Decision kinds name the call site: action_delay,
+think, keystroke, word_pause, typo_chance, typo_char, curve, click_dwell,
+scroll_distance and so on. Chances record the roll, not the outcome.
+
+REPLAY:
+A recorded action can be fed back to the engine, which then takes the
+recorded decisions in order instead of fresh random ones. As long as the
+code asks for the same kinds of decision in the same order, the action
+plays out identically, delays and all. The first decision whose kind
+differs is reported as the divergence; from there on the engine is back
+to random choices, since the rest of the recording no longer lines up.
+*/
+
+// TracesDir is where action traces are written, relative to the data dir
+const TracesDir = "traces"
+
+// Decision is one random choice made by the engine
+type Decision struct {
+	Kind  string  `json:"kind"`
+	Value float64 `json:"value"`
+}
+
+// ActionTrace records the decisions made during one high-level action
+type ActionTrace struct {
+	Action    string     `json:"action"` // connection, message
+	ProfileID string     `json:"profile_id"`
+	Template  string     `json:"template,omitempty"`
+	Persona   string     `json:"persona"`
+	Cursor    Point      `json:"cursor"`   // Where the pointer started
+	Viewport  Viewport   `json:"viewport"` // Page size mouse paths were clamped to
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   time.Time  `json:"ended_at"`
+	Error     string     `json:"error,omitempty"`
+	Decisions []Decision `json:"decisions"`
+}
+
+// Recorder appends action traces to a run's trace file
+type Recorder struct {
+	path string
+}
+
+// NewRecorder returns a recorder writing to a new trace file for a run
+// starting now
+func NewRecorder(dataDir string) *Recorder {
+	name := clock.Now().UTC().Format("20060102-150405") + ".jsonl"
+	return &Recorder{path: filepath.Join(dataDir, TracesDir, name)}
+}
+
+// Path returns the trace file the recorder writes to
+func (r *Recorder) Path() string {
+	return r.path
+}
+
+// Write appends one action trace
+func (r *Recorder) Write(t *ActionTrace) error {
+	line, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("failed to encode action trace: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create traces directory: %w", err)
+	}
+	f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open trace file: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write action trace: %w", err)
+	}
+	return f.Close()
+}
+
+// ReadTraces loads the action traces of a trace file, in recording order
+func ReadTraces(path string) ([]ActionTrace, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace file: %w", err)
+	}
+	defer f.Close()
+
+	var traces []ActionTrace
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var t ActionTrace
+		if err := json.Unmarshal(sc.Bytes(), &t); err != nil {
+			return nil, fmt.Errorf("invalid action trace on line %d of %s: %w", line, path, err)
+		}
+		traces = append(traces, t)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read trace file: %w", err)
+	}
+	return traces, nil
+}
+
+// SetRecorder makes the engine trace actions to r; nil stops tracing
+func (s *Stealth) SetRecorder(r *Recorder) {
+	s.recorder = r
+}
+
+// InFlight is an action against a profile that began and hasn't ended
+type InFlight struct {
+	Action    string `json:"action"`
+	ProfileID string `json:"profile_id"`
+
+	deadline time.Time // End of its budget, zero for none
+	started  time.Time
+}
+
+// InFlight returns the action under way, or nil. An action is left in
+// flight when a panic skips its EndAction.
+func (s *Stealth) InFlight() *InFlight {
+	return s.inFlight
+}
+
+// BeginAction starts tracing a high-level action against a profile,
+// with the message template for messages. It traces nothing unless a
+// recorder is set, but the action is in flight until EndAction either way.
+func (s *Stealth) BeginAction(action, profileID, template string) {
+	s.inFlight = &InFlight{Action: action, ProfileID: profileID, started: clock.Now()}
+	s.overheadOf(action).Actions++
+	if budget := s.actionBudget(action); budget > 0 {
+		s.inFlight.deadline = clock.Now().Add(budget)
+	}
+	if s.recorder == nil {
+		return
+	}
+	s.action = &ActionTrace{
+		Action:    action,
+		ProfileID: profileID,
+		Template:  template,
+		Persona:   s.config.Persona,
+		Cursor:    s.cursor,
+		Viewport:  s.viewport,
+		StartedAt: clock.Now(),
+	}
+}
+
+// EndAction finishes the traced action with its outcome and writes it out.
+// A trace that can't be written is logged, never failing the action.
+func (s *Stealth) EndAction(err error) {
+	if a := s.inFlight; a != nil {
+		s.overheadOf(a.Action).Total += clock.Since(a.started)
+	}
+	s.inFlight = nil
+	t := s.action
+	if t == nil {
+		return
+	}
+	s.action = nil
+	t.EndedAt = clock.Now()
+	if err != nil {
+		t.Error = err.Error()
+	}
+	if werr := s.recorder.Write(t); werr != nil {
+		s.log.Warn("Failed to record action trace", "action", t.Action, "error", werr)
+	}
+}
+
+// Divergence is where a replayed action stopped following its recording
+type Divergence struct {
+	Index    int    `json:"index"`    // Position in the decision sequence
+	Expected string `json:"expected"` // Recorded kind; empty if the replay asked for more
+	Got      string `json:"got"`      // Kind asked for; empty if the replay asked for fewer
+}
+
+// ReplayStats summarises how closely a replay followed its recording
+type ReplayStats struct {
+	Recorded   int         `json:"recorded"` // Decisions in the recording
+	Taken      int         `json:"taken"`    // Decisions the replay asked for
+	Divergence *Divergence `json:"divergence,omitempty"`
+}
+
+// replayState tracks progress through a recorded decision sequence
+type replayState struct {
+	decisions  []Decision
+	next       int
+	divergence *Divergence
+}
+
+// Replay makes the engine take the decisions of a recorded action, in
+// order, and restores the cursor and viewport it started with
+func (s *Stealth) Replay(t *ActionTrace) {
+	s.replay = &replayState{decisions: t.Decisions}
+	s.cursor = t.Cursor
+	if t.Viewport.Width > 0 && t.Viewport.Height > 0 {
+		s.viewport = t.Viewport
+	}
+}
+
+// EndReplay returns the engine to random decisions and reports how the
+// replay went
+func (s *Stealth) EndReplay() ReplayStats {
+	r := s.replay
+	if r == nil {
+		return ReplayStats{}
+	}
+	s.replay = nil
+	if r.divergence == nil && r.next < len(r.decisions) {
+		r.divergence = &Divergence{Index: r.next, Expected: r.decisions[r.next].Kind}
+	}
+	return ReplayStats{Recorded: len(r.decisions), Taken: r.next, Divergence: r.divergence}
+}
+
+// record notes a decision in the traced action, if any, and returns it.
+// While replaying, the recorded value replaces v.
+func (s *Stealth) record(kind string, v float64) float64 {
+	if r := s.replay; r != nil {
+		switch {
+		case r.divergence != nil:
+		case r.next >= len(r.decisions):
+			r.divergence = &Divergence{Index: r.next, Got: kind}
+		case r.decisions[r.next].Kind != kind:
+			r.divergence = &Divergence{Index: r.next, Expected: r.decisions[r.next].Kind, Got: kind}
+		default:
+			v = r.decisions[r.next].Value
+		}
+		r.next++
+	}
+	if s.action != nil {
+		s.action.Decisions = append(s.action.Decisions, Decision{Kind: kind, Value: v})
+	}
+	return v
+}
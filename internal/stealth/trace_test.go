@@ -0,0 +1,57 @@
+package stealth
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestActionTrace(t *testing.T) {
+	s := newBenchStealth(t)
+	s.config.TypoChance = 0
+	rec := NewRecorder(t.TempDir())
+	s.SetRecorder(rec)
+
+	s.RandomDelay() // Outside an action: not traced
+	s.BeginAction("message", "ada", "welcome")
+	s.RandomDelay()
+	s.TypeHumanLike("input", "hi, ada")
+	s.EndAction(errors.New("send button missing"))
+
+	f, err := os.Open(rec.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var traces []ActionTrace
+	for sc := bufio.NewScanner(f); sc.Scan(); {
+		var tr ActionTrace
+		if err := json.Unmarshal(sc.Bytes(), &tr); err != nil {
+			t.Fatal(err)
+		}
+		traces = append(traces, tr)
+	}
+	if len(traces) != 1 {
+		t.Fatalf("got %d traces, want 1", len(traces))
+	}
+
+	tr := traces[0]
+	if tr.Action != "message" || tr.ProfileID != "ada" || tr.Error != "send button missing" {
+		t.Errorf("trace header = %+v", tr)
+	}
+	counts := make(map[string]int)
+	for _, d := range tr.Decisions {
+		counts[d.Kind]++
+	}
+	want := map[string]int{"action_delay": 1, "keystroke": 7, "word_pause": 2}
+	for kind, n := range want {
+		if counts[kind] != n {
+			t.Errorf("%d %s decisions, want %d", counts[kind], kind, n)
+		}
+	}
+	if len(tr.Decisions) != 10 {
+		t.Errorf("got %d decisions, want 10: %+v", len(tr.Decisions), tr.Decisions)
+	}
+}
@@ -0,0 +1,402 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/seal"
+)
+
+/*
+ACTION LOG SEGMENTS
+
+The action log is the fastest-growing part of the data and every entry is
+written once and then only read, so it is kept out of db.json in an
+append-only directory next to it:
+
+	actions/2024-05.jsonl
+	actions/2024-06.jsonl
+
+Each line is one entry, and entries go to the segment of the month they
+were logged in, so the log rotates monthly. Logging an action appends one
+line instead of rewriting the whole database.
+
+At startup only the recent segments are read, which is all rate limiting
+needs. Queries reaching further back (full history, stats, maintenance)
+read the older segments once on demand. Maintenance that changes past
+entries (purge, compact, merge, repair) rewrites the segments it needs
+to, and drops segments left empty.
+
+Entries in db.json files written before the split are moved into the
+segments the first time the file is opened.
+*/
+
+// recentSegments is how many monthly segments, counting the current one,
+// are read at startup
+const recentSegments = 2
+
+// segmentLayout names segment files by the month of their entries
+const segmentLayout = "2006-01"
+
+// logDir returns the directory holding the action log segments
+func (s *Storage) logDir() string {
+	return filepath.Join(filepath.Dir(s.path), "actions")
+}
+
+// segmentOf returns the segment name an entry belongs to
+func segmentOf(log ActionLog) string {
+	return log.Timestamp.UTC().Format(segmentLayout)
+}
+
+// listSegments returns the segment names on disk, oldest first
+func (s *Storage) listSegments() ([]string, error) {
+	entries, err := os.ReadDir(s.logDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list action log: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".jsonl")
+		if !ok || e.IsDir() {
+			continue
+		}
+		if _, err := time.Parse(segmentLayout, name); err == nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// readSegment parses one segment file, decrypting sealed lines. A torn last
+// line, as a crash mid append leaves behind, is skipped; corruption
+// anywhere else is an error.
+func (s *Storage) readSegment(name string) ([]ActionLog, error) {
+	path := filepath.Join(s.logDir(), name+".jsonl")
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var logs []ActionLog
+	lines := bytes.Split(raw, []byte("\n"))
+	for i, line := range lines {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if plain := line[0] == '{'; plain == seal.Enabled() {
+			s.logsResealed = true
+		}
+		line, err := seal.OpenLine(line, seal.LabelActionLog)
+		if err == seal.ErrNoKey {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		var log ActionLog
+		if err == nil {
+			err = json.Unmarshal(line, &log)
+		}
+		if err != nil {
+			if i == len(lines)-1 {
+				s.log.Warn("Skipping torn action log line", "segment", name)
+				break
+			}
+			return nil, fmt.Errorf("%s:%d: corrupt action log entry: %w", path, i+1, err)
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
+
+// loadLogsLocked reads the recent segments, or every segment if all is
+// set; the caller must hold s.mu
+func (s *Storage) loadLogsLocked(all bool) error {
+	names, err := s.listSegments()
+	if err != nil {
+		return err
+	}
+
+	from := time.Time{}
+	if !all {
+		now := clock.Now().UTC()
+		from = time.Date(now.Year(), now.Month()-recentSegments+1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	logs := make([]ActionLog, 0)
+	for _, name := range names {
+		if !from.IsZero() && name < from.Format(segmentLayout) {
+			continue
+		}
+		segment, err := s.readSegment(name)
+		if err != nil {
+			return err
+		}
+		logs = append(logs, segment...)
+	}
+	sort.SliceStable(logs, func(i, j int) bool { return logs[i].Timestamp.Before(logs[j].Timestamp) })
+
+	s.logs = logs
+	s.logsFrom = from
+	actionLogCount.Set(float64(len(s.logs)))
+	return nil
+}
+
+// allLogsLocked makes sure the whole log is in memory; the caller must
+// hold s.mu for writing
+func (s *Storage) allLogsLocked() error {
+	if s.logsFrom.IsZero() {
+		return nil
+	}
+	if err := s.loadLogsLocked(true); err != nil {
+		return fmt.Errorf("failed to read action log: %w", err)
+	}
+	return nil
+}
+
+// logsSince makes sure every entry after since is in memory, reading the
+// older segments if since predates the recent ones. A read failure is
+// logged and the loaded entries are used.
+func (s *Storage) logsSince(since time.Time) {
+	s.mu.RLock()
+	loaded := s.logsFrom.IsZero() || !since.Before(s.logsFrom)
+	s.mu.RUnlock()
+	if loaded {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.allLogsLocked(); err != nil {
+		s.log.Error("Counting recent actions only", "error", err)
+	}
+}
+
+// tailLocked returns the in-memory entries logged after since; the caller
+// must hold s.mu
+func (s *Storage) tailLocked(since time.Time) []ActionLog {
+	i := sort.Search(len(s.logs), func(i int) bool { return s.logs[i].Timestamp.After(since) })
+	return s.logs[i:]
+}
+
+// appendLogsLocked writes entries to the end of their segments and then
+// adds them to memory; the caller must hold s.mu
+func (s *Storage) appendLogsLocked(logs []ActionLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	if s.readOnly {
+		return ErrReadOnly
+	}
+	if err := os.MkdirAll(s.logDir(), 0700); err != nil {
+		return fmt.Errorf("failed to create action log directory: %w", err)
+	}
+
+	bySegment := make(map[string][]byte)
+	var order []string
+	for _, log := range logs {
+		line, err := marshalLogLine(log)
+		if err != nil {
+			return err
+		}
+		name := segmentOf(log)
+		if _, ok := bySegment[name]; !ok {
+			order = append(order, name)
+		}
+		bySegment[name] = append(append(bySegment[name], line...), '\n')
+	}
+	for _, name := range order {
+		path := filepath.Join(s.logDir(), name+".jsonl")
+		if err := trimTornTail(path); err != nil {
+			return fmt.Errorf("failed to repair action log: %w", err)
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open action log: %w", err)
+		}
+		_, err = f.Write(bySegment[name])
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to append to action log: %w", err)
+		}
+	}
+
+	for _, log := range logs {
+		s.insertLogLocked(log)
+	}
+	actionLogCount.Set(float64(len(s.logs)))
+	return nil
+}
+
+// trimTornTail cuts a torn last line, as a crash mid append leaves behind,
+// off a segment so the next entry starts on a line of its own
+func trimTornTail(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return err
+	}
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, info.Size()-1); err != nil || last[0] == '\n' {
+		return err
+	}
+	raw := make([]byte, info.Size())
+	if _, err := f.ReadAt(raw, 0); err != nil {
+		return err
+	}
+	return f.Truncate(int64(bytes.LastIndexByte(raw, '\n') + 1))
+}
+
+// marshalLogLine encodes an entry as a segment line, sealed if encryption
+// is enabled
+func marshalLogLine(log ActionLog) ([]byte, error) {
+	line, err := json.Marshal(log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal action log entry: %w", err)
+	}
+	if line, err = seal.SealLine(line, seal.LabelActionLog); err != nil {
+		return nil, fmt.Errorf("failed to encrypt action log entry: %w", err)
+	}
+	return line, nil
+}
+
+// insertLogLocked adds an entry to memory, keeping the log in time order
+// even if the clock stepped back; the caller must hold s.mu
+func (s *Storage) insertLogLocked(log ActionLog) {
+	i := len(s.logs)
+	for i > 0 && s.logs[i-1].Timestamp.After(log.Timestamp) {
+		i--
+	}
+	s.logs = append(s.logs, ActionLog{})
+	copy(s.logs[i+1:], s.logs[i:])
+	s.logs[i] = log
+}
+
+// rewriteLogsLocked replaces the segments with the in-memory log after
+// maintenance changed past entries. The whole log must be loaded. Each
+// segment is replaced atomically and segments left empty are removed;
+// the caller must hold s.mu.
+func (s *Storage) rewriteLogsLocked() error {
+	if s.readOnly {
+		return ErrReadOnly
+	}
+	if !s.logsFrom.IsZero() {
+		return fmt.Errorf("action log rewrite needs the whole log loaded")
+	}
+	existing, err := s.listSegments()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.logDir(), 0700); err != nil {
+		return fmt.Errorf("failed to create action log directory: %w", err)
+	}
+
+	bySegment := make(map[string]*bytes.Buffer)
+	for _, log := range s.logs {
+		line, err := marshalLogLine(log)
+		if err != nil {
+			return err
+		}
+		name := segmentOf(log)
+		if bySegment[name] == nil {
+			bySegment[name] = &bytes.Buffer{}
+		}
+		bySegment[name].Write(line)
+		bySegment[name].WriteByte('\n')
+	}
+	for name, buf := range bySegment {
+		path := filepath.Join(s.logDir(), name+".jsonl")
+		if err := writeFileAtomic(path, buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to rewrite action log: %w", err)
+		}
+	}
+	for _, name := range existing {
+		if bySegment[name] == nil {
+			if err := os.Remove(filepath.Join(s.logDir(), name+".jsonl")); err != nil {
+				return fmt.Errorf("failed to remove empty action log segment: %w", err)
+			}
+		}
+	}
+	actionLogCount.Set(float64(len(s.logs)))
+	return nil
+}
+
+// migrateLogsLocked moves entries still in db.json, from before the log
+// was split out, into the segments; the caller must hold s.mu. Entries
+// already in the segments, as a crash before db.json was saved leaves
+// them, are not appended again.
+func (s *Storage) migrateLogsLocked() error {
+	legacy := s.data.ActionLogs
+	if len(legacy) == 0 {
+		return nil
+	}
+	if err := s.allLogsLocked(); err != nil {
+		return fmt.Errorf("failed to migrate action log: %w", err)
+	}
+	type entry struct {
+		at              int64
+		action, profile string
+	}
+	moved := make(map[entry]bool, len(s.logs))
+	for _, log := range s.logs {
+		moved[entry{log.Timestamp.UnixNano(), log.Action, log.ProfileID}] = true
+	}
+	pending := make([]ActionLog, 0, len(legacy))
+	for _, log := range legacy {
+		if !moved[entry{log.Timestamp.UnixNano(), log.Action, log.ProfileID}] {
+			pending = append(pending, log)
+		}
+	}
+	if err := s.appendLogsLocked(pending); err != nil {
+		return fmt.Errorf("failed to migrate action log: %w", err)
+	}
+	s.data.ActionLogs = make([]ActionLog, 0)
+	if err := s.saveLocked(); err != nil {
+		return fmt.Errorf("failed to migrate action log: %w", err)
+	}
+	s.log.Info("Moved action log out of db.json", "entries", len(legacy), "dir", s.logDir())
+	return nil
+}
+
+// logDirSize returns the combined size of the segments on disk
+func (s *Storage) logDirSize() int64 {
+	var size int64
+	names, _ := s.listSegments()
+	for _, name := range names {
+		if info, err := os.Stat(filepath.Join(s.logDir(), name+".jsonl")); err == nil {
+			size += info.Size()
+		}
+	}
+	return size
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+)
+
+func TestActionLogSegments(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 3, 20, 9, 0, 0, 0, time.UTC))
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "db.json")
+
+	// A file from before the split still carries its log
+	legacy := newData()
+	legacy.ActionLogs = []ActionLog{{Action: "connection", Timestamp: fake.Now().AddDate(0, -2, 0), Success: true}}
+	raw, err := json.Marshal(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := New(path, config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := os.ReadFile(path); json.Unmarshal(raw, newData()) != nil || bytes.Contains(raw, []byte("action_logs")) {
+		t.Error("action log left in db.json after migration")
+	}
+	for i := 0; i < 2; i++ {
+		if i > 0 {
+			fake.Advance(31 * 24 * time.Hour)
+		}
+		if err := db.LogAction("connection", "p1", true, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"2024-01", "2024-03", "2024-04"} {
+		if _, err := os.Stat(filepath.Join(dir, "actions", name+".jsonl")); err != nil {
+			t.Errorf("segment %s: %v", name, err)
+		}
+	}
+
+	// Reopened, only the recent segments are read until history is asked for
+	reopened, err := New(path, config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reopened.logs) != 2 {
+		t.Errorf("loaded %d entries at startup, want the 2 recent ones", len(reopened.logs))
+	}
+	if n := reopened.GetActionCountSince("connection", fake.Now().Add(-24*time.Hour)); n != 1 {
+		t.Errorf("connections in the last day = %d, want 1", n)
+	}
+	if n := reopened.GetActionCountSince("connection", time.Time{}); n != 3 {
+		t.Errorf("connections ever = %d, want 3", n)
+	}
+
+	// Purging drops the segments left empty
+	if _, err := reopened.Purge(time.Time{}, time.Time{}, fake.Now().AddDate(0, -1, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "actions", "2024-01.jsonl")); !os.IsNotExist(err) {
+		t.Errorf("purged segment still on disk: %v", err)
+	}
+	if n := len(reopened.GetActionLogs("")); n != 2 {
+		t.Errorf("%d entries after purge, want 2", n)
+	}
+}
+
+func TestActionLogAppendAfterTornLine(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 3, 20, 9, 0, 0, 0, time.UTC))
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	path := filepath.Join(t.TempDir(), "db.json")
+	db, err := New(path, config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.LogAction("connection", "p1", true, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// A crash mid append leaves half a line behind
+	segment := filepath.Join(filepath.Dir(path), "actions", "2024-03.jsonl")
+	f, err := os.OpenFile(segment, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"action":"connection","profile_`)
+	f.Close()
+
+	reopened, err := New(path, config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reopened.LogAction("connection", "p2", true, nil); err != nil {
+		t.Fatal(err)
+	}
+	again, err := New(path, config.StorageConfig{})
+	if err != nil {
+		t.Fatalf("reopening after appending to a torn segment: %v", err)
+	}
+	if n := len(again.GetActionLogs("")); n != 2 {
+		t.Errorf("%d entries, want 2", n)
+	}
+}
+
+func TestReadOnlyLeavesFilesAlone(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 3, 20, 9, 0, 0, 0, time.UTC))
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "db.json")
+
+	// Missing files read as empty and are not created
+	empty, err := NewReadOnly(path, config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(empty.GetAllProfiles()); n != 0 {
+		t.Errorf("%d profiles in a missing file, want 0", n)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("db.json created by a read-only open: %v", err)
+	}
+
+	// A file from before the split is read but not migrated
+	legacy := newData()
+	legacy.ActionLogs = []ActionLog{{Action: "connection", Timestamp: fake.Now().Add(-time.Hour), Success: true}}
+	raw, err := json.Marshal(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+	db, err := NewReadOnly(path, config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := db.GetActionCountToday("connection"); n != 1 {
+		t.Errorf("%d connections today, want 1", n)
+	}
+	if err := db.LogAction("connection", "p1", true, nil); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("logging read-only: got %v, want ErrReadOnly", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := os.ReadFile(path); !bytes.Equal(after, raw) {
+		t.Error("db.json changed by a read-only open")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "actions")); !os.IsNotExist(err) {
+		t.Errorf("action log written by a read-only open: %v", err)
+	}
+}
+
+func TestMigrationAfterCrash(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 3, 20, 9, 0, 0, 0, time.UTC))
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "db.json")
+	legacy := newData()
+	legacy.ActionLogs = []ActionLog{
+		{Action: "connection", ProfileID: "p1", Timestamp: fake.Now().AddDate(0, -2, 0), Success: true},
+		{Action: "message", ProfileID: "p1", Timestamp: fake.Now().Add(-time.Hour), Success: true},
+	}
+	raw, err := json.Marshal(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(path, config.StorageConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A crash after the segments were written but before db.json was
+	// saved leaves the entries in both
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+	db, err := New(path, config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(db.GetActionLogs("")); n != 2 {
+		t.Errorf("%d entries after migrating twice, want 2", n)
+	}
+
+	segment, err := os.Stat(filepath.Join(dir, "actions", "2024-03.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := segment.Mode().Perm(); mode != 0600 {
+		t.Errorf("segment mode = %v, want 0600", mode)
+	}
+}
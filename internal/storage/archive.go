@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+/*
+CONTENT ARCHIVE
+
+Every message is stored as it was sent, rendered, and every operator note
+is kept on its profile, so together they are an archive of what was said
+to whom. SearchArchive finds entries by their words: the query is split
+into terms, a quoted "several words" term matching those words in a row,
+and every term has to match the start of a word, case-insensitively, so
+"connect" finds "Connecting" but "nect" doesn't.
+
+The JSON file is scanned. The Postgres backend looks the words up in
+full-text indexes on message content and notes first, then applies the
+same matching to the candidates, so both backends return the same hits.
+*/
+
+// Archive hit kinds
+const (
+	ArchiveMessage = "message"
+	ArchiveNote    = "note"
+)
+
+// ArchiveQuery selects archive entries. Text is required; the zero value
+// of the other fields doesn't filter.
+type ArchiveQuery struct {
+	Text      string
+	ProfileID string
+	Kind      string    // ArchiveMessage or ArchiveNote
+	Since     time.Time // Sent or noted at or after
+	Limit     int       // Newest hits kept, all if 0
+}
+
+// ArchiveHit is one message or note matching a search
+type ArchiveHit struct {
+	Kind      string    `json:"kind"`
+	ProfileID string    `json:"profile_id"`
+	Name      string    `json:"name"` // Profile name, empty if it was purged
+	At        time.Time `json:"at"`   // Zero for notes written before timestamps
+	Text      string    `json:"text"`
+	Inbound   bool      `json:"inbound,omitempty"`  // A reply from the profile
+	Template  string    `json:"template,omitempty"` // The message's template
+}
+
+// archiveTerm is one search term: the words it must match in a row
+type archiveTerm []string
+
+// archiveWords splits text into lower-case words of letters and digits
+func archiveWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// terms parses the query text into terms
+func (q ArchiveQuery) terms() ([]archiveTerm, error) {
+	var terms []archiveTerm
+	for i, part := range strings.Split(q.Text, `"`) {
+		if i%2 == 1 {
+			if words := archiveWords(part); len(words) > 0 {
+				terms = append(terms, words)
+			}
+			continue
+		}
+		for _, word := range archiveWords(part) {
+			terms = append(terms, archiveTerm{word})
+		}
+	}
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("search needs at least one word")
+	}
+	if q.Kind != "" && q.Kind != ArchiveMessage && q.Kind != ArchiveNote {
+		return nil, fmt.Errorf("unknown archive kind %q (want %s or %s)", q.Kind, ArchiveMessage, ArchiveNote)
+	}
+	return terms, nil
+}
+
+// in reports whether the term matches the words
+func (t archiveTerm) in(words []string) bool {
+	for i := 0; i+len(t) <= len(words); i++ {
+		match := true
+		for k, w := range t {
+			// Only the last word of a phrase may be cut short
+			if (k < len(t)-1 && words[i+k] != w) || !strings.HasPrefix(words[i+k], w) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAll reports whether every term matches text
+func matchesAll(terms []archiveTerm, text string) bool {
+	words := archiveWords(text)
+	for _, t := range terms {
+		if !t.in(words) {
+			return false
+		}
+	}
+	return true
+}
+
+// searchArchive returns the messages and notes of the candidates that match
+// the query, newest first
+func searchArchive(q ArchiveQuery, terms []archiveTerm, messages []*Message, profiles []*Profile, profileOf func(id string) (*Profile, bool)) []ArchiveHit {
+	hits := make([]ArchiveHit, 0)
+	keep := func(profileID string, at time.Time) bool {
+		return (q.ProfileID == "" || profileID == q.ProfileID) && (q.Since.IsZero() || !at.Before(q.Since))
+	}
+
+	if q.Kind != ArchiveNote {
+		for _, msg := range messages {
+			if !keep(msg.ProfileID, msg.SentAt) || !matchesAll(terms, msg.Content) {
+				continue
+			}
+			hit := ArchiveHit{Kind: ArchiveMessage, ProfileID: msg.ProfileID, At: msg.SentAt,
+				Text: msg.Content, Inbound: msg.Inbound, Template: msg.Template}
+			if p, ok := profileOf(msg.ProfileID); ok {
+				hit.Name = p.Name
+			}
+			hits = append(hits, hit)
+		}
+	}
+	if q.Kind != ArchiveMessage {
+		for _, p := range profiles {
+			for _, note := range p.NoteList() {
+				if !keep(p.ID, note.At) || !matchesAll(terms, note.Text) {
+					continue
+				}
+				hits = append(hits, ArchiveHit{Kind: ArchiveNote, ProfileID: p.ID, Name: p.Name, At: note.At, Text: note.Text})
+			}
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		if !hits[i].At.Equal(hits[j].At) {
+			return hits[i].At.After(hits[j].At)
+		}
+		return hits[i].ProfileID < hits[j].ProfileID
+	})
+	if q.Limit > 0 && len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+	}
+	return hits
+}
+
+// SearchArchive returns the sent and received messages and operator notes
+// matching the query, newest first
+func (s *Storage) SearchArchive(q ArchiveQuery) ([]ArchiveHit, error) {
+	terms, err := q.terms()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	messages := make([]*Message, 0, len(s.data.Messages))
+	for _, msg := range s.data.Messages {
+		messages = append(messages, msg)
+	}
+	profiles := make([]*Profile, 0)
+	for _, p := range s.data.Profiles {
+		if p.Notes != "" {
+			profiles = append(profiles, p)
+		}
+	}
+	return searchArchive(q, terms, messages, profiles, func(id string) (*Profile, bool) {
+		p, ok := s.data.Profiles[id]
+		return p, ok
+	}), nil
+}
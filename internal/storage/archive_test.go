@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"subspace/internal/config"
+)
+
+func TestSearchArchive(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2024, 5, 1, 9, 0, 0, 0, time.Local)
+	p := &Profile{ID: "p1", Name: "Grace Hopper", ProfileURL: "https://www.linkedin.com/in/grace"}
+	if err := p.AddNote("Met at GopherCon, loves COBOL", day.Add(2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveProfile(p); err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range []*Message{
+		{ID: "m1", ProfileID: "p1", Content: "Thanks for connecting! Happy to talk about compilers.", SentAt: day, Template: "follow_up"},
+		{ID: "m2", ProfileID: "p1", Content: "Sure, let's talk compilers next week", SentAt: day.Add(time.Hour), Inbound: true},
+		{ID: "m3", ProfileID: "gone", Content: "Compilers are fun", SentAt: day.Add(-time.Hour)},
+	} {
+		if err := db.SaveMessage(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ids := func(q ArchiveQuery) []string {
+		t.Helper()
+		hits, err := db.SearchArchive(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, h := range hits {
+			out = append(out, h.Kind+":"+h.At.Format("15"))
+		}
+		return out
+	}
+	tests := []struct {
+		q    ArchiveQuery
+		want []string
+	}{
+		{ArchiveQuery{Text: "compiler"}, []string{"message:10", "message:09", "message:08"}},
+		{ArchiveQuery{Text: "COMPILERS talk"}, []string{"message:10", "message:09"}},
+		{ArchiveQuery{Text: "piler"}, nil},
+		{ArchiveQuery{Text: `"talk compilers"`}, []string{"message:10"}},
+		{ArchiveQuery{Text: "gophercon"}, []string{"note:11"}},
+		{ArchiveQuery{Text: "compilers", ProfileID: "p1", Limit: 1}, []string{"message:10"}},
+		{ArchiveQuery{Text: "compilers", Since: day}, []string{"message:10", "message:09"}},
+		{ArchiveQuery{Text: "cobol", Kind: ArchiveMessage}, nil},
+	}
+	for _, tt := range tests {
+		got := ids(tt.q)
+		if len(got) != len(tt.want) {
+			t.Errorf("%+v = %v, want %v", tt.q, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%+v = %v, want %v", tt.q, got, tt.want)
+				break
+			}
+		}
+	}
+
+	hits, _ := db.SearchArchive(ArchiveQuery{Text: "fun"})
+	if len(hits) != 1 || hits[0].Name != "" || hits[0].Inbound {
+		t.Errorf("hit of purged profile = %+v", hits)
+	}
+	if _, err := db.SearchArchive(ArchiveQuery{Text: " !? "}); err == nil {
+		t.Error("empty search accepted")
+	}
+}
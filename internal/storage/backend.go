@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+/*
+BACKENDS
+
+The automation modules (search, connect, messaging, auth, enrichment and
+the rate limiter) depend on Backend, the operations on profiles, messages,
+the action log and the company cache, instead of on the JSON file.
+Storage, the db.json file, is one Backend; another database can stand in
+for it without changes to the modules.
+
+Retention purges, reports and exports go through Backend too, so they
+see the data the automation wrote. Compaction, verification and merging
+duplicates stay on Storage: they work on the file itself.
+*/
+
+// Backend stores profiles, messages and the action log
+type Backend interface {
+	// Profiles
+	SaveProfile(profile *Profile) error
+	GetProfile(id string) (*Profile, error)
+	GetProfilesByState(state ProfileState) []*Profile
+	ListProfiles(opts ListOptions) (ProfilePage, error)
+	GetAllProfiles() []*Profile
+	ConnectCandidates(requireApproval bool) []*Profile
+	ProfileExists(profileURL string) bool
+	FindDuplicate(p *Profile) *Profile
+	Claim(profileID string) error
+	SnoozedProfiles(now time.Time) []*Profile
+	AddTag(profileID, tag string) error
+	RemoveTag(profileID, tag string) error
+
+	// Messages
+	SaveMessage(message *Message) error
+	GetMessagesByProfile(profileID string) []*Message
+	GetMessagesSince(since time.Time) []*Message
+	HasReplied(profileID string) bool
+	Inbox() []Conversation
+	SearchArchive(q ArchiveQuery) ([]ArchiveHit, error)
+
+	// Action log
+	LogAction(action, profileID string, success bool, err error) error
+	LogActionFrom(source, action, profileID string, success bool, err error) error
+	GetActionLogs(action string) []ActionLog
+	GetActionCountSince(action string, since time.Time) int
+	GetActionCountToday(action string) int
+	GetActionCountLastHour(action string) int
+	GetProfileTouchesSince(profileID string, since time.Time) int
+
+	// Company enrichment cache
+	CachedCompany(name string) *CompanyInfo
+	SaveCompanies(companies []*CompanyInfo, profiles []*Profile) error
+
+	// Transaction commits everything fn stages at once, or nothing
+	Transaction(fn func(tx *Tx) error) error
+
+	// Reports, exports and retention
+	GetStats() Stats
+	Activity(f StatsFilter) Activity
+	ExportCSV(w io.Writer, f ExportFilter) (int, error)
+	Purge(profilesBefore, messagesBefore, logsBefore time.Time) (PurgeResult, error)
+
+	// Close writes what is buffered and releases the backend
+	Close() error
+}
+
+// Storage is the JSON file backend
+var _ Backend = (*Storage)(nil)
+
+// ErrClaimed is returned by Claim for a profile another machine is working on
+var ErrClaimed = errors.New("profile claimed by another machine")
+
+// Claim reserves a profile for this process before it is acted on. Only
+// one process opens db.json at a time (see the run lock), so there is no
+// one to claim it from.
+func (s *Storage) Claim(profileID string) error {
+	return nil
+}
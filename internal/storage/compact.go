@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+)
+
+// CompactResult is what a compaction removed and how much it saved
+type CompactResult struct {
+	Tombstones       int   `json:"tombstones"`        // Null records and aliases to profiles no longer stored
+	DuplicateLogs    int   `json:"duplicate_logs"`    // Action log entries identical to an earlier one
+	OrphanedMessages int   `json:"orphaned_messages"` // Messages of profiles no longer stored
+	BytesBefore      int64 `json:"bytes_before"`
+	BytesAfter       int64 `json:"bytes_after"`
+}
+
+// Removed returns how many records the compaction dropped
+func (r CompactResult) Removed() int {
+	return r.Tombstones + r.DuplicateLogs + r.OrphanedMessages
+}
+
+// Compact drops what no query can reach any more and rewrites the file,
+// even when nothing was dropped, leaving no temporary file behind:
+//   - null records and aliases pointing at profiles that were purged
+//   - action log entries duplicated exactly (same action, time, profile,
+//     outcome and source), as replayed writes leave them
+//   - messages whose profile is gone
+func (s *Storage) Compact() (CompactResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result CompactResult
+	if err := s.allLogsLocked(); err != nil {
+		return result, err
+	}
+	if info, err := os.Stat(s.path); err == nil {
+		result.BytesBefore = info.Size()
+	}
+	result.BytesBefore += s.logDirSize()
+
+	for id, p := range s.data.Profiles {
+		if p == nil {
+			delete(s.data.Profiles, id)
+			result.Tombstones++
+		}
+	}
+	for key, c := range s.data.Companies {
+		if c == nil {
+			delete(s.data.Companies, key)
+			result.Tombstones++
+		}
+	}
+	for key, id := range s.data.Aliases {
+		if _, ok := s.data.Profiles[id]; !ok {
+			delete(s.data.Aliases, key)
+			result.Tombstones++
+		}
+	}
+	for id, msg := range s.data.Messages {
+		if msg == nil {
+			delete(s.data.Messages, id)
+			result.Tombstones++
+		} else if _, ok := s.data.Profiles[msg.ProfileID]; !ok {
+			delete(s.data.Messages, id)
+			result.OrphanedMessages++
+		}
+	}
+
+	seen := make(map[ActionLog]bool, len(s.logs))
+	logs := make([]ActionLog, 0, len(s.logs))
+	for _, l := range s.logs {
+		if seen[l] {
+			result.DuplicateLogs++
+			continue
+		}
+		seen[l] = true
+		logs = append(logs, l)
+	}
+	s.logs = logs
+
+	if err := s.saveLocked(); err != nil {
+		return result, fmt.Errorf("failed to rewrite storage: %w", err)
+	}
+	if err := s.rewriteLogsLocked(); err != nil {
+		return result, fmt.Errorf("failed to rewrite storage: %w", err)
+	}
+	if info, err := os.Stat(s.path); err == nil {
+		result.BytesAfter = info.Size()
+	}
+	result.BytesAfter += s.logDirSize()
+	s.log.Info("Storage compacted", "removed", result.Removed(),
+		"bytes_before", result.BytesBefore, "bytes_after", result.BytesAfter)
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+)
+
+func TestCompactDropsUnreachableRecords(t *testing.T) {
+	clock.Set(clock.NewFake(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)))
+	defer clock.Set(clock.Real{})
+
+	path := filepath.Join(t.TempDir(), "db.json")
+	db, err := New(path, config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveProfile(&Profile{ID: "kept", ProfileURL: "https://www.linkedin.com/in/kept"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range []*Message{{ID: "m1", ProfileID: "kept"}, {ID: "m2", ProfileID: "purged"}} {
+		if err := db.SaveMessage(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if err := db.LogAction("connection", "kept", true, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.LogAction("message", "kept", true, nil); err != nil {
+		t.Fatal(err)
+	}
+	db.data.Aliases["linkedin.com/in/gone"] = "purged"
+
+	result, err := db.Compact()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Tombstones != 1 || result.DuplicateLogs != 2 || result.OrphanedMessages != 1 {
+		t.Errorf("Compact = %+v, want 1 tombstone, 2 duplicate logs, 1 orphaned message", result)
+	}
+	if result.BytesAfter >= result.BytesBefore {
+		t.Errorf("size %d -> %d, want smaller", result.BytesBefore, result.BytesAfter)
+	}
+
+	reopened, err := New(path, config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(reopened.GetActionLogs("")); n != 2 {
+		t.Errorf("action logs after reopen = %d, want 2", n)
+	}
+	if _, ok := reopened.data.Messages["m1"]; !ok || len(reopened.data.Messages) != 1 {
+		t.Errorf("messages after reopen = %v, want only m1", reopened.data.Messages)
+	}
+
+	again, err := reopened.Compact()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Removed() != 0 {
+		t.Errorf("second Compact removed %d, want 0", again.Removed())
+	}
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"time"
+)
+
+// CompanyInfo is enrichment metadata about a company. Lookups are cached
+// per company, including those the provider didn't know, so each company
+// is queried once per refresh period.
+type CompanyInfo struct {
+	Name      string    `json:"name"`
+	Industry  string    `json:"industry,omitempty"`
+	Size      string    `json:"size,omitempty"` // Employee count bucket, e.g. "51-200"
+	Source    string    `json:"source"`         // Provider that answered
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// Known reports whether the provider had any data for the company
+func (c *CompanyInfo) Known() bool {
+	return c.Industry != "" || c.Size != ""
+}
+
+// CachedCompany returns the cached metadata for a company, matched by
+// CompanyKey, or nil if it was never looked up
+func (s *Storage) CachedCompany(name string) *CompanyInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.data.Companies[CompanyKey(name)]
+}
+
+// SaveCompanies caches company metadata and applies it to the given
+// profiles in a single write
+func (s *Storage) SaveCompanies(companies []*CompanyInfo, profiles []*Profile) error {
+	s.mu.Lock()
+	for _, c := range companies {
+		s.data.Companies[CompanyKey(c.Name)] = c
+	}
+	for _, p := range profiles {
+		NormalizeProfile(p)
+		s.data.Profiles[p.ID] = p
+	}
+	s.mu.Unlock()
+	return s.save()
+}
+
+// ApplyCompany copies company metadata onto the profile
+func (p *Profile) ApplyCompany(c *CompanyInfo) {
+	p.Industry, p.CompanySize = c.Industry, c.Size
+}
@@ -0,0 +1,373 @@
+package storage
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"subspace/internal/names"
+)
+
+/*
+DEDUPLICATION
+
+Two profiles are the same person when their profile keys match. The key is
+the lowercased public slug of a /in/<slug> URL, so
+"https://www.linkedin.com/in/Jane-Doe/", ".../in/jane-doe?trk=x" and
+"https://de.linkedin.com/in/jane-doe/en" all share the key "in/jane-doe".
+
+With storage.fuzzy_dedup enabled, profiles with different URLs also match
+when their parsed first/last names are (nearly) identical and they work at
+the same company. This catches people who changed their vanity URL.
+
+When duplicates are merged the losing profile's key is kept as an alias of
+the surviving profile so search does not rediscover it.
+*/
+
+// ProfileKey returns the identity key of a profile URL: "in/<slug>" for
+// public profile URLs, otherwise the canonical URL
+func ProfileKey(raw string) string {
+	canonical := CanonicalURL(raw)
+	u, err := url.Parse(canonical)
+	if err != nil || u.Host == "" {
+		return strings.ToLower(canonical)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) >= 2 && parts[0] == "in" && parts[1] != "" {
+		return "in/" + strings.ToLower(parts[1])
+	}
+	return strings.ToLower(canonical)
+}
+
+// FindDuplicate returns the stored profile that is the same person as p,
+// or nil if p is new
+func (s *Storage) FindDuplicate(p *Profile) *Profile {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key := ProfileKey(p.ProfileURL)
+	if id, ok := s.data.Aliases[key]; ok {
+		if existing, ok := s.data.Profiles[id]; ok {
+			return existing
+		}
+	}
+	for _, existing := range s.data.Profiles {
+		if existing.ID != p.ID && SameProfile(existing, p, s.fuzzyDedup) {
+			return existing
+		}
+	}
+	return nil
+}
+
+// SameProfile reports whether a and b describe the same person. Fuzzy
+// matching additionally compares names and companies.
+func SameProfile(a, b *Profile, fuzzy bool) bool {
+	if ProfileKey(a.ProfileURL) == ProfileKey(b.ProfileURL) {
+		return true
+	}
+	if !fuzzy {
+		return false
+	}
+
+	company := CompanyKey(a.Company)
+	if company == "" || company != CompanyKey(b.Company) {
+		return false
+	}
+	nameA, nameB := nameKey(a.Name), nameKey(b.Name)
+	if nameA == "" || nameB == "" {
+		return false
+	}
+	// Allow a single typo in longer names ("Jon Smith" vs "John Smith")
+	if len([]rune(nameA)) >= 8 {
+		return editDistance(nameA, nameB) <= 1
+	}
+	return nameA == nameB
+}
+
+// FindDuplicates groups stored profiles that describe the same person.
+// Each group is ordered with the profile that should survive a merge first.
+func (s *Storage) FindDuplicates(fuzzy bool) [][]*Profile {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	profiles := make([]*Profile, 0, len(s.data.Profiles))
+	for _, p := range s.data.Profiles {
+		profiles = append(profiles, p)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return mergeBefore(profiles[i], profiles[j]) })
+
+	var groups [][]*Profile
+	grouped := make(map[string]bool)
+	for i, p := range profiles {
+		if grouped[p.ID] {
+			continue
+		}
+		group := []*Profile{p}
+		for _, other := range profiles[i+1:] {
+			if !grouped[other.ID] && SameProfile(p, other, fuzzy) {
+				group = append(group, other)
+				grouped[other.ID] = true
+			}
+		}
+		if len(group) > 1 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// MergeDuplicates merges every group of profiles sharing a profile key and
+// returns the number of profiles merged away. Fuzzy matches are never merged
+// automatically.
+func (s *Storage) MergeDuplicates() (int, error) {
+	merged := 0
+	for _, group := range s.FindDuplicates(false) {
+		ids := make([]string, 0, len(group)-1)
+		for _, dup := range group[1:] {
+			ids = append(ids, dup.ID)
+		}
+		if _, err := s.Merge(group[0].ID, ids...); err != nil {
+			return merged, err
+		}
+		merged += len(ids)
+	}
+	return merged, nil
+}
+
+// Merge folds the duplicate profiles into keep and deletes them in a single
+// write. The merged profile takes the most advanced pipeline state, the
+// earliest timestamps and any fields keep is missing; messages and action
+// logs of the duplicates are reassigned to keep.
+func (s *Storage) Merge(keepID string, dupIDs ...string) (*Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keep, ok := s.data.Profiles[keepID]
+	if !ok {
+		return nil, fmt.Errorf("profile not found: %s", keepID)
+	}
+
+	reassigned := make(map[string]bool)
+	for _, id := range dupIDs {
+		dup, ok := s.data.Profiles[id]
+		if !ok {
+			return nil, fmt.Errorf("profile not found: %s", id)
+		}
+		if id == keepID {
+			return nil, fmt.Errorf("cannot merge profile %s into itself", id)
+		}
+		mergeProfile(keep, dup)
+		reassigned[id] = true
+	}
+	NormalizeProfile(keep)
+
+	for _, msg := range s.data.Messages {
+		if reassigned[msg.ProfileID] {
+			msg.ProfileID = keepID
+		}
+	}
+	if err := s.allLogsLocked(); err != nil {
+		return nil, fmt.Errorf("merge failed: %w", err)
+	}
+	relogged := false
+	for i := range s.logs {
+		if reassigned[s.logs[i].ProfileID] {
+			s.logs[i].ProfileID = keepID
+			relogged = true
+		}
+	}
+
+	keepKey := ProfileKey(keep.ProfileURL)
+	for id := range reassigned {
+		if key := ProfileKey(s.data.Profiles[id].ProfileURL); key != keepKey {
+			s.data.Aliases[key] = keepID
+		}
+		delete(s.data.Profiles, id)
+	}
+	for key, id := range s.data.Aliases {
+		if reassigned[id] {
+			s.data.Aliases[key] = keepID
+		}
+	}
+
+	err := s.saveLocked()
+	if err == nil && relogged {
+		err = s.rewriteLogsLocked()
+	}
+	if err != nil {
+		if rerr := s.rollbackLocked(); rerr != nil {
+			return nil, fmt.Errorf("merge failed: %v (rollback failed: %w)", err, rerr)
+		}
+		return nil, fmt.Errorf("merge failed: %w", err)
+	}
+	return keep, nil
+}
+
+// PreviewMerge returns what keep would look like after merging dups into
+// it, without touching storage
+func PreviewMerge(keep *Profile, dups ...*Profile) Profile {
+	merged := keep.Clone()
+	for _, dup := range dups {
+		mergeProfile(merged, dup)
+	}
+	NormalizeProfile(merged)
+	return *merged
+}
+
+// stateRank orders pipeline states from least to most advanced
+var stateRank = map[ProfileState]int{
+	StateDiscovered: 0,
+	StateSkipped:    1,
+	StateApproved:   2,
+	StateRequested:  3,
+	StateRejected:   4,
+	StateAccepted:   5,
+	StateCooledDown: 6,
+}
+
+// mergeBefore orders profiles so the best merge survivor comes first: the
+// most advanced state, then the earliest discovered
+func mergeBefore(a, b *Profile) bool {
+	if stateRank[a.State] != stateRank[b.State] {
+		return stateRank[a.State] > stateRank[b.State]
+	}
+	if !a.DiscoveredAt.Equal(b.DiscoveredAt) {
+		return a.DiscoveredAt.Before(b.DiscoveredAt)
+	}
+	return a.ID < b.ID
+}
+
+// mergeProfile folds dup into keep
+func mergeProfile(keep, dup *Profile) {
+	if stateRank[dup.State] > stateRank[keep.State] {
+		keep.State = dup.State
+	}
+	if !dup.DiscoveredAt.IsZero() && (keep.DiscoveredAt.IsZero() || dup.DiscoveredAt.Before(keep.DiscoveredAt)) {
+		keep.DiscoveredAt = dup.DiscoveredAt
+	}
+	keep.Shared = mergeShared(keep.Shared, dup.Shared)
+	keep.ReviewedAt = earliest(keep.ReviewedAt, dup.ReviewedAt)
+	keep.RequestedAt = earliest(keep.RequestedAt, dup.RequestedAt)
+	keep.AcceptedAt = earliest(keep.AcceptedAt, dup.AcceptedAt)
+	keep.CooledDownAt = earliest(keep.CooledDownAt, dup.CooledDownAt)
+	if dup.SnoozedUntil != nil && (keep.SnoozedUntil == nil || dup.SnoozedUntil.After(*keep.SnoozedUntil)) {
+		keep.SnoozedUntil, keep.SnoozeReason = dup.SnoozedUntil, dup.SnoozeReason
+	}
+
+	for _, f := range []struct{ dst, src *string }{
+		{&keep.Name, &dup.Name},
+		{&keep.Title, &dup.Title},
+		{&keep.Company, &dup.Company},
+		{&keep.Location, &dup.Location},
+		{&keep.Industry, &dup.Industry},
+		{&keep.CompanySize, &dup.CompanySize},
+		{&keep.SearchQuery, &dup.SearchQuery},
+	} {
+		if *f.dst == "" {
+			*f.dst = *f.src
+		}
+	}
+	if dup.Notes != "" && !strings.Contains(keep.Notes, dup.Notes) {
+		keep.Notes = strings.TrimSpace(keep.Notes + "\n" + dup.Notes)
+	}
+	for _, tag := range dup.Tags {
+		if !keep.HasTag(tag) {
+			keep.Tags = append(keep.Tags, tag)
+		}
+	}
+	sort.Strings(keep.Tags)
+}
+
+// mergeShared combines shared context, keeping the higher mutual count
+func mergeShared(a, b *SharedContext) *SharedContext {
+	if a == nil || b == nil {
+		if a == nil {
+			return b
+		}
+		return a
+	}
+	merged := &SharedContext{
+		MutualConnections: a.MutualConnections,
+		Groups:            union(a.Groups, b.Groups),
+		Schools:           union(a.Schools, b.Schools),
+	}
+	if b.MutualConnections > merged.MutualConnections {
+		merged.MutualConnections = b.MutualConnections
+	}
+	return merged
+}
+
+// union returns the distinct values of a followed by new values of b
+func union(a, b []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, v := range append(append([]string(nil), a...), b...) {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// earliest returns the earlier of two optional timestamps
+func earliest(a, b *time.Time) *time.Time {
+	if a == nil {
+		return b
+	}
+	if b != nil && b.Before(*a) {
+		return b
+	}
+	return a
+}
+
+// nameKey returns a lowercase letters-only "first last" key for fuzzy
+// name comparison
+func nameKey(raw string) string {
+	n := names.Parse(NormalizeText(raw))
+	if n.Family == "" {
+		return ""
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, n.First()+n.Last())
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minOf(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func minOf(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
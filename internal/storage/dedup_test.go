@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"subspace/internal/config"
+)
+
+func TestProfileKey(t *testing.T) {
+	tests := []struct{ url, want string }{
+		{"https://www.linkedin.com/in/Jane-Doe/", "in/jane-doe"},
+		{"https://www.linkedin.com/in/jane-doe?trk=x", "in/jane-doe"},
+		{"https://de.linkedin.com/in/jane-doe/en", "in/jane-doe"},
+		{"https://www.linkedin.com/company/acme", "https://www.linkedin.com/company/acme"},
+	}
+	for _, tt := range tests {
+		if got := ProfileKey(tt.url); got != tt.want {
+			t.Errorf("ProfileKey(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestSameProfile(t *testing.T) {
+	jane := &Profile{ProfileURL: "https://www.linkedin.com/in/jane-doe", Name: "Jane Doe", Company: "Acme Inc."}
+	tests := []struct {
+		other *Profile
+		exact bool // Same without fuzzy matching
+		fuzzy bool
+	}{
+		{&Profile{ProfileURL: "https://de.linkedin.com/in/Jane-Doe/"}, true, true},
+		{&Profile{ProfileURL: "https://www.linkedin.com/in/jd-2024", Name: "Jane Doe", Company: "ACME"}, false, true},
+		{&Profile{ProfileURL: "https://www.linkedin.com/in/jd-2024", Name: "Jane Doe", Company: "Globex"}, false, false},
+		{&Profile{ProfileURL: "https://www.linkedin.com/in/jd-2024", Name: "Jana Doe", Company: "Acme"}, false, false}, // Too short for a typo
+		{&Profile{ProfileURL: "https://www.linkedin.com/in/jd-2024", Name: "Jane", Company: "Acme"}, false, false},
+	}
+	for _, tt := range tests {
+		if got := SameProfile(jane, tt.other, false); got != tt.exact {
+			t.Errorf("SameProfile(%s, %s, false) = %v", jane.Name, tt.other.ProfileURL, got)
+		}
+		if got := SameProfile(jane, tt.other, true); got != tt.fuzzy {
+			t.Errorf("SameProfile(%s, %q at %s, true) = %v", jane.Name, tt.other.Name, tt.other.Company, got)
+		}
+	}
+
+	// Longer names allow a single typo
+	john := &Profile{ProfileURL: "https://www.linkedin.com/in/a", Name: "John Smithson", Company: "Acme"}
+	jon := &Profile{ProfileURL: "https://www.linkedin.com/in/b", Name: "Jon Smithson", Company: "Acme"}
+	if !SameProfile(john, jon, true) {
+		t.Error("one typo in a long name does not match")
+	}
+}
+
+func TestFindDuplicateAndMerge(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	requested := day.Add(time.Hour)
+	keep := &Profile{ID: "keep", ProfileURL: "https://www.linkedin.com/in/jane-doe", Name: "Jane Doe",
+		State: StateRequested, DiscoveredAt: day.Add(24 * time.Hour), RequestedAt: &requested, Tags: []string{"q3"}}
+	dup := &Profile{ID: "dup", ProfileURL: "https://www.linkedin.com/in/Jane-Doe/?trk=x", Title: "CTO", Company: "Acme",
+		State: StateDiscovered, DiscoveredAt: day, Tags: []string{"vip"}, Notes: "Met at GopherCon"}
+	for _, p := range []*Profile{keep, dup} {
+		if err := db.SaveProfile(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.SaveMessage(&Message{ID: "m1", ProfileID: "dup"})
+	db.LogAction("connection", "dup", true, nil)
+
+	if found := db.FindDuplicate(&Profile{ID: "new", ProfileURL: "https://www.linkedin.com/in/jane-doe/"}); found == nil {
+		t.Error("FindDuplicate missed a profile with the same key")
+	}
+	if found := db.FindDuplicate(&Profile{ID: "new", ProfileURL: "https://www.linkedin.com/in/someone-else"}); found != nil {
+		t.Errorf("FindDuplicate = %s for a new profile", found.ID)
+	}
+	groups := db.FindDuplicates(false)
+	if len(groups) != 1 || groups[0][0].ID != "keep" || groups[0][1].ID != "dup" {
+		t.Fatalf("groups = %v, want keep (more advanced) then dup", groups)
+	}
+
+	merged, err := db.MergeDuplicates()
+	if err != nil || merged != 1 {
+		t.Fatalf("MergeDuplicates = %d, %v", merged, err)
+	}
+	p, err := db.GetProfile("keep")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.State != StateRequested || !p.DiscoveredAt.Equal(day) || p.Title != "CTO" || p.Company != "Acme" {
+		t.Errorf("merged profile = %+v", p)
+	}
+	if !slices.Equal(p.Tags, []string{"q3", "vip"}) || p.Notes != "Met at GopherCon" {
+		t.Errorf("merged tags %v, notes %q", p.Tags, p.Notes)
+	}
+	if _, err := db.GetProfile("dup"); err == nil {
+		t.Error("duplicate still stored after the merge")
+	}
+	if n := len(db.GetMessagesByProfile("keep")); n != 1 {
+		t.Errorf("%d messages moved to the survivor, want 1", n)
+	}
+	if logs := db.GetActionLogs("connection"); len(logs) != 1 || logs[0].ProfileID != "keep" {
+		t.Errorf("connection log = %+v, want it reassigned to keep", logs)
+	}
+
+	if _, err := db.Merge("keep", "keep"); err == nil {
+		t.Error("merging a profile into itself succeeded")
+	}
+}
+
+func TestMergeKeepsAlias(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{FuzzyDedup: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []*Profile{
+		{ID: "a", ProfileURL: "https://www.linkedin.com/in/jane-doe", Name: "Jane Doe", Company: "Acme"},
+		{ID: "b", ProfileURL: "https://www.linkedin.com/in/jd-2024", Name: "Jane Doe", Company: "Acme"},
+	} {
+		if err := db.SaveProfile(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Merge("a", "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The merged-away URL still finds the survivor, even by another name
+	found := db.FindDuplicate(&Profile{ID: "new", ProfileURL: "https://www.linkedin.com/in/jd-2024", Name: "J. Doe"})
+	if found == nil || found.ID != "a" {
+		t.Errorf("FindDuplicate by alias = %v, want a", found)
+	}
+}
+
+func TestPreviewMerge(t *testing.T) {
+	day := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	keep := &Profile{ID: "keep", ProfileURL: "https://www.linkedin.com/in/jane-doe", State: StateApproved,
+		DiscoveredAt: day, Tags: make([]string, 2, 4), Shared: &SharedContext{MutualConnections: 2, Groups: []string{"Go"}}}
+	keep.Tags[0], keep.Tags[1] = "q3", "zz"
+	dup := &Profile{ID: "dup", ProfileURL: "https://www.linkedin.com/in/jane-doe/", State: StateAccepted, Company: "Acme",
+		Tags: []string{"a-list"}, Shared: &SharedContext{MutualConnections: 5, Schools: []string{"MIT"}}}
+
+	preview := PreviewMerge(keep, dup)
+	if preview.State != StateAccepted || preview.Company != "Acme" || preview.MutualConnections() != 5 {
+		t.Errorf("preview = %+v", preview)
+	}
+	if !slices.Equal(preview.Tags, []string{"a-list", "q3", "zz"}) {
+		t.Errorf("preview tags = %v", preview.Tags)
+	}
+
+	// The profiles previewed are left as they were
+	if keep.State != StateApproved || keep.Company != "" || !slices.Equal(keep.Tags, []string{"q3", "zz"}) || keep.Shared.MutualConnections != 2 {
+		t.Errorf("PreviewMerge changed keep: %+v", keep)
+	}
+}
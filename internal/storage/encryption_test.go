@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"subspace/internal/config"
+	"subspace/internal/seal"
+)
+
+func TestEncryptionAtRest(t *testing.T) {
+	t.Setenv("TEST_STORAGE_KEY", "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	t.Cleanup(func() { seal.Configure(config.EncryptionConfig{}) })
+	path := filepath.Join(t.TempDir(), "db.json")
+	open := func(enabled bool) (*Storage, error) {
+		t.Helper()
+		if err := seal.Configure(config.EncryptionConfig{Enabled: enabled, KeyEnv: "TEST_STORAGE_KEY"}); err != nil {
+			t.Fatal(err)
+		}
+		return New(path, config.StorageConfig{})
+	}
+	onDisk := func() string {
+		t.Helper()
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		segments, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "actions", "*.jsonl"))
+		for _, segment := range segments {
+			log, _ := os.ReadFile(segment)
+			raw = append(raw, log...)
+		}
+		return string(raw)
+	}
+
+	// Plaintext data is encrypted in place when encryption is turned on
+	db, err := open(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SaveProfile(&Profile{ID: "p1", Name: "Grace Hopper", ProfileURL: "https://www.linkedin.com/in/grace"})
+	db.LogAction("connection", "p1", false, os.ErrDeadlineExceeded)
+	if !strings.Contains(onDisk(), "Grace Hopper") {
+		t.Fatal("plaintext data not written")
+	}
+
+	db, err = open(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Markers with a space or quotes, which base64 never produces
+	if strings.Contains(onDisk(), "Grace Hopper") || strings.Contains(onDisk(), `"profile_id":"p1"`) {
+		t.Fatalf("data left in plaintext: %s", onDisk())
+	}
+	db.LogAction("message", "p1", true, nil)
+	if p, err := db.GetProfile("p1"); err != nil || p.Name != "Grace Hopper" || len(db.GetActionLogs("")) != 2 {
+		t.Fatalf("sealed data not read back: %v, %v", p, err)
+	}
+	if raw, _ := os.ReadFile(path); !bytes.HasPrefix(raw, []byte("SEAL1")) {
+		t.Fatal("db.json not sealed")
+	}
+
+	// Without the key sealed data can't be read
+	os.Unsetenv("TEST_STORAGE_KEY")
+	if _, err := open(false); err == nil || !strings.Contains(err.Error(), "no key") {
+		t.Fatalf("opened without key: %v", err)
+	}
+
+	// With the key but encryption off, it is decrypted in place
+	os.Setenv("TEST_STORAGE_KEY", "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	db, err = open(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(onDisk(), "Grace Hopper") || strings.Count(onDisk(), `"profile_id":"p1"`) != 2 {
+		t.Fatalf("data not decrypted: %s", onDisk())
+	}
+	if len(db.GetActionLogs("")) != 2 {
+		t.Error("action log lost")
+	}
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportFilter narrows ExportCSV to some profiles. Zero values don't
+// filter.
+type ExportFilter struct {
+	States []ProfileState
+	Tag    string
+	Since  time.Time // Discovered at or after
+
+	// Match keeps only the profiles it returns true for, e.g. a targeting
+	// rule
+	Match func(*Profile) bool
+}
+
+// matches reports whether the filter keeps the profile
+func (f ExportFilter) matches(p *Profile) bool {
+	return (len(f.States) == 0 || slices.Contains(f.States, p.State)) &&
+		(f.Tag == "" || p.HasTag(f.Tag)) &&
+		(f.Since.IsZero() || !p.DiscoveredAt.Before(f.Since)) &&
+		(f.Match == nil || f.Match(p))
+}
+
+// exportColumns heads the CSV written by ExportCSV
+var exportColumns = []string{
+	"id", "name", "title", "company", "location", "profile_url", "state",
+	"search_query", "discovered_at", "reviewed_at", "requested_at",
+	"accepted_at", "cooled_down_at", "seniority", "function", "industry",
+	"company_size", "mutual_connections", "tags", "messages_sent",
+}
+
+// ExportCSV writes the profiles matching the filter as CSV with a header
+// row, oldest discovery first, one row per profile with its pipeline state
+// and timestamps (RFC 3339, empty if not reached). It returns the number of
+// profiles written.
+func (s *Storage) ExportCSV(w io.Writer, f ExportFilter) (int, error) {
+	s.mu.RLock()
+	profiles := make([]*Profile, 0)
+	for _, p := range s.data.Profiles {
+		if f.matches(p) {
+			profiles = append(profiles, p)
+		}
+	}
+	sent := make(map[string]int)
+	for _, msg := range s.data.Messages {
+		if !msg.Inbound {
+			sent[msg.ProfileID]++
+		}
+	}
+	s.mu.RUnlock()
+	return writeCSV(w, profiles, sent)
+}
+
+// writeCSV writes profiles as ExportCSV does, with sent counting the
+// messages sent to each
+func writeCSV(w io.Writer, profiles []*Profile, sent map[string]int) (int, error) {
+	sort.Slice(profiles, func(i, j int) bool {
+		if !profiles[i].DiscoveredAt.Equal(profiles[j].DiscoveredAt) {
+			return profiles[i].DiscoveredAt.Before(profiles[j].DiscoveredAt)
+		}
+		return profiles[i].ID < profiles[j].ID
+	})
+
+	out := csv.NewWriter(w)
+	if err := out.Write(exportColumns); err != nil {
+		return 0, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	for _, p := range profiles {
+		mutual := ""
+		if p.Shared != nil {
+			mutual = strconv.Itoa(p.Shared.MutualConnections)
+		}
+		row := []string{
+			p.ID, p.Name, p.Title, p.Company, p.Location, p.ProfileURL, string(p.State),
+			p.SearchQuery, exportTime(&p.DiscoveredAt), exportTime(p.ReviewedAt), exportTime(p.RequestedAt),
+			exportTime(p.AcceptedAt), exportTime(p.CooledDownAt), p.Seniority, p.Function, p.Industry,
+			p.CompanySize, mutual, strings.Join(p.Tags, ","), strconv.Itoa(sent[p.ID]),
+		}
+		for i := range row {
+			row[i] = spreadsheetSafe(row[i])
+		}
+		if err := out.Write(row); err != nil {
+			return 0, fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return 0, fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return len(profiles), nil
+}
+
+// exportTime formats an optional timestamp for export, "" if unset
+func exportTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// spreadsheetSafe keeps scraped text from being run as a formula when the
+// CSV is opened in a spreadsheet, by quoting cells that start like one
+func spreadsheetSafe(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"encoding/csv"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"subspace/internal/config"
+)
+
+func TestExportCSV(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	requested := day.Add(time.Hour)
+	for _, p := range []*Profile{
+		{ID: "p2", Name: "=HYPERLINK(\"x\")", ProfileURL: "https://www.linkedin.com/in/p2", State: StateRequested,
+			DiscoveredAt: day.Add(-time.Hour), RequestedAt: &requested, SearchQuery: "golang", Tags: []string{"q3-outreach"}},
+		{ID: "p1", Name: "Grace Hopper", Title: "Admiral, retired", ProfileURL: "https://www.linkedin.com/in/p1", State: StateDiscovered,
+			DiscoveredAt: day},
+	} {
+		if err := db.SaveProfile(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.SaveMessage(&Message{ID: "m1", ProfileID: "p2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	n, err := db.ExportCSV(&out, ExportFilter{})
+	if err != nil || n != 2 {
+		t.Fatalf("ExportCSV = %d, %v; want 2 profiles", n, err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[0][0] != "id" || rows[1][0] != "p2" || rows[2][0] != "p1" {
+		t.Fatalf("rows = %v, want header, p2, p1", rows)
+	}
+	col := func(name string) int {
+		for i, c := range rows[0] {
+			if c == name {
+				return i
+			}
+		}
+		t.Fatalf("no column %s", name)
+		return -1
+	}
+	if got := rows[1][col("name")]; !strings.HasPrefix(got, "'=") {
+		t.Errorf("formula not neutralized: %q", got)
+	}
+	if rows[1][col("requested_at")] != "2024-05-01T10:00:00Z" || rows[2][col("requested_at")] != "" {
+		t.Errorf("requested_at = %q, %q", rows[1][col("requested_at")], rows[2][col("requested_at")])
+	}
+	if rows[1][col("messages_sent")] != "1" || rows[1][col("tags")] != "q3-outreach" || rows[2][col("title")] != "Admiral, retired" {
+		t.Errorf("row p2 = %v, p1 = %v", rows[1], rows[2])
+	}
+
+	out.Reset()
+	if n, err := db.ExportCSV(&out, ExportFilter{States: []ProfileState{StateRequested}, Tag: "Q3-Outreach"}); err != nil || n != 1 {
+		t.Errorf("filtered ExportCSV = %d, %v; want 1 profile", n, err)
+	}
+}
@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"subspace/internal/clock"
+	"subspace/internal/ids"
+)
+
+/*
+PROFILE IMPORT
+
+An existing target list can stand in for search: ImportCSV seeds the rows
+of a CSV as discovered profiles, which connect then works through like any
+search result. The first row names the columns, in any order and case:
+
+	name (or first_name and last_name), title (or headline), company,
+	location, profile_url (or url, linkedin_url), tags
+
+Only the profile URL is required; other columns are ignored, so the CSV
+written by ExportCSV imports as is. A row whose person is already stored,
+by profile key, alias or fuzzy name match, or repeats an earlier row, is
+counted as a duplicate and left alone.
+*/
+
+// ImportQuery is the search query recorded on imported profiles unless
+// the import sets its own
+const ImportQuery = "import"
+
+// ImportOptions configures ImportCSV
+type ImportOptions struct {
+	Tags   []string // Added to every imported profile, already normalized
+	Query  string   // Recorded as the search query, ImportQuery if empty
+	DryRun bool     // Count what would be imported without saving
+}
+
+// ImportResult counts the rows of an import
+type ImportResult struct {
+	Imported   int
+	Duplicates int
+	Invalid    []string // "line N: reason" for each row skipped
+}
+
+// importAliases maps accepted column names to the field they fill
+var importAliases = map[string]string{
+	"name":         "name",
+	"full_name":    "name",
+	"first_name":   "first_name",
+	"last_name":    "last_name",
+	"title":        "title",
+	"headline":     "title",
+	"company":      "company",
+	"location":     "location",
+	"profile_url":  "profile_url",
+	"url":          "profile_url",
+	"linkedin_url": "profile_url",
+	"tags":         "tags",
+}
+
+// ImportCSV reads profiles from a CSV and saves the new ones as
+// StateDiscovered in a single transaction. Rows without a usable profile
+// URL are reported in the result rather than failing the import; a
+// malformed file or a missing profile_url column is an error.
+func ImportCSV(db Backend, r io.Reader, opts ImportOptions) (ImportResult, error) {
+	var result ImportResult
+	in := csv.NewReader(r)
+	in.FieldsPerRecord = -1
+	in.TrimLeadingSpace = true
+
+	header, err := in.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return result, fmt.Errorf("empty CSV")
+		}
+		return result, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		name = strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+		if field, ok := importAliases[name]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+	if _, ok := columns["profile_url"]; !ok {
+		return result, fmt.Errorf("CSV has no profile_url column")
+	}
+
+	query := opts.Query
+	if query == "" {
+		query = ImportQuery
+	}
+	now := clock.Now()
+	seen := make(map[string]bool)
+	var profiles []*Profile
+	for {
+		row, err := in.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		line, _ := in.FieldPos(0)
+		cell := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(row) {
+				return unquoteCell(strings.TrimSpace(row[i]))
+			}
+			return ""
+		}
+
+		p := &Profile{
+			Name:         cell("name"),
+			Title:        cell("title"),
+			Company:      cell("company"),
+			Location:     cell("location"),
+			ProfileURL:   cell("profile_url"),
+			State:        StateDiscovered,
+			DiscoveredAt: now,
+			SearchQuery:  query,
+		}
+		if p.Name == "" {
+			p.Name = strings.TrimSpace(cell("first_name") + " " + cell("last_name"))
+		}
+		if p.ProfileURL == "" && p.Name == "" {
+			continue // Blank row
+		}
+		key := ProfileKey(p.ProfileURL)
+		if !strings.HasPrefix(key, "in/") {
+			result.Invalid = append(result.Invalid, fmt.Sprintf("line %d: not a profile URL: %q", line, p.ProfileURL))
+			continue
+		}
+		p.ID = "import-" + ids.New()
+
+		tags, err := importTags(cell("tags"))
+		if err != nil {
+			result.Invalid = append(result.Invalid, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+		if tags = union(tags, opts.Tags); len(tags) > 0 {
+			sort.Strings(tags)
+			p.Tags = tags
+		}
+
+		if seen[key] || db.FindDuplicate(p) != nil {
+			result.Duplicates++
+			continue
+		}
+		seen[key] = true
+		profiles = append(profiles, p)
+	}
+
+	result.Imported = len(profiles)
+	if opts.DryRun || len(profiles) == 0 {
+		return result, nil
+	}
+	err = db.Transaction(func(tx *Tx) error {
+		for _, p := range profiles {
+			tx.SaveProfile(p)
+		}
+		return nil
+	})
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("failed to save imported profiles: %w", err)
+	}
+	return result, nil
+}
+
+// importTags parses a comma-separated tags cell
+func importTags(cell string) ([]string, error) {
+	var tags []string
+	for _, raw := range strings.Split(cell, ",") {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		tag, err := NormalizeTag(raw)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// unquoteCell undoes spreadsheetSafe, so exported text imports unchanged
+func unquoteCell(cell string) string {
+	if len(cell) > 1 && cell[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(cell[1])) {
+		return cell[1:]
+	}
+	return cell
+}
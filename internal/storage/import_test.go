@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"subspace/internal/config"
+)
+
+func TestImportCSV(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveProfile(&Profile{ID: "p1", Name: "Grace Hopper", ProfileURL: "https://www.linkedin.com/in/grace/", State: StateRequested}); err != nil {
+		t.Fatal(err)
+	}
+
+	in := "\ufeffFirst Name,Last Name,Headline,Company,LinkedIn URL,Tags\n" +
+		"Ada,Lovelace,Mathematician,Analytical Engines,https://www.linkedin.com/in/Ada-L/?trk=x,vip\n" +
+		"Grace,Hopper,Admiral,Navy,https://de.linkedin.com/in/grace,\n" +
+		"Ada,Lovelace,,,https://www.linkedin.com/in/ada-l,\n" +
+		"Alan,Turing,,,https://example.com/alan,\n" +
+		"Bad,Tag,,,https://www.linkedin.com/in/bad,no spaces!\n" +
+		",,,,,\n"
+
+	dry, err := ImportCSV(db, strings.NewReader(in), ImportOptions{DryRun: true})
+	if err != nil || dry.Imported != 1 || len(db.GetAllProfiles()) != 1 {
+		t.Fatalf("dry run = %+v, %v; %d profiles stored", dry, err, len(db.GetAllProfiles()))
+	}
+
+	result, err := ImportCSV(db, strings.NewReader(in), ImportOptions{Tags: []string{"list-a"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Imported != 1 || result.Duplicates != 2 || len(result.Invalid) != 2 {
+		t.Fatalf("result = %+v", result)
+	}
+	if !strings.HasPrefix(result.Invalid[0], "line 5:") {
+		t.Errorf("invalid row reported as %q", result.Invalid[0])
+	}
+
+	p := db.FindDuplicate(&Profile{ProfileURL: "https://www.linkedin.com/in/ada-l"})
+	if p == nil || !strings.HasPrefix(p.ID, "import-") {
+		t.Fatalf("imported profile = %+v, want an import- ID", p)
+	}
+	if p.Name != "Ada Lovelace" || p.Title != "Mathematician" || p.State != StateDiscovered ||
+		p.SearchQuery != ImportQuery || p.ProfileURL != "https://www.linkedin.com/in/Ada-L" {
+		t.Errorf("imported profile = %+v", p)
+	}
+	if strings.Join(p.Tags, ",") != "list-a,vip" {
+		t.Errorf("tags = %v", p.Tags)
+	}
+
+	// The existing profile is left alone
+	if p, _ := db.GetProfile("p1"); p.State != StateRequested {
+		t.Errorf("existing profile changed: %+v", p)
+	}
+
+	if _, err := ImportCSV(db, strings.NewReader("name,title\nAda,Engineer\n"), ImportOptions{}); err == nil {
+		t.Error("CSV without profile_url column accepted")
+	}
+}
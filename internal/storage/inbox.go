@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"sort"
+	"time"
+)
+
+// Conversation is a profile whose latest messages are replies we have not
+// answered yet
+type Conversation struct {
+	Profile    *Profile   `json:"profile"`
+	Unanswered []*Message `json:"unanswered"` // Oldest first
+	Thread     []*Message `json:"-"`          // Every message, oldest first
+}
+
+// WaitingSince returns when the oldest unanswered reply arrived
+func (c Conversation) WaitingSince() time.Time {
+	return c.Unanswered[0].SentAt
+}
+
+// Inbox returns the conversations awaiting a reply, longest waiting first
+func (s *Storage) Inbox() []Conversation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	messages := make([]*Message, 0, len(s.data.Messages))
+	for _, msg := range s.data.Messages {
+		messages = append(messages, msg)
+	}
+	return buildInbox(messages, func(id string) (*Profile, bool) {
+		p, ok := s.data.Profiles[id]
+		return p, ok
+	})
+}
+
+// buildInbox threads the messages by profile and keeps those ending in
+// replies, longest waiting first
+func buildInbox(messages []*Message, profileOf func(id string) (*Profile, bool)) []Conversation {
+	threads := make(map[string][]*Message)
+	for _, msg := range messages {
+		threads[msg.ProfileID] = append(threads[msg.ProfileID], msg)
+	}
+
+	inbox := make([]Conversation, 0)
+	for profileID, thread := range threads {
+		sort.Slice(thread, func(i, j int) bool { return thread[i].SentAt.Before(thread[j].SentAt) })
+		first := len(thread)
+		for first > 0 && thread[first-1].Inbound {
+			first--
+		}
+		if first == len(thread) {
+			continue
+		}
+		profile, ok := profileOf(profileID)
+		if !ok {
+			continue
+		}
+		inbox = append(inbox, Conversation{Profile: profile, Unanswered: thread[first:], Thread: thread})
+	}
+	sort.Slice(inbox, func(i, j int) bool { return inbox[i].WaitingSince().Before(inbox[j].WaitingSince()) })
+	return inbox
+}
+
+// HasReplied reports whether a profile has ever replied
+func (s *Storage) HasReplied(profileID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, msg := range s.data.Messages {
+		if msg.ProfileID == profileID && msg.Inbound {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"subspace/internal/config"
+)
+
+func TestInbox(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	for _, id := range []string{"ada", "alan", "grace"} {
+		if err := db.SaveProfile(&Profile{ID: id, Name: id, State: StateAccepted}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	messages := []*Message{
+		{ID: "1", ProfileID: "ada", SentAt: at},
+		{ID: "2", ProfileID: "ada", SentAt: at.Add(3 * time.Hour), Inbound: true},
+		{ID: "3", ProfileID: "ada", SentAt: at.Add(4 * time.Hour), Inbound: true},
+		{ID: "4", ProfileID: "alan", SentAt: at},
+		{ID: "5", ProfileID: "alan", SentAt: at.Add(time.Hour), Inbound: true},
+		{ID: "6", ProfileID: "grace", SentAt: at},
+		{ID: "7", ProfileID: "grace", SentAt: at.Add(time.Hour), Inbound: true},
+		{ID: "8", ProfileID: "grace", SentAt: at.Add(2 * time.Hour)}, // answered
+	}
+	for _, m := range messages {
+		if err := db.SaveMessage(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	inbox := db.Inbox()
+	if len(inbox) != 2 {
+		t.Fatalf("got %d conversations, want 2: %+v", len(inbox), inbox)
+	}
+	if inbox[0].Profile.ID != "alan" || len(inbox[0].Unanswered) != 1 {
+		t.Errorf("first conversation: got %s with %d unanswered, want alan with 1",
+			inbox[0].Profile.ID, len(inbox[0].Unanswered))
+	}
+	if inbox[1].Profile.ID != "ada" || len(inbox[1].Unanswered) != 2 || len(inbox[1].Thread) != 3 {
+		t.Errorf("second conversation: got %s with %d unanswered of %d, want ada with 2 of 3",
+			inbox[1].Profile.ID, len(inbox[1].Unanswered), len(inbox[1].Thread))
+	}
+	if !db.HasReplied("grace") || db.HasReplied("nobody") {
+		t.Error("HasReplied disagrees with stored replies")
+	}
+}
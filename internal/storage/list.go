@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Sort orders of ListProfiles
+const (
+	SortDiscovered = "discovered"
+	SortRequested  = "requested"
+)
+
+// ListOptions selects, orders and pages the profiles ListProfiles returns.
+// The zero value lists every profile, oldest discovered first.
+type ListOptions struct {
+	States  []ProfileState // In any of these states
+	Tag     string         // Carrying this tag
+	Company string         // Company containing this, case-insensitively
+	Title   string         // Title containing this, case-insensitively
+
+	Sort   string // SortDiscovered (the default) or SortRequested
+	Desc   bool   // Newest first; never-requested profiles stay last
+	Limit  int    // Page size, all if 0
+	Offset int    // Matching profiles skipped before the page
+}
+
+// ProfilePage is one page of a profile listing
+type ProfilePage struct {
+	Profiles []*Profile `json:"profiles"`
+	Total    int        `json:"total"` // Matching profiles across all pages
+}
+
+// check rejects options no backend can list by
+func (o ListOptions) check() error {
+	if o.Sort != "" && o.Sort != SortDiscovered && o.Sort != SortRequested {
+		return fmt.Errorf("unknown sort %q (want %s or %s)", o.Sort, SortDiscovered, SortRequested)
+	}
+	if o.Limit < 0 || o.Offset < 0 {
+		return fmt.Errorf("limit and offset cannot be negative")
+	}
+	return nil
+}
+
+// matches reports whether the profile passes the filters
+func (o ListOptions) matches(p *Profile) bool {
+	if len(o.States) > 0 && !slices.Contains(o.States, p.State) {
+		return false
+	}
+	if o.Tag != "" && !p.HasTag(o.Tag) {
+		return false
+	}
+	return containsFold(p.Company, o.Company) && containsFold(p.Title, o.Title)
+}
+
+// sortKey is the time the profile is listed by; nil sorts last
+func (o ListOptions) sortKey(p *Profile) *time.Time {
+	if o.Sort == SortRequested {
+		return p.RequestedAt
+	}
+	return &p.DiscoveredAt
+}
+
+// page sorts the matching profiles and cuts out the requested page
+func (o ListOptions) page(profiles []*Profile) ProfilePage {
+	sort.SliceStable(profiles, func(i, j int) bool {
+		a, b := o.sortKey(profiles[i]), o.sortKey(profiles[j])
+		switch {
+		case a == nil || b == nil:
+			if (a == nil) != (b == nil) {
+				return b == nil
+			}
+		case !a.Equal(*b):
+			return a.Before(*b) != o.Desc
+		}
+		return profiles[i].ID < profiles[j].ID
+	})
+
+	page := ProfilePage{Profiles: make([]*Profile, 0), Total: len(profiles)}
+	if o.Offset < len(profiles) {
+		profiles = profiles[o.Offset:]
+		if o.Limit > 0 && len(profiles) > o.Limit {
+			profiles = profiles[:o.Limit]
+		}
+		page.Profiles = append(page.Profiles, profiles...)
+	}
+	return page
+}
+
+// Clone returns a copy of the profile sharing no memory with it, safe to
+// read after the storage lock is released
+func (p *Profile) Clone() *Profile {
+	cp := *p
+	for _, t := range []**time.Time{&cp.ReviewedAt, &cp.RequestedAt, &cp.AcceptedAt, &cp.CooledDownAt, &cp.SnoozedUntil} {
+		if *t != nil {
+			at := **t
+			*t = &at
+		}
+	}
+	cp.Tags = slices.Clone(p.Tags)
+	if p.Shared != nil {
+		shared := *p.Shared
+		shared.Groups = slices.Clone(p.Shared.Groups)
+		shared.Schools = slices.Clone(p.Shared.Schools)
+		cp.Shared = &shared
+	}
+	return &cp
+}
+
+// containsFold reports whether s contains substr, ignoring case
+func containsFold(s, substr string) bool {
+	return substr == "" || strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// ListProfiles returns the page of profiles opts selects, as copies the
+// caller may read while the bot keeps updating the originals
+func (s *Storage) ListProfiles(opts ListOptions) (ProfilePage, error) {
+	if err := opts.check(); err != nil {
+		return ProfilePage{}, err
+	}
+
+	s.mu.RLock()
+	matched := make([]*Profile, 0)
+	for _, p := range s.data.Profiles {
+		if opts.matches(p) {
+			matched = append(matched, p.Clone())
+		}
+	}
+	s.mu.RUnlock()
+	return opts.page(matched), nil
+}
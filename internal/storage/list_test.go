@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"subspace/internal/config"
+)
+
+func TestListProfiles(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	at := func(d int) *time.Time { t := day.AddDate(0, 0, d); return &t }
+	for _, p := range []*Profile{
+		{ID: "a", Company: "Acme Corp", Title: "Engineer", State: StateDiscovered, DiscoveredAt: *at(0)},
+		{ID: "b", Company: "ACME", Title: "Senior Engineer", State: StateRequested, DiscoveredAt: *at(1), RequestedAt: at(5)},
+		{ID: "c", Company: "Globex", Title: "Engineering Manager", State: StateRequested, DiscoveredAt: *at(2), RequestedAt: at(3), Tags: []string{"vip"}},
+		{ID: "d", Company: "Acme", Title: "Designer", State: StateAccepted, DiscoveredAt: *at(3), RequestedAt: at(4)},
+	} {
+		if err := db.SaveProfile(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tc := range []struct {
+		opts  ListOptions
+		want  string
+		total int
+	}{
+		{ListOptions{}, "a b c d", 4},
+		{ListOptions{Desc: true, Limit: 2}, "d c", 4},
+		{ListOptions{Offset: 3, Limit: 2}, "d", 4},
+		{ListOptions{Offset: 9}, "", 4},
+		{ListOptions{Company: "acme", Title: "engineer"}, "a b", 2},
+		{ListOptions{States: []ProfileState{StateRequested, StateAccepted}, Sort: SortRequested}, "c d b", 3},
+		{ListOptions{Sort: SortRequested, Desc: true}, "b d c a", 4}, // Never requested stays last
+		{ListOptions{Tag: "VIP"}, "c", 1},
+	} {
+		page, err := db.ListProfiles(tc.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, p := range page.Profiles {
+			got = append(got, p.ID)
+		}
+		if strings.Join(got, " ") != tc.want || page.Total != tc.total {
+			t.Errorf("%+v: got %v of %d, want %q of %d", tc.opts, got, page.Total, tc.want, tc.total)
+		}
+	}
+
+	if _, err := db.ListProfiles(ListOptions{Sort: "name"}); err == nil {
+		t.Error("unknown sort accepted")
+	}
+}
+
+func TestListProfilesReturnsCopies(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Profile{ID: "a", State: StateApproved, Tags: []string{"q3"}, Shared: &SharedContext{Groups: []string{"Go"}}}
+	if err := db.SaveProfile(p); err != nil {
+		t.Fatal(err)
+	}
+	page, err := db.ListProfiles(ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listed := page.Profiles[0]
+	if listed == p {
+		t.Fatal("ListProfiles returned the stored profile")
+	}
+
+	if err := p.Transition(StateRequested, time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	p.Tags[0] = "vip"
+	p.Shared.Groups[0] = "Rust"
+	if listed.State != StateApproved || listed.RequestedAt != nil || listed.Tags[0] != "q3" || listed.Shared.Groups[0] != "Go" {
+		t.Errorf("listed copy changed with the original: %+v", listed)
+	}
+}
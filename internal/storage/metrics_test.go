@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"subspace/internal/config"
+)
+
+func TestStorageMetrics(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	saves, _, _, _ := saveLatency.Snapshot()
+	if err := db.SaveProfile(&Profile{ID: "p1", ProfileURL: "https://www.linkedin.com/in/p1"}); err != nil {
+		t.Fatal(err)
+	}
+	if n, _, _, _ := saveLatency.Snapshot(); n != saves+1 {
+		t.Errorf("save count = %d, want %d", n, saves+1)
+	}
+	if profileCount.Value() != 1 || fileSize.Value() == 0 {
+		t.Errorf("gauges: %v profiles, %v bytes", profileCount.Value(), fileSize.Value())
+	}
+}
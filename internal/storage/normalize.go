@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"subspace/internal/geo"
+	"subspace/internal/taxonomy"
+)
+
+/*
+PROFILE NORMALIZATION
+
+Every profile passes through NormalizeProfile before it is stored, so
+deduplication and templating see one spelling of the same data regardless of
+how the search results page rendered it:
+
+- Text fields:  NFC unicode, invisible characters removed, whitespace collapsed
+- Profile URL:  https, lowercase desktop host, no query/fragment/trailing slash
+- Company:      legal form suffixes spelled one way ("Acme, inc" -> "Acme Inc.")
+- Location:     resolved to a city/region/country place (see the geo module)
+- Title:        classified into seniority and function (see the taxonomy module)
+*/
+
+// NormalizeProfile cleans up a profile in place
+func NormalizeProfile(p *Profile) {
+	p.Name = NormalizeText(p.Name)
+	p.Title = NormalizeText(p.Title)
+	p.Company = NormalizeCompany(p.Company)
+	p.Location = NormalizeText(p.Location)
+	p.Place = geo.Normalize(p.Location)
+	role := taxonomy.Classify(p.Title)
+	p.Seniority, p.Function = role.Seniority, role.Function
+	p.ProfileURL = CanonicalURL(p.ProfileURL)
+}
+
+// invisible matches zero-width and formatting characters that sneak in via
+// copy/paste and HTML rendering
+var invisible = strings.NewReplacer(
+	"\u200b", "", // zero width space
+	"\u200c", "", // zero width non-joiner
+	"\u200d", "", // zero width joiner
+	"\u2060", "", // word joiner
+	"\ufeff", "", // byte order mark
+	"\u00ad", "", // soft hyphen
+)
+
+// NormalizeText returns s in NFC form with invisible characters removed and
+// all runs of whitespace (including non-breaking spaces) collapsed to a
+// single space
+func NormalizeText(s string) string {
+	s = norm.NFC.String(invisible.Replace(s))
+	return strings.Join(strings.FieldsFunc(s, unicode.IsSpace), " ")
+}
+
+// CanonicalURL returns the canonical form of a profile URL. Strings that do
+// not parse as absolute URLs are returned trimmed but otherwise unchanged.
+func CanonicalURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	u.Scheme = "https"
+	u.Host = strings.ToLower(u.Host)
+	if strings.HasPrefix(u.Host, "m.") {
+		u.Host = "www." + strings.TrimPrefix(u.Host, "m.") // Mobile site links
+	}
+	u.User = nil
+	u.RawQuery = "" // Tracking params (trk, ref, miniProfileUrn...) never identify a profile
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+	return u.String()
+}
+
+// companyForms maps lowercased, dot-free legal form spellings to their
+// standard form
+var companyForms = map[string]string{
+	"inc":          "Inc.",
+	"incorporated": "Inc.",
+	"corp":         "Corp.",
+	"corporation":  "Corp.",
+	"co":           "Co.",
+	"ltd":          "Ltd.",
+	"limited":      "Ltd.",
+	"llc":          "LLC",
+	"llp":          "LLP",
+	"plc":          "PLC",
+	"gmbh":         "GmbH",
+	"ag":           "AG",
+	"kg":           "KG",
+	"sa":           "S.A.",
+	"sl":           "S.L.",
+	"bv":           "B.V.",
+	"nv":           "N.V.",
+	"srl":          "S.r.l.",
+	"spa":          "S.p.A.",
+	"oy":           "Oy",
+	"ab":           "AB",
+	"pty":          "Pty",
+}
+
+// companyFormSeparator matches the comma or whitespace before a legal form
+var companyFormSeparator = regexp.MustCompile(`[,\s]+$`)
+
+// NormalizeCompany normalizes text and spells trailing legal forms one way:
+// "Acme, inc" and "ACME Inc" both become "Acme Inc." / "ACME Inc." and
+// "Foo G.m.b.H." becomes "Foo GmbH". Only trailing tokens are touched so
+// names like "AB InBev" are left alone.
+func NormalizeCompany(s string) string {
+	s = NormalizeText(s)
+	words := strings.Fields(s)
+
+	end := len(words)
+	var forms []string
+	for end > 1 {
+		form, ok := companyForms[companyFormKey(words[end-1])]
+		if !ok {
+			break
+		}
+		forms = append([]string{form}, forms...)
+		end--
+	}
+	if len(forms) == 0 {
+		return s
+	}
+
+	base := companyFormSeparator.ReplaceAllString(strings.Join(words[:end], " "), "")
+	return base + " " + strings.Join(forms, " ")
+}
+
+// CompanyKey returns a comparison key for a company name with legal forms,
+// punctuation and case removed, so "Acme, Inc." and "ACME" compare equal
+func CompanyKey(s string) string {
+	words := strings.Fields(NormalizeCompany(s))
+	for len(words) > 1 {
+		if _, ok := companyForms[companyFormKey(words[len(words)-1])]; !ok {
+			break
+		}
+		words = words[:len(words)-1]
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, strings.Join(words, ""))
+}
+
+// companyFormKey strips dots, commas and parentheses and lowercases a token
+// for lookup in companyForms
+func companyFormKey(word string) string {
+	return strings.ToLower(strings.NewReplacer(".", "", ",", "", "(", "", ")", "").Replace(word))
+}
@@ -0,0 +1,67 @@
+package storage
+
+import "testing"
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"  Jane\u00a0 Doe \n", "Jane Doe"},
+		{"Jo\u200bhn\ufeff", "John"},
+		{"Rene\u0301", "Ren\u00e9"}, // NFC
+		{"soft\u00adware", "software"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeText(tt.in); got != tt.want {
+			t.Errorf("NormalizeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"http://WWW.LinkedIn.com/in/jane-doe/?trk=abc#top", "https://www.linkedin.com/in/jane-doe"},
+		{"https://m.linkedin.com/in/jane-doe", "https://www.linkedin.com/in/jane-doe"},
+		{" https://user@www.linkedin.com/in/jane-doe// ", "https://www.linkedin.com/in/jane-doe"},
+		{"not a url ", "not a url"},
+	}
+	for _, tt := range tests {
+		if got := CanonicalURL(tt.in); got != tt.want {
+			t.Errorf("CanonicalURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeCompany(t *testing.T) {
+	tests := []struct{ in, want, key string }{
+		{"Acme, inc", "Acme Inc.", "acme"},
+		{"ACME Inc", "ACME Inc.", "acme"},
+		{"Foo G.m.b.H.", "Foo GmbH", "foo"},
+		{"Widgets Co., Ltd.", "Widgets Co. Ltd.", "widgets"},
+		{"AB InBev", "AB InBev", "abinbev"}, // Only trailing forms
+		{"Inc", "Inc", "inc"},               // A lone form is the name
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeCompany(tt.in); got != tt.want {
+			t.Errorf("NormalizeCompany(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if got := CompanyKey(tt.in); got != tt.key {
+			t.Errorf("CompanyKey(%q) = %q, want %q", tt.in, got, tt.key)
+		}
+	}
+}
+
+func TestNormalizeProfileOnSave(t *testing.T) {
+	p := &Profile{
+		Name:       " Jane\u200b  Doe ",
+		Company:    "acme, inc",
+		Title:      "Senior Software Engineer",
+		ProfileURL: "http://www.linkedin.com/in/jane-doe/?trk=x",
+	}
+	NormalizeProfile(p)
+	if p.Name != "Jane Doe" || p.Company != "acme Inc." || p.ProfileURL != "https://www.linkedin.com/in/jane-doe" {
+		t.Errorf("normalized = %q, %q, %q", p.Name, p.Company, p.ProfileURL)
+	}
+	if p.Seniority == "" || p.Function == "" {
+		t.Errorf("title not classified: seniority %q, function %q", p.Seniority, p.Function)
+	}
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// noteTimeLayout prefixes every operator note in Profile.Notes
+const noteTimeLayout = "2006-01-02 15:04"
+
+// Note is one operator annotation on a profile
+type Note struct {
+	At   time.Time `json:"at"` // Zero for free-form notes written before timestamps
+	Text string    `json:"text"`
+}
+
+// AddNote appends a timestamped note. Notes are append-only: one per line
+// in Profile.Notes, so older db.json files and merged duplicates still
+// read back as a plain list.
+func (p *Profile) AddNote(text string, at time.Time) error {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return fmt.Errorf("note for %s is empty", p.ID)
+	}
+	line := at.Format(noteTimeLayout) + " " + text
+	if p.Notes == "" {
+		p.Notes = line
+	} else {
+		p.Notes += "\n" + line
+	}
+	return nil
+}
+
+// NoteList returns the profile's notes, oldest first
+func (p *Profile) NoteList() []Note {
+	var notes []Note
+	for _, line := range strings.Split(p.Notes, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		n := Note{Text: line}
+		if len(line) > len(noteTimeLayout) {
+			if at, err := time.ParseInLocation(noteTimeLayout, line[:len(noteTimeLayout)], time.Local); err == nil {
+				n = Note{At: at, Text: strings.TrimSpace(line[len(noteTimeLayout):])}
+			}
+		}
+		notes = append(notes, n)
+	}
+	return notes
+}
+
+// LatestNote returns the text of the most recent note, or ""
+func (p *Profile) LatestNote() string {
+	notes := p.NoteList()
+	if len(notes) == 0 {
+		return ""
+	}
+	return notes[len(notes)-1].Text
+}
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotes(t *testing.T) {
+	p := &Profile{ID: "p1", Notes: "legacy free-form note"}
+	first := time.Date(2024, 5, 1, 9, 30, 0, 0, time.Local)
+
+	if err := p.AddNote("  met at\nGopherCon  ", first); err != nil {
+		t.Fatalf("AddNote: %v", err)
+	}
+	if err := p.AddNote("asked about Go jobs", first.Add(time.Hour)); err != nil {
+		t.Fatalf("AddNote: %v", err)
+	}
+	if err := p.AddNote(" \n ", first); err == nil {
+		t.Error("AddNote accepted an empty note")
+	}
+
+	notes := p.NoteList()
+	want := []Note{
+		{Text: "legacy free-form note"},
+		{At: first, Text: "met at GopherCon"},
+		{At: first.Add(time.Hour), Text: "asked about Go jobs"},
+	}
+	if len(notes) != len(want) {
+		t.Fatalf("got %d notes, want %d: %+v", len(notes), len(want), notes)
+	}
+	for i := range want {
+		if !notes[i].At.Equal(want[i].At) || notes[i].Text != want[i].Text {
+			t.Errorf("note %d: got %+v, want %+v", i, notes[i], want[i])
+		}
+	}
+	if got := p.LatestNote(); got != "asked about Go jobs" {
+		t.Errorf("LatestNote() = %q", got)
+	}
+}
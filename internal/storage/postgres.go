@@ -0,0 +1,997 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/logger"
+)
+
+/*
+POSTGRES BACKEND
+
+Keeps profiles, messages, the action log and the company cache in a
+PostgreSQL database, so several machines and accounts share one pipeline:
+a profile found by one is not searched, requested or messaged by another,
+and the limits count every account's actions where they share a budget.
+
+Profiles, messages and companies are stored as JSON documents next to the
+columns the queries filter on, so adding a field to a record doesn't need
+a migration. The schema is migrated when the backend opens; an advisory
+lock keeps two machines starting at once from migrating together.
+
+The binary talks to the database through database/sql with the configured
+driver (storage.postgres.driver); cmd/app registers "pgx" from
+github.com/jackc/pgx/v5/stdlib.
+
+The getters of Backend can't return errors, so reads fail closed: after a
+query fails, every action count reports all limits reached for a minute,
+ProfileExists and HasReplied report true and lists come back empty. A run
+that loses the database stops acting instead of acting on missing data.
+
+Before a connection request is sent its profile is claimed (Claim) for
+claimTTL. Machines working the same candidates skip the profiles others
+hold, so no profile is requested twice.
+*/
+
+// migrations build the schema, one version per entry. Applied versions are
+// recorded in schema_migrations; append new versions, never edit old ones.
+var migrations = []string{
+	`CREATE TABLE profiles (
+		id          TEXT PRIMARY KEY,
+		profile_key TEXT NOT NULL,
+		state       TEXT NOT NULL,
+		data        JSONB NOT NULL
+	);
+	CREATE INDEX profiles_state ON profiles (state);
+	CREATE INDEX profiles_key ON profiles (profile_key);
+
+	CREATE TABLE messages (
+		id         TEXT PRIMARY KEY,
+		profile_id TEXT NOT NULL,
+		sent_at    TIMESTAMPTZ NOT NULL,
+		inbound    BOOLEAN NOT NULL,
+		data       JSONB NOT NULL
+	);
+	CREATE INDEX messages_profile ON messages (profile_id);
+	CREATE INDEX messages_sent_at ON messages (sent_at);
+
+	CREATE TABLE action_logs (
+		id         BIGSERIAL PRIMARY KEY,
+		action     TEXT NOT NULL,
+		at         TIMESTAMPTZ NOT NULL,
+		profile_id TEXT NOT NULL DEFAULT '',
+		success    BOOLEAN NOT NULL,
+		error      TEXT NOT NULL DEFAULT '',
+		source     TEXT NOT NULL DEFAULT '',
+		account    TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX action_logs_action_at ON action_logs (action, at);
+	CREATE INDEX action_logs_profile_at ON action_logs (profile_id, at);
+
+	CREATE TABLE companies (
+		key  TEXT PRIMARY KEY,
+		data JSONB NOT NULL
+	);`,
+
+	// Full-text indexes for SearchArchive
+	`ALTER TABLE messages ADD COLUMN search TSVECTOR
+		GENERATED ALWAYS AS (to_tsvector('simple', COALESCE(data->>'content', ''))) STORED;
+	CREATE INDEX messages_search ON messages USING GIN (search);
+
+	ALTER TABLE profiles ADD COLUMN notes_search TSVECTOR
+		GENERATED ALWAYS AS (to_tsvector('simple', COALESCE(data->>'notes', ''))) STORED;
+	CREATE INDEX profiles_notes_search ON profiles USING GIN (notes_search);`,
+
+	// Claims on profiles being acted on
+	`ALTER TABLE profiles ADD COLUMN claimed_by TEXT NOT NULL DEFAULT '';
+	ALTER TABLE profiles ADD COLUMN claimed_until TIMESTAMPTZ;`,
+}
+
+// migrationLock is the advisory lock held while migrating
+const migrationLock = 0x5b5ace
+
+// failClosedCount is the action count reported while reads fail, past any
+// configured limit
+const failClosedCount = math.MaxInt32
+
+// readOutage is how long counts stay failed closed after a read failed
+const readOutage = time.Minute
+
+// claimTTL is how long a claim keeps other machines off a profile, longer
+// than acting on one takes
+const claimTTL = 30 * time.Minute
+
+// Postgres is the PostgreSQL backend
+type Postgres struct {
+	db         *sql.DB
+	fuzzyDedup bool
+	mu         sync.RWMutex
+	account    string    // Stamped on new action log entries
+	owner      string    // Host and process claims are made by
+	readFailed time.Time // Last failed read
+	log        *logger.ContextLogger
+}
+
+var _ Backend = (*Postgres)(nil)
+
+// OpenPostgres connects to the configured database, sizes the connection
+// pool and migrates the schema
+func OpenPostgres(cfg config.StorageConfig) (*Postgres, error) {
+	pg := cfg.Postgres
+	db, err := sql.Open(pg.Driver, pg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres: %w", err)
+	}
+	db.SetMaxOpenConns(pg.MaxOpenConns)
+	db.SetMaxIdleConns(pg.MaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(pg.ConnMaxLifetimeMinutes) * time.Minute)
+
+	host, _ := os.Hostname()
+	p := &Postgres{db: db, fuzzyDedup: cfg.FuzzyDedup, owner: fmt.Sprintf("%s/%d", host, os.Getpid()),
+		log: logger.NewContext("storage", "backend", "postgres")}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	if err := p.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// migrate applies the migrations the database doesn't have yet
+func (p *Postgres) migrate() error {
+	ctx := context.Background()
+	conn, err := p.db.Conn(ctx) // The advisory lock belongs to one connection
+	if err != nil {
+		return fmt.Errorf("failed to migrate: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLock); err != nil {
+		return fmt.Errorf("failed to lock for migration: %w", err)
+	}
+	defer conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLock)
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INT PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("failed to migrate: %w", err)
+	}
+	var version int
+	if err := conn.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	for v := version + 1; v <= len(migrations); v++ {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("migration %d: %w", v, err)
+		}
+		if _, err := tx.Exec(migrations[v-1]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", v, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version, applied_at) VALUES ($1, $2)`, v, clock.Now()); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", v, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("migration %d: %w", v, err)
+		}
+		p.log.Info("Migrated schema", "version", v)
+	}
+	return nil
+}
+
+// Close closes the connection pool
+func (p *Postgres) Close() error {
+	return p.db.Close()
+}
+
+// SetAccount stamps the actions logged from now on with the account
+// running, so stats can be broken down by account
+func (p *Postgres) SetAccount(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.account = name
+}
+
+// currentAccount returns the account stamped on new action log entries
+func (p *Postgres) currentAccount() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.account
+}
+
+// readError logs a failed read and fails counts closed for readOutage
+func (p *Postgres) readError(what string, err error) {
+	p.log.Error("Failed to read "+what, "error", err)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.readFailed = clock.Now()
+}
+
+// failingReads reports whether a read failed within readOutage
+func (p *Postgres) failingReads() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return !p.readFailed.IsZero() && clock.Now().Sub(p.readFailed) < readOutage
+}
+
+// execer is a connection or transaction to write through
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// SaveProfile normalizes and saves or updates a profile
+func (p *Postgres) SaveProfile(profile *Profile) error {
+	NormalizeProfile(profile)
+	return upsertProfile(p.db, profile)
+}
+
+// upsertProfile writes a normalized profile
+func upsertProfile(db execer, profile *Profile) error {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return fmt.Errorf("failed to encode profile: %w", err)
+	}
+	_, err = db.Exec(`INSERT INTO profiles (id, profile_key, state, data) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE SET profile_key = EXCLUDED.profile_key, state = EXCLUDED.state, data = EXCLUDED.data`,
+		profile.ID, ProfileKey(profile.ProfileURL), string(profile.State), data)
+	if err != nil {
+		return fmt.Errorf("failed to save profile: %w", err)
+	}
+	return nil
+}
+
+// GetProfile retrieves a profile by ID
+func (p *Postgres) GetProfile(id string) (*Profile, error) {
+	profiles, err := p.queryProfiles(`SELECT data FROM profiles WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("profile not found: %s", id)
+	}
+	return profiles[0], nil
+}
+
+// GetProfilesByState retrieves all profiles in a given state
+func (p *Postgres) GetProfilesByState(state ProfileState) []*Profile {
+	return p.profiles(`SELECT data FROM profiles WHERE state = $1`, string(state))
+}
+
+// ListProfiles returns the page of profiles opts selects, filtered, sorted
+// and cut in the database
+func (p *Postgres) ListProfiles(opts ListOptions) (ProfilePage, error) {
+	if err := opts.check(); err != nil {
+		return ProfilePage{}, err
+	}
+
+	var where []string
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if len(opts.States) > 0 {
+		in := make([]string, len(opts.States))
+		for i, state := range opts.States {
+			in[i] = arg(string(state))
+		}
+		where = append(where, "state IN ("+strings.Join(in, ", ")+")")
+	}
+	if opts.Tag != "" {
+		where = append(where, "data->'tags' ? "+arg(strings.ToLower(strings.TrimSpace(opts.Tag))))
+	}
+	like := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	if opts.Company != "" {
+		where = append(where, "data->>'company' ILIKE "+arg("%"+like.Replace(opts.Company)+"%"))
+	}
+	if opts.Title != "" {
+		where = append(where, "data->>'title' ILIKE "+arg("%"+like.Replace(opts.Title)+"%"))
+	}
+	filter := ""
+	if len(where) > 0 {
+		filter = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	page := ProfilePage{}
+	if err := p.db.QueryRow(`SELECT COUNT(*) FROM profiles`+filter, args...).Scan(&page.Total); err != nil {
+		return ProfilePage{}, fmt.Errorf("failed to count profiles: %w", err)
+	}
+
+	column, dir := "discovered_at", "ASC"
+	if opts.Sort == SortRequested {
+		column = "requested_at"
+	}
+	if opts.Desc {
+		dir = "DESC"
+	}
+	query := fmt.Sprintf(`SELECT data FROM profiles%s ORDER BY (data->>'%s')::timestamptz %s NULLS LAST, id OFFSET %s`,
+		filter, column, dir, arg(opts.Offset))
+	if opts.Limit > 0 {
+		query += " LIMIT " + arg(opts.Limit)
+	}
+	profiles, err := p.queryProfiles(query, args...)
+	if err != nil {
+		return ProfilePage{}, fmt.Errorf("failed to list profiles: %w", err)
+	}
+	page.Profiles = profiles
+	return page, nil
+}
+
+// ConnectCandidates returns the profiles a connection request may be sent
+// to: approved ones first, then, unless approval is required, those still
+// awaiting review, each oldest discovered first. Snoozed profiles, and
+// those another machine has claimed, are left out.
+func (p *Postgres) ConnectCandidates(requireApproval bool) []*Profile {
+	unclaimed := `SELECT data FROM profiles WHERE state = $1
+		AND (claimed_by = $2 OR claimed_until IS NULL OR claimed_until < $3)`
+	candidates := p.profiles(unclaimed, string(StateApproved), p.owner, clock.Now())
+	sortByDiscovery(candidates)
+	if !requireApproval {
+		discovered := p.profiles(unclaimed, string(StateDiscovered), p.owner, clock.Now())
+		sortByDiscovery(discovered)
+		candidates = append(candidates, discovered...)
+	}
+	now := clock.Now()
+	awake := candidates[:0]
+	for _, profile := range candidates {
+		if !profile.Snoozed(now) {
+			awake = append(awake, profile)
+		}
+	}
+	return awake
+}
+
+// Claim reserves a profile for this process for claimTTL, or returns
+// ErrClaimed if another machine holds it. The update takes the row lock,
+// so of two machines claiming at once only one succeeds.
+func (p *Postgres) Claim(profileID string) error {
+	now := clock.Now()
+	res, err := p.db.Exec(`UPDATE profiles SET claimed_by = $1, claimed_until = $2
+		WHERE id = $3 AND (claimed_by = $1 OR claimed_until IS NULL OR claimed_until < $4)`,
+		p.owner, now.Add(claimTTL), profileID, now)
+	if err != nil {
+		return fmt.Errorf("failed to claim profile: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to claim profile: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: %s", ErrClaimed, profileID)
+	}
+	return nil
+}
+
+// SnoozedProfiles returns the profiles snoozed at now, waking soonest first
+func (p *Postgres) SnoozedProfiles(now time.Time) []*Profile {
+	return p.profiles(`SELECT data FROM profiles WHERE (data->>'snoozed_until')::timestamptz > $1
+		ORDER BY (data->>'snoozed_until')::timestamptz, id`, now)
+}
+
+// AddTag tags a profile; adding a tag it already carries changes nothing
+func (p *Postgres) AddTag(profileID, tag string) error {
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return err
+	}
+	return p.updateProfile(profileID, func(profile *Profile) bool {
+		if profile.HasTag(tag) {
+			return false
+		}
+		profile.Tags = append(profile.Tags, tag)
+		sort.Strings(profile.Tags)
+		return true
+	})
+}
+
+// RemoveTag removes a tag from a profile; removing one it doesn't carry
+// changes nothing
+func (p *Postgres) RemoveTag(profileID, tag string) error {
+	return p.updateProfile(profileID, func(profile *Profile) bool {
+		if !profile.HasTag(tag) {
+			return false
+		}
+		profile.Tags = slices.DeleteFunc(profile.Tags, func(t string) bool { return t == strings.ToLower(strings.TrimSpace(tag)) })
+		if len(profile.Tags) == 0 {
+			profile.Tags = nil
+		}
+		return true
+	})
+}
+
+// updateProfile changes a profile with its row locked, so changes made by
+// other machines at the same time aren't lost. change reports whether it
+// changed anything.
+func (p *Postgres) updateProfile(profileID string, change func(*Profile) bool) error {
+	return p.inTx(func(tx *sql.Tx) error {
+		var data []byte
+		err := tx.QueryRow(`SELECT data FROM profiles WHERE id = $1 FOR UPDATE`, profileID).Scan(&data)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("profile not found: %s", profileID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read profile: %w", err)
+		}
+		profile := &Profile{}
+		if err := json.Unmarshal(data, profile); err != nil {
+			return fmt.Errorf("failed to decode profile: %w", err)
+		}
+		if !change(profile) {
+			return nil
+		}
+		return upsertProfile(tx, profile)
+	})
+}
+
+// GetAllProfiles retrieves every stored profile
+func (p *Postgres) GetAllProfiles() []*Profile {
+	return p.profiles(`SELECT data FROM profiles`)
+}
+
+// ProfileExists checks if a profile URL has been seen before, compared by
+// profile key
+func (p *Postgres) ProfileExists(profileURL string) bool {
+	var exists bool
+	err := p.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM profiles WHERE profile_key = $1)`, ProfileKey(profileURL)).Scan(&exists)
+	if err != nil {
+		p.readError("profile", err)
+		return true // Skip the profile rather than store it twice
+	}
+	return exists
+}
+
+// FindDuplicate returns a stored profile, other than p itself, that
+// describes the same person, or nil
+func (p *Postgres) FindDuplicate(profile *Profile) *Profile {
+	same := p.profiles(`SELECT data FROM profiles WHERE profile_key = $1 AND id <> $2 LIMIT 1`,
+		ProfileKey(profile.ProfileURL), profile.ID)
+	if len(same) > 0 {
+		return same[0]
+	}
+	if !p.fuzzyDedup {
+		return nil
+	}
+	for _, existing := range p.GetAllProfiles() {
+		if existing.ID != profile.ID && SameProfile(existing, profile, true) {
+			return existing
+		}
+	}
+	return nil
+}
+
+// profiles runs a profile query; a failure returns no profiles and fails
+// counts closed
+func (p *Postgres) profiles(query string, args ...any) []*Profile {
+	profiles, err := p.queryProfiles(query, args...)
+	if err != nil {
+		p.readError("profiles", err)
+		return make([]*Profile, 0)
+	}
+	return profiles
+}
+
+// queryProfiles decodes the profile documents a query returns
+func (p *Postgres) queryProfiles(query string, args ...any) ([]*Profile, error) {
+	profiles := make([]*Profile, 0)
+	err := p.queryDocs(query, args, func(data []byte) error {
+		profile := &Profile{}
+		if err := json.Unmarshal(data, profile); err != nil {
+			return err
+		}
+		profiles = append(profiles, profile)
+		return nil
+	})
+	return profiles, err
+}
+
+// SaveMessage saves a message record
+func (p *Postgres) SaveMessage(message *Message) error {
+	return upsertMessage(p.db, message)
+}
+
+// upsertMessage writes a message
+func upsertMessage(db execer, message *Message) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	_, err = db.Exec(`INSERT INTO messages (id, profile_id, sent_at, inbound, data) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET profile_id = EXCLUDED.profile_id, sent_at = EXCLUDED.sent_at,
+			inbound = EXCLUDED.inbound, data = EXCLUDED.data`,
+		message.ID, message.ProfileID, message.SentAt, message.Inbound, data)
+	if err != nil {
+		return fmt.Errorf("failed to save message: %w", err)
+	}
+	return nil
+}
+
+// GetMessagesByProfile retrieves all messages for a profile
+func (p *Postgres) GetMessagesByProfile(profileID string) []*Message {
+	return p.messages(`SELECT data FROM messages WHERE profile_id = $1`, profileID)
+}
+
+// GetMessagesSince retrieves all messages sent at or after the given time
+func (p *Postgres) GetMessagesSince(since time.Time) []*Message {
+	return p.messages(`SELECT data FROM messages WHERE sent_at >= $1`, since)
+}
+
+// HasReplied reports whether a profile has ever replied
+func (p *Postgres) HasReplied(profileID string) bool {
+	var replied bool
+	err := p.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM messages WHERE profile_id = $1 AND inbound)`, profileID).Scan(&replied)
+	if err != nil {
+		p.readError("replies", err)
+		return true // Hold follow-ups until it is known
+	}
+	return replied
+}
+
+// Inbox returns the conversations awaiting a reply, longest waiting first
+func (p *Postgres) Inbox() []Conversation {
+	// Only threads with a reply can end in one
+	messages := p.messages(`SELECT data FROM messages WHERE profile_id IN
+		(SELECT profile_id FROM messages WHERE inbound)`)
+	return buildInbox(messages, func(id string) (*Profile, bool) {
+		profile, err := p.GetProfile(id)
+		return profile, err == nil
+	})
+}
+
+// SearchArchive returns the sent and received messages and operator notes
+// matching the query, newest first. The full-text indexes find the
+// candidates, which are then matched like in the JSON file.
+func (p *Postgres) SearchArchive(q ArchiveQuery) ([]ArchiveHit, error) {
+	terms, err := q.terms()
+	if err != nil {
+		return nil, err
+	}
+	var words []string
+	for _, t := range terms {
+		for _, w := range t {
+			words = append(words, w+":*")
+		}
+	}
+	tsquery := strings.Join(words, " & ")
+
+	var messages []*Message
+	if q.Kind != ArchiveNote {
+		query := `SELECT data FROM messages WHERE search @@ to_tsquery('simple', $1) AND sent_at >= $2`
+		args := []any{tsquery, q.Since}
+		if q.ProfileID != "" {
+			query += ` AND profile_id = $3`
+			args = append(args, q.ProfileID)
+		}
+		if messages, err = p.queryMessages(query, args...); err != nil {
+			return nil, fmt.Errorf("failed to search messages: %w", err)
+		}
+	}
+	var profiles []*Profile
+	if q.Kind != ArchiveMessage {
+		if profiles, err = p.queryProfiles(`SELECT data FROM profiles WHERE notes_search @@ to_tsquery('simple', $1)`, tsquery); err != nil {
+			return nil, fmt.Errorf("failed to search notes: %w", err)
+		}
+	}
+	return searchArchive(q, terms, messages, profiles, func(id string) (*Profile, bool) {
+		profile, err := p.GetProfile(id)
+		return profile, err == nil
+	}), nil
+}
+
+// messages runs a message query; a failure returns no messages and fails
+// counts closed
+func (p *Postgres) messages(query string, args ...any) []*Message {
+	messages, err := p.queryMessages(query, args...)
+	if err != nil {
+		p.readError("messages", err)
+		return make([]*Message, 0)
+	}
+	return messages
+}
+
+// queryMessages decodes the message documents a query returns
+func (p *Postgres) queryMessages(query string, args ...any) ([]*Message, error) {
+	messages := make([]*Message, 0)
+	err := p.queryDocs(query, args, func(data []byte) error {
+		message := &Message{}
+		if err := json.Unmarshal(data, message); err != nil {
+			return err
+		}
+		messages = append(messages, message)
+		return nil
+	})
+	return messages, err
+}
+
+// queryDocs calls decode with the JSON document of every row a query
+// returns
+func (p *Postgres) queryDocs(query string, args []any, decode func([]byte) error) error {
+	rows, err := p.db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		if err := decode(data); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// LogAction records an automated action for rate limiting purposes
+func (p *Postgres) LogAction(action, profileID string, success bool, err error) error {
+	return p.LogActionFrom(SourceAuto, action, profileID, success, err)
+}
+
+// LogActionFrom records an action initiated by source
+func (p *Postgres) LogActionFrom(source, action, profileID string, success bool, err error) error {
+	log := newActionLog(source, action, profileID, success, err)
+	log.Account = p.currentAccount()
+	return insertActionLog(p.db, log)
+}
+
+// insertActionLog appends an entry to the action log
+func insertActionLog(db execer, log ActionLog) error {
+	_, err := db.Exec(`INSERT INTO action_logs (action, at, profile_id, success, error, source, account)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		log.Action, log.Timestamp, log.ProfileID, log.Success, log.Error, log.Source, log.Account)
+	if err != nil {
+		return fmt.Errorf("failed to log action: %w", err)
+	}
+	return nil
+}
+
+// GetActionLogs returns the action log, optionally filtered to one action
+// type ("" returns every entry)
+func (p *Postgres) GetActionLogs(action string) []ActionLog {
+	rows, err := p.db.Query(`SELECT action, at, profile_id, success, error, source, account FROM action_logs
+		WHERE $1 = '' OR action = $1 ORDER BY id`, action)
+	if err != nil {
+		p.readError("action log", err)
+		return nil
+	}
+	defer rows.Close()
+
+	logs := make([]ActionLog, 0)
+	for rows.Next() {
+		var l ActionLog
+		if err := rows.Scan(&l.Action, &l.Timestamp, &l.ProfileID, &l.Success, &l.Error, &l.Source, &l.Account); err != nil {
+			p.readError("action log", err)
+			return logs
+		}
+		logs = append(logs, l)
+	}
+	if err := rows.Err(); err != nil {
+		p.readError("action log", err)
+	}
+	return logs
+}
+
+// GetActionCountSince returns the count of successful actions since a given time
+func (p *Postgres) GetActionCountSince(action string, since time.Time) int {
+	return p.count(`SELECT COUNT(*) FROM action_logs WHERE action = $1 AND success AND at > $2`, action, since)
+}
+
+// GetProfileTouchesSince returns the count of successful connection
+// requests and messages to a profile since a given time
+func (p *Postgres) GetProfileTouchesSince(profileID string, since time.Time) int {
+	return p.count(`SELECT COUNT(*) FROM action_logs WHERE profile_id = $1 AND success AND at > $2
+		AND action IN ('connection', 'message')`, profileID, since)
+}
+
+// GetActionCountToday returns today's action count
+func (p *Postgres) GetActionCountToday(action string) int {
+	return p.GetActionCountSince(action, startOfToday())
+}
+
+// GetActionCountLastHour returns the last hour's action count
+func (p *Postgres) GetActionCountLastHour(action string) int {
+	return p.GetActionCountSince(action, clock.Now().Add(-1*time.Hour))
+}
+
+// count runs a COUNT query the limits are checked against. While reads
+// fail it returns failClosedCount, so every limit counts as reached.
+func (p *Postgres) count(query string, args ...any) int {
+	n, err := p.countRows(query, args...)
+	if err != nil {
+		p.readError("action counts", err)
+		return failClosedCount
+	}
+	if p.failingReads() {
+		return failClosedCount
+	}
+	return n
+}
+
+// countRows runs a COUNT query
+func (p *Postgres) countRows(query string, args ...any) (int, error) {
+	var n int
+	err := p.db.QueryRow(query, args...).Scan(&n)
+	return n, err
+}
+
+// statCount runs a COUNT query for stats, logging a failure as 0
+func (p *Postgres) statCount(query string, args ...any) int {
+	n, err := p.countRows(query, args...)
+	if err != nil {
+		p.log.Error("Failed to count", "error", err)
+	}
+	return n
+}
+
+// CachedCompany returns the cached metadata for a company, matched by
+// CompanyKey, or nil if it was never looked up
+func (p *Postgres) CachedCompany(name string) *CompanyInfo {
+	var data []byte
+	err := p.db.QueryRow(`SELECT data FROM companies WHERE key = $1`, CompanyKey(name)).Scan(&data)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			p.log.Error("Failed to read company", "error", err)
+		}
+		return nil
+	}
+	c := &CompanyInfo{}
+	if err := json.Unmarshal(data, c); err != nil {
+		p.log.Error("Failed to decode company", "error", err)
+		return nil
+	}
+	return c
+}
+
+// SaveCompanies caches company metadata and applies it to the given
+// profiles in one transaction
+func (p *Postgres) SaveCompanies(companies []*CompanyInfo, profiles []*Profile) error {
+	return p.inTx(func(tx *sql.Tx) error {
+		for _, c := range companies {
+			data, err := json.Marshal(c)
+			if err != nil {
+				return fmt.Errorf("failed to encode company: %w", err)
+			}
+			if _, err := tx.Exec(`INSERT INTO companies (key, data) VALUES ($1, $2)
+				ON CONFLICT (key) DO UPDATE SET data = EXCLUDED.data`, CompanyKey(c.Name), data); err != nil {
+				return fmt.Errorf("failed to save company: %w", err)
+			}
+		}
+		for _, profile := range profiles {
+			NormalizeProfile(profile)
+			if err := upsertProfile(tx, profile); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Transaction runs fn and commits everything it staged in one database
+// transaction. If fn returns an error nothing is applied.
+func (p *Postgres) Transaction(fn func(tx *Tx) error) error {
+	staged := &Tx{}
+	if err := fn(staged); err != nil {
+		return err
+	}
+	account := p.currentAccount()
+	err := p.inTx(func(tx *sql.Tx) error {
+		for _, profile := range staged.profiles {
+			if err := upsertProfile(tx, profile); err != nil {
+				return err
+			}
+		}
+		for _, message := range staged.messages {
+			if err := upsertMessage(tx, message); err != nil {
+				return err
+			}
+		}
+		for _, log := range staged.logs {
+			log.Account = account
+			if err := insertActionLog(tx, log); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+	return nil
+}
+
+// inTx runs fn in a database transaction, committing if it succeeds
+func (p *Postgres) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetStats returns a snapshot of the pipeline and of today's activity
+func (p *Postgres) GetStats() Stats {
+	successes := `SELECT COUNT(*) FROM action_logs WHERE action = $1 AND success AND at > $2`
+	stats := Stats{
+		TotalMessages:       p.statCount(`SELECT COUNT(*) FROM messages`),
+		ConnectionsToday:    p.statCount(successes, "connection", startOfToday()),
+		MessagesToday:       p.statCount(successes, "message", startOfToday()),
+		ConnectionsLastHour: p.statCount(successes, "connection", clock.Now().Add(-1*time.Hour)),
+		TodayBySource:       make(map[string]map[string]int),
+	}
+
+	rows, err := p.db.Query(`SELECT state, COUNT(*) FROM profiles GROUP BY state`)
+	if err != nil {
+		p.log.Error("Failed to count profiles", "error", err)
+		return stats
+	}
+	for rows.Next() {
+		var state ProfileState
+		var n int
+		if err := rows.Scan(&state, &n); err != nil {
+			p.log.Error("Failed to count profiles", "error", err)
+			break
+		}
+		stats.TotalProfiles += n
+		switch state {
+		case StateDiscovered:
+			stats.Discovered = n
+		case StateApproved:
+			stats.Approved = n
+		case StateSkipped:
+			stats.Skipped = n
+		case StateRequested:
+			stats.Requested = n
+		case StateAccepted:
+			stats.Accepted = n
+		case StateCooledDown:
+			stats.CooledDown = n
+		case StateRejected:
+			stats.Rejected = n
+		}
+	}
+	rows.Close()
+
+	rows, err = p.db.Query(`SELECT source, action, COUNT(*) FROM action_logs WHERE success AND at > $1
+		GROUP BY source, action`, startOfToday())
+	if err != nil {
+		p.log.Error("Failed to count actions", "error", err)
+		return stats
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var source, action string
+		var n int
+		if err := rows.Scan(&source, &action, &n); err != nil {
+			p.log.Error("Failed to count actions", "error", err)
+			break
+		}
+		source = ActionLog{Source: source}.ActionSource()
+		if stats.TodayBySource[source] == nil {
+			stats.TodayBySource[source] = make(map[string]int)
+		}
+		stats.TodayBySource[source][action] += n
+	}
+	return stats
+}
+
+// Activity aggregates the action log entries matching the filter, as the
+// file backend does
+func (p *Postgres) Activity(f StatsFilter) Activity {
+	profiles := make(map[string]*Profile)
+	for _, profile := range p.GetAllProfiles() {
+		profiles[profile.ID] = profile
+	}
+	return activity(p.GetActionLogs(""), profiles, f)
+}
+
+// ExportCSV writes the profiles matching the filter as CSV, as the file
+// backend does
+func (p *Postgres) ExportCSV(w io.Writer, f ExportFilter) (int, error) {
+	all, err := p.queryProfiles(`SELECT data FROM profiles`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read profiles: %w", err)
+	}
+	profiles := make([]*Profile, 0, len(all))
+	for _, profile := range all {
+		if f.matches(profile) {
+			profiles = append(profiles, profile)
+		}
+	}
+
+	rows, err := p.db.Query(`SELECT profile_id, COUNT(*) FROM messages WHERE NOT inbound GROUP BY profile_id`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count messages: %w", err)
+	}
+	defer rows.Close()
+	sent := make(map[string]int)
+	for rows.Next() {
+		var id string
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return 0, fmt.Errorf("failed to count messages: %w", err)
+		}
+		sent[id] = n
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to count messages: %w", err)
+	}
+	return writeCSV(w, profiles, sent)
+}
+
+// Purge deletes records older than their cutoff in one transaction, as the
+// file backend does: profiles last active before profilesBefore, with
+// their messages, messages sent before messagesBefore and action log
+// entries before logsBefore. A zero cutoff keeps that record type.
+func (p *Postgres) Purge(profilesBefore, messagesBefore, logsBefore time.Time) (PurgeResult, error) {
+	var result PurgeResult
+	err := p.inTx(func(tx *sql.Tx) error {
+		var err error
+		if !profilesBefore.IsZero() {
+			// GREATEST skips the timestamps not reached yet
+			result.Profiles, err = affected(tx.Exec(`DELETE FROM profiles WHERE GREATEST(
+				(data->>'discovered_at')::timestamptz, (data->>'requested_at')::timestamptz,
+				(data->>'accepted_at')::timestamptz, (data->>'cooled_down_at')::timestamptz) < $1`, profilesBefore))
+			if err != nil {
+				return err
+			}
+		}
+		if result.Profiles > 0 {
+			n, err := affected(tx.Exec(`DELETE FROM messages m
+				WHERE NOT EXISTS (SELECT 1 FROM profiles p WHERE p.id = m.profile_id)`))
+			if err != nil {
+				return err
+			}
+			result.Messages += n
+		}
+		if !messagesBefore.IsZero() {
+			n, err := affected(tx.Exec(`DELETE FROM messages WHERE sent_at < $1`, messagesBefore))
+			if err != nil {
+				return err
+			}
+			result.Messages += n
+		}
+		if !logsBefore.IsZero() {
+			if result.ActionLogs, err = affected(tx.Exec(`DELETE FROM action_logs WHERE at < $1`, logsBefore)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return PurgeResult{}, fmt.Errorf("failed to purge: %w", err)
+	}
+	return result, nil
+}
+
+// affected returns the number of rows a statement changed
+func affected(res sql.Result, err error) (int, error) {
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// openTestPostgres connects to the database in SUBSPACE_TEST_POSTGRES_URL,
+// skipping the test when it is unset. The database is wiped first, so point
+// it at one kept for tests.
+func openTestPostgres(t *testing.T) *Postgres {
+	t.Helper()
+	url := os.Getenv("SUBSPACE_TEST_POSTGRES_URL")
+	if url == "" {
+		t.Skip("SUBSPACE_TEST_POSTGRES_URL not set")
+	}
+	cfg := config.Defaults().Storage
+	cfg.Backend = "postgres"
+	cfg.Postgres.URL = url
+	cfg.Postgres.Driver = "pgx"
+
+	p, err := OpenPostgres(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.db.Exec(`DROP TABLE IF EXISTS profiles, messages, action_logs, companies, schema_migrations`); err != nil {
+		t.Fatal(err)
+	}
+	p.Close()
+
+	// Reopening migrates the empty database from scratch
+	if p, err = OpenPostgres(cfg); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func TestPostgresIntegration(t *testing.T) {
+	p := openTestPostgres(t)
+	p.SetAccount("work")
+	now := clock.Now().Truncate(time.Microsecond) // Postgres keeps microseconds
+
+	accepted := now.Add(-time.Hour)
+	profile := &Profile{ID: "p1", Name: "Grace Hopper", Company: "Navy", ProfileURL: "https://example.com/in/grace",
+		State: StateAccepted, DiscoveredAt: now.Add(-2 * time.Hour), AcceptedAt: &accepted, Notes: "met at the compiler talk"}
+	err := p.Transaction(func(tx *Tx) error {
+		tx.SaveProfile(profile)
+		tx.SaveMessage(&Message{ID: "m1", ProfileID: "p1", Content: "Thanks for connecting about compilers", SentAt: now})
+		tx.LogAction("message", "p1", true, nil)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := p.GetProfile("p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "Grace Hopper" || got.State != StateAccepted || !got.AcceptedAt.Equal(accepted) {
+		t.Errorf("profile read back as %+v", got)
+	}
+	if !p.ProfileExists("https://example.com/in/grace") {
+		t.Error("saved profile not found by URL")
+	}
+	if msgs := p.GetMessagesByProfile("p1"); len(msgs) != 1 || msgs[0].Content != "Thanks for connecting about compilers" {
+		t.Errorf("got messages %+v", msgs)
+	}
+	if n := p.GetActionCountSince("message", now.Add(-time.Minute)); n != 1 {
+		t.Errorf("counted %d messages, want 1", n)
+	}
+	if logs := p.GetActionLogs("message"); len(logs) != 1 || logs[0].Account != "work" {
+		t.Errorf("got action logs %+v, want one stamped with the account", logs)
+	}
+
+	// Full-text search over messages and notes
+	hits, err := p.SearchArchive(ArchiveQuery{Text: "compilers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 1 || hits[0].Kind != ArchiveMessage {
+		t.Errorf("got hits %+v, want the message", hits)
+	}
+	if hits, err = p.SearchArchive(ArchiveQuery{Text: "talk", Kind: ArchiveNote}); err != nil || len(hits) != 1 {
+		t.Errorf("got note hits %+v (%v), want one", hits, err)
+	}
+
+	// A profile claimed here is held against other machines
+	other := &Postgres{db: p.db, owner: "other-host/1", log: p.log}
+	if err := p.Claim("p1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Claim("p1"); !errors.Is(err, ErrClaimed) {
+		t.Errorf("second claim got %v, want ErrClaimed", err)
+	}
+
+	// A failed transaction leaves nothing behind
+	failed := errors.New("boom")
+	err = p.Transaction(func(tx *Tx) error {
+		tx.SaveProfile(&Profile{ID: "p2", ProfileURL: "https://example.com/in/p2", State: StateDiscovered, DiscoveredAt: now})
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("got %v, want the transaction's error", err)
+	}
+	if _, err := p.GetProfile("p2"); err == nil {
+		t.Error("profile from a failed transaction was saved")
+	}
+
+	// Purging the profile takes its messages and the old logs with it
+	result, err := p.Purge(now.Add(time.Minute), time.Time{}, now.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (PurgeResult{Profiles: 1, Messages: 1, ActionLogs: 1}) {
+		t.Errorf("purged %+v, want one of each", result)
+	}
+	if profiles := p.GetAllProfiles(); len(profiles) != 0 {
+		t.Errorf("%d profiles left after the purge", len(profiles))
+	}
+}
@@ -0,0 +1,293 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/logger"
+)
+
+func TestOpenPostgresWithoutDriver(t *testing.T) {
+	cfg := config.Defaults().Storage
+	cfg.Backend = "postgres"
+	cfg.Postgres.URL = "postgres://localhost/subspace"
+	cfg.Postgres.Driver = "not-registered"
+	if _, err := OpenPostgres(cfg); err == nil || !strings.Contains(err.Error(), "not-registered") {
+		t.Fatalf("got %v, want an unknown driver error", err)
+	}
+}
+
+// step answers the next query containing match
+type step struct {
+	match    string
+	cols     []string
+	rows     [][]driver.Value
+	affected int64
+	err      error
+}
+
+// script is the conversation a test expects with the database
+type script struct {
+	mu    sync.Mutex
+	steps []step
+}
+
+// next pops the first step matching the query
+func (s *script) next(query string) (step, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, st := range s.steps {
+		if strings.Contains(query, st.match) {
+			s.steps = append(s.steps[:i], s.steps[i+1:]...)
+			return st, st.err
+		}
+	}
+	return step{}, fmt.Errorf("unexpected query: %s", query)
+}
+
+var scripts sync.Map // Test name to *script
+
+func init() { sql.Register("scripted", scriptedDriver{}) }
+
+// scriptedDriver is a database/sql driver answering from a script, so the
+// backend's queries and error handling can be tested without a database
+type scriptedDriver struct{}
+
+func (scriptedDriver) Open(name string) (driver.Conn, error) {
+	s, ok := scripts.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("no script %q", name)
+	}
+	return &scriptedConn{s.(*script)}, nil
+}
+
+type scriptedConn struct{ s *script }
+
+func (c *scriptedConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *scriptedConn) Close() error                        { return nil }
+func (c *scriptedConn) Begin() (driver.Tx, error)           { return c, nil }
+func (c *scriptedConn) Commit() error                       { return nil }
+func (c *scriptedConn) Rollback() error                     { return nil }
+
+func (c *scriptedConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	st, err := c.s.next(query)
+	if err != nil {
+		return nil, err
+	}
+	return &scriptedRows{cols: st.cols, rows: st.rows}, nil
+}
+
+func (c *scriptedConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	st, err := c.s.next(query)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(st.affected), nil
+}
+
+type scriptedRows struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+func (r *scriptedRows) Columns() []string { return r.cols }
+func (r *scriptedRows) Close() error      { return nil }
+func (r *scriptedRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// newScripted returns a backend talking to a scripted database
+func newScripted(t *testing.T, steps ...step) (*Postgres, *script) {
+	s := &script{steps: steps}
+	scripts.Store(t.Name(), s)
+	t.Cleanup(func() { scripts.Delete(t.Name()) })
+	db, err := sql.Open("scripted", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return &Postgres{db: db, log: logger.NewContext("storage", "backend", "postgres")}, s
+}
+
+// countOf answers a COUNT query
+func countOf(match string, n int) step {
+	return step{match: match, cols: []string{"count"}, rows: [][]driver.Value{{int64(n)}}}
+}
+
+func TestPostgresCounts(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 3, 20, 9, 0, 0, 0, time.UTC))
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	down := errors.New("connection refused")
+	pg, s := newScripted(t,
+		countOf("action = $1", 3),
+		countOf("profile_id = $1", 2),
+		step{match: "action = $1", err: down},
+		countOf("action = $1", 3),
+		countOf("action = $1", 3),
+	)
+
+	if n := pg.GetActionCountSince("connection", fake.Now().Add(-time.Hour)); n != 3 {
+		t.Errorf("connections = %d, want 3", n)
+	}
+	if n := pg.GetProfileTouchesSince("p1", fake.Now().Add(-time.Hour)); n != 2 {
+		t.Errorf("touches = %d, want 2", n)
+	}
+
+	// A failed count reports every limit reached, and so does the next one
+	// while the outage lasts
+	if n := pg.GetActionCountSince("connection", fake.Now().Add(-time.Hour)); n != failClosedCount {
+		t.Errorf("count while the database is down = %d, want %d", n, failClosedCount)
+	}
+	if n := pg.GetActionCountSince("connection", fake.Now().Add(-time.Hour)); n != failClosedCount {
+		t.Errorf("count right after a failure = %d, want %d", n, failClosedCount)
+	}
+	fake.Advance(readOutage)
+	if n := pg.GetActionCountSince("connection", fake.Now().Add(-time.Hour)); n != 3 {
+		t.Errorf("count after the outage = %d, want 3", n)
+	}
+	if len(s.steps) != 0 {
+		t.Errorf("queries not made: %+v", s.steps)
+	}
+}
+
+func TestPostgresReadsFailClosed(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 3, 20, 9, 0, 0, 0, time.UTC))
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	down := errors.New("connection refused")
+	pg, _ := newScripted(t,
+		step{match: "FROM profiles WHERE profile_key", err: down},
+		step{match: "inbound)", err: down},
+		step{match: "FROM messages WHERE profile_id", err: down},
+		countOf("profile_id = $1", 0),
+	)
+
+	if !pg.ProfileExists("https://www.linkedin.com/in/ada") {
+		t.Error("ProfileExists = false while the database is down, want true")
+	}
+	if !pg.HasReplied("p1") {
+		t.Error("HasReplied = false while the database is down, want true")
+	}
+	if messages := pg.GetMessagesByProfile("p1"); messages == nil || len(messages) != 0 {
+		t.Errorf("messages = %v, want an empty list", messages)
+	}
+	// A failed read elsewhere holds the touch guardrail shut too
+	if n := pg.GetProfileTouchesSince("p1", fake.Now().Add(-time.Hour)); n != failClosedCount {
+		t.Errorf("touches after a failed read = %d, want %d", n, failClosedCount)
+	}
+}
+
+func TestPostgresClaims(t *testing.T) {
+	doc := func(id string, discovered time.Time) []driver.Value {
+		return []driver.Value{[]byte(fmt.Sprintf(`{"id":%q,"state":"approved","discovered_at":%q}`, id, discovered.Format(time.RFC3339)))}
+	}
+	now := time.Date(2024, 3, 20, 9, 0, 0, 0, time.UTC)
+	pg, s := newScripted(t,
+		step{match: "claimed_until < $3", cols: []string{"data"}, rows: [][]driver.Value{doc("b", now), doc("a", now.Add(-time.Hour))}},
+		step{match: "UPDATE profiles SET claimed_by", affected: 1},
+		step{match: "UPDATE profiles SET claimed_by", affected: 0},
+	)
+
+	// Candidates come from a query leaving out other machines' claims
+	candidates := pg.ConnectCandidates(true)
+	if len(candidates) != 2 || candidates[0].ID != "a" {
+		t.Errorf("candidates = %v, want a then b", candidates)
+	}
+	if err := pg.Claim("a"); err != nil {
+		t.Errorf("claiming a free profile: %v", err)
+	}
+	if err := pg.Claim("b"); !errors.Is(err, ErrClaimed) {
+		t.Errorf("claiming a held profile: got %v, want ErrClaimed", err)
+	}
+	if len(s.steps) != 0 {
+		t.Errorf("queries not made: %+v", s.steps)
+	}
+}
+
+func TestPostgresTags(t *testing.T) {
+	doc := []driver.Value{[]byte(`{"id":"p1","tags":["q3"]}`)}
+	pg, s := newScripted(t,
+		step{match: "FOR UPDATE", cols: []string{"data"}, rows: [][]driver.Value{doc}},
+		step{match: "INSERT INTO profiles", affected: 1},
+		step{match: "FOR UPDATE", cols: []string{"data"}, rows: [][]driver.Value{doc}},
+		step{match: "FOR UPDATE", cols: []string{"data"}},
+	)
+
+	if err := pg.AddTag("p1", "VIP"); err != nil {
+		t.Fatal(err)
+	}
+	// Already tagged: nothing is written
+	if err := pg.AddTag("p1", "q3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pg.RemoveTag("missing", "q3"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("untagging a missing profile: got %v", err)
+	}
+	if len(s.steps) != 0 {
+		t.Errorf("queries not made: %+v", s.steps)
+	}
+}
+
+func TestPostgresPurge(t *testing.T) {
+	now := time.Date(2024, 3, 20, 9, 0, 0, 0, time.UTC)
+	pg, s := newScripted(t,
+		step{match: "DELETE FROM profiles", affected: 2},
+		step{match: "NOT EXISTS", affected: 3},
+		step{match: "DELETE FROM messages WHERE sent_at", affected: 1},
+		step{match: "DELETE FROM action_logs", affected: 40},
+		step{match: "DELETE FROM messages WHERE sent_at", err: errors.New("connection refused")},
+	)
+
+	result, err := pg.Purge(now.AddDate(-1, 0, 0), now.AddDate(0, -6, 0), now.AddDate(0, 0, -90))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (PurgeResult{Profiles: 2, Messages: 4, ActionLogs: 40}) {
+		t.Errorf("purged %+v", result)
+	}
+	// Zero cutoffs keep their record type: only messages are purged here
+	if _, err := pg.Purge(time.Time{}, now, time.Time{}); err == nil {
+		t.Error("failed purge reported no error")
+	}
+	if len(s.steps) != 0 {
+		t.Errorf("queries not made: %+v", s.steps)
+	}
+}
+
+func TestPostgresExportCSV(t *testing.T) {
+	pg, _ := newScripted(t,
+		step{match: "SELECT data FROM profiles", cols: []string{"data"}, rows: [][]driver.Value{
+			{[]byte(`{"id":"p1","name":"Ada","state":"accepted","tags":["vip"]}`)},
+			{[]byte(`{"id":"p2","name":"Grace","state":"discovered"}`)},
+		}},
+		step{match: "GROUP BY profile_id", cols: []string{"profile_id", "count"}, rows: [][]driver.Value{{"p1", int64(2)}}},
+	)
+
+	var out strings.Builder
+	n, err := pg.ExportCSV(&out, ExportFilter{Tag: "vip"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if n != 1 || len(lines) != 2 || !strings.HasPrefix(lines[1], "p1,Ada,") || !strings.HasSuffix(lines[1], ",vip,2") {
+		t.Errorf("exported %d:\n%s", n, out.String())
+	}
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"strings"
+
+	"subspace/internal/geo"
+	"subspace/internal/rules"
+	"subspace/internal/taxonomy"
+)
+
+// RuleEnv returns the profile's fields for evaluating targeting rules.
+// company_size compares as its bucket ("51-200") or the bucket's smallest
+// employee count (51).
+func (p *Profile) RuleEnv() rules.Env {
+	place := p.Place
+	if place == nil {
+		place = geo.Normalize(p.Location)
+	}
+	if place == nil {
+		place = &geo.Place{}
+	}
+	role := taxonomy.Role{Seniority: p.Seniority, Function: p.Function}
+	if role.Seniority == "" {
+		role = taxonomy.Classify(p.Title)
+	}
+	size := rules.Text(p.CompanySize)
+	if floor := taxonomy.SizeFloor(p.CompanySize); floor > 0 {
+		size.Num, size.IsNum = float64(floor), true
+	}
+	var groups, schools []string
+	if p.Shared != nil {
+		groups, schools = p.Shared.Groups, p.Shared.Schools
+	}
+	mutual := rules.Text("")
+	if p.Shared != nil {
+		mutual = rules.Number(float64(p.Shared.MutualConnections))
+	}
+
+	return rules.Env{Fields: map[string]rules.Value{
+		"name":               rules.Text(p.Name),
+		"title":              rules.Text(p.Title),
+		"company":            rules.Text(p.Company),
+		"location":           rules.Text(p.Location),
+		"city":               rules.Text(place.City),
+		"region":             rules.Text(place.Region),
+		"country":            rules.Text(place.Country),
+		"seniority":          rules.Text(role.Seniority),
+		"function":           rules.Text(role.Function),
+		"industry":           rules.Text(p.Industry),
+		"company_size":       size,
+		"mutual_connections": mutual,
+		"shared_groups":      rules.Text(strings.Join(groups, ", ")),
+		"shared_schools":     rules.Text(strings.Join(schools, ", ")),
+		"state":              rules.Text(string(p.State)),
+		"search_query":       rules.Text(p.SearchQuery),
+		"notes":              rules.Text(p.Notes),
+	}, Tags: p.Tags}
+}
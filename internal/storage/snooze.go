@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Snooze holds off every action on the profile until the given time.
+// Reason records why, e.g. the reply that asked for it.
+func (p *Profile) Snooze(until time.Time, reason string) {
+	p.SnoozedUntil = &until
+	p.SnoozeReason = strings.Join(strings.Fields(reason), " ")
+}
+
+// Unsnooze lifts a snooze
+func (p *Profile) Unsnooze() {
+	p.SnoozedUntil = nil
+	p.SnoozeReason = ""
+}
+
+// Snoozed reports whether the profile is snoozed at now
+func (p *Profile) Snoozed(now time.Time) bool {
+	return p.SnoozedUntil != nil && now.Before(*p.SnoozedUntil)
+}
+
+// SortByPriority orders profiles by queue priority, highest first, keeping
+// the order of profiles with the same priority
+func SortByPriority(profiles []*Profile) {
+	sort.SliceStable(profiles, func(i, j int) bool { return profiles[i].Priority > profiles[j].Priority })
+}
+
+// sortByDiscovery orders profiles oldest discovered first
+func sortByDiscovery(profiles []*Profile) {
+	sort.Slice(profiles, func(i, j int) bool {
+		if !profiles[i].DiscoveredAt.Equal(profiles[j].DiscoveredAt) {
+			return profiles[i].DiscoveredAt.Before(profiles[j].DiscoveredAt)
+		}
+		return profiles[i].ID < profiles[j].ID
+	})
+}
+
+// SnoozedProfiles returns the profiles snoozed at now, waking soonest first
+func (s *Storage) SnoozedProfiles(now time.Time) []*Profile {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snoozed := make([]*Profile, 0)
+	for _, p := range s.data.Profiles {
+		if p.Snoozed(now) {
+			snoozed = append(snoozed, p)
+		}
+	}
+	sort.Slice(snoozed, func(i, j int) bool { return snoozed[i].SnoozedUntil.Before(*snoozed[j].SnoozedUntil) })
+	return snoozed
+}
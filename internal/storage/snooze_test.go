@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+)
+
+func TestSnoozeExcludesConnectCandidates(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC))
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	db, err := New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	awake := &Profile{ID: "awake", ProfileURL: "https://www.linkedin.com/in/awake", State: StateDiscovered}
+	snoozed := &Profile{ID: "snoozed", ProfileURL: "https://www.linkedin.com/in/snoozed", State: StateApproved}
+	snoozed.Snooze(fake.Now().Add(48*time.Hour), "contact me  later")
+	for _, p := range []*Profile{awake, snoozed} {
+		if err := db.SaveProfile(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := db.ConnectCandidates(false); len(got) != 1 || got[0].ID != "awake" {
+		t.Errorf("candidates while snoozed = %v, want only awake", profileIDs(got))
+	}
+	if got := db.SnoozedProfiles(fake.Now()); len(got) != 1 || got[0].SnoozeReason != "contact me later" {
+		t.Errorf("SnoozedProfiles = %+v", got)
+	}
+
+	fake.Advance(49 * time.Hour)
+	if got := db.ConnectCandidates(false); len(got) != 2 || got[0].ID != "snoozed" {
+		t.Errorf("candidates after snooze = %v, want snoozed (approved) then awake", profileIDs(got))
+	}
+}
+
+func profileIDs(profiles []*Profile) []string {
+	out := make([]string, len(profiles))
+	for i, p := range profiles {
+		out[i] = p.ID
+	}
+	return out
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// transitions lists the legal moves through the connection pipeline.
+// Discovered profiles are either requested directly or reviewed first;
+// a review decision can be reversed until the request is sent. Requested
+// profiles can go back to discovered when the request is withdrawn;
+// cooled down and rejected profiles are final.
+var transitions = map[ProfileState][]ProfileState{
+	StateDiscovered: {StateApproved, StateSkipped, StateRequested, StateRejected},
+	StateApproved:   {StateRequested, StateSkipped},
+	StateSkipped:    {StateApproved},
+	StateRequested:  {StateAccepted, StateRejected, StateDiscovered},
+	StateAccepted:   {StateCooledDown},
+}
+
+// CanTransition reports whether a profile may move from one state to another
+func CanTransition(from, to ProfileState) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition moves the profile to a new state at the given time and
+// stamps the matching timestamp. The profile is left untouched if the move
+// is illegal or would date a step before the one it follows.
+func (p *Profile) Transition(to ProfileState, at time.Time) error {
+	if !CanTransition(p.State, to) {
+		return fmt.Errorf("illegal state transition for %s: %s -> %s", p.ID, p.State, to)
+	}
+
+	var after *time.Time
+	switch to {
+	case StateApproved, StateSkipped, StateRequested:
+		after = &p.DiscoveredAt
+	case StateAccepted:
+		after = p.RequestedAt
+	case StateCooledDown:
+		after = p.AcceptedAt
+	}
+	if after != nil && at.Before(*after) {
+		return fmt.Errorf("state transition for %s to %s at %s precedes previous step at %s",
+			p.ID, to, at.Format(time.RFC3339), after.Format(time.RFC3339))
+	}
+
+	switch to {
+	case StateApproved, StateSkipped:
+		p.ReviewedAt = &at
+	case StateRequested:
+		p.RequestedAt = &at
+	case StateAccepted:
+		p.AcceptedAt = &at
+	case StateCooledDown:
+		p.CooledDownAt = &at
+	case StateDiscovered:
+		p.RequestedAt = nil // Withdrawn
+	}
+	p.State = to
+	return nil
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+)
+
+var allStates = []ProfileState{StateDiscovered, StateApproved, StateSkipped, StateRequested, StateAccepted, StateCooledDown, StateRejected}
+
+// checkTimestamps asserts that a profile's timestamps agree with its state
+// and never run backwards through the pipeline
+func checkTimestamps(t *testing.T, p *Profile) {
+	t.Helper()
+	ordered := func(a, b *time.Time) bool { return a == nil || b == nil || !b.Before(*a) }
+
+	switch p.State {
+	case StateDiscovered:
+		if p.RequestedAt != nil || p.AcceptedAt != nil || p.CooledDownAt != nil {
+			t.Fatalf("discovered profile has later timestamps: %+v", p)
+		}
+	case StateApproved, StateSkipped:
+		if p.ReviewedAt == nil || p.RequestedAt != nil {
+			t.Fatalf("reviewed profile timestamps inconsistent: %+v", p)
+		}
+	case StateRequested:
+		if p.RequestedAt == nil || p.AcceptedAt != nil {
+			t.Fatalf("requested profile timestamps inconsistent: %+v", p)
+		}
+	case StateAccepted:
+		if p.RequestedAt == nil || p.AcceptedAt == nil || p.CooledDownAt != nil {
+			t.Fatalf("accepted profile timestamps inconsistent: %+v", p)
+		}
+	case StateCooledDown:
+		if p.RequestedAt == nil || p.AcceptedAt == nil || p.CooledDownAt == nil {
+			t.Fatalf("cooled down profile timestamps inconsistent: %+v", p)
+		}
+	case StateRejected:
+		if p.AcceptedAt != nil {
+			t.Fatalf("rejected profile was accepted: %+v", p)
+		}
+	}
+	if !ordered(&p.DiscoveredAt, p.ReviewedAt) || !ordered(&p.DiscoveredAt, p.RequestedAt) || !ordered(p.RequestedAt, p.AcceptedAt) || !ordered(p.AcceptedAt, p.CooledDownAt) {
+		t.Fatalf("profile timestamps out of order: %+v", p)
+	}
+}
+
+// TestTransitionProperties applies random transitions at random times
+// from a fake clock and checks that only legal moves succeed, failed moves
+// change nothing and timestamps stay consistent with the state
+func TestTransitionProperties(t *testing.T) {
+	for seed := int64(1); seed <= 500; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		fake := clock.NewFake(time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC))
+		p := &Profile{ID: "p1", State: StateDiscovered, DiscoveredAt: fake.Now()}
+
+		for i := 0; i < 30; i++ {
+			// Mostly forward in time, occasionally a stale timestamp
+			fake.Advance(time.Duration(rng.Intn(72)) * time.Hour)
+			at := fake.Now()
+			if rng.Intn(8) == 0 {
+				at = at.Add(-time.Duration(rng.Intn(96)) * time.Hour)
+			}
+			to := allStates[rng.Intn(len(allStates))]
+
+			before := *p
+			err := p.Transition(to, at)
+			if !CanTransition(before.State, to) {
+				if err == nil {
+					t.Fatalf("seed %d: illegal transition %s -> %s succeeded", seed, before.State, to)
+				}
+			}
+			if err != nil {
+				if !reflect.DeepEqual(before, *p) {
+					t.Fatalf("seed %d: failed transition %s -> %s modified profile: %+v -> %+v", seed, before.State, to, before, *p)
+				}
+				continue
+			}
+			if p.State != to {
+				t.Fatalf("seed %d: transition to %s left state %s", seed, to, p.State)
+			}
+			checkTimestamps(t, p)
+		}
+	}
+}
+
+// TestFinalStates checks that nothing leaves the final states and every
+// other state can make progress
+func TestFinalStates(t *testing.T) {
+	for _, from := range allStates {
+		final := from == StateCooledDown || from == StateRejected
+		moves := 0
+		for _, to := range allStates {
+			if CanTransition(from, to) {
+				moves++
+			}
+		}
+		if final && moves != 0 {
+			t.Errorf("final state %s has %d outgoing transitions", from, moves)
+		}
+		if !final && moves == 0 {
+			t.Errorf("state %s is a dead end", from)
+		}
+		if CanTransition(from, from) {
+			t.Errorf("state %s transitions to itself", from)
+		}
+	}
+}
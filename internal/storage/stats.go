@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"time"
+)
+
+// Stats is a snapshot of the pipeline and of today's activity
+type Stats struct {
+	TotalProfiles int `json:"total_profiles"`
+	Discovered    int `json:"discovered"`
+	Approved      int `json:"approved"`
+	Skipped       int `json:"skipped"`
+	Requested     int `json:"requested"`
+	Accepted      int `json:"accepted"`
+	CooledDown    int `json:"cooled_down"`
+	Rejected      int `json:"rejected"`
+
+	TotalMessages       int `json:"total_messages"`
+	ConnectionsToday    int `json:"connections_today"`
+	MessagesToday       int `json:"messages_today"`
+	ConnectionsLastHour int `json:"connections_last_hour"`
+
+	// Today's successful actions by source, then action
+	TodayBySource map[string]map[string]int `json:"today_by_source"`
+}
+
+// StatsFilter narrows Activity to part of the action log. Zero values
+// don't filter.
+type StatsFilter struct {
+	From     time.Time // Inclusive
+	To       time.Time // Exclusive
+	Account  string
+	Campaign string
+
+	// CampaignOf names the campaign of a profile; nil puts every profile
+	// in none. Actions without a profile, like searches, have no campaign.
+	CampaignOf func(*Profile) string
+}
+
+// Counts tallies the successful actions of part of the action log
+type Counts struct {
+	Connections int `json:"connections"`
+	Accepted    int `json:"accepted"` // Of the connection requests counted
+	Messages    int `json:"messages"`
+	Searches    int `json:"searches"`
+	Failures    int `json:"failures"` // Failed requests, messages and searches
+}
+
+// AcceptanceRate returns the share of the counted requests accepted so far
+func (c Counts) AcceptanceRate() float64 {
+	if c.Connections == 0 {
+		return 0
+	}
+	return float64(c.Accepted) / float64(c.Connections)
+}
+
+// Activity is the action log aggregated over a filter, in total and
+// broken down by campaign, account and day
+type Activity struct {
+	Total      Counts            `json:"total"`
+	ByCampaign map[string]Counts `json:"by_campaign"`
+	ByAccount  map[string]Counts `json:"by_account"`
+	ByDay      map[string]Counts `json:"by_day"` // YYYY-MM-DD, local time
+}
+
+// Activity aggregates the action log entries matching the filter in one
+// pass. A connection request counts as accepted if its profile has been
+// accepted since, whenever that was.
+func (s *Storage) Activity(f StatsFilter) Activity {
+	s.logsSince(f.From)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return activity(s.logs, s.data.Profiles, f)
+}
+
+// activity aggregates logs as Activity does, looking profiles up by ID
+func activity(logs []ActionLog, profiles map[string]*Profile, f StatsFilter) Activity {
+	a := Activity{ByCampaign: make(map[string]Counts), ByAccount: make(map[string]Counts), ByDay: make(map[string]Counts)}
+	campaigns := make(map[string]string) // Profile ID -> campaign, looked up once
+	campaignOf := func(profileID string) string {
+		if profileID == "" || f.CampaignOf == nil {
+			return ""
+		}
+		if name, ok := campaigns[profileID]; ok {
+			return name
+		}
+		name := ""
+		if p, ok := profiles[profileID]; ok {
+			name = f.CampaignOf(p)
+		}
+		campaigns[profileID] = name
+		return name
+	}
+
+	for _, log := range logs {
+		if !f.From.IsZero() && log.Timestamp.Before(f.From) || !f.To.IsZero() && !log.Timestamp.Before(f.To) {
+			continue
+		}
+		if f.Account != "" && log.Account != f.Account {
+			continue
+		}
+		campaign := campaignOf(log.ProfileID)
+		if f.Campaign != "" && campaign != f.Campaign {
+			continue
+		}
+
+		var c Counts
+		switch log.Action {
+		case "connection":
+			c.Connections = 1
+			if p, ok := profiles[log.ProfileID]; ok && p.AcceptedAt != nil {
+				c.Accepted = 1
+			}
+		case "message":
+			c.Messages = 1
+		case "search":
+			c.Searches = 1
+		default:
+			continue // Logins and the like
+		}
+		if !log.Success {
+			c = Counts{Failures: 1}
+		}
+		a.Total = a.Total.add(c)
+		a.ByCampaign[campaign] = a.ByCampaign[campaign].add(c)
+		a.ByAccount[log.Account] = a.ByAccount[log.Account].add(c)
+		day := log.Timestamp.Local().Format("2006-01-02")
+		a.ByDay[day] = a.ByDay[day].add(c)
+	}
+	return a
+}
+
+// add returns the sum of two tallies
+func (c Counts) add(o Counts) Counts {
+	return Counts{
+		Connections: c.Connections + o.Connections,
+		Accepted:    c.Accepted + o.Accepted,
+		Messages:    c.Messages + o.Messages,
+		Searches:    c.Searches + o.Searches,
+		Failures:    c.Failures + o.Failures,
+	}
+}
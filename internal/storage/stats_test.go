@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+)
+
+func TestActivityFiltersAndBreakdowns(t *testing.T) {
+	day := time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local)
+	fake := clock.NewFake(day)
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	db, err := New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	accepted := day.Add(time.Hour)
+	for _, p := range []*Profile{
+		{ID: "a", ProfileURL: "https://www.linkedin.com/in/a", Company: "Acme", State: StateAccepted, AcceptedAt: &accepted},
+		{ID: "b", ProfileURL: "https://www.linkedin.com/in/b", Company: "Other", State: StateRequested},
+	} {
+		if err := db.SaveProfile(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	db.SetAccount("alice")
+	db.LogAction("connection", "a", true, nil)
+	db.LogAction("connection", "b", true, nil)
+	db.LogAction("search", "", true, nil)
+	db.LogAction("login_attempt", "", false, nil) // Not an outreach action
+	fake.Advance(24 * time.Hour)
+	db.SetAccount("bob")
+	db.LogAction("message", "a", true, nil)
+	db.LogAction("message", "b", false, errors.New("send failed"))
+
+	campaignOf := func(p *Profile) string {
+		if p.Company == "Acme" {
+			return "acme"
+		}
+		return "rest"
+	}
+	all := db.Activity(StatsFilter{CampaignOf: campaignOf})
+	if want := (Counts{Connections: 2, Accepted: 1, Messages: 1, Searches: 1, Failures: 1}); all.Total != want {
+		t.Errorf("total = %+v, want %+v", all.Total, want)
+	}
+	if got := all.ByCampaign["acme"]; got != (Counts{Connections: 1, Accepted: 1, Messages: 1}) {
+		t.Errorf("acme = %+v", got)
+	}
+	if len(all.ByAccount) != 2 || len(all.ByDay) != 2 {
+		t.Errorf("by account %v, by day %v, want 2 each", all.ByAccount, all.ByDay)
+	}
+
+	bob := db.Activity(StatsFilter{Account: "bob", CampaignOf: campaignOf})
+	if bob.Total != (Counts{Messages: 1, Failures: 1}) {
+		t.Errorf("bob = %+v", bob.Total)
+	}
+	firstDay := db.Activity(StatsFilter{From: day.Add(-time.Hour), To: day.Add(time.Hour), Campaign: "rest", CampaignOf: campaignOf})
+	if firstDay.Total != (Counts{Connections: 1}) {
+		t.Errorf("rest on the first day = %+v", firstDay.Total)
+	}
+	if rate := all.Total.AcceptanceRate(); rate != 0.5 {
+		t.Errorf("AcceptanceRate = %v, want 0.5", rate)
+	}
+}
+
+func TestGetStats(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []*Profile{
+		{ID: "a", ProfileURL: "https://www.linkedin.com/in/a", State: StateAccepted},
+		{ID: "b", ProfileURL: "https://www.linkedin.com/in/b", State: StateRequested},
+		{ID: "c", ProfileURL: "https://www.linkedin.com/in/c", State: StateRequested},
+	} {
+		if err := db.SaveProfile(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.LogAction("connection", "b", true, nil)
+
+	stats := db.GetStats()
+	if stats.TotalProfiles != 3 || stats.Requested != 2 || stats.Accepted != 1 || stats.ConnectionsToday != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if stats.TodayBySource[SourceAuto]["connection"] != 1 {
+		t.Errorf("today_by_source = %v", stats.TodayBySource)
+	}
+}
+
+func TestGetStatsAroundMidnight(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 5, 1, 23, 45, 0, 0, time.Local))
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+
+	db, err := New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.LogAction("connection", "a", true, nil)
+	fake.Advance(30 * time.Minute)
+	db.LogAction("message", "a", true, nil)
+	db.LogAction("connection", "b", false, errors.New("send failed"))
+
+	// Yesterday's request still counts toward the last hour, not today
+	stats := db.GetStats()
+	if stats.ConnectionsLastHour != 1 || stats.ConnectionsToday != 0 || stats.MessagesToday != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+	if len(stats.TodayBySource) != 1 || stats.TodayBySource[SourceAuto]["message"] != 1 {
+		t.Errorf("today_by_source = %v", stats.TodayBySource)
+	}
+}
@@ -2,17 +2,44 @@ package storage
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
 	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/config"
+	"subspace/internal/geo"
+	"subspace/internal/logger"
+	"subspace/internal/metrics"
+	"subspace/internal/seal"
+	"subspace/internal/taxonomy"
+)
+
+// Storage performance metrics, exposed via the metrics endpoint
+var (
+	saveLatency    = metrics.NewTimer("storage_save_seconds", "Time spent rewriting the storage file")
+	loadLatency    = metrics.NewTimer("storage_load_seconds", "Time spent reading the storage file")
+	slowSaves      = metrics.NewCounter("storage_slow_saves_total", "Storage rewrites slower than slow_write_threshold_ms")
+	fileSize       = metrics.NewGauge("storage_file_size_bytes", "Size of the storage file after the last write")
+	actionLogCount = metrics.NewGauge("storage_action_logs", "Number of action log entries held in storage")
+	profileCount   = metrics.NewGauge("storage_profiles", "Number of profiles held in storage")
 )
 
+// ErrReadOnly is returned by writes to storage opened with NewReadOnly
+var ErrReadOnly = errors.New("storage is open read-only")
+
 // ProfileState represents the state of a profile in the connection pipeline
 type ProfileState string
 
 const (
 	StateDiscovered  ProfileState = "discovered"
+	StateApproved    ProfileState = "approved" // Cleared for contact in the review queue
+	StateSkipped     ProfileState = "skipped"  // Passed over in the review queue
 	StateRequested   ProfileState = "requested"
 	StateAccepted    ProfileState = "accepted"
 	StateCooledDown  ProfileState = "cooled_down"
@@ -25,23 +52,123 @@ type Profile struct {
 	Name         string       `json:"name"`
 	Title        string       `json:"title"`
 	Company      string       `json:"company"`
+	Location     string       `json:"location,omitempty"`
+	Place        *geo.Place   `json:"place,omitempty"` // Location normalized, nil if unknown
+	Seniority    string       `json:"seniority,omitempty"` // Classified from Title, see the taxonomy module
+	Function     string       `json:"function,omitempty"`
 	ProfileURL   string       `json:"profile_url"`
 	State        ProfileState `json:"state"`
 	DiscoveredAt time.Time    `json:"discovered_at"`
+	ReviewedAt   *time.Time   `json:"reviewed_at,omitempty"`
 	RequestedAt  *time.Time   `json:"requested_at,omitempty"`
 	AcceptedAt   *time.Time   `json:"accepted_at,omitempty"`
 	CooledDownAt *time.Time   `json:"cooled_down_at,omitempty"`
 	SearchQuery  string       `json:"search_query"`
 	Notes        string       `json:"notes"`
+
+	// Operator labels grouping profiles, e.g. by campaign, lower case and
+	// sorted; see NormalizeTag
+	Tags []string `json:"tags,omitempty"`
+
+	// No actions are taken on the profile before SnoozedUntil
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty"`
+	SnoozeReason string     `json:"snooze_reason,omitempty"`
+
+	// Set by the operator to move the profile's pending connection request
+	// or message up (or down) the queue; higher goes first
+	Priority int `json:"priority,omitempty"`
+
+	// Shared is nil until the profile has been enriched
+	Shared *SharedContext `json:"shared,omitempty"`
+
+	// Company metadata from the enrichment provider, empty until enriched
+	// or when the provider doesn't know the company
+	Industry    string `json:"industry,omitempty"`
+	CompanySize string `json:"company_size,omitempty"`
+}
+
+// SharedContext is what the operator has in common with a profile
+type SharedContext struct {
+	MutualConnections int      `json:"mutual_connections"`
+	Groups            []string `json:"groups,omitempty"`
+	Schools           []string `json:"schools,omitempty"`
+}
+
+// Group returns the first shared group, or "" (also for nil context)
+func (c *SharedContext) Group() string {
+	if c == nil || len(c.Groups) == 0 {
+		return ""
+	}
+	return c.Groups[0]
+}
+
+// School returns the first shared school, or "" (also for nil context)
+func (c *SharedContext) School() string {
+	if c == nil || len(c.Schools) == 0 {
+		return ""
+	}
+	return c.Schools[0]
+}
+
+// MutualConnections returns the mutual connection count, 0 if unknown
+func (p *Profile) MutualConnections() int {
+	if p.Shared == nil {
+		return 0
+	}
+	return p.Shared.MutualConnections
+}
+
+// InSegments reports whether the profile's seniority is one of seniorities
+// and its function one of functions; an empty list matches every profile
+func (p *Profile) InSegments(seniorities, functions []string) bool {
+	role := taxonomy.Role{Seniority: p.Seniority, Function: p.Function}
+	if role.Seniority == "" {
+		role = taxonomy.Classify(p.Title) // Not stored since the taxonomy module was added
+	}
+	return (len(seniorities) == 0 || slices.Contains(seniorities, role.Seniority)) &&
+		(len(functions) == 0 || slices.Contains(functions, role.Function))
+}
+
+// InCompanies reports whether the profile's company is in one of the
+// industries (case-insensitive) and size buckets; an empty list matches
+// every profile, while unenriched profiles match no non-empty list
+func (p *Profile) InCompanies(industries, sizes []string) bool {
+	if len(industries) > 0 && !slices.ContainsFunc(industries, func(i string) bool { return strings.EqualFold(i, p.Industry) }) {
+		return false
+	}
+	return len(sizes) == 0 || slices.Contains(sizes, p.CompanySize)
+}
+
+// InLocations reports whether the profile is in one of the given cities,
+// regions or countries. Every profile is in an empty list; profiles of
+// unknown place are in none.
+func (p *Profile) InLocations(locations []string) bool {
+	if len(locations) == 0 {
+		return true
+	}
+	place := p.Place
+	if place == nil {
+		place = geo.Normalize(p.Location) // Not stored since the geo module was added
+	}
+	for _, location := range locations {
+		if place.Matches(location) {
+			return true
+		}
+	}
+	return false
 }
 
-// Message represents a message sent to a connection
+// Message represents a message sent to a connection, or one of their
+// replies when Inbound is set
 type Message struct {
 	ID          string    `json:"id"`
 	ProfileID   string    `json:"profile_id"`
 	Content     string    `json:"content"`
 	SentAt      time.Time `json:"sent_at"`
 	Template    string    `json:"template"`
+	Attachments []string  `json:"attachments,omitempty"` // File names as sent
+	Links       []string  `json:"links,omitempty"`
+	Inbound     bool      `json:"inbound,omitempty"` // Received from the profile
 }
 
 // ActionLog tracks all automated actions for rate limiting
@@ -51,6 +178,24 @@ type ActionLog struct {
 	ProfileID string    `json:"profile_id,omitempty"`
 	Success   bool      `json:"success"`
 	Error     string    `json:"error,omitempty"`
+	Source    string    `json:"source,omitempty"` // Who initiated it; empty means SourceAuto
+	Account   string    `json:"account,omitempty"` // app.account of the run; empty without accounts
+}
+
+// Action sources. Limits count actions from every source.
+const (
+	SourceAuto   = "auto"   // The automation pipeline
+	SourceManual = "manual" // The operator, e.g. replies typed in the inbox
+	SourceAPI    = "api"
+)
+
+// ActionSource returns who initiated the action, treating entries logged
+// before sources were recorded as automated
+func (l ActionLog) ActionSource() string {
+	if l.Source == "" {
+		return SourceAuto
+	}
+	return l.Source
 }
 
 // Storage handles all data persistence using JSON
@@ -58,25 +203,73 @@ type Storage struct {
 	path      string
 	data      *Data
 	mu        sync.RWMutex
+	slowWrite time.Duration
+	fuzzyDedup bool
+	account   string // Stamped on new action log entries
+	log       *logger.ContextLogger
+	readOnly  bool   // Opened without the run lock; nothing is written
+
+	// Write-behind: routine changes are flushed at most this often, 0
+	// writes each at once
+	flushEvery time.Duration
+	dirty      bool        // Changes not on disk yet
+	flushTimer *time.Timer // Pending flush of dirty changes
+
+	// Action log, kept in time order in the segments under actions/
+	logs     []ActionLog
+	logsFrom time.Time // Entries before this are on disk only; zero once all are loaded
+
+	// Whether what was read is encrypted differently from how it is
+	// written now, see the seal module
+	dataResealed bool
+	logsResealed bool
 }
 
 // Data represents the complete storage structure
 type Data struct {
 	Profiles   map[string]*Profile  `json:"profiles"`
 	Messages   map[string]*Message  `json:"messages"`
-	ActionLogs []ActionLog          `json:"action_logs"`
+	ActionLogs []ActionLog          `json:"action_logs,omitempty"` // Only in files written before the log moved to actions/
+	Aliases    map[string]string    `json:"aliases,omitempty"` // Profile key of a merged duplicate -> surviving profile ID
+	Companies  map[string]*CompanyInfo `json:"companies,omitempty"` // CompanyKey -> cached enrichment
 	LastSync   time.Time            `json:"last_sync"`
 }
 
+// newData returns an empty storage structure
+func newData() *Data {
+	return &Data{
+		Profiles:   make(map[string]*Profile),
+		Messages:   make(map[string]*Message),
+		ActionLogs: make([]ActionLog, 0),
+		Aliases:    make(map[string]string),
+		Companies:  make(map[string]*CompanyInfo),
+	}
+}
+
 // New creates a new storage instance
-func New(path string) (*Storage, error) {
+func New(path string, cfg config.StorageConfig) (*Storage, error) {
+	return open(path, cfg, false)
+}
+
+// NewReadOnly opens storage without writing to it, for commands that run
+// alongside the automation without the run lock. A missing file reads as
+// empty, and legacy entries and an encryption change are left for the
+// next writable open.
+func NewReadOnly(path string, cfg config.StorageConfig) (*Storage, error) {
+	return open(path, cfg, true)
+}
+
+// open loads storage, preparing the files for writing unless readOnly
+func open(path string, cfg config.StorageConfig, readOnly bool) (*Storage, error) {
 	s := &Storage{
-		path: path,
-		data: &Data{
-			Profiles:   make(map[string]*Profile),
-			Messages:   make(map[string]*Message),
-			ActionLogs: make([]ActionLog, 0),
-		},
+		path:      path,
+		readOnly:  readOnly,
+		slowWrite: time.Duration(cfg.SlowWriteThresholdMs) * time.Millisecond,
+		fuzzyDedup: cfg.FuzzyDedup,
+		log:       logger.NewContext("storage"),
+		data:      newData(),
+
+		flushEvery: time.Duration(cfg.FlushIntervalMs) * time.Millisecond,
 	}
 
 	// Load existing data if available
@@ -85,49 +278,237 @@ func New(path string) (*Storage, error) {
 			return nil, fmt.Errorf("failed to load storage: %w", err)
 		}
 		// File doesn't exist, start fresh
-		if err := s.save(); err != nil {
-			return nil, fmt.Errorf("failed to initialize storage: %w", err)
+		if !readOnly {
+			s.mu.Lock()
+			err := s.saveLocked()
+			s.mu.Unlock()
+			if err != nil {
+				return nil, fmt.Errorf("failed to initialize storage: %w", err)
+			}
 		}
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadLogsLocked(false); err != nil {
+		return nil, fmt.Errorf("failed to load storage: %w", err)
+	}
+	if readOnly {
+		// Legacy entries still in db.json are only read
+		for _, log := range s.data.ActionLogs {
+			s.insertLogLocked(log)
+		}
+		s.data.ActionLogs = make([]ActionLog, 0)
+		return s, nil
+	}
+	if err := s.migrateLogsLocked(); err != nil {
+		return nil, err
+	}
+	if err := s.resealLocked(); err != nil {
+		return nil, err
+	}
+
 	return s, nil
 }
 
+// resealLocked rewrites the files at once when storage.encryption was
+// turned on or off since they were written, rather than leaving them as
+// they are until the next change; the caller must hold s.mu
+func (s *Storage) resealLocked() error {
+	if s.dataResealed {
+		if err := s.saveLocked(); err != nil {
+			return fmt.Errorf("failed to re-encrypt storage: %w", err)
+		}
+	}
+	if !s.dataResealed && !s.logsResealed {
+		return nil
+	}
+	if err := s.allLogsLocked(); err != nil {
+		return err
+	}
+	if err := s.rewriteLogsLocked(); err != nil {
+		return fmt.Errorf("failed to re-encrypt action log: %w", err)
+	}
+	s.log.Info("Rewrote storage files", "encrypted", seal.Enabled())
+	s.dataResealed, s.logsResealed = false, false
+	return nil
+}
+
 // load reads data from disk
 func (s *Storage) load() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.loadLocked()
+}
+
+// loadLocked reads data from disk; the caller must hold s.mu
+func (s *Storage) loadLocked() error {
+	start := time.Now()
+	defer func() { loadLatency.Observe(time.Since(start)) }()
 
-	data, err := os.ReadFile(s.path)
+	raw, err := os.ReadFile(s.path)
 	if err != nil {
 		return err
 	}
+	sealed := seal.IsSealed(raw)
+	if raw, err = seal.Open(raw, seal.LabelStorage); err != nil {
+		return fmt.Errorf("%s: %w", s.path, err)
+	}
 
-	return json.Unmarshal(data, s.data)
+	data, err := decodeData(raw)
+	if err != nil {
+		return fmt.Errorf("%s: %w", s.path, err)
+	}
+	s.data = data
+	s.dataResealed = sealed != seal.Enabled()
+	s.updateGaugesLocked(len(raw))
+	return nil
+}
+
+// decodeData parses and checks a storage file. Nothing is returned unless
+// the whole file is valid, so a corrupt file can never be half loaded.
+func decodeData(raw []byte) (*Data, error) {
+	data := newData()
+	if err := json.Unmarshal(raw, data); err != nil {
+		return nil, fmt.Errorf("corrupt storage file: %w", err)
+	}
+
+	// Missing or null sections (and files written before aliases existed)
+	// load as empty rather than as nil maps that panic on first write
+	if data.Profiles == nil {
+		data.Profiles = make(map[string]*Profile)
+	}
+	if data.Messages == nil {
+		data.Messages = make(map[string]*Message)
+	}
+	if data.ActionLogs == nil {
+		data.ActionLogs = make([]ActionLog, 0)
+	}
+	if data.Aliases == nil {
+		data.Aliases = make(map[string]string)
+	}
+	if data.Companies == nil {
+		data.Companies = make(map[string]*CompanyInfo)
+	}
+
+	for id, profile := range data.Profiles {
+		if profile == nil {
+			return nil, fmt.Errorf("corrupt storage file: profile %q is null", id)
+		}
+		if profile.ID != id {
+			return nil, fmt.Errorf("corrupt storage file: profile %q is stored under key %q", profile.ID, id)
+		}
+	}
+	for id, msg := range data.Messages {
+		if msg == nil {
+			return nil, fmt.Errorf("corrupt storage file: message %q is null", id)
+		}
+		if msg.ID != id {
+			return nil, fmt.Errorf("corrupt storage file: message %q is stored under key %q", msg.ID, id)
+		}
+	}
+	return data, nil
 }
 
-// save writes data to disk
+// save writes a routine change to disk, or, with write-behind, schedules
+// the next flush. Writes that must be on disk at once, like transactions
+// and maintenance, call saveLocked instead.
 func (s *Storage) save() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.readOnly {
+		return ErrReadOnly
+	}
+	if s.flushEvery <= 0 {
+		return s.saveLocked()
+	}
+	s.dirty = true
+	if s.flushTimer == nil {
+		s.flushTimer = time.AfterFunc(s.flushEvery, func() {
+			if err := s.Flush(); err != nil {
+				s.log.Error("Failed to flush storage", "error", err)
+			}
+		})
+	}
+	return nil
+}
+
+// Flush writes buffered changes to disk now
+func (s *Storage) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.flushTimer != nil {
+		s.flushTimer.Stop()
+		s.flushTimer = nil
+	}
+	if !s.dirty {
+		return nil
+	}
+	return s.saveLocked()
+}
 
-	s.data.LastSync = time.Now()
+// Close writes buffered changes to disk; call it before the process exits
+func (s *Storage) Close() error {
+	return s.Flush()
+}
+
+// saveLocked writes data to disk; the caller must hold s.mu.
+// The file is written to a temporary path and renamed into place so a
+// crash mid-write never leaves a truncated db.json behind.
+func (s *Storage) saveLocked() error {
+	if s.readOnly {
+		return ErrReadOnly
+	}
+	start := time.Now()
+	s.data.LastSync = start
+	s.dirty = true // Until the write succeeds
 
 	data, err := json.MarshalIndent(s.data, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal data: %w", err)
 	}
+	if data, err = seal.Seal(data, seal.LabelStorage); err != nil {
+		return fmt.Errorf("failed to encrypt data: %w", err)
+	}
 
 	// Ensure directory exists
-	if err := os.MkdirAll(s.path[:len(s.path)-len("/db.json")], 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
 		return fmt.Errorf("failed to create data directory: %w", err)
 	}
 
-	return os.WriteFile(s.path, data, 0644)
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write data: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace data file: %w", err)
+	}
+
+	s.dirty = false
+
+	elapsed := time.Since(start)
+	saveLatency.Observe(elapsed)
+	s.updateGaugesLocked(len(data))
+	if s.slowWrite > 0 && elapsed > s.slowWrite {
+		slowSaves.Inc()
+		s.log.Warn("Slow storage write; consider pruning data or a different storage backend",
+			"duration_ms", elapsed.Milliseconds(),
+			"threshold_ms", s.slowWrite.Milliseconds(),
+			"size_bytes", len(data))
+	}
+	return nil
 }
 
-// SaveProfile saves or updates a profile
+// updateGaugesLocked refreshes size and count gauges; the caller must hold s.mu
+func (s *Storage) updateGaugesLocked(size int) {
+	fileSize.Set(float64(size))
+	profileCount.Set(float64(len(s.data.Profiles)))
+}
+
+// SaveProfile normalizes and saves or updates a profile
 func (s *Storage) SaveProfile(profile *Profile) error {
+	NormalizeProfile(profile)
 	s.mu.Lock()
 	s.data.Profiles[profile.ID] = profile
 	s.mu.Unlock()
@@ -160,13 +541,54 @@ func (s *Storage) GetProfilesByState(state ProfileState) []*Profile {
 	return profiles
 }
 
-// ProfileExists checks if a profile URL has been seen before (deduplication)
+// ConnectCandidates returns the profiles a connection request may be sent
+// to: approved ones first, then, unless approval is required, those still
+// awaiting review, each oldest discovered first. Snoozed profiles are left
+// out.
+func (s *Storage) ConnectCandidates(requireApproval bool) []*Profile {
+	candidates := s.GetProfilesByState(StateApproved)
+	sortByDiscovery(candidates)
+	if !requireApproval {
+		discovered := s.GetProfilesByState(StateDiscovered)
+		sortByDiscovery(discovered)
+		candidates = append(candidates, discovered...)
+	}
+	now := clock.Now()
+	awake := candidates[:0]
+	for _, p := range candidates {
+		if !p.Snoozed(now) {
+			awake = append(awake, p)
+		}
+	}
+	return awake
+}
+
+// GetAllProfiles retrieves every stored profile
+func (s *Storage) GetAllProfiles() []*Profile {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	profiles := make([]*Profile, 0, len(s.data.Profiles))
+	for _, profile := range s.data.Profiles {
+		profiles = append(profiles, profile)
+	}
+	return profiles
+}
+
+// ProfileExists checks if a profile URL has been seen before (deduplication).
+// URLs are compared by profile key, so tracking parameters and trailing
+// slashes do not make a known profile look new.
 func (s *Storage) ProfileExists(profileURL string) bool {
+	key := ProfileKey(profileURL)
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	if _, ok := s.data.Aliases[key]; ok {
+		return true
+	}
 	for _, profile := range s.data.Profiles {
-		if profile.ProfileURL == profileURL {
+		if ProfileKey(profile.ProfileURL) == key {
 			return true
 		}
 	}
@@ -195,34 +617,92 @@ func (s *Storage) GetMessagesByProfile(profileID string) []*Message {
 	return messages
 }
 
-// LogAction records an action for rate limiting purposes
+// GetMessagesSince retrieves all messages sent at or after the given time
+func (s *Storage) GetMessagesSince(since time.Time) []*Message {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	messages := make([]*Message, 0)
+	for _, msg := range s.data.Messages {
+		if !msg.SentAt.Before(since) {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+// LogAction records an automated action for rate limiting purposes
 func (s *Storage) LogAction(action, profileID string, success bool, err error) error {
+	return s.LogActionFrom(SourceAuto, action, profileID, success, err)
+}
+
+// LogActionFrom records an action initiated by source
+func (s *Storage) LogActionFrom(source, action, profileID string, success bool, err error) error {
 	s.mu.Lock()
-	
-	log := ActionLog{
-		Action:    action,
-		Timestamp: time.Now(),
-		ProfileID: profileID,
-		Success:   success,
-	}
-	if err != nil {
-		log.Error = err.Error()
+	defer s.mu.Unlock()
+
+	log := newActionLog(source, action, profileID, success, err)
+	log.Account = s.account
+	return s.appendLogsLocked([]ActionLog{log})
+}
+
+// SetAccount stamps the actions logged from now on with the account
+// running, so stats can be broken down by account
+func (s *Storage) SetAccount(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.account = name
+}
+
+// GetActionLogs returns a copy of the action log, optionally filtered to one
+// action type ("" returns every entry)
+func (s *Storage) GetActionLogs(action string) []ActionLog {
+	s.logsSince(time.Time{})
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	logs := make([]ActionLog, 0, len(s.logs))
+	for _, log := range s.logs {
+		if action == "" || log.Action == action {
+			logs = append(logs, log)
+		}
 	}
-	
-	s.data.ActionLogs = append(s.data.ActionLogs, log)
-	s.mu.Unlock()
-	
-	return s.save()
+	return logs
+}
+
+// MessageCount returns the number of stored messages
+func (s *Storage) MessageCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.data.Messages)
 }
 
 // GetActionCountSince returns the count of successful actions since a given time
 func (s *Storage) GetActionCountSince(action string, since time.Time) int {
+	s.logsSince(since)
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	count := 0
-	for _, log := range s.data.ActionLogs {
-		if log.Action == action && log.Success && log.Timestamp.After(since) {
+	for _, log := range s.tailLocked(since) {
+		if log.Action == action && log.Success {
+			count++
+		}
+	}
+	return count
+}
+
+// GetProfileTouchesSince returns the count of successful connection
+// requests and messages to a profile since a given time
+func (s *Storage) GetProfileTouchesSince(profileID string, since time.Time) int {
+	s.logsSince(since)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+	for _, log := range s.tailLocked(since) {
+		if log.ProfileID == profileID && log.Success &&
+			(log.Action == "connection" || log.Action == "message") {
 			count++
 		}
 	}
@@ -231,63 +711,194 @@ func (s *Storage) GetActionCountSince(action string, since time.Time) int {
 
 // GetActionCountToday returns today's action count
 func (s *Storage) GetActionCountToday(action string) int {
-	now := time.Now()
-	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	return s.GetActionCountSince(action, startOfDay)
+	return s.GetActionCountSince(action, startOfToday())
+}
+
+// GetActionCountsBySource returns the count of successful actions since a
+// given time per source and action, e.g. counts["manual"]["message"]
+func (s *Storage) GetActionCountsBySource(since time.Time) map[string]map[string]int {
+	s.logsSince(since)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]map[string]int)
+	for _, log := range s.tailLocked(since) {
+		if !log.Success {
+			continue
+		}
+		source := log.ActionSource()
+		if counts[source] == nil {
+			counts[source] = make(map[string]int)
+		}
+		counts[source][log.Action]++
+	}
+	return counts
+}
+
+// startOfToday returns local midnight of the current day
+func startOfToday() time.Time {
+	now := clock.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 }
 
 // GetActionCountLastHour returns the last hour's action count
 func (s *Storage) GetActionCountLastHour(action string) int {
-	return s.GetActionCountSince(action, time.Now().Add(-1*time.Hour))
+	return s.GetActionCountSince(action, clock.Now().Add(-1*time.Hour))
 }
 
 // CleanOldLogs removes action logs older than retention period (to prevent unbounded growth)
 func (s *Storage) CleanOldLogs(retentionDays int) error {
 	s.mu.Lock()
-	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+	defer s.mu.Unlock()
+	cutoff := clock.Now().AddDate(0, 0, -retentionDays)
+	if err := s.allLogsLocked(); err != nil {
+		return err
+	}
 	
 	filtered := make([]ActionLog, 0)
-	for _, log := range s.data.ActionLogs {
+	for _, log := range s.logs {
 		if log.Timestamp.After(cutoff) {
 			filtered = append(filtered, log)
 		}
 	}
-	s.data.ActionLogs = filtered
-	s.mu.Unlock()
+	s.logs = filtered
 	
-	return s.save()
+	return s.rewriteLogsLocked()
 }
 
-// GetStats returns summary statistics
-func (s *Storage) GetStats() map[string]interface{} {
+// PurgeResult counts the records removed by Purge
+type PurgeResult struct {
+	Profiles   int `json:"profiles"`
+	Messages   int `json:"messages"`
+	ActionLogs int `json:"action_logs"`
+}
+
+// Purge removes records older than the given cutoffs in a single write.
+// A zero cutoff leaves that record type untouched. Profiles are aged by
+// their most recent pipeline timestamp, and purging a profile also drops
+// its messages so no orphans are left behind.
+func (s *Storage) Purge(profilesBefore, messagesBefore, logsBefore time.Time) (PurgeResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result PurgeResult
+
+	if !profilesBefore.IsZero() {
+		for id, profile := range s.data.Profiles {
+			if profile.LastActivity().Before(profilesBefore) {
+				delete(s.data.Profiles, id)
+				result.Profiles++
+			}
+		}
+	}
+
+	for id, msg := range s.data.Messages {
+		_, hasProfile := s.data.Profiles[msg.ProfileID]
+		expired := !messagesBefore.IsZero() && msg.SentAt.Before(messagesBefore)
+		if expired || (result.Profiles > 0 && !hasProfile) {
+			delete(s.data.Messages, id)
+			result.Messages++
+		}
+	}
+
+	if !logsBefore.IsZero() {
+		if err := s.allLogsLocked(); err != nil {
+			return result, err
+		}
+		filtered := make([]ActionLog, 0, len(s.logs))
+		for _, log := range s.logs {
+			if log.Timestamp.Before(logsBefore) {
+				result.ActionLogs++
+				continue
+			}
+			filtered = append(filtered, log)
+		}
+		s.logs = filtered
+	}
+
+	if result == (PurgeResult{}) {
+		return result, nil
+	}
+	if result.ActionLogs > 0 {
+		if err := s.rewriteLogsLocked(); err != nil {
+			return result, err
+		}
+	}
+	if result.Profiles == 0 && result.Messages == 0 {
+		return result, nil
+	}
+	return result, s.saveLocked()
+}
+
+// LastActivity returns the most recent pipeline timestamp of the profile
+func (p *Profile) LastActivity() time.Time {
+	latest := p.DiscoveredAt
+	for _, t := range []*time.Time{p.RequestedAt, p.AcceptedAt, p.CooledDownAt} {
+		if t != nil && t.After(latest) {
+			latest = *t
+		}
+	}
+	return latest
+}
+
+// GetStats returns summary statistics. The counts are taken in one read
+// lock: the counting methods lock on their own, and a read lock taken
+// twice deadlocks once a flush is waiting for the write lock.
+func (s *Storage) GetStats() Stats {
+	today, hourAgo := startOfToday(), clock.Now().Add(-1*time.Hour)
+	since := today
+	if hourAgo.Before(since) {
+		since = hourAgo
+	}
+	s.logsSince(since)
+
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	stats := map[string]interface{}{
-		"total_profiles":         len(s.data.Profiles),
-		"discovered":             0,
-		"requested":              0,
-		"accepted":               0,
-		"cooled_down":            0,
-		"rejected":               0,
-		"total_messages":         len(s.data.Messages),
-		"connections_today":      s.GetActionCountToday("connection"),
-		"messages_today":         s.GetActionCountToday("message"),
-		"connections_last_hour":  s.GetActionCountLastHour("connection"),
+	stats := Stats{
+		TotalProfiles: len(s.data.Profiles),
+		TotalMessages: len(s.data.Messages),
+		TodayBySource: make(map[string]map[string]int),
+	}
+	for _, log := range s.tailLocked(since) {
+		if !log.Success {
+			continue
+		}
+		if log.Timestamp.After(hourAgo) && log.Action == "connection" {
+			stats.ConnectionsLastHour++
+		}
+		if !log.Timestamp.After(today) {
+			continue
+		}
+		switch log.Action {
+		case "connection":
+			stats.ConnectionsToday++
+		case "message":
+			stats.MessagesToday++
+		}
+		source := log.ActionSource()
+		if stats.TodayBySource[source] == nil {
+			stats.TodayBySource[source] = make(map[string]int)
+		}
+		stats.TodayBySource[source][log.Action]++
 	}
 
 	for _, profile := range s.data.Profiles {
 		switch profile.State {
 		case StateDiscovered:
-			stats["discovered"] = stats["discovered"].(int) + 1
+			stats.Discovered++
+		case StateApproved:
+			stats.Approved++
+		case StateSkipped:
+			stats.Skipped++
 		case StateRequested:
-			stats["requested"] = stats["requested"].(int) + 1
+			stats.Requested++
 		case StateAccepted:
-			stats["accepted"] = stats["accepted"].(int) + 1
+			stats.Accepted++
 		case StateCooledDown:
-			stats["cooled_down"] = stats["cooled_down"].(int) + 1
+			stats.CooledDown++
 		case StateRejected:
-			stats["rejected"] = stats["rejected"].(int) + 1
+			stats.Rejected++
 		}
 	}
 
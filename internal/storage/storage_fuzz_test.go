@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// FuzzDecodeData checks that arbitrary storage files either load completely
+// or fail with an error, never panic, and never leave nil collections behind
+func FuzzDecodeData(f *testing.F) {
+	f.Add([]byte(`{"profiles":{},"messages":{},"action_logs":[],"last_sync":"2024-01-01T00:00:00Z"}`))
+	f.Add([]byte(`{"profiles":{"p1":{"id":"p1","name":"Jane Doe","profile_url":"https://www.linkedin.com/in/jane","state":"requested","discovered_at":"2024-01-01T00:00:00Z","requested_at":"2024-01-02T00:00:00Z"}},"messages":{"m1":{"id":"m1","profile_id":"p1","sent_at":"2024-01-03T00:00:00Z"}},"action_logs":[{"action":"connection","timestamp":"2024-01-02T00:00:00Z","profile_id":"p1","success":true}],"aliases":{"in/jane-doe":"p1"}}`))
+	f.Add([]byte(``))
+	f.Add([]byte(`{}`))
+	f.Add([]byte(`null`))
+	f.Add([]byte(`{"profiles":null,"messages":null,"action_logs":null}`))
+	f.Add([]byte(`{"profiles":{"p1":null}}`))
+	f.Add([]byte(`{"profiles":{"p1":{"id":"p2"}}}`))
+	f.Add([]byte(`{"profiles":{"p1":{"id":"p1","discovered_at":"yesterday"}}}`))
+	f.Add([]byte(`{"profiles":{"p1":{"id":"p1"}},"action_logs":[{"timestamp":1}]}`))
+	f.Add([]byte(`{"profiles":{"p1":{"id":"p1"}}`))
+
+	f.Fuzz(func(t *testing.T, raw []byte) {
+		data, err := decodeData(raw)
+		if err != nil {
+			if data != nil {
+				t.Fatalf("decodeData returned data together with error %v", err)
+			}
+			return
+		}
+		if data.Profiles == nil || data.Messages == nil || data.ActionLogs == nil || data.Aliases == nil {
+			t.Fatalf("decodeData left a nil collection: %+v", data)
+		}
+		for id, p := range data.Profiles {
+			if p == nil || p.ID != id {
+				t.Fatalf("decodeData accepted inconsistent profile %q: %+v", id, p)
+			}
+		}
+
+		// Whatever loads must be written back in a form that loads again
+		out, err := json.Marshal(data)
+		if err != nil {
+			t.Fatalf("failed to marshal decoded data: %v", err)
+		}
+		if _, err := decodeData(out); err != nil {
+			t.Fatalf("re-decoding marshalled data failed: %v", err)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"subspace/internal/config"
+)
+
+func TestWriteBehind(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.json")
+	db, err := New(path, config.StorageConfig{FlushIntervalMs: 3600 * 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	onDisk := func() *Storage {
+		t.Helper()
+		disk, err := New(path, config.StorageConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return disk
+	}
+
+	if err := db.SaveProfile(&Profile{ID: "p1", ProfileURL: "https://www.linkedin.com/in/p1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.LogAction("search", "", true, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.GetProfile("p1"); err != nil {
+		t.Errorf("buffered profile not readable: %v", err)
+	}
+	if disk := onDisk(); len(disk.GetAllProfiles()) != 0 {
+		t.Error("routine changes were written before the flush")
+	}
+	if disk := onDisk(); len(disk.GetActionLogs("")) != 1 {
+		t.Error("action was not appended to the log at once")
+	}
+
+	// Transactions are written at once, with what was buffered before them
+	err = db.Transaction(func(tx *Tx) error {
+		tx.LogAction("connection", "p1", true, nil)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if disk := onDisk(); len(disk.GetAllProfiles()) != 1 || len(disk.GetActionLogs("")) != 2 {
+		t.Error("transaction did not reach disk with the buffered changes")
+	}
+
+	if err := db.SaveMessage(&Message{ID: "m1", ProfileID: "p1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if disk := onDisk(); len(disk.GetMessagesByProfile("p1")) != 1 {
+		t.Error("Close did not flush the buffered message")
+	}
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// NormalizeTag returns a tag in its stored form, lower case, or an error
+// if it has characters a targeting rule's tag:<name> can't match: tags
+// are made of letters, digits, "_" and "-", like "q3-outreach"
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", fmt.Errorf("tag is empty")
+	}
+	for _, c := range tag {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-') {
+			return "", fmt.Errorf("invalid tag %q: use letters, digits, _ and -", tag)
+		}
+	}
+	return tag, nil
+}
+
+// HasTag reports whether the profile carries the tag (case-insensitive)
+func (p *Profile) HasTag(tag string) bool {
+	return slices.Contains(p.Tags, strings.ToLower(strings.TrimSpace(tag)))
+}
+
+// InTags reports whether the profile carries one of the tags; every
+// profile is in an empty list
+func (p *Profile) InTags(tags []string) bool {
+	return len(tags) == 0 || slices.ContainsFunc(tags, p.HasTag)
+}
+
+// GetProfilesByTag returns the profiles carrying the tag
+func (s *Storage) GetProfilesByTag(tag string) []*Profile {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	profiles := make([]*Profile, 0)
+	for _, profile := range s.data.Profiles {
+		if profile.HasTag(tag) {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+// AddTag tags a profile; adding a tag it already carries changes nothing
+func (s *Storage) AddTag(profileID, tag string) error {
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	p, ok := s.data.Profiles[profileID]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("profile not found: %s", profileID)
+	}
+	if p.HasTag(tag) {
+		s.mu.Unlock()
+		return nil
+	}
+	p.Tags = append(p.Tags, tag)
+	sort.Strings(p.Tags)
+	s.mu.Unlock()
+
+	return s.save()
+}
+
+// RemoveTag removes a tag from a profile; removing one it doesn't carry
+// changes nothing
+func (s *Storage) RemoveTag(profileID, tag string) error {
+	s.mu.Lock()
+	p, ok := s.data.Profiles[profileID]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("profile not found: %s", profileID)
+	}
+	if !p.HasTag(tag) {
+		s.mu.Unlock()
+		return nil
+	}
+	p.Tags = slices.DeleteFunc(p.Tags, func(t string) bool { return t == strings.ToLower(strings.TrimSpace(tag)) })
+	if len(p.Tags) == 0 {
+		p.Tags = nil
+	}
+	s.mu.Unlock()
+
+	return s.save()
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"subspace/internal/config"
+	"subspace/internal/rules"
+)
+
+func TestTags(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"p1", "p2"} {
+		if err := db.SaveProfile(&Profile{ID: id, ProfileURL: "https://www.linkedin.com/in/" + id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tag := range []string{"Q3-Outreach", "golang-meetup", "q3-outreach"} {
+		if err := db.AddTag("p1", tag); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.AddTag("p2", "q3-outreach"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AddTag("p1", "two words"); err == nil {
+		t.Error("tag with a space accepted")
+	}
+	if err := db.AddTag("missing", "q3-outreach"); err == nil {
+		t.Error("tagged a profile that doesn't exist")
+	}
+
+	p1, _ := db.GetProfile("p1")
+	if want := []string{"golang-meetup", "q3-outreach"}; !slices.Equal(p1.Tags, want) {
+		t.Errorf("tags = %v, want %v", p1.Tags, want)
+	}
+	if n := len(db.GetProfilesByTag("Q3-OUTREACH")); n != 2 {
+		t.Errorf("profiles tagged q3-outreach = %d, want 2", n)
+	}
+	if !rules.MustCompile("tag:golang-meetup").Match(p1.RuleEnv()) {
+		t.Error("rule tag:golang-meetup doesn't match the tagged profile")
+	}
+
+	if err := db.RemoveTag("p1", "q3-outreach"); err != nil {
+		t.Fatal(err)
+	}
+	if got := db.GetProfilesByTag("q3-outreach"); len(got) != 1 || got[0].ID != "p2" {
+		t.Errorf("profiles tagged q3-outreach after removal = %v, want p2", got)
+	}
+
+	merged, err := db.Merge("p2", "p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"golang-meetup", "q3-outreach"}; !slices.Equal(merged.Tags, want) {
+		t.Errorf("merged tags = %v, want %v", merged.Tags, want)
+	}
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"fmt"
+
+	"subspace/internal/clock"
+)
+
+// Tx stages profile, message and action log writes so that a multi-record
+// update ("save profile + log action + save message") reaches disk in a
+// single write instead of several independent ones.
+//
+// A Tx is only valid inside the function passed to Storage.Transaction.
+type Tx struct {
+	profiles []*Profile
+	messages []*Message
+	logs     []ActionLog
+}
+
+// SaveProfile normalizes and stages a profile save
+func (tx *Tx) SaveProfile(profile *Profile) {
+	NormalizeProfile(profile)
+	tx.profiles = append(tx.profiles, profile)
+}
+
+// SaveMessage stages a message save
+func (tx *Tx) SaveMessage(message *Message) {
+	tx.messages = append(tx.messages, message)
+}
+
+// LogAction stages an automated action log entry
+func (tx *Tx) LogAction(action, profileID string, success bool, err error) {
+	tx.LogActionFrom(SourceAuto, action, profileID, success, err)
+}
+
+// LogActionFrom stages an action log entry initiated by source
+func (tx *Tx) LogActionFrom(source, action, profileID string, success bool, err error) {
+	tx.logs = append(tx.logs, newActionLog(source, action, profileID, success, err))
+}
+
+// Transaction runs fn and commits everything it staged atomically.
+//
+// If fn returns an error nothing is applied. If the commit itself fails,
+// in-memory state is reloaded from the last committed file so memory and
+// disk never disagree about a partially applied update; the staged action
+// log entries, appended first, are kept.
+func (s *Storage) Transaction(fn func(tx *Tx) error) error {
+	tx := &Tx{}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Actions are appended first: if the rest fails to commit they stay
+	// logged, and a rate limit counts an attempt too many rather than one
+	// too few
+	for i := range tx.logs {
+		tx.logs[i].Account = s.account
+	}
+	if err := s.appendLogsLocked(tx.logs); err != nil {
+		return fmt.Errorf("commit failed: %w", err)
+	}
+
+	// Buffered changes go to disk first, so a failed commit rolls back
+	// only what fn staged
+	if s.dirty {
+		if err := s.saveLocked(); err != nil {
+			return fmt.Errorf("commit failed: %w", err)
+		}
+	}
+
+	for _, profile := range tx.profiles {
+		s.data.Profiles[profile.ID] = profile
+	}
+	for _, message := range tx.messages {
+		s.data.Messages[message.ID] = message
+	}
+	if err := s.saveLocked(); err != nil {
+		if rerr := s.rollbackLocked(); rerr != nil {
+			return fmt.Errorf("commit failed: %v (rollback failed: %w)", err, rerr)
+		}
+		return fmt.Errorf("commit failed: %w", err)
+	}
+	return nil
+}
+
+// rollbackLocked discards in-memory changes by reloading the last committed
+// state from disk; the caller must hold s.mu
+func (s *Storage) rollbackLocked() error {
+	s.data = newData()
+	if err := s.loadLocked(); err != nil {
+		return err
+	}
+	return s.loadLogsLocked(s.logsFrom.IsZero())
+}
+
+// newActionLog builds an action log entry stamped with the current time
+func newActionLog(source, action, profileID string, success bool, err error) ActionLog {
+	log := ActionLog{
+		Action:    action,
+		Timestamp: clock.Now(),
+		ProfileID: profileID,
+		Success:   success,
+		Source:    source,
+	}
+	if err != nil {
+		log.Error = err.Error()
+	}
+	return log
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"subspace/internal/config"
+)
+
+func TestTransactionRollsBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.json")
+	db, err := New(path, config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveProfile(&Profile{ID: "p1", ProfileURL: "https://www.linkedin.com/in/p1", State: StateApproved}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failing closure applies nothing it staged
+	failed := errors.New("send failed")
+	err = db.Transaction(func(tx *Tx) error {
+		tx.SaveProfile(&Profile{ID: "p1", ProfileURL: "https://www.linkedin.com/in/p1", State: StateRequested})
+		tx.SaveMessage(&Message{ID: "m1", ProfileID: "p1"})
+		tx.LogAction("connection", "p1", true, nil)
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("got %v, want the closure's error", err)
+	}
+	if p, _ := db.GetProfile("p1"); p.State != StateApproved {
+		t.Errorf("state = %s after a failed transaction, want approved", p.State)
+	}
+	if n := len(db.GetMessagesByProfile("p1")); n != 0 {
+		t.Errorf("%d messages after a failed transaction", n)
+	}
+	if n := len(db.GetActionLogs("")); n != 0 {
+		t.Errorf("%d actions logged after a failed transaction", n)
+	}
+
+	// A commit that can't be written reloads the last committed state,
+	// keeping the actions logged
+	if err := os.MkdirAll(filepath.Join(path+".tmp", "blocked"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	err = db.Transaction(func(tx *Tx) error {
+		tx.SaveProfile(&Profile{ID: "p1", ProfileURL: "https://www.linkedin.com/in/p1", State: StateRequested})
+		tx.LogAction("connection", "p1", true, nil)
+		return nil
+	})
+	if err == nil {
+		t.Fatal("commit succeeded with its file blocked")
+	}
+	if p, _ := db.GetProfile("p1"); p.State != StateApproved {
+		t.Errorf("state = %s after a failed commit, want approved", p.State)
+	}
+	if n := len(db.GetActionLogs("connection")); n != 1 {
+		t.Errorf("%d connections logged after a failed commit, want 1", n)
+	}
+
+	reopened, err := New(path, config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := reopened.GetProfile("p1"); p.State != StateApproved {
+		t.Errorf("state on disk = %s, want approved", p.State)
+	}
+}
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Integrity issue kinds found by Verify
+const (
+	IssueOrphanedMessage  = "orphaned_message"  // Message of a profile not stored
+	IssueOrphanedLog      = "orphaned_log"      // Action log naming a profile not stored
+	IssueUnknownState     = "unknown_state"     // Profile state outside the pipeline
+	IssueMissingTimestamp = "missing_timestamp" // State reached without its timestamp
+	IssueTimestampOrder   = "timestamp_order"   // Step dated before the one it follows
+)
+
+// Issue is one inconsistency found by Verify
+type Issue struct {
+	Kind     string `json:"kind"`
+	ID       string `json:"id"` // Profile or message ID, or the log index
+	Detail   string `json:"detail"`
+	Repaired bool   `json:"repaired"`
+}
+
+// Verify checks referential integrity and that profile timestamps agree
+// with their states. With repair, what can be fixed safely is fixed in a
+// single write:
+//   - orphaned messages are dropped
+//   - orphaned action logs lose their profile ID but are kept, as they
+//     still count towards the rate limits
+//   - a missing or out-of-order timestamp is set to the step before it
+//
+// Profiles in an unknown state are only reported.
+func (s *Storage) Verify(repair bool) ([]Issue, error) {
+	s.logsSince(time.Time{})
+	if repair {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	} else {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	}
+
+	var issues []Issue
+	for id, msg := range s.data.Messages {
+		if _, ok := s.data.Profiles[msg.ProfileID]; ok {
+			continue
+		}
+		issues = append(issues, Issue{Kind: IssueOrphanedMessage, ID: id,
+			Detail: fmt.Sprintf("profile %s not found", msg.ProfileID), Repaired: repair})
+		if repair {
+			delete(s.data.Messages, id)
+		}
+	}
+	relogged := false
+	for i := range s.logs {
+		log := &s.logs[i]
+		if log.ProfileID == "" {
+			continue
+		}
+		if _, ok := s.data.Profiles[log.ProfileID]; ok {
+			continue
+		}
+		issues = append(issues, Issue{Kind: IssueOrphanedLog, ID: fmt.Sprint(i),
+			Detail: fmt.Sprintf("%s at %s: profile %s not found", log.Action, log.Timestamp.Format(time.RFC3339), log.ProfileID), Repaired: repair})
+		if repair {
+			log.ProfileID = ""
+			relogged = true
+		}
+	}
+	for _, p := range s.data.Profiles {
+		issues = append(issues, p.verify(repair)...)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Kind != issues[j].Kind {
+			return issues[i].Kind < issues[j].Kind
+		}
+		return issues[i].ID < issues[j].ID
+	})
+	if !repair || len(issues) == 0 {
+		return issues, nil
+	}
+	if err := s.saveLocked(); err != nil {
+		return issues, fmt.Errorf("failed to save repairs: %w", err)
+	}
+	if relogged {
+		if err := s.rewriteLogsLocked(); err != nil {
+			return issues, fmt.Errorf("failed to save repairs: %w", err)
+		}
+	}
+	s.log.Info("Storage repaired", "issues", len(issues))
+	return issues, nil
+}
+
+// verify checks the profile's timestamps against its state, repairing
+// them if asked to
+func (p *Profile) verify(repair bool) []Issue {
+	var issues []Issue
+	report := func(kind, detail string, repaired bool) {
+		issues = append(issues, Issue{Kind: kind, ID: p.ID, Detail: detail, Repaired: repaired})
+	}
+
+	reached := map[ProfileState]bool{StateDiscovered: true}
+	switch p.State {
+	case StateDiscovered, StateRejected:
+	case StateApproved, StateSkipped, StateRequested:
+		reached[p.State] = true
+	case StateAccepted:
+		reached[StateRequested], reached[StateAccepted] = true, true
+	case StateCooledDown:
+		reached[StateRequested], reached[StateAccepted], reached[StateCooledDown] = true, true, true
+	default:
+		report(IssueUnknownState, string(p.State), false)
+		return issues
+	}
+	if p.DiscoveredAt.IsZero() {
+		report(IssueMissingTimestamp, "discovered_at", false)
+		return issues
+	}
+
+	// Each step is dated no earlier than the step it follows; a review
+	// is optional and doesn't date the request
+	steps := []struct {
+		name     string
+		at       **time.Time
+		required bool
+	}{
+		{"reviewed_at", &p.ReviewedAt, reached[StateApproved] || reached[StateSkipped]},
+		{"requested_at", &p.RequestedAt, reached[StateRequested]},
+		{"accepted_at", &p.AcceptedAt, reached[StateAccepted]},
+		{"cooled_down_at", &p.CooledDownAt, reached[StateCooledDown]},
+	}
+	after := &p.DiscoveredAt
+	for _, step := range steps {
+		switch at := *step.at; {
+		case at == nil && step.required:
+			report(IssueMissingTimestamp, fmt.Sprintf("%s for state %s", step.name, p.State), repair)
+			if repair {
+				t := *after
+				*step.at = &t
+			}
+		case at != nil && at.Before(*after):
+			report(IssueTimestampOrder, fmt.Sprintf("%s %s precedes %s", step.name, at.Format(time.RFC3339), after.Format(time.RFC3339)), repair)
+			if repair {
+				t := *after
+				*step.at = &t
+			}
+		}
+		if *step.at != nil && step.name != "reviewed_at" {
+			after = *step.at
+		}
+	}
+	return issues
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"subspace/internal/config"
+)
+
+func TestVerifyReportsAndRepairs(t *testing.T) {
+	db, err := New(filepath.Join(t.TempDir(), "db.json"), config.StorageConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	before := day.Add(-time.Hour)
+	profiles := []*Profile{
+		{ID: "ok", ProfileURL: "https://www.linkedin.com/in/ok", State: StateDiscovered, DiscoveredAt: day},
+		{ID: "norequest", ProfileURL: "https://www.linkedin.com/in/norequest", State: StateAccepted, DiscoveredAt: day, AcceptedAt: &day},
+		{ID: "early", ProfileURL: "https://www.linkedin.com/in/early", State: StateRequested, DiscoveredAt: day, RequestedAt: &before},
+		{ID: "odd", ProfileURL: "https://www.linkedin.com/in/odd", State: "pending", DiscoveredAt: day},
+	}
+	for _, p := range profiles {
+		if err := db.SaveProfile(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.SaveMessage(&Message{ID: "m1", ProfileID: "gone"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.LogAction("connection", "gone", true, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := db.Verify(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{IssueMissingTimestamp, IssueOrphanedLog, IssueOrphanedMessage, IssueTimestampOrder, IssueUnknownState}
+	if len(issues) != len(want) {
+		t.Fatalf("Verify = %+v, want kinds %v", issues, want)
+	}
+	for i, issue := range issues {
+		if issue.Kind != want[i] || issue.Repaired {
+			t.Errorf("issue %d = %+v, want unrepaired %s", i, issue, want[i])
+		}
+	}
+
+	if _, err := db.Verify(true); err != nil {
+		t.Fatal(err)
+	}
+	if got := db.data.Profiles["norequest"].RequestedAt; got == nil || !got.Equal(day) {
+		t.Errorf("repaired requested_at = %v, want %v", got, day)
+	}
+	if got := db.data.Profiles["early"].RequestedAt; !got.Equal(day) {
+		t.Errorf("repaired requested_at = %v, want %v", got, day)
+	}
+	if len(db.GetMessagesByProfile("gone")) != 0 || db.logs[0].ProfileID != "" {
+		t.Error("orphans left after repair")
+	}
+
+	issues, err = db.Verify(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].Kind != IssueUnknownState {
+		t.Errorf("Verify after repair = %+v, want only the unknown state", issues)
+	}
+}
@@ -0,0 +1,48 @@
+package taxonomy
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CompanySizes lists the employee count buckets, smallest first
+var CompanySizes = []string{"1-10", "11-50", "51-200", "201-500", "501-1000", "1001-5000", "5001-10000", "10001+"}
+
+// sizeFloors holds the smallest employee count of each bucket
+var sizeFloors = []int{1, 11, 51, 201, 501, 1001, 5001, 10001}
+
+// SizeBucket returns the bucket for an employee count, or "" for none
+func SizeBucket(employees int) string {
+	if employees < 1 {
+		return ""
+	}
+	bucket := ""
+	for i, floor := range sizeFloors {
+		if employees >= floor {
+			bucket = CompanySizes[i]
+		}
+	}
+	return bucket
+}
+
+// NormalizeSize accepts a bucket in any spelling ("10,001+", "51 - 200") or
+// a plain employee count and returns the bucket, or "" if s is neither
+func NormalizeSize(s string) string {
+	s = strings.NewReplacer(",", "", " ", "", "employees", "").Replace(strings.ToLower(s))
+	if index(CompanySizes, s) >= 0 {
+		return s
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return SizeBucket(n)
+	}
+	return ""
+}
+
+// SizeFloor returns the smallest employee count of a bucket, or 0 for an
+// unknown bucket
+func SizeFloor(bucket string) int {
+	if i := index(CompanySizes, bucket); i >= 0 {
+		return sizeFloors[i]
+	}
+	return 0
+}
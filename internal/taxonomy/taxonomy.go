@@ -0,0 +1,175 @@
+package taxonomy
+
+import (
+	"strings"
+	"unicode"
+)
+
+/*
+TAXONOMY MODULE
+
+Classifies a free-text job title into a seniority level and a job function,
+so targeting and templates can work on segments ("engineering directors")
+instead of exact titles.
+
+Classification is rules-based: the title is lowercased and split into
+words, then matched against phrase tables. The longest matching phrase wins,
+so "Vice President" is a VP rather than a president, "Product Manager" is an
+individual contributor rather than a manager, and "Product Marketing
+Manager" is marketing rather than product. Equal-length matches go to the
+more senior level or the function listed first.
+*/
+
+// Seniority levels, most junior first
+const (
+	IC       = "ic"
+	Manager  = "manager"
+	Director = "director"
+	VP       = "vp"
+	CLevel   = "c_level"
+)
+
+// Job functions
+const (
+	Engineering = "engineering"
+	Data        = "data"
+	Product     = "product"
+	Design      = "design"
+	Sales       = "sales"
+	Marketing   = "marketing"
+	People      = "people"
+	Finance     = "finance"
+	Operations  = "operations"
+	General     = "general" // Founders and chief executives
+	Other       = "other"
+)
+
+// Seniorities lists the seniority levels, most junior first
+var Seniorities = []string{IC, Manager, Director, VP, CLevel}
+
+// Functions lists the job functions in tie-break order
+var Functions = []string{Engineering, Data, Product, Design, Sales, Marketing, People, Finance, Operations, General, Other}
+
+// seniorityPhrases maps title phrases to seniority levels
+var seniorityPhrases = map[string]string{
+	"intern": IC, "trainee": IC, "assistant": IC, "executive assistant": IC, "associate": IC,
+	"engineer": IC, "developer": IC, "analyst": IC, "designer": IC, "specialist": IC,
+	"consultant": IC, "scientist": IC, "lead": IC, "tech lead": IC, "staff": IC, "principal": IC,
+	"product manager": IC, "project manager": IC, "program manager": IC, "account manager": IC,
+	"account executive": IC,
+
+	"manager": Manager, "team lead": Manager, "team leader": Manager, "supervisor": Manager,
+	"engineering manager": Manager, "group product manager": Manager,
+
+	"director": Director, "head": Director, "head of": Director, "chief of staff": Director,
+
+	"vp": VP, "svp": VP, "evp": VP, "avp": VP, "vice president": VP,
+
+	"chief": CLevel, "ceo": CLevel, "cto": CLevel, "cfo": CLevel, "coo": CLevel, "cmo": CLevel,
+	"cio": CLevel, "ciso": CLevel, "cpo": CLevel, "cro": CLevel, "president": CLevel,
+	"founder": CLevel, "owner": CLevel, "managing director": CLevel, "partner": CLevel,
+}
+
+// functionPhrases maps title phrases to job functions
+var functionPhrases = map[string]string{
+	"engineer": Engineering, "engineering": Engineering, "developer": Engineering, "software": Engineering,
+	"programmer": Engineering, "sre": Engineering, "devops": Engineering, "architect": Engineering,
+	"backend": Engineering, "frontend": Engineering, "full stack": Engineering, "cto": Engineering,
+	"data engineer": Engineering, "qa": Engineering, "security": Engineering, "ciso": Engineering,
+
+	"data": Data, "analyst": Data, "analytics": Data, "data scientist": Data, "scientist": Data,
+	"machine learning": Data, "ml": Data, "ai": Data,
+
+	"product": Product, "product manager": Product, "product owner": Product, "cpo": Product,
+
+	"design": Design, "designer": Design, "ux": Design, "ui": Design, "product designer": Design,
+
+	"sales": Sales, "account executive": Sales, "account manager": Sales, "business development": Sales,
+	"bdr": Sales, "sdr": Sales, "cro": Sales, "customer success": Sales,
+
+	"marketing": Marketing, "product marketing": Marketing, "growth": Marketing, "brand": Marketing,
+	"content": Marketing, "seo": Marketing, "communications": Marketing, "cmo": Marketing,
+
+	"hr": People, "human resources": People, "people": People, "recruiter": People,
+	"recruiting": People, "talent": People, "talent acquisition": People,
+
+	"finance": Finance, "financial": Finance, "accountant": Finance, "accounting": Finance,
+	"controller": Finance, "cfo": Finance,
+
+	"operations": Operations, "ops": Operations, "coo": Operations, "supply chain": Operations,
+	"logistics": Operations,
+
+	"ceo": General, "founder": General, "owner": General, "president": General,
+	"managing director": General, "general manager": General,
+}
+
+// Role is the classification of a job title
+type Role struct {
+	Seniority string `json:"seniority"`
+	Function  string `json:"function"`
+}
+
+// Segment returns "seniority/function", e.g. "director/engineering"
+func (r Role) Segment() string {
+	return r.Seniority + "/" + r.Function
+}
+
+// Classify returns the role for a job title. Titles without any known
+// phrase are individual contributors in the other function; an empty title
+// has an empty role.
+func Classify(title string) Role {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return Role{}
+	}
+	padded := " " + strings.Join(words, " ") + " "
+	return Role{
+		Seniority: match(padded, seniorityPhrases, Seniorities, true, IC),
+		Function:  match(padded, functionPhrases, Functions, false, Other),
+	}
+}
+
+// match returns the value of the longest phrase found in the padded title.
+// Ties go to the latest value in order when later wins, else the earliest.
+func match(padded string, phrases map[string]string, order []string, laterWins bool, fallback string) string {
+	best, bestLen, bestRank := fallback, 0, -1
+	for phrase, value := range phrases {
+		if !strings.Contains(padded, " "+phrase+" ") {
+			continue
+		}
+		rank := index(order, value)
+		if !laterWins {
+			rank = len(order) - rank
+		}
+		if len(phrase) > bestLen || len(phrase) == bestLen && rank > bestRank {
+			best, bestLen, bestRank = value, len(phrase), rank
+		}
+	}
+	return best
+}
+
+// index returns the position of s in list, or -1
+func index(list []string, s string) int {
+	for i, v := range list {
+		if v == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// Valid reports whether s is a seniority level or job function
+func Valid(s string) bool {
+	return index(Seniorities, s) >= 0 || index(Functions, s) >= 0
+}
+
+// ValidSegment reports whether s is a seniority level, a job function or a
+// "seniority/function" pair
+func ValidSegment(s string) bool {
+	if seniority, function, ok := strings.Cut(s, "/"); ok {
+		return index(Seniorities, seniority) >= 0 && index(Functions, function) >= 0
+	}
+	return Valid(s)
+}
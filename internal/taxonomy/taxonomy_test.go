@@ -0,0 +1,41 @@
+package taxonomy
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	tests := map[string]Role{
+		"Senior Software Engineer":         {IC, Engineering},
+		"Engineering Manager, Platform":    {Manager, Engineering},
+		"Director of Engineering":          {Director, Engineering},
+		"Head of Data":                     {Director, Data},
+		"VP, Sales":                        {VP, Sales},
+		"Vice President of Marketing":      {VP, Marketing},
+		"Co-Founder & CEO":                 {CLevel, General},
+		"CTO":                              {CLevel, Engineering},
+		"Product Manager":                  {IC, Product},
+		"Senior Product Marketing Manager": {Manager, Marketing},
+		"Product Designer":                 {IC, Design},
+		"Data Engineer":                    {IC, Engineering},
+		"Machine Learning Scientist":       {IC, Data},
+		"Executive Assistant to the CEO":   {IC, General},
+		"Talent Acquisition Lead":          {IC, People},
+		"Gardener":                         {IC, Other},
+		"":                                 {},
+	}
+	for title, want := range tests {
+		if got := Classify(title); got != want {
+			t.Errorf("Classify(%q) = %+v, want %+v", title, got, want)
+		}
+	}
+}
+
+func TestValidSegment(t *testing.T) {
+	for s, want := range map[string]bool{
+		"director": true, "sales": true, "vp/engineering": true,
+		"engineering/vp": false, "intern": false, "": false,
+	} {
+		if got := ValidSegment(s); got != want {
+			t.Errorf("ValidSegment(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
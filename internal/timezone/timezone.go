@@ -0,0 +1,142 @@
+package timezone
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	_ "time/tzdata" // Zone data for minimal containers without /usr/share/zoneinfo
+)
+
+/*
+TIMEZONE MODULE
+
+Infers a recipient's time zone from the free-text location on their profile
+("Greater Munich Metropolitan Area", "San Francisco, California") so messages
+can be sent during the recipient's daytime rather than the operator's.
+
+Inference matches known city, region and country names, longest first, so
+"New South Wales" wins over "Wales". Locations that match nothing are
+unknown; operators can add their own names via messaging.location_time_zones.
+*/
+
+// builtIn maps lowercase location keywords to IANA zones
+var builtIn = map[string]string{
+	// North America
+	"new york": "America/New_York", "boston": "America/New_York", "atlanta": "America/New_York",
+	"miami": "America/New_York", "toronto": "America/Toronto",
+	"chicago": "America/Chicago", "austin": "America/Chicago", "dallas": "America/Chicago", "texas": "America/Chicago",
+	"denver": "America/Denver", "colorado": "America/Denver",
+	"san francisco": "America/Los_Angeles", "bay area": "America/Los_Angeles", "los angeles": "America/Los_Angeles",
+	"seattle": "America/Los_Angeles", "california": "America/Los_Angeles", "vancouver": "America/Vancouver",
+	"mexico city": "America/Mexico_City",
+	// South America
+	"são paulo": "America/Sao_Paulo", "sao paulo": "America/Sao_Paulo", "brazil": "America/Sao_Paulo",
+	"buenos aires": "America/Argentina/Buenos_Aires", "argentina": "America/Argentina/Buenos_Aires",
+	// Europe
+	"london": "Europe/London", "united kingdom": "Europe/London", "england": "Europe/London",
+	"scotland": "Europe/London", "wales": "Europe/London", "dublin": "Europe/Dublin", "ireland": "Europe/Dublin",
+	"lisbon": "Europe/Lisbon", "portugal": "Europe/Lisbon",
+	"madrid": "Europe/Madrid", "barcelona": "Europe/Madrid", "spain": "Europe/Madrid", "españa": "Europe/Madrid",
+	"paris": "Europe/Paris", "france": "Europe/Paris", "amsterdam": "Europe/Amsterdam", "netherlands": "Europe/Amsterdam",
+	"berlin": "Europe/Berlin", "munich": "Europe/Berlin", "münchen": "Europe/Berlin", "hamburg": "Europe/Berlin",
+	"germany": "Europe/Berlin", "deutschland": "Europe/Berlin", "zurich": "Europe/Zurich", "switzerland": "Europe/Zurich",
+	"vienna": "Europe/Vienna", "austria": "Europe/Vienna", "milan": "Europe/Rome", "italy": "Europe/Rome",
+	"stockholm": "Europe/Stockholm", "sweden": "Europe/Stockholm", "warsaw": "Europe/Warsaw", "poland": "Europe/Warsaw",
+	"helsinki": "Europe/Helsinki", "finland": "Europe/Helsinki", "athens": "Europe/Athens", "greece": "Europe/Athens",
+	"istanbul": "Europe/Istanbul", "turkey": "Europe/Istanbul",
+	// Africa and Middle East
+	"lagos": "Africa/Lagos", "nigeria": "Africa/Lagos", "cairo": "Africa/Cairo", "egypt": "Africa/Cairo",
+	"johannesburg": "Africa/Johannesburg", "cape town": "Africa/Johannesburg", "south africa": "Africa/Johannesburg",
+	"dubai": "Asia/Dubai", "united arab emirates": "Asia/Dubai", "tel aviv": "Asia/Jerusalem", "israel": "Asia/Jerusalem",
+	// Asia and Pacific
+	"bangalore": "Asia/Kolkata", "bengaluru": "Asia/Kolkata", "mumbai": "Asia/Kolkata", "delhi": "Asia/Kolkata",
+	"hyderabad": "Asia/Kolkata", "india": "Asia/Kolkata", "singapore": "Asia/Singapore",
+	"hong kong": "Asia/Hong_Kong", "shanghai": "Asia/Shanghai", "beijing": "Asia/Shanghai", "china": "Asia/Shanghai",
+	"tokyo": "Asia/Tokyo", "japan": "Asia/Tokyo", "seoul": "Asia/Seoul", "korea": "Asia/Seoul",
+	"sydney": "Australia/Sydney", "new south wales": "Australia/Sydney", "melbourne": "Australia/Melbourne",
+	"auckland": "Pacific/Auckland", "new zealand": "Pacific/Auckland",
+}
+
+// Resolver infers time zones from locations
+type Resolver struct {
+	keywords []string // Longest first
+	zones    map[string]*time.Location
+}
+
+// NewResolver builds a resolver from the built-in names plus extra
+// location keyword -> IANA zone entries, which take precedence
+func NewResolver(extra map[string]string) (*Resolver, error) {
+	names := make(map[string]string, len(builtIn)+len(extra))
+	for k, v := range builtIn {
+		names[k] = v
+	}
+	for k, v := range extra {
+		if k = strings.ToLower(strings.TrimSpace(k)); k != "" {
+			names[k] = v
+		}
+	}
+
+	r := &Resolver{zones: make(map[string]*time.Location, len(names))}
+	for keyword, zone := range names {
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q for %q: %w", zone, keyword, err)
+		}
+		r.keywords = append(r.keywords, keyword)
+		r.zones[keyword] = loc
+	}
+	sort.Slice(r.keywords, func(i, j int) bool {
+		if len(r.keywords[i]) != len(r.keywords[j]) {
+			return len(r.keywords[i]) > len(r.keywords[j])
+		}
+		return r.keywords[i] < r.keywords[j]
+	})
+	return r, nil
+}
+
+// Infer returns the time zone of a location, or nil if unknown
+func (r *Resolver) Infer(location string) *time.Location {
+	l := " " + strings.ToLower(location) + " "
+	for _, keyword := range r.keywords {
+		if containsWord(l, keyword) {
+			return r.zones[keyword]
+		}
+	}
+	return nil
+}
+
+// containsWord reports whether keyword occurs in s on word boundaries, so
+// "india" doesn't match "Indiana"
+func containsWord(s, keyword string) bool {
+	for i := 0; ; {
+		j := strings.Index(s[i:], keyword)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(keyword)
+		if !isLetter(s[start-1]) && (end >= len(s) || !isLetter(s[end])) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+// isLetter reports whether b is part of a word (ASCII letters and UTF-8
+// continuation bytes)
+func isLetter(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 0x80
+}
+
+// Within reports whether the local time at loc falls in [start, end),
+// both "15:04". A nil location is treated as always within.
+func Within(now time.Time, loc *time.Location, start, end string) bool {
+	if loc == nil {
+		return true
+	}
+	local := now.In(loc).Format("15:04")
+	if start <= end {
+		return local >= start && local < end
+	}
+	return local >= start || local < end // Window across midnight
+}
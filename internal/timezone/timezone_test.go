@@ -0,0 +1,63 @@
+package timezone
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInfer(t *testing.T) {
+	r, err := NewResolver(map[string]string{"Greater Boston": "America/New_York"})
+	if err != nil {
+		t.Fatalf("NewResolver: %v", err)
+	}
+
+	tests := map[string]string{
+		"San Francisco Bay Area":             "America/Los_Angeles",
+		"Berlin, Germany":                    "Europe/Berlin",
+		"Greater Munich Metropolitan Area":   "Europe/Berlin",
+		"Sydney, New South Wales, Australia": "Australia/Sydney",
+		"Cardiff, Wales, United Kingdom":     "Europe/London",
+		"Bengaluru, Karnataka, India":        "Asia/Kolkata",
+		"München, Bayern":                    "Europe/Berlin",
+		"greater boston":                     "America/New_York",
+		"Indianapolis, Indiana":              "", // not India
+		"Remote":                             "",
+		"":                                   "",
+	}
+	for location, want := range tests {
+		got := ""
+		if loc := r.Infer(location); loc != nil {
+			got = loc.String()
+		}
+		if got != want {
+			t.Errorf("Infer(%q) = %q, want %q", location, got, want)
+		}
+	}
+
+	if _, err := NewResolver(map[string]string{"mars": "Mars/Olympus_Mons"}); err == nil {
+		t.Error("NewResolver accepted an unknown zone")
+	}
+}
+
+func TestWithin(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	now := time.Date(2024, 6, 10, 8, 0, 0, 0, time.UTC) // 10:00 Berlin, 17:00 Tokyo
+
+	tests := []struct {
+		loc        *time.Location
+		start, end string
+		want       bool
+	}{
+		{berlin, "09:00", "18:00", true},
+		{tokyo, "09:00", "17:00", false},
+		{tokyo, "22:00", "18:00", true}, // across midnight
+		{berlin, "22:00", "06:00", false},
+		{nil, "09:00", "10:00", true},
+	}
+	for _, tt := range tests {
+		if got := Within(now, tt.loc, tt.start, tt.end); got != tt.want {
+			t.Errorf("Within(%v, %s-%s) = %v, want %v", tt.loc, tt.start, tt.end, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package update
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+UPDATE MODULE
+
+Checks GitHub releases for a newer version and replaces the running binary
+with it. Every release carries, next to one binary per platform:
+
+- SHA256SUMS: "<sha256>  <asset>" lines, as written by sha256sum
+- SHA256SUMS.sig: the base64 Ed25519 signature of the release tag on a
+  line of its own followed by SHA256SUMS (see SignedPayload)
+
+A download is only installed if it is newer than the running build, the
+signature verifies against the release signing key built into the binary,
+and the binary matches its checksum. Signing the tag with the checksums
+keeps an old, vulnerable release from being served again under a new tag.
+Builds without a key can check for updates but not install them.
+*/
+
+// Repo is the GitHub repository releases are published to
+const Repo = "shubhankarvyas/subspace"
+
+// PublicKey is the base64 Ed25519 release signing key, set when building
+// a release with -ldflags "-X subspace/internal/update.PublicKey=..."
+var PublicKey = ""
+
+// Release is a published GitHub release
+type Release struct {
+	Tag    string  `json:"tag_name"`
+	URL    string  `json:"html_url"`
+	Assets []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Client talks to the GitHub releases API
+type Client struct {
+	API  string // e.g. https://api.github.com
+	Repo string
+	HTTP *http.Client
+}
+
+// New returns a client for the releases of Repo
+func New() *Client {
+	return &Client{API: "https://api.github.com", Repo: Repo, HTTP: &http.Client{Timeout: 60 * time.Second}}
+}
+
+// Latest returns the latest published release
+func (c *Client) Latest() (*Release, error) {
+	body, err := c.get(fmt.Sprintf("%s/repos/%s/releases/latest", c.API, c.Repo))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for releases: %w", err)
+	}
+	var rel Release
+	if err := json.Unmarshal(body, &rel); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	return &rel, nil
+}
+
+// AssetName is the release asset holding the binary for a platform
+func AssetName(goos, goarch string) string {
+	name := fmt.Sprintf("subspace_%s_%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Download fetches the named asset of rel and verifies it against the
+// signed checksums with key. Releases not newer than current are refused.
+func (c *Client) Download(rel *Release, name, current string, key ed25519.PublicKey) ([]byte, error) {
+	if !Newer(current, rel.Tag) {
+		return nil, fmt.Errorf("release %s is not newer than %s", rel.Tag, current)
+	}
+	urls := make(map[string]string)
+	for _, a := range rel.Assets {
+		urls[a.Name] = a.URL
+	}
+	for _, want := range []string{name, "SHA256SUMS", "SHA256SUMS.sig"} {
+		if urls[want] == "" {
+			return nil, fmt.Errorf("release %s has no %s", rel.Tag, want)
+		}
+	}
+
+	sums, err := c.get(urls["SHA256SUMS"])
+	if err != nil {
+		return nil, err
+	}
+	sig, err := c.get(urls["SHA256SUMS.sig"])
+	if err != nil {
+		return nil, err
+	}
+	if err := Verify(rel.Tag, sums, sig, key); err != nil {
+		return nil, err
+	}
+	want, err := checksum(sums, name)
+	if err != nil {
+		return nil, err
+	}
+
+	binary, err := c.get(urls[name])
+	if err != nil {
+		return nil, err
+	}
+	if got := sha256.Sum256(binary); hex.EncodeToString(got[:]) != want {
+		return nil, fmt.Errorf("%s doesn't match its checksum", name)
+	}
+	return binary, nil
+}
+
+// ParseKey decodes a base64 Ed25519 public key
+func ParseKey(s string) (ed25519.PublicKey, error) {
+	if s == "" {
+		return nil, fmt.Errorf("this build has no release signing key, so updates can't be verified")
+	}
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid release signing key")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// SignedPayload is what a release's SHA256SUMS.sig signs: its tag on the
+// first line, then the checksums file
+func SignedPayload(tag string, sums []byte) []byte {
+	return append([]byte(tag+"\n"), sums...)
+}
+
+// Verify checks the base64 signature of the checksums file released as tag
+func Verify(tag string, sums, sig []byte, key ed25519.PublicKey) error {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return fmt.Errorf("invalid checksum signature: %w", err)
+	}
+	if !ed25519.Verify(key, SignedPayload(tag, sums), raw) {
+		return fmt.Errorf("signature of %s checksums doesn't verify with the release signing key", tag)
+	}
+	return nil
+}
+
+// checksum finds the hex SHA-256 of name in a sha256sum file
+func checksum(sums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("SHA256SUMS has no checksum for %s", name)
+}
+
+// Newer reports whether version latest is newer than current. A current
+// version that isn't a release, e.g. "dev", is always older.
+func Newer(current, latest string) bool {
+	cur, ok := parseVersion(current)
+	if !ok {
+		return true
+	}
+	next, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	for i := range cur {
+		if next[i] != cur[i] {
+			return next[i] > cur[i]
+		}
+	}
+	return false
+}
+
+// parseVersion parses "v1.2.3" or "1.2.3", ignoring any pre-release suffix
+func parseVersion(v string) ([3]int, bool) {
+	var out [3]int
+	v, _, _ = strings.Cut(strings.TrimPrefix(v, "v"), "-")
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return out, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return out, false
+		}
+		out[i] = n
+	}
+	return out, true
+}
+
+// Replace swaps the binary at exe for data. The old binary is kept next
+// to it as exe.old, which also works on Windows where a running binary
+// can be renamed but not overwritten.
+func Replace(exe string, data []byte) error {
+	info, err := os.Stat(exe)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(exe), ".subspace-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to stage update: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to stage update: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to stage update: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()|0o111); err != nil {
+		return err
+	}
+
+	old := exe + ".old"
+	os.Remove(old)
+	if err := os.Rename(exe, old); err != nil {
+		return fmt.Errorf("failed to move the current binary aside: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), exe); err != nil {
+		os.Rename(old, exe)
+		return fmt.Errorf("failed to install update: %w", err)
+	}
+	return nil
+}
+
+// get fetches url, failing on non-2xx responses
+func (c *Client) get(url string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 256<<20))
+}
@@ -0,0 +1,99 @@
+package update
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewer(t *testing.T) {
+	for _, tc := range []struct {
+		current, latest string
+		want            bool
+	}{
+		{"v1.2.3", "v1.2.4", true},
+		{"v1.2.3", "v1.10.0", true},
+		{"1.2.3", "v1.2.3", false},
+		{"v2.0.0", "v1.9.9", false},
+		{"dev", "v0.1.0", true},
+		{"v1.0.0", "nightly", false},
+	} {
+		if got := Newer(tc.current, tc.latest); got != tc.want {
+			t.Errorf("Newer(%q, %q) = %v", tc.current, tc.latest, got)
+		}
+	}
+}
+
+func TestDownloadVerifies(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	binary := []byte("new subspace binary")
+	name := AssetName("linux", "amd64")
+	sum := sha256.Sum256(binary)
+	sums := []byte(hex.EncodeToString(sum[:]) + "  " + name + "\n")
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, SignedPayload("v1.1.0", sums)))
+
+	files := map[string][]byte{"/" + name: binary, "/SHA256SUMS": sums, "/SHA256SUMS.sig": []byte(sig)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/o/r/releases/latest" {
+			rel := Release{Tag: "v1.1.0"}
+			for file := range files {
+				rel.Assets = append(rel.Assets, Asset{Name: file[1:], URL: "http://" + r.Host + file})
+			}
+			json.NewEncoder(w).Encode(rel)
+			return
+		}
+		w.Write(files[r.URL.Path])
+	}))
+	defer srv.Close()
+
+	c := New()
+	c.API, c.Repo = srv.URL, "o/r"
+	rel, err := c.Latest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.Download(rel, name, "v1.0.0", pub)
+	if err != nil || string(got) != string(binary) {
+		t.Fatalf("Download = %q, %v", got, err)
+	}
+
+	if _, err := c.Download(rel, name, "v1.1.0", pub); err == nil {
+		t.Error("expected a release not newer than the running build to be refused")
+	}
+	other, _, _ := ed25519.GenerateKey(nil)
+	if _, err := c.Download(rel, name, "v1.0.0", other); err == nil {
+		t.Error("expected a signature from another key to be rejected")
+	}
+	retagged := &Release{Tag: "v1.2.0", Assets: rel.Assets}
+	if _, err := c.Download(retagged, name, "v1.0.0", pub); err == nil {
+		t.Error("expected checksums signed for another tag to be rejected")
+	}
+	files["/"+name] = []byte("tampered")
+	if _, err := c.Download(rel, name, "v1.0.0", pub); err == nil {
+		t.Error("expected a binary not matching its checksum to be rejected")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "subspace")
+	os.WriteFile(exe, []byte("old"), 0o755)
+	if err := Replace(exe, []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(exe); string(data) != "new" {
+		t.Errorf("binary = %q", data)
+	}
+	if data, _ := os.ReadFile(exe + ".old"); string(data) != "old" {
+		t.Errorf("backup = %q", data)
+	}
+	if info, _ := os.Stat(exe); info.Mode().Perm()&0o111 == 0 {
+		t.Errorf("binary isn't executable: %v", info.Mode())
+	}
+}
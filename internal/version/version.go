@@ -0,0 +1,68 @@
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Build metadata, set when building a release:
+//
+//	go build -ldflags "-X subspace/internal/version.Version=v1.2.0
+//	  -X subspace/internal/version.Commit=$(git rev-parse HEAD)
+//	  -X subspace/internal/version.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// "make build" does this. Otherwise the commit and date come from the VCS
+// information Go records in the binary, when there is any.
+var (
+	Version = "dev"
+	Commit  = ""
+	Date    = ""
+)
+
+// BuildInfo describes the running binary
+type BuildInfo struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit,omitempty"`
+	Date    string `json:"date,omitempty"`
+	Dirty   bool   `json:"dirty,omitempty"`
+	Go      string `json:"go"`
+}
+
+// Info returns the build metadata of the running binary
+func Info() BuildInfo {
+	info := BuildInfo{Version: Version, Commit: Commit, Date: Date, Go: runtime.Version()}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch {
+			case s.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = s.Value
+			case s.Key == "vcs.time" && info.Date == "":
+				info.Date = s.Value
+			case s.Key == "vcs.modified":
+				info.Dirty = s.Value == "true"
+			}
+		}
+	}
+	return info
+}
+
+// String formats the build metadata on one line,
+// e.g. "v1.2.0 (commit 1a2b3c4d, built 2026-05-01T10:00:00Z, go1.22.3)"
+func (b BuildInfo) String() string {
+	s := b.Version + " ("
+	if b.Commit != "" {
+		commit := b.Commit
+		if len(commit) > 8 {
+			commit = commit[:8]
+		}
+		if b.Dirty {
+			commit += "-dirty"
+		}
+		s += "commit " + commit + ", "
+	}
+	if b.Date != "" {
+		s += "built " + b.Date + ", "
+	}
+	return fmt.Sprintf("%s%s)", s, b.Go)
+}
@@ -0,0 +1,159 @@
+package watchdog
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"subspace/internal/config"
+	"subspace/internal/logger"
+	"subspace/internal/metrics"
+)
+
+/*
+WATCHDOG MODULE
+
+Keeps long runs from degrading. Between workflow steps it samples:
+- the resident memory (RSS) of the Chrome browser process, read from
+  /proc (Linux only; elsewhere it is reported as unknown)
+- the number of open pages in the browser
+- the Go heap in use
+
+A browser over its RSS or page limit is recycled: relaunched with the
+current session kept. A heap over its limit compacts storage (dropping only
+what no query can reach, never data retention would keep) and returns freed
+memory to the OS. Checks run between steps
+rather than on a timer so nothing is recycled halfway through an action.
+*/
+
+var (
+	browserRSS   = metrics.NewGauge("watchdog_browser_rss_bytes", "Resident memory of the Chrome browser process")
+	browserPages = metrics.NewGauge("watchdog_browser_pages", "Pages open in the browser")
+	heapInUse    = metrics.NewGauge("watchdog_go_heap_bytes", "Go heap in use")
+	recycles     = metrics.NewCounter("watchdog_browser_recycles_total", "Browser relaunches triggered by the watchdog")
+	compactions  = metrics.NewCounter("watchdog_compactions_total", "Storage compactions triggered by the watchdog")
+)
+
+// Browser is the browser the watchdog measures and recycles
+type Browser interface {
+	PID() int                // Browser process ID, 0 if unknown
+	PageCount() (int, error) // Open pages
+	Recycle() error          // Relaunch, keeping the session
+}
+
+// Sample is one measurement. Unknown values are -1.
+type Sample struct {
+	BrowserRSS int64  `json:"browser_rss"`
+	Pages      int    `json:"pages"`
+	Heap       uint64 `json:"heap"`
+}
+
+// Watchdog checks resource use against the configured limits
+type Watchdog struct {
+	cfg     config.WatchdogConfig
+	browser Browser
+	compact func() error
+	log     *logger.ContextLogger
+
+	rss  func(pid int) int64 // Overridable in tests
+	heap func() uint64
+}
+
+// New returns a watchdog for the browser that compacts storage with the
+// given function, or nil if the watchdog is disabled
+func New(cfg config.WatchdogConfig, browser Browser, compact func() error) *Watchdog {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Watchdog{
+		cfg:     cfg,
+		browser: browser,
+		compact: compact,
+		log:     logger.NewContext("watchdog"),
+		rss:     processRSS,
+		heap:    goHeap,
+	}
+}
+
+// Sample measures current resource use
+func (w *Watchdog) Sample() Sample {
+	s := Sample{BrowserRSS: -1, Pages: -1, Heap: w.heap()}
+	if pid := w.browser.PID(); pid > 0 {
+		s.BrowserRSS = w.rss(pid)
+	}
+	if n, err := w.browser.PageCount(); err == nil {
+		s.Pages = n
+	}
+
+	browserRSS.Set(float64(s.BrowserRSS))
+	browserPages.Set(float64(s.Pages))
+	heapInUse.Set(float64(s.Heap))
+	return s
+}
+
+// Check samples resource use and recycles the browser or compacts storage
+// if a limit is exceeded. It returns the sample taken before acting.
+func (w *Watchdog) Check() (Sample, error) {
+	s := w.Sample()
+	w.log.Debug("Resource sample", "browser_rss_mb", s.BrowserRSS>>20, "pages", s.Pages, "heap_mb", s.Heap>>20)
+
+	if reason := w.browserOverLimit(s); reason != "" {
+		w.log.Warn("Recycling browser", "reason", reason)
+		recycles.Inc()
+		if err := w.browser.Recycle(); err != nil {
+			return s, fmt.Errorf("failed to recycle browser: %w", err)
+		}
+	}
+
+	if w.cfg.MaxHeapMB > 0 && s.Heap > uint64(w.cfg.MaxHeapMB)<<20 {
+		w.log.Warn("Compacting storage", "heap_mb", s.Heap>>20, "max_mb", w.cfg.MaxHeapMB)
+		compactions.Inc()
+		if err := w.compact(); err != nil {
+			return s, fmt.Errorf("failed to compact storage: %w", err)
+		}
+		debug.FreeOSMemory()
+	}
+	return s, nil
+}
+
+// browserOverLimit describes which browser limit s exceeds, if any
+func (w *Watchdog) browserOverLimit(s Sample) string {
+	if w.cfg.MaxBrowserRSSMB > 0 && s.BrowserRSS > int64(w.cfg.MaxBrowserRSSMB)<<20 {
+		return fmt.Sprintf("browser RSS %d MB over %d MB", s.BrowserRSS>>20, w.cfg.MaxBrowserRSSMB)
+	}
+	if w.cfg.MaxPages > 0 && s.Pages > w.cfg.MaxPages {
+		return fmt.Sprintf("%d pages open, limit %d", s.Pages, w.cfg.MaxPages)
+	}
+	return ""
+}
+
+// processRSS reads the resident set size of a process from /proc, or -1
+func processRSS(pid int) int64 {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return -1
+	}
+	defer f.Close()
+	for sc := bufio.NewScanner(f); sc.Scan(); {
+		// VmRSS:	  123456 kB
+		if fields := strings.Fields(sc.Text()); len(fields) == 3 && fields[0] == "VmRSS:" {
+			kb, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return -1
+			}
+			return kb << 10
+		}
+	}
+	return -1
+}
+
+// goHeap returns the Go heap in use
+func goHeap() uint64 {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.HeapInuse
+}
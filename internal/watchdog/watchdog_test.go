@@ -0,0 +1,63 @@
+package watchdog
+
+import (
+	"os"
+	"testing"
+
+	"subspace/internal/config"
+)
+
+type fakeBrowser struct {
+	pages    int
+	recycled int
+}
+
+func (b *fakeBrowser) PID() int                { return 42 }
+func (b *fakeBrowser) PageCount() (int, error) { return b.pages, nil }
+func (b *fakeBrowser) Recycle() error          { b.recycled++; b.pages = 1; return nil }
+
+func TestCheck(t *testing.T) {
+	b := &fakeBrowser{pages: 1}
+	compacted := 0
+	w := New(config.WatchdogConfig{Enabled: true, MaxBrowserRSSMB: 100, MaxPages: 3, MaxHeapMB: 64}, b, func() error {
+		compacted++
+		return nil
+	})
+	rss, heap := int64(50<<20), uint64(10<<20)
+	w.rss = func(int) int64 { return rss }
+	w.heap = func() uint64 { return heap }
+
+	check := func(wantRecycled, wantCompacted int) {
+		t.Helper()
+		if _, err := w.Check(); err != nil {
+			t.Fatal(err)
+		}
+		if b.recycled != wantRecycled || compacted != wantCompacted {
+			t.Errorf("recycled %d, compacted %d; want %d and %d", b.recycled, compacted, wantRecycled, wantCompacted)
+		}
+	}
+
+	check(0, 0) // within limits
+	b.pages = 4
+	check(1, 0)
+	rss = 200 << 20
+	check(2, 0)
+	rss, heap = 50<<20, 100<<20
+	check(2, 1)
+
+	if New(config.WatchdogConfig{}, b, nil) != nil {
+		t.Error("disabled watchdog was created")
+	}
+}
+
+func TestProcessRSS(t *testing.T) {
+	if _, err := os.Stat("/proc/self/status"); err != nil {
+		t.Skip("no /proc")
+	}
+	if rss := processRSS(os.Getpid()); rss <= 0 {
+		t.Errorf("processRSS(self) = %d, want positive", rss)
+	}
+	if rss := processRSS(-1); rss != -1 {
+		t.Errorf("processRSS(-1) = %d, want -1", rss)
+	}
+}
@@ -0,0 +1,140 @@
+package webstorage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"subspace/internal/browser"
+	"subspace/internal/config"
+)
+
+/*
+WEB STORAGE MODULE
+
+Sessions often hold their tokens in localStorage or sessionStorage rather
+than in cookies. When the session is saved, the items of the page's origin
+whose keys match auth.web_storage.keys are written to
+<data_dir>/web_storage.json, under the active account; restoring the
+session seeds them back into that origin before its scripts run. Keys not
+on the allow-list (caches, analytics IDs) are never written.
+*/
+
+// fileName is the saved storage inside the data directory
+const fileName = "web_storage.json"
+
+// Store saves and restores the allow-listed web storage of an account
+type Store struct {
+	keys    []string
+	path    string
+	account string
+}
+
+// New returns the store of an account ("" without one). It returns nil
+// when no key is allow-listed.
+func New(cfg config.WebStorageConfig, dataDir, account string) *Store {
+	if len(cfg.Keys) == 0 {
+		return nil
+	}
+	return &Store{keys: cfg.Keys, path: filepath.Join(dataDir, fileName), account: account}
+}
+
+// Filter returns the allow-listed items of ws
+func (s *Store) Filter(ws browser.WebStorage) browser.WebStorage {
+	return browser.WebStorage{Origin: ws.Origin, Local: s.allowed(ws.Local), Session: s.allowed(ws.Session)}
+}
+
+// allowed returns the items whose keys match an allow-listed pattern
+func (s *Store) allowed(items map[string]string) map[string]string {
+	kept := make(map[string]string)
+	for k, v := range items {
+		for _, p := range s.keys {
+			if ok, _ := path.Match(p, k); ok {
+				kept[k] = v
+				break
+			}
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
+
+// Save records the allow-listed items of ws for the account and returns
+// how many were kept
+func (s *Store) Save(ws browser.WebStorage) (int, error) {
+	ws = s.Filter(ws)
+	if err := s.put(ws); err != nil {
+		return 0, err
+	}
+	return len(ws.Local) + len(ws.Session), nil
+}
+
+// Saved returns the storage saved for an account as is, for account
+// bundles
+func Saved(dataDir, account string) (browser.WebStorage, bool, error) {
+	s := &Store{path: filepath.Join(dataDir, fileName), account: account}
+	saved, err := s.load()
+	if err != nil {
+		return browser.WebStorage{}, false, err
+	}
+	ws, ok := saved[account]
+	return ws, ok, nil
+}
+
+// Put replaces the storage saved for an account, for account bundles
+func Put(dataDir, account string, ws browser.WebStorage) error {
+	s := &Store{path: filepath.Join(dataDir, fileName), account: account}
+	return s.put(ws)
+}
+
+// put records ws as the account's saved storage
+func (s *Store) put(ws browser.WebStorage) error {
+	saved, err := s.load()
+	if err != nil {
+		return err
+	}
+	saved[s.account] = ws
+
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode web storage: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save web storage: %w", err)
+	}
+	return nil
+}
+
+// Load returns the account's saved storage, if any
+func (s *Store) Load() (browser.WebStorage, bool, error) {
+	saved, err := s.load()
+	if err != nil {
+		return browser.WebStorage{}, false, err
+	}
+	ws, ok := saved[s.account]
+	return s.Filter(ws), ok && ws.Origin != "", nil
+}
+
+// load reads the saved storage by account
+func (s *Store) load() (map[string]browser.WebStorage, error) {
+	saved := make(map[string]browser.WebStorage)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return saved, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read web storage: %w", err)
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse web storage %s: %w", s.path, err)
+	}
+	return saved, nil
+}
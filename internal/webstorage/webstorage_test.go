@@ -0,0 +1,40 @@
+package webstorage
+
+import (
+	"testing"
+
+	"subspace/internal/browser"
+	"subspace/internal/config"
+)
+
+func TestSaveLoadPerAccount(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.WebStorageConfig{Keys: []string{"auth_token", "li:*"}}
+	if New(config.WebStorageConfig{}, dir, "alice") != nil {
+		t.Fatal("store without keys should be nil")
+	}
+
+	alice := New(cfg, dir, "alice")
+	n, err := alice.Save(browser.WebStorage{
+		Origin:  "https://www.linkedin.com",
+		Local:   map[string]string{"auth_token": "a", "li:feed": "f", "_ga_cache": "x"},
+		Session: map[string]string{"scroll": "120"},
+	})
+	if err != nil || n != 2 {
+		t.Fatalf("saved %d items, err %v; want 2", n, err)
+	}
+	if _, err := New(cfg, dir, "bob").Save(browser.WebStorage{Origin: "https://www.linkedin.com", Local: map[string]string{"auth_token": "b"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	ws, ok, err := New(cfg, dir, "alice").Load()
+	if err != nil || !ok {
+		t.Fatalf("load: ok %v, err %v", ok, err)
+	}
+	if ws.Local["auth_token"] != "a" || ws.Local["li:feed"] != "f" || len(ws.Local) != 2 || ws.Session != nil {
+		t.Errorf("alice's storage = %+v", ws)
+	}
+	if _, ok, _ := New(cfg, dir, "carol").Load(); ok {
+		t.Error("carol has no saved storage")
+	}
+}
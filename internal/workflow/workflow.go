@@ -0,0 +1,91 @@
+package workflow
+
+import (
+	"slices"
+	"time"
+
+	"subspace/internal/config"
+)
+
+/*
+WORKFLOW MODULE
+
+Decides the order of the workflow steps (search, connect, acceptance,
+messaging) for one run:
+
+- sequential: every step once, in that order
+- interleaved: search once, then rounds of connect, acceptance and
+  messaging of batch_size requests and messages each, repeated while a
+  round sends something, so requests and messages alternate through the
+  session instead of coming in two blocks
+- message_first: before morning_until, acceptance and messaging run before
+  search and connect, answering overnight acceptances first; sequential
+  afterwards
+
+On search_only_days only search runs, whatever the strategy. Steps turned
+off under modules are left out of the plan.
+*/
+
+// Steps in sequential order
+const (
+	Search     = "search"
+	Connect    = "connect"
+	Acceptance = "acceptance"
+	Messaging  = "messaging"
+)
+
+// Plan is the order of the workflow steps for one run
+type Plan struct {
+	Strategy string   `json:"strategy"`
+	Steps    []string `json:"steps"`            // Run once, in order
+	Rounds   []string `json:"rounds,omitempty"` // Then repeated while a round sends something
+	Batch    int      `json:"batch,omitempty"`  // Requests and messages per round
+}
+
+// For returns the plan of a run starting at now
+func For(cfg config.WorkflowConfig, modules config.ModulesConfig, now time.Time) Plan {
+	plan := Plan{Strategy: cfg.Strategy}
+	switch {
+	case searchOnly(cfg, now):
+		plan.Strategy = "search_only"
+		plan.Steps = []string{Search}
+	case cfg.Strategy == "interleaved":
+		plan.Steps = []string{Search}
+		plan.Rounds = []string{Connect, Acceptance, Messaging}
+		plan.Batch = cfg.BatchSize
+	case cfg.Strategy == "message_first" && beforeMorningEnd(cfg, now):
+		plan.Steps = []string{Acceptance, Messaging, Search, Connect}
+	default:
+		plan.Strategy = "sequential"
+		plan.Steps = []string{Search, Connect, Acceptance, Messaging}
+	}
+
+	enabled := modules.Enabled()
+	off := func(step string) bool { return !slices.Contains(enabled, step) }
+	plan.Steps = slices.DeleteFunc(plan.Steps, off)
+	plan.Rounds = slices.DeleteFunc(plan.Rounds, off)
+	if len(plan.Rounds) == 0 {
+		plan.Rounds, plan.Batch = nil, 0
+	}
+	return plan
+}
+
+// searchOnly reports whether now falls on one of the search-only days
+func searchOnly(cfg config.WorkflowConfig, now time.Time) bool {
+	for _, name := range cfg.SearchOnlyDays {
+		if day, ok := config.ParseWeekday(name); ok && day == now.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// beforeMorningEnd reports whether now is earlier in the day than
+// morning_until
+func beforeMorningEnd(cfg config.WorkflowConfig, now time.Time) bool {
+	until, err := time.Parse("15:04", cfg.MorningUntil)
+	if err != nil {
+		return false // Validated with the config
+	}
+	return now.Hour()*60+now.Minute() < until.Hour()*60+until.Minute()
+}
@@ -0,0 +1,48 @@
+package workflow
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"subspace/internal/config"
+)
+
+func TestPlanStrategies(t *testing.T) {
+	all := config.ModulesConfig{Search: true, Connect: true, Acceptance: true, Messaging: true}
+	monday9 := time.Date(2024, 5, 6, 9, 0, 0, 0, time.Local)
+	monday15 := time.Date(2024, 5, 6, 15, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		cfg     config.WorkflowConfig
+		modules config.ModulesConfig
+		now     time.Time
+		steps   []string
+		rounds  []string
+	}{
+		{"sequential", config.WorkflowConfig{Strategy: "sequential"}, all, monday9,
+			[]string{Search, Connect, Acceptance, Messaging}, nil},
+		{"interleaved", config.WorkflowConfig{Strategy: "interleaved", BatchSize: 3}, all, monday9,
+			[]string{Search}, []string{Connect, Acceptance, Messaging}},
+		{"message first in the morning", config.WorkflowConfig{Strategy: "message_first", MorningUntil: "12:00"}, all, monday9,
+			[]string{Acceptance, Messaging, Search, Connect}, nil},
+		{"message first in the afternoon", config.WorkflowConfig{Strategy: "message_first", MorningUntil: "12:00"}, all, monday15,
+			[]string{Search, Connect, Acceptance, Messaging}, nil},
+		{"search only day", config.WorkflowConfig{Strategy: "interleaved", BatchSize: 3, SearchOnlyDays: []string{"Monday"}}, all, monday9,
+			[]string{Search}, nil},
+		{"disabled modules", config.WorkflowConfig{Strategy: "interleaved", BatchSize: 3}, config.ModulesConfig{Connect: true}, monday9,
+			[]string{}, []string{Connect}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := For(tt.cfg, tt.modules, tt.now)
+			if !slices.Equal(plan.Steps, tt.steps) || !slices.Equal(plan.Rounds, tt.rounds) {
+				t.Errorf("plan = %+v, want steps %v then rounds %v", plan, tt.steps, tt.rounds)
+			}
+			if (len(plan.Rounds) > 0) != (plan.Batch > 0) {
+				t.Errorf("batch %d with rounds %v", plan.Batch, plan.Rounds)
+			}
+		})
+	}
+}
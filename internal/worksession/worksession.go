@@ -0,0 +1,121 @@
+package worksession
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"subspace/internal/clock"
+	"subspace/internal/logger"
+)
+
+/*
+WORK SESSION MODULE
+
+A work session is an operator-started window during which automation may run
+outside the configured business hours, e.g. to supervise a run in the
+evening. Sessions are capped at stealth.work_session_max_minutes and expire
+on their own, so the business-hours gate never has to be edited away in
+config.yaml.
+
+The active session is a small JSON file in the data directory, so it is seen
+by every command run against that directory until it expires or is ended.
+*/
+
+// fileName is the session file inside the data directory
+const fileName = "work_session.json"
+
+// Session is a manually started work session
+type Session struct {
+	StartedAt time.Time `json:"started_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// Active reports whether the session covers the given time
+func (s *Session) Active(now time.Time) bool {
+	return s != nil && !now.Before(s.StartedAt) && now.Before(s.ExpiresAt)
+}
+
+// Remaining returns how long the session still runs at the given time
+func (s *Session) Remaining(now time.Time) time.Duration {
+	if !s.Active(now) {
+		return 0
+	}
+	return s.ExpiresAt.Sub(now)
+}
+
+// Path returns the session file for a data directory
+func Path(dataDir string) string {
+	return filepath.Join(dataDir, fileName)
+}
+
+// Start begins a session of the given length, replacing any earlier one.
+// Lengths above maxLength are rejected; a maxLength of 0 disables sessions.
+func Start(dataDir string, length, maxLength time.Duration, reason string) (*Session, error) {
+	if maxLength <= 0 {
+		return nil, fmt.Errorf("work sessions are disabled (stealth.work_session_max_minutes is 0)")
+	}
+	if length <= 0 {
+		return nil, fmt.Errorf("work session length must be positive")
+	}
+	if length > maxLength {
+		return nil, fmt.Errorf("work session of %s exceeds the %s cap", length, maxLength)
+	}
+
+	now := clock.Now()
+	s := &Session{StartedAt: now, ExpiresAt: now.Add(length), Reason: reason}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode work session: %w", err)
+	}
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	if err := os.WriteFile(Path(dataDir), data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to save work session: %w", err)
+	}
+
+	logger.NewContext("worksession").Warn("Manual work session started",
+		"expires_at", s.ExpiresAt.Format(time.RFC3339), "reason", reason)
+	return s, nil
+}
+
+// Load returns the session stored in a data directory, expired or not, or
+// nil if there is none
+func Load(dataDir string) (*Session, error) {
+	data, err := os.ReadFile(Path(dataDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read work session: %w", err)
+	}
+
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse work session %s: %w", Path(dataDir), err)
+	}
+	return &s, nil
+}
+
+// Current returns the session active right now, or nil
+func Current(dataDir string) (*Session, error) {
+	s, err := Load(dataDir)
+	if err != nil || !s.Active(clock.Now()) {
+		return nil, err
+	}
+	return s, nil
+}
+
+// End removes the stored session; ending when none exists is not an error
+func End(dataDir string) error {
+	if err := os.Remove(Path(dataDir)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to end work session: %w", err)
+	}
+	logger.NewContext("worksession").Info("Manual work session ended")
+	return nil
+}
@@ -0,0 +1,68 @@
+package worksession
+
+import (
+	"testing"
+	"time"
+
+	"subspace/internal/clock"
+)
+
+func TestStartAndExpire(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 5, 1, 19, 0, 0, 0, time.UTC))
+	clock.Set(fake)
+	defer clock.Set(clock.Real{})
+	dir := t.TempDir()
+
+	if s, err := Current(dir); s != nil || err != nil {
+		t.Fatalf("Current without a session = %v, %v", s, err)
+	}
+	s, err := Start(dir, time.Hour, 2*time.Hour, "supervised evening run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Remaining(fake.Now()); got != time.Hour {
+		t.Errorf("Remaining = %v, want 1h", got)
+	}
+
+	// Every command on the data directory sees the session until it expires
+	fake.Advance(59 * time.Minute)
+	current, err := Current(dir)
+	if err != nil || current == nil || current.Reason != "supervised evening run" {
+		t.Fatalf("Current during the session = %v, %v", current, err)
+	}
+	fake.Advance(time.Minute)
+	if current, _ := Current(dir); current != nil {
+		t.Error("session still current once expired")
+	}
+	if stored, _ := Load(dir); stored == nil || stored.Remaining(fake.Now()) != 0 {
+		t.Errorf("Load = %v, want the expired session", stored)
+	}
+
+	if err := End(dir); err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := Load(dir); stored != nil {
+		t.Error("session stored after End")
+	}
+	if err := End(dir); err != nil {
+		t.Errorf("ending twice: %v", err)
+	}
+}
+
+func TestStartRejects(t *testing.T) {
+	dir := t.TempDir()
+	for _, tt := range []struct {
+		length, max time.Duration
+	}{
+		{time.Hour, 0},                 // Sessions disabled
+		{0, time.Hour},                 // Empty session
+		{3 * time.Hour, 2 * time.Hour}, // Over the cap
+	} {
+		if _, err := Start(dir, tt.length, tt.max, ""); err == nil {
+			t.Errorf("Start(%v) with a cap of %v succeeded", tt.length, tt.max)
+		}
+	}
+	if s, _ := Load(dir); s != nil {
+		t.Errorf("rejected session stored: %+v", s)
+	}
+}